@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/urfave/cli/v2"
 	"github.com/yetanotherco/aligned_layer/aggregator/pkg"
@@ -66,7 +68,11 @@ func aggregatorMain(ctx *cli.Context) error {
 		}
 	}()
 
-	err = aggregator.Start(context.Background())
+	// On SIGTERM or SIGINT the aggregator stops, sending the pending telemetry events first
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	err = aggregator.Start(signalCtx)
 
 	return err
 }
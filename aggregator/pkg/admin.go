@@ -0,0 +1,55 @@
+package pkg
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// TelemetryAdminEndpoint turns the telemetry on or off at runtime
+const TelemetryAdminEndpoint = "/admin/telemetry"
+
+// telemetryStatus is the body of the telemetry admin endpoint
+type telemetryStatus struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TelemetryAdminHandler returns whether the telemetry is enabled on GET, and sets it on PUT with
+// a {"enabled": bool} body
+func (t *Telemetry) TelemetryAdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var status telemetryStatus
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&status); err != nil {
+				http.Error(w, "invalid body, expected {\"enabled\": bool}", http.StatusBadRequest)
+				return
+			}
+			t.SetEnabled(status.Enabled)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(telemetryStatus{Enabled: t.Enabled()}); err != nil {
+			t.logger.Error("Could not encode telemetry status", "err", err)
+		}
+	})
+}
+
+// ServeAdmin serves the admin endpoints at the address, which must only be reachable by the
+// aggregator operators
+func (agg *Aggregator) ServeAdmin(address string) error {
+	mux := http.NewServeMux()
+	mux.Handle(TelemetryAdminEndpoint, agg.telemetry.TelemetryAdminHandler())
+
+	agg.logger.Info("Starting admin server on address", "address", address)
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+	return server.ListenAndServe()
+}
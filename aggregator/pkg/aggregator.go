@@ -5,12 +5,15 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"os"
+	"strconv"
 	"sync"
 	"time"
 
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/prometheus/client_golang/prometheus"
+	retry "github.com/yetanotherco/aligned_layer/core"
 	"github.com/yetanotherco/aligned_layer/metrics"
 
 	sdkclients "github.com/Layr-Labs/eigensdk-go/chainio/clients"
@@ -19,12 +22,12 @@ import (
 	blsagg "github.com/Layr-Labs/eigensdk-go/services/bls_aggregation"
 	oppubkeysserv "github.com/Layr-Labs/eigensdk-go/services/operatorsinfo"
 	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
 	"github.com/yetanotherco/aligned_layer/core/chainio"
 	"github.com/yetanotherco/aligned_layer/core/config"
 	"github.com/yetanotherco/aligned_layer/core/types"
 	"github.com/yetanotherco/aligned_layer/core/utils"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
 )
 
 // FIXME(marian): Read this from Aligned contract directly
@@ -36,8 +39,11 @@ type TaskResponses = []types.SignedTaskResponse
 
 // BatchData stores the data of a batch, for use in map BatchIdentifierHash -> BatchData
 type BatchData struct {
-	BatchMerkleRoot [32]byte
-	SenderAddress   [20]byte
+	BatchMerkleRoot       [32]byte
+	SenderAddress         [20]byte
+	RespondToTaskFeeLimit *big.Int
+	// Unfunded batches are only responded once funded, or after the unfunded batches max delay
+	Unfunded bool
 }
 
 type Aggregator struct {
@@ -48,40 +54,30 @@ type Aggregator struct {
 	avsWriter             *chainio.AvsWriter
 	taskSubscriber        chan error
 	blsAggregationService blsagg.BlsAggregationService
+	avsRegistryService    avsregistry.AvsRegistryService
 
-	// BLS Signature Service returns an Index
-	// Since our ID is not an idx, we build this cache
-	// Note: In case of a reboot, this doesn't need to be loaded,
-	// and can start from zero
-	batchesIdentifierHashByIdx map[uint32][32]byte
-
-	// This is the counterpart,
-	// to use when we have the batch but not the index
-	// Note: In case of a reboot, this doesn't need to be loaded,
-	// and can start from zero
-	batchesIdxByIdentifierHash map[[32]byte]uint32
-
-	// Stores the taskCreatedBlock for each batch by batch index
-	batchCreatedBlockByIdx map[uint32]uint64
-
-	// Stores the TaskResponse for each batch by batchIdentifierHash
-	batchDataByIdentifierHash map[[32]byte]BatchData
-
-	// Stores the start time for each batch of the aggregator by task index
-	batchStartTimeByIdx map[uint32]time.Time
-
-	// This task index is to communicate with the local BLS
-	// Service.
-	// Note: In case of a reboot it can start from 0 again
-	nextBatchIndex uint32
-
-	// Mutex to protect:
-	// - batchesIdentifierHashByIdx
-	// - batchesIdxByIdentifierHash
-	// - batchCreatedBlockByIdx
-	// - batchDataByIdentifierHash
-	// - nextBatchIndex
-	// - batchStartTimeByIdx
+	// Last capabilities advertised by each operator
+	operatorsCapabilities *OperatorsCapabilities
+
+	// Batch senders whose tasks are aggregated
+	batchersAuthorization *BatchersAuthorization
+
+	// Checks no other replica responds to the same batch
+	responseCoordinator *ResponseCoordinator
+
+	// Attempts to misuse the aggregator, for the security team
+	securityEvents *SecurityEventLog
+
+	// Checks the responses are compensated by the batchers
+	fundingChecker        *FundingChecker
+	unfundedBatchesPolicy UnfundedBatchesPolicy
+
+	// Batches being aggregated and their lifecycle states, by the index of the
+	// BLS aggregation service and by batch identifier hash
+	tasks *TaskLifecycle
+
+	// Mutex to make the tasks initialized in the BLS aggregation service
+	// before their responses are handled
 	taskMutex *sync.Mutex
 
 	// Mutex to protect ethereum wallet
@@ -104,31 +100,61 @@ func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error
 
 	// Metrics
 	reg := prometheus.NewRegistry()
-	aggregatorMetrics := metrics.NewMetrics(aggregatorConfig.Aggregator.MetricsIpPortAddress, reg, logger)
+	aggregatorMetrics := metrics.NewMetricsWithConfig(aggregatorConfig.Aggregator.MetricsIpPortAddress, reg, logger, aggregatorConfig.MetricsConfig)
 
 	// Telemetry
-	aggregatorTelemetry := NewTelemetry(aggregatorConfig.Aggregator.TelemetryIpPortAddress, logger)
+	aggregatorTelemetry, err := NewTelemetryWithConfig(TelemetryConfig{
+		Transport:         TelemetryTransport(aggregatorConfig.Aggregator.TelemetryTransport),
+		ServerAddress:     aggregatorConfig.Aggregator.TelemetryIpPortAddress,
+		OtlpEndpoint:      aggregatorConfig.Aggregator.TelemetryOtlpEndpoint,
+		OtlpInsecure:      aggregatorConfig.Aggregator.TelemetryOtlpInsecure,
+		GrpcEndpoint:      aggregatorConfig.Aggregator.TelemetryGrpcEndpoint,
+		GrpcInsecure:      aggregatorConfig.Aggregator.TelemetryGrpcInsecure,
+		GrpcFlushInterval: aggregatorConfig.Aggregator.TelemetryGrpcFlushInterval,
+		Sampling: TelemetrySampling{
+			OneIn:       aggregatorConfig.Aggregator.TelemetrySampleOneIn,
+			Probability: aggregatorConfig.Aggregator.TelemetrySampleProbability,
+		},
+		SpillDir:             aggregatorConfig.Aggregator.TelemetrySpillDir,
+		SpillMaxFileSize:     aggregatorConfig.Aggregator.TelemetrySpillMaxFileSize,
+		SpillMaxFiles:        aggregatorConfig.Aggregator.TelemetrySpillMaxFiles,
+		ApiToken:             aggregatorConfig.Aggregator.TelemetryApiToken,
+		BreakerThreshold:     aggregatorConfig.Aggregator.TelemetryBreakerThreshold,
+		BreakerProbeInterval: aggregatorConfig.Aggregator.TelemetryBreakerProbeInterval,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	avsReader, err := chainio.NewAvsReaderFromConfig(aggregatorConfig.BaseConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	avsSubscriber, err := chainio.NewAvsSubscriberFromConfig(aggregatorConfig.BaseConfig)
+	avsSubscriber, err := chainio.NewAvsSubscriberFromConfig(aggregatorConfig.BaseConfig, aggregatorMetrics)
 	if err != nil {
 		return nil, err
 	}
 
-	avsWriter, err := chainio.NewAvsWriterFromConfig(aggregatorConfig.BaseConfig, aggregatorConfig.EcdsaConfig, aggregatorMetrics)
+	avsWriter, err := chainio.NewAvsWriterFromConfig(aggregatorConfig.BaseConfig, aggregatorConfig.EcdsaConfig, aggregatorConfig.RespondToTaskEcdsaConfig, aggregatorMetrics)
 	if err != nil {
 		return nil, err
 	}
 
-	batchesIdentifierHashByIdx := make(map[uint32][32]byte)
-	batchesIdxByIdentifierHash := make(map[[32]byte]uint32)
-	batchDataByIdentifierHash := make(map[[32]byte]BatchData)
-	batchCreatedBlockByIdx := make(map[uint32]uint64)
-	batchStartTimeByIdx := make(map[uint32]time.Time)
+	var taskStore TaskStore
+	if aggregatorConfig.Aggregator.TaskStatePath != "" {
+		taskStore = NewFileTaskStore(aggregatorConfig.Aggregator.TaskStatePath)
+	}
+	tasks, interruptedTasks, err := NewTaskLifecycle(taskStore, aggregatorMetrics, logger)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range interruptedTasks {
+		logger.Warn("Task was interrupted by the aggregator restart, it won't be responded",
+			"state", task.State,
+			"merkleRoot", "0x"+hex.EncodeToString(task.BatchData.BatchMerkleRoot[:]),
+			"batchIdentifierHash", "0x"+hex.EncodeToString(task.BatchIdentifierHash[:]))
+	}
 
 	chainioConfig := sdkclients.BuildAllConfig{
 		EthHttpUrl:                 aggregatorConfig.BaseConfig.EthRpcUrl,
@@ -164,7 +190,57 @@ func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error
 	avsRegistryService := avsregistry.NewAvsRegistryServiceChainCaller(avsReader.ChainReader, operatorPubkeysService, logger)
 	blsAggregationService := blsagg.NewBlsAggregatorService(avsRegistryService, hashFunction, logger)
 
-	nextBatchIndex := uint32(0)
+	batchersAuthorization := NewBatchersAuthorization(
+		aggregatorConfig.Aggregator.AuthorizedBatchers,
+		aggregatorConfig.BaseConfig.AlignedLayerDeploymentConfig.BatcherPaymentServiceAddr,
+		avsReader.GetBatcherBalance,
+	)
+
+	var responseClaims ResponseClaims
+	if aggregatorConfig.Aggregator.HaDatabaseUrl != "" {
+		replicaId := aggregatorConfig.Aggregator.HaReplicaId
+		if replicaId == "" {
+			replicaId, err = os.Hostname()
+			if err != nil {
+				return nil, fmt.Errorf("could not get the replica id: %w", err)
+			}
+		}
+		claimTtl := aggregatorConfig.Aggregator.HaClaimTtl
+		if claimTtl == 0 {
+			claimTtl = DefaultResponseClaimTtl
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		responseClaims, err = NewPostgresResponseClaims(ctx, aggregatorConfig.Aggregator.HaDatabaseUrl, replicaId, claimTtl)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Coordinating responses with the other aggregator replicas", "replicaId", replicaId)
+	}
+	responseCoordinator := NewResponseCoordinator(avsReader.GetBatchState, responseClaims)
+
+	unfundedBatchesPolicy := UnfundedBatchesPolicy(aggregatorConfig.Aggregator.UnfundedBatchesPolicy)
+	switch unfundedBatchesPolicy {
+	case "":
+		unfundedBatchesPolicy = DeprioritizeUnfundedBatches
+	case RespondUnfundedBatches, DeprioritizeUnfundedBatches, SkipUnfundedBatches:
+	default:
+		return nil, fmt.Errorf("invalid unfunded batches policy %q, expected respond, deprioritize or skip", unfundedBatchesPolicy)
+	}
+	securityEvents, err := NewSecurityEventLog(aggregatorConfig.Aggregator.SecurityEventsCapacity, aggregatorConfig.Aggregator.SecurityEventsFile, logger)
+	if err != nil {
+		return nil, fmt.Errorf("could not create security event log: %w", err)
+	}
+
+	// The gas price is bumped like the first response transaction
+	respondGasPrice := func() (*big.Int, error) {
+		gasPrice, err := utils.GetGasPriceRetryable(avsWriter.Client, avsWriter.ClientFallback, retry.NetworkRetryParams())
+		if err != nil {
+			return nil, err
+		}
+		return utils.CalculateGasPriceBumpBasedOnRetry(gasPrice, aggregatorConfig.Aggregator.GasBaseBumpPercentage, 0, aggregatorConfig.Aggregator.GasBumpPercentageLimit, 0), nil
+	}
+	fundingChecker := NewFundingChecker(respondGasPrice, avsReader.GetBatcherBalance, aggregatorConfig.Aggregator.RespondToTaskGasEstimate)
 
 	aggregator := Aggregator{
 		AggregatorConfig: &aggregatorConfig,
@@ -173,16 +249,18 @@ func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error
 		avsWriter:        avsWriter,
 		NewBatchChan:     newBatchChan,
 
-		batchesIdentifierHashByIdx: batchesIdentifierHashByIdx,
-		batchesIdxByIdentifierHash: batchesIdxByIdentifierHash,
-		batchDataByIdentifierHash:  batchDataByIdentifierHash,
-		batchCreatedBlockByIdx:     batchCreatedBlockByIdx,
-		batchStartTimeByIdx:        batchStartTimeByIdx,
-		nextBatchIndex:             nextBatchIndex,
-		taskMutex:                  &sync.Mutex{},
-		walletMutex:                &sync.Mutex{},
+		tasks:       tasks,
+		taskMutex:   &sync.Mutex{},
+		walletMutex: &sync.Mutex{},
 
 		blsAggregationService: blsAggregationService,
+		avsRegistryService:    avsRegistryService,
+		operatorsCapabilities: NewOperatorsCapabilities(),
+		batchersAuthorization: batchersAuthorization,
+		responseCoordinator:   responseCoordinator,
+		securityEvents:        securityEvents,
+		fundingChecker:        fundingChecker,
+		unfundedBatchesPolicy: unfundedBatchesPolicy,
 		logger:                logger,
 		metricsReg:            reg,
 		metrics:               aggregatorMetrics,
@@ -202,9 +280,27 @@ func (agg *Aggregator) Start(ctx context.Context) error {
 		}
 	}()
 
+	if address := agg.AggregatorConfig.Aggregator.SecurityEventsIpPortAddress; address != "" {
+		go func() {
+			err := agg.ServeSecurityEvents(address)
+			if err != nil {
+				agg.logger.Fatal("Error serving security events", "err", err)
+			}
+		}()
+	}
+
+	if address := agg.AggregatorConfig.Aggregator.AdminIpPortAddress; address != "" {
+		go func() {
+			err := agg.ServeAdmin(address)
+			if err != nil {
+				agg.logger.Fatal("Error serving admin endpoints", "err", err)
+			}
+		}()
+	}
+
 	var metricsErrChan <-chan error
 	if agg.AggregatorConfig.Aggregator.EnableMetrics {
-		metricsErrChan = agg.metrics.Start(ctx, agg.metricsReg)
+		metricsErrChan = agg.metrics.Start(ctx, prometheus.Gatherers{agg.metricsReg, agg.AggregatorConfig.BaseConfig.EthRpcMetricsRegistry})
 	} else {
 		metricsErrChan = make(chan error, 1)
 	}
@@ -212,6 +308,8 @@ func (agg *Aggregator) Start(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
+			agg.logger.Info("Stopping aggregator, sending the pending telemetry events")
+			agg.telemetry.Close()
 			return nil
 		case err := <-metricsErrChan:
 			agg.logger.Fatal("Metrics server failed", "err", err)
@@ -236,12 +334,17 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 
 	agg.taskMutex.Lock()
 	agg.AggregatorConfig.BaseConfig.Logger.Info("- Locked Resources: Fetching task data")
-	batchIdentifierHash := agg.batchesIdentifierHashByIdx[blsAggServiceResp.TaskIndex]
-	batchData := agg.batchDataByIdentifierHash[batchIdentifierHash]
-	taskCreatedBlock := agg.batchCreatedBlockByIdx[blsAggServiceResp.TaskIndex]
-	taskCreatedAt := agg.batchStartTimeByIdx[blsAggServiceResp.TaskIndex]
+	task, ok := agg.tasks.Get(blsAggServiceResp.TaskIndex)
 	agg.taskMutex.Unlock()
 	agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Fetching task data")
+	if !ok {
+		agg.logger.Error("Task of the BlsAggregationServiceResponse not found", "taskIndex", blsAggServiceResp.TaskIndex)
+		return
+	}
+	batchIdentifierHash := task.BatchIdentifierHash
+	batchData := task.BatchData
+	taskCreatedBlock := task.CreatedBlock
+	taskCreatedAt := task.StartTime
 
 	// Finish task trace once the task is processed (either successfully or not)
 	defer agg.telemetry.FinishTrace(batchData.BatchMerkleRoot)
@@ -249,6 +352,8 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 	if blsAggServiceResp.Err != nil {
 		agg.telemetry.LogTaskError(batchData.BatchMerkleRoot, blsAggServiceResp.Err)
 		agg.logger.Error("BlsAggregationServiceResponse contains an error", "err", blsAggServiceResp.Err, "batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]))
+		agg.metrics.IncAggregatorLostTasks("expired")
+		agg.transitionTask(task.Index, TaskExpired)
 		return
 	}
 	nonSignerPubkeys := []servicemanager.BN254G1Point{}
@@ -271,10 +376,11 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 		NonSignerStakeIndices:        blsAggServiceResp.NonSignerStakeIndices,
 	}
 
-	agg.telemetry.LogQuorumReached(batchData.BatchMerkleRoot)
+	agg.telemetry.LogQuorumReached(batchData.BatchMerkleRoot, len(nonSignerPubkeys))
+	agg.transitionTask(task.Index, TaskQuorumReached)
 
 	// Only observe quorum reached if successful
-	agg.metrics.ObserveTaskQuorumReached(time.Since(taskCreatedAt))
+	agg.metrics.ObserveTaskQuorumReached(QUORUM_NUMBER, time.Since(taskCreatedAt), task.TraceId)
 
 	agg.logger.Info("Threshold reached", "taskIndex", blsAggServiceResp.TaskIndex,
 		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
@@ -289,18 +395,52 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 		agg.logger.Error("Error waiting for one block, sending anyway", "err", err)
 	}
 
+	if batchData.Unfunded {
+		agg.waitForFunding(batchData)
+	}
+
+	shouldRespond, reason, err := agg.responseCoordinator.ShouldRespond(context.Background(), batchIdentifierHash, batchData.BatchMerkleRoot, batchData.SenderAddress)
+	if err != nil {
+		agg.logger.Error("Could not check other aggregator replicas are not responding, refusing to respond to task",
+			"err", err,
+			"taskIndex", blsAggServiceResp.TaskIndex,
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+		agg.telemetry.LogTaskError(batchData.BatchMerkleRoot, err)
+		agg.transitionTask(task.Index, TaskFailed)
+		return
+	}
+	if !shouldRespond {
+		agg.logger.Info("Task responded by another aggregator replica, skipping",
+			"reason", reason,
+			"taskIndex", blsAggServiceResp.TaskIndex,
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+		agg.metrics.IncAggregatorSkippedResponses(reason)
+		if reason == "responded" {
+			agg.transitionTask(task.Index, TaskConfirmed)
+		} else {
+			agg.transitionTask(task.Index, TaskFailed)
+		}
+		return
+	}
+
 	agg.logger.Info("Sending aggregated response onchain", "taskIndex", blsAggServiceResp.TaskIndex,
 		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]), "merkleRoot", "0x"+hex.EncodeToString(batchData.BatchMerkleRoot[:]))
-	receipt, err := agg.sendAggregatedResponse(batchIdentifierHash, batchData.BatchMerkleRoot, batchData.SenderAddress, nonSignerStakesAndSignature)
+	agg.transitionTask(task.Index, TaskSubmitted)
+	receipt, err := agg.sendAggregatedResponse(batchIdentifierHash, batchData.BatchMerkleRoot, batchData.SenderAddress, nonSignerStakesAndSignature, task.TraceId)
 	if err == nil {
+		agg.transitionTask(task.Index, TaskConfirmed)
 		// In some cases, we may fail to retrieve the receipt for the transaction.
 		txHash := "Unknown"
 		effectiveGasPrice := "Unknown"
+		gasUsed := "Unknown"
+		feePaid := "Unknown"
 		if receipt != nil {
 			txHash = receipt.TxHash.String()
 			effectiveGasPrice = receipt.EffectiveGasPrice.String()
+			gasUsed = strconv.FormatUint(receipt.GasUsed, 10)
+			feePaid = new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)).String()
 		}
-		agg.telemetry.TaskSentToEthereum(batchData.BatchMerkleRoot, txHash, effectiveGasPrice)
+		agg.telemetry.TaskSentToEthereum(batchData.BatchMerkleRoot, txHash, effectiveGasPrice, gasUsed, feePaid)
 		agg.logger.Info("Aggregator successfully responded to task",
 			"taskIndex", blsAggServiceResp.TaskIndex,
 			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
@@ -315,11 +455,45 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 		"senderAddress", "0x"+hex.EncodeToString(batchData.SenderAddress[:]),
 		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
 	agg.telemetry.LogTaskError(batchData.BatchMerkleRoot, err)
+	agg.metrics.IncAggregatorLostTasks("send_failed")
+	agg.transitionTask(task.Index, TaskFailed)
+}
+
+// transitionTask moves the task to the state, logging the transitions the lifecycle doesn't allow
+func (agg *Aggregator) transitionTask(taskIndex uint32, state TaskState) {
+	if err := agg.tasks.Transition(taskIndex, state); err != nil {
+		agg.logger.Error("Could not update the task state", "taskIndex", taskIndex, "state", state, "err", err)
+	}
+}
+
+// waitForFunding delays the response to an unfunded batch until responding is compensated by the
+// batcher, up to the unfunded batches max delay
+func (agg *Aggregator) waitForFunding(batchData BatchData) {
+	maxDelay := agg.AggregatorConfig.Aggregator.UnfundedBatchesMaxDelay
+	if maxDelay == 0 {
+		maxDelay = DefaultUnfundedBatchesMaxDelay
+	}
+	agg.logger.Info("Waiting for unfunded batch to be funded before responding",
+		"merkleRoot", "0x"+hex.EncodeToString(batchData.BatchMerkleRoot[:]),
+		"maxDelay", maxDelay)
+
+	deadline := time.Now().Add(maxDelay)
+	for time.Now().Before(deadline) {
+		funding, err := agg.fundingChecker.Check(batchData.SenderAddress, batchData.RespondToTaskFeeLimit)
+		if err == nil && funding.Funded {
+			agg.logger.Info("Unfunded batch was funded, responding",
+				"merkleRoot", "0x"+hex.EncodeToString(batchData.BatchMerkleRoot[:]))
+			return
+		}
+		time.Sleep(unfundedBatchRecheckInterval)
+	}
+	agg.logger.Warn("Unfunded batch max delay reached, responding anyway",
+		"merkleRoot", "0x"+hex.EncodeToString(batchData.BatchMerkleRoot[:]))
 }
 
 // / Sends response to contract and waits for transaction receipt
 // / Returns error if it fails to send tx or receipt is not found
-func (agg *Aggregator) sendAggregatedResponse(batchIdentifierHash [32]byte, batchMerkleRoot [32]byte, senderAddress [20]byte, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature) (*gethtypes.Receipt, error) {
+func (agg *Aggregator) sendAggregatedResponse(batchIdentifierHash [32]byte, batchMerkleRoot [32]byte, senderAddress [20]byte, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature, traceId string) (*gethtypes.Receipt, error) {
 
 	agg.walletMutex.Lock()
 	agg.logger.Infof("- Locked Wallet Resources: Sending aggregated response for batch",
@@ -352,70 +526,102 @@ func (agg *Aggregator) sendAggregatedResponse(batchIdentifierHash [32]byte, batc
 	}
 
 	// We only send the latency metric if the response is successul
-	agg.metrics.ObserveLatencyForRespondToTask(time.Since(startTime))
+	agg.metrics.ObserveLatencyForRespondToTask(QUORUM_NUMBER, time.Since(startTime), traceId)
 
 	agg.walletMutex.Unlock()
 	agg.logger.Infof("- Unlocked Wallet Resources: Sending aggregated response for batch %s", hex.EncodeToString(batchIdentifierHash[:]))
 
-	agg.metrics.IncAggregatedResponses()
+	agg.metrics.IncAggregatedResponses(QUORUM_NUMBER)
 
 	return receipt, nil
 }
 
-func (agg *Aggregator) AddNewTask(batchMerkleRoot [32]byte, senderAddress [20]byte, taskCreatedBlock uint32) {
-	agg.telemetry.InitNewTrace(batchMerkleRoot)
-	batchIdentifier := append(batchMerkleRoot[:], senderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+func (agg *Aggregator) AddNewTask(batchMerkleRoot [32]byte, senderAddress [20]byte, taskCreatedBlock uint32, respondToTaskFeeLimit *big.Int) {
+	batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, batchMerkleRoot, senderAddress)
 
 	agg.AggregatorConfig.BaseConfig.Logger.Info("Adding new task",
 		"Batch merkle root", "0x"+hex.EncodeToString(batchMerkleRoot[:]),
 		"Sender Address", "0x"+hex.EncodeToString(senderAddress[:]),
 		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
 
-	agg.taskMutex.Lock()
-	agg.AggregatorConfig.BaseConfig.Logger.Info("- Locked Resources: Adding new task")
-
-	// --- UPDATE BATCH - INDEX CACHES ---
-	batchIndex := agg.nextBatchIndex
-	if _, ok := agg.batchesIdxByIdentifierHash[batchIdentifierHash]; ok {
-		agg.logger.Warn("Batch already exists", "batchIndex", batchIndex, "batchIdentifierHash", batchIdentifierHash)
-		agg.taskMutex.Unlock()
-		agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Adding new task")
+	batchIndex, err := agg.tasks.Receive(batchIdentifierHash, BatchData{
+		BatchMerkleRoot:       batchMerkleRoot,
+		SenderAddress:         senderAddress,
+		RespondToTaskFeeLimit: respondToTaskFeeLimit,
+	}, uint64(taskCreatedBlock))
+	if err != nil {
+		agg.logger.Warn("Batch already exists", "batchIdentifierHash", batchIdentifierHash, "err", err)
 		return
 	}
 
-	// This shouldn't happen, since both maps are updated together
-	if _, ok := agg.batchesIdentifierHashByIdx[batchIndex]; ok {
-		agg.logger.Warn("Batch already exists", "batchIndex", batchIndex, "batchIdentifierHash", batchIdentifierHash)
-		agg.taskMutex.Unlock()
-		agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Adding new task")
+	authorized, err := retry.RetryWithData(func() (bool, error) {
+		return agg.batchersAuthorization.IsAuthorized(senderAddress)
+	}, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Error("Could not check the batch sender authorization, ignoring task",
+			"merkleRoot", "0x"+hex.EncodeToString(batchMerkleRoot[:]),
+			"senderAddress", "0x"+hex.EncodeToString(senderAddress[:]),
+			"err", err)
+		agg.transitionTask(batchIndex, TaskFailed)
+		return
+	}
+	if !authorized {
+		agg.logger.Warn("Batch sender is not an authorized batcher, ignoring task",
+			"merkleRoot", "0x"+hex.EncodeToString(batchMerkleRoot[:]),
+			"senderAddress", "0x"+hex.EncodeToString(senderAddress[:]))
+		agg.metrics.IncAggregatorUnauthorizedTasks()
+		eventType := InvalidSenderEvent
+		if agg.batchersAuthorization.HasAllowlist() {
+			eventType = AllowlistViolationEvent
+		}
+		agg.securityEvents.Record(eventType, "0x"+hex.EncodeToString(senderAddress[:]),
+			"merkleRoot", "0x"+hex.EncodeToString(batchMerkleRoot[:]))
+		agg.transitionTask(batchIndex, TaskFailed)
 		return
 	}
 
-	agg.batchesIdxByIdentifierHash[batchIdentifierHash] = batchIndex
-	agg.batchCreatedBlockByIdx[batchIndex] = uint64(taskCreatedBlock)
-	agg.batchesIdentifierHashByIdx[batchIndex] = batchIdentifierHash
-	agg.batchDataByIdentifierHash[batchIdentifierHash] = BatchData{
-		BatchMerkleRoot: batchMerkleRoot,
-		SenderAddress:   senderAddress,
+	funding, err := retry.RetryWithData(func() (BatchFunding, error) {
+		return agg.fundingChecker.Check(senderAddress, respondToTaskFeeLimit)
+	}, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Warn("Could not check the batch funding, treating it as funded",
+			"merkleRoot", "0x"+hex.EncodeToString(batchMerkleRoot[:]),
+			"senderAddress", "0x"+hex.EncodeToString(senderAddress[:]),
+			"err", err)
+	} else if !funding.Funded {
+		agg.logger.Warn("Responding to batch wouldn't be compensated by the batcher",
+			"merkleRoot", "0x"+hex.EncodeToString(batchMerkleRoot[:]),
+			"senderAddress", "0x"+hex.EncodeToString(senderAddress[:]),
+			"reason", funding.Reason,
+			"respondToTaskFeeLimit", funding.FeeLimit,
+			"estimatedCost", funding.EstimatedCost,
+			"batcherBalance", funding.BatcherBalance,
+			"policy", agg.unfundedBatchesPolicy)
+		agg.metrics.IncAggregatorUnfundedBatches(funding.Reason, string(agg.unfundedBatchesPolicy))
+		switch agg.unfundedBatchesPolicy {
+		case SkipUnfundedBatches:
+			agg.transitionTask(batchIndex, TaskFailed)
+			return
+		case DeprioritizeUnfundedBatches:
+			agg.tasks.MarkUnfunded(batchIndex)
+		}
 	}
-	agg.batchStartTimeByIdx[batchIndex] = time.Now()
-	agg.logger.Info(
-		"Task Info added in aggregator:",
-		"Task", batchIndex,
-		"batchIdentifierHash", batchIdentifierHash,
-	)
-	agg.nextBatchIndex += 1
+
+	agg.tasks.SetTraceContext(batchIndex, agg.telemetry.InitNewTrace(batchMerkleRoot))
+
+	agg.taskMutex.Lock()
+	agg.AggregatorConfig.BaseConfig.Logger.Info("- Locked Resources: Adding new task")
 
 	quorumNums := eigentypes.QuorumNums{eigentypes.QuorumNum(QUORUM_NUMBER)}
 	quorumThresholdPercentages := eigentypes.QuorumThresholdPercentages{eigentypes.QuorumThresholdPercentage(QUORUM_THRESHOLD)}
 
-	err := agg.blsAggregationService.InitializeNewTaskWithWindow(batchIndex, taskCreatedBlock, quorumNums, quorumThresholdPercentages, agg.AggregatorConfig.Aggregator.BlsServiceTaskTimeout, 15*time.Second)
+	err = agg.blsAggregationService.InitializeNewTaskWithWindow(batchIndex, taskCreatedBlock, quorumNums, quorumThresholdPercentages, agg.AggregatorConfig.Aggregator.BlsServiceTaskTimeout, 15*time.Second)
 	if err != nil {
 		agg.logger.Fatalf("BLS aggregation service error when initializing new task: %s", err)
 	}
+	agg.transitionTask(batchIndex, TaskInitialized)
 
-	agg.metrics.IncAggregatorReceivedTasks()
+	agg.metrics.IncAggregatorReceivedTasks(QUORUM_NUMBER)
 	agg.taskMutex.Unlock()
 	agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Adding new task")
 	agg.logger.Info("New task added", "batchIndex", batchIndex, "batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
@@ -450,28 +656,18 @@ func (agg *Aggregator) ClearTasksFromMaps() {
 			agg.logger.Warn("No old tasks found")
 			continue // Retry in the next iteration
 		}
-		agg.taskMutex.Lock()
-		agg.AggregatorConfig.BaseConfig.Logger.Info("- Locked Resources: Cleaning finalized tasks")
-
-		taskIdxToDelete := agg.batchesIdxByIdentifierHash[*oldTaskIdHash]
+		oldTask, _ := agg.tasks.GetByIdentifierHash(*oldTaskIdHash)
+		taskIdxToDelete := oldTask.Index
 		agg.logger.Info("Old task found", "taskIndex", taskIdxToDelete)
 		// delete from lastIdxDeleted to taskIdxToDelete
 		for i := lastIdxDeleted + 1; i <= taskIdxToDelete; i++ {
-			batchIdentifierHash, exists := agg.batchesIdentifierHashByIdx[i]
-			if exists {
-				agg.logger.Info("Cleaning up finalized task", "taskIndex", i)
-				delete(agg.batchesIdxByIdentifierHash, batchIdentifierHash)
-				delete(agg.batchCreatedBlockByIdx, i)
-				delete(agg.batchesIdentifierHashByIdx, i)
-				delete(agg.batchDataByIdentifierHash, batchIdentifierHash)
-				delete(agg.batchStartTimeByIdx, i)
-			} else {
+			if err := agg.tasks.Remove(i); err != nil {
 				agg.logger.Warn("Task not found in maps", "taskIndex", i)
+			} else {
+				agg.logger.Info("Cleaning up finalized task", "taskIndex", i)
 			}
 		}
 		lastIdxDeleted = taskIdxToDelete
-		agg.taskMutex.Unlock()
-		agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Cleaning finalized tasks")
 		agg.AggregatorConfig.BaseConfig.Logger.Info("Done cleaning finalized tasks from maps")
 	}
 }
@@ -0,0 +1,51 @@
+package pkg
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BatchersAuthorization decides which batch senders the aggregator creates BLS tasks for, so
+// spoofed or misrouted batches don't trigger aggregation work.
+// With an allowlist configured only its addresses are authorized. Otherwise, a sender is
+// authorized if it's the batcher payment service or it has funds deposited in the service manager
+// to pay for its tasks.
+type BatchersAuthorization struct {
+	allowlist      map[common.Address]struct{}
+	paymentService common.Address
+	batcherBalance func(common.Address) (*big.Int, error)
+}
+
+func NewBatchersAuthorization(allowlist []common.Address, paymentService common.Address, batcherBalance func(common.Address) (*big.Int, error)) *BatchersAuthorization {
+	authorization := &BatchersAuthorization{
+		allowlist:      make(map[common.Address]struct{}, len(allowlist)),
+		paymentService: paymentService,
+		batcherBalance: batcherBalance,
+	}
+	for _, address := range allowlist {
+		authorization.allowlist[address] = struct{}{}
+	}
+	return authorization
+}
+
+// HasAllowlist returns whether only the allowlisted senders are authorized
+func (a *BatchersAuthorization) HasAllowlist() bool {
+	return len(a.allowlist) > 0
+}
+
+// IsAuthorized returns whether the sender is an authorized batcher
+func (a *BatchersAuthorization) IsAuthorized(senderAddress common.Address) (bool, error) {
+	if len(a.allowlist) > 0 {
+		_, ok := a.allowlist[senderAddress]
+		return ok, nil
+	}
+	if a.paymentService != (common.Address{}) && senderAddress == a.paymentService {
+		return true, nil
+	}
+	balance, err := a.batcherBalance(senderAddress)
+	if err != nil {
+		return false, err
+	}
+	return balance.Sign() > 0, nil
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBatchersAuthorization(t *testing.T) {
+	paymentService := common.HexToAddress("0x01")
+	funded := common.HexToAddress("0x02")
+	unfunded := common.HexToAddress("0x03")
+	balances := func(address common.Address) (*big.Int, error) {
+		if address == funded {
+			return big.NewInt(1), nil
+		}
+		return new(big.Int), nil
+	}
+
+	authorization := NewBatchersAuthorization(nil, paymentService, balances)
+	for address, expected := range map[common.Address]bool{paymentService: true, funded: true, unfunded: false} {
+		authorized, err := authorization.IsAuthorized(address)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if authorized != expected {
+			t.Errorf("Expected %s to be authorized %v, got %v", address, expected, authorized)
+		}
+	}
+
+	// The allowlist replaces the on-chain checks
+	authorization = NewBatchersAuthorization([]common.Address{unfunded}, paymentService, func(common.Address) (*big.Int, error) {
+		return nil, errors.New("unexpected balance query")
+	})
+	for address, expected := range map[common.Address]bool{paymentService: false, funded: false, unfunded: true} {
+		authorized, err := authorization.IsAuthorized(address)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if authorized != expected {
+			t.Errorf("Expected %s to be authorized %v with the allowlist, got %v", address, expected, authorized)
+		}
+	}
+
+	authorization = NewBatchersAuthorization(nil, paymentService, func(common.Address) (*big.Int, error) {
+		return nil, errors.New("rpc down")
+	})
+	if _, err := authorization.IsAuthorized(funded); err == nil {
+		t.Errorf("Expected the balance query error")
+	}
+}
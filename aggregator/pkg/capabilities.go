@@ -0,0 +1,215 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/types"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+const CapabilitiesEndpoint = "/capabilities"
+
+// OperatorsCapabilities stores the last capabilities advertised by each operator
+type OperatorsCapabilities struct {
+	capabilities map[eigentypes.OperatorId]types.OperatorCapabilities
+	mutex        sync.Mutex
+}
+
+func NewOperatorsCapabilities() *OperatorsCapabilities {
+	return &OperatorsCapabilities{
+		capabilities: make(map[eigentypes.OperatorId]types.OperatorCapabilities),
+	}
+}
+
+func (c *OperatorsCapabilities) Set(capabilities types.OperatorCapabilities) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.capabilities[capabilities.OperatorId] = capabilities
+}
+
+func (c *OperatorsCapabilities) Snapshot() map[eigentypes.OperatorId]types.OperatorCapabilities {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	snapshot := make(map[eigentypes.OperatorId]types.OperatorCapabilities, len(c.capabilities))
+	for operatorId, capabilities := range c.capabilities {
+		snapshot[operatorId] = capabilities
+	}
+	return snapshot
+}
+
+// ProvingSystemCapabilities is the support of a proving system among the registered operators
+type ProvingSystemCapabilities struct {
+	// PinnedVersion is the verifier version operators must run to be counted, if any
+	PinnedVersion string `json:"pinned_version,omitempty"`
+	Operators     int    `json:"operators"`
+	// StakePercentage is the percentage of the quorum stake of the operators that verify the proving system
+	StakePercentage float64 `json:"stake_percentage"`
+	// Versions maps each advertised verifier version to the percentage of the quorum stake running it
+	Versions map[string]float64 `json:"versions"`
+}
+
+// CapabilitiesSummary is the response of the capabilities endpoint
+type CapabilitiesSummary struct {
+	BlockNumber uint32 `json:"block_number"`
+	// Operators is the number of registered operators, UnknownOperators the ones that didn't advertise their capabilities
+	Operators        int                                   `json:"operators"`
+	UnknownOperators int                                   `json:"unknown_operators"`
+	ProvingSystems   map[string]*ProvingSystemCapabilities `json:"proving_systems"`
+}
+
+// ProcessOperatorCapabilities is called by operators via RPC when they connect, to advertise the proving
+// systems they verify. The capabilities are only stored if they are signed by a registered operator.
+// Returns:
+//   - 0: Success
+//   - 1: Error
+func (agg *Aggregator) ProcessOperatorCapabilities(capabilities *types.OperatorCapabilities, reply *uint8) error {
+	*reply = 1
+	operatorId := hex.EncodeToString(capabilities.OperatorId[:])
+	if err := utils.ValidateG1Point("signature", capabilities.BlsSignature.G1Point); err != nil {
+		agg.logger.Warn("invalid operator capabilities signature", "operatorId", operatorId, "err", err)
+		agg.securityEvents.Record(RejectedSignatureEvent, operatorId, "message", "capabilities", "err", err.Error())
+		return fmt.Errorf("invalid capabilities: %w", err)
+	}
+
+	operatorsState, _, err := agg.getOperatorsState()
+	if err != nil {
+		return err
+	}
+	operatorState, ok := operatorsState[capabilities.OperatorId]
+	if !ok {
+		agg.logger.Warn("Capabilities received from an operator that is not registered", "operatorId", operatorId)
+		return fmt.Errorf("operator %s is not registered", operatorId)
+	}
+
+	verified, err := capabilities.BlsSignature.Verify(operatorState.OperatorInfo.Pubkeys.G2Pubkey, capabilities.Digest())
+	if err != nil || !verified {
+		agg.logger.Warn("Invalid signature on operator capabilities", "operatorId", operatorId)
+		agg.securityEvents.Record(RejectedSignatureEvent, operatorId, "message", "capabilities")
+		return fmt.Errorf("invalid capabilities signature")
+	}
+
+	agg.operatorsCapabilities.Set(*capabilities)
+	agg.logger.Info("Operator capabilities updated", "operatorId", operatorId, "verifierVersions", capabilities.VerifierVersions)
+	*reply = 0
+	return nil
+}
+
+func (agg *Aggregator) getOperatorsState() (map[eigentypes.OperatorId]eigentypes.OperatorAvsState, uint32, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	blockNumber, err := agg.avsSubscriber.BlockNumberRetryable(ctx, retry.NetworkRetryParams())
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not get latest block number: %w", err)
+	}
+	operatorsState, err := agg.avsRegistryService.GetOperatorsAvsStateAtBlock(ctx, eigentypes.QuorumNums{eigentypes.QuorumNum(QUORUM_NUMBER)}, uint32(blockNumber))
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not get operators state: %w", err)
+	}
+	return operatorsState, uint32(blockNumber), nil
+}
+
+// handleCapabilities serves the proving systems supported by the registered operators, weighted by stake,
+// so batchers can avoid proving systems that too little stake can verify.
+func (agg *Aggregator) handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	operatorsState, blockNumber, err := agg.getOperatorsState()
+	if err != nil {
+		agg.logger.Error("Could not get operators state for capabilities", "err", err)
+		http.Error(w, "could not get operators state", http.StatusServiceUnavailable)
+		return
+	}
+
+	summary := SummarizeCapabilities(operatorsState, agg.operatorsCapabilities.Snapshot(), agg.AggregatorConfig.Aggregator.PinnedVerifierVersions)
+	summary.BlockNumber = blockNumber
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(summary); err != nil {
+		agg.logger.Error("Could not encode capabilities", "err", err)
+	}
+}
+
+// SummarizeCapabilities computes the stake share of every advertised proving system. Only registered operators
+// are considered, and when a proving system has a pinned version, operators running another one are not counted.
+func SummarizeCapabilities(operatorsState map[eigentypes.OperatorId]eigentypes.OperatorAvsState, capabilities map[eigentypes.OperatorId]types.OperatorCapabilities, pinnedVersions map[string]string) CapabilitiesSummary {
+	summary := CapabilitiesSummary{
+		Operators:      len(operatorsState),
+		ProvingSystems: make(map[string]*ProvingSystemCapabilities),
+	}
+	for provingSystem, version := range pinnedVersions {
+		summary.ProvingSystems[provingSystem] = &ProvingSystemCapabilities{PinnedVersion: version, Versions: make(map[string]float64)}
+	}
+
+	totalStake := new(big.Int)
+	for _, operatorState := range operatorsState {
+		totalStake.Add(totalStake, quorumStake(operatorState))
+	}
+
+	// Sorted so float additions are deterministic
+	operatorIds := make([]eigentypes.OperatorId, 0, len(operatorsState))
+	for operatorId := range operatorsState {
+		operatorIds = append(operatorIds, operatorId)
+	}
+	sort.Slice(operatorIds, func(i, j int) bool {
+		return hex.EncodeToString(operatorIds[i][:]) < hex.EncodeToString(operatorIds[j][:])
+	})
+
+	for _, operatorId := range operatorIds {
+		operatorCapabilities, ok := capabilities[operatorId]
+		if !ok {
+			summary.UnknownOperators++
+			continue
+		}
+		stakePercentage := percentage(quorumStake(operatorsState[operatorId]), totalStake)
+
+		for provingSystem, version := range operatorCapabilities.VerifierVersions {
+			provingSystemCapabilities, ok := summary.ProvingSystems[provingSystem]
+			if !ok {
+				provingSystemCapabilities = &ProvingSystemCapabilities{Versions: make(map[string]float64)}
+				summary.ProvingSystems[provingSystem] = provingSystemCapabilities
+			}
+			provingSystemCapabilities.Versions[version] += stakePercentage
+
+			if provingSystemCapabilities.PinnedVersion != "" && provingSystemCapabilities.PinnedVersion != version {
+				continue
+			}
+			provingSystemCapabilities.Operators++
+			provingSystemCapabilities.StakePercentage += stakePercentage
+		}
+	}
+
+	return summary
+}
+
+func quorumStake(operatorState eigentypes.OperatorAvsState) *big.Int {
+	stake, ok := operatorState.StakePerQuorum[eigentypes.QuorumNum(QUORUM_NUMBER)]
+	if !ok || stake == nil {
+		return new(big.Int)
+	}
+	return stake
+}
+
+func percentage(stake *big.Int, totalStake *big.Int) float64 {
+	if totalStake.Sign() == 0 {
+		return 0
+	}
+	result, _ := new(big.Float).Quo(
+		new(big.Float).Mul(new(big.Float).SetInt(stake), big.NewFloat(100)),
+		new(big.Float).SetInt(totalStake),
+	).Float64()
+	return result
+}
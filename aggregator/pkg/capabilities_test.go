@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+func operatorState(stake int64) eigentypes.OperatorAvsState {
+	return eigentypes.OperatorAvsState{
+		StakePerQuorum: map[eigentypes.QuorumNum]eigentypes.StakeAmount{eigentypes.QuorumNum(QUORUM_NUMBER): big.NewInt(stake)},
+	}
+}
+
+func TestSummarizeCapabilities(t *testing.T) {
+	operatorsState := map[eigentypes.OperatorId]eigentypes.OperatorAvsState{
+		{1}: operatorState(50),
+		{2}: operatorState(30),
+		{3}: operatorState(20),
+	}
+	capabilities := map[eigentypes.OperatorId]types.OperatorCapabilities{
+		{1}: {OperatorId: [32]byte{1}, VerifierVersions: map[string]string{"SP1": "sp1-v3.0.0", "Risc0": "risc0-v1.1.2"}},
+		{2}: {OperatorId: [32]byte{2}, VerifierVersions: map[string]string{"SP1": "sp1-v1.0.1"}},
+		// Not registered, must be ignored
+		{4}: {OperatorId: [32]byte{4}, VerifierVersions: map[string]string{"Risc0": "risc0-v1.1.2"}},
+	}
+
+	summary := SummarizeCapabilities(operatorsState, capabilities, map[string]string{"SP1": "sp1-v3.0.0"})
+
+	if summary.Operators != 3 || summary.UnknownOperators != 1 {
+		t.Errorf("Expected 3 operators and 1 unknown, got %d and %d", summary.Operators, summary.UnknownOperators)
+	}
+
+	sp1 := summary.ProvingSystems["SP1"]
+	if sp1.Operators != 1 || sp1.StakePercentage != 50 {
+		t.Errorf("Expected SP1 pinned version to be supported by 1 operator with 50%% of the stake, got %d with %f", sp1.Operators, sp1.StakePercentage)
+	}
+	if sp1.Versions["sp1-v1.0.1"] != 30 {
+		t.Errorf("Expected 30%% of the stake running sp1-v1.0.1, got %f", sp1.Versions["sp1-v1.0.1"])
+	}
+
+	risc0 := summary.ProvingSystems["Risc0"]
+	if risc0.Operators != 1 || risc0.StakePercentage != 50 {
+		t.Errorf("Expected Risc0 to be supported by 1 operator with 50%% of the stake, got %d with %f", risc0.Operators, risc0.StakePercentage)
+	}
+}
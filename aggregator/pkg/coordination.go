@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	_ "github.com/lib/pq"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+const DefaultResponseClaimTtl = 30 * time.Minute
+
+const responseClaimsSchema = `CREATE TABLE IF NOT EXISTS aggregator_response_claims (
+	batch_identifier_hash BYTEA PRIMARY KEY,
+	replica_id TEXT NOT NULL,
+	claimed_at TIMESTAMPTZ NOT NULL
+)`
+
+// ResponseClaims is shared by the aggregator replicas, so a single one responds to each batch
+type ResponseClaims interface {
+	// Claim returns whether this replica may respond to the batch, claiming it. Claims expire after
+	// a while, so the batch is responded by another replica if the one that claimed it failed.
+	Claim(ctx context.Context, batchIdentifierHash [32]byte) (bool, error)
+}
+
+// PostgresResponseClaims stores the claims of the replicas in a database shared by them
+type PostgresResponseClaims struct {
+	db        *sql.DB
+	replicaId string
+	ttl       time.Duration
+}
+
+// NewPostgresResponseClaims connects to the database and creates the claims table if missing.
+// The ttl must be longer than the time a replica takes to respond, bumping the gas price included.
+func NewPostgresResponseClaims(ctx context.Context, databaseUrl string, replicaId string, ttl time.Duration) (*PostgresResponseClaims, error) {
+	db, err := sql.Open("postgres", databaseUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, responseClaimsSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not create tables: %w", err)
+	}
+	return &PostgresResponseClaims{db: db, replicaId: replicaId, ttl: ttl}, nil
+}
+
+// Claim inserts the claim of the batch, or takes it over if it's from this replica or expired
+func (c *PostgresResponseClaims) Claim(ctx context.Context, batchIdentifierHash [32]byte) (bool, error) {
+	rows, err := c.db.QueryContext(ctx, `INSERT INTO aggregator_response_claims (batch_identifier_hash, replica_id, claimed_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (batch_identifier_hash) DO UPDATE SET replica_id = EXCLUDED.replica_id, claimed_at = EXCLUDED.claimed_at
+		WHERE aggregator_response_claims.replica_id = EXCLUDED.replica_id
+			OR aggregator_response_claims.claimed_at < now() - make_interval(secs => $3)
+		RETURNING replica_id`,
+		batchIdentifierHash[:], c.replicaId, c.ttl.Seconds())
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	claimed := rows.Next()
+	return claimed, rows.Err()
+}
+
+func (c *PostgresResponseClaims) Close() error {
+	return c.db.Close()
+}
+
+// ResponseCoordinator checks no other aggregator replica responded or is responding to a batch
+// before sending its response, so they don't waste gas or conflict on nonces
+type ResponseCoordinator struct {
+	batchState func(batchMerkleRoot [32]byte, senderAddress common.Address) (chainio.BatchState, error)
+	// claims is nil when running a single aggregator
+	claims ResponseClaims
+}
+
+func NewResponseCoordinator(batchState func([32]byte, common.Address) (chainio.BatchState, error), claims ResponseClaims) *ResponseCoordinator {
+	return &ResponseCoordinator{batchState: batchState, claims: claims}
+}
+
+// ShouldRespond returns whether this replica must respond to the batch. When it mustn't, the
+// reason is "responded" if the batch was already responded on-chain and "claimed" if another
+// replica claimed it. The on-chain check is skipped if the state can't be read, since the claims
+// still prevent the double submissions.
+func (c *ResponseCoordinator) ShouldRespond(ctx context.Context, batchIdentifierHash [32]byte, batchMerkleRoot [32]byte, senderAddress common.Address) (bool, string, error) {
+	state, err := c.batchState(batchMerkleRoot, senderAddress)
+	if err == nil && state.Responded {
+		return false, "responded", nil
+	}
+	if c.claims == nil {
+		return true, "", nil
+	}
+	claimed, err := c.claims.Claim(ctx, batchIdentifierHash)
+	if err != nil {
+		return false, "", fmt.Errorf("could not claim batch response: %w", err)
+	}
+	if !claimed {
+		return false, "claimed", nil
+	}
+	return true, "", nil
+}
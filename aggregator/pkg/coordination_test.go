@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+// fakeResponseClaims grants each batch to the first replica claiming it
+type fakeResponseClaims struct {
+	claims map[[32]byte]string
+	err    error
+}
+
+func (c *fakeResponseClaims) replica(replicaId string) ResponseClaims {
+	return replicaClaims{claims: c, replicaId: replicaId}
+}
+
+type replicaClaims struct {
+	claims    *fakeResponseClaims
+	replicaId string
+}
+
+func (r replicaClaims) Claim(_ context.Context, batchIdentifierHash [32]byte) (bool, error) {
+	if r.claims.err != nil {
+		return false, r.claims.err
+	}
+	if claimant, ok := r.claims.claims[batchIdentifierHash]; ok {
+		return claimant == r.replicaId, nil
+	}
+	r.claims.claims[batchIdentifierHash] = r.replicaId
+	return true, nil
+}
+
+func TestResponseCoordinatorRespondsOnce(t *testing.T) {
+	responded := map[[32]byte]bool{{2}: true}
+	batchState := func(batchMerkleRoot [32]byte, _ common.Address) (chainio.BatchState, error) {
+		return chainio.BatchState{TaskCreatedBlock: 1, Responded: responded[batchMerkleRoot]}, nil
+	}
+	claims := &fakeResponseClaims{claims: make(map[[32]byte]string)}
+	first := NewResponseCoordinator(batchState, claims.replica("first"))
+	second := NewResponseCoordinator(batchState, claims.replica("second"))
+
+	should, _, err := first.ShouldRespond(context.Background(), [32]byte{1}, [32]byte{1}, common.Address{})
+	if err != nil || !should {
+		t.Fatalf("Expected the first replica to respond, got %v and %v", should, err)
+	}
+	should, reason, err := second.ShouldRespond(context.Background(), [32]byte{1}, [32]byte{1}, common.Address{})
+	if err != nil || should || reason != "claimed" {
+		t.Errorf("Expected the second replica to skip the claimed batch, got %v, %q and %v", should, reason, err)
+	}
+	should, reason, _ = second.ShouldRespond(context.Background(), [32]byte{2}, [32]byte{2}, common.Address{})
+	if should || reason != "responded" {
+		t.Errorf("Expected the batch responded on-chain to be skipped, got %v and %q", should, reason)
+	}
+
+	claims.err = errors.New("database down")
+	if should, _, err := first.ShouldRespond(context.Background(), [32]byte{3}, [32]byte{3}, common.Address{}); err == nil || should {
+		t.Errorf("Expected to refuse responding when the claims can't be checked, got %v and %v", should, err)
+	}
+
+	single := NewResponseCoordinator(func([32]byte, common.Address) (chainio.BatchState, error) {
+		return chainio.BatchState{}, errors.New("rpc down")
+	}, nil)
+	if should, _, err := single.ShouldRespond(context.Background(), [32]byte{3}, [32]byte{3}, common.Address{}); err != nil || !should {
+		t.Errorf("Expected a single aggregator to respond when the batch state can't be read, got %v and %v", should, err)
+	}
+}
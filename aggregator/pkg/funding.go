@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// UnfundedBatchesPolicy is what the aggregator does with the batches whose response wouldn't be
+// compensated by the batcher
+type UnfundedBatchesPolicy string
+
+const (
+	// RespondUnfundedBatches responds to them anyway
+	RespondUnfundedBatches UnfundedBatchesPolicy = "respond"
+	// DeprioritizeUnfundedBatches waits for them to be funded before responding, up to a max delay
+	DeprioritizeUnfundedBatches UnfundedBatchesPolicy = "deprioritize"
+	// SkipUnfundedBatches doesn't aggregate their signatures
+	SkipUnfundedBatches UnfundedBatchesPolicy = "skip"
+)
+
+const (
+	// DefaultRespondToTaskGasEstimate is the gas a response takes, as estimated by the batcher
+	DefaultRespondToTaskGasEstimate = 330_000
+	DefaultUnfundedBatchesMaxDelay  = 10 * time.Minute
+	// unfundedBatchRecheckInterval is how often the funding of a deprioritized batch is checked again
+	unfundedBatchRecheckInterval = 12 * time.Second
+)
+
+// BatchFunding is whether responding to a batch will be compensated by its batcher
+type BatchFunding struct {
+	Funded bool
+	// Reason is why it isn't funded: "batcher_balance" if the batcher doesn't have the fee limit
+	// deposited, so the response would revert, or "fee_limit" if the response would cost more
+	// than the fee limit
+	Reason         string
+	FeeLimit       *big.Int
+	EstimatedCost  *big.Int
+	BatcherBalance *big.Int
+}
+
+// FundingChecker estimates whether the responses are compensated, before the aggregator works on them
+type FundingChecker struct {
+	// gasPrice returns the gas price the aggregator would respond with
+	gasPrice       func() (*big.Int, error)
+	batcherBalance func(common.Address) (*big.Int, error)
+	gasEstimate    uint64
+}
+
+func NewFundingChecker(gasPrice func() (*big.Int, error), batcherBalance func(common.Address) (*big.Int, error), gasEstimate uint64) *FundingChecker {
+	if gasEstimate == 0 {
+		gasEstimate = DefaultRespondToTaskGasEstimate
+	}
+	return &FundingChecker{gasPrice: gasPrice, batcherBalance: batcherBalance, gasEstimate: gasEstimate}
+}
+
+// Check returns whether the response to the batch of the sender with the fee limit is funded
+func (c *FundingChecker) Check(senderAddress common.Address, feeLimit *big.Int) (BatchFunding, error) {
+	funding := BatchFunding{Funded: true, FeeLimit: feeLimit}
+
+	balance, err := c.batcherBalance(senderAddress)
+	if err != nil {
+		return funding, err
+	}
+	funding.BatcherBalance = balance
+	if balance.Cmp(feeLimit) < 0 {
+		funding.Funded, funding.Reason = false, "batcher_balance"
+		return funding, nil
+	}
+
+	gasPrice, err := c.gasPrice()
+	if err != nil {
+		return funding, err
+	}
+	funding.EstimatedCost = new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(c.gasEstimate))
+	if funding.EstimatedCost.Cmp(feeLimit) > 0 {
+		funding.Funded, funding.Reason = false, "fee_limit"
+	}
+	return funding, nil
+}
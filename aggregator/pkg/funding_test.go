@@ -0,0 +1,36 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestFundingChecker(t *testing.T) {
+	rich, poor := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	balances := func(address common.Address) (*big.Int, error) {
+		if address == rich {
+			return big.NewInt(1_000_000_000), nil
+		}
+		return big.NewInt(1000), nil
+	}
+	gasPrice := big.NewInt(10)
+	checker := NewFundingChecker(func() (*big.Int, error) { return gasPrice, nil }, balances, 100)
+
+	funding, err := checker.Check(rich, big.NewInt(1000))
+	if err != nil || !funding.Funded {
+		t.Fatalf("Expected the response costing the fee limit to be funded, got %+v and %v", funding, err)
+	}
+
+	funding, _ = checker.Check(poor, big.NewInt(2000))
+	if funding.Funded || funding.Reason != "batcher_balance" {
+		t.Errorf("Expected the batcher without the fee limit deposited to be unfunded, got %+v", funding)
+	}
+
+	gasPrice = big.NewInt(11)
+	funding, _ = checker.Check(rich, big.NewInt(1000))
+	if funding.Funded || funding.Reason != "fee_limit" || funding.EstimatedCost.Cmp(big.NewInt(1100)) != 0 {
+		t.Errorf("Expected the response costing more than the fee limit to be unfunded, got %+v", funding)
+	}
+}
@@ -0,0 +1,202 @@
+package pkg
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/metrics"
+	"golang.org/x/time/rate"
+)
+
+const (
+	DefaultIpRequestsPerSecond = 10
+	DefaultIpBurst             = 50
+	DefaultIpBanThreshold      = 100
+	DefaultIpBanDuration       = 10 * time.Minute
+
+	// ipThrottlePrunePeriod is how often the state of the IPs not seen lately is dropped
+	ipThrottlePrunePeriod = time.Minute
+)
+
+// IpThrottle rate limits the requests of each IP to the operators server, independently of the
+// operator identity checks. IPs that keep exceeding the rate are banned for a while, and the
+// configured ones are always banned.
+type IpThrottle struct {
+	requestsPerSecond rate.Limit
+	burst             int
+	banThreshold      int
+	banDuration       time.Duration
+	bannedIps         map[string]struct{}
+	metrics           *metrics.Metrics
+	securityEvents    *SecurityEventLog
+	now               func() time.Time
+
+	mutex     sync.Mutex
+	ips       map[string]*ipState
+	lastPrune time.Time
+}
+
+type ipState struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+	// rejected is the number of requests rejected since the IP was last allowed or banned
+	rejected    int
+	bannedUntil time.Time
+}
+
+// NewIpThrottle creates a throttle allowing requestsPerSecond with bursts of burst requests to each
+// IP, banning for banDuration the IPs with banThreshold requests rejected in a row. The unset
+// values are the defaults. The rejected requests and bans are recorded to the security events.
+func NewIpThrottle(requestsPerSecond float64, burst int, banThreshold int, banDuration time.Duration, bannedIps []string, metrics *metrics.Metrics, securityEvents *SecurityEventLog) *IpThrottle {
+	throttle := &IpThrottle{
+		requestsPerSecond: DefaultIpRequestsPerSecond,
+		burst:             DefaultIpBurst,
+		banThreshold:      DefaultIpBanThreshold,
+		banDuration:       DefaultIpBanDuration,
+		bannedIps:         make(map[string]struct{}, len(bannedIps)),
+		metrics:           metrics,
+		securityEvents:    securityEvents,
+		now:               time.Now,
+		ips:               make(map[string]*ipState),
+	}
+	if requestsPerSecond > 0 {
+		throttle.requestsPerSecond = rate.Limit(requestsPerSecond)
+	}
+	if burst > 0 {
+		throttle.burst = burst
+	}
+	if banThreshold > 0 {
+		throttle.banThreshold = banThreshold
+	}
+	if banDuration > 0 {
+		throttle.banDuration = banDuration
+	}
+	for _, ip := range bannedIps {
+		if parsed := net.ParseIP(ip); parsed != nil {
+			ip = parsed.String()
+		}
+		throttle.bannedIps[ip] = struct{}{}
+	}
+	return throttle
+}
+
+// Allow returns whether a request of the IP is allowed, counting it
+func (t *IpThrottle) Allow(ip string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.bannedIps[ip]; ok {
+		t.metrics.IncAggregatorThrottledRequests("banned")
+		t.securityEvents.Record(RateLimitedEvent, ip, "reason", "banned")
+		return false
+	}
+
+	now := t.now()
+	t.prune(now)
+	state, ok := t.ips[ip]
+	if !ok {
+		state = &ipState{limiter: rate.NewLimiter(t.requestsPerSecond, t.burst)}
+		t.ips[ip] = state
+	}
+	state.lastSeen = now
+
+	if now.Before(state.bannedUntil) {
+		t.metrics.IncAggregatorThrottledRequests("banned")
+		t.securityEvents.Record(RateLimitedEvent, ip, "reason", "banned")
+		return false
+	}
+	if state.limiter.AllowN(now, 1) {
+		state.rejected = 0
+		return true
+	}
+
+	t.metrics.IncAggregatorThrottledRequests("rate_limited")
+	t.securityEvents.Record(RateLimitedEvent, ip, "reason", "rate_limited")
+	state.rejected++
+	if state.rejected >= t.banThreshold {
+		state.rejected = 0
+		state.bannedUntil = now.Add(t.banDuration)
+		t.metrics.IncAggregatorIpBans()
+		t.securityEvents.Record(IpBannedEvent, ip, "until", state.bannedUntil.UTC().Format(time.RFC3339))
+	}
+	return false
+}
+
+// Banned returns whether the IP is banned, without counting a request
+func (t *IpThrottle) Banned(ip string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.bannedIps[ip]; ok {
+		return true
+	}
+	state, ok := t.ips[ip]
+	return ok && t.now().Before(state.bannedUntil)
+}
+
+// prune drops the IPs that aren't banned and whose rate limit is already replenished
+func (t *IpThrottle) prune(now time.Time) {
+	if now.Sub(t.lastPrune) < ipThrottlePrunePeriod {
+		return
+	}
+	t.lastPrune = now
+	replenished := time.Duration(float64(t.burst) / float64(t.requestsPerSecond) * float64(time.Second))
+	for ip, state := range t.ips {
+		if now.After(state.bannedUntil) && now.Sub(state.lastSeen) > replenished {
+			delete(t.ips, ip)
+		}
+	}
+}
+
+// Handler rejects the requests of the throttled IPs, with 403 if banned and 429 if rate limited
+func (t *IpThrottle) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := remoteIp(r.RemoteAddr)
+		if !t.Allow(ip) {
+			if t.Banned(ip) {
+				http.Error(w, "banned", http.StatusForbidden)
+			} else {
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Listener closes the connections of the banned IPs as soon as they are accepted
+func (t *IpThrottle) Listener(listener net.Listener) net.Listener {
+	return &throttledListener{Listener: listener, throttle: t}
+}
+
+type throttledListener struct {
+	net.Listener
+	throttle *IpThrottle
+}
+
+func (l *throttledListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if !l.throttle.Banned(remoteIp(conn.RemoteAddr().String())) {
+			return conn, nil
+		}
+		conn.Close()
+	}
+}
+
+// remoteIp returns the IP of the address, formatted like the configured banned IPs
+func remoteIp(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+	return host
+}
@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+func newTestIpThrottle(t *testing.T, bannedIps []string) (*IpThrottle, *time.Time) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("Could not create logger: %v", err)
+	}
+	// 1 request per second with bursts of 2, banning for a minute after 2 rejected requests
+	throttle := NewIpThrottle(1, 2, 2, time.Minute, bannedIps, metrics.NewMetrics("", prometheus.NewRegistry(), logger), nil)
+	now := time.Unix(1700000000, 0)
+	throttle.now = func() time.Time { return now }
+	return throttle, &now
+}
+
+func TestIpThrottleBansIpsExceedingTheRate(t *testing.T) {
+	throttle, now := newTestIpThrottle(t, nil)
+
+	if !throttle.Allow("192.0.2.1") || !throttle.Allow("192.0.2.1") {
+		t.Fatalf("Expected the burst to be allowed")
+	}
+	if throttle.Allow("192.0.2.1") {
+		t.Fatalf("Expected the request over the burst to be rejected")
+	}
+	if !throttle.Allow("192.0.2.2") {
+		t.Fatalf("Expected other IPs to be allowed")
+	}
+	if throttle.Allow("192.0.2.1") || !throttle.Banned("192.0.2.1") {
+		t.Fatalf("Expected the IP to be banned after 2 rejected requests")
+	}
+
+	*now = now.Add(30 * time.Second)
+	if throttle.Allow("192.0.2.1") {
+		t.Errorf("Expected the IP to be banned while the ban lasts, even with its rate replenished")
+	}
+
+	*now = now.Add(time.Minute)
+	if throttle.Banned("192.0.2.1") || !throttle.Allow("192.0.2.1") {
+		t.Errorf("Expected the IP to be allowed once the ban expired")
+	}
+}
+
+func TestIpThrottleHandler(t *testing.T) {
+	throttle, _ := newTestIpThrottle(t, []string{"192.0.2.3"})
+	handler := throttle.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(remoteAddr string) int {
+		request := httptest.NewRequest(http.MethodGet, CapabilitiesEndpoint, nil)
+		request.RemoteAddr = remoteAddr
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	if code := serve("192.0.2.3:1234"); code != http.StatusForbidden {
+		t.Errorf("Expected the configured banned IP to be forbidden, got %d", code)
+	}
+	serve("192.0.2.1:1234")
+	serve("192.0.2.1:1235")
+	if code := serve("192.0.2.1:1236"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected the request over the burst to be rate limited, got %d", code)
+	}
+	if code := serve("192.0.2.1:1237"); code != http.StatusForbidden {
+		t.Errorf("Expected the IP to be banned, got %d", code)
+	}
+}
@@ -0,0 +1,224 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+const (
+	DefaultRpcMaxMessageSize = 1024 * 1024
+	DefaultRpcMaxConnections = 1024
+	DefaultRpcIdleTimeout    = 5 * time.Minute
+	DefaultRpcReadTimeout    = 30 * time.Second
+	DefaultRpcWriteTimeout   = 30 * time.Second
+
+	// rpcMaxHeaderBytes bounds the HTTP headers, operators only send the CONNECT request to start the RPC connection
+	rpcMaxHeaderBytes = 16 * 1024
+	// rpcConnected is the status net/rpc clients expect in response to the CONNECT request
+	rpcConnected = "200 Connected to Go RPC"
+)
+
+var errRpcMessageTooLarge = errors.New("rpc message too large")
+
+// RpcLimits bound the resources a client of the operators server can hold
+type RpcLimits struct {
+	// MaxMessageSize is the max bytes of each gob or protobuf message of the RPC requests, their header and body
+	MaxMessageSize int64
+	// MaxConnections is the max number of open connections, new ones wait until others are closed
+	MaxConnections int
+	// IdleTimeout is the time a RPC connection is kept open without requests
+	IdleTimeout time.Duration
+	// ReadTimeout is the time to read a request once started, WriteTimeout the time to write a response
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// NewRpcLimits returns the given limits, with the defaults for the unset ones
+func NewRpcLimits(maxMessageSize int64, maxConnections int, idleTimeout time.Duration, readTimeout time.Duration, writeTimeout time.Duration) RpcLimits {
+	limits := RpcLimits{
+		MaxMessageSize: DefaultRpcMaxMessageSize,
+		MaxConnections: DefaultRpcMaxConnections,
+		IdleTimeout:    DefaultRpcIdleTimeout,
+		ReadTimeout:    DefaultRpcReadTimeout,
+		WriteTimeout:   DefaultRpcWriteTimeout,
+	}
+	if maxMessageSize > 0 {
+		limits.MaxMessageSize = maxMessageSize
+	}
+	if maxConnections > 0 {
+		limits.MaxConnections = maxConnections
+	}
+	if idleTimeout > 0 {
+		limits.IdleTimeout = idleTimeout
+	}
+	if readTimeout > 0 {
+		limits.ReadTimeout = readTimeout
+	}
+	if writeTimeout > 0 {
+		limits.WriteTimeout = writeTimeout
+	}
+	return limits
+}
+
+// rpcHandler serves net/rpc over HTTP like rpc.Server.ServeHTTP, but enforcing the limits on the
+// hijacked connections, which the HTTP server timeouts don't cover
+type rpcHandler struct {
+	server *rpc.Server
+	limits RpcLimits
+	logger logging.Logger
+	// protobuf is whether the messages are the protobuf ones of core/types/pb instead of gob
+	protobuf bool
+}
+
+func (h *rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "405 must CONNECT", http.StatusMethodNotAllowed)
+		return
+	}
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		h.logger.Error("Could not hijack RPC connection", "remoteAddr", r.RemoteAddr, "err", err)
+		return
+	}
+	conn.SetWriteDeadline(time.Now().Add(h.limits.WriteTimeout))
+	if _, err := io.WriteString(conn, "HTTP/1.0 "+rpcConnected+"\n\n"); err != nil {
+		conn.Close()
+		return
+	}
+	if h.protobuf {
+		h.server.ServeCodec(newProtoServerCodec(conn, h.limits))
+		return
+	}
+	h.server.ServeCodec(newLimitedServerCodec(conn, h.limits))
+}
+
+// limitedServerCodec is the gob codec of net/rpc, failing on messages larger than the max message size
+// and closing connections that stay idle or stall reading a request or writing a response
+type limitedServerCodec struct {
+	conn   net.Conn
+	limits RpcLimits
+	dec    *gob.Decoder
+	enc    *gob.Encoder
+	encBuf *bufio.Writer
+	closed bool
+}
+
+func newLimitedServerCodec(conn net.Conn, limits RpcLimits) *limitedServerCodec {
+	reader := &gobMessageLimitReader{r: bufio.NewReader(conn), limit: uint64(limits.MaxMessageSize)}
+	encBuf := bufio.NewWriter(conn)
+	return &limitedServerCodec{
+		conn:   conn,
+		limits: limits,
+		dec:    gob.NewDecoder(reader),
+		enc:    gob.NewEncoder(encBuf),
+		encBuf: encBuf,
+	}
+}
+
+func (c *limitedServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	c.conn.SetReadDeadline(time.Now().Add(c.limits.IdleTimeout))
+	if err := c.dec.Decode(r); err != nil {
+		return err
+	}
+	c.conn.SetReadDeadline(time.Now().Add(c.limits.ReadTimeout))
+	return nil
+}
+
+func (c *limitedServerCodec) ReadRequestBody(body any) error {
+	return c.dec.Decode(body)
+}
+
+func (c *limitedServerCodec) WriteResponse(r *rpc.Response, body any) error {
+	c.conn.SetWriteDeadline(time.Now().Add(c.limits.WriteTimeout))
+	if err := c.enc.Encode(r); err != nil {
+		if c.encBuf.Flush() == nil {
+			// Couldn't encode the header, shut down the connection
+			c.Close()
+		}
+		return err
+	}
+	if err := c.enc.Encode(body); err != nil {
+		if c.encBuf.Flush() == nil {
+			// Couldn't encode the body, shut down the connection
+			c.Close()
+		}
+		return err
+	}
+	return c.encBuf.Flush()
+}
+
+func (c *limitedServerCodec) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
+
+// gobMessageLimitReader reads a stream of gob messages, failing on the messages larger than the
+// limit before the decoder allocates them. Each message is prefixed by its length, encoded as a
+// gob unsigned integer: a single byte if lower than 128, otherwise the negated byte count followed
+// by the big-endian bytes.
+type gobMessageLimitReader struct {
+	r     *bufio.Reader
+	limit uint64
+	// prefix is the length prefix of the current message not yet returned
+	prefix []byte
+	// remaining is the number of bytes of the current message not yet returned
+	remaining uint64
+}
+
+func (l *gobMessageLimitReader) Read(p []byte) (int, error) {
+	if len(l.prefix) == 0 && l.remaining == 0 {
+		if err := l.readPrefix(); err != nil {
+			return 0, err
+		}
+	}
+	if len(l.prefix) > 0 {
+		n := copy(p, l.prefix)
+		l.prefix = l.prefix[n:]
+		return n, nil
+	}
+	if uint64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= uint64(n)
+	return n, err
+}
+
+func (l *gobMessageLimitReader) readPrefix() error {
+	first, err := l.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	l.prefix = []byte{first}
+	if first < 0x80 {
+		l.remaining = uint64(first)
+	} else {
+		byteCount := -int(int8(first))
+		if byteCount > 8 {
+			return errRpcMessageTooLarge
+		}
+		length := make([]byte, byteCount)
+		if _, err := io.ReadFull(l.r, length); err != nil {
+			return err
+		}
+		for _, b := range length {
+			l.remaining = l.remaining<<8 | uint64(b)
+		}
+		l.prefix = append(l.prefix, length...)
+	}
+	if l.remaining > l.limit {
+		return errRpcMessageTooLarge
+	}
+	return nil
+}
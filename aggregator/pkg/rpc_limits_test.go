@@ -0,0 +1,152 @@
+package pkg
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/rpc"
+	"strings"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/core/types"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+type echoService struct{}
+
+func (echoService) Echo(message string, reply *string) error {
+	*reply = message
+	return nil
+}
+
+func (echoService) EchoCapabilities(capabilities *types.OperatorCapabilities, reply *types.OperatorCapabilities) error {
+	if capabilities.OperatorId == (eigentypes.OperatorId{}) {
+		return errors.New("missing operator id")
+	}
+	*reply = *capabilities
+	return nil
+}
+
+func startOperatorsServer(t *testing.T, limits RpcLimits) string {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("Could not create logger: %v", err)
+	}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("Echo", echoService{}); err != nil {
+		t.Fatalf("Could not register service: %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Could not listen: %v", err)
+	}
+	throttle := NewIpThrottle(0, 0, 0, 0, nil, metrics.NewMetrics("", prometheus.NewRegistry(), logger), nil)
+	go serveOperators(listener, rpcServer, http.NotFoundHandler(), limits, throttle, logger)
+	t.Cleanup(func() { listener.Close() })
+	return listener.Addr().String()
+}
+
+func TestOperatorsServerRejectsLargeMessages(t *testing.T) {
+	addr := startOperatorsServer(t, NewRpcLimits(1024, 0, 0, 0, 0))
+
+	client, err := rpc.DialHTTP("tcp", addr)
+	if err != nil {
+		t.Fatalf("Could not dial: %v", err)
+	}
+	defer client.Close()
+
+	var reply string
+	if err := client.Call("Echo.Echo", "hello", &reply); err != nil || reply != "hello" {
+		t.Fatalf("Expected the small message to be echoed, got %q and %v", reply, err)
+	}
+	if err := client.Call("Echo.Echo", strings.Repeat("a", 2048), &reply); err == nil {
+		t.Fatalf("Expected the large message to be rejected")
+	}
+	if err := client.Call("Echo.Echo", "hello", &reply); err == nil {
+		t.Errorf("Expected the connection to be closed after the large message")
+	}
+}
+
+func TestOperatorsServerClosesIdleConnections(t *testing.T) {
+	addr := startOperatorsServer(t, NewRpcLimits(0, 0, 100*time.Millisecond, 0, 0))
+
+	client, err := rpc.DialHTTP("tcp", addr)
+	if err != nil {
+		t.Fatalf("Could not dial: %v", err)
+	}
+	defer client.Close()
+
+	var reply string
+	if err := client.Call("Echo.Echo", "hello", &reply); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if err := client.Call("Echo.Echo", "hello", &reply); err == nil {
+		t.Errorf("Expected the idle connection to be closed")
+	}
+}
+
+func TestOperatorsServerLimitsConnections(t *testing.T) {
+	addr := startOperatorsServer(t, NewRpcLimits(0, 1, 0, 0, 0))
+
+	first, err := rpc.DialHTTP("tcp", addr)
+	if err != nil {
+		t.Fatalf("Could not dial: %v", err)
+	}
+
+	// The second connection waits until the first one is closed
+	connected := make(chan *rpc.Client)
+	go func() {
+		second, err := rpc.DialHTTP("tcp", addr)
+		if err != nil {
+			t.Errorf("Could not dial: %v", err)
+		}
+		connected <- second
+	}()
+	select {
+	case <-connected:
+		t.Fatalf("Expected the second connection to wait")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	first.Close()
+	select {
+	case second := <-connected:
+		if second != nil {
+			second.Close()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the second connection once the first one was closed")
+	}
+}
+
+func TestOperatorsServerServesProtobufMessages(t *testing.T) {
+	addr := startOperatorsServer(t, NewRpcLimits(1024, 0, 0, 0, 0))
+
+	client, err := types.DialProtoRpc(addr)
+	if err != nil {
+		t.Fatalf("Could not dial: %v", err)
+	}
+	defer client.Close()
+
+	capabilities := types.OperatorCapabilities{OperatorId: eigentypes.OperatorId{1}, VerifierVersions: map[string]string{"SP1": "v3.0.0"}}
+	var reply types.OperatorCapabilities
+	if err := client.Call("Echo.EchoCapabilities", &capabilities, &reply); err != nil || reply.Digest() != capabilities.Digest() {
+		t.Fatalf("Expected the capabilities to be echoed, got %+v and %v", reply, err)
+	}
+	if err := client.Call("Echo.EchoCapabilities", &types.OperatorCapabilities{}, &reply); err == nil || err.Error() != "missing operator id" {
+		t.Fatalf("Expected the error of the call, got %v", err)
+	}
+
+	large := types.OperatorCapabilities{OperatorId: eigentypes.OperatorId{1}, VerifierVersions: map[string]string{"SP1": strings.Repeat("a", 2048)}}
+	if err := client.Call("Echo.EchoCapabilities", &large, &reply); err == nil {
+		t.Fatalf("Expected the large message to be rejected")
+	}
+	if err := client.Call("Echo.EchoCapabilities", &capabilities, &reply); err == nil {
+		t.Errorf("Expected the connection to be closed after the large message")
+	}
+}
@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/rpc"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/core/types"
+	"github.com/yetanotherco/aligned_layer/core/types/pb"
+)
+
+// protoServerCodec is the net/rpc codec of the protobuf messages of core/types/pb. Like the gob
+// one, it fails on messages larger than the max message size and closes connections that stay
+// idle or stall reading a request or writing a response.
+type protoServerCodec struct {
+	conn   net.Conn
+	limits RpcLimits
+	r      *bufio.Reader
+	w      *bufio.Writer
+	closed bool
+}
+
+func newProtoServerCodec(conn net.Conn, limits RpcLimits) *protoServerCodec {
+	return &protoServerCodec{
+		conn:   conn,
+		limits: limits,
+		r:      bufio.NewReader(conn),
+		w:      bufio.NewWriter(conn),
+	}
+}
+
+func (c *protoServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	c.conn.SetReadDeadline(time.Now().Add(c.limits.IdleTimeout))
+	var header pb.RequestHeader
+	if err := types.ReadProtoMessage(c.r, c.limits.MaxMessageSize, &header); err != nil {
+		return err
+	}
+	r.ServiceMethod = header.ServiceMethod
+	r.Seq = header.Seq
+	c.conn.SetReadDeadline(time.Now().Add(c.limits.ReadTimeout))
+	return nil
+}
+
+func (c *protoServerCodec) ReadRequestBody(body any) error {
+	err := types.ReadProtoMessage(c.r, c.limits.MaxMessageSize, body)
+	if errors.Is(err, types.ErrProtoMessageTooLarge) {
+		// the message isn't read, so the following ones can't be either
+		c.Close()
+	}
+	return err
+}
+
+func (c *protoServerCodec) WriteResponse(r *rpc.Response, body any) error {
+	c.conn.SetWriteDeadline(time.Now().Add(c.limits.WriteTimeout))
+	header := &pb.ResponseHeader{ServiceMethod: r.ServiceMethod, Seq: r.Seq, Error: r.Error}
+	if err := types.WriteProtoMessage(c.w, header); err != nil {
+		c.Close()
+		return err
+	}
+	if err := types.WriteProtoMessage(c.w, body); err != nil {
+		// the header is already buffered, the response can't be completed
+		c.Close()
+		return err
+	}
+	return c.w.Flush()
+}
+
+func (c *protoServerCodec) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
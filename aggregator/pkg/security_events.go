@@ -0,0 +1,186 @@
+package pkg
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// SecurityEventType is the kind of misbehavior recorded in the security event log
+type SecurityEventType string
+
+const (
+	// RejectedSignatureEvent is an operator message whose BLS signature was invalid or didn't verify
+	RejectedSignatureEvent SecurityEventType = "rejected_signature"
+	// RateLimitedEvent is a request to the operators server rejected for its IP exceeding the rate
+	// limit, or being banned
+	RateLimitedEvent SecurityEventType = "rate_limited"
+	// IpBannedEvent is an IP banned for exceeding the rate limit
+	IpBannedEvent SecurityEventType = "ip_banned"
+	// InvalidSenderEvent is a batch of a sender that isn't the payment service and has no funds deposited
+	InvalidSenderEvent SecurityEventType = "invalid_sender"
+	// AllowlistViolationEvent is a batch of a sender not in the authorized batchers allowlist
+	AllowlistViolationEvent SecurityEventType = "allowlist_violation"
+)
+
+const (
+	DefaultSecurityEventsCapacity = 10_000
+	SecurityEventsEndpoint        = "/security/events"
+)
+
+// SecurityEvent is an attempt to misuse the aggregator
+type SecurityEvent struct {
+	Time time.Time         `json:"time"`
+	Type SecurityEventType `json:"type"`
+	// Source is who misbehaved: an IP, an operator id or a batch sender address
+	Source  string            `json:"source"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// SecurityEventLog keeps the last security events in memory, to be exported through the security
+// events endpoint, and appends all of them to a JSON lines file if configured. Recording to a nil
+// log does nothing, so the components work without one.
+type SecurityEventLog struct {
+	mutex    sync.Mutex
+	events   []SecurityEvent
+	capacity int
+	// next is the position of the next event in events once it's full
+	next   int
+	file   *os.File
+	logger logging.Logger
+	now    func() time.Time
+}
+
+// NewSecurityEventLog creates a log keeping the last capacity events, the default if unset, and
+// appending them to the file at filePath unless it's empty
+func NewSecurityEventLog(capacity int, filePath string, logger logging.Logger) (*SecurityEventLog, error) {
+	if capacity <= 0 {
+		capacity = DefaultSecurityEventsCapacity
+	}
+	eventLog := &SecurityEventLog{capacity: capacity, logger: logger, now: time.Now}
+	if filePath != "" {
+		file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		eventLog.file = file
+	}
+	return eventLog, nil
+}
+
+// Record adds an event of the type by the source. details are pairs of keys and values.
+func (l *SecurityEventLog) Record(eventType SecurityEventType, source string, details ...string) {
+	if l == nil {
+		return
+	}
+	event := SecurityEvent{Time: l.now().UTC(), Type: eventType, Source: source}
+	if len(details) > 0 {
+		event.Details = make(map[string]string, len(details)/2)
+		for i := 0; i+1 < len(details); i += 2 {
+			event.Details[details[i]] = details[i+1]
+		}
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.events) < l.capacity {
+		l.events = append(l.events, event)
+	} else {
+		l.events[l.next] = event
+		l.next = (l.next + 1) % l.capacity
+	}
+
+	if l.file != nil {
+		line, err := json.Marshal(event)
+		if err == nil {
+			_, err = l.file.Write(append(line, '\n'))
+		}
+		if err != nil {
+			l.logger.Warn("Could not write security event to file", "err", err)
+		}
+	}
+}
+
+// Events returns the kept events since the time, oldest first, only the ones of the type if not empty
+func (l *SecurityEventLog) Events(since time.Time, eventType SecurityEventType) []SecurityEvent {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	events := make([]SecurityEvent, 0)
+	for i := range l.events {
+		event := l.events[(l.next+i)%len(l.events)]
+		if event.Time.Before(since) || (eventType != "" && event.Type != eventType) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// Close closes the events file
+func (l *SecurityEventLog) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// Handler exports the kept events as JSON, filtered by the since (RFC 3339 time) and type query
+// parameters. With format=jsonl, one event is written per line.
+func (l *SecurityEventLog) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var since time.Time
+		if sinceParam := r.URL.Query().Get("since"); sinceParam != "" {
+			var err error
+			since, err = time.Parse(time.RFC3339, sinceParam)
+			if err != nil {
+				http.Error(w, "invalid since, expected an RFC 3339 time", http.StatusBadRequest)
+				return
+			}
+		}
+		events := l.Events(since, SecurityEventType(r.URL.Query().Get("type")))
+
+		encoder := json.NewEncoder(w)
+		var err error
+		if r.URL.Query().Get("format") == "jsonl" {
+			w.Header().Set("Content-Type", "application/jsonl")
+			for _, event := range events {
+				if err = encoder.Encode(event); err != nil {
+					break
+				}
+			}
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+			err = encoder.Encode(events)
+		}
+		if err != nil {
+			l.logger.Error("Could not encode security events", "err", err)
+		}
+	})
+}
+
+// ServeSecurityEvents serves the export of the security events at the address, which must only be
+// reachable by the security team
+func (agg *Aggregator) ServeSecurityEvents(address string) error {
+	mux := http.NewServeMux()
+	mux.Handle(SecurityEventsEndpoint, agg.securityEvents.Handler())
+
+	agg.logger.Info("Starting security events server on address", "address", address)
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+	return server.ListenAndServe()
+}
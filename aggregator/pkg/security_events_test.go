@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+func TestSecurityEventLogKeepsTheLastEvents(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	filePath := filepath.Join(t.TempDir(), "security_events.jsonl")
+	eventLog, err := NewSecurityEventLog(2, filePath, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer eventLog.Close()
+	now := time.Unix(1700000000, 0)
+	eventLog.now = func() time.Time { return now }
+
+	eventLog.Record(InvalidSenderEvent, "0x01")
+	now = now.Add(time.Second)
+	eventLog.Record(RejectedSignatureEvent, "operator", "message", "task_response")
+	now = now.Add(time.Second)
+	eventLog.Record(InvalidSenderEvent, "0x02")
+
+	events := eventLog.Events(time.Time{}, "")
+	if len(events) != 2 || events[0].Type != RejectedSignatureEvent || events[1].Source != "0x02" {
+		t.Fatalf("Expected the last 2 events oldest first, got %+v", events)
+	}
+	if events[0].Details["message"] != "task_response" {
+		t.Errorf("Expected the event details to be kept, got %+v", events[0].Details)
+	}
+	if events := eventLog.Events(now, ""); len(events) != 1 || events[0].Source != "0x02" {
+		t.Errorf("Expected the events since the time, got %+v", events)
+	}
+	if events := eventLog.Events(time.Time{}, InvalidSenderEvent); len(events) != 1 || events[0].Source != "0x02" {
+		t.Errorf("Expected the events of the type, got %+v", events)
+	}
+
+	data, _ := os.ReadFile(filePath)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 {
+		t.Errorf("Expected every event to be appended to the file, got %q", data)
+	}
+}
+
+func TestSecurityEventsExport(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	eventLog, _ := NewSecurityEventLog(0, "", logger)
+	throttle := NewIpThrottle(1, 1, 1, time.Minute, nil, metrics.NewMetrics("", prometheus.NewRegistry(), logger), eventLog)
+	throttle.Allow("192.0.2.1")
+	throttle.Allow("192.0.2.1")
+
+	recorder := httptest.NewRecorder()
+	eventLog.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SecurityEventsEndpoint, nil))
+	var events []SecurityEvent
+	if err := json.NewDecoder(recorder.Body).Decode(&events); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != RateLimitedEvent || events[1].Type != IpBannedEvent || events[1].Source != "192.0.2.1" {
+		t.Fatalf("Expected the rate limit hit and the ban, got %+v", events)
+	}
+
+	recorder = httptest.NewRecorder()
+	eventLog.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SecurityEventsEndpoint+"?type=ip_banned&format=jsonl", nil))
+	if lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"ip_banned"`) {
+		t.Errorf("Expected one line with the ban, got %q", recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	eventLog.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SecurityEventsEndpoint+"?since=yesterday", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected the invalid since to be rejected, got %d", recorder.Code)
+	}
+}
@@ -5,34 +5,69 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/rpc"
 	"time"
 
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	blsagg "github.com/Layr-Labs/eigensdk-go/services/bls_aggregation"
+	"golang.org/x/net/netutil"
+
 	retry "github.com/yetanotherco/aligned_layer/core"
 	"github.com/yetanotherco/aligned_layer/core/types"
+	"github.com/yetanotherco/aligned_layer/core/utils"
 )
 
 func (agg *Aggregator) ServeOperators() error {
 	// Registers a new RPC server
-	err := rpc.Register(agg)
+	rpcServer := rpc.NewServer()
+	err := rpcServer.Register(agg)
 	if err != nil {
 		return err
 	}
 
-	// Registers an HTTP handler for RPC messages
-	rpc.HandleHTTP()
+	aggregatorConfig := agg.AggregatorConfig.Aggregator
+	limits := NewRpcLimits(aggregatorConfig.RpcMaxMessageSize, aggregatorConfig.RpcMaxConnections,
+		aggregatorConfig.RpcIdleTimeout, aggregatorConfig.RpcReadTimeout, aggregatorConfig.RpcWriteTimeout)
 
 	// Start listening for requests on aggregator address
 	// ServeOperators accepts incoming HTTP connections on the listener, creating
 	// a new service goroutine for each. The service goroutines read requests
 	// and then call handler to reply to them
 	agg.logger.Info("Starting RPC server on address", "address",
-		agg.AggregatorConfig.Aggregator.ServerIpPortAddress)
+		aggregatorConfig.ServerIpPortAddress, "limits", limits)
 
-	err = http.ListenAndServe(agg.AggregatorConfig.Aggregator.ServerIpPortAddress, nil)
+	throttle := NewIpThrottle(aggregatorConfig.IpRequestsPerSecond, aggregatorConfig.IpBurst,
+		aggregatorConfig.IpBanThreshold, aggregatorConfig.IpBanDuration, aggregatorConfig.BannedIps, agg.metrics, agg.securityEvents)
+
+	listener, err := net.Listen("tcp", aggregatorConfig.ServerIpPortAddress)
+	if err != nil {
+		return err
+	}
+	return serveOperators(listener, rpcServer, http.HandlerFunc(agg.handleCapabilities), limits, throttle, agg.logger)
+}
 
-	return err
+// serveOperators serves the RPC messages of the operators and the proving systems they support on
+// the listener, within the limits and throttling each IP
+func serveOperators(listener net.Listener, rpcServer *rpc.Server, capabilitiesHandler http.Handler, limits RpcLimits, throttle *IpThrottle, logger logging.Logger) error {
+	mux := http.NewServeMux()
+	// Registers an HTTP handler for RPC messages
+	mux.Handle(rpc.DefaultRPCPath, &rpcHandler{server: rpcServer, limits: limits, logger: logger})
+	// The same methods with protobuf messages, gob being kept for the operators of previous releases
+	mux.Handle(types.ProtoRpcPath, &rpcHandler{server: rpcServer, limits: limits, logger: logger, protobuf: true})
+	// Serves the proving systems supported by the operators
+	mux.Handle(CapabilitiesEndpoint, http.MaxBytesHandler(capabilitiesHandler, limits.MaxMessageSize))
+
+	server := &http.Server{
+		Handler:           throttle.Handler(mux),
+		ReadHeaderTimeout: limits.ReadTimeout,
+		ReadTimeout:       limits.ReadTimeout,
+		WriteTimeout:      limits.WriteTimeout,
+		IdleTimeout:       limits.IdleTimeout,
+		MaxHeaderBytes:    rpcMaxHeaderBytes,
+	}
+	return server.Serve(netutil.LimitListener(throttle.Listener(listener), limits.MaxConnections))
 }
 
 // Aggregator Methods
@@ -48,16 +83,32 @@ func (agg *Aggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *t
 		"BatchMerkleRoot", "0x"+hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
 		"SenderAddress", "0x"+hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
 		"BatchIdentifierHash", "0x"+hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
-		"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]))
+		"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]),
+		"version", signedTaskResponse.MessageVersion())
+
+	// Responses of operators too many releases behind may miss fields the aggregation needs
+	if err := signedTaskResponse.CheckVersion(); err != nil {
+		agg.logger.Warn("unsupported operator response version",
+			"err", err,
+			"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]))
+		*reply = 1
+		return err
+	}
 
-	if signedTaskResponse.BlsSignature.G1Point == nil {
-		agg.logger.Warn("invalid operator response with nil signature",
+	// Crafted points must not reach the aggregation, as its operations assume valid points
+	if err := utils.ValidateG1Point("signature", signedTaskResponse.BlsSignature.G1Point); err != nil {
+		agg.logger.Warn("invalid operator response signature",
+			"err", err,
 			"BatchMerkleRoot", "0x"+hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
 			"SenderAddress", "0x"+hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
 			"BatchIdentifierHash", "0x"+hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
 			"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]))
+		agg.securityEvents.Record(RejectedSignatureEvent, hex.EncodeToString(signedTaskResponse.OperatorId[:]),
+			"message", "task_response",
+			"batchIdentifierHash", hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
+			"err", err.Error())
 		*reply = 1
-		return errors.New("invalid response: nil signature")
+		return fmt.Errorf("invalid response: %w", err)
 	}
 
 	taskIndex := uint32(0)
@@ -92,6 +143,12 @@ func (agg *Aggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *t
 
 		if err != nil {
 			agg.logger.Warnf("BLS aggregation service error: %s", err)
+			if errors.Is(err, blsagg.IncorrectSignatureError) {
+				agg.securityEvents.Record(RejectedSignatureEvent, hex.EncodeToString(signedTaskResponse.OperatorId[:]),
+					"message", "task_response",
+					"batchIdentifierHash", hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
+					"err", err.Error())
+			}
 			done<- 1
 			// todo shouldn't we here close the channel with a reply = 1?
 		} else {
@@ -132,13 +189,13 @@ TODO: We should refactor the retry duration considering extending it to a larger
 */
 func (agg *Aggregator) GetTaskIndexRetryable(batchIdentifierHash [32]byte, config *retry.RetryParams) (uint32, error) {
 	getTaskIndex_func := func() (uint32, error) {
-		agg.taskMutex.Lock()
-		taskIndex, ok := agg.batchesIdxByIdentifierHash[batchIdentifierHash]
-		agg.taskMutex.Unlock()
+		task, ok := agg.tasks.GetByIdentifierHash(batchIdentifierHash)
 		if !ok {
-			return taskIndex, fmt.Errorf("Task not found in the internal map")
+			return task.Index, fmt.Errorf("Task not found in the internal map")
+		} else if task.State == TaskReceived {
+			return task.Index, fmt.Errorf("Task not initialized yet")
 		} else {
-			return taskIndex, nil
+			return task.Index, nil
 		}
 	}
 
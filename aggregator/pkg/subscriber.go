@@ -16,7 +16,7 @@ func (agg *Aggregator) SubscribeToNewTasks() error {
 			}
 		case newBatch := <-agg.NewBatchChan:
 			agg.AggregatorConfig.BaseConfig.Logger.Info("Adding new task")
-			agg.AddNewTask(newBatch.BatchMerkleRoot, newBatch.SenderAddress, newBatch.TaskCreatedBlock)
+			agg.AddNewTask(newBatch.BatchMerkleRoot, newBatch.SenderAddress, newBatch.TaskCreatedBlock, newBatch.RespondToTaskFeeLimit)
 		}
 	}
 }
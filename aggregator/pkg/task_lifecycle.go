@@ -0,0 +1,313 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/types"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+// TaskState is the stage of the lifecycle of a batch in the aggregator
+type TaskState string
+
+const (
+	// TaskReceived is a batch seen on-chain, being checked before aggregating its signatures
+	TaskReceived TaskState = "received"
+	// TaskInitialized is a batch whose signatures are being aggregated by the BLS aggregation service
+	TaskInitialized TaskState = "initialized"
+	// TaskQuorumReached is a batch signed by the quorum, waiting to be responded
+	TaskQuorumReached TaskState = "quorum_reached"
+	// TaskSubmitted is a batch whose response is being sent
+	TaskSubmitted TaskState = "submitted"
+	// TaskConfirmed is a batch responded on-chain, by this aggregator or another replica
+	TaskConfirmed TaskState = "confirmed"
+	// TaskFailed is a batch the aggregator won't respond to
+	TaskFailed TaskState = "failed"
+	// TaskExpired is a batch that didn't reach quorum in time, or was interrupted by a restart
+	TaskExpired TaskState = "expired"
+)
+
+// taskTransitions are the states each state can be followed by
+var taskTransitions = map[TaskState][]TaskState{
+	TaskReceived:      {TaskInitialized, TaskFailed, TaskExpired},
+	TaskInitialized:   {TaskQuorumReached, TaskFailed, TaskExpired},
+	TaskQuorumReached: {TaskSubmitted, TaskConfirmed, TaskFailed, TaskExpired},
+	TaskSubmitted:     {TaskConfirmed, TaskFailed, TaskExpired},
+}
+
+// IsFinal returns whether the task lifecycle is over
+func (s TaskState) IsFinal() bool {
+	return len(taskTransitions[s]) == 0
+}
+
+func (s TaskState) canTransitionTo(to TaskState) bool {
+	for _, next := range taskTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ErrTaskExists        = errors.New("task already exists")
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrInvalidTransition = errors.New("invalid task state transition")
+)
+
+// Task is a batch tracked by the aggregator. Its index identifies it in the BLS aggregation service.
+type Task struct {
+	Index               uint32
+	BatchIdentifierHash [32]byte
+	BatchData           BatchData
+	CreatedBlock        uint64
+	StartTime           time.Time
+	// TraceId is the telemetry trace of the task, empty if it couldn't be started
+	TraceId string
+	// SpanId is the aggregator span of the trace, which the operator spans are attached to
+	SpanId    string
+	State     TaskState
+	UpdatedAt time.Time
+}
+
+// TaskStore persists the tasks, so their state can be inspected after a restart
+type TaskStore interface {
+	Load() ([]Task, error)
+	Save(tasks []Task) error
+}
+
+// TaskLifecycle tracks the tasks of the aggregator and their states. Each change is persisted to
+// the store, if any, and reflected in the tasks per state metrics.
+type TaskLifecycle struct {
+	mutex                 sync.Mutex
+	tasks                 map[uint32]*Task
+	indexByIdentifierHash map[[32]byte]uint32
+	// nextIndex is the index of the next task. It starts from 0 again after a restart, since
+	// the BLS aggregation service state isn't persisted.
+	nextIndex uint32
+	store     TaskStore
+	metrics   *metrics.Metrics
+	logger    logging.Logger
+	now       func() time.Time
+}
+
+// NewTaskLifecycle creates the lifecycle, returning the tasks of a previous run in the store that
+// weren't over. They can't be resumed, since their signatures were lost with the restart.
+func NewTaskLifecycle(store TaskStore, metrics *metrics.Metrics, logger logging.Logger) (*TaskLifecycle, []Task, error) {
+	l := &TaskLifecycle{
+		tasks:                 make(map[uint32]*Task),
+		indexByIdentifierHash: make(map[[32]byte]uint32),
+		store:                 store,
+		metrics:               metrics,
+		logger:                logger,
+		now:                   time.Now,
+	}
+	if store == nil {
+		return l, nil, nil
+	}
+
+	previousTasks, err := store.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load tasks: %w", err)
+	}
+	var interrupted []Task
+	for _, task := range previousTasks {
+		if !task.State.IsFinal() {
+			interrupted = append(interrupted, task)
+		}
+	}
+	// The previous tasks are replaced by the ones of this run
+	if err := store.Save(nil); err != nil {
+		return nil, nil, fmt.Errorf("could not save tasks: %w", err)
+	}
+	return l, interrupted, nil
+}
+
+// Receive adds a task in the received state, returning its index
+func (l *TaskLifecycle) Receive(batchIdentifierHash [32]byte, batchData BatchData, createdBlock uint64) (uint32, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, ok := l.indexByIdentifierHash[batchIdentifierHash]; ok {
+		return 0, ErrTaskExists
+	}
+	// This shouldn't happen, since both maps are updated together
+	if _, ok := l.tasks[l.nextIndex]; ok {
+		return 0, ErrTaskExists
+	}
+
+	now := l.now()
+	task := &Task{
+		Index:               l.nextIndex,
+		BatchIdentifierHash: batchIdentifierHash,
+		BatchData:           batchData,
+		CreatedBlock:        createdBlock,
+		StartTime:           now,
+		State:               TaskReceived,
+		UpdatedAt:           now,
+	}
+	l.tasks[task.Index] = task
+	l.indexByIdentifierHash[batchIdentifierHash] = task.Index
+	l.nextIndex++
+	l.metrics.ObserveAggregatorTaskTransition("", string(TaskReceived))
+
+	l.persist()
+	return task.Index, nil
+}
+
+// Transition moves the task to the state, if it can follow its current one
+func (l *TaskLifecycle) Transition(index uint32, to TaskState) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	task, ok := l.tasks[index]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	if !task.State.canTransitionTo(to) {
+		return fmt.Errorf("%w from %s to %s", ErrInvalidTransition, task.State, to)
+	}
+	l.metrics.ObserveAggregatorTaskTransition(string(task.State), string(to))
+	l.observeFinalization(task, to)
+	task.State = to
+	task.UpdatedAt = l.now()
+	l.persist()
+	return nil
+}
+
+// observeFinalization records the task ending in the state, if final, for the finalization SLO.
+// Tasks that were never initialized are ignored, as they were rejected before being worked on.
+func (l *TaskLifecycle) observeFinalization(task *Task, to TaskState) {
+	if !to.IsFinal() || task.State == TaskReceived {
+		return
+	}
+	l.metrics.ObserveBatchFinalization(QUORUM_NUMBER, to == TaskConfirmed, l.now().Sub(task.StartTime))
+}
+
+// MarkUnfunded marks the batch of the task as unfunded
+func (l *TaskLifecycle) MarkUnfunded(index uint32) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if task, ok := l.tasks[index]; ok {
+		task.BatchData.Unfunded = true
+		l.persist()
+	}
+}
+
+// SetTraceContext links the task to its telemetry trace
+func (l *TaskLifecycle) SetTraceContext(index uint32, traceContext types.TaskTraceContext) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if task, ok := l.tasks[index]; ok {
+		task.TraceId = traceContext.TraceId
+		task.SpanId = traceContext.SpanId
+		l.persist()
+	}
+}
+
+// Get returns the task with the index
+func (l *TaskLifecycle) Get(index uint32) (Task, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	task, ok := l.tasks[index]
+	if !ok {
+		return Task{}, false
+	}
+	return *task, true
+}
+
+// GetByIdentifierHash returns the task of the batch
+func (l *TaskLifecycle) GetByIdentifierHash(batchIdentifierHash [32]byte) (Task, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	index, ok := l.indexByIdentifierHash[batchIdentifierHash]
+	if !ok {
+		return Task{}, false
+	}
+	return *l.tasks[index], true
+}
+
+// Remove drops the task, expiring it first if it wasn't over
+func (l *TaskLifecycle) Remove(index uint32) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	task, ok := l.tasks[index]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	if !task.State.IsFinal() {
+		l.metrics.ObserveAggregatorTaskTransition(string(task.State), string(TaskExpired))
+		l.observeFinalization(task, TaskExpired)
+		task.State = TaskExpired
+	}
+	l.metrics.ObserveAggregatorTaskTransition(string(task.State), "")
+	delete(l.indexByIdentifierHash, task.BatchIdentifierHash)
+	delete(l.tasks, index)
+	l.persist()
+	return nil
+}
+
+// persist saves the tasks to the store, must be called with the mutex locked. Failing to persist
+// them doesn't stop the aggregator, the store is only used to inspect the tasks.
+func (l *TaskLifecycle) persist() {
+	if l.store == nil {
+		return
+	}
+	tasks := make([]Task, 0, len(l.tasks))
+	for _, task := range l.tasks {
+		tasks = append(tasks, *task)
+	}
+	if err := l.store.Save(tasks); err != nil {
+		l.logger.Warn("Could not persist the aggregator tasks", "err", err)
+	}
+}
+
+// FileTaskStore persists the tasks as JSON in a file
+type FileTaskStore struct {
+	filePath string
+}
+
+func NewFileTaskStore(filePath string) *FileTaskStore {
+	return &FileTaskStore{filePath: filePath}
+}
+
+// Load reads the tasks of the file, none if it doesn't exist
+func (s *FileTaskStore) Load() ([]Task, error) {
+	data, err := os.ReadFile(s.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tasks []Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Save replaces the tasks of the file
+func (s *FileTaskStore) Save(tasks []Task) error {
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so a crash can't leave truncated tasks behind
+	tmpFilePath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpFilePath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, s.filePath)
+}
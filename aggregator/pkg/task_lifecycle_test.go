@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+func newTestTaskLifecycle(t *testing.T, store TaskStore) (*TaskLifecycle, []Task) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	tasks, interrupted, err := NewTaskLifecycle(store, metrics.NewMetrics("", prometheus.NewRegistry(), logger), logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return tasks, interrupted
+}
+
+func TestTaskLifecycleTransitions(t *testing.T) {
+	tasks, _ := newTestTaskLifecycle(t, nil)
+
+	index, err := tasks.Receive([32]byte{1}, BatchData{BatchMerkleRoot: [32]byte{2}}, 10)
+	if err != nil || index != 0 {
+		t.Fatalf("Expected the first task to have index 0, got %d and %v", index, err)
+	}
+	if _, err := tasks.Receive([32]byte{1}, BatchData{}, 10); !errors.Is(err, ErrTaskExists) {
+		t.Errorf("Expected the batch to be received once, got %v", err)
+	}
+
+	if err := tasks.Transition(index, TaskSubmitted); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected the task not to be submitted before reaching quorum, got %v", err)
+	}
+	for _, state := range []TaskState{TaskInitialized, TaskQuorumReached, TaskSubmitted, TaskConfirmed} {
+		if err := tasks.Transition(index, state); err != nil {
+			t.Fatalf("Unexpected error moving the task to %s: %v", state, err)
+		}
+	}
+	if err := tasks.Transition(index, TaskFailed); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected the confirmed task not to change, got %v", err)
+	}
+
+	task, ok := tasks.GetByIdentifierHash([32]byte{1})
+	if !ok || task.State != TaskConfirmed || task.BatchData.BatchMerkleRoot != [32]byte{2} || task.CreatedBlock != 10 {
+		t.Errorf("Unexpected task %+v", task)
+	}
+
+	if err := tasks.Remove(index); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := tasks.Get(index); ok {
+		t.Errorf("Expected the task to be removed")
+	}
+	if err := tasks.Transition(index, TaskFailed); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected the removed task not to be found, got %v", err)
+	}
+}
+
+func TestTaskLifecyclePersistence(t *testing.T) {
+	store := NewFileTaskStore(filepath.Join(t.TempDir(), "tasks.json"))
+	tasks, _ := newTestTaskLifecycle(t, store)
+
+	confirmed, _ := tasks.Receive([32]byte{1}, BatchData{}, 10)
+	_ = tasks.Transition(confirmed, TaskInitialized)
+	_ = tasks.Transition(confirmed, TaskQuorumReached)
+	_ = tasks.Transition(confirmed, TaskConfirmed)
+	initialized, _ := tasks.Receive([32]byte{2}, BatchData{}, 11)
+	_ = tasks.Transition(initialized, TaskInitialized)
+
+	_, interrupted := newTestTaskLifecycle(t, store)
+	if len(interrupted) != 1 || interrupted[0].BatchIdentifierHash != [32]byte{2} || interrupted[0].State != TaskInitialized {
+		t.Fatalf("Expected the initialized task to be interrupted by the restart, got %+v", interrupted)
+	}
+
+	_, interrupted = newTestTaskLifecycle(t, store)
+	if len(interrupted) != 0 {
+		t.Errorf("Expected the tasks of the previous run to be dropped, got %+v", interrupted)
+	}
+}
@@ -1,28 +1,87 @@
 package pkg
 
 import (
-	"bytes"
-	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
+	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/types"
 )
 
+// TelemetryTransport is the sink the task traces are sent to
+type TelemetryTransport string
+
+const (
+	// HttpTelemetryTransport sends them to the telemetry API, which records them
+	HttpTelemetryTransport TelemetryTransport = "http"
+	// OtlpTelemetryTransport records them in the aggregator and exports them over OTLP/gRPC to an
+	// OpenTelemetry collector
+	OtlpTelemetryTransport TelemetryTransport = "otlp"
+	// BothTelemetryTransport sends them to both
+	BothTelemetryTransport TelemetryTransport = "both"
+	// GrpcTelemetryTransport streams them in batches to the telemetry API over gRPC
+	GrpcTelemetryTransport TelemetryTransport = "grpc"
+	// StdoutTelemetryTransport writes them to stdout as JSON lines, for development
+	StdoutTelemetryTransport TelemetryTransport = "stdout"
+	// NoopTelemetryTransport drops them, to run without the telemetry API
+	NoopTelemetryTransport TelemetryTransport = "none"
+)
+
+// TelemetryConfig sets where the task traces are sent
+type TelemetryConfig struct {
+	// Transport is HttpTelemetryTransport if unset, or NoopTelemetryTransport if there is no
+	// ServerAddress either
+	Transport TelemetryTransport
+	// ServerAddress is the telemetry API address, for the http transport
+	ServerAddress string
+	// OtlpEndpoint is the OpenTelemetry collector OTLP/gRPC endpoint, for the otlp transport
+	OtlpEndpoint string
+	// OtlpInsecure connects to the collector without TLS
+	OtlpInsecure bool
+	// GrpcEndpoint is the telemetry API gRPC endpoint, for the grpc transport
+	GrpcEndpoint string
+	// GrpcInsecure connects to the telemetry API gRPC endpoint without TLS
+	GrpcInsecure bool
+	// GrpcFlushInterval is how often the queued events are streamed, DefaultTelemetryGrpcFlushInterval if unset
+	GrpcFlushInterval time.Duration
+	Sampling          TelemetrySampling
+	// SpillDir is where the messages to the telemetry API are kept while it's unreachable, to be
+	// sent once it recovers. They are dropped if unset.
+	SpillDir string
+	// SpillMaxFileSize is the size in bytes of each spill file, DefaultTelemetrySpillMaxFileSize if unset
+	SpillMaxFileSize int64
+	// SpillMaxFiles is the number of spill files kept, DefaultTelemetrySpillMaxFiles if unset
+	SpillMaxFiles int
+	// ApiToken authenticates the messages to the telemetry API, sent unauthenticated if unset
+	ApiToken string
+	// BreakerThreshold is the number of consecutive failures to reach the telemetry API that stop
+	// sending it messages, DefaultTelemetryBreakerThreshold if unset
+	BreakerThreshold int
+	// BreakerProbeInterval is how often a message is sent to check whether the telemetry API
+	// recovered, DefaultTelemetryBreakerProbeInterval if unset
+	BreakerProbeInterval time.Duration
+}
+
 type TraceMessage struct {
 	MerkleRoot string `json:"merkle_root"`
 }
 
+type TraceResponse struct {
+	MerkleRoot string `json:"merkle_root"`
+	TraceId    string `json:"trace_id"`
+	// SpanId is the aggregator span of the trace
+	SpanId string `json:"span_id"`
+}
+
 type OperatorResponseMessage struct {
 	MerkleRoot string `json:"merkle_root"`
 	OperatorId string `json:"operator_id"`
 }
 type QuorumReachedMessage struct {
 	MerkleRoot string `json:"merkle_root"`
+	NonSigners int    `json:"non_signers"`
 }
 
 type TaskErrorMessage struct {
@@ -39,128 +98,253 @@ type TaskSentToEthereumMessage struct {
 	MerkleRoot        string `json:"merkle_root"`
 	TxHash            string `json:"tx_hash"`
 	EffectiveGasPrice string `json:"effective_gas_price"`
+	GasUsed           string `json:"gas_used"`
+	FeePaid           string `json:"fee_paid"`
+}
+
+// TelemetrySink records the task traces, one per batch. The sinks only get the events of the
+// sampled batches, and handle their own errors since telemetry never fails a task.
+type TelemetrySink interface {
+	// InitTrace starts the trace of the batch, returning its trace context, empty if unknown
+	InitTrace(batchMerkleRoot [32]byte) types.TaskTraceContext
+	OperatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte)
+	QuorumReached(batchMerkleRoot [32]byte, nonSigners int)
+	TaskError(batchMerkleRoot [32]byte, taskError error)
+	TaskSetGasPrice(batchMerkleRoot [32]byte, gasPrice string)
+	TaskSentToEthereum(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string, gasUsed string, feePaid string)
+	FinishTrace(batchMerkleRoot [32]byte)
 }
 
+// telemetrySinkCloser is implemented by the sinks that must send what they buffer before the
+// aggregator exits
+type telemetrySinkCloser interface {
+	Close()
+}
+
+// Telemetry samples the batches and sends the traces of the sampled ones to its sink, while enabled
 type Telemetry struct {
-	client  http.Client
-	baseURL url.URL
-	logger  logging.Logger
+	sink   TelemetrySink
+	logger logging.Logger
+	// sampler picks the traced batches, all of them if nil
+	sampler *telemetrySampler
+	// disabled stops the traces at runtime, the batches aren't sampled meanwhile
+	disabled atomic.Bool
 }
 
 func NewTelemetry(serverAddress string, logger logging.Logger) *Telemetry {
-	client := http.Client{}
+	telemetry, _ := NewTelemetryWithConfig(TelemetryConfig{ServerAddress: serverAddress}, logger)
+	return telemetry
+}
 
-	baseURL := url.URL{
-		Scheme: "http",
-		Host:   serverAddress,
+func NewTelemetryWithConfig(config TelemetryConfig, logger logging.Logger) (*Telemetry, error) {
+	sink, err := newTelemetrySink(config, logger)
+	if err != nil {
+		return nil, err
 	}
-	logger.Info("[Telemetry] Starting Telemetry client.", "server_address",
-		serverAddress)
+	logger.Info("[Telemetry] Starting Telemetry client.", "server_address", config.ServerAddress,
+		"transport", config.Transport, "otlp_endpoint", config.OtlpEndpoint, "grpc_endpoint", config.GrpcEndpoint)
+	return NewTelemetryWithSink(sink, config.Sampling, logger)
+}
 
-	return &Telemetry{
-		client:  client,
-		baseURL: baseURL,
-		logger:  logger,
+// NewTelemetryWithSink sends the traces of the batches sampled by the sampling to the sink
+func NewTelemetryWithSink(sink TelemetrySink, sampling TelemetrySampling, logger logging.Logger) (*Telemetry, error) {
+	sampler, err := newTelemetrySampler(sampling)
+	if err != nil {
+		return nil, err
 	}
+	return &Telemetry{sink: sink, logger: logger, sampler: sampler}, nil
 }
 
-func (t *Telemetry) InitNewTrace(batchMerkleRoot [32]byte) {
-	body := TraceMessage{
-		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+// newTelemetrySink creates the sink of the config transport
+func newTelemetrySink(config TelemetryConfig, logger logging.Logger) (TelemetrySink, error) {
+	transport := config.Transport
+	if transport == "" && config.ServerAddress == "" {
+		logger.Warn("[Telemetry] No telemetry address set, the task traces are dropped")
+		transport = NoopTelemetryTransport
 	}
-	if err := t.sendTelemetryMessage("/api/initTaskTrace", body); err != nil {
-		t.logger.Warn("[Telemetry] Error in InitNewTrace", "error", err)
+	switch transport {
+	case "", HttpTelemetryTransport:
+		return newHttpTelemetrySink(config, logger)
+	case OtlpTelemetryTransport:
+		exporter, err := newOtlpExporter(config.OtlpEndpoint, config.OtlpInsecure)
+		if err != nil {
+			return nil, fmt.Errorf("could not create OTLP exporter: %w", err)
+		}
+		return newOtlpTracer(exporter), nil
+	case BothTelemetryTransport:
+		httpSink, err := newHttpTelemetrySink(config, logger)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err := newOtlpExporter(config.OtlpEndpoint, config.OtlpInsecure)
+		if err != nil {
+			return nil, fmt.Errorf("could not create OTLP exporter: %w", err)
+		}
+		// the trace context is the one of the telemetry API, if it's known
+		return multiTelemetrySink{httpSink, newOtlpTracer(exporter)}, nil
+	case GrpcTelemetryTransport:
+		return newGrpcTelemetrySink(config, logger)
+	case StdoutTelemetryTransport:
+		return newStdoutTelemetrySink(os.Stdout), nil
+	case NoopTelemetryTransport:
+		return noopTelemetrySink{}, nil
+	default:
+		return nil, fmt.Errorf("unknown telemetry transport %q", config.Transport)
 	}
 }
 
-func (t *Telemetry) LogOperatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte) {
-	body := OperatorResponseMessage{
-		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
-		OperatorId: fmt.Sprintf("0x%s", hex.EncodeToString(operatorId[:])),
+// SetEnabled turns the telemetry on or off at runtime. Once back on, the later events of the
+// batches started while off are sent too, the sinks that don't know their trace ignore them.
+func (t *Telemetry) SetEnabled(enabled bool) {
+	if t.disabled.Swap(!enabled) != !enabled {
+		t.logger.Info("[Telemetry] Telemetry toggled", "enabled", enabled)
 	}
-	if err := t.sendTelemetryMessage("/api/operatorResponse", body); err != nil {
-		t.logger.Warn("[Telemetry] Error in LogOperatorResponse", "error", err)
+}
+
+// Enabled returns whether the traces are sent
+func (t *Telemetry) Enabled() bool {
+	return !t.disabled.Load()
+}
+
+// InitNewTrace starts the trace of the batch, returning its trace id and aggregator span id, empty
+// if it couldn't be started, the batch isn't sampled or the telemetry is disabled
+func (t *Telemetry) InitNewTrace(batchMerkleRoot [32]byte) types.TaskTraceContext {
+	if !t.Enabled() || !t.sampler.sample(batchMerkleRoot) {
+		return types.TaskTraceContext{}
 	}
+	return t.sink.InitTrace(batchMerkleRoot)
 }
 
-func (t *Telemetry) LogQuorumReached(batchMerkleRoot [32]byte) {
-	body := QuorumReachedMessage{
-		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+func (t *Telemetry) LogOperatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte) {
+	if !t.Enabled() || !t.sampler.isSampled(batchMerkleRoot) {
+		return
 	}
-	if err := t.sendTelemetryMessage("/api/quorumReached", body); err != nil {
-		t.logger.Warn("[Telemetry] Error in LogQuorumReached", "error", err)
+	t.sink.OperatorResponse(batchMerkleRoot, operatorId)
+}
+
+// LogQuorumReached records the quorum reached by the batch, with the number of operators that didn't sign it
+func (t *Telemetry) LogQuorumReached(batchMerkleRoot [32]byte, nonSigners int) {
+	if !t.Enabled() || !t.sampler.isSampled(batchMerkleRoot) {
+		return
 	}
+	t.sink.QuorumReached(batchMerkleRoot, nonSigners)
 }
 
+// LogTaskError records the error of the batch, starting its trace if it wasn't sampled
 func (t *Telemetry) LogTaskError(batchMerkleRoot [32]byte, taskError error) {
-	body := TaskErrorMessage{
-		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
-		TaskError:  taskError.Error(),
+	if !t.Enabled() {
+		return
 	}
-	if err := t.sendTelemetryMessage("/api/taskError", body); err != nil {
-		t.logger.Warn("[Telemetry] Error in LogTaskError", "error", err)
+	if t.sampler.forceSample(batchMerkleRoot) {
+		t.sink.InitTrace(batchMerkleRoot)
 	}
+	t.sink.TaskError(batchMerkleRoot, taskError)
 }
 
 func (t *Telemetry) TaskSetGasPrice(batchMerkleRoot [32]byte, gasPrice string) {
-	body := TaskSetGasPriceMessage{
-		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
-		GasPrice:   gasPrice,
-	}
-	if err := t.sendTelemetryMessage("/api/aggregatorTaskSetGasPrice", body); err != nil {
-		t.logger.Warn("[Telemetry] Error in LogOperatorResponse", "error", err)
+	if !t.Enabled() || !t.sampler.isSampled(batchMerkleRoot) {
+		return
 	}
+	t.sink.TaskSetGasPrice(batchMerkleRoot, gasPrice)
 }
 
-func (t *Telemetry) TaskSentToEthereum(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string) {
-	body := TaskSentToEthereumMessage{
-		MerkleRoot:        fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
-		TxHash:            txHash,
-		EffectiveGasPrice: effectiveGasPrice,
-	}
-	if err := t.sendTelemetryMessage("/api/aggregatorTaskSent", body); err != nil {
-		t.logger.Warn("[Telemetry] Error in TaskSentToEthereum", "error", err)
+// TaskSentToEthereum records the response of the batch, with the gas used and the fee paid by its transaction
+func (t *Telemetry) TaskSentToEthereum(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string, gasUsed string, feePaid string) {
+	if !t.Enabled() || !t.sampler.isSampled(batchMerkleRoot) {
+		return
 	}
+	t.sink.TaskSentToEthereum(batchMerkleRoot, txHash, effectiveGasPrice, gasUsed, feePaid)
 }
 
 func (t *Telemetry) FinishTrace(batchMerkleRoot [32]byte) {
 	// In order to wait for all operator responses, even if the quorum is reached, this function has a delayed execution
 	go func() {
 		time.Sleep(10 * time.Second)
-		body := TraceMessage{
-			MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		if !t.sampler.isSampled(batchMerkleRoot) {
+			return
 		}
-		if err := t.sendTelemetryMessage("/api/finishTaskTrace", body); err != nil {
-			t.logger.Warn("[Telemetry] Error in FinishTrace", "error", err)
+		defer t.sampler.release(batchMerkleRoot)
+		if t.Enabled() {
+			t.sink.FinishTrace(batchMerkleRoot)
 		}
 	}()
 }
 
-func (t *Telemetry) sendTelemetryMessage(endpoint string, message interface{}) error {
-	encodedBody, err := json.Marshal(message)
-	if err != nil {
-		t.logger.Warn("[Telemetry] Error marshalling JSON", "error", err)
-		return fmt.Errorf("error marshalling JSON: %w", err)
+// Close sends the traces buffered by the sink and releases it, the later events are dropped
+func (t *Telemetry) Close() {
+	if closer, ok := t.sink.(telemetrySinkCloser); ok {
+		closer.Close()
 	}
+}
 
-	t.logger.Info("[Telemetry] Sending message.", "endpoint", endpoint, "message", message)
+// multiTelemetrySink sends the traces to every sink, in order. The trace context is the first
+// one known.
+type multiTelemetrySink []TelemetrySink
+
+func (m multiTelemetrySink) InitTrace(batchMerkleRoot [32]byte) types.TaskTraceContext {
+	var traceContext types.TaskTraceContext
+	for _, sink := range m {
+		sinkTraceContext := sink.InitTrace(batchMerkleRoot)
+		if traceContext.TraceId == "" {
+			traceContext = sinkTraceContext
+		}
+	}
+	return traceContext
+}
 
-	fullURL := t.baseURL.ResolveReference(&url.URL{Path: endpoint})
+func (m multiTelemetrySink) OperatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte) {
+	for _, sink := range m {
+		sink.OperatorResponse(batchMerkleRoot, operatorId)
+	}
+}
 
-	resp, err := t.client.Post(fullURL.String(), "application/json", bytes.NewBuffer(encodedBody))
-	if err != nil {
-		t.logger.Warn("[Telemetry] Error sending POST request", "error", err)
-		return fmt.Errorf("error making POST request: %w", err)
+func (m multiTelemetrySink) QuorumReached(batchMerkleRoot [32]byte, nonSigners int) {
+	for _, sink := range m {
+		sink.QuorumReached(batchMerkleRoot, nonSigners)
 	}
-	defer resp.Body.Close()
+}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		t.logger.Warn("[Telemetry] Error reading response body", "error", err)
-		return fmt.Errorf("error reading response body: %w", err)
+func (m multiTelemetrySink) TaskError(batchMerkleRoot [32]byte, taskError error) {
+	for _, sink := range m {
+		sink.TaskError(batchMerkleRoot, taskError)
 	}
+}
+
+func (m multiTelemetrySink) TaskSetGasPrice(batchMerkleRoot [32]byte, gasPrice string) {
+	for _, sink := range m {
+		sink.TaskSetGasPrice(batchMerkleRoot, gasPrice)
+	}
+}
+
+func (m multiTelemetrySink) TaskSentToEthereum(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string, gasUsed string, feePaid string) {
+	for _, sink := range m {
+		sink.TaskSentToEthereum(batchMerkleRoot, txHash, effectiveGasPrice, gasUsed, feePaid)
+	}
+}
+
+func (m multiTelemetrySink) FinishTrace(batchMerkleRoot [32]byte) {
+	for _, sink := range m {
+		sink.FinishTrace(batchMerkleRoot)
+	}
+}
+
+func (m multiTelemetrySink) Close() {
+	for _, sink := range m {
+		if closer, ok := sink.(telemetrySinkCloser); ok {
+			closer.Close()
+		}
+	}
+}
 
-	t.logger.Info("[Telemetry] Response received", "status", resp.Status, "response_body", string(respBody))
+// noopTelemetrySink drops the traces
+type noopTelemetrySink struct{}
 
-	return nil
+func (noopTelemetrySink) InitTrace([32]byte) types.TaskTraceContext {
+	return types.TaskTraceContext{}
 }
+func (noopTelemetrySink) OperatorResponse([32]byte, [32]byte)                         {}
+func (noopTelemetrySink) QuorumReached([32]byte, int)                                 {}
+func (noopTelemetrySink) TaskError([32]byte, error)                                   {}
+func (noopTelemetrySink) TaskSetGasPrice([32]byte, string)                            {}
+func (noopTelemetrySink) TaskSentToEthereum([32]byte, string, string, string, string) {}
+func (noopTelemetrySink) FinishTrace([32]byte)                                        {}
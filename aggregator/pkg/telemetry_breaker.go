@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+const (
+	DefaultTelemetryBreakerThreshold     = 5
+	DefaultTelemetryBreakerProbeInterval = 30 * time.Second
+)
+
+// telemetryBreaker stops sending messages to the telemetry API after threshold consecutive failures
+// to reach it, so a dead API doesn't slow down the tasks waiting on every message to time out.
+// While open, a single message is let through every probe interval to check whether the API
+// recovered, closing the breaker once one succeeds.
+type telemetryBreaker struct {
+	threshold     int
+	probeInterval time.Duration
+	logger        logging.Logger
+	now           func() time.Time
+
+	mutex    sync.Mutex
+	failures int
+	// nextProbe is when the next message is let through while open, zero if closed
+	nextProbe time.Time
+}
+
+func newTelemetryBreaker(threshold int, probeInterval time.Duration, logger logging.Logger) *telemetryBreaker {
+	if threshold <= 0 {
+		threshold = DefaultTelemetryBreakerThreshold
+	}
+	if probeInterval <= 0 {
+		probeInterval = DefaultTelemetryBreakerProbeInterval
+	}
+	return &telemetryBreaker{threshold: threshold, probeInterval: probeInterval, logger: logger, now: time.Now}
+}
+
+// allow returns whether a message can be sent, which while open is only the probe
+func (b *telemetryBreaker) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.nextProbe.IsZero() {
+		return true
+	}
+	now := b.now()
+	if now.Before(b.nextProbe) {
+		return false
+	}
+	// the other messages wait for the probe result until the next interval
+	b.nextProbe = now.Add(b.probeInterval)
+	return true
+}
+
+// record counts the result of sending a message, opening or closing the breaker
+func (b *telemetryBreaker) record(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !errors.Is(err, errTelemetryUnreachable) {
+		if !b.nextProbe.IsZero() {
+			b.logger.Info("[Telemetry] Telemetry API recovered, closing the circuit")
+		}
+		b.failures = 0
+		b.nextProbe = time.Time{}
+		return
+	}
+	b.failures++
+	if b.failures == b.threshold {
+		b.logger.Warn("[Telemetry] Telemetry API keeps failing, opening the circuit", "failures", b.failures, "probe_interval", b.probeInterval)
+	}
+	if b.failures >= b.threshold {
+		b.nextProbe = b.now().Add(b.probeInterval)
+	}
+}
+
+// isOpen returns whether the messages are being stopped
+func (b *telemetryBreaker) isOpen() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return !b.nextProbe.IsZero()
+}
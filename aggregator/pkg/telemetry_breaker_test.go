@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func TestTelemetryBreaker(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	breaker := newTelemetryBreaker(2, time.Minute, logger)
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+
+	breaker.record(errTelemetryUnreachable)
+	if !breaker.allow() {
+		t.Fatalf("Expected the breaker to stay closed below the threshold")
+	}
+	// other errors don't mean the API is down
+	breaker.record(errors.New("telemetry API token rejected"))
+	breaker.record(errTelemetryUnreachable)
+	if breaker.isOpen() {
+		t.Fatalf("Expected the failures to be consecutive")
+	}
+	breaker.record(errTelemetryUnreachable)
+	if !breaker.isOpen() || breaker.allow() {
+		t.Fatalf("Expected the breaker to open at the threshold")
+	}
+
+	// a single probe is let through every interval
+	now = now.Add(time.Minute)
+	if !breaker.allow() || breaker.allow() {
+		t.Fatalf("Expected a single probe once the interval passed")
+	}
+	breaker.record(errTelemetryUnreachable)
+	now = now.Add(time.Minute)
+	if !breaker.allow() {
+		t.Fatalf("Expected another probe after a failed one")
+	}
+	breaker.record(nil)
+	if breaker.isOpen() || !breaker.allow() {
+		t.Errorf("Expected a successful probe to close the breaker")
+	}
+}
+
+func TestTelemetryBreakerStopsMessages(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	telemetry, err := NewTelemetryWithConfig(TelemetryConfig{
+		ServerAddress:    strings.TrimPrefix(unreachable.URL, "http://"),
+		BreakerThreshold: 2,
+	}, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	httpSink := telemetry.sink.(*httpTelemetrySink)
+	for i := 0; i < 2; i++ {
+		telemetry.LogQuorumReached([32]byte{1}, 0)
+	}
+	if !httpSink.breaker.isOpen() {
+		t.Fatalf("Expected the breaker to open after the failures")
+	}
+
+	// the messages are dropped without reaching the API while open, even if it recovered
+	httpSink.baseURL.Host = strings.TrimPrefix(server.URL, "http://")
+	telemetry.LogQuorumReached([32]byte{1}, 0)
+	if requests.Load() != 0 {
+		t.Fatalf("Expected no messages sent while open, got %d", requests.Load())
+	}
+
+	// the next probe closes it
+	httpSink.breaker.nextProbe = time.Now()
+	telemetry.LogQuorumReached([32]byte{1}, 0)
+	if requests.Load() != 1 || httpSink.breaker.isOpen() {
+		t.Errorf("Expected the probe to be sent and close the breaker, got %d messages", requests.Load())
+	}
+}
@@ -0,0 +1,482 @@
+package pkg
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// telemetryGrpcInitMethod starts the trace of a batch, returning its trace context
+	telemetryGrpcInitMethod = "/aligned.telemetry.v1.Telemetry/InitTaskTrace"
+	// telemetryGrpcStreamMethod streams the rest of the events of the traces, in batches
+	telemetryGrpcStreamMethod = "/aligned.telemetry.v1.Telemetry/StreamEvents"
+
+	DefaultTelemetryGrpcFlushInterval = time.Second
+	// telemetryGrpcMaxBatch is the max number of events sent in a single stream message
+	telemetryGrpcMaxBatch = 100
+	// telemetryGrpcMaxQueued is the max number of events waiting to be sent, the oldest ones are
+	// dropped once exceeded
+	telemetryGrpcMaxQueued = 10_000
+)
+
+// grpcTelemetrySink sends the task traces to the telemetry API over a persistent gRPC connection.
+// Starting a trace is a call, since its trace context is needed right away, while the rest of the
+// events are queued and streamed in batches, so they never wait on the telemetry API. The events
+// are the same messages sent by the http sink, with their endpoints.
+type grpcTelemetrySink struct {
+	conn          *grpc.ClientConn
+	apiToken      string
+	logger        logging.Logger
+	breaker       *telemetryBreaker
+	flushInterval time.Duration
+
+	mutex sync.Mutex
+	queue []telemetryGrpcEvent
+	// full is signaled once a batch of events is queued, to send it without waiting for the interval
+	full chan struct{}
+	// stop ends the flushing goroutine, which closes flushed once it returns
+	stop      chan struct{}
+	flushed   chan struct{}
+	closeOnce sync.Once
+
+	// stream is the open events stream, only used by the flushing goroutine
+	stream       grpc.ClientStream
+	cancelStream context.CancelFunc
+}
+
+func newGrpcTelemetrySink(config TelemetryConfig, logger logging.Logger) (*grpcTelemetrySink, error) {
+	transportCredentials := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if config.GrpcInsecure {
+		transportCredentials = insecure.NewCredentials()
+	}
+	// The connection is established in the background, it doesn't fail if the telemetry API is down
+	conn, err := grpc.Dial(config.GrpcEndpoint, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the telemetry API: %w", err)
+	}
+	flushInterval := config.GrpcFlushInterval
+	if flushInterval <= 0 {
+		flushInterval = DefaultTelemetryGrpcFlushInterval
+	}
+	sink := &grpcTelemetrySink{
+		conn:          conn,
+		apiToken:      config.ApiToken,
+		logger:        logger,
+		breaker:       newTelemetryBreaker(config.BreakerThreshold, config.BreakerProbeInterval, logger),
+		flushInterval: flushInterval,
+		full:          make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		flushed:       make(chan struct{}),
+	}
+	go sink.flushEvents()
+	return sink, nil
+}
+
+// InitTrace returns the trace id and aggregator span id of the telemetry API, empty if it couldn't
+// be started
+func (s *grpcTelemetrySink) InitTrace(batchMerkleRoot [32]byte) types.TaskTraceContext {
+	if !s.breaker.allow() {
+		return types.TaskTraceContext{}
+	}
+	ctx, cancel := context.WithTimeout(s.outgoingContext(context.Background()), telemetryRequestTimeout)
+	defer cancel()
+
+	request := &telemetryGrpcInitRequest{merkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:]))}
+	response := &telemetryGrpcInitResponse{}
+	err := s.conn.Invoke(ctx, telemetryGrpcInitMethod, request, response, grpc.ForceCodec(telemetryGrpcCodec{}))
+	if code := status.Code(err); code == codes.Unavailable || code == codes.DeadlineExceeded {
+		err = fmt.Errorf("%w: %w", errTelemetryUnreachable, err)
+	}
+	s.breaker.record(err)
+	if err != nil {
+		s.logger.Warn("[Telemetry] Error in InitNewTrace", "error", err)
+		return types.TaskTraceContext{}
+	}
+	return types.TaskTraceContext{TraceId: response.traceId, SpanId: response.spanId}
+}
+
+func (s *grpcTelemetrySink) OperatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte) {
+	s.enqueue("/api/operatorResponse", OperatorResponseMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		OperatorId: fmt.Sprintf("0x%s", hex.EncodeToString(operatorId[:])),
+	})
+}
+
+func (s *grpcTelemetrySink) QuorumReached(batchMerkleRoot [32]byte, nonSigners int) {
+	s.enqueue("/api/quorumReached", QuorumReachedMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		NonSigners: nonSigners,
+	})
+}
+
+func (s *grpcTelemetrySink) TaskError(batchMerkleRoot [32]byte, taskError error) {
+	s.enqueue("/api/taskError", TaskErrorMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		TaskError:  taskError.Error(),
+	})
+}
+
+func (s *grpcTelemetrySink) TaskSetGasPrice(batchMerkleRoot [32]byte, gasPrice string) {
+	s.enqueue("/api/aggregatorTaskSetGasPrice", TaskSetGasPriceMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		GasPrice:   gasPrice,
+	})
+}
+
+func (s *grpcTelemetrySink) TaskSentToEthereum(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string, gasUsed string, feePaid string) {
+	s.enqueue("/api/aggregatorTaskSent", TaskSentToEthereumMessage{
+		MerkleRoot:        fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		TxHash:            txHash,
+		EffectiveGasPrice: effectiveGasPrice,
+		GasUsed:           gasUsed,
+		FeePaid:           feePaid,
+	})
+}
+
+func (s *grpcTelemetrySink) FinishTrace(batchMerkleRoot [32]byte) {
+	s.enqueue("/api/finishTaskTrace", TraceMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+	})
+}
+
+// enqueue queues the message to the endpoint to be streamed with the next batch of events
+func (s *grpcTelemetrySink) enqueue(endpoint string, message interface{}) {
+	encodedMessage, err := json.Marshal(message)
+	if err != nil {
+		s.logger.Warn("[Telemetry] Error marshalling JSON", "error", err)
+		return
+	}
+
+	s.mutex.Lock()
+	s.queue = append(s.queue, telemetryGrpcEvent{endpoint: endpoint, message: encodedMessage})
+	s.dropOverflow()
+	full := len(s.queue) >= telemetryGrpcMaxBatch
+	s.mutex.Unlock()
+
+	if full {
+		select {
+		case s.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// dropOverflow drops the oldest events over the max. Must be called with the mutex locked.
+func (s *grpcTelemetrySink) dropOverflow() {
+	if overflow := len(s.queue) - telemetryGrpcMaxQueued; overflow > 0 {
+		s.logger.Warn("[Telemetry] Too many events queued, dropping the oldest", "dropped", overflow)
+		s.queue = s.queue[overflow:]
+	}
+}
+
+// flushEvents sends the queued events every flush interval, or as soon as a batch is queued,
+// until the sink is closed
+func (s *grpcTelemetrySink) flushEvents() {
+	defer close(s.flushed)
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.full:
+		case <-s.stop:
+			return
+		}
+		s.flush()
+	}
+}
+
+// Close stops streaming the events, sending the queued ones first, and closes the connection.
+// The events that can't be sent are dropped.
+func (s *grpcTelemetrySink) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.flushed
+		s.flush()
+
+		if s.stream != nil {
+			if err := s.stream.CloseSend(); err != nil {
+				s.logger.Warn("[Telemetry] Could not close the events stream", "error", err)
+			} else {
+				// the response is received once the telemetry API got every event
+				timer := time.AfterFunc(telemetryRequestTimeout, s.cancelStream)
+				if err := s.stream.RecvMsg(&telemetryGrpcStreamResponse{}); err != nil {
+					s.logger.Warn("[Telemetry] Could not close the events stream", "error", err)
+				}
+				timer.Stop()
+			}
+			s.cancelStream()
+			s.stream, s.cancelStream = nil, nil
+		}
+
+		s.mutex.Lock()
+		if dropped := len(s.queue); dropped > 0 {
+			s.logger.Warn("[Telemetry] Dropping the events that couldn't be sent", "dropped", dropped)
+		}
+		s.mutex.Unlock()
+		if err := s.conn.Close(); err != nil {
+			s.logger.Warn("[Telemetry] Could not close the connection to the telemetry API", "error", err)
+		}
+	})
+}
+
+// flush sends the queued events in batches. The events of a batch that can't be sent are queued
+// again, to be sent with the next flush.
+func (s *grpcTelemetrySink) flush() {
+	for {
+		s.mutex.Lock()
+		n := min(len(s.queue), telemetryGrpcMaxBatch)
+		events := append([]telemetryGrpcEvent{}, s.queue[:n]...)
+		s.queue = s.queue[n:]
+		s.mutex.Unlock()
+		if n == 0 {
+			return
+		}
+
+		if err := s.sendEvents(events); err != nil {
+			s.logger.Warn("[Telemetry] Could not stream events, will try again", "error", err, "events", n)
+			s.mutex.Lock()
+			s.queue = append(events, s.queue...)
+			s.dropOverflow()
+			s.mutex.Unlock()
+			return
+		}
+	}
+}
+
+// sendEvents sends the events through the stream, opening it if it isn't open. Events sent right
+// before the connection is lost may not be received.
+func (s *grpcTelemetrySink) sendEvents(events []telemetryGrpcEvent) error {
+	if s.stream == nil {
+		ctx, cancel := context.WithCancel(s.outgoingContext(context.Background()))
+		stream, err := s.conn.NewStream(ctx, &grpc.StreamDesc{StreamName: "StreamEvents", ClientStreams: true},
+			telemetryGrpcStreamMethod, grpc.ForceCodec(telemetryGrpcCodec{}))
+		if err != nil {
+			cancel()
+			return err
+		}
+		s.stream, s.cancelStream = stream, cancel
+	}
+
+	err := s.stream.SendMsg(&telemetryGrpcEvents{events: events})
+	if err == nil {
+		return nil
+	}
+	// the server closed the stream, the reason is received on it
+	if errors.Is(err, io.EOF) {
+		if recvErr := s.stream.RecvMsg(&telemetryGrpcStreamResponse{}); recvErr != nil {
+			err = recvErr
+		}
+	}
+	s.cancelStream()
+	s.stream, s.cancelStream = nil, nil
+	return err
+}
+
+func (s *grpcTelemetrySink) outgoingContext(ctx context.Context) context.Context {
+	if s.apiToken == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.apiToken)
+}
+
+// The telemetry gRPC messages are encoded by hand, like the remote signer ones, since they are few
+// and simple. They are defined in telemetry_api/priv/proto/telemetry.proto.
+type telemetryGrpcInitRequest struct {
+	merkleRoot string
+}
+
+type telemetryGrpcInitResponse struct {
+	merkleRoot string
+	traceId    string
+	spanId     string
+}
+
+type telemetryGrpcEvent struct {
+	endpoint string
+	// message is the JSON message sent to the endpoint by the http sink
+	message []byte
+}
+
+type telemetryGrpcEvents struct {
+	events []telemetryGrpcEvent
+}
+
+type telemetryGrpcStreamResponse struct {
+	received uint64
+}
+
+func (r *telemetryGrpcInitRequest) marshal() []byte {
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendString(b, r.merkleRoot)
+}
+
+func (r *telemetryGrpcInitRequest) unmarshal(b []byte) error {
+	return unmarshalTelemetryProtoFields(b, func(num protowire.Number, value []byte, _ uint64) error {
+		if num == 1 {
+			r.merkleRoot = string(value)
+		}
+		return nil
+	})
+}
+
+func (r *telemetryGrpcInitResponse) marshal() []byte {
+	var b []byte
+	for i, value := range []string{r.merkleRoot, r.traceId, r.spanId} {
+		b = protowire.AppendTag(b, protowire.Number(i+1), protowire.BytesType)
+		b = protowire.AppendString(b, value)
+	}
+	return b
+}
+
+func (r *telemetryGrpcInitResponse) unmarshal(b []byte) error {
+	return unmarshalTelemetryProtoFields(b, func(num protowire.Number, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			r.merkleRoot = string(value)
+		case 2:
+			r.traceId = string(value)
+		case 3:
+			r.spanId = string(value)
+		}
+		return nil
+	})
+}
+
+func (e *telemetryGrpcEvent) marshal() []byte {
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	b = protowire.AppendString(b, e.endpoint)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	return protowire.AppendBytes(b, e.message)
+}
+
+func (e *telemetryGrpcEvent) unmarshal(b []byte) error {
+	return unmarshalTelemetryProtoFields(b, func(num protowire.Number, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			e.endpoint = string(value)
+		case 2:
+			e.message = value
+		}
+		return nil
+	})
+}
+
+func (e *telemetryGrpcEvents) marshal() []byte {
+	var b []byte
+	for _, event := range e.events {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, event.marshal())
+	}
+	return b
+}
+
+func (e *telemetryGrpcEvents) unmarshal(b []byte) error {
+	return unmarshalTelemetryProtoFields(b, func(num protowire.Number, value []byte, _ uint64) error {
+		if num != 1 {
+			return nil
+		}
+		var event telemetryGrpcEvent
+		if err := event.unmarshal(value); err != nil {
+			return err
+		}
+		e.events = append(e.events, event)
+		return nil
+	})
+}
+
+func (r *telemetryGrpcStreamResponse) marshal() []byte {
+	b := protowire.AppendTag(nil, 1, protowire.VarintType)
+	return protowire.AppendVarint(b, r.received)
+}
+
+func (r *telemetryGrpcStreamResponse) unmarshal(b []byte) error {
+	return unmarshalTelemetryProtoFields(b, func(num protowire.Number, _ []byte, varint uint64) error {
+		if num == 1 {
+			r.received = varint
+		}
+		return nil
+	})
+}
+
+// unmarshalTelemetryProtoFields calls field with the length delimited and varint fields of a
+// protobuf message, skipping the others
+func unmarshalTelemetryProtoFields(b []byte, field func(num protowire.Number, value []byte, varint uint64) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		var err error
+		switch typ {
+		case protowire.BytesType:
+			var value []byte
+			value, n = protowire.ConsumeBytes(b)
+			if n >= 0 {
+				err = field(num, append([]byte(nil), value...), 0)
+			}
+		case protowire.VarintType:
+			var varint uint64
+			varint, n = protowire.ConsumeVarint(b)
+			if n >= 0 {
+				err = field(num, nil, varint)
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+type telemetryGrpcMessage interface {
+	marshal() []byte
+	unmarshal([]byte) error
+}
+
+// telemetryGrpcCodec encodes the hand written telemetry messages as protobuf
+type telemetryGrpcCodec struct{}
+
+func (telemetryGrpcCodec) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(telemetryGrpcMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected telemetry message %T", v)
+	}
+	return message.marshal(), nil
+}
+
+func (telemetryGrpcCodec) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(telemetryGrpcMessage)
+	if !ok {
+		return fmt.Errorf("unexpected telemetry message %T", v)
+	}
+	return message.unmarshal(data)
+}
+
+func (telemetryGrpcCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,160 @@
+package pkg
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+)
+
+// startFakeGrpcTelemetryApi serves the telemetry gRPC methods, sending the streamed events to events
+func startFakeGrpcTelemetryApi(t *testing.T, events chan<- telemetryGrpcEvents) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	server := grpc.NewServer(
+		grpc.ForceServerCodec(telemetryGrpcCodec{}),
+		grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+			md, _ := metadata.FromIncomingContext(stream.Context())
+			if authorization := md.Get("authorization"); len(authorization) != 1 || authorization[0] != "Bearer aggregator-token" {
+				t.Errorf("Expected the api token to be sent, got %v", authorization)
+			}
+			method, _ := grpc.MethodFromServerStream(stream)
+			switch method {
+			case telemetryGrpcInitMethod:
+				request := &telemetryGrpcInitRequest{}
+				if err := stream.RecvMsg(request); err != nil {
+					return err
+				}
+				return stream.SendMsg(&telemetryGrpcInitResponse{merkleRoot: request.merkleRoot, traceId: "1234", spanId: "5678"})
+			case telemetryGrpcStreamMethod:
+				var received uint64
+				for {
+					message := &telemetryGrpcEvents{}
+					err := stream.RecvMsg(message)
+					if errors.Is(err, io.EOF) {
+						return stream.SendMsg(&telemetryGrpcStreamResponse{received: received})
+					}
+					if err != nil {
+						return err
+					}
+					received += uint64(len(message.events))
+					events <- *message
+				}
+			default:
+				t.Errorf("Unexpected telemetry method %s", method)
+				return nil
+			}
+		}),
+	)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestGrpcTelemetryStreamsEventBatches(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	events := make(chan telemetryGrpcEvents, 10)
+	telemetry, err := NewTelemetryWithConfig(TelemetryConfig{
+		Transport:         GrpcTelemetryTransport,
+		GrpcEndpoint:      startFakeGrpcTelemetryApi(t, events),
+		GrpcInsecure:      true,
+		GrpcFlushInterval: time.Hour,
+		ApiToken:          "aggregator-token",
+	}, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	traceContext := telemetry.InitNewTrace([32]byte{1})
+	if traceContext.TraceId != "1234" || traceContext.SpanId != "5678" {
+		t.Fatalf("Expected the trace to be started, got %+v", traceContext)
+	}
+
+	// a full batch is sent without waiting for the flush interval, in a single message
+	for i := 0; i < telemetryGrpcMaxBatch; i++ {
+		telemetry.LogOperatorResponse([32]byte{1}, [32]byte{byte(i)})
+	}
+	select {
+	case message := <-events:
+		if len(message.events) != telemetryGrpcMaxBatch {
+			t.Errorf("Expected the %d events in one message, got %d", telemetryGrpcMaxBatch, len(message.events))
+		}
+		if event := message.events[0]; event.endpoint != "/api/operatorResponse" || len(event.message) == 0 {
+			t.Errorf("Unexpected event %s %s", event.endpoint, event.message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the batch of events to be streamed")
+	}
+
+	// the following events go through the same stream
+	telemetry.LogQuorumReached([32]byte{1}, 0)
+	telemetry.sink.(*grpcTelemetrySink).full <- struct{}{}
+	select {
+	case message := <-events:
+		if len(message.events) != 1 || message.events[0].endpoint != "/api/quorumReached" {
+			t.Errorf("Unexpected events %+v", message.events)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the quorum event to be streamed")
+	}
+}
+
+func TestGrpcTelemetryCloseSendsQueuedEvents(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	events := make(chan telemetryGrpcEvents, 10)
+	telemetry, err := NewTelemetryWithConfig(TelemetryConfig{
+		Transport:         GrpcTelemetryTransport,
+		GrpcEndpoint:      startFakeGrpcTelemetryApi(t, events),
+		GrpcInsecure:      true,
+		GrpcFlushInterval: time.Hour,
+		ApiToken:          "aggregator-token",
+	}, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	telemetry.InitNewTrace([32]byte{1})
+	telemetry.LogQuorumReached([32]byte{1}, 0)
+
+	// the events queued until the next flush are sent before closing the connection
+	telemetry.Close()
+	select {
+	case message := <-events:
+		if len(message.events) != 1 || message.events[0].endpoint != "/api/quorumReached" {
+			t.Errorf("Unexpected events %+v", message.events)
+		}
+	default:
+		t.Errorf("Expected the queued events to be streamed on close")
+	}
+	if state := telemetry.sink.(*grpcTelemetrySink).conn.GetState(); state != connectivity.Shutdown {
+		t.Errorf("Expected the connection to be closed, got %s", state)
+	}
+	// closing again does nothing
+	telemetry.Close()
+}
+
+func TestGrpcTelemetryMessagesEncoding(t *testing.T) {
+	message := &telemetryGrpcEvents{events: []telemetryGrpcEvent{
+		{endpoint: "/api/quorumReached", message: []byte(`{"merkle_root":"0x01"}`)},
+		{endpoint: "/api/finishTaskTrace", message: []byte(`{"merkle_root":"0x01"}`)},
+	}}
+	decoded := &telemetryGrpcEvents{}
+	if err := decoded.unmarshal(message.marshal()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(decoded.events) != 2 || decoded.events[1].endpoint != "/api/finishTaskTrace" || string(decoded.events[0].message) != `{"merkle_root":"0x01"}` {
+		t.Errorf("Unexpected decoded events %+v", decoded.events)
+	}
+
+	response := &telemetryGrpcStreamResponse{}
+	if err := response.unmarshal((&telemetryGrpcStreamResponse{received: 300}).marshal()); err != nil || response.received != 300 {
+		t.Errorf("Expected 300 received events, got %d and %v", response.received, err)
+	}
+}
@@ -0,0 +1,236 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// httpTelemetrySink sends the task traces to the telemetry API, which records them
+type httpTelemetrySink struct {
+	client  http.Client
+	baseURL url.URL
+	logger  logging.Logger
+	// spill keeps the messages while the telemetry API is unreachable, if set
+	spill    *telemetrySpill
+	breaker  *telemetryBreaker
+	apiToken string
+}
+
+// telemetryRequestTimeout bounds the time a task waits on each message to the telemetry API
+const telemetryRequestTimeout = 5 * time.Second
+
+func newHttpTelemetrySink(config TelemetryConfig, logger logging.Logger) (*httpTelemetrySink, error) {
+	sink := &httpTelemetrySink{
+		client: http.Client{Timeout: telemetryRequestTimeout},
+		baseURL: url.URL{
+			Scheme: "http",
+			Host:   config.ServerAddress,
+		},
+		logger:   logger,
+		breaker:  newTelemetryBreaker(config.BreakerThreshold, config.BreakerProbeInterval, logger),
+		apiToken: config.ApiToken,
+	}
+	if config.SpillDir != "" {
+		spill, err := newTelemetrySpill(config.SpillDir, config.SpillMaxFileSize, config.SpillMaxFiles, logger)
+		if err != nil {
+			return nil, fmt.Errorf("could not create telemetry spill: %w", err)
+		}
+		sink.spill = spill
+		go sink.resendSpilledMessages()
+	}
+	return sink, nil
+}
+
+// InitTrace returns the trace id and aggregator span id of the telemetry API, empty if it couldn't
+// be started or the message was spilled or dropped
+func (s *httpTelemetrySink) InitTrace(batchMerkleRoot [32]byte) types.TaskTraceContext {
+	body := TraceMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+	}
+	respBody, err := s.send("/api/initTaskTrace", body)
+	if err != nil {
+		s.logger.Warn("[Telemetry] Error in InitNewTrace", "error", err)
+		return types.TaskTraceContext{}
+	}
+	// spilled or dropped, the trace id is only known once it's sent
+	if respBody == nil {
+		return types.TaskTraceContext{}
+	}
+	var response TraceResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		s.logger.Warn("[Telemetry] Error decoding InitNewTrace response", "error", err)
+		return types.TaskTraceContext{}
+	}
+	return types.TaskTraceContext{TraceId: response.TraceId, SpanId: response.SpanId}
+}
+
+func (s *httpTelemetrySink) OperatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte) {
+	body := OperatorResponseMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		OperatorId: fmt.Sprintf("0x%s", hex.EncodeToString(operatorId[:])),
+	}
+	if err := s.sendTelemetryMessage("/api/operatorResponse", body); err != nil {
+		s.logger.Warn("[Telemetry] Error in LogOperatorResponse", "error", err)
+	}
+}
+
+func (s *httpTelemetrySink) QuorumReached(batchMerkleRoot [32]byte, nonSigners int) {
+	body := QuorumReachedMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		NonSigners: nonSigners,
+	}
+	if err := s.sendTelemetryMessage("/api/quorumReached", body); err != nil {
+		s.logger.Warn("[Telemetry] Error in LogQuorumReached", "error", err)
+	}
+}
+
+func (s *httpTelemetrySink) TaskError(batchMerkleRoot [32]byte, taskError error) {
+	body := TaskErrorMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		TaskError:  taskError.Error(),
+	}
+	if err := s.sendTelemetryMessage("/api/taskError", body); err != nil {
+		s.logger.Warn("[Telemetry] Error in LogTaskError", "error", err)
+	}
+}
+
+func (s *httpTelemetrySink) TaskSetGasPrice(batchMerkleRoot [32]byte, gasPrice string) {
+	body := TaskSetGasPriceMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		GasPrice:   gasPrice,
+	}
+	if err := s.sendTelemetryMessage("/api/aggregatorTaskSetGasPrice", body); err != nil {
+		s.logger.Warn("[Telemetry] Error in TaskSetGasPrice", "error", err)
+	}
+}
+
+func (s *httpTelemetrySink) TaskSentToEthereum(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string, gasUsed string, feePaid string) {
+	body := TaskSentToEthereumMessage{
+		MerkleRoot:        fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+		TxHash:            txHash,
+		EffectiveGasPrice: effectiveGasPrice,
+		GasUsed:           gasUsed,
+		FeePaid:           feePaid,
+	}
+	if err := s.sendTelemetryMessage("/api/aggregatorTaskSent", body); err != nil {
+		s.logger.Warn("[Telemetry] Error in TaskSentToEthereum", "error", err)
+	}
+}
+
+func (s *httpTelemetrySink) FinishTrace(batchMerkleRoot [32]byte) {
+	body := TraceMessage{
+		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
+	}
+	if err := s.sendTelemetryMessage("/api/finishTaskTrace", body); err != nil {
+		s.logger.Warn("[Telemetry] Error in FinishTrace", "error", err)
+	}
+}
+
+func (s *httpTelemetrySink) sendTelemetryMessage(endpoint string, message interface{}) error {
+	_, err := s.send(endpoint, message)
+	return err
+}
+
+// send posts the message to the endpoint, or spills it if the telemetry API is unreachable or
+// previous messages are spilled. Without a spill, the message is dropped while the circuit is
+// open. The response body is nil if it was spilled or dropped.
+func (s *httpTelemetrySink) send(endpoint string, message interface{}) ([]byte, error) {
+	if s.spill == nil || !s.spill.isPending() {
+		if s.breaker.allow() {
+			respBody, err := s.postTelemetryMessage(endpoint, message)
+			s.breaker.record(err)
+			if s.spill == nil || !errors.Is(err, errTelemetryUnreachable) {
+				return respBody, err
+			}
+			s.logger.Warn("[Telemetry] Telemetry API unreachable, spilling messages until it recovers", "error", err)
+		} else if s.spill == nil {
+			s.logger.Debug("[Telemetry] Circuit open, dropping message", "endpoint", endpoint)
+			return nil, nil
+		}
+	}
+	if err := s.spill.append(endpoint, message); err != nil {
+		return nil, fmt.Errorf("error spilling message: %w", err)
+	}
+	return nil, nil
+}
+
+// resendSpilledMessages periodically sends the spilled messages until they are all sent
+func (s *httpTelemetrySink) resendSpilledMessages() {
+	ticker := time.NewTicker(telemetrySpillRetryInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.resendSpilled()
+	}
+}
+
+func (s *httpTelemetrySink) resendSpilled() {
+	if !s.spill.isPending() {
+		return
+	}
+	err := s.spill.replay(func(endpoint string, message json.RawMessage) error {
+		_, err := s.postTelemetryMessage(endpoint, message)
+		s.breaker.record(err)
+		if errors.Is(err, errTelemetryUnreachable) {
+			return err
+		}
+		// other errors would happen again, the message is dropped
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn("[Telemetry] Could not send spilled messages, will try again", "error", err)
+		return
+	}
+	s.logger.Info("[Telemetry] Spilled messages sent")
+}
+
+// postTelemetryMessage sends the message to the endpoint, returning the response body
+func (s *httpTelemetrySink) postTelemetryMessage(endpoint string, message interface{}) ([]byte, error) {
+	encodedBody, err := json.Marshal(message)
+	if err != nil {
+		s.logger.Warn("[Telemetry] Error marshalling JSON", "error", err)
+		return nil, fmt.Errorf("error marshalling JSON: %w", err)
+	}
+
+	s.logger.Info("[Telemetry] Sending message.", "endpoint", endpoint, "message", message)
+
+	fullURL := s.baseURL.ResolveReference(&url.URL{Path: endpoint})
+
+	req, err := http.NewRequest(http.MethodPost, fullURL.String(), bytes.NewBuffer(encodedBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating POST request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiToken)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.logger.Warn("[Telemetry] Error sending POST request", "error", err)
+		return nil, fmt.Errorf("error making POST request: %w: %w", errTelemetryUnreachable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		s.logger.Warn("[Telemetry] Message rejected, check telemetry_api_token")
+		return nil, errors.New("telemetry API token rejected")
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.logger.Warn("[Telemetry] Error reading response body", "error", err)
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	s.logger.Info("[Telemetry] Response received", "status", resp.Status, "response_body", string(respBody))
+
+	return respBody, nil
+}
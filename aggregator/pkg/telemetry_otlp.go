@@ -0,0 +1,124 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+const telemetryServiceName = "aligned-aggregator"
+
+// otlpTracer records the task traces as OpenTelemetry spans, one per batch, with the same events
+// the telemetry API records
+type otlpTracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	mutex    sync.Mutex
+	spans    map[[32]byte]trace.Span
+}
+
+// newOtlpExporter exports the spans over OTLP/gRPC to the collector at the endpoint
+func newOtlpExporter(endpoint string, insecure bool) (sdktrace.SpanExporter, error) {
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+	// The connection is established in the background, it doesn't fail if the collector is down
+	return otlptracegrpc.New(context.Background(), options...)
+}
+
+func newOtlpTracer(exporter sdktrace.SpanExporter) *otlpTracer {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(telemetryServiceName))),
+	)
+	return &otlpTracer{
+		provider: provider,
+		tracer:   provider.Tracer(telemetryServiceName),
+		spans:    make(map[[32]byte]trace.Span),
+	}
+}
+
+// InitTrace starts the span of the batch, returning its trace and span ids
+func (t *otlpTracer) InitTrace(batchMerkleRoot [32]byte) types.TaskTraceContext {
+	_, span := t.tracer.Start(context.Background(), "Aggregator",
+		trace.WithAttributes(attribute.String("merkle_root", "0x"+hex.EncodeToString(batchMerkleRoot[:]))))
+	span.AddEvent("New task event received")
+
+	t.mutex.Lock()
+	t.spans[batchMerkleRoot] = span
+	t.mutex.Unlock()
+	return types.TaskTraceContext{
+		TraceId: span.SpanContext().TraceID().String(),
+		SpanId:  span.SpanContext().SpanID().String(),
+	}
+}
+
+func (t *otlpTracer) OperatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte) {
+	t.addEvent(batchMerkleRoot, "Operator Response", attribute.String("operator_id", "0x"+hex.EncodeToString(operatorId[:])))
+}
+
+func (t *otlpTracer) QuorumReached(batchMerkleRoot [32]byte, nonSigners int) {
+	t.setAttributes(batchMerkleRoot, attribute.Int("non_signers", nonSigners))
+	t.addEvent(batchMerkleRoot, "Quorum Reached")
+}
+
+// TaskError marks the span of the batch as failed with the error
+func (t *otlpTracer) TaskError(batchMerkleRoot [32]byte, taskError error) {
+	if span, ok := t.span(batchMerkleRoot); ok {
+		span.RecordError(taskError)
+		span.SetStatus(codes.Error, taskError.Error())
+	}
+}
+
+func (t *otlpTracer) TaskSetGasPrice(batchMerkleRoot [32]byte, gasPrice string) {
+	t.addEvent(batchMerkleRoot, "Gas price set", attribute.String("gas_price", gasPrice))
+}
+
+func (t *otlpTracer) TaskSentToEthereum(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string, gasUsed string, feePaid string) {
+	t.setAttributes(batchMerkleRoot, attribute.String("gas_used", gasUsed), attribute.String("fee_paid", feePaid))
+	t.addEvent(batchMerkleRoot, "Task Sent to Ethereum",
+		attribute.String("tx_hash", txHash), attribute.String("effective_gas_price", effectiveGasPrice))
+}
+
+// addEvent records the event in the span of the batch, if its trace was started
+func (t *otlpTracer) addEvent(batchMerkleRoot [32]byte, name string, attributes ...attribute.KeyValue) {
+	if span, ok := t.span(batchMerkleRoot); ok {
+		span.AddEvent(name, trace.WithAttributes(attributes...))
+	}
+}
+
+// setAttributes sets the attributes of the batch in its span
+func (t *otlpTracer) setAttributes(batchMerkleRoot [32]byte, attributes ...attribute.KeyValue) {
+	if span, ok := t.span(batchMerkleRoot); ok {
+		span.SetAttributes(attributes...)
+	}
+}
+
+// FinishTrace ends the span of the batch, which is exported afterwards
+func (t *otlpTracer) FinishTrace(batchMerkleRoot [32]byte) {
+	t.mutex.Lock()
+	span, ok := t.spans[batchMerkleRoot]
+	delete(t.spans, batchMerkleRoot)
+	t.mutex.Unlock()
+	if ok {
+		span.End()
+	}
+}
+
+func (t *otlpTracer) span(batchMerkleRoot [32]byte) (trace.Span, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	span, ok := t.spans[batchMerkleRoot]
+	return span, ok
+}
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestOtlpTelemetryRecordsTaskSpans(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := newOtlpTracer(exporter)
+	telemetry, err := NewTelemetryWithSink(tracer, TelemetrySampling{}, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	batchMerkleRoot := [32]byte{1}
+	traceContext := telemetry.InitNewTrace(batchMerkleRoot)
+	telemetry.LogOperatorResponse(batchMerkleRoot, [32]byte{2})
+	telemetry.LogQuorumReached(batchMerkleRoot, 2)
+	telemetry.LogTaskError(batchMerkleRoot, errors.New("respond to task failed"))
+	// events of batches without a trace are ignored
+	telemetry.LogQuorumReached([32]byte{3}, 0)
+	tracer.FinishTrace(batchMerkleRoot)
+
+	if err := tracer.provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected one span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.SpanContext.TraceID().String() != traceContext.TraceId || span.SpanContext.SpanID().String() != traceContext.SpanId {
+		t.Errorf("Expected the trace %s and span %s to be returned, got %+v", span.SpanContext.TraceID(), span.SpanContext.SpanID(), traceContext)
+	}
+	// the new task, operator response and quorum events, and the error
+	if len(span.Events) != 4 {
+		t.Errorf("Expected 4 events, got %d", len(span.Events))
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("Expected the span to be failed, got %v", span.Status.Code)
+	}
+	var nonSigners int64 = -1
+	for _, attr := range span.Attributes {
+		if attr.Key == "non_signers" {
+			nonSigners = attr.Value.AsInt64()
+		}
+	}
+	if nonSigners != 2 {
+		t.Errorf("Expected the span to have 2 non signers, got %d", nonSigners)
+	}
+}
+
+func TestTelemetryRejectsUnknownTransport(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	if _, err := NewTelemetryWithConfig(TelemetryConfig{Transport: "udp"}, logger); err == nil {
+		t.Errorf("Expected an unknown transport to be rejected")
+	}
+}
@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+// TelemetrySampling sets which batches are traced, all of them if unset. The batches with errors
+// are always traced, from the error on if they weren't sampled.
+type TelemetrySampling struct {
+	// OneIn traces 1 in every OneIn batches
+	OneIn uint64
+	// Probability traces each batch with the probability, between 0 and 1
+	Probability float64
+}
+
+// telemetrySampler decides whether each batch is traced when its trace would start, and keeps the
+// traced batches until their traces finish. A nil sampler traces every batch.
+type telemetrySampler struct {
+	oneIn       uint64
+	probability float64
+	// count is the number of batches seen, for the 1 in N sampling
+	count   uint64
+	random  func() float64
+	mutex   sync.Mutex
+	sampled map[[32]byte]struct{}
+}
+
+// newTelemetrySampler returns the sampler of the sampling, nil if every batch is traced
+func newTelemetrySampler(sampling TelemetrySampling) (*telemetrySampler, error) {
+	if sampling.OneIn > 0 && sampling.Probability > 0 {
+		return nil, errors.New("telemetry sampling can be 1 in N or probabilistic, not both")
+	}
+	if sampling.Probability < 0 || sampling.Probability > 1 {
+		return nil, errors.New("telemetry sampling probability must be between 0 and 1")
+	}
+	if sampling.OneIn <= 1 && (sampling.Probability == 0 || sampling.Probability == 1) {
+		return nil, nil
+	}
+	return &telemetrySampler{
+		oneIn:       sampling.OneIn,
+		probability: sampling.Probability,
+		random:      rand.Float64,
+		sampled:     make(map[[32]byte]struct{}),
+	}, nil
+}
+
+// sample decides whether the batch is traced
+func (s *telemetrySampler) sample(batchMerkleRoot [32]byte) bool {
+	if s == nil {
+		return true
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var sampled bool
+	if s.oneIn > 0 {
+		sampled = s.count%s.oneIn == 0
+		s.count++
+	} else {
+		sampled = s.random() < s.probability
+	}
+	if sampled {
+		s.sampled[batchMerkleRoot] = struct{}{}
+	}
+	return sampled
+}
+
+// forceSample traces the batch regardless of the sampling, returning false if it already was
+func (s *telemetrySampler) forceSample(batchMerkleRoot [32]byte) bool {
+	if s == nil {
+		return false
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.sampled[batchMerkleRoot]; ok {
+		return false
+	}
+	s.sampled[batchMerkleRoot] = struct{}{}
+	return true
+}
+
+func (s *telemetrySampler) isSampled(batchMerkleRoot [32]byte) bool {
+	if s == nil {
+		return true
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, ok := s.sampled[batchMerkleRoot]
+	return ok
+}
+
+// release forgets the batch once its trace is finished
+func (s *telemetrySampler) release(batchMerkleRoot [32]byte) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.sampled, batchMerkleRoot)
+}
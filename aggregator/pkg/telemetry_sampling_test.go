@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTelemetrySamplingOneIn(t *testing.T) {
+	sampler, err := newTelemetrySampler(TelemetrySampling{OneIn: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var sampled int
+	for i := byte(0); i < 9; i++ {
+		if sampler.sample([32]byte{i}) {
+			sampled++
+		}
+	}
+	if sampled != 3 {
+		t.Errorf("Expected 3 of 9 batches to be sampled, got %d", sampled)
+	}
+}
+
+func TestTelemetrySamplingProbability(t *testing.T) {
+	sampler, err := newTelemetrySampler(TelemetrySampling{Probability: 0.25})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sampler.random = func() float64 { return 0.5 }
+	if sampler.sample([32]byte{1}) {
+		t.Errorf("Expected the batch not to be sampled above the probability")
+	}
+	sampler.random = func() float64 { return 0.1 }
+	if !sampler.sample([32]byte{2}) {
+		t.Errorf("Expected the batch to be sampled below the probability")
+	}
+}
+
+func TestTelemetrySamplingConfig(t *testing.T) {
+	if sampler, err := newTelemetrySampler(TelemetrySampling{}); sampler != nil || err != nil {
+		t.Errorf("Expected every batch to be traced by default, got %v and %v", sampler, err)
+	}
+	if _, err := newTelemetrySampler(TelemetrySampling{OneIn: 2, Probability: 0.5}); err == nil {
+		t.Errorf("Expected both samplings to be rejected")
+	}
+	if _, err := newTelemetrySampler(TelemetrySampling{Probability: 2}); err == nil {
+		t.Errorf("Expected a probability above 1 to be rejected")
+	}
+}
+
+func TestTelemetryAlwaysTracesErrors(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	exporter := tracetest.NewInMemoryExporter()
+	sampler, _ := newTelemetrySampler(TelemetrySampling{Probability: 0.5})
+	sampler.random = func() float64 { return 0.9 }
+	tracer := newOtlpTracer(exporter)
+	telemetry := &Telemetry{sink: tracer, logger: logger, sampler: sampler}
+
+	okRoot, failedRoot := [32]byte{1}, [32]byte{2}
+	for _, root := range [][32]byte{okRoot, failedRoot} {
+		if traceContext := telemetry.InitNewTrace(root); traceContext.TraceId != "" {
+			t.Fatalf("Expected the batch not to be sampled, got trace %s", traceContext.TraceId)
+		}
+		telemetry.LogQuorumReached(root, 0)
+	}
+	telemetry.LogTaskError(failedRoot, errors.New("respond to task failed"))
+	tracer.FinishTrace(okRoot)
+	tracer.FinishTrace(failedRoot)
+
+	if err := tracer.provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if spans := exporter.GetSpans(); len(spans) != 1 {
+		t.Errorf("Expected only the failed batch to be traced, got %d spans", len(spans))
+	}
+}
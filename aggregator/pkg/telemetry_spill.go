@@ -0,0 +1,234 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+const (
+	DefaultTelemetrySpillMaxFileSize = 10 * 1024 * 1024
+	DefaultTelemetrySpillMaxFiles    = 10
+	// telemetrySpillRetryInterval is how often the spilled messages are sent again
+	telemetrySpillRetryInterval = 30 * time.Second
+	telemetrySpillFilePrefix    = "telemetry-spill-"
+	telemetrySpillFileSuffix    = ".jsonl"
+)
+
+// errTelemetryUnreachable is a telemetry message that couldn't reach the telemetry API
+var errTelemetryUnreachable = errors.New("telemetry endpoint unreachable")
+
+// spilledMessage is a telemetry message that couldn't be sent, to be sent again
+type spilledMessage struct {
+	Endpoint string          `json:"endpoint"`
+	Message  json.RawMessage `json:"message"`
+}
+
+// telemetrySpill keeps the telemetry messages sent while the telemetry API is unreachable, in
+// rotating JSON lines files of a directory, numbered in the order they are written. Once a message
+// is spilled, the following ones are too until the spilled ones are sent, so they arrive in order.
+// When the files exceed the max, the oldest ones are dropped.
+type telemetrySpill struct {
+	dir         string
+	maxFileSize int64
+	maxFiles    int
+	logger      logging.Logger
+
+	mutex    sync.Mutex
+	file     *os.File
+	fileSize int64
+	// nextSeq is the number of the next file
+	nextSeq uint64
+	pending bool
+}
+
+func newTelemetrySpill(dir string, maxFileSize int64, maxFiles int, logger logging.Logger) (*telemetrySpill, error) {
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultTelemetrySpillMaxFileSize
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultTelemetrySpillMaxFiles
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	spill := &telemetrySpill{dir: dir, maxFileSize: maxFileSize, maxFiles: maxFiles, logger: logger}
+
+	// messages spilled before a restart are sent too
+	seqs, err := spill.fileSeqs()
+	if err != nil {
+		return nil, err
+	}
+	if len(seqs) > 0 {
+		spill.nextSeq = seqs[len(seqs)-1] + 1
+		spill.pending = true
+	}
+	return spill, nil
+}
+
+// isPending returns whether there are spilled messages not sent yet
+func (s *telemetrySpill) isPending() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.pending
+}
+
+// append spills the message to the endpoint
+func (s *telemetrySpill) append(endpoint string, message interface{}) error {
+	encodedMessage, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(spilledMessage{Endpoint: endpoint, Message: encodedMessage})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil || s.fileSize+int64(len(line)) > s.maxFileSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.fileSize += int64(n)
+	s.pending = true
+	return err
+}
+
+// rotate starts a new file, dropping the oldest ones over the max. Must be called with the mutex locked.
+func (s *telemetrySpill) rotate() error {
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+	file, err := os.OpenFile(s.filePath(s.nextSeq), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	s.file, s.fileSize = file, 0
+	s.nextSeq++
+
+	seqs, err := s.fileSeqs()
+	if err != nil {
+		return err
+	}
+	for len(seqs) > s.maxFiles {
+		s.logger.Warn("[Telemetry] Spilled messages over the max, dropping the oldest", "file", s.filePath(seqs[0]))
+		if err := os.Remove(s.filePath(seqs[0])); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		seqs = seqs[1:]
+	}
+	return nil
+}
+
+// replay sends the spilled messages in order, stopping at the first one that can't be sent, which
+// is kept with the following ones to be sent again later
+func (s *telemetrySpill) replay(send func(endpoint string, message json.RawMessage) error) error {
+	// The current file is closed, so the messages spilled while replaying go to a new one
+	s.mutex.Lock()
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+	seqs, err := s.fileSeqs()
+	s.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, seq := range seqs {
+		if err := s.replayFile(s.filePath(seq), send); err != nil {
+			return err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		s.pending = false
+	}
+	return nil
+}
+
+func (s *telemetrySpill) replayFile(filePath string, send func(endpoint string, message json.RawMessage) error) error {
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		// dropped for being over the max
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), int(s.maxFileSize)+1)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte{}, scanner.Bytes()...))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for i, line := range lines {
+		var message spilledMessage
+		if err := json.Unmarshal(line, &message); err != nil {
+			s.logger.Warn("[Telemetry] Dropping invalid spilled message", "file", filePath, "error", err)
+			continue
+		}
+		if err := send(message.Endpoint, message.Message); err != nil {
+			// keep the messages not sent yet
+			remaining := append(bytes.Join(lines[i:], []byte{'\n'}), '\n')
+			if writeErr := os.WriteFile(filePath, remaining, 0600); writeErr != nil {
+				return writeErr
+			}
+			return err
+		}
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// fileSeqs returns the numbers of the spill files, oldest first
+func (s *telemetrySpill) fileSeqs() ([]uint64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var seqs []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, telemetrySpillFilePrefix) || !strings.HasSuffix(name, telemetrySpillFileSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, telemetrySpillFilePrefix), telemetrySpillFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs, nil
+}
+
+func (s *telemetrySpill) filePath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s%020d%s", telemetrySpillFilePrefix, seq, telemetrySpillFileSuffix))
+}
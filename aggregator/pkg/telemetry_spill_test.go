@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func TestTelemetrySpillsWhileUnreachable(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+
+	var mutex sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		received = append(received, r.URL.Path+" "+string(body))
+		mutex.Unlock()
+		w.Write([]byte(`{"merkle_root":"0x00","trace_id":"1234"}`))
+	}))
+	defer server.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	dir := t.TempDir()
+	telemetry, err := NewTelemetryWithConfig(TelemetryConfig{
+		ServerAddress: strings.TrimPrefix(unreachable.URL, "http://"),
+		SpillDir:      dir,
+	}, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if traceContext := telemetry.InitNewTrace([32]byte{1}); traceContext.TraceId != "" {
+		t.Errorf("Expected no trace id while unreachable, got %s", traceContext.TraceId)
+	}
+	telemetry.LogQuorumReached([32]byte{1}, 0)
+
+	// the following messages are spilled while the previous ones aren't sent, even if reachable
+	serverURL, _ := url.Parse(server.URL)
+	httpSink := telemetry.sink.(*httpTelemetrySink)
+	httpSink.baseURL.Host = serverURL.Host
+	telemetry.TaskSetGasPrice([32]byte{1}, "1000")
+	if len(received) != 0 {
+		t.Fatalf("Expected the messages to be spilled, got %v", received)
+	}
+
+	httpSink.resendSpilled()
+	if len(received) != 3 {
+		t.Fatalf("Expected the 3 spilled messages to be sent, got %v", received)
+	}
+	for i, endpoint := range []string{"/api/initTaskTrace", "/api/quorumReached", "/api/aggregatorTaskSetGasPrice"} {
+		if !strings.HasPrefix(received[i], endpoint+" ") {
+			t.Errorf("Expected message %d to be sent to %s, got %s", i, endpoint, received[i])
+		}
+	}
+	if httpSink.spill.isPending() {
+		t.Errorf("Expected no spilled messages pending")
+	}
+	if seqs, _ := httpSink.spill.fileSeqs(); len(seqs) != 0 {
+		t.Errorf("Expected the spill files to be removed, got %v", seqs)
+	}
+
+	telemetry.LogQuorumReached([32]byte{1}, 0)
+	if len(received) != 4 {
+		t.Errorf("Expected the messages to be sent once recovered, got %v", received)
+	}
+}
+
+func TestTelemetrySpillRotation(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	message := QuorumReachedMessage{MerkleRoot: "0x01"}
+	line, _ := json.Marshal(spilledMessage{Endpoint: "/api/quorumReached", Message: json.RawMessage(`{"merkle_root":"0x01"}`)})
+
+	// one message per file
+	spill, err := newTelemetrySpill(t.TempDir(), int64(len(line)+1), 2, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := spill.append("/api/quorumReached", message); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	seqs, err := spill.fileSeqs()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(seqs) != 2 || seqs[0] != 2 || seqs[1] != 3 {
+		t.Errorf("Expected the 2 newest files to be kept, got %v", seqs)
+	}
+
+	// a restart sends the files kept
+	restarted, err := newTelemetrySpill(spill.dir, spill.maxFileSize, spill.maxFiles, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !restarted.isPending() {
+		t.Errorf("Expected the spilled messages to be pending after a restart")
+	}
+	var sent int
+	err = restarted.replay(func(endpoint string, message json.RawMessage) error {
+		sent++
+		return nil
+	})
+	if err != nil || sent != 2 {
+		t.Errorf("Expected the 2 kept messages to be sent, got %d and %v", sent, err)
+	}
+}
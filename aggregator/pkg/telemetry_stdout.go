@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// stdoutTelemetrySink writes the task traces as JSON lines, one per event, with the same messages
+// sent to the telemetry API. It's meant for development, to follow the tasks without the API.
+type stdoutTelemetrySink struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// stdoutTelemetryEvent is a line written by the stdout sink
+type stdoutTelemetryEvent struct {
+	Time    time.Time   `json:"time"`
+	Event   string      `json:"event"`
+	Message interface{} `json:"message"`
+}
+
+func newStdoutTelemetrySink(writer io.Writer) *stdoutTelemetrySink {
+	return &stdoutTelemetrySink{encoder: json.NewEncoder(writer)}
+}
+
+// InitTrace returns an empty trace context, since the traces aren't recorded anywhere to be joined
+func (s *stdoutTelemetrySink) InitTrace(batchMerkleRoot [32]byte) types.TaskTraceContext {
+	s.write("init_task_trace", TraceMessage{MerkleRoot: hexRoot(batchMerkleRoot)})
+	return types.TaskTraceContext{}
+}
+
+func (s *stdoutTelemetrySink) OperatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte) {
+	s.write("operator_response", OperatorResponseMessage{
+		MerkleRoot: hexRoot(batchMerkleRoot),
+		OperatorId: fmt.Sprintf("0x%s", hex.EncodeToString(operatorId[:])),
+	})
+}
+
+func (s *stdoutTelemetrySink) QuorumReached(batchMerkleRoot [32]byte, nonSigners int) {
+	s.write("quorum_reached", QuorumReachedMessage{MerkleRoot: hexRoot(batchMerkleRoot), NonSigners: nonSigners})
+}
+
+func (s *stdoutTelemetrySink) TaskError(batchMerkleRoot [32]byte, taskError error) {
+	s.write("task_error", TaskErrorMessage{MerkleRoot: hexRoot(batchMerkleRoot), TaskError: taskError.Error()})
+}
+
+func (s *stdoutTelemetrySink) TaskSetGasPrice(batchMerkleRoot [32]byte, gasPrice string) {
+	s.write("task_set_gas_price", TaskSetGasPriceMessage{MerkleRoot: hexRoot(batchMerkleRoot), GasPrice: gasPrice})
+}
+
+func (s *stdoutTelemetrySink) TaskSentToEthereum(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string, gasUsed string, feePaid string) {
+	s.write("task_sent", TaskSentToEthereumMessage{
+		MerkleRoot:        hexRoot(batchMerkleRoot),
+		TxHash:            txHash,
+		EffectiveGasPrice: effectiveGasPrice,
+		GasUsed:           gasUsed,
+		FeePaid:           feePaid,
+	})
+}
+
+func (s *stdoutTelemetrySink) FinishTrace(batchMerkleRoot [32]byte) {
+	s.write("finish_task_trace", TraceMessage{MerkleRoot: hexRoot(batchMerkleRoot)})
+}
+
+func (s *stdoutTelemetrySink) write(event string, message interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// the messages can always be encoded, and stdout failing isn't worth reporting anywhere
+	_ = s.encoder.Encode(stdoutTelemetryEvent{Time: time.Now(), Event: event, Message: message})
+}
+
+func hexRoot(batchMerkleRoot [32]byte) string {
+	return fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:]))
+}
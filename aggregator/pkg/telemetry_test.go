@@ -0,0 +1,135 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func TestTelemetryApiToken(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer aggregator-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"merkle_root":"0x01","trace_id":"1234","span_id":"5678"}`))
+	}))
+	defer server.Close()
+	serverAddress := strings.TrimPrefix(server.URL, "http://")
+
+	telemetry, err := NewTelemetryWithConfig(TelemetryConfig{ServerAddress: serverAddress, ApiToken: "aggregator-token"}, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	traceContext := telemetry.InitNewTrace([32]byte{1})
+	if traceContext.TraceId != "1234" || traceContext.SpanId != "5678" {
+		t.Errorf("Expected the authenticated trace to be started, got %+v", traceContext)
+	}
+
+	telemetry, err = NewTelemetryWithConfig(TelemetryConfig{ServerAddress: serverAddress, ApiToken: "other-token"}, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := telemetry.sink.(*httpTelemetrySink).sendTelemetryMessage("/api/quorumReached", QuorumReachedMessage{MerkleRoot: "0x01"}); err == nil {
+		t.Errorf("Expected a rejected token to fail")
+	}
+}
+
+func TestTelemetrySinkSelection(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	for _, test := range []struct {
+		config TelemetryConfig
+		sink   TelemetrySink
+	}{
+		{TelemetryConfig{ServerAddress: "localhost:4001"}, &httpTelemetrySink{}},
+		{TelemetryConfig{}, noopTelemetrySink{}},
+		{TelemetryConfig{Transport: NoopTelemetryTransport, ServerAddress: "localhost:4001"}, noopTelemetrySink{}},
+		{TelemetryConfig{Transport: StdoutTelemetryTransport}, &stdoutTelemetrySink{}},
+	} {
+		telemetry, err := NewTelemetryWithConfig(test.config, logger)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got, want := fmt.Sprintf("%T", telemetry.sink), fmt.Sprintf("%T", test.sink); got != want {
+			t.Errorf("Expected config %+v to use the %s sink, got %s", test.config, want, got)
+		}
+	}
+}
+
+func TestStdoutTelemetrySink(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	var out bytes.Buffer
+	telemetry, err := NewTelemetryWithSink(newStdoutTelemetrySink(&out), TelemetrySampling{}, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	telemetry.InitNewTrace([32]byte{1})
+	telemetry.LogQuorumReached([32]byte{1}, 2)
+	telemetry.LogTaskError([32]byte{1}, errors.New("respond to task failed"))
+
+	var events []string
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var event struct {
+			Event   string          `json:"event"`
+			Message json.RawMessage `json:"message"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Expected a JSON line, got %s: %v", scanner.Text(), err)
+		}
+		events = append(events, event.Event)
+	}
+	if strings.Join(events, ",") != "init_task_trace,quorum_reached,task_error" {
+		t.Errorf("Unexpected events %v", events)
+	}
+}
+
+func TestTelemetryAdminToggle(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	var out bytes.Buffer
+	telemetry, err := NewTelemetryWithSink(newStdoutTelemetrySink(&out), TelemetrySampling{}, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server := httptest.NewServer(telemetry.TelemetryAdminHandler())
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"enabled":false}`))
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || telemetry.Enabled() {
+		t.Fatalf("Expected the telemetry to be disabled, got status %d", res.StatusCode)
+	}
+	if traceContext := telemetry.InitNewTrace([32]byte{1}); traceContext.TraceId != "" || out.Len() != 0 {
+		t.Errorf("Expected nothing to be traced while disabled, got %s", out.String())
+	}
+
+	res, err = http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+	var status telemetryStatus
+	if err := json.NewDecoder(res.Body).Decode(&status); err != nil || status.Enabled {
+		t.Errorf("Expected the status to be disabled, got %+v and %v", status, err)
+	}
+
+	telemetry.SetEnabled(true)
+	telemetry.InitNewTrace([32]byte{2})
+	if out.Len() == 0 {
+		t.Errorf("Expected the traces to be sent once enabled again")
+	}
+}
@@ -0,0 +1,23 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// GetTaskTraceContext is called by operators via RPC to get the trace context of a task, so their
+// spans of the batch are attached to the aggregator span. The tasks are announced on-chain, so the
+// operators can't receive it along with them. Fails if the task isn't known or traced.
+func (agg *Aggregator) GetTaskTraceContext(batchIdentifierHash *[32]byte, reply *types.TaskTraceContext) error {
+	task, ok := agg.tasks.GetByIdentifierHash(*batchIdentifierHash)
+	if !ok {
+		return fmt.Errorf("task 0x%s not found", hex.EncodeToString(batchIdentifierHash[:]))
+	}
+	if task.TraceId == "" {
+		return fmt.Errorf("task 0x%s is not traced", hex.EncodeToString(batchIdentifierHash[:]))
+	}
+	*reply = types.TaskTraceContext{TraceId: task.TraceId, SpanId: task.SpanId}
+	return nil
+}
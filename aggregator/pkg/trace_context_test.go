@@ -0,0 +1,33 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+func TestGetTaskTraceContext(t *testing.T) {
+	tasks, _ := newTestTaskLifecycle(t, nil)
+	agg := &Aggregator{tasks: tasks}
+
+	index, err := tasks.Receive([32]byte{1}, BatchData{}, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var reply types.TaskTraceContext
+	if err := agg.GetTaskTraceContext(&[32]byte{1}, &reply); err == nil {
+		t.Errorf("Expected an error for a task not traced")
+	}
+	if err := agg.GetTaskTraceContext(&[32]byte{2}, &reply); err == nil {
+		t.Errorf("Expected an error for an unknown task")
+	}
+
+	traceContext := types.TaskTraceContext{TraceId: "0af7651916cd43dd8448eb211c80319c", SpanId: "b7ad6b7169203331"}
+	tasks.SetTraceContext(index, traceContext)
+	if err := agg.GetTaskTraceContext(&[32]byte{1}, &reply); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reply != traceContext {
+		t.Errorf("Expected trace context %+v, got %+v", traceContext, reply)
+	}
+}
@@ -50,6 +50,11 @@ func VerifyGroth16ProofBN254(proofBytes C.ListRef, pubInputBytes C.ListRef, veri
 	return verifyGroth16Proof(proofBytes, pubInputBytes, verificationKeyBytes, ecc.BN254)
 }
 
+//export VerifyGroth16ProofBLS12_381
+func VerifyGroth16ProofBLS12_381(proofBytes C.ListRef, pubInputBytes C.ListRef, verificationKeyBytes C.ListRef) bool {
+	return verifyGroth16Proof(proofBytes, pubInputBytes, verificationKeyBytes, ecc.BLS12_381)
+}
+
 // verifyPlonkProof contains the common proof verification logic.
 func verifyPlonkProof(proofBytesRef C.ListRef, pubInputBytesRef C.ListRef, verificationKeyBytesRef C.ListRef, curve ecc.ID) bool {
 	proofBytes := listRefToBytes(proofBytesRef)
@@ -116,6 +121,12 @@ func verifyGroth16Proof(proofBytesRef C.ListRef, pubInputBytesRef C.ListRef, ver
 		return false
 	}
 
+	// Must match the operator checks, see operator/pkg/operator.go
+	if curve == ecc.BLS12_381 && (proofReader.Len() != 0 || verificationKeyReader.Len() != 0) {
+		log.Printf("Groth16 proof or verifying key have trailing bytes, they are probably not encoded for %s", curve)
+		return false
+	}
+
 	err = groth16.Verify(proof, verificationKey, pubInput)
 	return err == nil
 }
@@ -0,0 +1,60 @@
+use binius_core::constraint_system::{self, channel::Boundary, ConstraintSystem, Proof};
+use binius_core::fiat_shamir::HasherChallenger;
+use binius_field::arch::OptimalUnderlier;
+use binius_field::tower::CanonicalTowerFamily;
+use binius_field::BinaryField128b;
+use binius_hash::compress::Groestl256ByteCompression;
+use binius_utils::serialization::{DeserializeBytes, SerializationMode};
+use groestl_crypto::Groestl256;
+use log::{debug, warn};
+
+const LOG_INV_RATE: usize = 1;
+const SECURITY_BITS: usize = 100;
+
+type F = BinaryField128b;
+
+pub fn verify_binius_proof(proof: &[u8], pub_input: &[u8], constraint_system: &[u8]) -> bool {
+    if proof.is_empty() || pub_input.is_empty() || constraint_system.is_empty() {
+        warn!("Binius input buffers zero size");
+        return false;
+    }
+
+    let mut constraint_system = constraint_system;
+    let Ok(constraint_system) = ConstraintSystem::<F>::deserialize(
+        &mut constraint_system,
+        SerializationMode::CanonicalTower,
+    ) else {
+        warn!("Failed to decode Binius constraint system");
+        return false;
+    };
+
+    let mut pub_input = pub_input;
+    let Ok(boundaries) =
+        Vec::<Boundary<F>>::deserialize(&mut pub_input, SerializationMode::CanonicalTower)
+    else {
+        warn!("Failed to decode Binius boundaries");
+        return false;
+    };
+
+    let proof = Proof {
+        transcript: proof.to_vec(),
+    };
+
+    debug!("Verifying Binius proof");
+    let res = constraint_system::verify::<
+        OptimalUnderlier,
+        CanonicalTowerFamily,
+        Groestl256,
+        Groestl256ByteCompression,
+        HasherChallenger<Groestl256>,
+    >(
+        &constraint_system,
+        LOG_INV_RATE,
+        SECURITY_BITS,
+        boundaries,
+        proof,
+    )
+    .is_ok();
+    debug!("Binius proof is valid: {}", res);
+    res
+}
@@ -0,0 +1,63 @@
+use circuit_definitions::boojum::cs::implementations::pow::NoPow;
+use circuit_definitions::boojum::field::PrimeField;
+use circuit_definitions::circuit_definitions::recursion_layer::{
+    ZkSyncRecursionLayerProof, ZkSyncRecursionLayerStorageType, ZkSyncRecursionLayerVerificationKey,
+};
+use log::{debug, warn};
+use zkevm_test_harness::prover_utils::verify_recursion_layer_proof_for_type;
+
+const FELT_SIZE: usize = 8;
+
+pub fn verify_boojum_proof(proof: &[u8], pub_input: &[u8], vk: &[u8]) -> bool {
+    if proof.is_empty() || vk.is_empty() || pub_input.len() % FELT_SIZE != 0 {
+        warn!("Boojum proof, public input or verification key have an invalid size");
+        return false;
+    }
+
+    let Ok(vk) = bincode::deserialize::<ZkSyncRecursionLayerVerificationKey>(vk) else {
+        warn!("Failed to decode Boojum verification key");
+        return false;
+    };
+
+    let Ok(proof) = bincode::deserialize::<ZkSyncRecursionLayerProof>(proof) else {
+        warn!("Failed to decode Boojum proof");
+        return false;
+    };
+
+    // Only recursion tip and scheduler proofs, which cover a whole batch, are accepted
+    let circuit_type = vk.numeric_circuit_type();
+    let Ok(storage_type) = ZkSyncRecursionLayerStorageType::try_from(circuit_type) else {
+        warn!("Unknown Boojum circuit type {}", circuit_type);
+        return false;
+    };
+    if !matches!(
+        storage_type,
+        ZkSyncRecursionLayerStorageType::RecursionTipCircuit
+            | ZkSyncRecursionLayerStorageType::SchedulerCircuit
+    ) || proof.numeric_circuit_type() != circuit_type
+    {
+        warn!("Unsupported Boojum circuit type {}", circuit_type);
+        return false;
+    }
+
+    let expected_public_inputs: Vec<u64> = pub_input
+        .chunks_exact(FELT_SIZE)
+        .map(|chunk| u64::from_le_bytes(chunk.try_into().unwrap()))
+        .collect();
+    let public_inputs: Vec<u64> = proof
+        .clone()
+        .into_inner()
+        .public_inputs
+        .iter()
+        .map(|input| input.as_u64_reduced())
+        .collect();
+    if public_inputs != expected_public_inputs {
+        debug!("Boojum public inputs don't match");
+        return false;
+    }
+
+    debug!("Verifying Boojum proof");
+    let res = verify_recursion_layer_proof_for_type::<NoPow>(storage_type, &proof, &vk);
+    debug!("Boojum proof is valid: {}", res);
+    res
+}
@@ -0,0 +1,61 @@
+use log::{debug, warn};
+use starknet_crypto::Felt;
+use swiftness::{parse, types::StarkProof, TransformTo};
+use swiftness_air::layout::recursive::Layout;
+
+const FELT_SIZE: usize = 32;
+// Cairo has no verification key, so nothing but the proof sets its config. Proofs below this
+// security level are rejected instead of trusting the one the proof declares
+const MIN_SECURITY_BITS: u64 = 96;
+
+fn read_public_input(bytes: &[u8]) -> Option<Vec<Felt>> {
+    bytes
+        .chunks_exact(FELT_SIZE)
+        .map(|chunk| {
+            let felt = Felt::from_bytes_be_slice(chunk);
+            // Only canonical encodings are accepted
+            (felt.to_bytes_be() == chunk).then_some(felt)
+        })
+        .collect()
+}
+
+pub fn verify_cairo_proof(proof: &[u8], pub_input: &[u8]) -> bool {
+    if proof.is_empty() || pub_input.is_empty() || pub_input.len() % FELT_SIZE != 0 {
+        warn!("Cairo proof or public input have an invalid size");
+        return false;
+    }
+
+    // The public input is the program hash followed by the program output
+    let Some(expected) = read_public_input(pub_input) else {
+        warn!("Cairo public input has non canonical felts");
+        return false;
+    };
+
+    let Ok(proof_json) = std::str::from_utf8(proof) else {
+        warn!("Cairo proof is not valid UTF-8");
+        return false;
+    };
+
+    let Ok(proof) = parse(proof_json.to_string()) else {
+        warn!("Failed to parse Cairo proof");
+        return false;
+    };
+    let proof: StarkProof = proof.transform_to();
+
+    debug!("Verifying Cairo proof");
+    let min_security_bits = Felt::from(MIN_SECURITY_BITS);
+    if proof.config.security_bits() < min_security_bits {
+        warn!("Cairo proof security is below {} bits", MIN_SECURITY_BITS);
+        return false;
+    }
+    let Ok((program_hash, output)) = proof.verify::<Layout>(min_security_bits) else {
+        debug!("Cairo proof is not valid");
+        return false;
+    };
+
+    let res = expected.len() == output.len() + 1
+        && expected[0] == program_hash
+        && expected[1..] == output[..];
+    debug!("Cairo proof is valid: {}", res);
+    res
+}
@@ -1,3 +1,6 @@
+use std::collections::HashMap;
+
+use aligned_sdk::core::types::ProvingSystemId;
 use ethers::{core::k256::ecdsa::SigningKey, signers::Wallet, types::Address};
 use serde::Deserialize;
 
@@ -35,6 +38,13 @@ impl NonPayingConfig {
     }
 }
 
+/// A verifier plugin compiled to WASM, only loaded if its keccak256 hash matches `hash`
+#[derive(Clone, Debug, Deserialize)]
+pub struct WasmPluginConfig {
+    pub path: String,
+    pub hash: String,
+}
+
 #[derive(Debug, Deserialize)]
 pub struct BatcherConfigFromYaml {
     #[serde(default = "default_aggregator_fee_percentage_multiplier")]
@@ -44,12 +54,24 @@ pub struct BatcherConfigFromYaml {
     pub block_interval: u64,
     pub transaction_wait_timeout: u64,
     pub max_proof_size: usize,
+    /// Optional per proving system max proof sizes, on top of `max_proof_size`
+    #[serde(default)]
+    pub max_proof_size_by_proving_system: HashMap<ProvingSystemId, usize>,
     pub max_batch_byte_size: usize,
     pub max_batch_proof_qty: usize,
     pub pre_verification_is_enabled: bool,
     pub metrics_port: u16,
     pub telemetry_ip_port_address: String,
+    /// Optional token authenticating the traces sent to the telemetry API
+    #[serde(default)]
+    pub telemetry_api_token: Option<String>,
     pub non_paying: Option<NonPayingConfigFromYaml>,
+    /// Optional verifier plugins, used to pre verify WasmPlugin proofs
+    #[serde(default)]
+    pub wasm_plugins: Vec<WasmPluginConfig>,
+    /// Compress batches with zstd before uploading them. Operators must support compressed batches
+    #[serde(default)]
+    pub zstd_compress_batches: bool,
 }
 
 #[derive(Debug, Deserialize)]
@@ -47,6 +47,9 @@ pub fn verify_gnark(
         ProvingSystemId::Groth16Bn254 => unsafe {
             VerifyGroth16ProofBN254(proof, public_input, verification_key)
         },
+        ProvingSystemId::Groth16Bls12_381 => unsafe {
+            VerifyGroth16ProofBLS12_381(proof, public_input, verification_key)
+        },
         _ => false,
     }
 }
@@ -67,4 +70,9 @@ extern "C" {
         public_input: ListRef,
         verification_key: ListRef,
     ) -> bool;
+    pub fn VerifyGroth16ProofBLS12_381(
+        proof: ListRef,
+        public_input: ListRef,
+        verification_key: ListRef,
+    ) -> bool;
 }
@@ -0,0 +1,186 @@
+use halo2_backend::plonk::verifier::verify_proof_single;
+use halo2_backend::plonk::VerifyingKey;
+use halo2_backend::poly::commitment::Params;
+use halo2_backend::poly::ipa::commitment::{IPACommitmentScheme, ParamsIPA};
+use halo2_backend::poly::ipa::multiopen::VerifierIPA;
+use halo2_backend::poly::ipa::strategy::SingleStrategy as IPASingleStrategy;
+use halo2_backend::poly::kzg::commitment::{KZGCommitmentScheme, ParamsVerifierKZG};
+use halo2_backend::poly::kzg::multiopen::VerifierSHPLONK;
+use halo2_backend::poly::kzg::strategy::SingleStrategy as KZGSingleStrategy;
+use halo2_backend::transcript::{Blake2bRead, Challenge255, TranscriptReadBuffer};
+use halo2_backend::SerdeFormat;
+use halo2_middleware::circuit::ConstraintSystemMid;
+use halo2curves::bn256::{Bn256, G1Affine};
+use halo2curves::ff::PrimeField;
+use halo2curves::pasta::EqAffine;
+use halo2curves::CurveAffine;
+use log::{debug, warn};
+
+// Params blob encoding, see the operator Halo2 bindings
+const PARAMS_VERSION: u8 = 1;
+const KZG: u8 = 0;
+const IPA: u8 = 1;
+const BN256: u8 = 0;
+const PASTA: u8 = 1;
+const MAX_K: u8 = 26;
+
+struct Halo2Params<'a> {
+    commitment_scheme: u8,
+    curve: u8,
+    k: u32,
+    constraint_system: &'a [u8],
+    verifying_key: &'a [u8],
+    verifier_params: &'a [u8],
+}
+
+fn read_length_prefixed(buffer: &[u8]) -> Option<(&[u8], &[u8])> {
+    let length = u32::from_le_bytes(buffer.get(..4)?.try_into().ok()?) as usize;
+    let buffer = &buffer[4..];
+    if length == 0 || length > buffer.len() {
+        return None;
+    }
+    Some(buffer.split_at(length))
+}
+
+fn parse_params(blob: &[u8]) -> Option<Halo2Params> {
+    let [version, commitment_scheme, curve, k] = *blob.get(..4)? else {
+        return None;
+    };
+    if version != PARAMS_VERSION || k == 0 || k > MAX_K {
+        return None;
+    }
+    match (commitment_scheme, curve) {
+        (KZG, BN256) | (IPA, BN256) | (IPA, PASTA) => {}
+        _ => return None,
+    }
+
+    let (constraint_system, rest) = read_length_prefixed(&blob[4..])?;
+    let (verifying_key, verifier_params) = read_length_prefixed(rest)?;
+    if verifier_params.is_empty() {
+        return None;
+    }
+
+    Some(Halo2Params {
+        commitment_scheme,
+        curve,
+        k: k as u32,
+        constraint_system,
+        verifying_key,
+        verifier_params,
+    })
+}
+
+/// Instances are encoded as the number of columns, and for each column its length
+/// followed by its field elements, lengths being 4 bytes little endian integers.
+fn read_instances<F: PrimeField<Repr = [u8; 32]>>(mut bytes: &[u8]) -> Option<Vec<Vec<F>>> {
+    let mut read_u32 = |bytes: &mut &[u8]| -> Option<usize> {
+        let value = u32::from_le_bytes(bytes.get(..4)?.try_into().ok()?);
+        *bytes = &bytes[4..];
+        Some(value as usize)
+    };
+
+    let num_columns = read_u32(&mut bytes)?;
+    let mut instances = Vec::new();
+    for _ in 0..num_columns {
+        let column_len = read_u32(&mut bytes)?;
+        if column_len.checked_mul(32)? > bytes.len() {
+            return None;
+        }
+        let mut column = Vec::with_capacity(column_len);
+        for _ in 0..column_len {
+            let repr: [u8; 32] = bytes[..32].try_into().ok()?;
+            column.push(Option::from(F::from_repr(repr))?);
+            bytes = &bytes[32..];
+        }
+        instances.push(column);
+    }
+
+    bytes.is_empty().then_some(instances)
+}
+
+fn read_verifying_key<C: CurveAffine>(params: &Halo2Params) -> Option<VerifyingKey<C>>
+where
+    C::Scalar: serde::de::DeserializeOwned,
+{
+    let constraint_system: ConstraintSystemMid<C::Scalar> =
+        bincode::deserialize(params.constraint_system).ok()?;
+    VerifyingKey::<C>::read(
+        &mut &params.verifying_key[..],
+        SerdeFormat::RawBytes,
+        constraint_system.into(),
+    )
+    .ok()
+}
+
+fn verify_kzg_bn256(params: &Halo2Params, proof: &[u8], pub_input: &[u8]) -> Option<bool> {
+    let verifier_params = ParamsVerifierKZG::<Bn256>::read_custom(
+        &mut &params.verifier_params[..],
+        SerdeFormat::RawBytes,
+    )
+    .ok()?;
+    if verifier_params.k() != params.k {
+        return None;
+    }
+    let vk = read_verifying_key::<G1Affine>(params)?;
+    let instances = read_instances(pub_input)?;
+
+    let mut transcript = Blake2bRead::<_, G1Affine, Challenge255<_>>::init(proof);
+    Some(
+        verify_proof_single::<KZGCommitmentScheme<Bn256>, VerifierSHPLONK<Bn256>, _, _, _>(
+            &verifier_params,
+            &vk,
+            KZGSingleStrategy::new(&verifier_params),
+            instances,
+            &mut transcript,
+        )
+        .is_ok(),
+    )
+}
+
+fn verify_ipa<C: CurveAffine>(params: &Halo2Params, proof: &[u8], pub_input: &[u8]) -> Option<bool>
+where
+    C::Scalar: PrimeField<Repr = [u8; 32]> + serde::de::DeserializeOwned,
+{
+    let verifier_params = ParamsIPA::<C>::read(&mut &params.verifier_params[..]).ok()?;
+    if verifier_params.k() != params.k {
+        return None;
+    }
+    let vk = read_verifying_key::<C>(params)?;
+    let instances = read_instances(pub_input)?;
+
+    let mut transcript = Blake2bRead::<_, C, Challenge255<_>>::init(proof);
+    Some(
+        verify_proof_single::<IPACommitmentScheme<C>, VerifierIPA<C>, _, _, _>(
+            &verifier_params,
+            &vk,
+            IPASingleStrategy::new(&verifier_params),
+            instances,
+            &mut transcript,
+        )
+        .is_ok(),
+    )
+}
+
+pub fn verify_halo2_proof(proof: &[u8], pub_input: &[u8], params: &[u8]) -> bool {
+    if proof.is_empty() || pub_input.is_empty() || params.is_empty() {
+        warn!("Halo2 input buffers zero size");
+        return false;
+    }
+
+    let Some(params) = parse_params(params) else {
+        warn!("Invalid Halo2 params");
+        return false;
+    };
+
+    debug!("Verifying Halo2 proof");
+    let result = match (params.commitment_scheme, params.curve) {
+        (KZG, BN256) => verify_kzg_bn256(&params, proof, pub_input),
+        (IPA, BN256) => verify_ipa::<G1Affine>(&params, proof, pub_input),
+        (IPA, PASTA) => verify_ipa::<EqAffine>(&params, proof, pub_input),
+        _ => None,
+    };
+
+    let res = result.unwrap_or(false);
+    debug!("Halo2 proof is valid: {}", res);
+    res
+}
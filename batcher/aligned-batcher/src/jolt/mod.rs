@@ -0,0 +1,62 @@
+use jolt_core::jolt::vm::rv32i_vm::RV32IJoltVM;
+use jolt_core::jolt::vm::Jolt;
+use jolt_sdk::host_utils::JoltHyperKZGProof;
+use jolt_sdk::{JoltDevice, Serializable};
+use log::{debug, warn};
+
+const MAX_BYTECODE_SIZE: usize = 1 << 20;
+const MAX_MEMORY_SIZE: usize = 1 << 24;
+const MAX_TRACE_LENGTH: usize = 1 << 24;
+
+// ELF header of a 32 bit RISC-V executable: magic, ELFCLASS32 and EM_RISCV
+const ELF_MAGIC: [u8; 4] = [0x7f, b'E', b'L', b'F'];
+const ELF_CLASS_32: u8 = 1;
+const EM_RISCV: u16 = 0xF3;
+
+pub fn verify_jolt_proof(proof: &[u8], elf: &[u8], program_io: &[u8]) -> bool {
+    if proof.is_empty() || elf.is_empty() || program_io.is_empty() {
+        warn!("Jolt input buffers zero size");
+        return false;
+    }
+
+    if elf.len() < 20
+        || elf[..4] != ELF_MAGIC
+        || elf[4] != ELF_CLASS_32
+        || u16::from_le_bytes([elf[18], elf[19]]) != EM_RISCV
+    {
+        warn!("Jolt ELF is not a 32 bit RISC-V executable");
+        return false;
+    }
+
+    let Ok(program_io) = JoltDevice::deserialize_from_bytes(program_io) else {
+        warn!("Failed to decode Jolt program io");
+        return false;
+    };
+
+    let Ok(proof) = JoltHyperKZGProof::deserialize_from_bytes(proof) else {
+        warn!("Failed to decode Jolt proof");
+        return false;
+    };
+
+    debug!("Verifying Jolt proof");
+    let (bytecode, memory_init) = tracer::decode(elf);
+    let preprocessing = RV32IJoltVM::verifier_preprocess(
+        bytecode,
+        program_io.memory_layout.clone(),
+        memory_init,
+        MAX_BYTECODE_SIZE,
+        MAX_MEMORY_SIZE,
+        MAX_TRACE_LENGTH,
+    );
+
+    let res = RV32IJoltVM::verify(
+        preprocessing,
+        proof.proof,
+        proof.commitments,
+        program_io,
+        None,
+    )
+    .is_ok();
+    debug!("Jolt proof is valid: {}", res);
+    res
+}
@@ -0,0 +1,143 @@
+use std::str::FromStr;
+use std::sync::Arc;
+
+use ark_ff::PrimeField;
+use groupmap::GroupMap;
+use kimchi::linearization::expr_linearization;
+use kimchi::proof::ProverProof;
+use kimchi::verifier::verify;
+use kimchi::verifier_index::VerifierIndex;
+use log::{debug, warn};
+use mina_curves::pasta::{Fp, Vesta, VestaParameters};
+use mina_poseidon::constants::PlonkSpongeConstantsKimchi;
+use mina_poseidon::sponge::{DefaultFqSponge, DefaultFrSponge};
+use poly_commitment::commitment::CommitmentCurve;
+use poly_commitment::evaluation_proof::OpeningProof;
+use poly_commitment::srs::SRS;
+use serde::Deserialize;
+
+type BaseSponge = DefaultFqSponge<VestaParameters, PlonkSpongeConstantsKimchi>;
+type ScalarSponge = DefaultFrSponge<Fp, PlonkSpongeConstantsKimchi>;
+
+const FIELD_ELEMENT_SIZE: usize = 32;
+
+/// Public input as exported by o1js with `proof.toJSON()`
+#[derive(Deserialize)]
+#[serde(rename_all = "camelCase", deny_unknown_fields)]
+struct MinaJsonPublicInput {
+    public_input: Vec<String>,
+    public_output: Vec<String>,
+}
+
+/// Decodes the public input, either as 32 bytes little endian field elements or as the o1js JSON,
+/// in the same way the operator does (see `NormalizeMinaPublicInput` in `core/types`).
+fn read_public_input(bytes: &[u8]) -> Option<Vec<Fp>> {
+    // Only JSON whitespace is skipped, as the operator does
+    let first = bytes.iter().find(|byte| !b" \t\n\r".contains(byte));
+    if first == Some(&b'{') {
+        let json: MinaJsonPublicInput = serde_json::from_slice(bytes).ok()?;
+        return json
+            .public_input
+            .iter()
+            .chain(json.public_output.iter())
+            .map(|element| {
+                // Fp::from_str reduces the element, so out of field values are rejected explicitly
+                let value = Fp::from_str(element).ok()?;
+                (value.into_bigint().to_string() == *element).then_some(value)
+            })
+            .collect();
+    }
+
+    if bytes.len() % FIELD_ELEMENT_SIZE != 0 {
+        return None;
+    }
+    bytes
+        .chunks_exact(FIELD_ELEMENT_SIZE)
+        .map(|chunk| {
+            let element = Fp::from_le_bytes_mod_order(chunk);
+            (element.into_bigint().to_bytes_le() == chunk).then_some(element)
+        })
+        .collect()
+}
+
+fn read_verifier_index(bytes: &[u8]) -> Option<VerifierIndex<Vesta, OpeningProof<Vesta>>> {
+    let mut verifier_index: VerifierIndex<Vesta, OpeningProof<Vesta>> =
+        rmp_serde::from_slice(bytes).ok()?;
+
+    let mut srs = SRS::<Vesta>::create(verifier_index.max_poly_size);
+    srs.add_lagrange_basis(verifier_index.domain);
+    verifier_index.srs = Arc::new(srs);
+
+    let (linearization, powers_of_alpha) =
+        expr_linearization(Some(&verifier_index.feature_flags), true);
+    verifier_index.linearization = linearization;
+    verifier_index.powers_of_alpha = powers_of_alpha;
+
+    Some(verifier_index)
+}
+
+pub fn verify_kimchi_proof(proof: &[u8], pub_input: &[u8], vk: &[u8]) -> bool {
+    if proof.is_empty() || pub_input.is_empty() || vk.is_empty() {
+        warn!("Kimchi input buffers zero size");
+        return false;
+    }
+
+    let Some(public_input) = read_public_input(pub_input) else {
+        warn!("Failed to decode Kimchi public input");
+        return false;
+    };
+
+    let Some(verifier_index) = read_verifier_index(vk) else {
+        warn!("Failed to decode Kimchi verifier index");
+        return false;
+    };
+
+    let Ok(proof) = rmp_serde::from_slice::<ProverProof<Vesta, OpeningProof<Vesta>>>(proof) else {
+        warn!("Failed to decode Kimchi proof");
+        return false;
+    };
+
+    debug!("Verifying Kimchi proof");
+    let group_map = <Vesta as CommitmentCurve>::Map::setup();
+    let res = verify::<Vesta, BaseSponge, ScalarSponge, OpeningProof<Vesta>>(
+        &group_map,
+        &verifier_index,
+        &proof,
+        &public_input,
+    )
+    .is_ok();
+    debug!("Kimchi proof is valid: {}", res);
+    res
+}
+
+#[cfg(test)]
+mod test {
+    use super::read_public_input;
+    use ark_ff::{BigInteger, PrimeField};
+    use serde::Deserialize;
+
+    /// Cases shared with the operator tests of `NormalizeMinaPublicInput`
+    const PUBLIC_INPUTS: &str = include_str!("../../../../scripts/test_files/mina/public_inputs.json");
+
+    #[derive(Deserialize)]
+    struct PublicInputCase {
+        name: String,
+        input: String,
+        expected: Option<String>,
+    }
+
+    #[test]
+    fn read_public_input_decodes_like_the_operator() {
+        let cases: Vec<PublicInputCase> = serde_json::from_str(PUBLIC_INPUTS).unwrap();
+        for case in cases {
+            let encoded = read_public_input(case.input.as_bytes()).map(|elements| {
+                let bytes: Vec<u8> = elements
+                    .iter()
+                    .flat_map(|element| element.into_bigint().to_bytes_le())
+                    .collect();
+                hex::encode(bytes)
+            });
+            assert_eq!(encoded, case.expected, "case: {}", case.name);
+        }
+    }
+}
@@ -52,21 +52,36 @@ use types::errors::{BatcherError, TransactionSendError};
 
 use crate::config::{ConfigFromYaml, ContractDeploymentOutput};
 use crate::telemetry::sender::TelemetrySender;
+use crate::wasm_plugin::WasmPlugins;
 
+pub mod binius;
+pub mod boojum;
+pub mod cairo;
 mod config;
 mod connection;
 mod eth;
 pub mod gnark;
+pub mod halo2;
+pub mod jolt;
+pub mod kimchi;
 pub mod metrics;
+pub mod miden;
+pub mod nova;
+pub mod plonky2;
 pub mod retry;
 pub mod risc_zero;
 pub mod s3;
 pub mod sp1;
 pub mod telemetry;
 pub mod types;
+pub mod valida;
+pub mod wasm_plugin;
 mod zk_utils;
 
 pub const LISTEN_NEW_BLOCKS_MAX_TIMES: usize = usize::MAX;
+/// zstd level used to compress batches before uploading them, a good trade off between
+/// compression ratio and speed for CBOR encoded proofs.
+pub const BATCH_ZSTD_COMPRESSION_LEVEL: i32 = 3;
 
 pub struct Batcher {
     s3_client: S3Client,
@@ -85,10 +100,13 @@ pub struct Batcher {
     min_block_interval: u64,
     transaction_wait_timeout: u64,
     max_proof_size: usize,
+    max_proof_size_by_proving_system: HashMap<ProvingSystemId, usize>,
     max_batch_byte_size: usize,
     max_batch_proof_qty: usize,
     last_uploaded_batch_block: Mutex<u64>,
     pre_verification_is_enabled: bool,
+    wasm_plugins: Arc<WasmPlugins>,
+    zstd_compress_batches: bool,
     non_paying_config: Option<NonPayingConfig>,
     posting_batch: Mutex<bool>,
     disabled_verifiers: Mutex<U256>,
@@ -239,10 +257,19 @@ impl Batcher {
         }
         .expect("Failed to get disabled verifiers");
 
-        let telemetry = TelemetrySender::new(format!(
-            "http://{}",
-            config.batcher.telemetry_ip_port_address
-        ));
+        let wasm_plugins = WasmPlugins::load(&config.batcher.wasm_plugins)
+            .expect("Failed to load WASM verifier plugins");
+        if !wasm_plugins.is_empty() {
+            info!(
+                "Loaded {} WASM verifier plugins",
+                config.batcher.wasm_plugins.len()
+            );
+        }
+
+        let telemetry = TelemetrySender::new(
+            format!("http://{}", config.batcher.telemetry_ip_port_address),
+            config.batcher.telemetry_api_token.clone(),
+        );
 
         Self {
             s3_client,
@@ -260,10 +287,13 @@ impl Batcher {
             min_block_interval: config.batcher.block_interval,
             transaction_wait_timeout: config.batcher.transaction_wait_timeout,
             max_proof_size: config.batcher.max_proof_size,
+            max_proof_size_by_proving_system: config.batcher.max_proof_size_by_proving_system,
             max_batch_byte_size: config.batcher.max_batch_byte_size,
             max_batch_proof_qty: config.batcher.max_batch_proof_qty,
             last_uploaded_batch_block: Mutex::new(last_uploaded_batch_block),
             pre_verification_is_enabled: config.batcher.pre_verification_is_enabled,
+            wasm_plugins: Arc::new(wasm_plugins),
+            zstd_compress_batches: config.batcher.zstd_compress_batches,
             non_paying_config,
             aggregator_fee_percentage_multiplier: config
                 .batcher
@@ -606,7 +636,11 @@ impl Batcher {
         info!("Message signature verified");
 
         let proof_size = client_msg.verification_data.verification_data.proof.len();
-        if proof_size > self.max_proof_size {
+        let proving_system = client_msg
+            .verification_data
+            .verification_data
+            .proving_system;
+        if proof_size > self.max_proof_size_for(proving_system) {
             error!("Proof size exceeds the maximum allowed size.");
             send_message(
                 ws_conn_sink.clone(),
@@ -644,7 +678,7 @@ impl Batcher {
                 return Ok(());
             }
 
-            if !zk_utils::verify(verification_data).await {
+            if !zk_utils::verify(verification_data, self.wasm_plugins.clone()).await {
                 error!("Invalid proof detected. Verification failed");
                 send_message(
                     ws_conn_sink.clone(),
@@ -854,6 +888,15 @@ impl Batcher {
         zk_utils::is_verifier_disabled(*disabled_verifiers, verifier)
     }
 
+    /// Returns the max proof size for the given proving system, which can't be bigger than the global max proof size.
+    fn max_proof_size_for(&self, proving_system: ProvingSystemId) -> usize {
+        self.max_proof_size_by_proving_system
+            .get(&proving_system)
+            .map_or(self.max_proof_size, |max_size| {
+                (*max_size).min(self.max_proof_size)
+            })
+    }
+
     // Verifies user has enough balance for paying all his proofs in the current batch.
     fn verify_user_has_enough_balance(
         &self,
@@ -1481,8 +1524,36 @@ impl Batcher {
             .gas_price_used_on_latest_batch
             .set(gas_price.as_u64() as i64);
 
+        // Operators check each proof against its leaf while downloading the batch, so corrupted
+        // batches are detected early. It's optional for them, so a failed upload isn't fatal
+        let leaves_file_name = batch_merkle_root_hex.clone() + ".leaves";
+        let leaves_bytes: Vec<u8> = leaves.concat();
+        if let Err(e) = self
+            .upload_batch_to_s3(&leaves_bytes, &leaves_file_name)
+            .await
+        {
+            warn!("Failed to upload batch leaves to S3: {:?}", e);
+        }
+
+        // Compression doesn't change the batch merkle root, which is computed over the proof commitments,
+        // nor its size limits, which apply to the uncompressed batch. Operators detect zstd batches
+        // by their magic number, so the file name is kept.
+        let compressed_batch_bytes;
+        let upload_bytes = if self.zstd_compress_batches {
+            compressed_batch_bytes = zstd::encode_all(batch_bytes, BATCH_ZSTD_COMPRESSION_LEVEL)
+                .map_err(|e| BatcherError::BatchUploadError(e.to_string()))?;
+            info!(
+                "Batch compressed with zstd: {} bytes, {} bytes uncompressed",
+                compressed_batch_bytes.len(),
+                batch_bytes.len()
+            );
+            compressed_batch_bytes.as_slice()
+        } else {
+            batch_bytes
+        };
+
         info!("Uploading batch to S3...");
-        self.upload_batch_to_s3(batch_bytes, &file_name).await?;
+        self.upload_batch_to_s3(upload_bytes, &file_name).await?;
         if let Err(e) = self
             .telemetry
             .task_uploaded_to_s3(&batch_merkle_root_hex)
@@ -1491,12 +1562,24 @@ impl Batcher {
             warn!("Failed to send task status to telemetry: {:?}", e);
         };
         info!("Batch sent to S3 with name: {}", file_name);
+        let mut proofs_by_proving_system: HashMap<String, usize> = HashMap::new();
+        for entry in finalized_batch {
+            let proving_system = entry
+                .nonced_verification_data
+                .verification_data
+                .proving_system;
+            *proofs_by_proving_system
+                .entry(proving_system.to_string())
+                .or_default() += 1;
+        }
         if let Err(e) = self
             .telemetry
             .task_created(
                 &hex::encode(batch_merkle_root),
                 ethers::utils::format_ether(fee_per_proof),
                 num_proofs_in_batch,
+                batch_bytes.len(),
+                proofs_by_proving_system,
             )
             .await
         {
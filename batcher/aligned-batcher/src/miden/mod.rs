@@ -0,0 +1,64 @@
+use log::{debug, warn};
+use miden_core::crypto::hash::RpoDigest;
+use miden_core::Felt;
+use miden_verifier::{verify, ExecutionProof, Kernel, ProgramInfo, StackInputs, StackOutputs};
+
+const FELT_SIZE: usize = 8;
+const PROGRAM_HASH_SIZE: usize = 4 * FELT_SIZE;
+
+fn felts_from_bytes(bytes: &[u8]) -> Option<Vec<Felt>> {
+    bytes
+        .chunks_exact(FELT_SIZE)
+        .map(|chunk| {
+            let value = u64::from_le_bytes(chunk.try_into().ok()?);
+            (value < Felt::MODULUS).then(|| Felt::new(value))
+        })
+        .collect()
+}
+
+/// The public input is the number of stack inputs as a little endian u32,
+/// followed by the stack inputs and outputs as little endian field elements.
+fn decode_public_input(
+    program_hash: &[u8],
+    pub_input: &[u8],
+) -> Option<(ProgramInfo, StackInputs, StackOutputs)> {
+    if program_hash.len() != PROGRAM_HASH_SIZE {
+        return None;
+    }
+    let program_hash: [Felt; 4] = felts_from_bytes(program_hash)?.try_into().ok()?;
+    let program_info = ProgramInfo::new(RpoDigest::new(program_hash), Kernel::default());
+
+    if pub_input.len() < 4 || (pub_input.len() - 4) % FELT_SIZE != 0 {
+        return None;
+    }
+    let num_inputs = u32::from_le_bytes(pub_input[..4].try_into().ok()?) as usize;
+    let felts = felts_from_bytes(&pub_input[4..])?;
+    if num_inputs > felts.len() {
+        return None;
+    }
+    let (inputs, outputs) = felts.split_at(num_inputs);
+
+    let stack_inputs = StackInputs::new(inputs.to_vec()).ok()?;
+    let stack_outputs = StackOutputs::new(outputs.to_vec()).ok()?;
+
+    Some((program_info, stack_inputs, stack_outputs))
+}
+
+pub fn verify_miden_proof(proof: &[u8], program_hash: &[u8], pub_input: &[u8]) -> bool {
+    let Some((program_info, stack_inputs, stack_outputs)) =
+        decode_public_input(program_hash, pub_input)
+    else {
+        warn!("Failed to decode Miden program hash and public input");
+        return false;
+    };
+
+    let Ok(proof) = ExecutionProof::from_bytes(proof) else {
+        warn!("Failed to decode Miden proof");
+        return false;
+    };
+
+    debug!("Verifying Miden proof");
+    let res = verify(program_info, stack_inputs, stack_outputs, proof).is_ok();
+    debug!("Miden proof is valid: {}", res);
+    res
+}
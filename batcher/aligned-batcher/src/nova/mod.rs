@@ -0,0 +1,60 @@
+use log::{debug, warn};
+use nova_snark::provider::{hyperkzg, ipa_pc, Bn256EngineKZG, GrumpkinEngine};
+use nova_snark::spartan::snark::RelaxedR1CSSNARK;
+use nova_snark::traits::circuit::TrivialCircuit;
+use nova_snark::traits::Engine;
+use nova_snark::{CompressedSNARK, VerifierKey};
+use serde::Deserialize;
+
+type E1 = Bn256EngineKZG;
+type E2 = GrumpkinEngine;
+type S1 = RelaxedR1CSSNARK<E1, hyperkzg::EvaluationEngine<E1>>;
+type S2 = RelaxedR1CSSNARK<E2, ipa_pc::EvaluationEngine<E2>>;
+type C1 = TrivialCircuit<<E1 as Engine>::Scalar>;
+type C2 = TrivialCircuit<<E2 as Engine>::Scalar>;
+
+#[derive(Deserialize)]
+struct NovaPublicInput {
+    num_steps: usize,
+    z0_primary: Vec<<E1 as Engine>::Scalar>,
+    z0_secondary: Vec<<E2 as Engine>::Scalar>,
+    zn_primary: Vec<<E1 as Engine>::Scalar>,
+    zn_secondary: Vec<<E2 as Engine>::Scalar>,
+}
+
+pub fn verify_nova_proof(proof: &[u8], pub_input: &[u8], vk: &[u8]) -> bool {
+    if proof.is_empty() || pub_input.is_empty() || vk.is_empty() {
+        warn!("Nova input buffers zero size");
+        return false;
+    }
+
+    let Ok(vk) = bincode::deserialize::<VerifierKey<E1, E2, C1, C2, S1, S2>>(vk) else {
+        warn!("Failed to decode Nova verifier key");
+        return false;
+    };
+
+    let Ok(pub_input) = bincode::deserialize::<NovaPublicInput>(pub_input) else {
+        warn!("Failed to decode Nova public input");
+        return false;
+    };
+
+    let Ok(proof) = bincode::deserialize::<CompressedSNARK<E1, E2, C1, C2, S1, S2>>(proof) else {
+        warn!("Failed to decode Nova proof");
+        return false;
+    };
+
+    debug!("Verifying Nova proof");
+    let res = match proof.verify(
+        &vk,
+        pub_input.num_steps,
+        &pub_input.z0_primary,
+        &pub_input.z0_secondary,
+    ) {
+        Ok((zn_primary, zn_secondary)) => {
+            zn_primary == pub_input.zn_primary && zn_secondary == pub_input.zn_secondary
+        }
+        Err(_) => false,
+    };
+    debug!("Nova proof is valid: {}", res);
+    res
+}
@@ -0,0 +1,36 @@
+use log::{debug, error, warn};
+use plonky2::plonk::circuit_data::VerifierCircuitData;
+use plonky2::plonk::config::{GenericConfig, PoseidonGoldilocksConfig};
+use plonky2::plonk::proof::ProofWithPublicInputs;
+use plonky2::util::serialization::DefaultGateSerializer;
+
+const D: usize = 2;
+type C = PoseidonGoldilocksConfig;
+type F = <C as GenericConfig<D>>::F;
+
+pub fn verify_plonky2_proof(proof: &[u8], verifier_data: &[u8]) -> bool {
+    if proof.is_empty() || verifier_data.is_empty() {
+        error!("Plonky2 input buffers zero size");
+        return false;
+    }
+
+    debug!("Verifying Plonky2 proof");
+    let Ok(verifier_data) =
+        VerifierCircuitData::<F, C, D>::from_bytes(verifier_data.to_vec(), &DefaultGateSerializer)
+    else {
+        warn!("Failed to decode Plonky2 verifier data");
+        return false;
+    };
+
+    if let Ok(proof) =
+        ProofWithPublicInputs::<F, C, D>::from_bytes(proof.to_vec(), &verifier_data.common)
+    {
+        let res = verifier_data.verify(proof).is_ok();
+        debug!("Plonky2 proof is valid: {}", res);
+        return res;
+    }
+
+    warn!("Failed to decode Plonky2 proof");
+
+    false
+}
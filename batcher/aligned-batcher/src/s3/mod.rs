@@ -3,6 +3,7 @@ use aws_config::BehaviorVersion;
 use aws_sdk_s3::error::SdkError;
 use aws_sdk_s3::operation::put_object::{PutObjectError, PutObjectOutput};
 use aws_sdk_s3::primitives::ByteStream;
+use aws_sdk_s3::types::ChecksumAlgorithm;
 use aws_sdk_s3::Client;
 use log::info;
 
@@ -36,6 +37,8 @@ pub async fn upload_object(
         .bucket(bucket_name)
         .key(key)
         .body(body)
+        // Operators check the downloaded batches against this checksum
+        .checksum_algorithm(ChecksumAlgorithm::Sha256)
         .send()
         .await
 }
@@ -1,3 +1,5 @@
+use std::collections::HashMap;
+
 use ethers::types::H256;
 
 #[derive(Debug, serde::Serialize)]
@@ -17,6 +19,8 @@ pub struct TraceMessageTaskStarted {
     merkle_root: String,
     fee_per_proof: String,
     num_proofs_in_batch: usize,
+    batch_size_bytes: usize,
+    proofs_by_proving_system: HashMap<String, usize>,
 }
 
 #[derive(Debug, serde::Serialize)]
@@ -39,26 +43,40 @@ pub struct TraceMessageTaskError {
 
 pub struct TelemetrySender {
     base_url: String,
+    api_token: Option<String>,
     client: reqwest::Client,
 }
 
 impl TelemetrySender {
-    pub fn new(base_url: String) -> Self {
+    pub fn new(base_url: String, api_token: Option<String>) -> Self {
         let client = reqwest::Client::new();
-        Self { base_url, client }
+        Self {
+            base_url,
+            api_token,
+            client,
+        }
     }
 
     pub fn get_full_url(&self, path: &str) -> String {
         format!("{}/api/{}", self.base_url, path)
     }
 
+    /// Builds a POST request to the telemetry API, authenticated with the API token if set
+    fn post(&self, url: &str) -> reqwest::RequestBuilder {
+        let request = self.client.post(url);
+        match &self.api_token {
+            Some(api_token) => request.bearer_auth(api_token),
+            None => request,
+        }
+    }
+
     pub async fn init_task_trace(&self, batch_merkle_root: &str) -> Result<(), reqwest::Error> {
         let url = self.get_full_url("initBatcherTaskTrace");
         let formatted_merkle_root = format!("0x{}", batch_merkle_root);
         let task = TraceMessageTask {
             merkle_root: formatted_merkle_root,
         };
-        self.client.post(&url).json(&task).send().await?;
+        self.post(&url).json(&task).send().await?;
         Ok(())
     }
 
@@ -73,7 +91,7 @@ impl TelemetrySender {
             merkle_root: formatted_merkle_root,
             tx_hash,
         };
-        self.client.post(&url).json(&task).send().await?;
+        self.post(&url).json(&task).send().await?;
         Ok(())
     }
 
@@ -82,6 +100,8 @@ impl TelemetrySender {
         batch_merkle_root: &str,
         fee_per_proof: String,
         num_proofs_in_batch: usize,
+        batch_size_bytes: usize,
+        proofs_by_proving_system: HashMap<String, usize>,
     ) -> Result<(), reqwest::Error> {
         let url = self.get_full_url("batcherTaskStarted");
         let formatted_merkle_root = format!("0x{}", batch_merkle_root);
@@ -89,8 +109,10 @@ impl TelemetrySender {
             merkle_root: formatted_merkle_root,
             fee_per_proof,
             num_proofs_in_batch,
+            batch_size_bytes,
+            proofs_by_proving_system,
         };
-        self.client.post(&url).json(&task).send().await?;
+        self.post(&url).json(&task).send().await?;
         Ok(())
     }
 
@@ -100,7 +122,7 @@ impl TelemetrySender {
         let task = TraceMessageTask {
             merkle_root: formatted_merkle_root,
         };
-        self.client.post(&url).json(&task).send().await?;
+        self.post(&url).json(&task).send().await?;
         Ok(())
     }
 
@@ -115,7 +137,7 @@ impl TelemetrySender {
             merkle_root: formatted_merkle_root,
             error: reason.to_string(),
         };
-        self.client.post(&url).json(&task).send().await?;
+        self.post(&url).json(&task).send().await?;
         Ok(())
     }
 }
@@ -0,0 +1,41 @@
+mod config;
+
+use config::{stark_config, Val, ValidaStarkConfig};
+use log::{debug, warn};
+use valida_basic::BasicMachine;
+use valida_machine::{Machine, MachineProof};
+use valida_program::ProgramROM;
+
+// An opcode and five operands of 4 bytes each
+const INSTRUCTION_SIZE: usize = 24;
+
+pub fn verify_valida_proof(proof: &[u8], program: &[u8], pub_input: &[u8]) -> bool {
+    if proof.is_empty() || program.is_empty() || pub_input.is_empty() {
+        warn!("Valida input buffers zero size");
+        return false;
+    }
+
+    if program.len() % INSTRUCTION_SIZE != 0 {
+        warn!("Valida program is not a sequence of instructions");
+        return false;
+    }
+
+    let Ok(proof) = ciborium::from_reader::<MachineProof<ValidaStarkConfig>, _>(proof) else {
+        warn!("Failed to decode Valida proof");
+        return false;
+    };
+
+    debug!("Verifying Valida proof");
+    let mut machine = BasicMachine::<Val>::default();
+    let rom = ProgramROM::from_machine_code(program);
+    machine.program_mut().set_program_rom(&rom);
+    machine.output_mut().values = pub_input
+        .iter()
+        .enumerate()
+        .map(|(i, byte)| (i as u32, *byte))
+        .collect();
+
+    let res = machine.verify(&stark_config(), &proof).is_ok();
+    debug!("Valida proof is valid: {}", res);
+    res
+}
@@ -0,0 +1,149 @@
+use std::collections::HashMap;
+
+use log::{debug, warn};
+use sha3::{Digest, Keccak256};
+use wasmtime::{Config, Engine, Instance, Module, Store};
+
+use crate::config::WasmPluginConfig;
+
+/// Version of the ABI plugins must declare through their `aligned_abi_version` export
+pub const WASM_PLUGIN_ABI_VERSION: i32 = 1;
+
+/// Size of the plugin module hash the verification key of WasmPlugin proofs starts with
+pub const WASM_PLUGIN_HASH_SIZE: usize = 32;
+
+// Max number of instructions a plugin can execute to verify a single proof
+const WASM_PLUGIN_FUEL: u64 = 10_000_000_000;
+
+/// Verifier plugins compiled to WASM, indexed by the keccak256 hash of their module.
+///
+/// Plugins must export `memory`, `alloc(len: i32) -> i32`, `aligned_abi_version() -> i32`
+/// and `verify(proof_ptr, proof_len, pub_input_ptr, pub_input_len, vk_ptr, vk_len) -> i32`,
+/// which returns 1 for valid proofs, 0 for invalid ones and a negative value on errors.
+/// Plugins can't import anything from the host.
+pub struct WasmPlugins {
+    engine: Engine,
+    modules: HashMap<[u8; WASM_PLUGIN_HASH_SIZE], Module>,
+}
+
+impl WasmPlugins {
+    /// Loads the plugins in the config, failing if any of them doesn't match its allowlisted hash
+    pub fn load(plugins: &[WasmPluginConfig]) -> Result<Self, String> {
+        let mut config = Config::new();
+        config.consume_fuel(true);
+        let engine = Engine::new(&config).map_err(|e| e.to_string())?;
+
+        let mut modules = HashMap::new();
+        for plugin in plugins {
+            let bytes = std::fs::read(&plugin.path)
+                .map_err(|e| format!("Failed to read WASM plugin {}: {}", plugin.path, e))?;
+            let hash: [u8; WASM_PLUGIN_HASH_SIZE] = Keccak256::digest(&bytes).into();
+
+            let expected_hash = hex::decode(plugin.hash.trim_start_matches("0x"))
+                .map_err(|e| format!("Invalid hash of WASM plugin {}: {}", plugin.path, e))?;
+            if hash.as_slice() != expected_hash.as_slice() {
+                return Err(format!(
+                    "WASM plugin {} hash 0x{} doesn't match the allowlisted hash {}",
+                    plugin.path,
+                    hex::encode(hash),
+                    plugin.hash
+                ));
+            }
+
+            let module = Module::new(&engine, &bytes)
+                .map_err(|e| format!("Failed to compile WASM plugin {}: {}", plugin.path, e))?;
+            if module.imports().next().is_some() {
+                return Err(format!(
+                    "WASM plugin {} must not import host functions",
+                    plugin.path
+                ));
+            }
+            modules.insert(hash, module);
+        }
+
+        Ok(Self { engine, modules })
+    }
+
+    pub fn is_empty(&self) -> bool {
+        self.modules.is_empty()
+    }
+}
+
+/// Verifies a proof with the plugin whose hash is at the start of the verification key,
+/// the rest of the verification key being passed to the plugin.
+pub fn verify_wasm_plugin_proof(
+    plugins: &WasmPlugins,
+    proof: &[u8],
+    pub_input: &[u8],
+    verification_key: &[u8],
+) -> bool {
+    if verification_key.len() < WASM_PLUGIN_HASH_SIZE {
+        warn!("WASM plugin verification key doesn't include the plugin hash");
+        return false;
+    }
+    let (hash, vk) = verification_key.split_at(WASM_PLUGIN_HASH_SIZE);
+
+    let mut plugin_hash = [0u8; WASM_PLUGIN_HASH_SIZE];
+    plugin_hash.copy_from_slice(hash);
+    let Some(module) = plugins.modules.get(&plugin_hash) else {
+        warn!("WASM plugin 0x{} is not enabled", hex::encode(hash));
+        return false;
+    };
+
+    debug!("Verifying proof with WASM plugin 0x{}", hex::encode(hash));
+    match run_plugin(&plugins.engine, module, proof, pub_input, vk) {
+        Ok(result) => {
+            debug!("WASM plugin proof is valid: {}", result);
+            result
+        }
+        Err(e) => {
+            warn!("WASM plugin 0x{} failed: {}", hex::encode(hash), e);
+            false
+        }
+    }
+}
+
+fn run_plugin(
+    engine: &Engine,
+    module: &Module,
+    proof: &[u8],
+    pub_input: &[u8],
+    vk: &[u8],
+) -> anyhow::Result<bool> {
+    let mut store = Store::new(engine, ());
+    store.set_fuel(WASM_PLUGIN_FUEL)?;
+    let instance = Instance::new(&mut store, module, &[])?;
+
+    let abi_version = instance
+        .get_typed_func::<(), i32>(&mut store, "aligned_abi_version")?
+        .call(&mut store, ())?;
+    if abi_version != WASM_PLUGIN_ABI_VERSION {
+        anyhow::bail!("unsupported ABI version {}", abi_version);
+    }
+
+    let memory = instance
+        .get_memory(&mut store, "memory")
+        .ok_or_else(|| anyhow::anyhow!("missing memory export"))?;
+    let alloc = instance.get_typed_func::<i32, i32>(&mut store, "alloc")?;
+    let verify =
+        instance.get_typed_func::<(i32, i32, i32, i32, i32, i32), i32>(&mut store, "verify")?;
+
+    let mut args = [0i32; 6];
+    for (i, input) in [proof, pub_input, vk].iter().enumerate() {
+        let len = i32::try_from(input.len())?;
+        let ptr = alloc.call(&mut store, len)?;
+        memory.write(&mut store, ptr as u32 as usize, input)?;
+        args[2 * i] = ptr;
+        args[2 * i + 1] = len;
+    }
+
+    let result = verify.call(
+        &mut store,
+        (args[0], args[1], args[2], args[3], args[4], args[5]),
+    )?;
+    match result {
+        1 => Ok(true),
+        0 => Ok(false),
+        code => anyhow::bail!("verify returned error code {}", code),
+    }
+}
@@ -1,18 +1,33 @@
+use crate::binius::verify_binius_proof;
+use crate::boojum::verify_boojum_proof;
+use crate::cairo::verify_cairo_proof;
 use crate::gnark::verify_gnark;
+use crate::halo2::verify_halo2_proof;
+use crate::jolt::verify_jolt_proof;
+use crate::kimchi::verify_kimchi_proof;
+use crate::miden::verify_miden_proof;
+use crate::nova::verify_nova_proof;
+use crate::plonky2::verify_plonky2_proof;
 use crate::risc_zero::verify_risc_zero_proof;
 use crate::sp1::verify_sp1_proof;
+use crate::valida::verify_valida_proof;
+use crate::wasm_plugin::{verify_wasm_plugin_proof, WasmPlugins};
 use aligned_sdk::core::types::{ProvingSystemId, VerificationData};
 use ethers::types::U256;
 use log::{debug, warn};
+use std::sync::Arc;
 
-pub(crate) async fn verify(verification_data: &VerificationData) -> bool {
+pub(crate) async fn verify(
+    verification_data: &VerificationData,
+    wasm_plugins: Arc<WasmPlugins>,
+) -> bool {
     let verification_data = verification_data.clone();
-    tokio::task::spawn_blocking(move || verify_internal(&verification_data))
+    tokio::task::spawn_blocking(move || verify_internal(&verification_data, &wasm_plugins))
         .await
         .unwrap_or(false)
 }
 
-fn verify_internal(verification_data: &VerificationData) -> bool {
+fn verify_internal(verification_data: &VerificationData, wasm_plugins: &WasmPlugins) -> bool {
     match verification_data.proving_system {
         ProvingSystemId::SP1 => {
             let Some(elf) = &verification_data.vm_program_code else {
@@ -36,9 +51,193 @@ fn verify_internal(verification_data: &VerificationData) -> bool {
             image_id.copy_from_slice(image_id_slice.as_slice());
             verify_risc_zero_proof(verification_data.proof.as_slice(), &image_id, &pub_input)
         }
+        ProvingSystemId::Plonky2 => {
+            let Some(verifier_data) = &verification_data.verification_key else {
+                warn!(
+                    "Trying to verify Plonky2 proof but verifier data was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_plonky2_proof(verification_data.proof.as_slice(), verifier_data.as_slice())
+        }
+        ProvingSystemId::Cairo => {
+            let Some(pub_input) = &verification_data.pub_input else {
+                warn!(
+                    "Trying to verify Cairo proof but public input was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_cairo_proof(verification_data.proof.as_slice(), pub_input.as_slice())
+        }
+        ProvingSystemId::Nova => {
+            let Some(vk) = &verification_data.verification_key else {
+                warn!(
+                    "Trying to verify Nova proof but verifier key was not provided. Returning false"
+                );
+                return false;
+            };
+            let Some(pub_input) = &verification_data.pub_input else {
+                warn!(
+                    "Trying to verify Nova proof but public input was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_nova_proof(
+                verification_data.proof.as_slice(),
+                pub_input.as_slice(),
+                vk.as_slice(),
+            )
+        }
+        ProvingSystemId::Jolt => {
+            let Some(elf) = &verification_data.vm_program_code else {
+                warn!("Trying to verify Jolt proof but ELF was not provided. Returning false");
+                return false;
+            };
+            let Some(program_io) = &verification_data.pub_input else {
+                warn!(
+                    "Trying to verify Jolt proof but program io was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_jolt_proof(
+                verification_data.proof.as_slice(),
+                elf.as_slice(),
+                program_io.as_slice(),
+            )
+        }
+        ProvingSystemId::Miden => {
+            let Some(program_hash) = &verification_data.vm_program_code else {
+                warn!(
+                    "Trying to verify Miden proof but program hash was not provided. Returning false"
+                );
+                return false;
+            };
+            // The public input always holds the number of stack inputs, even if it is zero
+            let Some(pub_input) = &verification_data.pub_input else {
+                warn!(
+                    "Trying to verify Miden proof but public input was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_miden_proof(
+                verification_data.proof.as_slice(),
+                program_hash.as_slice(),
+                pub_input.as_slice(),
+            )
+        }
+        ProvingSystemId::Binius => {
+            let Some(constraint_system) = &verification_data.verification_key else {
+                warn!(
+                    "Trying to verify Binius proof but constraint system was not provided. Returning false"
+                );
+                return false;
+            };
+            let Some(pub_input) = &verification_data.pub_input else {
+                warn!(
+                    "Trying to verify Binius proof but public input was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_binius_proof(
+                verification_data.proof.as_slice(),
+                pub_input.as_slice(),
+                constraint_system.as_slice(),
+            )
+        }
+        ProvingSystemId::Halo2 => {
+            let Some(params) = &verification_data.verification_key else {
+                warn!("Trying to verify Halo2 proof but params were not provided. Returning false");
+                return false;
+            };
+            let Some(pub_input) = &verification_data.pub_input else {
+                warn!(
+                    "Trying to verify Halo2 proof but public input was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_halo2_proof(
+                verification_data.proof.as_slice(),
+                pub_input.as_slice(),
+                params.as_slice(),
+            )
+        }
+        ProvingSystemId::Boojum => {
+            let Some(vk) = &verification_data.verification_key else {
+                warn!(
+                    "Trying to verify Boojum proof but verification key was not provided. Returning false"
+                );
+                return false;
+            };
+            let Some(pub_input) = &verification_data.pub_input else {
+                warn!(
+                    "Trying to verify Boojum proof but public input was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_boojum_proof(
+                verification_data.proof.as_slice(),
+                pub_input.as_slice(),
+                vk.as_slice(),
+            )
+        }
+        ProvingSystemId::Valida => {
+            let Some(program) = &verification_data.vm_program_code else {
+                warn!(
+                    "Trying to verify Valida proof but program was not provided. Returning false"
+                );
+                return false;
+            };
+            let Some(pub_input) = &verification_data.pub_input else {
+                warn!(
+                    "Trying to verify Valida proof but public input was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_valida_proof(
+                verification_data.proof.as_slice(),
+                program.as_slice(),
+                pub_input.as_slice(),
+            )
+        }
+        ProvingSystemId::Kimchi => {
+            let Some(vk) = &verification_data.verification_key else {
+                warn!(
+                    "Trying to verify Kimchi proof but verifier index was not provided. Returning false"
+                );
+                return false;
+            };
+            let Some(pub_input) = &verification_data.pub_input else {
+                warn!(
+                    "Trying to verify Kimchi proof but public input was not provided. Returning false"
+                );
+                return false;
+            };
+            verify_kimchi_proof(
+                verification_data.proof.as_slice(),
+                pub_input.as_slice(),
+                vk.as_slice(),
+            )
+        }
+        ProvingSystemId::WasmPlugin => {
+            let Some(vk) = &verification_data.verification_key else {
+                warn!(
+                    "Trying to verify WASM plugin proof but verification key was not provided. Returning false"
+                );
+                return false;
+            };
+            // Plugins may not need a public input, in which case an empty one is passed
+            let pub_input = verification_data.pub_input.clone().unwrap_or_default();
+            verify_wasm_plugin_proof(
+                wasm_plugins,
+                verification_data.proof.as_slice(),
+                pub_input.as_slice(),
+                vk.as_slice(),
+            )
+        }
         ProvingSystemId::GnarkPlonkBls12_381
         | ProvingSystemId::GnarkPlonkBn254
-        | ProvingSystemId::Groth16Bn254 => {
+        | ProvingSystemId::Groth16Bn254
+        | ProvingSystemId::Groth16Bls12_381 => {
             let Some(vk) = verification_data.verification_key.as_ref() else {
                 warn!("Gnark verification key missing");
                 return false;
@@ -82,6 +281,18 @@ mod test {
             ProvingSystemId::Groth16Bn254,
             ProvingSystemId::SP1,
             ProvingSystemId::Risc0,
+            ProvingSystemId::Plonky2,
+            ProvingSystemId::Groth16Bls12_381,
+            ProvingSystemId::Cairo,
+            ProvingSystemId::Nova,
+            ProvingSystemId::Jolt,
+            ProvingSystemId::Miden,
+            ProvingSystemId::Binius,
+            ProvingSystemId::Halo2,
+            ProvingSystemId::Boojum,
+            ProvingSystemId::Valida,
+            ProvingSystemId::Kimchi,
+            ProvingSystemId::WasmPlugin,
         ];
         // Just to make sure we are not missing any verifier. The compilation will fail if we do and it forces us to add it to the vec above.
         for verifier in verifiers.iter() {
@@ -91,6 +302,18 @@ mod test {
                 ProvingSystemId::GnarkPlonkBls12_381 => (),
                 ProvingSystemId::GnarkPlonkBn254 => (),
                 ProvingSystemId::Groth16Bn254 => (),
+                ProvingSystemId::Plonky2 => (),
+                ProvingSystemId::Groth16Bls12_381 => (),
+                ProvingSystemId::Cairo => (),
+                ProvingSystemId::Nova => (),
+                ProvingSystemId::Jolt => (),
+                ProvingSystemId::Miden => (),
+                ProvingSystemId::Binius => (),
+                ProvingSystemId::Halo2 => (),
+                ProvingSystemId::Boojum => (),
+                ProvingSystemId::Valida => (),
+                ProvingSystemId::Kimchi => (),
+                ProvingSystemId::WasmPlugin => (),
             }
         }
         verifiers
@@ -141,8 +364,9 @@ mod test {
     #[test]
     fn test_some_verifiers_disabled() {
         let verifiers = get_all_verifiers();
-        // Disabling only the first verifier
-        let disabled_verifiers = ethers::types::U256::from(0b10001);
+        // Disabling only the first and the last verifiers
+        let disabled_verifiers =
+            ethers::types::U256::one() | (ethers::types::U256::one() << (verifiers.len() - 1));
         for verifier in get_all_verifiers().iter() {
             let verification_data = VerificationData {
                 proving_system: *verifier,
@@ -26,7 +26,7 @@ use super::errors::VerifySignatureError;
 const NONCED_VERIFICATION_DATA_TYPE: &[u8] =
     b"NoncedVerificationData(bytes32 verification_data_hash,uint256 nonce,uint256 max_fee)";
 
-#[derive(Debug, Serialize, Deserialize, Default, Clone, PartialEq, Eq, Copy)]
+#[derive(Debug, Serialize, Deserialize, Default, Clone, PartialEq, Eq, Hash, Copy)]
 #[repr(u8)]
 pub enum ProvingSystemId {
     GnarkPlonkBls12_381,
@@ -35,6 +35,18 @@ pub enum ProvingSystemId {
     #[default]
     SP1,
     Risc0,
+    Plonky2,
+    Groth16Bls12_381,
+    Cairo,
+    Nova,
+    Jolt,
+    Miden,
+    Binius,
+    Halo2,
+    Boojum,
+    Valida,
+    Kimchi,
+    WasmPlugin,
 }
 
 impl Display for ProvingSystemId {
@@ -45,6 +57,18 @@ impl Display for ProvingSystemId {
             ProvingSystemId::Groth16Bn254 => write!(f, "Groth16Bn254"),
             ProvingSystemId::SP1 => write!(f, "SP1"),
             ProvingSystemId::Risc0 => write!(f, "Risc0"),
+            ProvingSystemId::Plonky2 => write!(f, "Plonky2"),
+            ProvingSystemId::Groth16Bls12_381 => write!(f, "Groth16Bls12_381"),
+            ProvingSystemId::Cairo => write!(f, "Cairo"),
+            ProvingSystemId::Nova => write!(f, "Nova"),
+            ProvingSystemId::Jolt => write!(f, "Jolt"),
+            ProvingSystemId::Miden => write!(f, "Miden"),
+            ProvingSystemId::Binius => write!(f, "Binius"),
+            ProvingSystemId::Halo2 => write!(f, "Halo2"),
+            ProvingSystemId::Boojum => write!(f, "Boojum"),
+            ProvingSystemId::Valida => write!(f, "Valida"),
+            ProvingSystemId::Kimchi => write!(f, "Kimchi"),
+            ProvingSystemId::WasmPlugin => write!(f, "WasmPlugin"),
         }
     }
 }
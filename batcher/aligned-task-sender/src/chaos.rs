@@ -0,0 +1,193 @@
+use std::collections::HashSet;
+use std::sync::Arc;
+
+use aligned_sdk::core::types::{Network, VerificationData};
+use aligned_sdk::eth::aligned_service_manager::AlignedLayerServiceManagerContract;
+use aligned_sdk::sdk::get_aligned_service_manager_address;
+use clap::ValueEnum;
+use ethers::prelude::*;
+use log::{error, info};
+use serde::Serialize;
+
+/// A way of breaking a valid proof that the batcher or the operators must refuse
+#[derive(Clone, Copy, Debug, PartialEq, Eq, ValueEnum, Serialize)]
+#[serde(rename_all = "kebab-case")]
+pub enum ChaosScenario {
+    /// The proof with a byte in its middle flipped
+    MalformedProof,
+    /// The proof with a byte of its public input flipped
+    WrongPublicInput,
+    /// The proof padded past the max proof size of the batcher
+    OversizedPayload,
+    /// The proof with the verification key, or program, of another proof
+    MismatchedVerificationKey,
+}
+
+impl ChaosScenario {
+    pub fn name(&self) -> String {
+        self.to_possible_value()
+            .expect("Chaos scenarios are not skipped")
+            .get_name()
+            .to_string()
+    }
+
+    /// Returns the verification data of the scenario, breaking `valid` and taking the
+    /// verification key or program of `other` when it differs
+    pub fn apply(
+        &self,
+        valid: &VerificationData,
+        other: Option<&VerificationData>,
+        oversized_payload_size: usize,
+    ) -> VerificationData {
+        let mut data = valid.clone();
+        match self {
+            ChaosScenario::MalformedProof => flip_middle_byte(&mut data.proof),
+            ChaosScenario::WrongPublicInput => match data.pub_input.as_mut() {
+                Some(pub_input) if !pub_input.is_empty() => flip_middle_byte(pub_input),
+                _ => data.pub_input = Some(vec![0xff; 32]),
+            },
+            ChaosScenario::OversizedPayload => {
+                let size = oversized_payload_size.max(data.proof.len() + 1);
+                data.proof.resize(size, 0);
+            }
+            ChaosScenario::MismatchedVerificationKey => {
+                let other_key = other.and_then(|other| {
+                    if data.verification_key.is_some() {
+                        other.verification_key.clone()
+                    } else {
+                        other.vm_program_code.clone()
+                    }
+                });
+                let key = if data.verification_key.is_some() {
+                    &mut data.verification_key
+                } else {
+                    &mut data.vm_program_code
+                };
+                match other_key {
+                    Some(other_key) if key.as_ref() != Some(&other_key) => *key = Some(other_key),
+                    _ => {
+                        if let Some(key) = key.as_mut() {
+                            flip_middle_byte(key)
+                        }
+                    }
+                }
+            }
+        }
+        data
+    }
+}
+
+fn flip_middle_byte(content: &mut [u8]) {
+    if !content.is_empty() {
+        let middle = content.len() / 2;
+        content[middle] ^= 0xff;
+    }
+}
+
+/// What happened to the proof of a scenario
+#[derive(Debug, Clone, Serialize)]
+#[serde(tag = "outcome", rename_all = "snake_case")]
+pub enum ChaosOutcome {
+    /// The batcher refused the proof with the reason
+    Rejected { reason: String },
+    /// The proof was included in a batch that wasn't verified before the timeout
+    NotVerified { batch_merkle_root: String },
+    /// The proof was included in a batch that was verified, so operators signed it
+    Verified { batch_merkle_root: String },
+    /// The proof couldn't be sent, so nothing was asserted
+    Error { reason: String },
+}
+
+#[derive(Debug, Clone, Serialize)]
+pub struct ChaosResult {
+    pub scenario: ChaosScenario,
+    #[serde(flatten)]
+    pub outcome: ChaosOutcome,
+}
+
+impl ChaosResult {
+    /// Whether the broken proof was refused, either by the batcher or by the operators
+    pub fn passed(&self) -> bool {
+        matches!(
+            self.outcome,
+            ChaosOutcome::Rejected { .. } | ChaosOutcome::NotVerified { .. }
+        )
+    }
+
+    pub fn log(&self) {
+        let scenario = self.scenario.name();
+        match &self.outcome {
+            ChaosOutcome::Rejected { reason } => {
+                info!("PASS {}: rejected by the batcher ({})", scenario, reason)
+            }
+            ChaosOutcome::NotVerified { batch_merkle_root } => info!(
+                "PASS {}: batch {} not verified by the operators",
+                scenario, batch_merkle_root
+            ),
+            ChaosOutcome::Verified { batch_merkle_root } => error!(
+                "FAIL {}: batch {} was verified with the broken proof",
+                scenario, batch_merkle_root
+            ),
+            ChaosOutcome::Error { reason } => {
+                error!("FAIL {}: could not send the proof: {}", scenario, reason)
+            }
+        }
+    }
+}
+
+/// Reads the batches verified in the Aligned service manager from `from_block` on
+pub struct VerifiedBatches {
+    provider: Provider<Http>,
+    service_manager: AlignedLayerServiceManagerContract<Provider<Http>>,
+    from_block: U64,
+    verified: HashSet<[u8; 32]>,
+}
+
+impl VerifiedBatches {
+    pub async fn new(eth_rpc_url: &str, network: Network) -> Result<Self, String> {
+        let provider = Provider::<Http>::try_from(eth_rpc_url)
+            .map_err(|e| format!("Could not connect to eth rpc: {}", e))?;
+        let from_block = provider
+            .get_block_number()
+            .await
+            .map_err(|e| format!("Could not get block number: {}", e))?;
+        let service_manager = AlignedLayerServiceManagerContract::new(
+            get_aligned_service_manager_address(network),
+            Arc::new(provider.clone()),
+        );
+        Ok(Self {
+            provider,
+            service_manager,
+            from_block,
+            verified: HashSet::new(),
+        })
+    }
+
+    /// Reads the `BatchVerified` events of the blocks since the last call
+    pub async fn update(&mut self) -> Result<(), String> {
+        let to_block = self
+            .provider
+            .get_block_number()
+            .await
+            .map_err(|e| format!("Could not get block number: {}", e))?;
+        if to_block < self.from_block {
+            return Ok(());
+        }
+        let events = self
+            .service_manager
+            .batch_verified_filter()
+            .from_block(self.from_block)
+            .to_block(to_block)
+            .query()
+            .await
+            .map_err(|e| format!("Could not get BatchVerified events: {}", e))?;
+        self.verified
+            .extend(events.into_iter().map(|event| event.batch_merkle_root));
+        self.from_block = to_block + 1;
+        Ok(())
+    }
+
+    pub fn is_verified(&self, batch_merkle_root: &[u8; 32]) -> bool {
+        self.verified.contains(batch_merkle_root)
+    }
+}
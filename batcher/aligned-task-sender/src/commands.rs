@@ -1,29 +1,75 @@
-use aligned_sdk::core::types::{Network, ProvingSystemId, VerificationData};
+use aligned_sdk::core::errors::SubmitError;
+use aligned_sdk::core::types::{AlignedVerificationData, Network, VerificationData};
 use aligned_sdk::sdk::{deposit_to_aligned, get_nonce_from_batcher, submit_multiple};
 use ethers::prelude::*;
-use ethers::utils::parse_ether;
+use ethers::utils::{hex, parse_ether};
 use futures_util::StreamExt;
 use k256::ecdsa::SigningKey;
-use log::{debug, error, info};
+use log::{debug, error, info, warn};
 use rand::seq::SliceRandom;
-use rand::thread_rng;
+use rand::{thread_rng, Rng};
+use std::collections::HashMap;
 use std::fs::{self, File};
 use std::io::ErrorKind;
 use std::io::{BufRead, BufReader, Write};
+use std::path::Path;
 use std::process::Command;
 use std::str::FromStr;
+use std::sync::{Arc, Mutex};
 use std::thread;
-use std::time::Duration;
+use std::time::{Duration, Instant};
 use tokio::join;
+use tokio::sync::mpsc;
 use tokio_tungstenite::connect_async;
 
+use crate::chaos::{ChaosOutcome, ChaosResult, VerifiedBatches};
+use crate::corpus::{
+    add_invalid_proofs, fetch_corpus, index_corpus, load_corpus, log_catalog_summary, Catalog,
+    CorpusProof,
+};
+use crate::fee::{self, FeeModel};
+use crate::report::{failure_reason, watch_verified_batches, ProofTracker};
 use crate::structs::{
-    GenerateAndFundWalletsArgs, GenerateProofsArgs, ProofType, SendInfiniteProofsArgs,
-    TestConnectionsArgs,
+    ChaosArgs, CorpusArgs, CorpusCommands, EstimateFeeArgs, GenerateAndFundWalletsArgs,
+    GenerateProofsArgs, ProofMix, ProofSize, ProofSizeMix, ProofType, SendInfiniteProofsArgs,
+    SendProofsLoadTestArgs, TestConnectionsArgs,
 };
 
 const GROTH_16_PROOF_GENERATOR_FILE_PATH: &str =
     "../../scripts/test_files/gnark_groth16_bn254_infinite_script/cmd/main.go";
+
+/// Returns the files of the example proof of the proof types that can't be generated with
+/// different inputs, which are copied instead
+fn example_proof_files(proof_type: ProofType) -> &'static [&'static str] {
+    match proof_type {
+        ProofType::Groth16 => &[],
+        ProofType::Groth16Bls12_381 => &[
+            "../../scripts/test_files/gnark_groth16_bls12_381_script/groth16.proof",
+            "../../scripts/test_files/gnark_groth16_bls12_381_script/groth16.pub",
+            "../../scripts/test_files/gnark_groth16_bls12_381_script/groth16.vk",
+        ],
+        ProofType::PlonkBn254 => &[
+            "../../scripts/test_files/gnark_plonk_bn254_script/plonk.proof",
+            "../../scripts/test_files/gnark_plonk_bn254_script/plonk_pub_input.pub",
+            "../../scripts/test_files/gnark_plonk_bn254_script/plonk.vk",
+        ],
+        ProofType::PlonkBls12_381 => &[
+            "../../scripts/test_files/gnark_plonk_bls12_381_script/plonk.proof",
+            "../../scripts/test_files/gnark_plonk_bls12_381_script/plonk_pub_input.pub",
+            "../../scripts/test_files/gnark_plonk_bls12_381_script/plonk.vk",
+        ],
+        ProofType::SP1 => &[
+            "../../scripts/test_files/sp1/sp1_fibonacci.proof",
+            "../../scripts/test_files/sp1/sp1_fibonacci.elf",
+        ],
+        ProofType::Risc0 => &[
+            "../../scripts/test_files/risc_zero/fibonacci_proof_generator/risc_zero_fibonacci.proof",
+            "../../scripts/test_files/risc_zero/fibonacci_proof_generator/risc_zero_fibonacci.pub",
+            "../../scripts/test_files/risc_zero/fibonacci_proof_generator/fibonacci_id.bin",
+        ],
+    }
+}
+
 pub async fn generate_proofs(args: GenerateProofsArgs) {
     std::fs::create_dir_all(args.dir_to_save_proofs.clone()).expect("Could not create directory");
 
@@ -53,6 +99,24 @@ pub async fn generate_proofs(args: GenerateProofsArgs) {
                         .status()
                         .unwrap();
                 }
+                proof_type => {
+                    let dir_to_save_proofs =
+                        format!("{}/{}_{}/", dir_to_save_proofs, proof_type.name(), i);
+                    if let Err(e) = fs::create_dir_all(&dir_to_save_proofs) {
+                        eprintln!("Error creating directory: {}", e);
+                        return;
+                    }
+
+                    for file in example_proof_files(proof_type) {
+                        let file_path = Path::new(file);
+                        let file_name = file_path.file_name().expect("Example proof file name");
+                        if let Err(e) =
+                            fs::copy(file_path, Path::new(&dir_to_save_proofs).join(file_name))
+                        {
+                            eprintln!("Error copying example proof file {}: {}", file, e);
+                        }
+                    }
+                }
             }
         });
         handles.push(handle);
@@ -245,205 +309,813 @@ pub async fn test_connection(args: TestConnectionsArgs) {
     }
 }
 
+/// A wallet sending proofs. Each sender tracks its own nonce, so it only asks the batcher for it
+/// when it's unknown, and senders can submit concurrently without coordinating.
 struct Sender {
     wallet: Wallet<SigningKey>,
+    /// The nonce of the next proof sent, None if it must be fetched from the batcher
+    next_nonce: Option<U256>,
 }
 
-pub async fn send_infinite_proofs(args: SendInfiniteProofsArgs) {
-    if matches!(args.network.into(), Network::Holesky) {
-        error!("Network not supported this infinite proof sender");
-        return;
+impl Sender {
+    fn new(wallet: Wallet<SigningKey>) -> Self {
+        Self {
+            wallet,
+            next_nonce: None,
+        }
     }
 
-    info!("Loading wallets");
-    let mut senders = vec![];
-    let Ok(eth_rpc_provider) = Provider::<Http>::try_from(args.eth_rpc_url.clone()) else {
-        error!("Could not connect to eth rpc");
-        return;
-    };
-    let Ok(chain_id) = eth_rpc_provider.get_chainid().await else {
-        error!("Could not get chain id");
-        return;
-    };
+    async fn next_nonce(&mut self, batcher_url: &str) -> Result<U256, String> {
+        if let Some(nonce) = self.next_nonce {
+            return Ok(nonce);
+        }
+        let nonce = get_nonce_from_batcher(batcher_url, self.wallet.address())
+            .await
+            .map_err(|e| {
+                format!(
+                    "Could not get nonce: {:?}, for sender {:?}",
+                    e,
+                    self.wallet.address()
+                )
+            })?;
+        self.next_nonce = Some(nonce);
+        Ok(nonce)
+    }
 
-    let file = match File::open(&args.private_keys_filepath) {
-        Ok(file) => file,
-        Err(err) => {
-            error!("Could not open private keys file: {}", err.to_string());
-            return;
+    /// Submits the proofs with the next nonces of the sender, returning the nonce of the first one
+    /// and the batcher responses. The batcher only accepts the next nonce of each sender, so a
+    /// sender submits one burst at a time. If any proof fails, the nonce is fetched again before
+    /// the next submission, as the batcher may not have accepted all of them.
+    async fn submit(
+        &mut self,
+        batcher_url: &str,
+        network: Network,
+        verification_data: &[VerificationData],
+        max_fee: U256,
+    ) -> Result<(U256, Vec<Result<AlignedVerificationData, SubmitError>>), String> {
+        let nonce = self.next_nonce(batcher_url).await?;
+        let responses = submit_multiple(
+            batcher_url,
+            network,
+            verification_data,
+            max_fee,
+            self.wallet.clone(),
+            nonce,
+        )
+        .await;
+
+        if responses.len() == verification_data.len() && responses.iter().all(Result::is_ok) {
+            self.next_nonce = Some(nonce + verification_data.len());
+        } else {
+            self.next_nonce = None;
         }
+        Ok((nonce, responses))
+    }
+}
+
+/// Loads the sender wallets from a file with a private key per line, keeping the first
+/// `num_senders` if set
+async fn load_senders(
+    eth_rpc_url: &str,
+    private_keys_filepath: &str,
+    num_senders: Option<usize>,
+) -> Result<Vec<Sender>, String> {
+    let Ok(eth_rpc_provider) = Provider::<Http>::try_from(eth_rpc_url) else {
+        return Err("Could not connect to eth rpc".to_string());
+    };
+    let Ok(chain_id) = eth_rpc_provider.get_chainid().await else {
+        return Err("Could not get chain id".to_string());
     };
 
+    let file = File::open(private_keys_filepath)
+        .map_err(|err| format!("Could not open private keys file: {}", err))?;
     let reader = BufReader::new(file);
 
-    // now here we need to load the senders from the provided files
+    let mut senders = vec![];
     for line in reader.lines() {
-        let private_key_str = match line {
-            Ok(line) => line,
-            Err(err) => {
-                error!(
-                    "Could not read line from private keys file: {}",
-                    err.to_string()
-                );
-                return;
-            }
-        };
+        if num_senders.is_some_and(|num_senders| senders.len() == num_senders) {
+            break;
+        }
+        let private_key_str =
+            line.map_err(|err| format!("Could not read line from private keys file: {}", err))?;
         let wallet = Wallet::from_str(private_key_str.trim()).expect("Invalid private key");
         let wallet = wallet.with_chain_id(chain_id.as_u64());
-        let sender = Sender { wallet };
-
-        // info!("Wallet {} loaded", i);
-        senders.push(sender);
+        senders.push(Sender::new(wallet));
     }
 
     if senders.is_empty() {
-        error!("No wallets in file");
+        return Err("No wallets in file".to_string());
+    }
+    if let Some(num_senders) = num_senders {
+        if senders.len() < num_senders {
+            return Err(format!(
+                "{} senders requested, but there are only {} wallets in file",
+                num_senders,
+                senders.len()
+            ));
+        }
+    }
+    Ok(senders)
+}
+
+pub async fn send_infinite_proofs(args: SendInfiniteProofsArgs) {
+    if matches!(args.network.into(), Network::Holesky) {
+        error!("Network not supported this infinite proof sender");
         return;
     }
+
+    info!("Loading wallets");
+    let senders = match load_senders(
+        &args.eth_rpc_url,
+        &args.private_keys_filepath,
+        args.num_senders,
+    )
+    .await
+    {
+        Ok(senders) => senders,
+        Err(err) => {
+            error!("{}", err);
+            return;
+        }
+    };
     info!("All wallets loaded");
 
     info!("Loading proofs verification data");
-    let verification_data =
-        get_verification_data_from_proofs_folder(args.proofs_dir, senders[0].wallet.address());
-    if verification_data.is_empty() {
-        error!("Verification data empty, not continuing");
+    let Some(proof_sampler) = load_proof_sampler(
+        &args.proofs_dir,
+        senders[0].wallet.address(),
+        &args.proof_mix,
+        &args.proof_size_mix,
+        args.invalid_proofs_ratio,
+    ) else {
         return;
-    }
+    };
+    let proof_sampler = Arc::new(proof_sampler);
     info!("Proofs loaded!");
 
     let max_fee = U256::from_dec_str(&args.max_fee).expect("Invalid max fee");
 
     let mut handles = vec![];
     info!("Starting senders!");
-    for (i, sender) in senders.iter().enumerate() {
+    for (i, mut sender) in senders.into_iter().enumerate() {
         // this is necessary because of the move
         let batcher_url = args.batcher_url.clone();
-        let wallet = sender.wallet.clone();
-        let verification_data = verification_data.clone();
+        let proof_sampler = proof_sampler.clone();
 
         // a thread to send tasks from each loaded wallet:
         let handle = tokio::spawn(async move {
             loop {
-                let mut result = Vec::with_capacity(args.burst_size);
-                let nonce = get_nonce_from_batcher(&batcher_url, wallet.address())
-                    .await
-                    .inspect_err(|e| {
-                        error!(
-                            "Could not get nonce: {:?}, for sender {:?}",
-                            e,
-                            wallet.address()
-                        )
-                    })
-                    .unwrap();
-                while result.len() < args.burst_size {
-                    let samples = verification_data
-                        .choose_multiple(&mut thread_rng(), args.burst_size - result.len());
-                    result.extend(samples.cloned());
+                let verification_data_to_send = proof_sampler.sample(args.burst_size);
+
+                let address = sender.wallet.address();
+                let submission = sender
+                    .submit(
+                        &batcher_url,
+                        args.network.into(),
+                        &verification_data_to_send,
+                        max_fee,
+                    )
+                    .await;
+                match submission {
+                    Ok((nonce, aligned_verification_data)) => {
+                        info!(
+                            "Sent {:?} Proofs to Aligned Batcher on {:?} from sender {}, nonce: {}, address: {:?}",
+                            args.burst_size, args.network, i, nonce, address,
+                        );
+                        for aligned_verification_data in aligned_verification_data {
+                            match aligned_verification_data {
+                                Ok(_) => {
+                                    debug!("Response received for sender {}", i);
+                                }
+                                Err(e) => {
+                                    error!(
+                                        "Error submitting proofs to aligned: {:?} from sender {}",
+                                        e, i
+                                    );
+                                }
+                            }
+                        }
+                        info!("All responses received for sender {}", i);
+                    }
+                    Err(err) => error!("{}", err),
                 }
-                let verification_data_to_send = result;
 
-                info!(
-                    "Sending {:?} Proofs to Aligned Batcher on {:?} from sender {}, nonce: {}, address: {:?}",
-                    args.burst_size, args.network, i, nonce, wallet.address(),
-                );
-                let batcher_url = batcher_url.clone();
+                tokio::time::sleep(Duration::from_secs(args.burst_time_secs)).await;
+            }
+        });
 
-                let aligned_verification_data = submit_multiple(
-                    &batcher_url.clone(),
-                    args.network.into(),
-                    &verification_data_to_send.clone(),
+        handles.push(handle);
+    }
+
+    for handle in handles {
+        let _ = join!(handle);
+    }
+}
+
+const LOAD_TEST_TICK: Duration = Duration::from_millis(100);
+const LOAD_TEST_VERIFICATION_POLL_INTERVAL: Duration = Duration::from_secs(3);
+
+/// Returns the number of proofs that should have been sent after `elapsed`, with the rate
+/// increasing linearly from 0 to `rate` during the ramp up.
+fn proofs_due(elapsed: Duration, rate: f64, ramp_up: Duration) -> usize {
+    let elapsed = elapsed.as_secs_f64();
+    let ramp_up = ramp_up.as_secs_f64();
+    let due = if elapsed < ramp_up {
+        rate * elapsed * elapsed / (2.0 * ramp_up)
+    } else {
+        rate * (elapsed - ramp_up / 2.0)
+    };
+    due as usize
+}
+
+/// Sends proofs at a target rate from the loaded wallets for a given duration, tracks each proof
+/// until its batch is verified, from the `BatchVerified` events, and reports the achieved
+/// throughput, the latency percentiles and the failures by reason.
+/// Each sender sends a single submission at a time, so the achievable rate depends on the number of senders.
+pub async fn send_proofs_load_test(args: SendProofsLoadTestArgs) {
+    if matches!(args.network.into(), Network::Holesky | Network::Mainnet) {
+        error!("Network not supported by the load test");
+        return;
+    }
+    if args.proofs_per_second <= 0.0 {
+        error!("The target proofs per second must be greater than 0");
+        return;
+    }
+    if args.ramp_up_secs > args.duration_secs {
+        error!("The ramp up can't be longer than the duration");
+        return;
+    }
+
+    info!("Loading wallets");
+    let senders = match load_senders(
+        &args.eth_rpc_url,
+        &args.private_keys_filepath,
+        args.num_senders,
+    )
+    .await
+    {
+        Ok(senders) => senders,
+        Err(err) => {
+            error!("{}", err);
+            return;
+        }
+    };
+    info!("{} wallets loaded", senders.len());
+
+    info!("Loading proofs verification data");
+    let Some(proof_sampler) = load_proof_sampler(
+        &args.proofs_dir,
+        senders[0].wallet.address(),
+        &args.proof_mix,
+        &args.proof_size_mix,
+        args.invalid_proofs_ratio,
+    ) else {
+        return;
+    };
+    info!("Proofs loaded!");
+
+    let max_fee = U256::from_dec_str(&args.max_fee).expect("Invalid max fee");
+    let duration = Duration::from_secs(args.duration_secs);
+    let ramp_up = Duration::from_secs(args.ramp_up_secs);
+    let verification_timeout = Duration::from_secs(args.verification_timeout_secs);
+
+    let from_block = match Provider::<Http>::try_from(args.eth_rpc_url.as_str()) {
+        Ok(provider) => match provider.get_block_number().await {
+            Ok(block_number) => block_number,
+            Err(err) => {
+                error!("Could not get block number: {}", err);
+                return;
+            }
+        },
+        Err(err) => {
+            error!("Could not connect to eth rpc: {}", err);
+            return;
+        }
+    };
+    let tracker = Arc::new(Mutex::new(ProofTracker::default()));
+    let watcher = {
+        let tracker = tracker.clone();
+        let eth_rpc_url = args.eth_rpc_url.clone();
+        let network = args.network;
+        tokio::spawn(async move {
+            if let Err(err) = watch_verified_batches(
+                tracker,
+                &eth_rpc_url,
+                network.into(),
+                from_block,
+                LOAD_TEST_VERIFICATION_POLL_INTERVAL,
+            )
+            .await
+            {
+                error!("{}", err);
+            }
+        })
+    };
+
+    // wallets without a submission in flight
+    let (idle_senders_tx, mut idle_senders_rx) = mpsc::channel(senders.len());
+    for sender in senders {
+        let _ = idle_senders_tx.send(sender).await;
+    }
+
+    let mut handles = vec![];
+    let mut ticks_without_idle_sender = 0;
+    let mut ticker = tokio::time::interval(LOAD_TEST_TICK);
+
+    info!(
+        "Sending {} proofs per second for {} seconds, with {} seconds of ramp up",
+        args.proofs_per_second, args.duration_secs, args.ramp_up_secs
+    );
+    let start = Instant::now();
+    loop {
+        ticker.tick().await;
+        let elapsed = start.elapsed();
+        if elapsed >= duration {
+            break;
+        }
+
+        let proofs_sent = tracker.lock().unwrap().proofs_sent();
+        let due = proofs_due(elapsed, args.proofs_per_second, ramp_up);
+        if due <= proofs_sent {
+            continue;
+        }
+        let Ok(mut sender) = idle_senders_rx.try_recv() else {
+            ticks_without_idle_sender += 1;
+            continue;
+        };
+        let number_of_proofs = (due - proofs_sent).min(args.max_proofs_per_submission);
+
+        let verification_data_to_send = proof_sampler.sample(number_of_proofs);
+        let address = sender.wallet.address();
+        let first =
+            tracker
+                .lock()
+                .unwrap()
+                .submitted(address, &verification_data_to_send, Instant::now());
+
+        let batcher_url = args.batcher_url.clone();
+        let network = args.network;
+        let idle_senders_tx = idle_senders_tx.clone();
+        let tracker = tracker.clone();
+        let handle = tokio::spawn(async move {
+            let submission = sender
+                .submit(
+                    &batcher_url,
+                    network.into(),
+                    &verification_data_to_send,
                     max_fee,
-                    wallet.clone(),
-                    nonce,
                 )
                 .await;
+            let responded_at = Instant::now();
+            // a sender can send again once the batcher answered all its proofs
+            let _ = idle_senders_tx.send(sender).await;
 
-                for aligned_verification_data in aligned_verification_data {
-                    match aligned_verification_data {
-                        Ok(_) => {
-                            debug!("Response received for sender {}", i);
-                        }
-                        Err(e) => {
-                            error!(
-                                "Error submitting proofs to aligned: {:?} from sender {}",
-                                e, i
-                            );
-                        }
+            match submission {
+                Ok((nonce, aligned_verification_data)) => {
+                    debug!(
+                        "Sent {} proofs from sender {:?}, nonce: {}",
+                        number_of_proofs, address, nonce
+                    );
+                    for e in aligned_verification_data
+                        .iter()
+                        .filter_map(|r| r.as_ref().err())
+                    {
+                        error!(
+                            "Error submitting proofs to aligned: {:?} from sender {:?}",
+                            e, address
+                        );
                     }
+                    tracker.lock().unwrap().responded(
+                        first,
+                        number_of_proofs,
+                        &aligned_verification_data,
+                        responded_at,
+                    );
+                }
+                Err(err) => {
+                    error!("{}", err);
+                    tracker.lock().unwrap().submission_failed(
+                        first,
+                        number_of_proofs,
+                        "SubmissionError",
+                    );
                 }
-                info!("All responses received for sender {}", i);
-
-                tokio::time::sleep(Duration::from_secs(args.burst_time_secs)).await;
             }
         });
-
         handles.push(handle);
     }
 
+    info!("Waiting for the sent proofs to be included");
     for handle in handles {
         let _ = join!(handle);
     }
+
+    info!("Waiting for the batches to be verified");
+    let deadline = Instant::now() + verification_timeout;
+    while Instant::now() < deadline && !tracker.lock().unwrap().pending_batches().is_empty() {
+        tokio::time::sleep(LOAD_TEST_VERIFICATION_POLL_INTERVAL).await;
+    }
+    watcher.abort();
+
+    let report = tracker.lock().unwrap().report(duration);
+    info!("Load test finished");
+    info!(
+        "Target rate: {:.2} proofs/s, achieved send rate: {:.2} proofs/s",
+        args.proofs_per_second,
+        report.proofs_sent as f64 / duration.as_secs_f64(),
+    );
+    if ticks_without_idle_sender > 0 {
+        warn!(
+            "All senders were busy for {:.1} seconds, add more senders to reach the target rate",
+            (ticks_without_idle_sender * LOAD_TEST_TICK).as_secs_f64()
+        );
+    }
+    report.log();
+
+    if let Some(report_path) = &args.report_path {
+        match report.write(report_path, args.report_format) {
+            Ok(()) => info!("Report saved in {}", report_path),
+            Err(err) => error!("{}", err),
+        }
+    }
+}
+
+/// Randomly picks proofs following the weights of the proof types and sizes, so batches mix
+/// proving systems the way real traffic does
+struct ProofSampler {
+    valid_proofs: WeightedProofs,
+    invalid_proofs: Option<WeightedProofs>,
+    invalid_proofs_ratio: f64,
+}
+
+/// The proofs of each proof type grouped by size, with the weight of the type and of each size
+struct WeightedProofs(Vec<(Vec<(Vec<VerificationData>, u32)>, u32)>);
+
+impl WeightedProofs {
+    fn new(
+        proofs: &[&CorpusProof],
+        proof_mix: &ProofMix,
+        proof_size_mix: &ProofSizeMix,
+    ) -> Result<Self, String> {
+        let mut proofs_by_type = vec![];
+        for (proof_type, weight) in &proof_mix.0 {
+            if *weight == 0 {
+                continue;
+            }
+
+            let mut proofs_by_size: HashMap<ProofSize, Vec<VerificationData>> = HashMap::new();
+            for proof in proofs
+                .iter()
+                .filter(|proof| proof.entry.proof_type == *proof_type)
+            {
+                proofs_by_size
+                    .entry(proof.entry.size)
+                    .or_default()
+                    .push(proof.verification_data.clone());
+            }
+            let proofs_by_size: Vec<_> = proofs_by_size
+                .into_iter()
+                .map(|(size, proofs)| (proofs, proof_size_mix.weight(&size)))
+                .filter(|(_, weight)| *weight > 0)
+                .collect();
+            if proofs_by_size.is_empty() {
+                return Err(format!(
+                    "No {} proofs of the proof size mix in the corpus",
+                    proof_type.name()
+                ));
+            }
+            proofs_by_type.push((proofs_by_size, *weight));
+        }
+        Ok(Self(proofs_by_type))
+    }
+
+    fn sample(&self, rng: &mut impl Rng) -> VerificationData {
+        let (proofs_by_size, _) = self
+            .0
+            .choose_weighted(rng, |(_, weight)| *weight)
+            .expect("Proof types should have a weight");
+        let (proofs, _) = proofs_by_size
+            .choose_weighted(rng, |(_, weight)| *weight)
+            .expect("Proof sizes should have a weight");
+        proofs
+            .choose(rng)
+            .expect("Proof sizes should have proofs")
+            .clone()
+    }
 }
 
-/// Returns the corresponding verification data for the generated proofs directory
-fn get_verification_data_from_proofs_folder(
-    dir_path: String,
+impl ProofSampler {
+    /// Creates a sampler of the corpus proofs, picking invalid proofs with a probability of
+    /// `invalid_proofs_ratio`, following the same weights as the valid ones
+    fn new(
+        corpus: Vec<CorpusProof>,
+        proof_mix: &ProofMix,
+        proof_size_mix: &ProofSizeMix,
+        invalid_proofs_ratio: f64,
+    ) -> Result<Self, String> {
+        if !(0.0..=1.0).contains(&invalid_proofs_ratio) {
+            return Err("The invalid proofs ratio must be between 0 and 1".to_string());
+        }
+        let (valid_proofs, invalid_proofs): (Vec<_>, Vec<_>) =
+            corpus.iter().partition(|proof| proof.entry.valid);
+
+        let valid_proofs = WeightedProofs::new(&valid_proofs, proof_mix, proof_size_mix)?;
+        let invalid_proofs = if invalid_proofs_ratio > 0.0 {
+            Some(
+                WeightedProofs::new(&invalid_proofs, proof_mix, proof_size_mix)
+                    .map_err(|e| format!("{}. Add them with the corpus add-invalid command", e))?,
+            )
+        } else {
+            None
+        };
+        Ok(Self {
+            valid_proofs,
+            invalid_proofs,
+            invalid_proofs_ratio,
+        })
+    }
+
+    fn sample(&self, number_of_proofs: usize) -> Vec<VerificationData> {
+        let mut rng = thread_rng();
+        (0..number_of_proofs)
+            .map(|_| match &self.invalid_proofs {
+                Some(invalid_proofs) if rng.gen_bool(self.invalid_proofs_ratio) => {
+                    invalid_proofs.sample(&mut rng)
+                }
+                _ => self.valid_proofs.sample(&mut rng),
+            })
+            .collect()
+    }
+}
+
+/// Loads the proofs of the corpus and the sampler to pick them, logging the errors
+fn load_proof_sampler(
+    corpus_dir: &str,
     default_addr: Address,
-) -> Vec<VerificationData> {
-    let mut verifications_data = vec![];
-
-    info!("Reading proofs from {:?}", dir_path);
-
-    let dir = std::fs::read_dir(dir_path).expect("Directory does not exists");
-
-    for proof_folder in dir {
-        // each proof_folder is a dir called groth16_n
-        let proof_folder_dir = proof_folder.unwrap().path();
-        if proof_folder_dir.is_dir() {
-            // todo(marcos): this should be improved if we want to support more proofs
-            // currently we stored the proofs on subdirs with a prefix for the proof type
-            // and here we check the subdir name and based on build the verification data accordingly
-            if proof_folder_dir.to_str().unwrap().contains("groth16") {
-                // Get the first file from the folder
-                let first_file = fs::read_dir(proof_folder_dir.clone())
-                    .expect("Can't read proofs directory")
-                    .filter_map(|entry| entry.ok().map(|e| e.path()))
-                    .find(|path| path.is_file()) // Find any valid file
-                    .expect("No valid proof files found");
-
-                // Extract the base name (file stem) without extension
-                let base_name = first_file
-                    .file_stem()
-                    .and_then(|s| s.to_str())
-                    .expect("Failed to extract base name");
-
-                // Generate the paths for the other files
-                let proof_path = proof_folder_dir.join(format!("{}.proof", base_name));
-                let public_input_path = proof_folder_dir.join(format!("{}.pub", base_name));
-                let vk_path = proof_folder_dir.join(format!("{}.vk", base_name));
-
-                let Ok(proof) = std::fs::read(&proof_path) else {
-                    continue;
-                };
-                let Ok(public_input) = std::fs::read(&public_input_path) else {
-                    continue;
-                };
-                let Ok(vk) = std::fs::read(&vk_path) else {
-                    continue;
-                };
+    proof_mix: &ProofMix,
+    proof_size_mix: &ProofSizeMix,
+    invalid_proofs_ratio: f64,
+) -> Option<ProofSampler> {
+    let corpus = match load_corpus(corpus_dir, default_addr) {
+        Ok(corpus) => corpus,
+        Err(err) => {
+            error!("{}", err);
+            return None;
+        }
+    };
+    if corpus.is_empty() {
+        error!("Verification data empty, not continuing");
+        return None;
+    }
+    match ProofSampler::new(corpus, proof_mix, proof_size_mix, invalid_proofs_ratio) {
+        Ok(proof_sampler) => Some(proof_sampler),
+        Err(err) => {
+            error!("{}", err);
+            None
+        }
+    }
+}
+
+pub async fn corpus(args: CorpusArgs) {
+    let result = match args.command {
+        CorpusCommands::Index(args) => index_corpus(&args.proofs_dir).map(|catalog| {
+            info!("Corpus cataloged");
+            log_catalog_summary(&catalog);
+        }),
+        CorpusCommands::List(args) => {
+            Catalog::read(&args.proofs_dir).map(|catalog| match catalog {
+                Some(catalog) => log_catalog_summary(&catalog),
+                None => info!("The corpus hasn't been cataloged, run the corpus index command"),
+            })
+        }
+        CorpusCommands::Fetch(args) => fetch_corpus(&args.url, &args.proofs_dir)
+            .await
+            .map(|fetched| info!("{} proofs fetched", fetched)),
+        CorpusCommands::AddInvalid(args) => {
+            add_invalid_proofs(&args.proofs_dir, args.proof_type, args.number_of_proofs).map(
+                |added| {
+                    for dir in added {
+                        info!("Invalid proof saved in {}", dir);
+                    }
+                },
+            )
+        }
+    };
+    if let Err(err) = result {
+        error!("{}", err);
+    }
+}
+
+pub async fn estimate_fee(args: EstimateFeeArgs) {
+    let model = FeeModel {
+        aggregator_gas_cost: args.aggregator_gas_cost,
+        aggregator_fee_percentage_multiplier: args.aggregator_fee_percentage_multiplier,
+    };
+    let estimate = match fee::estimate_fee(
+        &args.eth_rpc_url,
+        args.network.into(),
+        model,
+        args.blocks,
+        &args.batch_sizes,
+        args.target_batch_size,
+    )
+    .await
+    {
+        Ok(estimate) => estimate,
+        Err(err) => {
+            error!("Could not estimate fee: {}", err);
+            return;
+        }
+    };
+
+    if args.json {
+        match serde_json::to_string_pretty(&estimate) {
+            Ok(json) => println!("{}", json),
+            Err(err) => error!("Could not serialize estimate: {}", err),
+        }
+    } else {
+        estimate.log();
+    }
+}
+
+const CHAOS_VERIFICATION_POLL_INTERVAL: Duration = Duration::from_secs(3);
+
+/// Sends a valid proof as control, and then a broken copy of it per scenario, checking that the
+/// batcher rejects each broken proof or that the operators refuse to sign its batch. It exits
+/// with an error if any broken proof is verified, so it can guard changes to the verifiers.
+pub async fn chaos(args: ChaosArgs) {
+    let mut sender =
+        match load_senders(&args.eth_rpc_url, &args.private_keys_filepath, Some(1)).await {
+            Ok(mut senders) => senders.remove(0),
+            Err(err) => {
+                error!("{}", err);
+                return;
+            }
+        };
+    let corpus = match load_corpus(&args.proofs_dir, sender.wallet.address()) {
+        Ok(corpus) => corpus,
+        Err(err) => {
+            error!("{}", err);
+            return;
+        }
+    };
+    let valid_proofs: Vec<&VerificationData> = corpus
+        .iter()
+        .filter(|proof| proof.entry.valid && proof.entry.proof_type == args.proof_type)
+        .map(|proof| &proof.verification_data)
+        .collect();
+    let Some(&valid_proof) = valid_proofs.first() else {
+        error!(
+            "No valid {} proofs in the corpus to break",
+            args.proof_type.name()
+        );
+        return;
+    };
+    // the mismatched verification key is taken from another proof if there is one
+    let other_proof = valid_proofs.get(1).copied();
+
+    let network: Network = args.network.into();
+    let max_fee = U256::from_dec_str(&args.max_fee).expect("Invalid max fee");
+    let verification_timeout = Duration::from_secs(args.verification_timeout_secs);
+    let mut verified_batches = match VerifiedBatches::new(&args.eth_rpc_url, network).await {
+        Ok(verified_batches) => verified_batches,
+        Err(err) => {
+            error!("{}", err);
+            return;
+        }
+    };
+
+    // a broken proof not being verified only means something if valid proofs are
+    info!(
+        "Sending a valid {} proof as control",
+        args.proof_type.name()
+    );
+    let control_root = match send_chaos_proof(
+        &mut sender,
+        &args.batcher_url,
+        network,
+        valid_proof.clone(),
+        max_fee,
+    )
+    .await
+    {
+        Ok(batch_merkle_root) => batch_merkle_root,
+        Err(outcome) => {
+            error!("The control proof was not included: {:?}", outcome);
+            return;
+        }
+    };
+    let deadline = Instant::now() + verification_timeout;
+    loop {
+        tokio::time::sleep(CHAOS_VERIFICATION_POLL_INTERVAL).await;
+        if let Err(err) = verified_batches.update().await {
+            warn!("{}", err);
+        }
+        if verified_batches.is_verified(&control_root) {
+            info!("Control batch {} verified", hex::encode(control_root));
+            break;
+        }
+        if Instant::now() >= deadline {
+            error!(
+                "The control batch {} wasn't verified in time, check the operators and the aggregator",
+                hex::encode(control_root)
+            );
+            return;
+        }
+    }
+
+    let mut results = vec![];
+    let mut included = vec![];
+    for &scenario in &args.scenarios {
+        info!("Sending {} proof", scenario.name());
+        let verification_data =
+            scenario.apply(valid_proof, other_proof, args.oversized_payload_size);
+        let outcome = match send_chaos_proof(
+            &mut sender,
+            &args.batcher_url,
+            network,
+            verification_data,
+            max_fee,
+        )
+        .await
+        {
+            Ok(batch_merkle_root) => {
+                included.push((results.len(), batch_merkle_root));
+                ChaosOutcome::NotVerified {
+                    batch_merkle_root: hex::encode(batch_merkle_root),
+                }
+            }
+            Err(outcome) => outcome,
+        };
+        results.push(ChaosResult { scenario, outcome });
+    }
 
-                let verification_data = VerificationData {
-                    proving_system: ProvingSystemId::Groth16Bn254,
-                    proof,
-                    pub_input: Some(public_input),
-                    verification_key: Some(vk),
-                    vm_program_code: None,
-                    proof_generator_addr: default_addr,
+    if !included.is_empty() {
+        info!(
+            "Waiting {} seconds for the batches with broken proofs not to be verified",
+            args.verification_timeout_secs
+        );
+        let deadline = Instant::now() + verification_timeout;
+        while Instant::now() < deadline
+            && !included
+                .iter()
+                .all(|(_, root)| verified_batches.is_verified(root))
+        {
+            tokio::time::sleep(CHAOS_VERIFICATION_POLL_INTERVAL).await;
+            if let Err(err) = verified_batches.update().await {
+                warn!("{}", err);
+            }
+        }
+        for (i, batch_merkle_root) in included {
+            if verified_batches.is_verified(&batch_merkle_root) {
+                results[i].outcome = ChaosOutcome::Verified {
+                    batch_merkle_root: hex::encode(batch_merkle_root),
                 };
-                verifications_data.push(verification_data);
             }
         }
     }
 
-    verifications_data
+    for result in &results {
+        result.log();
+    }
+    if let Some(report_path) = &args.report_path {
+        match serde_json::to_string_pretty(&results) {
+            Ok(json) => match fs::write(report_path, json) {
+                Ok(()) => info!("Results saved in {}", report_path),
+                Err(err) => error!("Could not write results {}: {}", report_path, err),
+            },
+            Err(err) => error!("Could not serialize results: {}", err),
+        }
+    }
+
+    let failed = results.iter().filter(|result| !result.passed()).count();
+    if failed > 0 {
+        error!("{} of {} scenarios failed", failed, results.len());
+        std::process::exit(1);
+    }
+    info!("All {} scenarios passed", results.len());
+}
+
+/// Sends a single proof, returning the root of the batch it was included in, or the outcome of
+/// the scenario if the batcher didn't include it
+async fn send_chaos_proof(
+    sender: &mut Sender,
+    batcher_url: &str,
+    network: Network,
+    verification_data: VerificationData,
+    max_fee: U256,
+) -> Result<[u8; 32], ChaosOutcome> {
+    let (_, responses) = sender
+        .submit(batcher_url, network, &[verification_data], max_fee)
+        .await
+        .map_err(|reason| ChaosOutcome::Error { reason })?;
+    match responses.into_iter().next() {
+        Some(Ok(aligned_verification_data)) => Ok(aligned_verification_data.batch_merkle_root),
+        Some(Err(e)) => Err(ChaosOutcome::Rejected {
+            reason: failure_reason(&e),
+        }),
+        None => Err(ChaosOutcome::Error {
+            reason: "NoResponse".to_string(),
+        }),
+    }
 }
@@ -0,0 +1,396 @@
+use std::collections::BTreeMap;
+use std::fs;
+use std::path::Path;
+
+use aligned_sdk::core::types::VerificationData;
+use clap::ValueEnum;
+use ethers::types::Address;
+use ethers::utils::hex;
+use log::{info, warn};
+use serde::{Deserialize, Serialize};
+use sha3::{Digest, Keccak256};
+use url::Url;
+
+use crate::structs::{ProofSize, ProofType};
+
+/// The file cataloging the proofs of a corpus, stored in the corpus directory
+pub const CATALOG_FILE_NAME: &str = "corpus.json";
+
+/// A proof of the corpus. Each proof is stored in its own subdir of the corpus directory,
+/// named `<proof type>_<suffix>`.
+#[derive(Serialize, Deserialize, Debug, Clone)]
+pub struct CatalogEntry {
+    pub dir: String,
+    pub proof_type: ProofType,
+    pub size: ProofSize,
+    /// The size of the proof, including its public input, verification key and program
+    pub bytes: usize,
+    pub valid: bool,
+}
+
+#[derive(Serialize, Deserialize, Debug, Default)]
+pub struct Catalog {
+    pub proofs: Vec<CatalogEntry>,
+}
+
+impl Catalog {
+    /// Reads the catalog of the corpus directory, returning None if it hasn't been cataloged
+    pub fn read(corpus_dir: &str) -> Result<Option<Catalog>, String> {
+        let path = Path::new(corpus_dir).join(CATALOG_FILE_NAME);
+        if !path.exists() {
+            return Ok(None);
+        }
+        let content = fs::read(&path)
+            .map_err(|e| format!("Could not read corpus catalog {:?}: {}", path, e))?;
+        serde_json::from_slice(&content)
+            .map(Some)
+            .map_err(|e| format!("Invalid corpus catalog {:?}: {}", path, e))
+    }
+
+    pub fn write(&self, corpus_dir: &str) -> Result<(), String> {
+        let path = Path::new(corpus_dir).join(CATALOG_FILE_NAME);
+        let content = serde_json::to_vec_pretty(self)
+            .map_err(|e| format!("Could not serialize corpus catalog: {}", e))?;
+        fs::write(&path, content)
+            .map_err(|e| format!("Could not write corpus catalog {:?}: {}", path, e))
+    }
+
+    /// Returns the number of proofs by proof type, size and validity
+    pub fn summary(&self) -> BTreeMap<(String, String, bool), usize> {
+        let mut summary = BTreeMap::new();
+        for entry in &self.proofs {
+            let size = format!("{:?}", entry.size).to_lowercase();
+            *summary
+                .entry((entry.proof_type.name(), size, entry.valid))
+                .or_insert(0) += 1;
+        }
+        summary
+    }
+}
+
+/// A proof of the corpus loaded to be sent
+#[derive(Clone)]
+pub struct CorpusProof {
+    pub entry: CatalogEntry,
+    pub verification_data: VerificationData,
+}
+
+/// Loads the proofs of the corpus directory. If it hasn't been cataloged, all the proofs
+/// of its subdirs are loaded as valid proofs.
+pub fn load_corpus(corpus_dir: &str, default_addr: Address) -> Result<Vec<CorpusProof>, String> {
+    info!("Reading proofs from {:?}", corpus_dir);
+
+    let catalog = match Catalog::read(corpus_dir)? {
+        Some(catalog) => catalog,
+        None => scan_corpus(corpus_dir, &Catalog::default())?,
+    };
+
+    let mut proofs = vec![];
+    for entry in catalog.proofs {
+        let proof_dir = Path::new(corpus_dir).join(&entry.dir);
+        let Some(verification_data) = read_proof_dir(&proof_dir, entry.proof_type, default_addr)
+        else {
+            warn!(
+                "Could not read cataloged proof {:?}, skipping it",
+                proof_dir
+            );
+            continue;
+        };
+        proofs.push(CorpusProof {
+            entry,
+            verification_data,
+        });
+    }
+    Ok(proofs)
+}
+
+/// Catalogs the proofs of the corpus directory and writes the catalog, keeping the validity
+/// of the proofs that were already cataloged
+pub fn index_corpus(corpus_dir: &str) -> Result<Catalog, String> {
+    let previous_catalog = Catalog::read(corpus_dir)?.unwrap_or_default();
+    let catalog = scan_corpus(corpus_dir, &previous_catalog)?;
+    catalog.write(corpus_dir)?;
+    Ok(catalog)
+}
+
+fn scan_corpus(corpus_dir: &str, previous_catalog: &Catalog) -> Result<Catalog, String> {
+    let dir = fs::read_dir(corpus_dir)
+        .map_err(|e| format!("Could not read corpus directory {:?}: {}", corpus_dir, e))?;
+
+    let mut proofs = vec![];
+    for proof_dir in dir.filter_map(|entry| entry.ok().map(|e| e.path())) {
+        if !proof_dir.is_dir() {
+            continue;
+        }
+        let Some(dir_name) = proof_dir.file_name().and_then(|name| name.to_str()) else {
+            continue;
+        };
+        let Some(proof_type) = proof_type_of_dir(dir_name) else {
+            continue;
+        };
+        let Some(verification_data) = read_proof_dir(&proof_dir, proof_type, Address::zero())
+        else {
+            continue;
+        };
+
+        let valid = previous_catalog
+            .proofs
+            .iter()
+            .find(|entry| entry.dir == dir_name)
+            .map_or(true, |entry| entry.valid);
+        proofs.push(CatalogEntry {
+            dir: dir_name.to_string(),
+            proof_type,
+            size: ProofSize::of(&verification_data),
+            bytes: ProofSize::bytes(&verification_data),
+            valid,
+        });
+    }
+    proofs.sort_by(|a, b| a.dir.cmp(&b.dir));
+    Ok(Catalog { proofs })
+}
+
+/// Returns the proof type of a proof directory, from its `<proof type>_<suffix>` name
+pub fn proof_type_of_dir(dir_name: &str) -> Option<ProofType> {
+    dir_name
+        .rsplit_once('_')
+        .and_then(|(prefix, _)| <ProofType as ValueEnum>::from_str(prefix, true).ok())
+}
+
+/// Reads the verification data of a proof directory, with the proof, public input,
+/// verification key and program files told apart by their extension
+pub fn read_proof_dir(
+    proof_dir: &Path,
+    proof_type: ProofType,
+    default_addr: Address,
+) -> Option<VerificationData> {
+    let mut proof = None;
+    let mut pub_input = None;
+    let mut verification_key = None;
+    let mut vm_program_code = None;
+    let files = fs::read_dir(proof_dir)
+        .ok()?
+        .filter_map(|entry| entry.ok().map(|e| e.path()))
+        .filter(|path| path.is_file());
+    for file in files {
+        let content = match file.extension().and_then(|extension| extension.to_str()) {
+            Some("proof") => &mut proof,
+            Some("pub") => &mut pub_input,
+            Some("vk") => &mut verification_key,
+            // sp1 programs and risc0 image ids
+            Some("elf") | Some("bin") => &mut vm_program_code,
+            _ => continue,
+        };
+        let Ok(file_content) = fs::read(&file) else {
+            continue;
+        };
+        *content = Some(file_content);
+    }
+
+    Some(VerificationData {
+        proving_system: proof_type.proving_system(),
+        proof: proof?,
+        pub_input,
+        verification_key,
+        vm_program_code,
+        proof_generator_addr: default_addr,
+    })
+}
+
+/// Adds invalid proofs of a proof type to the corpus, copying its valid proofs with the proof
+/// corrupted, and returns the directories they were saved in
+pub fn add_invalid_proofs(
+    corpus_dir: &str,
+    proof_type: ProofType,
+    number_of_proofs: usize,
+) -> Result<Vec<String>, String> {
+    let mut catalog = index_corpus(corpus_dir)?;
+    let valid_proofs: Vec<CatalogEntry> = catalog
+        .proofs
+        .iter()
+        .filter(|entry| entry.proof_type == proof_type && entry.valid)
+        .cloned()
+        .collect();
+    if valid_proofs.is_empty() {
+        return Err(format!(
+            "No valid {} proofs in the corpus to corrupt",
+            proof_type.name()
+        ));
+    }
+
+    let mut added = vec![];
+    let mut suffix = 1;
+    for valid_proof in valid_proofs.iter().cycle().take(number_of_proofs) {
+        let mut dir_name = format!("{}_invalid-{}", proof_type.name(), suffix);
+        while Path::new(corpus_dir).join(&dir_name).exists() {
+            suffix += 1;
+            dir_name = format!("{}_invalid-{}", proof_type.name(), suffix);
+        }
+        let source_dir = Path::new(corpus_dir).join(&valid_proof.dir);
+        let target_dir = Path::new(corpus_dir).join(&dir_name);
+        copy_corrupting_proof(&source_dir, &target_dir)
+            .map_err(|e| format!("Could not corrupt proof {:?}: {}", source_dir, e))?;
+
+        catalog.proofs.push(CatalogEntry {
+            dir: dir_name.clone(),
+            valid: false,
+            ..valid_proof.clone()
+        });
+        added.push(dir_name);
+    }
+
+    catalog.write(corpus_dir)?;
+    Ok(added)
+}
+
+/// Copies the files of a proof directory, flipping the bits of a byte in the middle of the proof
+fn copy_corrupting_proof(source_dir: &Path, target_dir: &Path) -> std::io::Result<()> {
+    fs::create_dir_all(target_dir)?;
+    for entry in fs::read_dir(source_dir)? {
+        let path = entry?.path();
+        let Some(file_name) = path.file_name() else {
+            continue;
+        };
+        let mut content = fs::read(&path)?;
+        if path
+            .extension()
+            .is_some_and(|extension| extension == "proof")
+            && !content.is_empty()
+        {
+            let middle = content.len() / 2;
+            content[middle] ^= 0xff;
+        }
+        fs::write(target_dir.join(file_name), content)?;
+    }
+    Ok(())
+}
+
+/// The manifest of a published corpus
+#[derive(Deserialize, Debug)]
+pub struct CorpusManifest {
+    pub proofs: Vec<ManifestProof>,
+}
+
+#[derive(Deserialize, Debug)]
+pub struct ManifestProof {
+    pub dir: String,
+    pub proof_type: ProofType,
+    #[serde(default = "default_valid")]
+    pub valid: bool,
+    pub files: Vec<ManifestFile>,
+}
+
+fn default_valid() -> bool {
+    true
+}
+
+#[derive(Deserialize, Debug)]
+pub struct ManifestFile {
+    pub name: String,
+    /// The URL of the file, relative to the manifest URL or absolute
+    pub url: String,
+    /// The hex encoded keccak256 hash of the file, checked if set
+    pub keccak256: Option<String>,
+}
+
+/// Fetches the proofs of the published corpus manifest at `url` that aren't in the corpus
+/// directory yet, and catalogs them. Returns the number of proofs fetched.
+pub async fn fetch_corpus(url: &str, corpus_dir: &str) -> Result<usize, String> {
+    let manifest_url = Url::parse(url).map_err(|e| format!("Invalid corpus URL: {}", e))?;
+    let manifest: CorpusManifest = reqwest::get(manifest_url.clone())
+        .await
+        .and_then(|response| response.error_for_status())
+        .map_err(|e| format!("Could not fetch corpus manifest: {}", e))?
+        .json()
+        .await
+        .map_err(|e| format!("Invalid corpus manifest: {}", e))?;
+
+    fs::create_dir_all(corpus_dir)
+        .map_err(|e| format!("Could not create corpus directory: {}", e))?;
+
+    let mut fetched = vec![];
+    for proof in &manifest.proofs {
+        if proof_type_of_dir(&proof.dir) != Some(proof.proof_type) || !is_plain_name(&proof.dir) {
+            return Err(format!(
+                "Invalid directory {} for a {} proof, it must be named {}_<suffix>",
+                proof.dir,
+                proof.proof_type.name(),
+                proof.proof_type.name()
+            ));
+        }
+        let proof_dir = Path::new(corpus_dir).join(&proof.dir);
+        if proof_dir.exists() {
+            info!("Proof {} already in the corpus, skipping it", proof.dir);
+            continue;
+        }
+
+        info!("Fetching proof {}", proof.dir);
+        let mut files = vec![];
+        for file in &proof.files {
+            if !is_plain_name(&file.name) {
+                return Err(format!("Invalid file name {}", file.name));
+            }
+            files.push((&file.name, fetch_file(&manifest_url, file).await?));
+        }
+        // the proof directory is only created once all its files were fetched
+        fs::create_dir_all(&proof_dir)
+            .map_err(|e| format!("Could not create proof directory: {}", e))?;
+        for (name, content) in files {
+            fs::write(proof_dir.join(name), content)
+                .map_err(|e| format!("Could not write proof file {}: {}", name, e))?;
+        }
+        fetched.push(proof);
+    }
+
+    let mut catalog = index_corpus(corpus_dir)?;
+    for entry in catalog.proofs.iter_mut() {
+        if let Some(proof) = fetched.iter().find(|proof| proof.dir == entry.dir) {
+            entry.valid = proof.valid;
+        }
+    }
+    catalog.write(corpus_dir)?;
+    Ok(fetched.len())
+}
+
+async fn fetch_file(manifest_url: &Url, file: &ManifestFile) -> Result<Vec<u8>, String> {
+    let file_url = manifest_url
+        .join(&file.url)
+        .map_err(|e| format!("Invalid URL {} for file {}: {}", file.url, file.name, e))?;
+    let content = reqwest::get(file_url)
+        .await
+        .and_then(|response| response.error_for_status())
+        .map_err(|e| format!("Could not fetch file {}: {}", file.name, e))?
+        .bytes()
+        .await
+        .map_err(|e| format!("Could not fetch file {}: {}", file.name, e))?;
+
+    if let Some(expected_hash) = &file.keccak256 {
+        let hash = hex::encode(Keccak256::digest(&content));
+        if hash != expected_hash.trim_start_matches("0x").to_lowercase() {
+            return Err(format!(
+                "Hash of file {} is {}, expected {}",
+                file.name, hash, expected_hash
+            ));
+        }
+    }
+    Ok(content.to_vec())
+}
+
+/// Returns whether a name from a manifest can be safely used as a file or directory name
+fn is_plain_name(name: &str) -> bool {
+    !name.is_empty() && name != "." && name != ".." && !name.contains(['/', '\\'])
+}
+
+/// Logs the number of proofs of the catalog by proof type, size and validity
+pub fn log_catalog_summary(catalog: &Catalog) {
+    info!("{} proofs in the corpus", catalog.proofs.len());
+    for ((proof_type, size, valid), count) in catalog.summary() {
+        info!(
+            "{} {} {} proofs: {}",
+            if valid { "valid" } else { "invalid" },
+            size,
+            proof_type,
+            count
+        );
+    }
+}
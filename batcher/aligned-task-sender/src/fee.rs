@@ -0,0 +1,312 @@
+use std::sync::Arc;
+
+use aligned_sdk::core::constants::{
+    ADDITIONAL_SUBMISSION_GAS_COST_PER_PROOF, BATCHER_SUBMISSION_BASE_GAS_COST,
+    DEFAULT_AGGREGATOR_FEE_PERCENTAGE_MULTIPLIER, DEFAULT_AGGREGATOR_GAS_COST,
+    GAS_PRICE_PERCENTAGE_MULTIPLIER, PERCENTAGE_DIVIDER,
+    RESPOND_TO_TASK_FEE_LIMIT_PERCENTAGE_MULTIPLIER,
+};
+use aligned_sdk::core::types::Network;
+use aligned_sdk::eth::batcher_payment_service::{BatcherPaymentServiceContract, CreateNewTaskCall};
+use aligned_sdk::sdk::get_payment_service_address;
+use ethers::abi::AbiDecode;
+use ethers::prelude::*;
+use ethers::utils::format_ether;
+use log::{info, warn};
+use serde::{Serialize, Serializer};
+
+/// The gas costs the batcher charges the proofs of a batch for, as in its config
+#[derive(Debug, Clone, Copy)]
+pub struct FeeModel {
+    pub aggregator_gas_cost: u128,
+    pub aggregator_fee_percentage_multiplier: u128,
+}
+
+impl Default for FeeModel {
+    fn default() -> Self {
+        FeeModel {
+            aggregator_gas_cost: DEFAULT_AGGREGATOR_GAS_COST,
+            aggregator_fee_percentage_multiplier: DEFAULT_AGGREGATOR_FEE_PERCENTAGE_MULTIPLIER,
+        }
+    }
+}
+
+impl FeeModel {
+    /// The gas price the batcher creates tasks with, the network gas price with a margin
+    pub fn batcher_gas_price(network_gas_price: U256) -> U256 {
+        network_gas_price * U256::from(GAS_PRICE_PERCENTAGE_MULTIPLIER)
+            / U256::from(PERCENTAGE_DIVIDER)
+    }
+
+    /// The fee sent to the aggregator to respond to the task of a batch
+    pub fn fee_for_aggregator(&self, gas_price: U256) -> U256 {
+        U256::from(self.aggregator_gas_cost)
+            * gas_price
+            * U256::from(self.aggregator_fee_percentage_multiplier)
+            / U256::from(PERCENTAGE_DIVIDER)
+    }
+
+    /// The max the aggregator can spend of the fee for the aggregator responding to the task
+    pub fn respond_to_task_fee_limit(&self, gas_price: U256) -> U256 {
+        self.fee_for_aggregator(gas_price)
+            * U256::from(RESPOND_TO_TASK_FEE_LIMIT_PERCENTAGE_MULTIPLIER)
+            / U256::from(PERCENTAGE_DIVIDER)
+    }
+
+    /// The fee each proof of a batch of batch_size proofs pays, splitting the cost of creating
+    /// and responding to the task among them
+    pub fn fee_per_proof(&self, gas_price: U256, batch_size: usize) -> U256 {
+        let batch_size = batch_size.max(1) as u128;
+        let constant_gas_cost = (self.aggregator_fee_percentage_multiplier
+            * self.aggregator_gas_cost)
+            / PERCENTAGE_DIVIDER
+            + BATCHER_SUBMISSION_BASE_GAS_COST;
+        let gas_per_proof = (constant_gas_cost
+            + ADDITIONAL_SUBMISSION_GAS_COST_PER_PROOF * batch_size)
+            / batch_size;
+        U256::from(gas_per_proof) * gas_price
+    }
+}
+
+/// The network gas prices of the last blocks, each the base fee plus the median priority fee
+#[derive(Debug, Clone, Serialize)]
+pub struct GasPrices {
+    pub blocks: usize,
+    #[serde(serialize_with = "wei")]
+    pub current: U256,
+    #[serde(serialize_with = "wei")]
+    pub recent_median: U256,
+    #[serde(serialize_with = "wei")]
+    pub recent_max: U256,
+}
+
+/// The sizes and fees of the batches created in the last blocks, decoded from the createNewTask
+/// transactions of the BatcherPaymentService
+#[derive(Debug, Clone, Serialize)]
+pub struct RecentBatches {
+    pub blocks: u64,
+    pub count: usize,
+    pub median_size: Option<usize>,
+    pub max_size: Option<usize>,
+    #[serde(serialize_with = "optional_wei")]
+    pub median_fee_per_proof: Option<U256>,
+}
+
+#[derive(Debug, Clone, Serialize)]
+pub struct BatchSizeFee {
+    pub batch_size: usize,
+    #[serde(serialize_with = "wei")]
+    pub fee_per_proof: U256,
+    #[serde(serialize_with = "wei")]
+    pub fee_per_proof_at_recent_max: U256,
+}
+
+#[derive(Debug, Clone, Serialize)]
+pub struct FeeEstimate {
+    pub gas_prices: GasPrices,
+    pub recent_batches: RecentBatches,
+    #[serde(serialize_with = "wei")]
+    pub fee_for_aggregator: U256,
+    #[serde(serialize_with = "wei")]
+    pub respond_to_task_fee_limit: U256,
+    pub fees_by_batch_size: Vec<BatchSizeFee>,
+    /// The batch size the suggested max fee pays for
+    pub target_batch_size: usize,
+    #[serde(serialize_with = "wei")]
+    pub suggested_max_fee: U256,
+}
+
+fn wei<S: Serializer>(value: &U256, serializer: S) -> Result<S::Ok, S::Error> {
+    serializer.serialize_str(&value.to_string())
+}
+
+fn optional_wei<S: Serializer>(value: &Option<U256>, serializer: S) -> Result<S::Ok, S::Error> {
+    match value {
+        Some(value) => wei(value, serializer),
+        None => serializer.serialize_none(),
+    }
+}
+
+fn median<T: Ord + Copy>(values: &mut [T]) -> Option<T> {
+    values.sort();
+    values.get(values.len() / 2).copied()
+}
+
+/// Estimates the fee per proof the batcher charges at the current and recent gas prices, for
+/// each of the batch sizes. The suggested max fee pays for a proof in a batch of the target
+/// batch size, or of the median size of the recent batches if not set, at the highest recent
+/// gas price, so it's enough for the proof to be included unless gas spikes or batches shrink.
+pub async fn estimate_fee(
+    eth_rpc_url: &str,
+    network: Network,
+    model: FeeModel,
+    blocks: u64,
+    batch_sizes: &[usize],
+    target_batch_size: Option<usize>,
+) -> Result<FeeEstimate, String> {
+    let provider = Provider::<Http>::try_from(eth_rpc_url)
+        .map_err(|e| format!("Could not connect to eth rpc: {}", e))?;
+    let gas_prices = fetch_gas_prices(&provider, blocks).await?;
+    let recent_batches = fetch_recent_batches(&provider, network, blocks).await?;
+
+    let gas_price = FeeModel::batcher_gas_price(gas_prices.current);
+    let recent_max_gas_price = FeeModel::batcher_gas_price(gas_prices.recent_max);
+    let fees_by_batch_size = batch_sizes
+        .iter()
+        .map(|&batch_size| BatchSizeFee {
+            batch_size,
+            fee_per_proof: model.fee_per_proof(gas_price, batch_size),
+            fee_per_proof_at_recent_max: model.fee_per_proof(recent_max_gas_price, batch_size),
+        })
+        .collect();
+    let target_batch_size = target_batch_size
+        .or(recent_batches.median_size)
+        .unwrap_or(1);
+
+    Ok(FeeEstimate {
+        fee_for_aggregator: model.fee_for_aggregator(gas_price),
+        respond_to_task_fee_limit: model.respond_to_task_fee_limit(gas_price),
+        fees_by_batch_size,
+        target_batch_size,
+        suggested_max_fee: model.fee_per_proof(recent_max_gas_price, target_batch_size),
+        gas_prices,
+        recent_batches,
+    })
+}
+
+async fn fetch_gas_prices(provider: &Provider<Http>, blocks: u64) -> Result<GasPrices, String> {
+    let current = provider
+        .get_gas_price()
+        .await
+        .map_err(|e| format!("Could not get gas price: {}", e))?;
+    let history = provider
+        .fee_history(blocks, BlockNumber::Latest, &[50.0])
+        .await
+        .map_err(|e| format!("Could not get fee history: {}", e))?;
+
+    // The base fees include the one of the next block, which has no rewards yet
+    let mut prices: Vec<U256> = history
+        .base_fee_per_gas
+        .iter()
+        .zip(history.reward.iter())
+        .map(|(base_fee, reward)| *base_fee + reward.first().copied().unwrap_or_default())
+        .collect();
+    let blocks = prices.len();
+    let recent_max = prices.iter().copied().max().unwrap_or(current).max(current);
+    let recent_median = median(&mut prices).unwrap_or(current);
+    Ok(GasPrices {
+        blocks,
+        current,
+        recent_median,
+        recent_max,
+    })
+}
+
+async fn fetch_recent_batches(
+    provider: &Provider<Http>,
+    network: Network,
+    blocks: u64,
+) -> Result<RecentBatches, String> {
+    let payment_service = BatcherPaymentServiceContract::new(
+        get_payment_service_address(network),
+        Arc::new(provider.clone()),
+    );
+    let to_block = provider
+        .get_block_number()
+        .await
+        .map_err(|e| format!("Could not get block number: {}", e))?;
+    let from_block = to_block.saturating_sub(U64::from(blocks));
+    let events = payment_service
+        .task_created_filter()
+        .from_block(from_block)
+        .to_block(to_block)
+        .query_with_meta()
+        .await
+        .map_err(|e| format!("Could not get TaskCreated events: {}", e))?;
+
+    let mut sizes = Vec::with_capacity(events.len());
+    let mut fees_per_proof = Vec::with_capacity(events.len());
+    for (_, meta) in events {
+        let transaction = match provider.get_transaction(meta.transaction_hash).await {
+            Ok(Some(transaction)) => transaction,
+            Ok(None) => continue,
+            Err(e) => {
+                warn!(
+                    "Could not get transaction {:?}: {}",
+                    meta.transaction_hash, e
+                );
+                continue;
+            }
+        };
+        match CreateNewTaskCall::decode(&transaction.input) {
+            Ok(call) => {
+                sizes.push(call.proof_submitters.len());
+                fees_per_proof.push(call.fee_per_proof);
+            }
+            Err(e) => warn!(
+                "Could not decode createNewTask transaction {:?}: {}",
+                meta.transaction_hash, e
+            ),
+        }
+    }
+
+    Ok(RecentBatches {
+        blocks,
+        count: sizes.len(),
+        max_size: sizes.iter().copied().max(),
+        median_size: median(&mut sizes),
+        median_fee_per_proof: median(&mut fees_per_proof),
+    })
+}
+
+impl FeeEstimate {
+    pub fn log(&self) {
+        info!(
+            "Gas price: {} gwei, median of the last {} blocks {} gwei, max {} gwei",
+            format_gwei(self.gas_prices.current),
+            self.gas_prices.blocks,
+            format_gwei(self.gas_prices.recent_median),
+            format_gwei(self.gas_prices.recent_max)
+        );
+        match (
+            self.recent_batches.median_size,
+            self.recent_batches.max_size,
+            self.recent_batches.median_fee_per_proof,
+        ) {
+            (Some(median_size), Some(max_size), Some(median_fee_per_proof)) => info!(
+                "Recent batches: {}, median size {}, max size {}, median fee per proof {} ether",
+                self.recent_batches.count,
+                median_size,
+                max_size,
+                format_ether(median_fee_per_proof)
+            ),
+            _ => info!(
+                "No batches created in the last {} blocks",
+                self.recent_batches.blocks
+            ),
+        }
+        info!(
+            "Fee for aggregator: {} ether, respond to task fee limit: {} ether",
+            format_ether(self.fee_for_aggregator),
+            format_ether(self.respond_to_task_fee_limit)
+        );
+        for fee in &self.fees_by_batch_size {
+            info!(
+                "Batch of {} proofs: {} ether per proof, {} ether at the recent max gas price",
+                fee.batch_size,
+                format_ether(fee.fee_per_proof),
+                format_ether(fee.fee_per_proof_at_recent_max)
+            );
+        }
+        info!(
+            "Suggested max fee for a batch of {} proofs: {} wei ({} ether)",
+            self.target_batch_size,
+            self.suggested_max_fee,
+            format_ether(self.suggested_max_fee)
+        );
+    }
+}
+
+fn format_gwei(gas_price: U256) -> String {
+    ethers::utils::format_units(gas_price, "gwei").unwrap_or_else(|_| gas_price.to_string())
+}
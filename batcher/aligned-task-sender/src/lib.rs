@@ -1,2 +1,6 @@
+pub mod chaos;
 pub mod commands;
+pub mod corpus;
+pub mod fee;
+pub mod report;
 pub mod structs;
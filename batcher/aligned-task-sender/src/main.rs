@@ -17,5 +17,9 @@ async fn main() {
         TaskSenderCommands::GenerateProofs(args) => commands::generate_proofs(args).await,
         TaskSenderCommands::SendInfiniteProofs(args) => commands::send_infinite_proofs(args).await,
         TaskSenderCommands::TestConnections(args) => commands::test_connection(args).await,
+        TaskSenderCommands::SendProofsLoadTest(args) => commands::send_proofs_load_test(args).await,
+        TaskSenderCommands::Corpus(args) => commands::corpus(args).await,
+        TaskSenderCommands::EstimateFee(args) => commands::estimate_fee(args).await,
+        TaskSenderCommands::Chaos(args) => commands::chaos(args).await,
     }
 }
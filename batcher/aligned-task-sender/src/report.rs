@@ -0,0 +1,373 @@
+use std::collections::{BTreeMap, HashSet};
+use std::fs;
+use std::sync::{Arc, Mutex};
+use std::time::{Duration, Instant};
+
+use aligned_sdk::core::errors::SubmitError;
+use aligned_sdk::core::types::{AlignedVerificationData, Network, VerificationData};
+use aligned_sdk::eth::aligned_service_manager::AlignedLayerServiceManagerContract;
+use aligned_sdk::sdk::get_aligned_service_manager_address;
+use clap::ValueEnum;
+use ethers::prelude::*;
+use ethers::utils::hex;
+use log::{info, warn};
+use serde::Serialize;
+
+/// The failure reason of the proofs included in a batch that wasn't verified in time
+const VERIFICATION_TIMEOUT_REASON: &str = "VerificationTimeout";
+
+#[derive(Clone, Copy, Debug, PartialEq, Eq, ValueEnum)]
+pub enum ReportFormat {
+    Json,
+    Csv,
+}
+
+/// A proof sent, tracked from its submission until its batch is verified on chain
+struct ProofRecord {
+    sender: Address,
+    proving_system: String,
+    submitted_at: Instant,
+    included_at: Option<Instant>,
+    batch_merkle_root: Option<[u8; 32]>,
+    verified_at: Option<Instant>,
+    failure: Option<String>,
+}
+
+/// Tracks each proof sent through its batch inclusion and verification
+#[derive(Default)]
+pub struct ProofTracker {
+    records: Vec<ProofRecord>,
+}
+
+impl ProofTracker {
+    /// Records the proofs of a submission, returning the index of the first one
+    pub fn submitted(
+        &mut self,
+        sender: Address,
+        verification_data: &[VerificationData],
+        submitted_at: Instant,
+    ) -> usize {
+        let first = self.records.len();
+        self.records
+            .extend(verification_data.iter().map(|data| ProofRecord {
+                sender,
+                proving_system: data.proving_system.to_string(),
+                submitted_at,
+                included_at: None,
+                batch_merkle_root: None,
+                verified_at: None,
+                failure: None,
+            }));
+        first
+    }
+
+    /// Records the batcher responses of the submission starting at `first`. The proofs without
+    /// a response failed, as the batcher stops answering after the first error.
+    pub fn responded(
+        &mut self,
+        first: usize,
+        number_of_proofs: usize,
+        responses: &[Result<AlignedVerificationData, SubmitError>],
+        responded_at: Instant,
+    ) {
+        for (i, record) in self.records[first..first + number_of_proofs]
+            .iter_mut()
+            .enumerate()
+        {
+            match responses.get(i) {
+                Some(Ok(aligned_verification_data)) => {
+                    record.included_at = Some(responded_at);
+                    record.batch_merkle_root = Some(aligned_verification_data.batch_merkle_root);
+                }
+                Some(Err(e)) => record.failure = Some(failure_reason(e)),
+                None => record.failure = Some("NoResponse".to_string()),
+            }
+        }
+    }
+
+    /// Records the submission starting at `first` as failed before reaching the batcher
+    pub fn submission_failed(&mut self, first: usize, number_of_proofs: usize, reason: &str) {
+        for record in &mut self.records[first..first + number_of_proofs] {
+            record.failure = Some(reason.to_string());
+        }
+    }
+
+    /// Records the proofs of a batch as verified, returning whether any proof sent was in it
+    pub fn batch_verified(&mut self, batch_merkle_root: [u8; 32], verified_at: Instant) -> bool {
+        let mut found = false;
+        for record in &mut self.records {
+            if record.batch_merkle_root == Some(batch_merkle_root) && record.verified_at.is_none() {
+                record.verified_at = Some(verified_at);
+                found = true;
+            }
+        }
+        found
+    }
+
+    /// Returns the batches with proofs sent that haven't been verified yet
+    pub fn pending_batches(&self) -> HashSet<[u8; 32]> {
+        self.records
+            .iter()
+            .filter(|record| record.verified_at.is_none())
+            .filter_map(|record| record.batch_merkle_root)
+            .collect()
+    }
+
+    pub fn proofs_sent(&self) -> usize {
+        self.records.len()
+    }
+
+    /// Builds the report of the proofs sent. The proofs included in batches that weren't
+    /// verified are reported as failed by verification timeout.
+    pub fn report(&self, duration: Duration) -> Report {
+        let mut inclusion_latencies = vec![];
+        let mut verification_latencies = vec![];
+        let mut failures = BTreeMap::new();
+        let mut failures_by_proving_system = BTreeMap::new();
+        let mut senders = HashSet::new();
+        let mut batches = HashSet::new();
+
+        for record in &self.records {
+            if let Some(included_at) = record.included_at {
+                inclusion_latencies.push(included_at.duration_since(record.submitted_at));
+                senders.insert(record.sender);
+            }
+            if let Some(batch_merkle_root) = record.batch_merkle_root {
+                batches.insert(batch_merkle_root);
+            }
+            let failure = match (&record.failure, record.batch_merkle_root) {
+                (Some(failure), _) => Some(failure.as_str()),
+                (None, Some(_)) if record.verified_at.is_none() => {
+                    Some(VERIFICATION_TIMEOUT_REASON)
+                }
+                _ => None,
+            };
+            match (failure, record.verified_at) {
+                (Some(failure), _) => {
+                    *failures.entry(failure.to_string()).or_insert(0) += 1;
+                    *failures_by_proving_system
+                        .entry(record.proving_system.clone())
+                        .or_insert(0) += 1;
+                }
+                (None, Some(verified_at)) => {
+                    verification_latencies.push(verified_at.duration_since(record.submitted_at))
+                }
+                (None, None) => {}
+            }
+        }
+
+        Report {
+            duration_secs: duration.as_secs_f64(),
+            proofs_sent: self.records.len(),
+            proofs_included: inclusion_latencies.len(),
+            proofs_verified: verification_latencies.len(),
+            proofs_failed: failures.values().sum(),
+            senders: senders.len(),
+            batches: batches.len(),
+            inclusion_latency: LatencySummary::of(inclusion_latencies),
+            verification_latency: LatencySummary::of(verification_latencies),
+            failures,
+            failures_by_proving_system,
+        }
+    }
+}
+
+/// Returns the name of the error variant, which is used to group the failures
+pub fn failure_reason(error: &SubmitError) -> String {
+    let debug = format!("{:?}", error);
+    debug
+        .split(|c: char| !c.is_alphanumeric())
+        .next()
+        .unwrap_or(&debug)
+        .to_string()
+}
+
+/// The latency percentiles of a set of proofs, in seconds
+#[derive(Serialize, Debug, Default)]
+pub struct LatencySummary {
+    pub count: usize,
+    pub min_secs: Option<f64>,
+    pub p50_secs: Option<f64>,
+    pub p95_secs: Option<f64>,
+    pub p99_secs: Option<f64>,
+    pub max_secs: Option<f64>,
+}
+
+impl LatencySummary {
+    fn of(mut latencies: Vec<Duration>) -> Self {
+        latencies.sort();
+        Self {
+            count: latencies.len(),
+            min_secs: latencies.first().map(Duration::as_secs_f64),
+            p50_secs: percentile(&latencies, 50),
+            p95_secs: percentile(&latencies, 95),
+            p99_secs: percentile(&latencies, 99),
+            max_secs: latencies.last().map(Duration::as_secs_f64),
+        }
+    }
+}
+
+/// Returns the nearest-rank percentile of the sorted latencies
+fn percentile(sorted: &[Duration], percentile: usize) -> Option<f64> {
+    if sorted.is_empty() {
+        return None;
+    }
+    let rank = (percentile * sorted.len()).div_ceil(100).max(1);
+    Some(sorted[rank - 1].as_secs_f64())
+}
+
+/// The report of a run, with the latencies from the submission of each proof to the batcher
+/// response and to the verification of its batch, and the failures by reason and proving system
+#[derive(Serialize, Debug)]
+pub struct Report {
+    pub duration_secs: f64,
+    pub proofs_sent: usize,
+    pub proofs_included: usize,
+    pub proofs_verified: usize,
+    pub proofs_failed: usize,
+    pub senders: usize,
+    pub batches: usize,
+    pub inclusion_latency: LatencySummary,
+    pub verification_latency: LatencySummary,
+    pub failures: BTreeMap<String, usize>,
+    pub failures_by_proving_system: BTreeMap<String, usize>,
+}
+
+impl Report {
+    pub fn log(&self) {
+        info!(
+            "Proofs sent: {}, included: {} from {} senders in {} batches, verified: {}, failed: {}",
+            self.proofs_sent,
+            self.proofs_included,
+            self.senders,
+            self.batches,
+            self.proofs_verified,
+            self.proofs_failed
+        );
+        for (name, latency) in [
+            ("Inclusion", &self.inclusion_latency),
+            ("Verification", &self.verification_latency),
+        ] {
+            if let (Some(p50), Some(p95), Some(p99)) =
+                (latency.p50_secs, latency.p95_secs, latency.p99_secs)
+            {
+                info!(
+                    "{} latency: p50 {:.2}s, p95 {:.2}s, p99 {:.2}s over {} proofs",
+                    name, p50, p95, p99, latency.count
+                );
+            }
+        }
+        for (reason, count) in &self.failures {
+            warn!("{} proofs failed with {}", count, reason);
+        }
+    }
+
+    /// Returns the report as `metric,value` rows
+    fn to_csv(&self) -> String {
+        let mut rows = vec![
+            ("duration_secs".to_string(), self.duration_secs.to_string()),
+            ("proofs_sent".to_string(), self.proofs_sent.to_string()),
+            (
+                "proofs_included".to_string(),
+                self.proofs_included.to_string(),
+            ),
+            (
+                "proofs_verified".to_string(),
+                self.proofs_verified.to_string(),
+            ),
+            ("proofs_failed".to_string(), self.proofs_failed.to_string()),
+            ("senders".to_string(), self.senders.to_string()),
+            ("batches".to_string(), self.batches.to_string()),
+        ];
+        for (name, latency) in [
+            ("inclusion_latency", &self.inclusion_latency),
+            ("verification_latency", &self.verification_latency),
+        ] {
+            rows.push((format!("{}_count", name), latency.count.to_string()));
+            for (stat, value) in [
+                ("min", latency.min_secs),
+                ("p50", latency.p50_secs),
+                ("p95", latency.p95_secs),
+                ("p99", latency.p99_secs),
+                ("max", latency.max_secs),
+            ] {
+                rows.push((
+                    format!("{}_{}_secs", name, stat),
+                    value.map(|value| value.to_string()).unwrap_or_default(),
+                ));
+            }
+        }
+        for (reason, count) in &self.failures {
+            rows.push((format!("failures_reason_{}", reason), count.to_string()));
+        }
+        for (proving_system, count) in &self.failures_by_proving_system {
+            rows.push((
+                format!("failures_proving_system_{}", proving_system),
+                count.to_string(),
+            ));
+        }
+
+        let mut csv = String::from("metric,value\n");
+        for (metric, value) in rows {
+            csv.push_str(&format!("{},{}\n", metric, value));
+        }
+        csv
+    }
+
+    pub fn write(&self, path: &str, format: ReportFormat) -> Result<(), String> {
+        let content = match format {
+            ReportFormat::Json => serde_json::to_string_pretty(self)
+                .map_err(|e| format!("Could not serialize report: {}", e))?,
+            ReportFormat::Csv => self.to_csv(),
+        };
+        fs::write(path, content).map_err(|e| format!("Could not write report {}: {}", path, e))
+    }
+}
+
+/// Watches the `BatchVerified` events of the Aligned service manager from `from_block`,
+/// recording the verification of the batches with tracked proofs. It runs until aborted.
+pub async fn watch_verified_batches(
+    tracker: Arc<Mutex<ProofTracker>>,
+    eth_rpc_url: &str,
+    network: Network,
+    from_block: U64,
+    poll_interval: Duration,
+) -> Result<(), String> {
+    let provider = Provider::<Http>::try_from(eth_rpc_url)
+        .map_err(|e| format!("Could not connect to eth rpc: {}", e))?;
+    let service_manager = AlignedLayerServiceManagerContract::new(
+        get_aligned_service_manager_address(network),
+        Arc::new(provider.clone()),
+    );
+
+    let mut from_block = from_block;
+    loop {
+        tokio::time::sleep(poll_interval).await;
+
+        match provider.get_block_number().await {
+            Ok(to_block) if to_block >= from_block => {
+                match service_manager
+                    .batch_verified_filter()
+                    .from_block(from_block)
+                    .to_block(to_block)
+                    .query()
+                    .await
+                {
+                    Ok(events) => {
+                        let verified_at = Instant::now();
+                        let mut tracker = tracker.lock().unwrap();
+                        for event in events {
+                            if tracker.batch_verified(event.batch_merkle_root, verified_at) {
+                                info!("Batch {} verified", hex::encode(event.batch_merkle_root));
+                            }
+                        }
+                        from_block = to_block + 1;
+                    }
+                    Err(e) => warn!("Could not get BatchVerified events: {}", e),
+                }
+            }
+            Ok(_) => {}
+            Err(e) => warn!("Could not get block number: {}", e),
+        }
+    }
+}
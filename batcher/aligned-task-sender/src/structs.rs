@@ -1,7 +1,14 @@
-use aligned_sdk::core::types::Network;
+use std::fmt::Debug;
+use std::str::FromStr;
+
+use aligned_sdk::core::types::{Network, ProvingSystemId, VerificationData};
 use clap::Parser;
 use clap::Subcommand;
 use clap::ValueEnum;
+use serde::{Deserialize, Serialize};
+
+use crate::chaos::ChaosScenario;
+use crate::report::ReportFormat;
 
 #[derive(Parser, Debug)]
 #[command(version, about, long_about = None)]
@@ -20,6 +27,14 @@ pub enum TaskSenderCommands {
     SendInfiniteProofs(SendInfiniteProofsArgs),
     #[clap(about = "Generates wallets and funds it in aligned from one wallet")]
     GenerateAndFundWallets(GenerateAndFundWalletsArgs),
+    #[clap(about = "Send proofs at a target rate and report the achieved throughput")]
+    SendProofsLoadTest(SendProofsLoadTestArgs),
+    #[clap(about = "Manage the corpus of proofs to send")]
+    Corpus(CorpusArgs),
+    #[clap(about = "Estimate the fee per proof from the recent gas prices and batches")]
+    EstimateFee(EstimateFeeArgs),
+    #[clap(about = "Send broken proofs and check that they are refused")]
+    Chaos(ChaosArgs),
 }
 
 #[derive(Parser, Debug)]
@@ -36,11 +51,126 @@ pub struct GenerateProofsArgs {
     pub dir_to_save_proofs: String,
 }
 
-#[derive(Parser, Clone, Debug, ValueEnum)]
+#[derive(Parser, Clone, Copy, Debug, PartialEq, Eq, Hash, ValueEnum, Serialize, Deserialize)]
 pub enum ProofType {
+    #[value(name = "groth16")]
+    #[serde(rename = "groth16")]
     Groth16,
+    #[value(name = "groth16-bls12-381")]
+    #[serde(rename = "groth16-bls12-381")]
+    Groth16Bls12_381,
+    #[value(name = "plonk-bn254")]
+    #[serde(rename = "plonk-bn254")]
+    PlonkBn254,
+    #[value(name = "plonk-bls12-381")]
+    #[serde(rename = "plonk-bls12-381")]
+    PlonkBls12_381,
+    #[value(name = "sp1")]
+    #[serde(rename = "sp1")]
+    SP1,
+    #[value(name = "risc0")]
+    #[serde(rename = "risc0")]
+    Risc0,
+}
+
+impl ProofType {
+    pub fn proving_system(&self) -> ProvingSystemId {
+        match self {
+            ProofType::Groth16 => ProvingSystemId::Groth16Bn254,
+            ProofType::Groth16Bls12_381 => ProvingSystemId::Groth16Bls12_381,
+            ProofType::PlonkBn254 => ProvingSystemId::GnarkPlonkBn254,
+            ProofType::PlonkBls12_381 => ProvingSystemId::GnarkPlonkBls12_381,
+            ProofType::SP1 => ProvingSystemId::SP1,
+            ProofType::Risc0 => ProvingSystemId::Risc0,
+        }
+    }
+
+    /// The name of the proof type, which prefixes the directories its proofs are saved in
+    pub fn name(&self) -> String {
+        self.to_possible_value()
+            .expect("Proof types are not skipped")
+            .get_name()
+            .to_string()
+    }
+}
+
+/// The size of a proof, including its public input, verification key and program
+#[derive(Clone, Copy, Debug, PartialEq, Eq, Hash, ValueEnum, Serialize, Deserialize)]
+#[serde(rename_all = "lowercase")]
+pub enum ProofSize {
+    /// Less than 64KiB, like gnark proofs
+    Small,
+    /// Less than 1MiB, like risc0 proofs
+    Medium,
+    /// 1MiB or more, like sp1 proofs
+    Large,
+}
+
+impl ProofSize {
+    pub fn of(verification_data: &VerificationData) -> ProofSize {
+        match Self::bytes(verification_data) {
+            size if size < 64 * 1024 => ProofSize::Small,
+            size if size < 1024 * 1024 => ProofSize::Medium,
+            _ => ProofSize::Large,
+        }
+    }
+
+    pub fn bytes(verification_data: &VerificationData) -> usize {
+        verification_data.proof.len()
+            + verification_data.pub_input.as_ref().map_or(0, Vec::len)
+            + verification_data
+                .verification_key
+                .as_ref()
+                .map_or(0, Vec::len)
+            + verification_data
+                .vm_program_code
+                .as_ref()
+                .map_or(0, Vec::len)
+    }
+}
+
+/// The weights of a set of values, parsed from comma separated `<value>:<weight>` pairs.
+/// The weight defaults to 1 if omitted.
+#[derive(Clone, Debug)]
+pub struct WeightedMix<T>(pub Vec<(T, u32)>);
+
+impl<T: ValueEnum + Debug> FromStr for WeightedMix<T> {
+    type Err = String;
+
+    fn from_str(s: &str) -> Result<Self, Self::Err> {
+        let mut weights = vec![];
+        for entry in s.split(',') {
+            let (value, weight) = entry.split_once(':').unwrap_or((entry, "1"));
+            let value = <T as ValueEnum>::from_str(value.trim(), true)?;
+            let weight = weight
+                .trim()
+                .parse::<u32>()
+                .map_err(|e| format!("Invalid weight {} for {:?}: {}", weight, value, e))?;
+            weights.push((value, weight));
+        }
+        if weights.iter().all(|(_, weight)| *weight == 0) {
+            return Err("At least one value must have a weight greater than 0".to_string());
+        }
+        Ok(WeightedMix(weights))
+    }
 }
 
+impl<T: PartialEq> WeightedMix<T> {
+    pub fn weight(&self, value: &T) -> u32 {
+        self.0
+            .iter()
+            .filter(|(v, _)| v == value)
+            .map(|(_, weight)| weight)
+            .sum()
+    }
+}
+
+/// The weights of the proof types to send
+pub type ProofMix = WeightedMix<ProofType>;
+
+/// The weights of the proof sizes to send
+pub type ProofSizeMix = WeightedMix<ProofSize>;
+
 #[derive(Parser, Debug)]
 #[command(version, about, long_about = None)]
 pub struct GenerateAndFundWalletsArgs {
@@ -129,6 +259,24 @@ pub struct SendInfiniteProofsArgs {
         default_value = "3"
     )]
     pub burst_time_secs: u64,
+    #[arg(
+        name = "Weights of the proof types to send, as <proof type>:<weight> pairs separated by commas",
+        long = "proof-mix",
+        default_value = "groth16:1"
+    )]
+    pub proof_mix: ProofMix,
+    #[arg(
+        name = "Weights of the proof sizes to send, as <size>:<weight> pairs separated by commas",
+        long = "proof-size-mix",
+        default_value = "small:1,medium:1,large:1"
+    )]
+    pub proof_size_mix: ProofSizeMix,
+    #[arg(
+        name = "Ratio of the proofs sent that are invalid proofs of the corpus, from 0 to 1",
+        long = "invalid-proofs-ratio",
+        default_value = "0"
+    )]
+    pub invalid_proofs_ratio: f64,
     #[arg(name = "Max Fee", long = "max-fee", default_value = "1300000000000000")]
     pub max_fee: String,
     #[arg(
@@ -142,6 +290,11 @@ pub struct SendInfiniteProofsArgs {
         long = "private-keys-filepath"
     )]
     pub private_keys_filepath: String,
+    #[arg(
+        name = "Number of wallets of the private keys file to send from concurrently, all of them if not set",
+        long = "num-senders"
+    )]
+    pub num_senders: Option<usize>,
     #[arg(
         name = "The generated proofs directory",
         long = "proofs-dirpath",
@@ -150,6 +303,259 @@ pub struct SendInfiniteProofsArgs {
     pub proofs_dir: String,
 }
 
+#[derive(Parser, Debug)]
+#[command(version, about, long_about = None)]
+pub struct SendProofsLoadTestArgs {
+    #[arg(
+        name = "Ethereum RPC provider connection address",
+        long = "eth-rpc-url",
+        default_value = "http://localhost:8545"
+    )]
+    pub eth_rpc_url: String,
+    #[arg(
+        name = "Batcher connection address",
+        long = "batcher-url",
+        default_value = "ws://localhost:8080"
+    )]
+    pub batcher_url: String,
+    #[arg(name = "Target proofs per second", long = "proofs-per-second")]
+    pub proofs_per_second: f64,
+    #[arg(
+        name = "Time to linearly increase the rate up to the target in seconds",
+        long = "ramp-up-secs",
+        default_value = "0"
+    )]
+    pub ramp_up_secs: u64,
+    #[arg(
+        name = "Time to send proofs for in seconds, including the ramp up",
+        long = "duration-secs",
+        default_value = "60"
+    )]
+    pub duration_secs: u64,
+    #[arg(
+        name = "Weights of the proof types to send, as <proof type>:<weight> pairs separated by commas",
+        long = "proof-mix",
+        default_value = "groth16:1"
+    )]
+    pub proof_mix: ProofMix,
+    #[arg(
+        name = "Weights of the proof sizes to send, as <size>:<weight> pairs separated by commas",
+        long = "proof-size-mix",
+        default_value = "small:1,medium:1,large:1"
+    )]
+    pub proof_size_mix: ProofSizeMix,
+    #[arg(
+        name = "Ratio of the proofs sent that are invalid proofs of the corpus, from 0 to 1",
+        long = "invalid-proofs-ratio",
+        default_value = "0"
+    )]
+    pub invalid_proofs_ratio: f64,
+    #[arg(
+        name = "Max number of proofs sent by a sender at once",
+        long = "max-proofs-per-submission",
+        default_value = "10"
+    )]
+    pub max_proofs_per_submission: usize,
+    #[arg(
+        name = "Time to wait for the batches to be verified after being included in seconds",
+        long = "verification-timeout-secs",
+        default_value = "600"
+    )]
+    pub verification_timeout_secs: u64,
+    #[arg(
+        name = "The filepath to which to save the report of the run",
+        long = "report-path"
+    )]
+    pub report_path: Option<String>,
+    #[arg(
+        name = "The format of the report",
+        long = "report-format",
+        default_value = "json"
+    )]
+    pub report_format: ReportFormat,
+    #[arg(name = "Max Fee", long = "max-fee", default_value = "1300000000000000")]
+    pub max_fee: String,
+    #[arg(
+        name = "The Ethereum network's name",
+        long = "network",
+        default_value = "devnet"
+    )]
+    pub network: NetworkArg,
+    #[arg(
+        name = "Private keys filepath for the senders",
+        long = "private-keys-filepath"
+    )]
+    pub private_keys_filepath: String,
+    #[arg(
+        name = "Number of wallets of the private keys file to send from concurrently, all of them if not set",
+        long = "num-senders"
+    )]
+    pub num_senders: Option<usize>,
+    #[arg(name = "The generated proofs directory", long = "proofs-dirpath")]
+    pub proofs_dir: String,
+}
+
+#[derive(Parser, Debug)]
+#[command(version, about, long_about = None)]
+pub struct CorpusArgs {
+    #[clap(subcommand)]
+    pub command: CorpusCommands,
+}
+
+#[derive(Subcommand, Debug)]
+pub enum CorpusCommands {
+    #[clap(about = "Catalog the proofs of the corpus directory")]
+    Index(CorpusDirArgs),
+    #[clap(about = "List the number of proofs of the corpus by type, size and validity")]
+    List(CorpusDirArgs),
+    #[clap(about = "Fetch a published corpus into the corpus directory")]
+    Fetch(FetchCorpusArgs),
+    #[clap(about = "Add invalid proofs to the corpus by corrupting valid ones")]
+    AddInvalid(AddInvalidProofsArgs),
+}
+
+#[derive(Parser, Debug)]
+#[command(version, about, long_about = None)]
+pub struct CorpusDirArgs {
+    #[arg(name = "The corpus directory", long = "proofs-dirpath")]
+    pub proofs_dir: String,
+}
+
+#[derive(Parser, Debug)]
+#[command(version, about, long_about = None)]
+pub struct FetchCorpusArgs {
+    #[arg(name = "The URL of the published corpus manifest", long = "url")]
+    pub url: String,
+    #[arg(name = "The corpus directory", long = "proofs-dirpath")]
+    pub proofs_dir: String,
+}
+
+#[derive(Parser, Debug)]
+#[command(version, about, long_about = None)]
+pub struct AddInvalidProofsArgs {
+    #[arg(name = "The corpus directory", long = "proofs-dirpath")]
+    pub proofs_dir: String,
+    #[arg(name = "The type of the proofs to corrupt", long = "proof-type")]
+    pub proof_type: ProofType,
+    #[arg(
+        name = "The number of invalid proofs to add",
+        long = "number-of-proofs",
+        default_value = "1"
+    )]
+    pub number_of_proofs: usize,
+}
+
+#[derive(Parser, Debug)]
+#[command(version, about, long_about = None)]
+pub struct EstimateFeeArgs {
+    #[arg(
+        name = "Ethereum RPC provider connection address",
+        long = "eth-rpc-url",
+        default_value = "http://localhost:8545"
+    )]
+    pub eth_rpc_url: String,
+    #[arg(
+        name = "The Ethereum network's name",
+        long = "network",
+        default_value = "devnet"
+    )]
+    pub network: NetworkArg,
+    #[arg(
+        name = "Number of recent blocks to take the gas prices and batches from",
+        long = "blocks",
+        default_value = "300"
+    )]
+    pub blocks: u64,
+    #[arg(
+        name = "Batch sizes to estimate the fee per proof for, separated by commas",
+        long = "batch-sizes",
+        value_delimiter = ',',
+        default_value = "1,8,32"
+    )]
+    pub batch_sizes: Vec<usize>,
+    #[arg(
+        name = "Batch size the suggested max fee pays for, the median recent batch size if not set",
+        long = "target-batch-size"
+    )]
+    pub target_batch_size: Option<usize>,
+    #[arg(
+        name = "Gas cost of the aggregator responding to a task, as in the batcher config",
+        long = "aggregator-gas-cost",
+        default_value = "330000"
+    )]
+    pub aggregator_gas_cost: u128,
+    #[arg(
+        name = "Percentage of the aggregator gas cost paid to the aggregator, as in the batcher config",
+        long = "aggregator-fee-percentage-multiplier",
+        default_value = "125"
+    )]
+    pub aggregator_fee_percentage_multiplier: u128,
+    #[arg(name = "Print the estimate as JSON", long = "json")]
+    pub json: bool,
+}
+
+#[derive(Parser, Debug)]
+#[command(version, about, long_about = None)]
+pub struct ChaosArgs {
+    #[arg(
+        name = "Ethereum RPC provider connection address",
+        long = "eth-rpc-url",
+        default_value = "http://localhost:8545"
+    )]
+    pub eth_rpc_url: String,
+    #[arg(
+        name = "Batcher connection address",
+        long = "batcher-url",
+        default_value = "ws://localhost:8080"
+    )]
+    pub batcher_url: String,
+    #[arg(
+        name = "The Ethereum network's name",
+        long = "network",
+        default_value = "devnet"
+    )]
+    pub network: NetworkArg,
+    #[arg(
+        name = "Private keys filepath, the first wallet sends the proofs",
+        long = "private-keys-filepath"
+    )]
+    pub private_keys_filepath: String,
+    #[arg(name = "The generated proofs directory", long = "proofs-dirpath")]
+    pub proofs_dir: String,
+    #[arg(
+        name = "The type of the valid proof to break",
+        long = "proof-type",
+        default_value = "groth16"
+    )]
+    pub proof_type: ProofType,
+    #[arg(
+        name = "Scenarios to run, separated by commas",
+        long = "scenarios",
+        value_delimiter = ',',
+        default_value = "malformed-proof,wrong-public-input,oversized-payload,mismatched-verification-key"
+    )]
+    pub scenarios: Vec<ChaosScenario>,
+    #[arg(
+        name = "Size in bytes of the proof of the oversized payload scenario, over the max proof size of the batcher",
+        long = "oversized-payload-size",
+        default_value = "67108865"
+    )]
+    pub oversized_payload_size: usize,
+    #[arg(
+        name = "Time to wait for the batches to be verified in seconds. Broken proofs whose batch isn't verified by then pass",
+        long = "verification-timeout-secs",
+        default_value = "300"
+    )]
+    pub verification_timeout_secs: u64,
+    #[arg(name = "Max Fee", long = "max-fee", default_value = "1300000000000000")]
+    pub max_fee: String,
+    #[arg(
+        name = "The filepath to which to save the results as JSON",
+        long = "report-path"
+    )]
+    pub report_path: Option<String>,
+}
+
 #[derive(Debug, Clone, Copy, ValueEnum)]
 pub enum NetworkArg {
     Devnet,
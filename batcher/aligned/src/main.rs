@@ -21,6 +21,7 @@ use env_logger::Env;
 use ethers::prelude::*;
 use ethers::utils::format_ether;
 use ethers::utils::hex;
+use ethers::utils::keccak256;
 use ethers::utils::parse_ether;
 use log::warn;
 use log::{error, info};
@@ -85,6 +86,8 @@ pub struct SubmitArgs {
     verification_key_file_name: Option<PathBuf>,
     #[arg(name = "VM prgram code file name", long = "vm_program")]
     vm_program_code_file_name: Option<PathBuf>,
+    #[arg(name = "WASM verifier plugin file name", long = "wasm_plugin")]
+    wasm_plugin_file_name: Option<PathBuf>,
     #[arg(
         name = "Number of repetitions",
         long = "repetitions",
@@ -249,6 +252,30 @@ pub enum ProvingSystemArg {
     SP1,
     #[clap(name = "Risc0")]
     Risc0,
+    #[clap(name = "Plonky2")]
+    Plonky2,
+    #[clap(name = "Groth16Bls12_381")]
+    Groth16Bls12_381,
+    #[clap(name = "Cairo")]
+    Cairo,
+    #[clap(name = "Nova")]
+    Nova,
+    #[clap(name = "Jolt")]
+    Jolt,
+    #[clap(name = "Miden")]
+    Miden,
+    #[clap(name = "Binius")]
+    Binius,
+    #[clap(name = "Halo2")]
+    Halo2,
+    #[clap(name = "Boojum")]
+    Boojum,
+    #[clap(name = "Valida")]
+    Valida,
+    #[clap(name = "Kimchi")]
+    Kimchi,
+    #[clap(name = "WasmPlugin")]
+    WasmPlugin,
 }
 
 const ANVIL_PRIVATE_KEY: &str = "2a871d0798f97d79848a013d4936a73bf4cc922c825d33c1cf7073dff6d409c6"; // Anvil address 9
@@ -261,6 +288,18 @@ impl From<ProvingSystemArg> for ProvingSystemId {
             ProvingSystemArg::Groth16Bn254 => ProvingSystemId::Groth16Bn254,
             ProvingSystemArg::SP1 => ProvingSystemId::SP1,
             ProvingSystemArg::Risc0 => ProvingSystemId::Risc0,
+            ProvingSystemArg::Plonky2 => ProvingSystemId::Plonky2,
+            ProvingSystemArg::Groth16Bls12_381 => ProvingSystemId::Groth16Bls12_381,
+            ProvingSystemArg::Cairo => ProvingSystemId::Cairo,
+            ProvingSystemArg::Nova => ProvingSystemId::Nova,
+            ProvingSystemArg::Jolt => ProvingSystemId::Jolt,
+            ProvingSystemArg::Miden => ProvingSystemId::Miden,
+            ProvingSystemArg::Binius => ProvingSystemId::Binius,
+            ProvingSystemArg::Halo2 => ProvingSystemId::Halo2,
+            ProvingSystemArg::Boojum => ProvingSystemId::Boojum,
+            ProvingSystemArg::Valida => ProvingSystemId::Valida,
+            ProvingSystemArg::Kimchi => ProvingSystemId::Kimchi,
+            ProvingSystemArg::WasmPlugin => ProvingSystemId::WasmPlugin,
         }
     }
 }
@@ -573,9 +612,61 @@ fn verification_data_from_args(args: &SubmitArgs) -> Result<VerificationData, Su
                 .map(read_file)
                 .transpose()?;
         }
+        ProvingSystemId::Plonky2 => {
+            // Plonky2 public inputs are part of the proof, the verification key is the
+            // serialized verifier circuit data
+            verification_key = Some(read_file_option(
+                "--vk",
+                args.verification_key_file_name.clone(),
+            )?);
+        }
+        ProvingSystemId::Jolt | ProvingSystemId::Miden | ProvingSystemId::Valida => {
+            // The public input of Jolt proofs is the serialized program io, Miden proofs use
+            // the program hash as program and the stack inputs and outputs as public input,
+            // and Valida proofs use the program output as public input
+            vm_program_code = Some(read_file_option(
+                "--vm_program",
+                args.vm_program_code_file_name.clone(),
+            )?);
+            pub_input = Some(read_file_option(
+                "--public_input",
+                args.pub_input_file_name.clone(),
+            )?);
+        }
+        ProvingSystemId::Cairo => {
+            // Cairo proofs are Stone JSON proofs, the public input is the program hash
+            // followed by the program output
+            pub_input = Some(read_file_option(
+                "--public_input",
+                args.pub_input_file_name.clone(),
+            )?);
+        }
+        ProvingSystemId::WasmPlugin => {
+            // The verification key starts with the hash of the plugin module, so the proof
+            // commitment binds the plugin that verifies it
+            let plugin = read_file_option("--wasm_plugin", args.wasm_plugin_file_name.clone())?;
+            let mut plugin_verification_key = keccak256(plugin).to_vec();
+            plugin_verification_key.extend(read_file_option(
+                "--vk",
+                args.verification_key_file_name.clone(),
+            )?);
+            verification_key = Some(plugin_verification_key);
+
+            pub_input = args
+                .pub_input_file_name
+                .clone()
+                .map(read_file)
+                .transpose()?;
+        }
         ProvingSystemId::GnarkPlonkBls12_381
         | ProvingSystemId::GnarkPlonkBn254
-        | ProvingSystemId::Groth16Bn254 => {
+        | ProvingSystemId::Groth16Bn254
+        | ProvingSystemId::Groth16Bls12_381
+        | ProvingSystemId::Nova
+        | ProvingSystemId::Binius
+        | ProvingSystemId::Halo2
+        | ProvingSystemId::Boojum
+        | ProvingSystemId::Kimchi => {
             verification_key = Some(read_file_option(
                 "--vk",
                 args.verification_key_file_name.clone(),
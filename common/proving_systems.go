@@ -16,10 +16,26 @@ const (
 	Groth16Bn254
 	SP1
 	Risc0
+	Plonky2
+	Groth16Bls12_381
+	Cairo
+	Nova
+	Jolt
+	Miden
+	Binius
+	Halo2
+	Boojum
+	Valida
+	Kimchi
+	WasmPlugin
 )
 
 func (t *ProvingSystemId) String() string {
-	return [...]string{"GnarkPlonkBls12_381", "GnarkPlonkBn254", "Groth16Bn254", "SP1"}[*t]
+	str, err := ProvingSystemIdToString(*t)
+	if err != nil {
+		return fmt.Sprintf("Unknown(%d)", *t)
+	}
+	return str
 }
 
 func ProvingSystemIdFromString(provingSystem string) (ProvingSystemId, error) {
@@ -34,6 +50,30 @@ func ProvingSystemIdFromString(provingSystem string) (ProvingSystemId, error) {
 		return SP1, nil
 	case "Risc0":
 		return Risc0, nil
+	case "Plonky2":
+		return Plonky2, nil
+	case "Groth16Bls12_381":
+		return Groth16Bls12_381, nil
+	case "Cairo":
+		return Cairo, nil
+	case "Nova":
+		return Nova, nil
+	case "Jolt":
+		return Jolt, nil
+	case "Miden":
+		return Miden, nil
+	case "Binius":
+		return Binius, nil
+	case "Halo2":
+		return Halo2, nil
+	case "Boojum":
+		return Boojum, nil
+	case "Valida":
+		return Valida, nil
+	case "Kimchi":
+		return Kimchi, nil
+	case "WasmPlugin":
+		return WasmPlugin, nil
 	}
 
 	return 0, fmt.Errorf("unknown proving system: %s", provingSystem)
@@ -51,6 +91,30 @@ func ProvingSystemIdToString(provingSystem ProvingSystemId) (string, error) {
 		return "SP1", nil
 	case Risc0:
 		return "Risc0", nil
+	case Plonky2:
+		return "Plonky2", nil
+	case Groth16Bls12_381:
+		return "Groth16Bls12_381", nil
+	case Cairo:
+		return "Cairo", nil
+	case Nova:
+		return "Nova", nil
+	case Jolt:
+		return "Jolt", nil
+	case Miden:
+		return "Miden", nil
+	case Binius:
+		return "Binius", nil
+	case Halo2:
+		return "Halo2", nil
+	case Boojum:
+		return "Boojum", nil
+	case Valida:
+		return "Valida", nil
+	case Kimchi:
+		return "Kimchi", nil
+	case WasmPlugin:
+		return "WasmPlugin", nil
 	}
 
 	return "", fmt.Errorf("unknown proving system: %d", provingSystem)
@@ -105,11 +169,43 @@ func (s *ProvingSystemId) UnmarshalCBOR(data []byte) error {
 		*s = SP1
 	case "Risc0":
 		*s = Risc0
+	case "Plonky2":
+		*s = Plonky2
+	case "Groth16Bls12_381":
+		*s = Groth16Bls12_381
+	case "Cairo":
+		*s = Cairo
+	case "Nova":
+		*s = Nova
+	case "Jolt":
+		*s = Jolt
+	case "Miden":
+		*s = Miden
+	case "Binius":
+		*s = Binius
+	case "Halo2":
+		*s = Halo2
+	case "Boojum":
+		*s = Boojum
+	case "Valida":
+		*s = Valida
+	case "Kimchi":
+		*s = Kimchi
+	case "WasmPlugin":
+		*s = WasmPlugin
 	}
 
 	return nil
 }
 
+func (s ProvingSystemId) MarshalCBOR() ([]byte, error) {
+	str, err := ProvingSystemIdToString(s)
+	if err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(str)
+}
+
 func (t ProvingSystemId) MarshalBinary() ([]byte, error) {
 	// needs to be defined but should never be called
 	return nil, fmt.Errorf("not implemented")
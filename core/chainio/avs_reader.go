@@ -4,23 +4,27 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
 	contractERC20Mock "github.com/yetanotherco/aligned_layer/contracts/bindings/ERC20Mock"
 	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients"
 	sdkavsregistry "github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
 )
 
 type AvsReader struct {
 	*sdkavsregistry.ChainReader
 	AvsContractBindings            *AvsServiceBindings
 	AlignedLayerServiceManagerAddr ethcommon.Address
+	registryCoordinator            *regcoord.ContractRegistryCoordinatorFilterer
 	logger                         logging.Logger
 }
 
@@ -47,10 +51,16 @@ func NewAvsReaderFromConfig(baseConfig *config.BaseConfig) (*AvsReader, error) {
 		return nil, err
 	}
 
+	registryCoordinator, err := regcoord.NewContractRegistryCoordinatorFilterer(baseConfig.AlignedLayerDeploymentConfig.AlignedLayerRegistryCoordinatorAddr, &baseConfig.EthRpcClient)
+	if err != nil {
+		return nil, err
+	}
+
 	return &AvsReader{
 		ChainReader:                    chainReader,
 		AvsContractBindings:            avsServiceBindings,
 		AlignedLayerServiceManagerAddr: baseConfig.AlignedLayerDeploymentConfig.AlignedLayerServiceManagerAddr,
+		registryCoordinator:            registryCoordinator,
 		logger:                         baseConfig.Logger,
 	}, nil
 }
@@ -71,6 +81,18 @@ func (r *AvsReader) IsOperatorRegistered(address ethcommon.Address) (bool, error
 	return r.ChainReader.IsOperatorRegistered(&bind.CallOpts{}, address)
 }
 
+// IsOperatorIdRegistered checks if the operator with the given id, the hash of its BLS public key, is registered
+func (r *AvsReader) IsOperatorIdRegistered(ctx context.Context, operatorId eigentypes.OperatorId) (bool, error) {
+	address, err := r.ChainReader.GetOperatorFromId(&bind.CallOpts{Context: ctx}, operatorId)
+	if err != nil {
+		return false, err
+	}
+	if address == (ethcommon.Address{}) {
+		return false, nil
+	}
+	return r.ChainReader.IsOperatorRegistered(&bind.CallOpts{Context: ctx}, address)
+}
+
 func (r *AvsReader) DisabledVerifiers() (*big.Int, error) {
 	return r.AvsContractBindings.ServiceManager.ContractAlignedLayerServiceManagerCaller.DisabledVerifiers(&bind.CallOpts{})
 }
@@ -92,8 +114,7 @@ func (r *AvsReader) GetNotRespondedTasksFrom(fromBlock uint64) ([]servicemanager
 		}
 
 		// now check if its finalized or not before appending
-		batchIdentifier := append(task.BatchMerkleRoot[:], task.SenderAddress[:]...)
-		batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+		batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, task.BatchMerkleRoot, task.SenderAddress)
 		state, err := r.AvsContractBindings.ServiceManager.ContractAlignedLayerServiceManagerCaller.BatchesState(nil, batchIdentifierHash)
 
 		if err != nil {
@@ -109,6 +130,106 @@ func (r *AvsReader) GetNotRespondedTasksFrom(fromBlock uint64) ([]servicemanager
 	return tasks, nil
 }
 
+// OperatorQuorumStake is the stake of an operator in a quorum, along with the total stake of the quorum.
+type OperatorQuorumStake struct {
+	Quorum     eigentypes.QuorumNum
+	Stake      *big.Int
+	TotalStake *big.Int
+}
+
+// GetOperatorQuorumStakes returns the current stake of the operator in each quorum it's registered in.
+func (r *AvsReader) GetOperatorQuorumStakes(operatorAddress ethcommon.Address) ([]OperatorQuorumStake, error) {
+	operatorId, err := r.GetOperatorId(&bind.CallOpts{}, operatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	stakes, err := r.GetOperatorStakeInQuorumsOfOperatorAtCurrentBlock(&bind.CallOpts{}, operatorId)
+	if err != nil {
+		return nil, err
+	}
+
+	quorumStakes := make([]OperatorQuorumStake, 0, len(stakes))
+	for quorum, stake := range stakes {
+		operators, err := r.GetOperatorsStakeInQuorumsAtCurrentBlock(&bind.CallOpts{}, eigentypes.QuorumNums{quorum})
+		if err != nil {
+			return nil, err
+		}
+		totalStake := new(big.Int)
+		for _, operator := range operators[0] {
+			totalStake.Add(totalStake, operator.Stake)
+		}
+		quorumStakes = append(quorumStakes, OperatorQuorumStake{Quorum: quorum, Stake: stake, TotalStake: totalStake})
+	}
+	sort.Slice(quorumStakes, func(i, j int) bool { return quorumStakes[i].Quorum < quorumStakes[j].Quorum })
+	return quorumStakes, nil
+}
+
+// GetOperatorSocket returns the socket the operator last registered or updated, which the
+// registry coordinator only keeps in its events. It's empty if the operator never set one.
+func (r *AvsReader) GetOperatorSocket(ctx context.Context, operatorAddress ethcommon.Address) (eigentypes.Socket, error) {
+	operatorId, err := r.GetOperatorId(&bind.CallOpts{Context: ctx}, operatorAddress)
+	if err != nil {
+		return "", err
+	}
+	socketUpdates, err := r.registryCoordinator.FilterOperatorSocketUpdate(&bind.FilterOpts{Context: ctx}, [][32]byte{operatorId})
+	if err != nil {
+		return "", fmt.Errorf("failed to filter the operator socket updates: %w", err)
+	}
+	defer socketUpdates.Close()
+
+	var socket eigentypes.Socket
+	for socketUpdates.Next() {
+		socket = eigentypes.Socket(socketUpdates.Event.Socket)
+	}
+	return socket, socketUpdates.Error()
+}
+
+// BlockNumber returns the latest block number, from the fallback node if the main one fails
+func (r *AvsReader) BlockNumber(ctx context.Context) (uint64, error) {
+	latestBlock, err := r.AvsContractBindings.ethClient.BlockNumber(ctx)
+	if err != nil {
+		latestBlock, err = r.AvsContractBindings.ethClientFallback.BlockNumber(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get latest block number: %w", err)
+		}
+	}
+	return latestBlock, nil
+}
+
+// BatchState is the state of a batch in the service manager
+type BatchState struct {
+	// TaskCreatedBlock is 0 if the task of the batch was never created
+	TaskCreatedBlock uint32
+	Responded        bool
+}
+
+// GetBatchState returns the state of the batch identified by its merkle root and the address
+// that created its task, from the fallback node if the main one fails
+func (r *AvsReader) GetBatchState(batchMerkleRoot [32]byte, senderAddress ethcommon.Address) (BatchState, error) {
+	batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, batchMerkleRoot, senderAddress)
+	state, err := r.AvsContractBindings.ServiceManager.BatchesState(&bind.CallOpts{}, batchIdentifierHash)
+	if err != nil {
+		state, err = r.AvsContractBindings.ServiceManagerFallback.BatchesState(&bind.CallOpts{}, batchIdentifierHash)
+		if err != nil {
+			return BatchState{}, fmt.Errorf("failed to get batch state: %w", err)
+		}
+	}
+	return BatchState{TaskCreatedBlock: state.TaskCreatedBlock, Responded: state.Responded}, nil
+}
+
+// GetBatcherBalance returns the funds the batcher deposited in the service manager to pay for its
+// tasks, from the fallback node if the main one fails
+func (r *AvsReader) GetBatcherBalance(batcherAddress ethcommon.Address) (*big.Int, error) {
+	balance, err := r.AvsContractBindings.ServiceManager.BatchersBalances(&bind.CallOpts{}, batcherAddress)
+	if err != nil {
+		balance, err = r.AvsContractBindings.ServiceManagerFallback.BatchersBalances(&bind.CallOpts{}, batcherAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get batcher balance: %w", err)
+		}
+	}
+	return balance, nil
+}
+
 // This function is a helper to get a task hash of aproximately nBlocksOld blocks ago
 func (r *AvsReader) GetOldTaskHash(nBlocksOld uint64, interval uint64) (*[32]byte, error) {
 	latestBlock, err := r.AvsContractBindings.ethClient.BlockNumber(context.Background())
@@ -146,7 +267,6 @@ func (r *AvsReader) GetOldTaskHash(nBlocksOld uint64, interval uint64) (*[32]byt
 		return nil, err
 	}
 
-	batchIdentifier := append(task.BatchMerkleRoot[:], task.SenderAddress[:]...)
-	batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+	batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, task.BatchMerkleRoot, task.SenderAddress)
 	return &batchIdentifierHash, nil
 }
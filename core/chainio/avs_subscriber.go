@@ -13,9 +13,10 @@ import (
 	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
 	retry "github.com/yetanotherco/aligned_layer/core"
 	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
+	"github.com/yetanotherco/aligned_layer/metrics"
 
 	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
-	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
@@ -24,6 +25,9 @@ const (
 	BlockInterval              uint64 = 1000
 	PollLatestBatchInterval           = 5 * time.Second
 	RemoveBatchFromSetInterval        = 5 * time.Minute
+	// NewBatchSendTimeout is how long a new batch waits to be taken from the new tasks channel before
+	// being dropped, so a stuck consumer doesn't block the subscriptions until they overflow
+	NewBatchSendTimeout = 5 * time.Minute
 )
 
 // NOTE(marian): Leaving this commented code here as it may be useful in the short term.
@@ -41,9 +45,11 @@ type AvsSubscriber struct {
 	AvsContractBindings            *AvsServiceBindings
 	AlignedLayerServiceManagerAddr ethcommon.Address
 	logger                         sdklogging.Logger
+	// metrics counts the dropped new batches, if set
+	metrics *metrics.Metrics
 }
 
-func NewAvsSubscriberFromConfig(baseConfig *config.BaseConfig) (*AvsSubscriber, error) {
+func NewAvsSubscriberFromConfig(baseConfig *config.BaseConfig, metrics *metrics.Metrics) (*AvsSubscriber, error) {
 	avsContractBindings, err := NewAvsServiceBindings(
 		baseConfig.AlignedLayerDeploymentConfig.AlignedLayerServiceManagerAddr,
 		baseConfig.AlignedLayerDeploymentConfig.AlignedLayerOperatorStateRetrieverAddr,
@@ -58,6 +64,7 @@ func NewAvsSubscriberFromConfig(baseConfig *config.BaseConfig) (*AvsSubscriber,
 		AvsContractBindings:            avsContractBindings,
 		AlignedLayerServiceManagerAddr: baseConfig.AlignedLayerDeploymentConfig.AlignedLayerServiceManagerAddr,
 		logger:                         baseConfig.Logger,
+		metrics:                        metrics,
 	}, nil
 }
 
@@ -207,8 +214,7 @@ func (s *AvsSubscriber) processNewBatchV2(batch *servicemanager.ContractAlignedL
 	newBatchMutex.Lock()
 	defer newBatchMutex.Unlock()
 
-	batchIdentifier := append(batch.BatchMerkleRoot[:], batch.SenderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+	batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, batch.BatchMerkleRoot, batch.SenderAddress)
 
 	if _, ok := batchesSet[batchIdentifierHash]; !ok {
 		s.logger.Info("Received new task",
@@ -216,8 +222,20 @@ func (s *AvsSubscriber) processNewBatchV2(batch *servicemanager.ContractAlignedL
 			"senderAddress", hex.EncodeToString(batch.SenderAddress[:]),
 			"batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]))
 
+		select {
+		case newTaskCreatedChan <- batch:
+		case <-time.After(NewBatchSendTimeout):
+			// Not added to the set, so it's processed if received again
+			s.logger.Error("New task was not taken in time, dropping it",
+				"batchMerkleRoot", hex.EncodeToString(batch.BatchMerkleRoot[:]),
+				"senderAddress", hex.EncodeToString(batch.SenderAddress[:]),
+				"batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]))
+			if s.metrics != nil {
+				s.metrics.IncDroppedNewBatches()
+			}
+			return
+		}
 		batchesSet[batchIdentifierHash] = struct{}{}
-		newTaskCreatedChan <- batch
 
 		// Remove the batch from the set after RemoveBatchFromSetInterval time
 		go func() {
@@ -233,8 +251,7 @@ func (s *AvsSubscriber) processNewBatchV3(batch *servicemanager.ContractAlignedL
 	newBatchMutex.Lock()
 	defer newBatchMutex.Unlock()
 
-	batchIdentifier := append(batch.BatchMerkleRoot[:], batch.SenderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+	batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, batch.BatchMerkleRoot, batch.SenderAddress)
 
 	if _, ok := batchesSet[batchIdentifierHash]; !ok {
 		s.logger.Info("Received new task",
@@ -242,8 +259,20 @@ func (s *AvsSubscriber) processNewBatchV3(batch *servicemanager.ContractAlignedL
 			"senderAddress", hex.EncodeToString(batch.SenderAddress[:]),
 			"batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]))
 
+		select {
+		case newTaskCreatedChan <- batch:
+		case <-time.After(NewBatchSendTimeout):
+			// Not added to the set, so it's processed if received again
+			s.logger.Error("New task was not taken in time, dropping it",
+				"batchMerkleRoot", hex.EncodeToString(batch.BatchMerkleRoot[:]),
+				"senderAddress", hex.EncodeToString(batch.SenderAddress[:]),
+				"batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]))
+			if s.metrics != nil {
+				s.metrics.IncDroppedNewBatches()
+			}
+			return
+		}
 		batchesSet[batchIdentifierHash] = struct{}{}
-		newTaskCreatedChan <- batch
 
 		// Remove the batch from the set after RemoveBatchFromSetInterval time
 		go func() {
@@ -291,8 +320,7 @@ func (s *AvsSubscriber) getLatestNotRespondedTaskFromEthereumV2() (*servicemanag
 		return nil, nil
 	}
 
-	batchIdentifier := append(lastLog.BatchMerkleRoot[:], lastLog.SenderAddress[:]...)
-	batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+	batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, lastLog.BatchMerkleRoot, lastLog.SenderAddress)
 	state, err := s.BatchesStateRetryable(nil, batchIdentifierHash, retry.NetworkRetryParams())
 	if err != nil {
 		return nil, err
@@ -340,8 +368,7 @@ func (s *AvsSubscriber) getLatestNotRespondedTaskFromEthereumV3() (*servicemanag
 		return nil, nil
 	}
 
-	batchIdentifier := append(lastLog.BatchMerkleRoot[:], lastLog.SenderAddress[:]...)
-	batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+	batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, lastLog.BatchMerkleRoot, lastLog.SenderAddress)
 	state, err := s.BatchesStateRetryable(nil, batchIdentifierHash, retry.NetworkRetryParams())
 	if err != nil {
 		return nil, err
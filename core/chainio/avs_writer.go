@@ -3,6 +3,7 @@ package chainio
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -22,17 +23,26 @@ import (
 	"github.com/yetanotherco/aligned_layer/metrics"
 )
 
+// ErrWrongKeyRole is returned for a transaction not signed with the key of its role
+var ErrWrongKeyRole = errors.New("transaction not signed with the key of its role")
+
+// AvsWriter sends the administrative and registration transactions, including the ones of the
+// ChainWriter, with the Signer key, and the task responses with the RespondToTaskSigner key.
 type AvsWriter struct {
 	*avsregistry.ChainWriter
 	AvsContractBindings *AvsServiceBindings
 	logger              logging.Logger
 	Signer              signer.Signer
+	RespondToTaskSigner signer.Signer
 	Client              eth.InstrumentedClient
 	ClientFallback      eth.InstrumentedClient
 	metrics             *metrics.Metrics
 }
 
-func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.EcdsaConfig, metrics *metrics.Metrics) (*AvsWriter, error) {
+// NewAvsWriterFromConfig creates a writer sending the task responses with the respondToTaskEcdsaConfig
+// key and every other transaction with the ecdsaConfig key. If respondToTaskEcdsaConfig is nil, all
+// of them are sent with the ecdsaConfig key.
+func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.EcdsaConfig, respondToTaskEcdsaConfig *config.EcdsaConfig, metrics *metrics.Metrics) (*AvsWriter, error) {
 
 	buildAllConfig := clients.BuildAllConfig{
 		EthHttpUrl:                 baseConfig.EthRpcUrl,
@@ -63,6 +73,15 @@ func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.E
 		return nil, err
 	}
 
+	respondToTaskSigner := privateKeySigner
+	if respondToTaskEcdsaConfig != nil {
+		respondToTaskSigner, err = signer.NewPrivateKeySigner(respondToTaskEcdsaConfig.PrivateKey, baseConfig.ChainId)
+		if err != nil {
+			baseConfig.Logger.Error("Cannot create respond to task signer", "err", err)
+			return nil, err
+		}
+	}
+
 	chainWriter := clients.AvsRegistryChainWriter
 
 	return &AvsWriter{
@@ -70,6 +89,7 @@ func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.E
 		AvsContractBindings: avsServiceBindings,
 		logger:              baseConfig.Logger,
 		Signer:              privateKeySigner,
+		RespondToTaskSigner: respondToTaskSigner,
 		Client:              baseConfig.EthRpcClient,
 		ClientFallback:      baseConfig.EthRpcClientFallback,
 		metrics:             metrics,
@@ -90,7 +110,7 @@ func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.E
 //     without an error (returning `nil, nil`).
 //   - An error if the process encounters a fatal issue (e.g., permanent failure in verifying balances or state).
 func (w *AvsWriter) SendAggregatedResponse(batchIdentifierHash [32]byte, batchMerkleRoot [32]byte, senderAddress [20]byte, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature, gasBumpPercentage uint, gasBumpIncrementalPercentage uint, gasBumpPercentageLimit uint, timeToWaitBeforeBump time.Duration, metrics *metrics.Metrics, onSetGasPrice func(*big.Int)) (*types.Receipt, error) {
-	txOpts := *w.Signer.GetTxOpts()
+	txOpts := *w.RespondToTaskSigner.GetTxOpts()
 	txOpts.NoSend = true // simulate the transaction
 	simTx, err := w.RespondToTaskV2Retryable(&txOpts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature, retry.SendToChainRetryParams())
 	if err != nil {
@@ -150,7 +170,7 @@ func (w *AvsWriter) SendAggregatedResponse(batchIdentifierHash [32]byte, batchMe
 				if receipt == nil {
 					receipt, _ = w.ClientFallback.TransactionReceipt(context.Background(), tx.Hash())
 					if receipt != nil {
-						w.updateAggregatorGasCostMetrics(receipt, batchIdentifierHash)
+						w.updateAggregatorGasCostMetrics(receipt, batchIdentifierHash, true)
 						return receipt, nil
 					}
 				}
@@ -185,7 +205,7 @@ func (w *AvsWriter) SendAggregatedResponse(batchIdentifierHash [32]byte, batchMe
 		w.logger.Infof("Transaction sent, waiting for receipt", "merkle root", batchMerkleRootHashString)
 		receipt, err := utils.WaitForTransactionReceiptRetryable(w.Client, w.ClientFallback, realTx.Hash(), retry.WaitForTxRetryParams(timeToWaitBeforeBump))
 		if receipt != nil {
-			w.updateAggregatorGasCostMetrics(receipt, batchIdentifierHash)
+			w.updateAggregatorGasCostMetrics(receipt, batchIdentifierHash, i > 0)
 			return receipt, nil
 		}
 
@@ -209,7 +229,8 @@ func (w *AvsWriter) SendAggregatedResponse(batchIdentifierHash [32]byte, batchMe
 // Calculates the transaction cost from the receipt and updates the total amount paid by the aggregator metric
 // Then, it compares that tx cost with the batcher respondToTaskFeeLimit.
 // If the tx cost was higher, it means the aggregator has paid the difference for the batcher (txCost - respondToTaskFeeLimit) and so metrics are updated accordingly.
-func (w *AvsWriter) updateAggregatorGasCostMetrics(receipt *types.Receipt, batchIdentifierHash [32]byte) {
+// The fee recovered from the batcher, the tx cost capped at respondToTaskFeeLimit, is compared to the tx cost for the net margin metrics.
+func (w *AvsWriter) updateAggregatorGasCostMetrics(receipt *types.Receipt, batchIdentifierHash [32]byte, gasPriceBumped bool) {
 	batchState, err := w.BatchesStateRetryable(&bind.CallOpts{}, batchIdentifierHash, retry.NetworkRetryParams())
 	if err != nil {
 		return
@@ -221,6 +242,13 @@ func (w *AvsWriter) updateAggregatorGasCostMetrics(receipt *types.Receipt, batch
 	txCostInEth := utils.WeiToEth(txCost)
 	w.metrics.AddAggregatorGasCostPaidTotal(txCostInEth)
 
+	// the service manager refunds about the tx cost, up to respondToTaskFeeLimit
+	feeRecovered := txCost
+	if respondToTaskFeeLimit.Cmp(txCost) < 0 {
+		feeRecovered = respondToTaskFeeLimit
+	}
+	w.metrics.ObserveAggregatorBatchMargin(utils.WeiToEth(feeRecovered), txCostInEth, gasPriceBumped)
+
 	if respondToTaskFeeLimit.Cmp(txCost) < 0 {
 		aggregatorDifferencePaid := new(big.Int).Sub(txCost, respondToTaskFeeLimit)
 		aggregatorDifferencePaidInEth := utils.WeiToEth(aggregatorDifferencePaid)
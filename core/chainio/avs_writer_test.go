@@ -0,0 +1,26 @@
+package chainio
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/signer"
+	"github.com/ethereum/go-ethereum/crypto"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	retry "github.com/yetanotherco/aligned_layer/core"
+)
+
+func TestRespondToTaskRejectsOtherKeys(t *testing.T) {
+	chainId := big.NewInt(31337)
+	adminKey, _ := crypto.GenerateKey()
+	respondToTaskKey, _ := crypto.GenerateKey()
+	adminSigner, _ := signer.NewPrivateKeySigner(adminKey, chainId)
+	respondToTaskSigner, _ := signer.NewPrivateKeySigner(respondToTaskKey, chainId)
+	w := &AvsWriter{Signer: adminSigner, RespondToTaskSigner: respondToTaskSigner}
+
+	_, err := w.RespondToTaskV2Retryable(adminSigner.GetTxOpts(), [32]byte{}, [20]byte{}, servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature{}, retry.SendToChainRetryParams())
+	if !errors.Is(err, ErrWrongKeyRole) {
+		t.Fatalf("Expected the response signed with the admin key to be rejected, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package chainio
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
@@ -21,8 +22,12 @@ Send a transaction to the AVS contract to respond to a task.
 - All errors are considered Transient Errors
 - Retry times (3 retries): 12 sec (1 Blocks), 24 sec (2 Blocks), 48 sec (4 Blocks)
 - NOTE: Contract call reverts are not considered `PermanentError`'s as block reorg's may lead to contract call revert in which case the aggregator should retry.
+- Transactions not signed with the respond to task key are rejected without sending them.
 */
 func (w *AvsWriter) RespondToTaskV2Retryable(opts *bind.TransactOpts, batchMerkleRoot [32]byte, senderAddress common.Address, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature, config *retry.RetryParams) (*types.Transaction, error) {
+	if opts.From != w.RespondToTaskSigner.GetTxOpts().From {
+		return nil, retry.PermanentError{Inner: fmt.Errorf("respond to task sent from %s: %w", opts.From, ErrWrongKeyRole)}
+	}
 	respondToTaskV2_func := func() (*types.Transaction, error) {
 		// Try with main connection
 		tx, err := w.AvsContractBindings.ServiceManager.RespondToTaskV2(opts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature)
@@ -8,13 +8,17 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/yetanotherco/aligned_layer/core/utils"
+	"github.com/yetanotherco/aligned_layer/metrics"
 )
 
 type AggregatorConfig struct {
 	BaseConfig  *BaseConfig
 	EcdsaConfig *EcdsaConfig
-	BlsConfig   *BlsConfig
-	Aggregator  struct {
+	// RespondToTaskEcdsaConfig is the key the task responses are sent with, nil to send them with EcdsaConfig
+	RespondToTaskEcdsaConfig *EcdsaConfig
+	BlsConfig                *BlsConfig
+	MetricsConfig            metrics.Config
+	Aggregator               struct {
 		ServerIpPortAddress           string
 		BlsPublicKeyCompendiumAddress common.Address
 		AvsServiceManagerAddress      common.Address
@@ -29,25 +33,99 @@ type AggregatorConfig struct {
 		GasBumpIncrementalPercentage  uint
 		GasBumpPercentageLimit        uint
 		TimeToWaitBeforeBump          time.Duration
+		PinnedVerifierVersions        map[string]string
+		AuthorizedBatchers            []common.Address
+		RpcMaxMessageSize             int64
+		RpcMaxConnections             int
+		RpcIdleTimeout                time.Duration
+		RpcReadTimeout                time.Duration
+		RpcWriteTimeout               time.Duration
+		IpRequestsPerSecond           float64
+		IpBurst                       int
+		IpBanThreshold                int
+		IpBanDuration                 time.Duration
+		BannedIps                     []string
+		HaDatabaseUrl                 string
+		HaReplicaId                   string
+		HaClaimTtl                    time.Duration
+		UnfundedBatchesPolicy         string
+		UnfundedBatchesMaxDelay       time.Duration
+		RespondToTaskGasEstimate      uint64
+		TaskStatePath                 string
+		SecurityEventsIpPortAddress   string
+		SecurityEventsCapacity        int
+		SecurityEventsFile            string
+		TelemetryTransport            string
+		TelemetryOtlpEndpoint         string
+		TelemetryOtlpInsecure         bool
+		TelemetrySampleOneIn          uint64
+		TelemetrySampleProbability    float64
+		TelemetrySpillDir             string
+		TelemetrySpillMaxFileSize     int64
+		TelemetrySpillMaxFiles        int
+		TelemetryApiToken             string
+		TelemetryBreakerThreshold     int
+		TelemetryBreakerProbeInterval time.Duration
+		AdminIpPortAddress            string
+		TelemetryGrpcEndpoint         string
+		TelemetryGrpcInsecure         bool
+		TelemetryGrpcFlushInterval    time.Duration
 	}
 }
 
 type AggregatorConfigFromYaml struct {
 	Aggregator struct {
-		ServerIpPortAddress           string         `yaml:"server_ip_port_address"`
-		BlsPublicKeyCompendiumAddress common.Address `yaml:"bls_public_key_compendium_address"`
-		AvsServiceManagerAddress      common.Address `yaml:"avs_service_manager_address"`
-		EnableMetrics                 bool           `yaml:"enable_metrics"`
-		MetricsIpPortAddress          string         `yaml:"metrics_ip_port_address"`
-		TelemetryIpPortAddress        string         `yaml:"telemetry_ip_port_address"`
-		GarbageCollectorPeriod        time.Duration  `yaml:"garbage_collector_period"`
-		GarbageCollectorTasksAge      uint64         `yaml:"garbage_collector_tasks_age"`
-		GarbageCollectorTasksInterval uint64         `yaml:"garbage_collector_tasks_interval"`
-		BlsServiceTaskTimeout         time.Duration  `yaml:"bls_service_task_timeout"`
-		GasBaseBumpPercentage         uint           `yaml:"gas_base_bump_percentage"`
-		GasBumpIncrementalPercentage  uint           `yaml:"gas_bump_incremental_percentage"`
-		GasBumpPercentageLimit        uint           `yaml:"gas_bump_percentage_limit"`
-		TimeToWaitBeforeBump          time.Duration  `yaml:"time_to_wait_before_bump"`
+		ServerIpPortAddress           string            `yaml:"server_ip_port_address"`
+		BlsPublicKeyCompendiumAddress common.Address    `yaml:"bls_public_key_compendium_address"`
+		AvsServiceManagerAddress      common.Address    `yaml:"avs_service_manager_address"`
+		EnableMetrics                 bool              `yaml:"enable_metrics"`
+		MetricsIpPortAddress          string            `yaml:"metrics_ip_port_address"`
+		TelemetryIpPortAddress        string            `yaml:"telemetry_ip_port_address"`
+		GarbageCollectorPeriod        time.Duration     `yaml:"garbage_collector_period"`
+		GarbageCollectorTasksAge      uint64            `yaml:"garbage_collector_tasks_age"`
+		GarbageCollectorTasksInterval uint64            `yaml:"garbage_collector_tasks_interval"`
+		BlsServiceTaskTimeout         time.Duration     `yaml:"bls_service_task_timeout"`
+		GasBaseBumpPercentage         uint              `yaml:"gas_base_bump_percentage"`
+		GasBumpIncrementalPercentage  uint              `yaml:"gas_bump_incremental_percentage"`
+		GasBumpPercentageLimit        uint              `yaml:"gas_bump_percentage_limit"`
+		TimeToWaitBeforeBump          time.Duration     `yaml:"time_to_wait_before_bump"`
+		PinnedVerifierVersions        map[string]string `yaml:"pinned_verifier_versions"`
+		AuthorizedBatchers            []common.Address  `yaml:"authorized_batchers"`
+		RpcMaxMessageSize             int64             `yaml:"rpc_max_message_size"`
+		RpcMaxConnections             int               `yaml:"rpc_max_connections"`
+		RpcIdleTimeout                time.Duration     `yaml:"rpc_idle_timeout"`
+		RpcReadTimeout                time.Duration     `yaml:"rpc_read_timeout"`
+		RpcWriteTimeout               time.Duration     `yaml:"rpc_write_timeout"`
+		IpRequestsPerSecond           float64           `yaml:"ip_requests_per_second"`
+		IpBurst                       int               `yaml:"ip_burst"`
+		IpBanThreshold                int               `yaml:"ip_ban_threshold"`
+		IpBanDuration                 time.Duration     `yaml:"ip_ban_duration"`
+		BannedIps                     []string          `yaml:"banned_ips"`
+		HaDatabaseUrl                 string            `yaml:"ha_database_url"`
+		HaReplicaId                   string            `yaml:"ha_replica_id"`
+		HaClaimTtl                    time.Duration     `yaml:"ha_claim_ttl"`
+		UnfundedBatchesPolicy         string            `yaml:"unfunded_batches_policy"`
+		UnfundedBatchesMaxDelay       time.Duration     `yaml:"unfunded_batches_max_delay"`
+		RespondToTaskGasEstimate      uint64            `yaml:"respond_to_task_gas_estimate"`
+		TaskStatePath                 string            `yaml:"task_state_path"`
+		SecurityEventsIpPortAddress   string            `yaml:"security_events_ip_port_address"`
+		SecurityEventsCapacity        int               `yaml:"security_events_capacity"`
+		SecurityEventsFile            string            `yaml:"security_events_file"`
+		TelemetryTransport            string            `yaml:"telemetry_transport"`
+		TelemetryOtlpEndpoint         string            `yaml:"telemetry_otlp_endpoint"`
+		TelemetryOtlpInsecure         bool              `yaml:"telemetry_otlp_insecure"`
+		TelemetrySampleOneIn          uint64            `yaml:"telemetry_sample_one_in"`
+		TelemetrySampleProbability    float64           `yaml:"telemetry_sample_probability"`
+		TelemetrySpillDir             string            `yaml:"telemetry_spill_dir"`
+		TelemetrySpillMaxFileSize     int64             `yaml:"telemetry_spill_max_file_size"`
+		TelemetrySpillMaxFiles        int               `yaml:"telemetry_spill_max_files"`
+		TelemetryApiToken             string            `yaml:"telemetry_api_token"`
+		TelemetryBreakerThreshold     int               `yaml:"telemetry_breaker_threshold"`
+		TelemetryBreakerProbeInterval time.Duration     `yaml:"telemetry_breaker_probe_interval"`
+		AdminIpPortAddress            string            `yaml:"admin_ip_port_address"`
+		TelemetryGrpcEndpoint         string            `yaml:"telemetry_grpc_endpoint"`
+		TelemetryGrpcInsecure         bool              `yaml:"telemetry_grpc_insecure"`
+		TelemetryGrpcFlushInterval    time.Duration     `yaml:"telemetry_grpc_flush_interval"`
 	} `yaml:"aggregator"`
 }
 
@@ -67,6 +145,11 @@ func NewAggregatorConfig(configFilePath string) *AggregatorConfig {
 		log.Fatal("Error reading ecdsa config: ")
 	}
 
+	respondToTaskEcdsaConfig := NewRespondToTaskEcdsaConfig(configFilePath, baseConfig.ChainId)
+	if respondToTaskEcdsaConfig != nil && respondToTaskEcdsaConfig.PrivateKey.Equal(ecdsaConfig.PrivateKey) {
+		log.Fatal("Respond to task ecdsa key must be different from the ecdsa key")
+	}
+
 	blsConfig := NewBlsConfig(configFilePath)
 	if blsConfig == nil {
 		log.Fatal("Error reading bls config: ")
@@ -79,9 +162,11 @@ func NewAggregatorConfig(configFilePath string) *AggregatorConfig {
 	}
 
 	return &AggregatorConfig{
-		BaseConfig:  baseConfig,
-		EcdsaConfig: ecdsaConfig,
-		BlsConfig:   blsConfig,
+		BaseConfig:               baseConfig,
+		EcdsaConfig:              ecdsaConfig,
+		RespondToTaskEcdsaConfig: respondToTaskEcdsaConfig,
+		BlsConfig:                blsConfig,
+		MetricsConfig:            NewMetricsConfig(configFilePath),
 		Aggregator: struct {
 			ServerIpPortAddress           string
 			BlsPublicKeyCompendiumAddress common.Address
@@ -97,6 +182,43 @@ func NewAggregatorConfig(configFilePath string) *AggregatorConfig {
 			GasBumpIncrementalPercentage  uint
 			GasBumpPercentageLimit        uint
 			TimeToWaitBeforeBump          time.Duration
+			PinnedVerifierVersions        map[string]string
+			AuthorizedBatchers            []common.Address
+			RpcMaxMessageSize             int64
+			RpcMaxConnections             int
+			RpcIdleTimeout                time.Duration
+			RpcReadTimeout                time.Duration
+			RpcWriteTimeout               time.Duration
+			IpRequestsPerSecond           float64
+			IpBurst                       int
+			IpBanThreshold                int
+			IpBanDuration                 time.Duration
+			BannedIps                     []string
+			HaDatabaseUrl                 string
+			HaReplicaId                   string
+			HaClaimTtl                    time.Duration
+			UnfundedBatchesPolicy         string
+			UnfundedBatchesMaxDelay       time.Duration
+			RespondToTaskGasEstimate      uint64
+			TaskStatePath                 string
+			SecurityEventsIpPortAddress   string
+			SecurityEventsCapacity        int
+			SecurityEventsFile            string
+			TelemetryTransport            string
+			TelemetryOtlpEndpoint         string
+			TelemetryOtlpInsecure         bool
+			TelemetrySampleOneIn          uint64
+			TelemetrySampleProbability    float64
+			TelemetrySpillDir             string
+			TelemetrySpillMaxFileSize     int64
+			TelemetrySpillMaxFiles        int
+			TelemetryApiToken             string
+			TelemetryBreakerThreshold     int
+			TelemetryBreakerProbeInterval time.Duration
+			AdminIpPortAddress            string
+			TelemetryGrpcEndpoint         string
+			TelemetryGrpcInsecure         bool
+			TelemetryGrpcFlushInterval    time.Duration
 		}(aggregatorConfigFromYaml.Aggregator),
 	}
 }
@@ -13,6 +13,7 @@ type AlignedLayerDeploymentConfig struct {
 	AlignedLayerServiceManagerAddr         common.Address
 	AlignedLayerRegistryCoordinatorAddr    common.Address
 	AlignedLayerOperatorStateRetrieverAddr common.Address
+	BatcherPaymentServiceAddr              common.Address
 }
 
 type AlignedLayerDeploymentConfigFromJson struct {
@@ -20,6 +21,7 @@ type AlignedLayerDeploymentConfigFromJson struct {
 		AlignedLayerServiceManagerAddr         common.Address `json:"alignedLayerServiceManager"`
 		AlignedLayerRegistryCoordinatorAddr    common.Address `json:"registryCoordinator"`
 		AlignedLayerOperatorStateRetrieverAddr common.Address `json:"operatorStateRetriever"`
+		BatcherPaymentServiceAddr              common.Address `json:"batcherPaymentService"`
 	} `json:"addresses"`
 }
 
@@ -52,5 +54,6 @@ func NewAlignedLayerDeploymentConfig(alignedLayerDeploymentFilePath string) *Ali
 		AlignedLayerServiceManagerAddr:         alignedLayerDeploymentConfigFromJson.Addresses.AlignedLayerServiceManagerAddr,
 		AlignedLayerRegistryCoordinatorAddr:    alignedLayerDeploymentConfigFromJson.Addresses.AlignedLayerRegistryCoordinatorAddr,
 		AlignedLayerOperatorStateRetrieverAddr: alignedLayerDeploymentConfigFromJson.Addresses.AlignedLayerOperatorStateRetrieverAddr,
+		BatcherPaymentServiceAddr:              alignedLayerDeploymentConfigFromJson.Addresses.BatcherPaymentServiceAddr,
 	}
 }
@@ -37,6 +37,9 @@ type BaseConfig struct {
 	EthWsUrlFallback             string
 	EigenMetricsIpPortAddress    string
 	ChainId                      *big.Int
+	// EthRpcMetricsRegistry has the requests and latency metrics of each eth endpoint, labeled
+	// with its name as avs_name: ethRpc, ethRpcFallback, ethWs or ethWsFallback
+	EthRpcMetricsRegistry *prometheus.Registry
 }
 
 type BaseConfigFromYaml struct {
@@ -100,13 +103,13 @@ func NewBaseConfig(configFilePath string) *BaseConfig {
 		log.Fatal("Eth ws url or fallback is empty")
 	}
 
+	// The endpoints are told apart by the collectors name, so they can share the registry
 	reg := prometheus.NewRegistry()
 	rpcCallsCollector := rpccalls.NewCollector("ethWs", reg)
 	ethWsClient, err := eth.NewInstrumentedClient(baseConfigFromYaml.EthWsUrl, rpcCallsCollector)
 	if err != nil {
 		log.Fatal("Error initializing eth ws client: ", err)
 	}
-	reg = prometheus.NewRegistry()
 	rpcCallsCollector = rpccalls.NewCollector("ethWsFallback", reg)
 	ethWsClientFallback, err := eth.NewInstrumentedClient(baseConfigFromYaml.EthWsUrlFallback, rpcCallsCollector)
 	if err != nil {
@@ -117,15 +120,13 @@ func NewBaseConfig(configFilePath string) *BaseConfig {
 		log.Fatal("Eth rpc url is empty")
 	}
 
-	reg = prometheus.NewRegistry()
 	rpcCallsCollector = rpccalls.NewCollector("ethRpc", reg)
 	ethRpcClient, err := eth.NewInstrumentedClient(baseConfigFromYaml.EthRpcUrl, rpcCallsCollector)
 	if err != nil {
 		log.Fatal("Error initializing eth rpc client: ", err)
 	}
 
-	reg = prometheus.NewRegistry()
-	rpcCallsCollector = rpccalls.NewCollector("ethRpc", reg)
+	rpcCallsCollector = rpccalls.NewCollector("ethRpcFallback", reg)
 	ethRpcClientFallback, err := eth.NewInstrumentedClient(baseConfigFromYaml.EthRpcUrlFallback, rpcCallsCollector)
 	if err != nil {
 		log.Fatal("Error initializing eth rpc client fallback: ", err)
@@ -155,5 +156,6 @@ func NewBaseConfig(configFilePath string) *BaseConfig {
 		EthWsUrlFallback:             baseConfigFromYaml.EthWsUrlFallback,
 		EigenMetricsIpPortAddress:    baseConfigFromYaml.EigenMetricsIpPortAddress,
 		ChainId:                      chainId,
+		EthRpcMetricsRegistry:        reg,
 	}
 }
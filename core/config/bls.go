@@ -3,20 +3,56 @@ package config
 import (
 	"errors"
 	"log"
+	"net"
 	"os"
+	"strings"
 
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/yetanotherco/aligned_layer/core/utils"
 )
 
 type BlsConfig struct {
-	KeyPair *bls.KeyPair
+	// KeyPair is nil when signing with a remote signer
+	KeyPair      *bls.KeyPair
+	RemoteSigner *RemoteBlsSignerConfig
+}
+
+// RemoteBlsSignerConfig is the config of a Cerberus compatible remote BLS signer,
+// which keeps the BLS private key out of the operator host.
+type RemoteBlsSignerConfig struct {
+	Url string
+	// PublicKeyG1Hex identifies the key in the remote signer
+	PublicKeyG1Hex string
+	PublicKeyG1    *bls.G1Point
+	PublicKeyG2    *bls.G2Point
+	Password       string
+	ApiKey         string
+	// TlsCaCertPath is the CA certificate used to verify the signer, the connection isn't encrypted if empty,
+	// which is only allowed without password and api key or for a loopback signer
+	TlsCaCertPath string
+}
+
+// PublicKeyG1 returns the operator BLS public key, whether it's signing with a local key or a remote signer.
+func (c *BlsConfig) PublicKeyG1() *bls.G1Point {
+	if c.RemoteSigner != nil {
+		return c.RemoteSigner.PublicKeyG1
+	}
+	return c.KeyPair.GetPubKeyG1()
 }
 
 type BlsConfigFromYaml struct {
 	Bls struct {
 		PrivateKeyStorePath     string `yaml:"private_key_store_path"`
 		PrivateKeyStorePassword string `yaml:"private_key_store_password"`
+		RemoteSigner            struct {
+			Url           string `yaml:"url"`
+			PublicKeyG1   string `yaml:"public_key_g1"`
+			PublicKeyG2   string `yaml:"public_key_g2"`
+			Password      string `yaml:"password"`
+			ApiKey        string `yaml:"api_key"`
+			TlsCaCertPath string `yaml:"tls_ca_cert_path"`
+		} `yaml:"remote_signer"`
 	} `yaml:"bls"`
 }
 
@@ -31,6 +67,29 @@ func NewBlsConfig(blsConfigFilePath string) *BlsConfig {
 		log.Fatal("Error reading bls config: ", err)
 	}
 
+	remoteSigner := blsConfigFromYaml.Bls.RemoteSigner
+	if remoteSigner.Url != "" {
+		publicKeyG1, publicKeyG2, err := parseBlsPublicKeys(remoteSigner.PublicKeyG1, remoteSigner.PublicKeyG2)
+		if err != nil {
+			log.Fatal("Error reading bls remote signer public keys: ", err)
+		}
+		err = validateRemoteSignerTransport(remoteSigner.Url, remoteSigner.Password, remoteSigner.ApiKey, remoteSigner.TlsCaCertPath)
+		if err != nil {
+			log.Fatal("Error reading bls remote signer config: ", err)
+		}
+		return &BlsConfig{
+			RemoteSigner: &RemoteBlsSignerConfig{
+				Url:            remoteSigner.Url,
+				PublicKeyG1Hex: strings.TrimPrefix(remoteSigner.PublicKeyG1, "0x"),
+				PublicKeyG1:    publicKeyG1,
+				PublicKeyG2:    publicKeyG2,
+				Password:       remoteSigner.Password,
+				ApiKey:         remoteSigner.ApiKey,
+				TlsCaCertPath:  remoteSigner.TlsCaCertPath,
+			},
+		}
+	}
+
 	if blsConfigFromYaml.Bls.PrivateKeyStorePath == "" {
 		log.Fatal("Bls private key store path is empty")
 	}
@@ -44,3 +103,73 @@ func NewBlsConfig(blsConfigFilePath string) *BlsConfig {
 		KeyPair: blsKeyPair,
 	}
 }
+
+// validateRemoteSignerTransport fails if the remote signer password or api key would be sent in plaintext
+// to a signer outside the operator host.
+func validateRemoteSignerTransport(url string, password string, apiKey string, tlsCaCertPath string) error {
+	if tlsCaCertPath != "" || (password == "" && apiKey == "") || isLoopbackTarget(url) {
+		return nil
+	}
+	return errors.New("tls_ca_cert_path is required to send the password or api key to a remote signer outside the operator host")
+}
+
+// isLoopbackTarget tells if the gRPC target is in the operator host, e.g. localhost:50051,
+// dns:///127.0.0.1:50051 or a unix socket
+func isLoopbackTarget(target string) bool {
+	if strings.HasPrefix(target, "unix:") || strings.HasPrefix(target, "unix-abstract:") {
+		return true
+	}
+	// targets with a resolver scheme can have an authority, e.g. dns://8.8.8.8/localhost:50051
+	if scheme := strings.Index(target, "://"); scheme >= 0 {
+		authority := target[scheme+len("://"):]
+		endpoint := strings.Index(authority, "/")
+		if endpoint < 0 {
+			return false
+		}
+		target = authority[endpoint+1:]
+	}
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		host = target
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// parseBlsPublicKeys parses the hex encoded G1 and G2 public keys of a BLS key, either compressed
+// or not, checking they belong to the same private key.
+func parseBlsPublicKeys(publicKeyG1Hex string, publicKeyG2Hex string) (*bls.G1Point, *bls.G2Point, error) {
+	if publicKeyG1Hex == "" || publicKeyG2Hex == "" {
+		return nil, nil, errors.New("both the G1 and G2 public keys are required")
+	}
+
+	publicKeyG1Bytes, err := hexutil.Decode("0x" + strings.TrimPrefix(publicKeyG1Hex, "0x"))
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKeyG1 := bls.NewZeroG1Point()
+	if _, err = publicKeyG1.SetBytes(publicKeyG1Bytes); err != nil {
+		return nil, nil, err
+	}
+
+	publicKeyG2Bytes, err := hexutil.Decode("0x" + strings.TrimPrefix(publicKeyG2Hex, "0x"))
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKeyG2 := bls.NewZeroG2Point()
+	if _, err = publicKeyG2.SetBytes(publicKeyG2Bytes); err != nil {
+		return nil, nil, err
+	}
+
+	equivalent, err := publicKeyG1.VerifyEquivalence(publicKeyG2)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !equivalent {
+		return nil, nil, errors.New("the G1 and G2 public keys don't belong to the same key")
+	}
+	return publicKeyG1, publicKeyG2, nil
+}
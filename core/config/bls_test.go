@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestValidateRemoteSignerTransport(t *testing.T) {
+	for _, test := range []struct {
+		url, password, apiKey, tlsCaCertPath string
+		valid                                bool
+	}{
+		{url: "signer.example.com:50051", password: "secret", tlsCaCertPath: "ca.pem", valid: true},
+		{url: "signer.example.com:50051", valid: true},
+		{url: "signer.example.com:50051", password: "secret"},
+		{url: "signer.example.com:50051", apiKey: "api-key"},
+		{url: "dns:///signer.example.com:50051", apiKey: "api-key"},
+		{url: "dns://127.0.0.1/signer.example.com:50051", apiKey: "api-key"},
+		{url: "10.0.0.1:50051", password: "secret"},
+		{url: "localhost:50051", password: "secret", apiKey: "api-key", valid: true},
+		{url: "127.0.0.1:50051", password: "secret", valid: true},
+		{url: "[::1]:50051", password: "secret", valid: true},
+		{url: "dns:///localhost:50051", password: "secret", valid: true},
+		{url: "unix:///run/signer.sock", password: "secret", valid: true},
+	} {
+		err := validateRemoteSignerTransport(test.url, test.password, test.apiKey, test.tlsCaCertPath)
+		if test.valid && err != nil {
+			t.Errorf("Expected the config of %s to be accepted, got %v", test.url, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("Expected the config of %s to be rejected", test.url)
+		}
+	}
+}
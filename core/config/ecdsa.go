@@ -24,6 +24,13 @@ type EcdsaConfigFromYaml struct {
 	} `yaml:"ecdsa"`
 }
 
+type RespondToTaskEcdsaConfigFromYaml struct {
+	RespondToTaskEcdsa struct {
+		PrivateKeyStorePath     string `yaml:"private_key_store_path"`
+		PrivateKeyStorePassword string `yaml:"private_key_store_password"`
+	} `yaml:"respond_to_task_ecdsa"`
+}
+
 func NewEcdsaConfig(ecdsaConfigFilePath string, chainId *big.Int) *EcdsaConfig {
 	if _, err := os.Stat(ecdsaConfigFilePath); errors.Is(err, os.ErrNotExist) {
 		log.Fatal("Setup ecdsa config file does not exist")
@@ -54,3 +61,34 @@ func NewEcdsaConfig(ecdsaConfigFilePath string, chainId *big.Int) *EcdsaConfig {
 		Signer:     privateKeySigner,
 	}
 }
+
+// NewRespondToTaskEcdsaConfig reads the key the aggregator sends the task responses with, returning
+// nil if it isn't set, in which case they are sent with the ecdsa key. Keeping this hot key apart
+// from the one of the administrative and registration transactions limits what a leak of it exposes.
+func NewRespondToTaskEcdsaConfig(configFilePath string, chainId *big.Int) *EcdsaConfig {
+	var respondToTaskEcdsaConfigFromYaml RespondToTaskEcdsaConfigFromYaml
+	err := utils.ReadYamlConfig(configFilePath, &respondToTaskEcdsaConfigFromYaml)
+	if err != nil {
+		log.Fatal("Error reading respond to task ecdsa config: ", err)
+	}
+
+	respondToTaskEcdsa := respondToTaskEcdsaConfigFromYaml.RespondToTaskEcdsa
+	if respondToTaskEcdsa.PrivateKeyStorePath == "" {
+		return nil
+	}
+
+	ecdsaKeyPair, err := ecdsa2.ReadKey(respondToTaskEcdsa.PrivateKeyStorePath, respondToTaskEcdsa.PrivateKeyStorePassword)
+	if err != nil {
+		log.Fatal("Error reading respond to task ecdsa private key from file: ", err)
+	}
+
+	privateKeySigner, err := signer.NewPrivateKeySigner(ecdsaKeyPair, chainId)
+	if err != nil {
+		log.Fatal("Error creating respond to task private key signer: ", err)
+	}
+
+	return &EcdsaConfig{
+		PrivateKey: ecdsaKeyPair,
+		Signer:     privateKeySigner,
+	}
+}
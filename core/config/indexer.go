@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+type IndexerConfig struct {
+	BaseConfig *BaseConfig
+	Indexer    struct {
+		DatabaseUrl      string
+		FromBlock        uint64
+		BlockRange       uint64
+		PollInterval     time.Duration
+		ApiIpPortAddress string
+		IndexProofs      bool
+		MaxBatchSize     int64
+		FinalityDepth    uint64
+	}
+}
+
+type IndexerConfigFromYaml struct {
+	Indexer struct {
+		DatabaseUrl      string        `yaml:"database_url"`
+		FromBlock        uint64        `yaml:"from_block"`
+		BlockRange       uint64        `yaml:"block_range"`
+		PollInterval     time.Duration `yaml:"poll_interval"`
+		ApiIpPortAddress string        `yaml:"api_ip_port_address"`
+		IndexProofs      bool          `yaml:"index_proofs"`
+		MaxBatchSize     int64         `yaml:"max_batch_size"`
+		FinalityDepth    uint64        `yaml:"finality_depth"`
+	} `yaml:"indexer"`
+}
+
+func NewIndexerConfig(configFilePath string) *IndexerConfig {
+	if _, err := os.Stat(configFilePath); errors.Is(err, os.ErrNotExist) {
+		log.Fatal("Setup config file does not exist")
+	}
+
+	baseConfig := NewBaseConfig(configFilePath)
+	if baseConfig == nil {
+		log.Fatal("Error reading base config: ")
+	}
+
+	var indexerConfigFromYaml IndexerConfigFromYaml
+	err := utils.ReadYamlConfig(configFilePath, &indexerConfigFromYaml)
+	if err != nil {
+		log.Fatal("Error reading indexer config: ", err)
+	}
+
+	indexer := indexerConfigFromYaml.Indexer
+	if indexer.DatabaseUrl == "" {
+		log.Fatal("Indexer database url is empty")
+	}
+	if indexer.BlockRange == 0 || indexer.PollInterval <= 0 {
+		log.Fatal("Indexer block range and poll interval must be positive")
+	}
+	if indexer.ApiIpPortAddress == "" {
+		log.Fatal("Indexer api ip port address is empty")
+	}
+	if indexer.IndexProofs && indexer.MaxBatchSize <= 0 {
+		log.Fatal("Indexer max batch size must be positive to index proofs")
+	}
+
+	return &IndexerConfig{
+		BaseConfig: baseConfig,
+		Indexer: struct {
+			DatabaseUrl      string
+			FromBlock        uint64
+			BlockRange       uint64
+			PollInterval     time.Duration
+			ApiIpPortAddress string
+			IndexProofs      bool
+			MaxBatchSize     int64
+			FinalityDepth    uint64
+		}(indexer),
+	}
+}
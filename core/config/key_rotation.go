@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	ecdsa2 "github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
+	"github.com/Layr-Labs/eigensdk-go/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// KeyRotationConfig is the config of an operator rotating its keys. The operator identity is its
+// ECDSA address and BLS key, which can't be changed once registered, so rotating the keys means
+// registering a new operator and retiring the previous one. Until OverlapUntil, the operator
+// signs the task responses with both identities, so no signing window is missed meanwhile.
+type KeyRotationConfig struct {
+	PreviousOperatorAddress common.Address
+	PreviousBlsKeyPair      *bls.KeyPair
+	OverlapUntil            time.Time
+	// The previous ECDSA key is only needed to retire the previous identity, so it's loaded on demand
+	previousEcdsaPrivateKeyStorePath     string
+	previousEcdsaPrivateKeyStorePassword string
+}
+
+type KeyRotationConfigFromYaml struct {
+	KeyRotation struct {
+		PreviousOperatorAddress              common.Address `yaml:"previous_operator_address"`
+		PreviousBlsPrivateKeyStorePath       string         `yaml:"previous_bls_private_key_store_path"`
+		PreviousBlsPrivateKeyStorePassword   string         `yaml:"previous_bls_private_key_store_password"`
+		PreviousEcdsaPrivateKeyStorePath     string         `yaml:"previous_ecdsa_private_key_store_path"`
+		PreviousEcdsaPrivateKeyStorePassword string         `yaml:"previous_ecdsa_private_key_store_password"`
+		OverlapUntil                         time.Time      `yaml:"overlap_until"`
+	} `yaml:"key_rotation"`
+}
+
+// NewKeyRotationConfig reads the key rotation config, returning nil if the operator isn't rotating its keys.
+func NewKeyRotationConfig(configFilePath string) *KeyRotationConfig {
+	var keyRotationConfigFromYaml KeyRotationConfigFromYaml
+	err := utils.ReadYamlConfig(configFilePath, &keyRotationConfigFromYaml)
+	if err != nil {
+		log.Fatal("Error reading key rotation config: ", err)
+	}
+
+	keyRotation := keyRotationConfigFromYaml.KeyRotation
+	if keyRotation.PreviousOperatorAddress == (common.Address{}) {
+		return nil
+	}
+	if keyRotation.PreviousBlsPrivateKeyStorePath == "" {
+		log.Fatal("Key rotation previous bls private key store path is empty")
+	}
+	if keyRotation.OverlapUntil.IsZero() {
+		log.Fatal("Key rotation overlap_until is not set")
+	}
+
+	previousBlsKeyPair, err := bls.ReadPrivateKeyFromFile(keyRotation.PreviousBlsPrivateKeyStorePath, keyRotation.PreviousBlsPrivateKeyStorePassword)
+	if err != nil {
+		log.Fatal("Error reading previous bls private key from file: ", err)
+	}
+
+	return &KeyRotationConfig{
+		PreviousOperatorAddress:              keyRotation.PreviousOperatorAddress,
+		PreviousBlsKeyPair:                   previousBlsKeyPair,
+		OverlapUntil:                         keyRotation.OverlapUntil,
+		previousEcdsaPrivateKeyStorePath:     keyRotation.PreviousEcdsaPrivateKeyStorePath,
+		previousEcdsaPrivateKeyStorePassword: keyRotation.PreviousEcdsaPrivateKeyStorePassword,
+	}
+}
+
+// PreviousEcdsaConfig loads the ECDSA key of the previous operator identity, to retire it.
+func (c *KeyRotationConfig) PreviousEcdsaConfig(chainId *big.Int) (*EcdsaConfig, error) {
+	if c.previousEcdsaPrivateKeyStorePath == "" {
+		return nil, errors.New("key rotation previous ecdsa private key store path is empty")
+	}
+	previousEcdsaKey, err := ecdsa2.ReadKey(c.previousEcdsaPrivateKeyStorePath, c.previousEcdsaPrivateKeyStorePassword)
+	if err != nil {
+		return nil, fmt.Errorf("error reading previous ecdsa private key from file: %w", err)
+	}
+	privateKeySigner, err := signer.NewPrivateKeySigner(previousEcdsaKey, chainId)
+	if err != nil {
+		return nil, fmt.Errorf("error creating previous private key signer: %w", err)
+	}
+	return &EcdsaConfig{
+		PrivateKey: previousEcdsaKey,
+		Signer:     privateKeySigner,
+	}, nil
+}
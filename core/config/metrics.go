@@ -0,0 +1,38 @@
+package config
+
+import (
+	"log"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/core/utils"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+type MetricsConfigFromYaml struct {
+	Metrics struct {
+		HistogramBuckets    map[string][]float64 `yaml:"histogram_buckets"`
+		PushgatewayUrl      string               `yaml:"pushgateway_url"`
+		PushgatewayJob      string               `yaml:"pushgateway_job"`
+		PushgatewayInstance string               `yaml:"pushgateway_instance"`
+		FinalizationSlo     time.Duration        `yaml:"finalization_slo"`
+	} `yaml:"metrics"`
+}
+
+// NewMetricsConfig reads the optional metrics section of the config
+func NewMetricsConfig(configFilePath string) metrics.Config {
+	var metricsConfigFromYaml MetricsConfigFromYaml
+	err := utils.ReadYamlConfig(configFilePath, &metricsConfigFromYaml)
+	if err != nil {
+		log.Fatal("Error reading metrics config: ", err)
+	}
+
+	return metrics.Config{
+		HistogramBuckets: metricsConfigFromYaml.Metrics.HistogramBuckets,
+		Pushgateway: metrics.PushgatewayConfig{
+			Url:      metricsConfigFromYaml.Metrics.PushgatewayUrl,
+			Job:      metricsConfigFromYaml.Metrics.PushgatewayJob,
+			Instance: metricsConfigFromYaml.Metrics.PushgatewayInstance,
+		},
+		FinalizationSlo: metricsConfigFromYaml.Metrics.FinalizationSlo,
+	}
+}
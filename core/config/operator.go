@@ -4,48 +4,132 @@ import (
 	"errors"
 	"log"
 	"os"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/yetanotherco/aligned_layer/core/utils"
+	"github.com/yetanotherco/aligned_layer/metrics"
 )
 
+// VerificationLimits bounds the resources a single proof verification can use.
+// Zero values mean no limit. MaxMemory can only be set for sandboxed verifiers.
+type VerificationLimits struct {
+	Timeout      time.Duration `yaml:"timeout"`
+	MaxInputSize int64         `yaml:"max_input_size"`
+	MaxMemory    int64         `yaml:"max_memory"`
+}
+
+// WasmPluginConfig is a verifier plugin compiled to WASM. The plugin is only loaded if the
+// keccak256 hash of the module matches Hash, so operators choose exactly which code they run.
+type WasmPluginConfig struct {
+	Path string `yaml:"path"`
+	Hash string `yaml:"hash"`
+}
+
 type OperatorConfig struct {
 	BaseConfig                   *BaseConfig
 	BlsConfig                    *BlsConfig
 	AlignedLayerDeploymentConfig *AlignedLayerDeploymentConfig
+	// KeyRotation is nil unless the operator is rotating its keys
+	KeyRotation   *KeyRotationConfig
+	MetricsConfig metrics.Config
 
 	Operator struct {
-		AggregatorServerIpPortAddress string
-		OperatorTrackerIpPortAddress  string
-		Address                       common.Address
-		EarningsReceiverAddress       common.Address
-		DelegationApproverAddress     common.Address
-		StakerOptOutWindowBlocks      int
-		MetadataUrl                   string
-		RegisterOperatorOnStartup     bool
-		EnableMetrics                 bool
-		MetricsIpPortAddress          string
-		MaxBatchSize                  int64
-		LastProcessedBatchFilePath    string
+		AggregatorServerIpPortAddress           string
+		AggregatorServerFallbackIpPortAddresses []string
+		OperatorTrackerIpPortAddress            string
+		Address                                 common.Address
+		EarningsReceiverAddress                 common.Address
+		DelegationApproverAddress               common.Address
+		StakerOptOutWindowBlocks                int
+		MetadataUrl                             string
+		RegisterOperatorOnStartup               bool
+		EnableMetrics                           bool
+		MetricsIpPortAddress                    string
+		MaxBatchSize                            int64
+		LastProcessedBatchFilePath              string
+		MaxVerificationWorkers                  int
+		ProvingSystemWorkerLimits               map[string]int
+		VerificationCacheSize                   int
+		VerificationCacheFilePath               string
+		VerificationLimits                      map[string]VerificationLimits
+		SandboxVerifiers                        bool
+		DisabledProvingSystems                  []string
+		ExperimentalProvingSystems              []string
+		StreamBatches                           bool
+		GpuVerification                         bool
+		GpuVerificationBenchmark                bool
+		WasmPlugins                             []WasmPluginConfig
+		IpfsGateways                            []string
+		BatchMirrors                            []string
+		CelestiaRpcUrl                          string
+		CelestiaAuthToken                       string
+		EigenDAProxyUrl                         string
+		BatchCacheDir                           string
+		BatchCacheSize                          int64
+		P2PListenAddresses                      []string
+		P2PBootstrapPeers                       []string
+		P2PKeyPath                              string
+		BatchDownloadRateLimit                  int64
+		BatchDownloadMaxConnections             int
+		ShutdownDrainTimeout                    time.Duration
+		StatusApiIpPortAddress                  string
+		VersionCheckUrl                         string
+		VersionCheckSignerAddress               common.Address
+		VersionCheckInterval                    time.Duration
+		TelemetryIpPortAddress                  string
+		TelemetryApiToken                       string
 	}
 }
 
 type OperatorConfigFromYaml struct {
 	Operator struct {
-		AggregatorServerIpPortAddress string         `yaml:"aggregator_rpc_server_ip_port_address"`
-		OperatorTrackerIpPortAddress  string         `yaml:"operator_tracker_ip_port_address"`
-		Address                       common.Address `yaml:"address"`
-		EarningsReceiverAddress       common.Address `yaml:"earnings_receiver_address"`
-		DelegationApproverAddress     common.Address `yaml:"delegation_approver_address"`
-		StakerOptOutWindowBlocks      int            `yaml:"staker_opt_out_window_blocks"`
-		MetadataUrl                   string         `yaml:"metadata_url"`
-		RegisterOperatorOnStartup     bool           `yaml:"register_operator_on_startup"`
-		EnableMetrics                 bool           `yaml:"enable_metrics"`
-		MetricsIpPortAddress          string         `yaml:"metrics_ip_port_address"`
-		MaxBatchSize                  int64          `yaml:"max_batch_size"`
-		LastProcessedBatchFilePath    string         `yaml:"last_processed_batch_filepath"`
+		AggregatorServerIpPortAddress           string                        `yaml:"aggregator_rpc_server_ip_port_address"`
+		AggregatorServerFallbackIpPortAddresses []string                      `yaml:"aggregator_rpc_server_fallback_ip_port_addresses"`
+		OperatorTrackerIpPortAddress            string                        `yaml:"operator_tracker_ip_port_address"`
+		Address                                 common.Address                `yaml:"address"`
+		EarningsReceiverAddress                 common.Address                `yaml:"earnings_receiver_address"`
+		DelegationApproverAddress               common.Address                `yaml:"delegation_approver_address"`
+		StakerOptOutWindowBlocks                int                           `yaml:"staker_opt_out_window_blocks"`
+		MetadataUrl                             string                        `yaml:"metadata_url"`
+		RegisterOperatorOnStartup               bool                          `yaml:"register_operator_on_startup"`
+		EnableMetrics                           bool                          `yaml:"enable_metrics"`
+		MetricsIpPortAddress                    string                        `yaml:"metrics_ip_port_address"`
+		MaxBatchSize                            int64                         `yaml:"max_batch_size"`
+		LastProcessedBatchFilePath              string                        `yaml:"last_processed_batch_filepath"`
+		MaxVerificationWorkers                  int                           `yaml:"max_verification_workers"`
+		ProvingSystemWorkerLimits               map[string]int                `yaml:"proving_system_worker_limits"`
+		VerificationCacheSize                   int                           `yaml:"verification_cache_size"`
+		VerificationCacheFilePath               string                        `yaml:"verification_cache_filepath"`
+		VerificationLimits                      map[string]VerificationLimits `yaml:"verification_limits"`
+		SandboxVerifiers                        bool                          `yaml:"sandbox_verifiers"`
+		DisabledProvingSystems                  []string                      `yaml:"disabled_proving_systems"`
+		ExperimentalProvingSystems              []string                      `yaml:"experimental_proving_systems"`
+		StreamBatches                           bool                          `yaml:"stream_batches"`
+		GpuVerification                         bool                          `yaml:"gpu_verification"`
+		GpuVerificationBenchmark                bool                          `yaml:"gpu_verification_benchmark"`
+		WasmPlugins                             []WasmPluginConfig            `yaml:"wasm_plugins"`
+		IpfsGateways                            []string                      `yaml:"ipfs_gateways"`
+		BatchMirrors                            []string                      `yaml:"batch_mirrors"`
+		CelestiaRpcUrl                          string                        `yaml:"celestia_rpc_url"`
+		CelestiaAuthToken                       string                        `yaml:"celestia_auth_token"`
+		EigenDAProxyUrl                         string                        `yaml:"eigenda_proxy_url"`
+		BatchCacheDir                           string                        `yaml:"batch_cache_dir"`
+		BatchCacheSize                          int64                         `yaml:"batch_cache_size"`
+		P2PListenAddresses                      []string                      `yaml:"p2p_listen_addresses"`
+		P2PBootstrapPeers                       []string                      `yaml:"p2p_bootstrap_peers"`
+		P2PKeyPath                              string                        `yaml:"p2p_key_path"`
+		BatchDownloadRateLimit                  int64                         `yaml:"batch_download_rate_limit"`
+		BatchDownloadMaxConnections             int                           `yaml:"batch_download_max_connections"`
+		ShutdownDrainTimeout                    time.Duration                 `yaml:"shutdown_drain_timeout"`
+		StatusApiIpPortAddress                  string                        `yaml:"status_api_ip_port_address"`
+		VersionCheckUrl                         string                        `yaml:"version_check_url"`
+		VersionCheckSignerAddress               common.Address                `yaml:"version_check_signer_address"`
+		VersionCheckInterval                    time.Duration                 `yaml:"version_check_interval"`
+		TelemetryIpPortAddress                  string                        `yaml:"telemetry_ip_port_address"`
+		TelemetryApiToken                       string                        `yaml:"telemetry_api_token"`
 	} `yaml:"operator"`
-	BlsConfigFromYaml   BlsConfigFromYaml   `yaml:"bls"`
+	BlsConfigFromYaml BlsConfigFromYaml `yaml:"bls"`
 }
 
 func NewOperatorConfig(configFilePath string) *OperatorConfig {
@@ -74,19 +158,53 @@ func NewOperatorConfig(configFilePath string) *OperatorConfig {
 		BaseConfig:                   baseConfig,
 		BlsConfig:                    blsConfig,
 		AlignedLayerDeploymentConfig: baseConfig.AlignedLayerDeploymentConfig,
+		KeyRotation:                  NewKeyRotationConfig(configFilePath),
+		MetricsConfig:                NewMetricsConfig(configFilePath),
 		Operator: struct {
-			AggregatorServerIpPortAddress string
-			OperatorTrackerIpPortAddress  string
-			Address                       common.Address
-			EarningsReceiverAddress       common.Address
-			DelegationApproverAddress     common.Address
-			StakerOptOutWindowBlocks      int
-			MetadataUrl                   string
-			RegisterOperatorOnStartup     bool
-			EnableMetrics                 bool
-			MetricsIpPortAddress          string
-			MaxBatchSize                  int64
-			LastProcessedBatchFilePath    string
+			AggregatorServerIpPortAddress           string
+			AggregatorServerFallbackIpPortAddresses []string
+			OperatorTrackerIpPortAddress            string
+			Address                                 common.Address
+			EarningsReceiverAddress                 common.Address
+			DelegationApproverAddress               common.Address
+			StakerOptOutWindowBlocks                int
+			MetadataUrl                             string
+			RegisterOperatorOnStartup               bool
+			EnableMetrics                           bool
+			MetricsIpPortAddress                    string
+			MaxBatchSize                            int64
+			LastProcessedBatchFilePath              string
+			MaxVerificationWorkers                  int
+			ProvingSystemWorkerLimits               map[string]int
+			VerificationCacheSize                   int
+			VerificationCacheFilePath               string
+			VerificationLimits                      map[string]VerificationLimits
+			SandboxVerifiers                        bool
+			DisabledProvingSystems                  []string
+			ExperimentalProvingSystems              []string
+			StreamBatches                           bool
+			GpuVerification                         bool
+			GpuVerificationBenchmark                bool
+			WasmPlugins                             []WasmPluginConfig
+			IpfsGateways                            []string
+			BatchMirrors                            []string
+			CelestiaRpcUrl                          string
+			CelestiaAuthToken                       string
+			EigenDAProxyUrl                         string
+			BatchCacheDir                           string
+			BatchCacheSize                          int64
+			P2PListenAddresses                      []string
+			P2PBootstrapPeers                       []string
+			P2PKeyPath                              string
+			BatchDownloadRateLimit                  int64
+			BatchDownloadMaxConnections             int
+			ShutdownDrainTimeout                    time.Duration
+			StatusApiIpPortAddress                  string
+			VersionCheckUrl                         string
+			VersionCheckSignerAddress               common.Address
+			VersionCheckInterval                    time.Duration
+			TelemetryIpPortAddress                  string
+			TelemetryApiToken                       string
 		}(operatorConfigFromYaml.Operator),
 	}
 }
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+type ReferenceBatcherConfig struct {
+	BaseConfig       *BaseConfig
+	EcdsaConfig      *EcdsaConfig
+	ReferenceBatcher struct {
+		ServerIpPortAddress string
+		StorageDir          string
+		DownloadEndpoint    string
+		BatchInterval       time.Duration
+		MaxBatchProofQty    int
+		MaxProofSize        int
+		FeePerProof         uint64
+		FeeForAggregator    uint64
+	}
+}
+
+type ReferenceBatcherConfigFromYaml struct {
+	ReferenceBatcher struct {
+		ServerIpPortAddress string        `yaml:"server_ip_port_address"`
+		StorageDir          string        `yaml:"storage_dir"`
+		DownloadEndpoint    string        `yaml:"download_endpoint"`
+		BatchInterval       time.Duration `yaml:"batch_interval"`
+		MaxBatchProofQty    int           `yaml:"max_batch_proof_qty"`
+		MaxProofSize        int           `yaml:"max_proof_size"`
+		FeePerProof         uint64        `yaml:"fee_per_proof"`
+		FeeForAggregator    uint64        `yaml:"fee_for_aggregator"`
+	} `yaml:"reference_batcher"`
+}
+
+func NewReferenceBatcherConfig(configFilePath string) *ReferenceBatcherConfig {
+	if _, err := os.Stat(configFilePath); errors.Is(err, os.ErrNotExist) {
+		log.Fatal("Setup config file does not exist")
+	}
+
+	baseConfig := NewBaseConfig(configFilePath)
+	if baseConfig == nil {
+		log.Fatal("Error reading base config: ")
+	}
+
+	if baseConfig.AlignedLayerDeploymentConfig.BatcherPaymentServiceAddr == (common.Address{}) {
+		log.Fatal("Batcher payment service address is empty")
+	}
+
+	ecdsaConfig := NewEcdsaConfig(configFilePath, baseConfig.ChainId)
+	if ecdsaConfig == nil {
+		log.Fatal("Error reading ecdsa config: ")
+	}
+
+	var referenceBatcherConfigFromYaml ReferenceBatcherConfigFromYaml
+	err := utils.ReadYamlConfig(configFilePath, &referenceBatcherConfigFromYaml)
+	if err != nil {
+		log.Fatal("Error reading reference batcher config: ", err)
+	}
+
+	referenceBatcher := referenceBatcherConfigFromYaml.ReferenceBatcher
+	if referenceBatcher.StorageDir == "" || referenceBatcher.DownloadEndpoint == "" {
+		log.Fatal("Reference batcher storage dir or download endpoint is empty")
+	}
+	if referenceBatcher.BatchInterval <= 0 || referenceBatcher.MaxBatchProofQty <= 0 || referenceBatcher.MaxProofSize <= 0 {
+		log.Fatal("Reference batcher batch interval, max batch proof qty and max proof size must be positive")
+	}
+	// The BatcherPaymentService reverts tasks whose fees don't cover the aggregator fee
+	if referenceBatcher.FeePerProof <= referenceBatcher.FeeForAggregator {
+		log.Fatal("Reference batcher fee per proof must be greater than the fee for the aggregator")
+	}
+
+	return &ReferenceBatcherConfig{
+		BaseConfig:  baseConfig,
+		EcdsaConfig: ecdsaConfig,
+		ReferenceBatcher: struct {
+			ServerIpPortAddress string
+			StorageDir          string
+			DownloadEndpoint    string
+			BatchInterval       time.Duration
+			MaxBatchProofQty    int
+			MaxProofSize        int
+			FeePerProof         uint64
+			FeeForAggregator    uint64
+		}(referenceBatcher),
+	}
+}
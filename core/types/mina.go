@@ -0,0 +1,122 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+)
+
+// MinaFieldElementSize is the size of a little endian encoded Mina field element.
+const MinaFieldElementSize = 32
+
+// MinaFieldModulus is the modulus of the Pallas base field, the field of the Mina public inputs.
+var MinaFieldModulus, _ = new(big.Int).SetString("40000000000000000000000000000000224698fc094cf91b992d30ed00000001", 16)
+
+// jsonWhitespace is the whitespace allowed around JSON values.
+const jsonWhitespace = " \t\n\r"
+
+// minaJsonPublicInputFields are the keys of the JSON exported by o1js with proof.toJSON(), whose field elements
+// are decimal strings and whose public output is kept apart from the public input.
+var minaJsonPublicInputFields = []string{"publicInput", "publicOutput"}
+
+// NormalizeMinaPublicInput converts a Mina public input to the encoding the Kimchi verifier expects,
+// the concatenation of its field elements as 32 bytes little endian integers.
+// Besides that encoding, which is validated and returned as is, it accepts the JSON exported by o1js,
+// whose public input and public output are concatenated, in that order.
+// The batcher decodes it in the same way, so both accept exactly the same encodings of a statement.
+func NormalizeMinaPublicInput(pubInput []byte) ([]byte, error) {
+	if trimmed := bytes.TrimLeft(pubInput, jsonWhitespace); len(trimmed) > 0 && trimmed[0] == '{' {
+		return minaPublicInputFromJson(pubInput)
+	}
+
+	if len(pubInput)%MinaFieldElementSize != 0 {
+		return nil, fmt.Errorf("public input size %d is not a multiple of the field element size", len(pubInput))
+	}
+	for i := 0; i < len(pubInput); i += MinaFieldElementSize {
+		element := new(big.Int).SetBytes(reverse(pubInput[i : i+MinaFieldElementSize]))
+		if element.Cmp(MinaFieldModulus) >= 0 {
+			return nil, fmt.Errorf("public input element %d is not a canonical field element", i/MinaFieldElementSize)
+		}
+	}
+	return pubInput, nil
+}
+
+// minaPublicInputFromJson decodes the o1js JSON strictly: both fields are required, keys are case sensitive
+// and can't be repeated, and elements must be canonical decimal strings.
+func minaPublicInputFromJson(data []byte) ([]byte, error) {
+	fields, err := readMinaJsonFields(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON public input: %v", err)
+	}
+
+	var elements []string
+	for _, field := range minaJsonPublicInputFields {
+		elements = append(elements, fields[field]...)
+	}
+	encoded := make([]byte, 0, len(elements)*MinaFieldElementSize)
+	for i, element := range elements {
+		value, ok := new(big.Int).SetString(element, 10)
+		if !ok || value.Sign() < 0 || value.Cmp(MinaFieldModulus) >= 0 || value.String() != element {
+			return nil, fmt.Errorf("public input element %d is not a canonical field element", i)
+		}
+		encoded = append(encoded, reverse(value.FillBytes(make([]byte, MinaFieldElementSize)))...)
+	}
+	return encoded, nil
+}
+
+func readMinaJsonFields(data []byte) (map[string][]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+
+	fields := make(map[string][]string)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		if !slices.Contains(minaJsonPublicInputFields, key) {
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+		if _, ok := fields[key]; ok {
+			return nil, fmt.Errorf("duplicate field %q", key)
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+		var elements []string
+		if bytes.Equal(raw, []byte("null")) || json.Unmarshal(raw, &elements) != nil {
+			return nil, fmt.Errorf("field %q is not an array of strings", key)
+		}
+		fields[key] = elements
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("trailing data after the JSON object")
+	}
+
+	for _, field := range minaJsonPublicInputFields {
+		if _, ok := fields[field]; !ok {
+			return nil, fmt.Errorf("missing field %q", field)
+		}
+	}
+	return fields, nil
+}
+
+// reverse returns a reversed copy of b, to convert between big and little endian.
+func reverse(b []byte) []byte {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return reversed
+}
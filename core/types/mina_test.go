@@ -0,0 +1,89 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestNormalizeMinaPublicInputFromJson(t *testing.T) {
+	pubInput, err := NormalizeMinaPublicInput([]byte(`{"publicInput": ["1"], "publicOutput": ["258"]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error normalizing public input: %v", err)
+	}
+
+	expected := make([]byte, 2*MinaFieldElementSize)
+	expected[0] = 1
+	expected[MinaFieldElementSize] = 2
+	expected[MinaFieldElementSize+1] = 1
+	if !bytes.Equal(pubInput, expected) {
+		t.Errorf("Expected %x, got %x", expected, pubInput)
+	}
+}
+
+func TestNormalizeMinaPublicInputKeepsCanonicalEncoding(t *testing.T) {
+	encoded := make([]byte, MinaFieldElementSize)
+	encoded[0] = 7
+	pubInput, err := NormalizeMinaPublicInput(encoded)
+	if err != nil {
+		t.Fatalf("Unexpected error normalizing public input: %v", err)
+	}
+	if !bytes.Equal(pubInput, encoded) {
+		t.Errorf("Expected %x, got %x", encoded, pubInput)
+	}
+}
+
+func TestNormalizeMinaPublicInputRejectsElementsOutOfField(t *testing.T) {
+	if _, err := NormalizeMinaPublicInput([]byte(`{"publicInput": ["` + MinaFieldModulus.String() + `"], "publicOutput": []}`)); err == nil {
+		t.Errorf("Expected an error for an element out of the field")
+	}
+
+	encoded := bytes.Repeat([]byte{0xFF}, MinaFieldElementSize)
+	if _, err := NormalizeMinaPublicInput(encoded); err == nil {
+		t.Errorf("Expected an error for a non canonical element")
+	}
+}
+
+func TestNormalizeMinaPublicInputRejectsInvalidSizes(t *testing.T) {
+	if _, err := NormalizeMinaPublicInput(make([]byte, MinaFieldElementSize+1)); err == nil {
+		t.Errorf("Expected an error for a public input not made of field elements")
+	}
+}
+
+// minaPublicInputsFilePath holds the cases the batcher tests too, so both decode public inputs in the same way
+const minaPublicInputsFilePath = "../../scripts/test_files/mina/public_inputs.json"
+
+func TestNormalizeMinaPublicInputSharedCases(t *testing.T) {
+	data, err := os.ReadFile(minaPublicInputsFilePath)
+	if err != nil {
+		t.Fatalf("could not open test file: %s", err)
+	}
+	var cases []struct {
+		Name     string  `json:"name"`
+		Input    string  `json:"input"`
+		Expected *string `json:"expected"`
+	}
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("could not decode test file: %s", err)
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			pubInput, err := NormalizeMinaPublicInput([]byte(c.Input))
+			if c.Expected == nil {
+				if err == nil {
+					t.Errorf("Expected an error, got %x", pubInput)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error normalizing public input: %v", err)
+			}
+			if hex.EncodeToString(pubInput) != *c.Expected {
+				t.Errorf("Expected %s, got %x", *c.Expected, pubInput)
+			}
+		})
+	}
+}
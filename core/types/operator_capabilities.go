@@ -0,0 +1,45 @@
+package types
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// OperatorCapabilities is sent by an operator to the aggregator when it connects, to advertise
+// the proving systems it verifies. VerifierVersions maps each enabled proving system to the
+// version of the verifier library the operator runs.
+// It is signed with the operator BLS key, so the aggregator can check it was sent by the operator.
+type OperatorCapabilities struct {
+	OperatorId       eigentypes.OperatorId
+	VerifierVersions map[string]string
+	BlsSignature     bls.Signature
+}
+
+// Digest returns the message signed by the operator, the keccak256 of the operator id followed by
+// the length prefixed proving systems and versions, sorted by proving system.
+func (c *OperatorCapabilities) Digest() [32]byte {
+	provingSystems := make([]string, 0, len(c.VerifierVersions))
+	for provingSystem := range c.VerifierVersions {
+		provingSystems = append(provingSystems, provingSystem)
+	}
+	sort.Strings(provingSystems)
+
+	message := append([]byte{}, c.OperatorId[:]...)
+	for _, provingSystem := range provingSystems {
+		message = appendLengthPrefixed(message, provingSystem)
+		message = appendLengthPrefixed(message, c.VerifierVersions[provingSystem])
+	}
+
+	var digest [32]byte
+	copy(digest[:], crypto.Keccak256(message))
+	return digest
+}
+
+func appendLengthPrefixed(message []byte, value string) []byte {
+	message = binary.BigEndian.AppendUint32(message, uint32(len(value)))
+	return append(message, value...)
+}
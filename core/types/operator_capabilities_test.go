@@ -0,0 +1,35 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestOperatorCapabilitiesDigest(t *testing.T) {
+	capabilities := OperatorCapabilities{
+		OperatorId:       [32]byte{1},
+		VerifierVersions: map[string]string{"SP1": "v3.0.0", "Risc0": "v1.1.2", "Groth16Bn254": "v0.10.0"},
+	}
+	digest := capabilities.Digest()
+
+	// Map iteration order must not change the digest
+	for i := 0; i < 10; i++ {
+		if capabilities.Digest() != digest {
+			t.Fatalf("Digest is not deterministic")
+		}
+	}
+
+	changedVersion := OperatorCapabilities{
+		OperatorId:       capabilities.OperatorId,
+		VerifierVersions: map[string]string{"SP1": "v1.0.1", "Risc0": "v1.1.2", "Groth16Bn254": "v0.10.0"},
+	}
+	if changedVersion.Digest() == digest {
+		t.Errorf("Digest should change with the verifier versions")
+	}
+
+	// Moving bytes between the proving system and the version must change the digest
+	shifted := OperatorCapabilities{OperatorId: capabilities.OperatorId, VerifierVersions: map[string]string{"SP1v": "3.0.0"}}
+	original := OperatorCapabilities{OperatorId: capabilities.OperatorId, VerifierVersions: map[string]string{"SP1": "v3.0.0"}}
+	if shifted.Digest() == original.Digest() {
+		t.Errorf("Digest should be unambiguous")
+	}
+}
@@ -0,0 +1,816 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: core/types/pb/operator.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RequestHeader precedes every request
+type RequestHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// service_method is the called method, e.g. Aggregator.ProcessOperatorCapabilities
+	ServiceMethod string `protobuf:"bytes,1,opt,name=service_method,json=serviceMethod,proto3" json:"service_method,omitempty"`
+	// seq is chosen by the operator to match the response to the request
+	Seq uint64 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+}
+
+func (x *RequestHeader) Reset() {
+	*x = RequestHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_types_pb_operator_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestHeader) ProtoMessage() {}
+
+func (x *RequestHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_pb_operator_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestHeader.ProtoReflect.Descriptor instead.
+func (*RequestHeader) Descriptor() ([]byte, []int) {
+	return file_core_types_pb_operator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RequestHeader) GetServiceMethod() string {
+	if x != nil {
+		return x.ServiceMethod
+	}
+	return ""
+}
+
+func (x *RequestHeader) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+// ResponseHeader precedes every response
+type ResponseHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ServiceMethod string `protobuf:"bytes,1,opt,name=service_method,json=serviceMethod,proto3" json:"service_method,omitempty"`
+	Seq           uint64 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	// error is the error of the call, empty if it succeeded
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ResponseHeader) Reset() {
+	*x = ResponseHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_types_pb_operator_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseHeader) ProtoMessage() {}
+
+func (x *ResponseHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_pb_operator_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseHeader.ProtoReflect.Descriptor instead.
+func (*ResponseHeader) Descriptor() ([]byte, []int) {
+	return file_core_types_pb_operator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ResponseHeader) GetServiceMethod() string {
+	if x != nil {
+		return x.ServiceMethod
+	}
+	return ""
+}
+
+func (x *ResponseHeader) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ResponseHeader) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type Empty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_types_pb_operator_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_pb_operator_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_core_types_pb_operator_proto_rawDescGZIP(), []int{2}
+}
+
+// Reply is the result of the calls without a response: 0 if succeeded, 1 if failed
+type Reply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code uint32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *Reply) Reset() {
+	*x = Reply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_types_pb_operator_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Reply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reply) ProtoMessage() {}
+
+func (x *Reply) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_pb_operator_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reply.ProtoReflect.Descriptor instead.
+func (*Reply) Descriptor() ([]byte, []int) {
+	return file_core_types_pb_operator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Reply) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+type ServerRunningReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Running int64 `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
+}
+
+func (x *ServerRunningReply) Reset() {
+	*x = ServerRunningReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_types_pb_operator_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerRunningReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerRunningReply) ProtoMessage() {}
+
+func (x *ServerRunningReply) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_pb_operator_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerRunningReply.ProtoReflect.Descriptor instead.
+func (*ServerRunningReply) Descriptor() ([]byte, []int) {
+	return file_core_types_pb_operator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ServerRunningReply) GetRunning() int64 {
+	if x != nil {
+		return x.Running
+	}
+	return 0
+}
+
+// SignedTaskResponse is the BLS signature of the operator of a verified batch
+type SignedTaskResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// version is the version of the response, see SignedTaskResponseVersion
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// batch_merkle_root is the 32 bytes merkle root of the batch
+	BatchMerkleRoot []byte `protobuf:"bytes,2,opt,name=batch_merkle_root,json=batchMerkleRoot,proto3" json:"batch_merkle_root,omitempty"`
+	// sender_address is the 20 bytes address of the batcher that submitted the batch
+	SenderAddress []byte `protobuf:"bytes,3,opt,name=sender_address,json=senderAddress,proto3" json:"sender_address,omitempty"`
+	// batch_identifier_hash is the 32 bytes keccak256 of the merkle root followed by the sender address
+	BatchIdentifierHash []byte `protobuf:"bytes,4,opt,name=batch_identifier_hash,json=batchIdentifierHash,proto3" json:"batch_identifier_hash,omitempty"`
+	// bls_signature is the signature of the batch identifier hash, the 32 bytes compressed G1 point:
+	// its x coordinate big-endian, with the two most significant bits telling which y it has
+	BlsSignature []byte `protobuf:"bytes,5,opt,name=bls_signature,json=blsSignature,proto3" json:"bls_signature,omitempty"`
+	// operator_id is the 32 bytes EigenLayer id of the operator
+	OperatorId []byte `protobuf:"bytes,6,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+}
+
+func (x *SignedTaskResponse) Reset() {
+	*x = SignedTaskResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_types_pb_operator_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignedTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedTaskResponse) ProtoMessage() {}
+
+func (x *SignedTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_pb_operator_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedTaskResponse.ProtoReflect.Descriptor instead.
+func (*SignedTaskResponse) Descriptor() ([]byte, []int) {
+	return file_core_types_pb_operator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SignedTaskResponse) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SignedTaskResponse) GetBatchMerkleRoot() []byte {
+	if x != nil {
+		return x.BatchMerkleRoot
+	}
+	return nil
+}
+
+func (x *SignedTaskResponse) GetSenderAddress() []byte {
+	if x != nil {
+		return x.SenderAddress
+	}
+	return nil
+}
+
+func (x *SignedTaskResponse) GetBatchIdentifierHash() []byte {
+	if x != nil {
+		return x.BatchIdentifierHash
+	}
+	return nil
+}
+
+func (x *SignedTaskResponse) GetBlsSignature() []byte {
+	if x != nil {
+		return x.BlsSignature
+	}
+	return nil
+}
+
+func (x *SignedTaskResponse) GetOperatorId() []byte {
+	if x != nil {
+		return x.OperatorId
+	}
+	return nil
+}
+
+// OperatorCapabilities advertises the proving systems the operator verifies
+type OperatorCapabilities struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// operator_id is the 32 bytes EigenLayer id of the operator
+	OperatorId []byte `protobuf:"bytes,1,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	// verifier_versions maps each enabled proving system to the version of its verifier library
+	VerifierVersions map[string]string `protobuf:"bytes,2,rep,name=verifier_versions,json=verifierVersions,proto3" json:"verifier_versions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// bls_signature is the signature of the capabilities digest, compressed as the one of the responses
+	BlsSignature []byte `protobuf:"bytes,3,opt,name=bls_signature,json=blsSignature,proto3" json:"bls_signature,omitempty"`
+}
+
+func (x *OperatorCapabilities) Reset() {
+	*x = OperatorCapabilities{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_types_pb_operator_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OperatorCapabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperatorCapabilities) ProtoMessage() {}
+
+func (x *OperatorCapabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_pb_operator_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperatorCapabilities.ProtoReflect.Descriptor instead.
+func (*OperatorCapabilities) Descriptor() ([]byte, []int) {
+	return file_core_types_pb_operator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *OperatorCapabilities) GetOperatorId() []byte {
+	if x != nil {
+		return x.OperatorId
+	}
+	return nil
+}
+
+func (x *OperatorCapabilities) GetVerifierVersions() map[string]string {
+	if x != nil {
+		return x.VerifierVersions
+	}
+	return nil
+}
+
+func (x *OperatorCapabilities) GetBlsSignature() []byte {
+	if x != nil {
+		return x.BlsSignature
+	}
+	return nil
+}
+
+type BatchIdentifier struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// batch_identifier_hash is the 32 bytes identifier hash of the task batch
+	BatchIdentifierHash []byte `protobuf:"bytes,1,opt,name=batch_identifier_hash,json=batchIdentifierHash,proto3" json:"batch_identifier_hash,omitempty"`
+}
+
+func (x *BatchIdentifier) Reset() {
+	*x = BatchIdentifier{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_types_pb_operator_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchIdentifier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchIdentifier) ProtoMessage() {}
+
+func (x *BatchIdentifier) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_pb_operator_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchIdentifier.ProtoReflect.Descriptor instead.
+func (*BatchIdentifier) Descriptor() ([]byte, []int) {
+	return file_core_types_pb_operator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchIdentifier) GetBatchIdentifierHash() []byte {
+	if x != nil {
+		return x.BatchIdentifierHash
+	}
+	return nil
+}
+
+// TaskTraceContext identifies the aggregator span of a task, its ids hex encoded
+type TaskTraceContext struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TraceId string `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	SpanId  string `protobuf:"bytes,2,opt,name=span_id,json=spanId,proto3" json:"span_id,omitempty"`
+}
+
+func (x *TaskTraceContext) Reset() {
+	*x = TaskTraceContext{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_types_pb_operator_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskTraceContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskTraceContext) ProtoMessage() {}
+
+func (x *TaskTraceContext) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_pb_operator_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskTraceContext.ProtoReflect.Descriptor instead.
+func (*TaskTraceContext) Descriptor() ([]byte, []int) {
+	return file_core_types_pb_operator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TaskTraceContext) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *TaskTraceContext) GetSpanId() string {
+	if x != nil {
+		return x.SpanId
+	}
+	return ""
+}
+
+var File_core_types_pb_operator_proto protoreflect.FileDescriptor
+
+var file_core_types_pb_operator_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x70, 0x62, 0x2f,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x13,
+	0x61, 0x6c, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x22, 0x48, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f,
+	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x73,
+	0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x22, 0x5f, 0x0a,
+	0x0e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12,
+	0x25, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x07,
+	0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x1b, 0x0a, 0x05, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x22, 0x2e, 0x0a, 0x12, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x75,
+	0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x75,
+	0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x75, 0x6e,
+	0x6e, 0x69, 0x6e, 0x67, 0x22, 0xfb, 0x01, 0x0a, 0x12, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x54,
+	0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6d,
+	0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f,
+	0x74, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x73, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x62, 0x61, 0x74, 0x63,
+	0x68, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x62, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x48, 0x61, 0x73, 0x68, 0x12, 0x23, 0x0a, 0x0d,
+	0x62, 0x6c, 0x73, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0c, 0x62, 0x6c, 0x73, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x49, 0x64, 0x22, 0x8f, 0x02, 0x0a, 0x14, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43,
+	0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x6c, 0x0a, 0x11,
+	0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3f, 0x2e, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x10, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x6c,
+	0x73, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0c, 0x62, 0x6c, 0x73, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x1a,
+	0x43, 0x0a, 0x15, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x45, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x15, 0x62, 0x61, 0x74, 0x63, 0x68,
+	0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x62, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x48, 0x61, 0x73, 0x68, 0x22, 0x46, 0x0a, 0x10, 0x54,
+	0x61, 0x73, 0x6b, 0x54, 0x72, 0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x70,
+	0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x70, 0x61,
+	0x6e, 0x49, 0x64, 0x32, 0x98, 0x03, 0x0a, 0x0a, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
+	0x6f, 0x72, 0x12, 0x6a, 0x0a, 0x23, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x54, 0x61, 0x73, 0x6b, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x56, 0x32, 0x12, 0x27, 0x2e, 0x61, 0x6c, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x1a, 0x1a, 0x2e, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x2e, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x64,
+	0x0a, 0x1b, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x29, 0x2e,
+	0x61, 0x6c, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x1a, 0x1a, 0x2e, 0x61, 0x6c, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x62, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x54,
+	0x72, 0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x24, 0x2e, 0x61, 0x6c,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x1a, 0x25, 0x2e, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x2e, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x54, 0x72, 0x61, 0x63,
+	0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x54, 0x0a, 0x0d, 0x53, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x2e, 0x61, 0x6c, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x27, 0x2e, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x2e,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x42, 0x35,
+	0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x79, 0x65, 0x74,
+	0x61, 0x6e, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x63, 0x6f, 0x2f, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x5f, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_core_types_pb_operator_proto_rawDescOnce sync.Once
+	file_core_types_pb_operator_proto_rawDescData = file_core_types_pb_operator_proto_rawDesc
+)
+
+func file_core_types_pb_operator_proto_rawDescGZIP() []byte {
+	file_core_types_pb_operator_proto_rawDescOnce.Do(func() {
+		file_core_types_pb_operator_proto_rawDescData = protoimpl.X.CompressGZIP(file_core_types_pb_operator_proto_rawDescData)
+	})
+	return file_core_types_pb_operator_proto_rawDescData
+}
+
+var file_core_types_pb_operator_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_core_types_pb_operator_proto_goTypes = []any{
+	(*RequestHeader)(nil),        // 0: aligned.operator.v1.RequestHeader
+	(*ResponseHeader)(nil),       // 1: aligned.operator.v1.ResponseHeader
+	(*Empty)(nil),                // 2: aligned.operator.v1.Empty
+	(*Reply)(nil),                // 3: aligned.operator.v1.Reply
+	(*ServerRunningReply)(nil),   // 4: aligned.operator.v1.ServerRunningReply
+	(*SignedTaskResponse)(nil),   // 5: aligned.operator.v1.SignedTaskResponse
+	(*OperatorCapabilities)(nil), // 6: aligned.operator.v1.OperatorCapabilities
+	(*BatchIdentifier)(nil),      // 7: aligned.operator.v1.BatchIdentifier
+	(*TaskTraceContext)(nil),     // 8: aligned.operator.v1.TaskTraceContext
+	nil,                          // 9: aligned.operator.v1.OperatorCapabilities.VerifierVersionsEntry
+}
+var file_core_types_pb_operator_proto_depIdxs = []int32{
+	9, // 0: aligned.operator.v1.OperatorCapabilities.verifier_versions:type_name -> aligned.operator.v1.OperatorCapabilities.VerifierVersionsEntry
+	5, // 1: aligned.operator.v1.Aggregator.ProcessOperatorSignedTaskResponseV2:input_type -> aligned.operator.v1.SignedTaskResponse
+	6, // 2: aligned.operator.v1.Aggregator.ProcessOperatorCapabilities:input_type -> aligned.operator.v1.OperatorCapabilities
+	7, // 3: aligned.operator.v1.Aggregator.GetTaskTraceContext:input_type -> aligned.operator.v1.BatchIdentifier
+	2, // 4: aligned.operator.v1.Aggregator.ServerRunning:input_type -> aligned.operator.v1.Empty
+	3, // 5: aligned.operator.v1.Aggregator.ProcessOperatorSignedTaskResponseV2:output_type -> aligned.operator.v1.Reply
+	3, // 6: aligned.operator.v1.Aggregator.ProcessOperatorCapabilities:output_type -> aligned.operator.v1.Reply
+	8, // 7: aligned.operator.v1.Aggregator.GetTaskTraceContext:output_type -> aligned.operator.v1.TaskTraceContext
+	4, // 8: aligned.operator.v1.Aggregator.ServerRunning:output_type -> aligned.operator.v1.ServerRunningReply
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_core_types_pb_operator_proto_init() }
+func file_core_types_pb_operator_proto_init() {
+	if File_core_types_pb_operator_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_core_types_pb_operator_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*RequestHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_types_pb_operator_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ResponseHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_types_pb_operator_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_types_pb_operator_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*Reply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_types_pb_operator_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ServerRunningReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_types_pb_operator_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*SignedTaskResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_types_pb_operator_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*OperatorCapabilities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_types_pb_operator_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*BatchIdentifier); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_types_pb_operator_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*TaskTraceContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_core_types_pb_operator_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_core_types_pb_operator_proto_goTypes,
+		DependencyIndexes: file_core_types_pb_operator_proto_depIdxs,
+		MessageInfos:      file_core_types_pb_operator_proto_msgTypes,
+	}.Build()
+	File_core_types_pb_operator_proto = out.File
+	file_core_types_pb_operator_proto_rawDesc = nil
+	file_core_types_pb_operator_proto_goTypes = nil
+	file_core_types_pb_operator_proto_depIdxs = nil
+}
@@ -0,0 +1,289 @@
+package types
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yetanotherco/aligned_layer/core/types/pb"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// ProtoRpcPath is the HTTP path the aggregator serves the operators RPC on with the protobuf
+// messages of core/types/pb, instead of the gob ones of rpc.DefaultRPCPath. The protocol is
+// described in core/types/pb/operator.proto, so operators can be implemented in other languages.
+const ProtoRpcPath = "/_alignedRPC_proto"
+
+const (
+	// rpcConnected is the status the aggregator responds the CONNECT request with
+	rpcConnected = "200 Connected to Go RPC"
+	// maxProtoRpcResponseSize bounds the responses of the aggregator, which are small
+	maxProtoRpcResponseSize = 1024 * 1024
+)
+
+var (
+	ErrProtoMessageTooLarge = errors.New("protobuf message too large")
+	// ErrProtoRpcUnsupported is returned by the aggregators of previous releases, which only support gob
+	ErrProtoRpcUnsupported = errors.New("aggregator doesn't support protobuf messages")
+)
+
+// DialProtoRpc connects to the aggregator RPC with the protobuf messages, like rpc.DialHTTP does
+// with the gob ones
+func DialProtoRpc(address string) (*rpc.Client, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(conn, "CONNECT "+ProtoRpcPath+" HTTP/1.0\n\n"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if resp.Status != rpcConnected {
+		conn.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrProtoRpcUnsupported
+		}
+		return nil, fmt.Errorf("unexpected HTTP response: %s", resp.Status)
+	}
+	return rpc.NewClientWithCodec(NewProtoClientCodec(conn, reader, maxProtoRpcResponseSize)), nil
+}
+
+// WriteProtoMessage writes the protobuf message of the RPC argument or reply v, prefixed by its
+// length as a varint. v is either a protobuf message or a type with a message in core/types/pb.
+func WriteProtoMessage(w io.Writer, v any) error {
+	message, err := toProto(v)
+	if err != nil {
+		return err
+	}
+	encoded, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(protowire.AppendVarint(nil, uint64(len(encoded)))); err != nil {
+		return err
+	}
+	_, err = w.Write(encoded)
+	return err
+}
+
+// ReadProtoMessage reads a length prefixed protobuf message into the RPC argument or reply v,
+// discarding it if v is nil. It fails on messages larger than maxSize before reading them.
+func ReadProtoMessage(r *bufio.Reader, maxSize int64, v any) error {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+	if size > uint64(maxSize) {
+		return ErrProtoMessageTooLarge
+	}
+	encoded := make([]byte, size)
+	if _, err := io.ReadFull(r, encoded); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	message, err := newProto(v)
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(encoded, message); err != nil {
+		return err
+	}
+	return fromProto(message, v)
+}
+
+// protoClientCodec is the net/rpc client codec of the protobuf messages
+type protoClientCodec struct {
+	conn    io.ReadWriteCloser
+	r       *bufio.Reader
+	w       *bufio.Writer
+	maxSize int64
+}
+
+// NewProtoClientCodec returns the net/rpc client codec of the protobuf messages, reading the responses
+// from r, which buffers conn, and failing on the ones larger than maxSize
+func NewProtoClientCodec(conn io.ReadWriteCloser, r *bufio.Reader, maxSize int64) rpc.ClientCodec {
+	return &protoClientCodec{conn: conn, r: r, w: bufio.NewWriter(conn), maxSize: maxSize}
+}
+
+func (c *protoClientCodec) WriteRequest(r *rpc.Request, body any) error {
+	header := &pb.RequestHeader{ServiceMethod: r.ServiceMethod, Seq: r.Seq}
+	if err := WriteProtoMessage(c.w, header); err != nil {
+		return err
+	}
+	if err := WriteProtoMessage(c.w, body); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
+
+func (c *protoClientCodec) ReadResponseHeader(r *rpc.Response) error {
+	var header pb.ResponseHeader
+	if err := ReadProtoMessage(c.r, c.maxSize, &header); err != nil {
+		return err
+	}
+	r.ServiceMethod = header.ServiceMethod
+	r.Seq = header.Seq
+	r.Error = header.Error
+	return nil
+}
+
+func (c *protoClientCodec) ReadResponseBody(body any) error {
+	return ReadProtoMessage(c.r, c.maxSize, body)
+}
+
+func (c *protoClientCodec) Close() error {
+	return c.conn.Close()
+}
+
+// newProto returns the empty protobuf message of the RPC argument or reply v
+func newProto(v any) (proto.Message, error) {
+	switch v := v.(type) {
+	case proto.Message:
+		return v, nil
+	case *SignedTaskResponse:
+		return &pb.SignedTaskResponse{}, nil
+	case *OperatorCapabilities:
+		return &pb.OperatorCapabilities{}, nil
+	case *[32]byte:
+		return &pb.BatchIdentifier{}, nil
+	case *TaskTraceContext:
+		return &pb.TaskTraceContext{}, nil
+	case *uint8:
+		return &pb.Reply{}, nil
+	case *int64:
+		return &pb.ServerRunningReply{}, nil
+	case *struct{}:
+		return &pb.Empty{}, nil
+	}
+	return nil, fmt.Errorf("no protobuf message for %T", v)
+}
+
+// toProto returns the protobuf message of the RPC argument or reply v
+func toProto(v any) (proto.Message, error) {
+	switch v := v.(type) {
+	case proto.Message:
+		return v, nil
+	case *SignedTaskResponse:
+		return &pb.SignedTaskResponse{
+			Version:             uint32(v.Version),
+			BatchMerkleRoot:     v.BatchMerkleRoot[:],
+			SenderAddress:       v.SenderAddress[:],
+			BatchIdentifierHash: v.BatchIdentifierHash[:],
+			BlsSignature:        g1PointToProto(v.BlsSignature.G1Point),
+			OperatorId:          v.OperatorId[:],
+		}, nil
+	case *OperatorCapabilities:
+		return &pb.OperatorCapabilities{
+			OperatorId:       v.OperatorId[:],
+			VerifierVersions: v.VerifierVersions,
+			BlsSignature:     g1PointToProto(v.BlsSignature.G1Point),
+		}, nil
+	case *[32]byte:
+		return &pb.BatchIdentifier{BatchIdentifierHash: v[:]}, nil
+	case *TaskTraceContext:
+		return &pb.TaskTraceContext{TraceId: v.TraceId, SpanId: v.SpanId}, nil
+	case *uint8:
+		return &pb.Reply{Code: uint32(*v)}, nil
+	case *int64:
+		return &pb.ServerRunningReply{Running: *v}, nil
+	case struct{}, *struct{}:
+		// the response of the failed calls
+		return &pb.Empty{}, nil
+	}
+	return nil, fmt.Errorf("no protobuf message for %T", v)
+}
+
+// fromProto sets the RPC argument or reply v to the protobuf message returned by newProto
+func fromProto(message proto.Message, v any) error {
+	var err error
+	switch v := v.(type) {
+	case proto.Message:
+		return nil
+	case *SignedTaskResponse:
+		m := message.(*pb.SignedTaskResponse)
+		if m.Version > uint32(^uint16(0)) {
+			return fmt.Errorf("signed task response version %d out of range", m.Version)
+		}
+		response := SignedTaskResponse{Version: uint16(m.Version)}
+		err = errors.Join(
+			copyFixedBytes(response.BatchMerkleRoot[:], m.BatchMerkleRoot, "batch_merkle_root"),
+			copyFixedBytes(response.SenderAddress[:], m.SenderAddress, "sender_address"),
+			copyFixedBytes(response.BatchIdentifierHash[:], m.BatchIdentifierHash, "batch_identifier_hash"),
+			copyFixedBytes(response.OperatorId[:], m.OperatorId, "operator_id"),
+		)
+		if err == nil {
+			response.BlsSignature.G1Point, err = g1PointFromProto(m.BlsSignature)
+		}
+		*v = response
+	case *OperatorCapabilities:
+		m := message.(*pb.OperatorCapabilities)
+		capabilities := OperatorCapabilities{VerifierVersions: m.VerifierVersions}
+		err = copyFixedBytes(capabilities.OperatorId[:], m.OperatorId, "operator_id")
+		if err == nil {
+			capabilities.BlsSignature.G1Point, err = g1PointFromProto(m.BlsSignature)
+		}
+		*v = capabilities
+	case *[32]byte:
+		err = copyFixedBytes(v[:], message.(*pb.BatchIdentifier).BatchIdentifierHash, "batch_identifier_hash")
+	case *TaskTraceContext:
+		m := message.(*pb.TaskTraceContext)
+		*v = TaskTraceContext{TraceId: m.TraceId, SpanId: m.SpanId}
+	case *uint8:
+		code := message.(*pb.Reply).Code
+		if code > 0xff {
+			return fmt.Errorf("reply code %d out of range", code)
+		}
+		*v = uint8(code)
+	case *int64:
+		*v = message.(*pb.ServerRunningReply).Running
+	case *struct{}:
+	default:
+		return fmt.Errorf("no protobuf message for %T", v)
+	}
+	return err
+}
+
+func copyFixedBytes(dst []byte, src []byte, field string) error {
+	if len(src) != len(dst) {
+		return fmt.Errorf("%s must be %d bytes, got %d", field, len(dst), len(src))
+	}
+	copy(dst, src)
+	return nil
+}
+
+func g1PointToProto(p *bls.G1Point) []byte {
+	if p == nil || p.G1Affine == nil {
+		return nil
+	}
+	compressed := utils.CompressG1Point(p)
+	return compressed[:]
+}
+
+// g1PointFromProto decompresses the signature, rejecting the invalid points. A missing one is left
+// to utils.ValidateG1Point, like for the gob messages.
+func g1PointFromProto(data []byte) (*bls.G1Point, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return utils.DecompressG1Point("signature", data)
+}
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/core/types/pb"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+func protoRoundTrip(t *testing.T, from interface{}, to interface{}) {
+	var buf bytes.Buffer
+	if err := WriteProtoMessage(&buf, from); err != nil {
+		t.Fatalf("Unexpected error encoding: %v", err)
+	}
+	if err := ReadProtoMessage(bufio.NewReader(&buf), 1024, to); err != nil {
+		t.Fatalf("Unexpected error decoding: %v", err)
+	}
+}
+
+func TestSignedTaskResponseProtoRoundTrip(t *testing.T) {
+	keyPair, err := bls.NewKeyPairFromString("12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response := NewSignedTaskResponse([32]byte{3}, [32]byte{1}, [20]byte{4}, *keyPair.SignMessage([32]byte{3}), eigentypes.OperatorId{2})
+
+	var decoded SignedTaskResponse
+	protoRoundTrip(t, &response, &decoded)
+	if decoded.Version != SignedTaskResponseVersion || decoded.BatchIdentifierHash != response.BatchIdentifierHash ||
+		decoded.BatchMerkleRoot != response.BatchMerkleRoot || decoded.SenderAddress != response.SenderAddress ||
+		decoded.OperatorId != response.OperatorId {
+		t.Errorf("Unexpected decoded response %+v", decoded)
+	}
+	if !decoded.BlsSignature.G1Affine.Equal(response.BlsSignature.G1Affine) {
+		t.Errorf("Expected the signature to be decoded, got %v", decoded.BlsSignature)
+	}
+}
+
+func TestOperatorCapabilitiesProtoRoundTrip(t *testing.T) {
+	capabilities := OperatorCapabilities{
+		OperatorId:       [32]byte{1},
+		VerifierVersions: map[string]string{"SP1": "v3.0.0", "Risc0": "v1.1.2"},
+	}
+	var decoded OperatorCapabilities
+	protoRoundTrip(t, &capabilities, &decoded)
+	if decoded.Digest() != capabilities.Digest() {
+		t.Errorf("Unexpected decoded capabilities %+v", decoded)
+	}
+	// the missing signature is left to the signature check
+	if decoded.BlsSignature.G1Point != nil {
+		t.Errorf("Expected no signature, got %v", decoded.BlsSignature)
+	}
+}
+
+func TestProtoMessageRejectsInvalidFields(t *testing.T) {
+	var decoded SignedTaskResponse
+	var buf bytes.Buffer
+	if err := WriteProtoMessage(&buf, &pb.SignedTaskResponse{BatchMerkleRoot: []byte{1}}); err != nil {
+		t.Fatalf("Unexpected error encoding: %v", err)
+	}
+	if err := ReadProtoMessage(bufio.NewReader(&buf), 1024, &decoded); err == nil {
+		t.Errorf("Expected a short merkle root to be rejected")
+	}
+
+	// an x coordinate greater than the field modulus isn't canonical
+	nonCanonical := bytes.Repeat([]byte{0xff}, 32)
+	buf.Reset()
+	err := WriteProtoMessage(&buf, &pb.SignedTaskResponse{
+		BatchMerkleRoot:     make([]byte, 32),
+		SenderAddress:       make([]byte, 20),
+		BatchIdentifierHash: make([]byte, 32),
+		OperatorId:          make([]byte, 32),
+		BlsSignature:        nonCanonical,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error encoding: %v", err)
+	}
+	var invalidPoint *utils.InvalidPointError
+	if err := ReadProtoMessage(bufio.NewReader(&buf), 1024, &decoded); !errors.As(err, &invalidPoint) {
+		t.Errorf("Expected a non-canonical coordinate to be rejected, got %v", err)
+	}
+}
+
+func TestProtoMessageRejectsLargeMessages(t *testing.T) {
+	var buf bytes.Buffer
+	capabilities := OperatorCapabilities{VerifierVersions: map[string]string{"SP1": string(make([]byte, 2048))}}
+	if err := WriteProtoMessage(&buf, &capabilities); err != nil {
+		t.Fatalf("Unexpected error encoding: %v", err)
+	}
+	var decoded OperatorCapabilities
+	if err := ReadProtoMessage(bufio.NewReader(&buf), 1024, &decoded); !errors.Is(err, ErrProtoMessageTooLarge) {
+		t.Errorf("Expected the large message to be rejected, got %v", err)
+	}
+}
@@ -1,14 +1,61 @@
 package types
 
 import (
+	"fmt"
+
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
 )
 
+const (
+	// SignedTaskResponseVersion is the version of the responses sent by this release
+	SignedTaskResponseVersion uint16 = 2
+	// MinSignedTaskResponseVersion is the oldest version the aggregator accepts, the one of the
+	// previous release, so operators can be upgraded after the aggregator
+	MinSignedTaskResponseVersion uint16 = 1
+)
+
+// SignedTaskResponse is sent by the operators to the aggregator over net/rpc, as the protobuf
+// message of core/types/pb or gob encoded by the operators of previous releases.
+//
+// gob matches the fields by name, ignoring the unknown ones and leaving the missing ones zero, so
+// the responses stay compatible between releases as long as fields are only added. A field can
+// only be removed once MinSignedTaskResponseVersion is past the last version that needs it.
+// Responses of operators before versioning have no Version, they are version 1.
 type SignedTaskResponse struct {
+	Version uint16
 	BatchMerkleRoot [32]byte
 	SenderAddress [20]byte
 	BatchIdentifierHash [32]byte
 	BlsSignature    bls.Signature
 	OperatorId      eigentypes.OperatorId
 }
+
+// NewSignedTaskResponse returns the response of this release, of SignedTaskResponseVersion
+func NewSignedTaskResponse(batchIdentifierHash [32]byte, batchMerkleRoot [32]byte, senderAddress [20]byte, blsSignature bls.Signature, operatorId eigentypes.OperatorId) SignedTaskResponse {
+	return SignedTaskResponse{
+		Version:             SignedTaskResponseVersion,
+		BatchMerkleRoot:     batchMerkleRoot,
+		SenderAddress:       senderAddress,
+		BatchIdentifierHash: batchIdentifierHash,
+		BlsSignature:        blsSignature,
+		OperatorId:          operatorId,
+	}
+}
+
+// MessageVersion returns the version of the response, 1 if it has none
+func (r *SignedTaskResponse) MessageVersion() uint16 {
+	if r.Version == 0 {
+		return 1
+	}
+	return r.Version
+}
+
+// CheckVersion fails if the response is older than MinSignedTaskResponseVersion. Newer responses
+// are accepted, since their fields are a superset of the known ones.
+func (r *SignedTaskResponse) CheckVersion() error {
+	if version := r.MessageVersion(); version < MinSignedTaskResponseVersion {
+		return fmt.Errorf("signed task response version %d is no longer supported, the oldest supported is %d", version, MinSignedTaskResponseVersion)
+	}
+	return nil
+}
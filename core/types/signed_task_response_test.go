@@ -0,0 +1,70 @@
+package types
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+)
+
+// unversionedSignedTaskResponse is the response of the operators before versioning
+type unversionedSignedTaskResponse struct {
+	BatchMerkleRoot     [32]byte
+	SenderAddress       [20]byte
+	BatchIdentifierHash [32]byte
+	BlsSignature        bls.Signature
+	OperatorId          eigentypes.OperatorId
+}
+
+// futureSignedTaskResponse is a response of a later release, with a field added
+type futureSignedTaskResponse struct {
+	Version             uint16
+	BatchMerkleRoot     [32]byte
+	SenderAddress       [20]byte
+	BatchIdentifierHash [32]byte
+	BlsSignature        bls.Signature
+	OperatorId          eigentypes.OperatorId
+	VerificationTimeMs  uint64
+}
+
+func gobRoundTrip(t *testing.T, from interface{}, to interface{}) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(from); err != nil {
+		t.Fatalf("Unexpected error encoding: %v", err)
+	}
+	if err := gob.NewDecoder(&buf).Decode(to); err != nil {
+		t.Fatalf("Unexpected error decoding: %v", err)
+	}
+}
+
+func TestSignedTaskResponseDecodesUnversioned(t *testing.T) {
+	var decoded SignedTaskResponse
+	gobRoundTrip(t, unversionedSignedTaskResponse{BatchMerkleRoot: [32]byte{1}, OperatorId: eigentypes.OperatorId{2}}, &decoded)
+	if decoded.MessageVersion() != 1 || decoded.BatchMerkleRoot != [32]byte{1} || decoded.OperatorId != (eigentypes.OperatorId{2}) {
+		t.Errorf("Unexpected decoded response %+v", decoded)
+	}
+	if err := decoded.CheckVersion(); err != nil {
+		t.Errorf("Expected the response of the previous release to be accepted, got %v", err)
+	}
+
+	// and the previous release decodes the versioned ones
+	var unversioned unversionedSignedTaskResponse
+	response := NewSignedTaskResponse([32]byte{3}, [32]byte{1}, [20]byte{}, bls.Signature{}, eigentypes.OperatorId{2})
+	gobRoundTrip(t, response, &unversioned)
+	if unversioned.BatchIdentifierHash != [32]byte{3} || unversioned.BatchMerkleRoot != [32]byte{1} {
+		t.Errorf("Unexpected decoded response %+v", unversioned)
+	}
+}
+
+func TestSignedTaskResponseDecodesNewerVersions(t *testing.T) {
+	var decoded SignedTaskResponse
+	gobRoundTrip(t, futureSignedTaskResponse{Version: SignedTaskResponseVersion + 1, BatchMerkleRoot: [32]byte{1}, VerificationTimeMs: 10}, &decoded)
+	if decoded.MessageVersion() != SignedTaskResponseVersion+1 || decoded.BatchMerkleRoot != [32]byte{1} {
+		t.Errorf("Unexpected decoded response %+v", decoded)
+	}
+	if err := decoded.CheckVersion(); err != nil {
+		t.Errorf("Expected a newer response to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package types
+
+import "fmt"
+
+// TaskTraceContext identifies the aggregator span of a task trace, so the operators can attach their
+// spans of the batch to it. Its ids are hex encoded, and empty if the task isn't traced.
+type TaskTraceContext struct {
+	TraceId string
+	SpanId  string
+}
+
+// TraceParent returns the context as a W3C traceparent header value, empty if the span isn't known
+func (c TaskTraceContext) TraceParent() string {
+	if len(c.TraceId) != 32 || len(c.SpanId) != 16 {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", c.TraceId, c.SpanId)
+}
@@ -0,0 +1,14 @@
+package types
+
+import "testing"
+
+func TestTaskTraceContextTraceParent(t *testing.T) {
+	traceContext := TaskTraceContext{TraceId: "4bf92f3577b34da6a3ce929d0e0e4736", SpanId: "00f067aa0ba902b7"}
+	if traceParent := traceContext.TraceParent(); traceParent != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("Unexpected traceparent %s", traceParent)
+	}
+	// the operator spans can't be attached to a trace without its span
+	if traceParent := (TaskTraceContext{TraceId: traceContext.TraceId}).TraceParent(); traceParent != "" {
+		t.Errorf("Expected no traceparent without a span id, got %s", traceParent)
+	}
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+const (
+	// G1CompressedSize and G2CompressedSize are the sizes of the compressed points
+	G1CompressedSize = bn254.SizeOfG1AffineCompressed
+	G2CompressedSize = bn254.SizeOfG2AffineCompressed
+	// G1SerializedSize is the size of a G1 point serialized as its big endian X and Y coordinates
+	G1SerializedSize = 2 * fp.Bytes
+)
+
+// InvalidPointError is returned for a BN254 point that can't be safely used in the BLS operations
+type InvalidPointError struct {
+	// Point is what the point is, e.g. "signature"
+	Point string
+	// Reason is why it's invalid: "missing", "invalid encoding", "non-canonical coordinate",
+	// "point at infinity", "not on curve" or "not in subgroup"
+	Reason string
+}
+
+func (e *InvalidPointError) Error() string {
+	return fmt.Sprintf("invalid %s point: %s", e.Point, e.Reason)
+}
+
+// ValidateG1Point checks the point has canonical coordinates and is a point of the G1 subgroup
+// other than the infinity. Points decoded from the operators messages are the raw coordinates
+// in Montgomery form, so they are only checked here.
+func ValidateG1Point(name string, p *bls.G1Point) error {
+	if p == nil || p.G1Affine == nil {
+		return &InvalidPointError{Point: name, Reason: "missing"}
+	}
+	if !isCanonical(&p.X) || !isCanonical(&p.Y) {
+		return &InvalidPointError{Point: name, Reason: "non-canonical coordinate"}
+	}
+	if p.IsInfinity() {
+		return &InvalidPointError{Point: name, Reason: "point at infinity"}
+	}
+	if !p.IsOnCurve() {
+		return &InvalidPointError{Point: name, Reason: "not on curve"}
+	}
+	if !p.IsInSubGroup() {
+		return &InvalidPointError{Point: name, Reason: "not in subgroup"}
+	}
+	return nil
+}
+
+// ValidateG2Point checks the point has canonical coordinates and is a point of the G2 subgroup
+// other than the infinity
+func ValidateG2Point(name string, p *bls.G2Point) error {
+	if p == nil || p.G2Affine == nil {
+		return &InvalidPointError{Point: name, Reason: "missing"}
+	}
+	if !isCanonical(&p.X.A0) || !isCanonical(&p.X.A1) || !isCanonical(&p.Y.A0) || !isCanonical(&p.Y.A1) {
+		return &InvalidPointError{Point: name, Reason: "non-canonical coordinate"}
+	}
+	if p.IsInfinity() {
+		return &InvalidPointError{Point: name, Reason: "point at infinity"}
+	}
+	if !p.IsOnCurve() {
+		return &InvalidPointError{Point: name, Reason: "not on curve"}
+	}
+	if !p.IsInSubGroup() {
+		return &InvalidPointError{Point: name, Reason: "not in subgroup"}
+	}
+	return nil
+}
+
+// CompressG1Point returns the compressed encoding of the point: its x coordinate big-endian, with
+// the two most significant bits telling which of the two y coordinates of x it has
+func CompressG1Point(p *bls.G1Point) [G1CompressedSize]byte {
+	return p.G1Affine.Bytes()
+}
+
+// DecompressG1Point decodes a point encoded by CompressG1Point, failing with an InvalidPointError
+// unless it's a point ValidateG1Point accepts
+func DecompressG1Point(name string, data []byte) (*bls.G1Point, error) {
+	if len(data) != G1CompressedSize {
+		return nil, &InvalidPointError{Point: name, Reason: "invalid encoding"}
+	}
+	var point bn254.G1Affine
+	// the subgroup is checked with the rest by ValidateG1Point
+	if err := bn254.NewDecoder(bytes.NewReader(data), bn254.NoSubgroupChecks()).Decode(&point); err != nil {
+		return nil, &InvalidPointError{Point: name, Reason: "invalid encoding"}
+	}
+	p := &bls.G1Point{G1Affine: &point}
+	if err := ValidateG1Point(name, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DeserializeG1Point decodes a point serialized as its big endian X and Y coordinates, as
+// bls.G1Point.Serialize does, failing with an InvalidPointError unless it's a point ValidateG1Point
+// accepts. Unlike bls.G1Point.Deserialize, coordinates aren't reduced, so they must be canonical.
+func DeserializeG1Point(name string, data []byte) (*bls.G1Point, error) {
+	if len(data) != G1SerializedSize {
+		return nil, &InvalidPointError{Point: name, Reason: "invalid encoding"}
+	}
+	var point bn254.G1Affine
+	if point.X.SetBytesCanonical(data[:fp.Bytes]) != nil || point.Y.SetBytesCanonical(data[fp.Bytes:]) != nil {
+		return nil, &InvalidPointError{Point: name, Reason: "non-canonical coordinate"}
+	}
+	p := &bls.G1Point{G1Affine: &point}
+	if err := ValidateG1Point(name, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// CompressG2Point returns the compressed encoding of the point, like CompressG1Point
+func CompressG2Point(p *bls.G2Point) [G2CompressedSize]byte {
+	return p.G2Affine.Bytes()
+}
+
+// DecompressG2Point decodes a point encoded by CompressG2Point, failing with an InvalidPointError
+// unless it's a point ValidateG2Point accepts
+func DecompressG2Point(name string, data []byte) (*bls.G2Point, error) {
+	if len(data) != G2CompressedSize {
+		return nil, &InvalidPointError{Point: name, Reason: "invalid encoding"}
+	}
+	var point bn254.G2Affine
+	if err := bn254.NewDecoder(bytes.NewReader(data), bn254.NoSubgroupChecks()).Decode(&point); err != nil {
+		return nil, &InvalidPointError{Point: name, Reason: "invalid encoding"}
+	}
+	p := &bls.G2Point{G2Affine: &point}
+	if err := ValidateG2Point(name, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// isCanonical returns whether the Montgomery form of the element is reduced modulo the field modulus
+func isCanonical(e *fp.Element) bool {
+	value := new(big.Int)
+	for i := len(e) - 1; i >= 0; i-- {
+		value.Lsh(value, 64)
+		value.Or(value, new(big.Int).SetUint64(e[i]))
+	}
+	return value.Cmp(fp.Modulus()) < 0
+}
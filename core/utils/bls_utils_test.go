@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+func TestValidateG1Point(t *testing.T) {
+	_, _, g1, _ := bn254.Generators()
+	signature := bls.NewG1Point(g1.X.BigInt(new(big.Int)), g1.Y.BigInt(new(big.Int)))
+	if err := ValidateG1Point("signature", signature); err != nil {
+		t.Fatalf("Expected the generator to be valid, got %v", err)
+	}
+
+	offCurve := bls.NewG1Point(big.NewInt(1), big.NewInt(1))
+	// The coordinates as sent by a crafted message, the modulus isn't reduced
+	var modulus fp.Element
+	for i, word := range fp.Modulus().Bits() {
+		modulus[i] = uint64(word)
+	}
+	nonCanonical := &bls.G1Point{G1Affine: &bn254.G1Affine{X: modulus, Y: g1.Y}}
+	for reason, point := range map[string]*bls.G1Point{
+		"missing":                  nil,
+		"point at infinity":        bls.NewZeroG1Point(),
+		"not on curve":             offCurve,
+		"non-canonical coordinate": nonCanonical,
+	} {
+		err := ValidateG1Point("signature", point)
+		var invalid *InvalidPointError
+		if !errors.As(err, &invalid) || invalid.Reason != reason || invalid.Point != "signature" {
+			t.Errorf("Expected the point to be rejected as %q, got %v", reason, err)
+		}
+	}
+}
+
+func TestValidateG2Point(t *testing.T) {
+	_, _, _, g2 := bn254.Generators()
+	if err := ValidateG2Point("public key", &bls.G2Point{G2Affine: &g2}); err != nil {
+		t.Fatalf("Expected the generator to be valid, got %v", err)
+	}
+
+	offCurve := g2
+	offCurve.Y.A0.SetOne()
+	var invalid *InvalidPointError
+	if err := ValidateG2Point("public key", &bls.G2Point{G2Affine: &offCurve}); !errors.As(err, &invalid) || invalid.Reason != "not on curve" {
+		t.Errorf("Expected the point to be rejected as not on curve, got %v", err)
+	}
+}
+
+func TestCompressedPoints(t *testing.T) {
+	keyPair, err := bls.NewKeyPairFromString("12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	compressedG1 := CompressG1Point(keyPair.GetPubKeyG1())
+	g1, err := DecompressG1Point("public key", compressedG1[:])
+	if err != nil || !g1.Equal(keyPair.GetPubKeyG1().G1Affine) {
+		t.Errorf("Expected the G1 point to be decompressed, got %v and %v", g1, err)
+	}
+	compressedG2 := CompressG2Point(keyPair.GetPubKeyG2())
+	g2, err := DecompressG2Point("public key", compressedG2[:])
+	if err != nil || !g2.Equal(keyPair.GetPubKeyG2().G2Affine) {
+		t.Errorf("Expected the G2 point to be decompressed, got %v and %v", g2, err)
+	}
+
+	// A point of the twist curve outside the G2 subgroup, y^2 = x^3 + 3/(9+u)
+	var b, x, ySquared, y bn254.E2
+	b.A0.SetUint64(9)
+	b.A1.SetOne()
+	b.Inverse(&b).MulByElement(&b, new(fp.Element).SetUint64(3))
+	for x.A0.SetOne(); ; x.A0.Add(&x.A0, new(fp.Element).SetOne()) {
+		ySquared.Square(&x).Mul(&ySquared, &x).Add(&ySquared, &b)
+		if ySquared.Legendre() == 1 {
+			break
+		}
+	}
+	y.Sqrt(&ySquared)
+	outsideSubgroup := bn254.G2Affine{X: x, Y: y}
+	compressedOutside := outsideSubgroup.Bytes()
+
+	infinity := CompressG1Point(bls.NewZeroG1Point())
+	for reason, decompress := range map[string]func() error{
+		"invalid encoding": func() error {
+			_, err := DecompressG1Point("public key", compressedG1[:31])
+			return err
+		},
+		"point at infinity": func() error {
+			_, err := DecompressG1Point("public key", infinity[:])
+			return err
+		},
+		"not in subgroup": func() error {
+			_, err := DecompressG2Point("public key", compressedOutside[:])
+			return err
+		},
+	} {
+		var invalid *InvalidPointError
+		if err := decompress(); !errors.As(err, &invalid) || invalid.Reason != reason {
+			t.Errorf("Expected the point to be rejected as %q, got %v", reason, err)
+		}
+	}
+}
+
+func TestDeserializeG1Point(t *testing.T) {
+	keyPair, err := bls.NewKeyPairFromString("12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	serialized := keyPair.GetPubKeyG1().Serialize()
+	g1, err := DeserializeG1Point("signature", serialized)
+	if err != nil || !g1.Equal(keyPair.GetPubKeyG1().G1Affine) {
+		t.Errorf("Expected the G1 point to be deserialized, got %v and %v", g1, err)
+	}
+
+	// X plus the modulus is reduced to the same point by bls.G1Point.Deserialize
+	x := new(big.Int).SetBytes(serialized[:fp.Bytes])
+	nonCanonical := append(x.Add(x, fp.Modulus()).FillBytes(make([]byte, fp.Bytes)), serialized[fp.Bytes:]...)
+	offCurve := append([]byte{}, serialized...)
+	offCurve[G1SerializedSize-1] ^= 1
+	for reason, data := range map[string][]byte{
+		"invalid encoding":         serialized[:fp.Bytes],
+		"non-canonical coordinate": nonCanonical,
+		"point at infinity":        make([]byte, G1SerializedSize),
+		"not on curve":             offCurve,
+	} {
+		var invalid *InvalidPointError
+		if _, err := DeserializeG1Point("signature", data); !errors.As(err, &invalid) || invalid.Reason != reason {
+			t.Errorf("Expected the point to be rejected as %q, got %v", reason, err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
 )
 
@@ -25,3 +26,37 @@ func ConvertToBN254G2Point(input *bls.G2Point) servicemanager.BN254G2Point {
 	}
 	return output
 }
+
+// ConvertFromBN254G1Point returns the point of the contracts, failing with an InvalidPointError
+// unless it's a point ValidateG1Point accepts
+func ConvertFromBN254G1Point(name string, input servicemanager.BN254G1Point) (*bls.G1Point, error) {
+	if !isCanonicalBigInt(input.X) || !isCanonicalBigInt(input.Y) {
+		return nil, &InvalidPointError{Point: name, Reason: "non-canonical coordinate"}
+	}
+	output := bls.NewG1Point(input.X, input.Y)
+	if err := ValidateG1Point(name, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// ConvertFromBN254G2Point returns the point of the contracts, whose coordinates have the imaginary
+// part first, failing with an InvalidPointError unless it's a point ValidateG2Point accepts
+func ConvertFromBN254G2Point(name string, input servicemanager.BN254G2Point) (*bls.G2Point, error) {
+	for _, coordinate := range [][2]*big.Int{input.X, input.Y} {
+		if !isCanonicalBigInt(coordinate[0]) || !isCanonicalBigInt(coordinate[1]) {
+			return nil, &InvalidPointError{Point: name, Reason: "non-canonical coordinate"}
+		}
+	}
+	output := bls.NewG2Point(input.X, input.Y)
+	if err := ValidateG2Point(name, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// isCanonicalBigInt returns whether the coordinate is set and reduced modulo the field modulus,
+// as the points would otherwise be reduced silently
+func isCanonicalBigInt(coordinate *big.Int) bool {
+	return coordinate != nil && coordinate.Sign() >= 0 && coordinate.Cmp(fp.Modulus()) < 0
+}
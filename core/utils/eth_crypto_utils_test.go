@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+func TestConvertFromBN254Points(t *testing.T) {
+	keyPair, err := bls.NewKeyPairFromString("12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g1, err := ConvertFromBN254G1Point("public key", ConvertToBN254G1Point(keyPair.GetPubKeyG1()))
+	if err != nil || !g1.Equal(keyPair.GetPubKeyG1().G1Affine) {
+		t.Errorf("Expected the G1 point to be converted back, got %v and %v", g1, err)
+	}
+	g2, err := ConvertFromBN254G2Point("public key", ConvertToBN254G2Point(keyPair.GetPubKeyG2()))
+	if err != nil || !g2.Equal(keyPair.GetPubKeyG2().G2Affine) {
+		t.Errorf("Expected the G2 point to be converted back, got %v and %v", g2, err)
+	}
+
+	// The same point with the modulus added to x, which NewG1Point would reduce
+	point := ConvertToBN254G1Point(keyPair.GetPubKeyG1())
+	point.X = new(big.Int).Add(point.X, fp.Modulus())
+	var invalid *InvalidPointError
+	if _, err := ConvertFromBN254G1Point("public key", point); !errors.As(err, &invalid) || invalid.Reason != "non-canonical coordinate" {
+		t.Errorf("Expected the point to be rejected as non-canonical, got %v", err)
+	}
+}
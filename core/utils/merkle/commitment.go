@@ -0,0 +1,44 @@
+package merkle
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// VerificationDataCommitment is the leaf of a proof in the batch merkle tree, computed alike by the
+// batcher, the SDK and the operators
+type VerificationDataCommitment struct {
+	ProofCommitment    [32]byte
+	PubInputCommitment [32]byte
+	// ProvingSystemAuxDataCommitment commits to the program of the zkVM proving systems, or to
+	// the verification key of the rest, along with the proving system
+	ProvingSystemAuxDataCommitment [32]byte
+	ProofGeneratorAddr             [20]byte
+}
+
+// NewVerificationDataCommitment computes the commitment of a proof. The public input and aux data
+// commitments are zero when the proof has no public input, program or verification key.
+func NewVerificationDataCommitment(provingSystem byte, proof []byte, pubInput []byte, verificationKey []byte, vmProgramCode []byte, proofGeneratorAddr [20]byte) VerificationDataCommitment {
+	commitment := VerificationDataCommitment{
+		ProofCommitment:    crypto.Keccak256Hash(proof),
+		ProofGeneratorAddr: proofGeneratorAddr,
+	}
+	if pubInput != nil {
+		commitment.PubInputCommitment = crypto.Keccak256Hash(pubInput)
+	}
+	if vmProgramCode != nil {
+		commitment.ProvingSystemAuxDataCommitment = crypto.Keccak256Hash(vmProgramCode, []byte{provingSystem})
+	} else if verificationKey != nil {
+		commitment.ProvingSystemAuxDataCommitment = crypto.Keccak256Hash(verificationKey, []byte{provingSystem})
+	}
+	return commitment
+}
+
+// Hash returns the hash of the commitment, which is its leaf in the batch merkle tree
+func (c *VerificationDataCommitment) Hash() [32]byte {
+	return crypto.Keccak256Hash(
+		c.ProofCommitment[:],
+		c.PubInputCommitment[:],
+		c.ProvingSystemAuxDataCommitment[:],
+		c.ProofGeneratorAddr[:],
+	)
+}
@@ -0,0 +1,34 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVerificationDataCommitment(t *testing.T) {
+	proof, pubInput, verificationKey, program := []byte{1}, []byte{2}, []byte{3}, []byte{4}
+	sender := [20]byte{5}
+	const provingSystem = 6
+
+	commitment := NewVerificationDataCommitment(provingSystem, proof, pubInput, verificationKey, nil, sender)
+	if commitment.ProofCommitment != crypto.Keccak256Hash(proof) || commitment.PubInputCommitment != crypto.Keccak256Hash(pubInput) {
+		t.Errorf("Expected the proof and public input commitments to be their keccak")
+	}
+	if commitment.ProvingSystemAuxDataCommitment != crypto.Keccak256Hash(verificationKey, []byte{provingSystem}) {
+		t.Errorf("Expected the aux data commitment to commit to the verification key and proving system")
+	}
+	expected := crypto.Keccak256Hash(crypto.Keccak256(proof), crypto.Keccak256(pubInput), crypto.Keccak256(verificationKey, []byte{provingSystem}), sender[:])
+	if commitment.Hash() != expected {
+		t.Errorf("Expected the leaf to be the keccak of the commitments and sender")
+	}
+
+	// the program of zkVM proofs is committed instead of the verification key
+	commitment = NewVerificationDataCommitment(provingSystem, proof, nil, verificationKey, program, sender)
+	if commitment.ProvingSystemAuxDataCommitment != crypto.Keccak256Hash(program, []byte{provingSystem}) {
+		t.Errorf("Expected the aux data commitment to commit to the program")
+	}
+	if commitment.PubInputCommitment != ([32]byte{}) {
+		t.Errorf("Expected a zero public input commitment without public input")
+	}
+}
@@ -0,0 +1,186 @@
+// Package merkle builds and verifies the batch merkle trees and batch identifiers.
+//
+// Version V1 is the tree the batcher and the service manager use: the leaves are the proof
+// commitments, parents are the keccak of their children and the leaves are padded to a power of
+// two repeating the last one. Leaves and internal nodes are hashed alike, so an internal node can
+// be passed off as a leaf, and batches differing in the repeated last leaf have the same root.
+//
+// Version V2 hardens the tree: leaves and internal nodes are hashed with distinct domain prefixes,
+// so an internal node can't be presented as a leaf (second preimages), and the padding is a
+// constant node that no leaf hashes to.
+//
+// A Tree keeps the nodes of a batch tree, to return the inclusion proofs of all its leaves, the
+// paths, and multiproofs proving several leaves at once. The batcher, SDK and operators all use
+// it, so they compute the same roots.
+package merkle
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Version is the way the trees and batch identifiers are hashed
+type Version uint8
+
+const (
+	V1 Version = 1
+	V2 Version = 2
+)
+
+// CurrentVersion is the version of the deployed contracts, which every component must use
+// to compute the same roots and identifiers. It's switched to V2 once the contracts verify it.
+const CurrentVersion = V1
+
+// Domain prefixes of the V2 hashes
+const (
+	leafPrefix            byte = 0x00
+	nodePrefix            byte = 0x01
+	batchIdentifierPrefix byte = 0x02
+	paddingPrefix         byte = 0x03
+)
+
+var ErrEmptyTree = errors.New("merkle tree has no leaves")
+
+// paddingNode fills a V2 level up to a power of two, a leaf can't hash to it as it has another prefix
+var paddingNode = crypto.Keccak256Hash([]byte{paddingPrefix})
+
+// HashLeaf returns the tree node of the leaf
+func HashLeaf(version Version, leaf [32]byte) [32]byte {
+	if version == V1 {
+		return leaf
+	}
+	return crypto.Keccak256Hash([]byte{leafPrefix}, leaf[:])
+}
+
+// HashNodes returns the parent of the left and right nodes
+func HashNodes(version Version, left [32]byte, right [32]byte) [32]byte {
+	if version == V1 {
+		return crypto.Keccak256Hash(left[:], right[:])
+	}
+	return crypto.Keccak256Hash([]byte{nodePrefix}, left[:], right[:])
+}
+
+// BatchIdentifierHash identifies the batch with the merkle root sent by the sender, the key of the
+// batch state in the service manager and the message the operators sign
+func BatchIdentifierHash(version Version, batchMerkleRoot [32]byte, senderAddress [20]byte) [32]byte {
+	if version == V1 {
+		return crypto.Keccak256Hash(batchMerkleRoot[:], senderAddress[:])
+	}
+	return crypto.Keccak256Hash([]byte{batchIdentifierPrefix}, batchMerkleRoot[:], senderAddress[:])
+}
+
+// Root returns the merkle root of the leaves
+func Root(version Version, leaves [][32]byte) ([32]byte, error) {
+	root, _, err := Path(version, leaves, 0)
+	return root, err
+}
+
+// Path returns the merkle root of the leaves and the path of the leaf at the index: the sibling of
+// the leaf node followed by the siblings of its ancestors
+func Path(version Version, leaves [][32]byte, index int) ([32]byte, [][32]byte, error) {
+	tree, err := NewTree(version, leaves)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+	path, err := tree.Path(index)
+	return tree.Root(), path, err
+}
+
+// Tree is the merkle tree of a batch, keeping all its nodes to return the paths of its leaves
+// without hashing it again for each one
+type Tree struct {
+	version Version
+	size    int
+	// levels are the nodes of each level, from the leaf nodes padded to a power of two to the root
+	levels [][][32]byte
+}
+
+// NewTree builds the merkle tree of the leaves
+func NewTree(version Version, leaves [][32]byte) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTree
+	}
+
+	width := 1
+	for width < len(leaves) {
+		width *= 2
+	}
+	level := make([][32]byte, width)
+	for i, leaf := range leaves {
+		level[i] = HashLeaf(version, leaf)
+	}
+	for i := len(leaves); i < width; i++ {
+		if version == V1 {
+			level[i] = level[len(leaves)-1]
+		} else {
+			level[i] = paddingNode
+		}
+	}
+
+	levels := [][][32]byte{level}
+	for len(level) > 1 {
+		parents := make([][32]byte, len(level)/2)
+		for i := range parents {
+			parents[i] = HashNodes(version, level[2*i], level[2*i+1])
+		}
+		level = parents
+		levels = append(levels, level)
+	}
+	return &Tree{version: version, size: len(leaves), levels: levels}, nil
+}
+
+// Root returns the merkle root of the tree
+func (t *Tree) Root() [32]byte {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Len returns the number of leaves of the tree, without the padding
+func (t *Tree) Len() int {
+	return t.size
+}
+
+// Depth returns the number of levels below the root, the length of the paths
+func (t *Tree) Depth() int {
+	return len(t.levels) - 1
+}
+
+// Path returns the path of the leaf at the index, as the Path function
+func (t *Tree) Path(index int) ([][32]byte, error) {
+	if index < 0 || index >= t.size {
+		return nil, errors.New("leaf index out of the tree")
+	}
+	path := make([][32]byte, 0, t.Depth())
+	for _, level := range t.levels[:t.Depth()] {
+		path = append(path, level[index^1])
+		index /= 2
+	}
+	return path, nil
+}
+
+// Paths returns the path of every leaf, in order
+func (t *Tree) Paths() [][][32]byte {
+	paths := make([][][32]byte, t.size)
+	for i := range paths {
+		paths[i], _ = t.Path(i)
+	}
+	return paths
+}
+
+// VerifyPath checks the path leads from the leaf at the index to the root. Indexes outside the
+// tree the path spans are rejected, so a leaf can't be proven at more than one position.
+func VerifyPath(version Version, leaf [32]byte, path [][32]byte, index uint64, root [32]byte) bool {
+	if len(path) < 64 && index>>len(path) != 0 {
+		return false
+	}
+	node := HashLeaf(version, leaf)
+	for _, sibling := range path {
+		if index%2 == 0 {
+			node = HashNodes(version, node, sibling)
+		} else {
+			node = HashNodes(version, sibling, node)
+		}
+		index >>= 1
+	}
+	return node == root
+}
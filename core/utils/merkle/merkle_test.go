@@ -0,0 +1,116 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func testLeaves(n int) [][32]byte {
+	leaves := make([][32]byte, n)
+	for i := range leaves {
+		leaves[i] = crypto.Keccak256Hash([]byte{byte(i)})
+	}
+	return leaves
+}
+
+func TestV1MatchesTheBatcherTree(t *testing.T) {
+	leaves := testLeaves(3)
+	left := crypto.Keccak256Hash(leaves[0][:], leaves[1][:])
+	right := crypto.Keccak256Hash(leaves[2][:], leaves[2][:])
+	expected := crypto.Keccak256Hash(left[:], right[:])
+
+	root, err := Root(V1, leaves)
+	if err != nil || root != expected {
+		t.Fatalf("Expected root %x, got %x and %v", expected, root, err)
+	}
+
+	sender := [20]byte{1}
+	if BatchIdentifierHash(V1, root, sender) != crypto.Keccak256Hash(root[:], sender[:]) {
+		t.Errorf("Expected the V1 batch identifier to be the keccak of the root and sender")
+	}
+}
+
+func TestPathsVerify(t *testing.T) {
+	for _, version := range []Version{V1, V2} {
+		for size := 1; size <= 9; size++ {
+			leaves := testLeaves(size)
+			for i, leaf := range leaves {
+				root, path, err := Path(version, leaves, i)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if !VerifyPath(version, leaf, path, uint64(i), root) {
+					t.Errorf("V%d path of leaf %d of %d doesn't verify", version, i, size)
+				}
+				if VerifyPath(version, leaf, path, uint64(i)+uint64(1)<<len(path), root) {
+					t.Errorf("V%d path of leaf %d of %d verifies at an index outside the tree", version, i, size)
+				}
+			}
+		}
+	}
+	if _, err := Root(V2, nil); err != ErrEmptyTree {
+		t.Errorf("Expected the empty tree error, got %v", err)
+	}
+}
+
+func TestV2SecondPreimageProtection(t *testing.T) {
+	leaves := testLeaves(4)
+
+	// An internal node presented as a leaf, with the path of its parent
+	for _, version := range []Version{V1, V2} {
+		root, path, _ := Path(version, leaves, 0)
+		internalNode := HashNodes(version, HashLeaf(version, leaves[0]), HashLeaf(version, leaves[1]))
+		verified := VerifyPath(version, internalNode, path[1:], 0, root)
+		if version == V1 && !verified {
+			t.Errorf("Expected V1 to accept the internal node as a leaf")
+		}
+		if version == V2 && verified {
+			t.Errorf("Expected V2 to reject the internal node as a leaf")
+		}
+	}
+
+	// The padding repeating the last leaf makes V1 roots ambiguous
+	three := testLeaves(3)
+	four := append(testLeaves(3), three[2])
+	rootV1Three, _ := Root(V1, three)
+	rootV1Four, _ := Root(V1, four)
+	if rootV1Three != rootV1Four {
+		t.Errorf("Expected V1 roots of the padded batches to collide")
+	}
+	rootV2Three, _ := Root(V2, three)
+	rootV2Four, _ := Root(V2, four)
+	if rootV2Three == rootV2Four {
+		t.Errorf("Expected V2 roots of the padded batches to differ")
+	}
+}
+
+func TestTreePathsMatchPath(t *testing.T) {
+	for _, version := range []Version{V1, V2} {
+		leaves := testLeaves(5)
+		tree, err := NewTree(version, leaves)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for i, path := range tree.Paths() {
+			root, expected, _ := Path(version, leaves, i)
+			if tree.Root() != root || len(path) != len(expected) || len(path) != tree.Depth() {
+				t.Fatalf("V%d tree path of leaf %d doesn't match", version, i)
+			}
+			for j := range path {
+				if path[j] != expected[j] {
+					t.Errorf("V%d tree path of leaf %d differs at %d", version, i, j)
+				}
+			}
+		}
+		if _, err := tree.Path(5); err == nil {
+			t.Errorf("Expected the padding to have no path")
+		}
+	}
+
+	// a single leaf is the V1 root, with an empty path
+	tree, _ := NewTree(V1, [][32]byte{{1}})
+	if tree.Root() != [32]byte{1} || len(tree.Paths()) != 1 || len(tree.Paths()[0]) != 0 {
+		t.Errorf("Unexpected tree of one leaf: %x, %v", tree.Root(), tree.Paths())
+	}
+}
@@ -0,0 +1,101 @@
+package merkle
+
+import (
+	"errors"
+	"sort"
+)
+
+// MultiProof proves the inclusion of several leaves of a tree at once, sharing the nodes their
+// paths have in common and leaving out the ones computed from the leaves themselves
+type MultiProof struct {
+	// Depth is the number of levels below the root
+	Depth int
+	// Indexes are the positions of the proven leaves, ascending
+	Indexes []uint64
+	// Nodes are the siblings the leaves can't compute, from the leaf level up and left to right in
+	// each level
+	Nodes [][32]byte
+}
+
+// MultiProof returns the proof of the leaves at the indexes, which must be distinct
+func (t *Tree) MultiProof(indexes []int) (MultiProof, error) {
+	if len(indexes) == 0 {
+		return MultiProof{}, errors.New("no leaf to prove")
+	}
+	sorted := make([]uint64, len(indexes))
+	for i, index := range indexes {
+		if index < 0 || index >= t.size {
+			return MultiProof{}, errors.New("leaf index out of the tree")
+		}
+		sorted[i] = uint64(index)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return MultiProof{}, errors.New("leaf index repeated")
+		}
+	}
+
+	proof := MultiProof{Depth: t.Depth(), Indexes: sorted}
+	known := sorted
+	for _, level := range t.levels[:t.Depth()] {
+		var parents []uint64
+		for i := 0; i < len(known); i++ {
+			index := known[i]
+			if index%2 == 0 && i+1 < len(known) && known[i+1] == index+1 {
+				// both children are known
+				i++
+			} else {
+				proof.Nodes = append(proof.Nodes, level[index^1])
+			}
+			parents = append(parents, index/2)
+		}
+		known = parents
+	}
+	return proof, nil
+}
+
+// VerifyMultiProof checks the proof leads from the leaves, in the order of the proof indexes, to
+// the root. As with VerifyPath, indexes outside the tree the proof spans are rejected.
+func VerifyMultiProof(version Version, leaves [][32]byte, proof MultiProof, root [32]byte) bool {
+	if len(leaves) == 0 || len(leaves) != len(proof.Indexes) || proof.Depth < 0 || proof.Depth >= 64 {
+		return false
+	}
+	for i, index := range proof.Indexes {
+		if index>>proof.Depth != 0 || (i > 0 && index <= proof.Indexes[i-1]) {
+			return false
+		}
+	}
+
+	indexes := append([]uint64(nil), proof.Indexes...)
+	nodes := make([][32]byte, len(leaves))
+	for i, leaf := range leaves {
+		nodes[i] = HashLeaf(version, leaf)
+	}
+	siblings := proof.Nodes
+	for level := 0; level < proof.Depth; level++ {
+		var parentIndexes []uint64
+		var parents [][32]byte
+		for i := 0; i < len(indexes); i++ {
+			index := indexes[i]
+			var left, right [32]byte
+			switch {
+			case index%2 == 0 && i+1 < len(indexes) && indexes[i+1] == index+1:
+				left, right = nodes[i], nodes[i+1]
+				i++
+			case len(siblings) == 0:
+				return false
+			case index%2 == 0:
+				left, right = nodes[i], siblings[0]
+				siblings = siblings[1:]
+			default:
+				left, right = siblings[0], nodes[i]
+				siblings = siblings[1:]
+			}
+			parentIndexes = append(parentIndexes, index/2)
+			parents = append(parents, HashNodes(version, left, right))
+		}
+		indexes, nodes = parentIndexes, parents
+	}
+	return len(siblings) == 0 && nodes[0] == root
+}
@@ -0,0 +1,78 @@
+package merkle
+
+import (
+	"testing"
+)
+
+func TestMultiProofsVerify(t *testing.T) {
+	for _, version := range []Version{V1, V2} {
+		for size := 1; size <= 9; size++ {
+			leaves := testLeaves(size)
+			tree, err := NewTree(version, leaves)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			// every subset of the leaves
+			for subset := 1; subset < 1<<size; subset++ {
+				var indexes []int
+				var proven [][32]byte
+				for i := 0; i < size; i++ {
+					if subset&(1<<i) != 0 {
+						indexes = append(indexes, i)
+						proven = append(proven, leaves[i])
+					}
+				}
+				proof, err := tree.MultiProof(indexes)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if !VerifyMultiProof(version, proven, proof, tree.Root()) {
+					t.Errorf("V%d multiproof of leaves %v of %d doesn't verify", version, indexes, size)
+				}
+				if len(proof.Nodes) > len(indexes)*tree.Depth() {
+					t.Errorf("V%d multiproof of leaves %v of %d has more nodes than their paths", version, indexes, size)
+				}
+			}
+		}
+	}
+}
+
+func TestMultiProofRejectsTampering(t *testing.T) {
+	leaves := testLeaves(8)
+	tree, _ := NewTree(V2, leaves)
+	proof, err := tree.MultiProof([]int{5, 1, 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	proven := [][32]byte{leaves[1], leaves[2], leaves[5]}
+	if !VerifyMultiProof(V2, proven, proof, tree.Root()) {
+		t.Fatalf("Expected the multiproof to verify")
+	}
+
+	swapped := [][32]byte{leaves[2], leaves[1], leaves[5]}
+	if VerifyMultiProof(V2, swapped, proof, tree.Root()) {
+		t.Errorf("Expected the leaves out of the indexes order to be rejected")
+	}
+	truncated := proof
+	truncated.Nodes = proof.Nodes[:len(proof.Nodes)-1]
+	if VerifyMultiProof(V2, proven, truncated, tree.Root()) {
+		t.Errorf("Expected a missing node to be rejected")
+	}
+	extended := proof
+	extended.Nodes = append(append([][32]byte{}, proof.Nodes...), [32]byte{})
+	if VerifyMultiProof(V2, proven, extended, tree.Root()) {
+		t.Errorf("Expected an extra node to be rejected")
+	}
+	outside := proof
+	outside.Indexes = []uint64{1, 2, 5 + 8}
+	if VerifyMultiProof(V2, proven, outside, tree.Root()) {
+		t.Errorf("Expected an index outside the tree to be rejected")
+	}
+
+	if _, err := tree.MultiProof([]int{1, 1}); err == nil {
+		t.Errorf("Expected a repeated index to be rejected")
+	}
+	if _, err := tree.MultiProof([]int{8}); err == nil {
+		t.Errorf("Expected an index outside the leaves to be rejected")
+	}
+}
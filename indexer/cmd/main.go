@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/indexer/pkg"
+)
+
+var (
+	// Version is the version of the binary.
+	Version   string
+	GitCommit string
+	GitDate   string
+)
+
+var flags = []cli.Flag{
+	config.ConfigFileFlag,
+}
+
+var (
+	datasetFlag = &cli.StringFlag{
+		Name:     "dataset",
+		Usage:    "Dataset to export: batches, proofs or participation",
+		Required: true,
+	}
+	formatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "Format of the export: csv or parquet",
+		Value: string(pkg.CSV),
+	}
+	fromFlag = &cli.TimestampFlag{
+		Name:     "from",
+		Usage:    "Start of the range exported, included, as YYYY-MM-DD",
+		Layout:   time.DateOnly,
+		Timezone: time.UTC,
+		Required: true,
+	}
+	toFlag = &cli.TimestampFlag{
+		Name:     "to",
+		Usage:    "End of the range exported, excluded, as YYYY-MM-DD",
+		Layout:   time.DateOnly,
+		Timezone: time.UTC,
+		Required: true,
+	}
+	outputFlag = &cli.StringFlag{
+		Name:     "output",
+		Usage:    "File the export is written to",
+		Required: true,
+	}
+)
+
+// exportCommand dumps the indexed rows of a range to a file, for offline analysis
+var exportCommand = &cli.Command{
+	Name:   "export",
+	Usage:  "Export the indexed batches, proofs or operator participation of a range to CSV or Parquet",
+	Flags:  []cli.Flag{datasetFlag, formatFlag, fromFlag, toFlag, outputFlag},
+	Action: exportMain,
+}
+
+func main() {
+	app := cli.NewApp()
+
+	app.Flags = flags
+	app.Version = fmt.Sprintf("%s-%s-%s", Version, GitCommit, GitDate)
+	app.Name = "aligned-layer-indexer"
+	app.Usage = "Aligned Layer Indexer"
+	app.Description = "Service that indexes the batches of the service manager and their responses into Postgres."
+	app.Action = indexerMain
+	app.Commands = []*cli.Command{exportCommand}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Fatalln("Application failed.", "Message:", err)
+	}
+}
+
+func indexerMain(ctx *cli.Context) error {
+	configFilePath := ctx.String(config.ConfigFileFlag.Name)
+	indexerConfig := config.NewIndexerConfig(configFilePath)
+	logger := indexerConfig.BaseConfig.Logger
+
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	store, err := pkg.NewPostgresStore(runCtx, indexerConfig.Indexer.DatabaseUrl)
+	if err != nil {
+		logger.Error("Cannot connect to the indexer database", "err", err)
+		return err
+	}
+	defer store.Close()
+
+	indexer, err := pkg.NewIndexer(*indexerConfig, store)
+	if err != nil {
+		logger.Error("Cannot create indexer", "err", err)
+		return err
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- pkg.NewAPI(logger, store, store).Serve(runCtx, indexerConfig.Indexer.ApiIpPortAddress)
+		stop()
+	}()
+
+	if indexerConfig.Indexer.IndexProofs {
+		go func() {
+			err := pkg.NewProofIndexer(*indexerConfig, store).Start(runCtx)
+			if !errors.Is(err, context.Canceled) {
+				logger.Error("Proof indexer stopped", "err", err)
+			}
+		}()
+		// webhooks are sent for the indexed proofs
+		go func() {
+			err := pkg.NewWebhookNotifier(logger, store, indexerConfig.Indexer.PollInterval).Start(runCtx)
+			if !errors.Is(err, context.Canceled) {
+				logger.Error("Webhook notifier stopped", "err", err)
+			}
+		}()
+	}
+
+	logger.Info("Indexer started", "fromBlock", indexerConfig.Indexer.FromBlock)
+	if err := indexer.Start(runCtx); !errors.Is(err, context.Canceled) {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+func exportMain(ctx *cli.Context) error {
+	dataset, err := pkg.ParseExportDataset(ctx.String(datasetFlag.Name))
+	if err != nil {
+		return err
+	}
+	format, err := pkg.ParseExportFormat(ctx.String(formatFlag.Name))
+	if err != nil {
+		return err
+	}
+	from, to := *ctx.Timestamp(fromFlag.Name), *ctx.Timestamp(toFlag.Name)
+	if !from.Before(to) {
+		return errors.New("the start of the range must be before its end")
+	}
+
+	configFilePath := ctx.String(config.ConfigFileFlag.Name)
+	indexerConfig := config.NewIndexerConfig(configFilePath)
+	store, err := pkg.NewPostgresStore(ctx.Context, indexerConfig.Indexer.DatabaseUrl)
+	if err != nil {
+		return fmt.Errorf("cannot connect to the indexer database: %w", err)
+	}
+	defer store.Close()
+
+	output, err := os.Create(ctx.String(outputFlag.Name))
+	if err != nil {
+		return err
+	}
+	if err := pkg.Export(ctx.Context, store, output, dataset, format, from, to); err != nil {
+		_ = output.Close()
+		return fmt.Errorf("could not export %s: %w", dataset, err)
+	}
+	return output.Close()
+}
@@ -0,0 +1,195 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AnalyticsPeriod is the length of the periods the analytics are aggregated by, starting at
+// midnight UTC, on mondays for the weeks
+type AnalyticsPeriod string
+
+const (
+	Day  AnalyticsPeriod = "day"
+	Week AnalyticsPeriod = "week"
+)
+
+// AnalyticsFilter selects the time range of the analytics, from included and to excluded
+type AnalyticsFilter struct {
+	Period AnalyticsPeriod
+	From   time.Time
+	To     time.Time
+}
+
+// PeriodStats are the aggregates of a period. The batches are counted when their task is created,
+// and everything else when their response is.
+type PeriodStats struct {
+	Start           time.Time
+	BatchesCreated  uint64
+	BatchesVerified uint64
+	// ProofsVerified counts the proofs of the verified batches whose proofs were indexed
+	ProofsVerified uint64
+	// GasUsed and GasCost, in wei, are the spend of the aggregators responding to the tasks
+	GasUsed uint64
+	GasCost *big.Int
+	// IndexedGasCost is the part of GasCost spent on the batches whose proofs were indexed, over
+	// which the average cost per proof is taken
+	IndexedGasCost *big.Int
+	// QuorumTimes are the seconds from the creation of the tasks to their responses, nil without
+	// verified batches
+	QuorumTimes *QuorumTimes
+}
+
+// QuorumTimes summarize the seconds the tasks of a period took to reach quorum
+type QuorumTimes struct {
+	Average float64
+	Median  float64
+	P95     float64
+	Max     float64
+}
+
+// AverageCostPerProof returns the gas cost per proof in wei, nil if no proofs were verified
+func (s *PeriodStats) AverageCostPerProof() *big.Int {
+	if s.ProofsVerified == 0 {
+		return nil
+	}
+	return new(big.Int).Div(s.IndexedGasCost, new(big.Int).SetUint64(s.ProofsVerified))
+}
+
+// AggregatorSpend is the gas an aggregator spent responding to tasks
+type AggregatorSpend struct {
+	AggregatorAddress common.Address
+	Responses         uint64
+	GasUsed           uint64
+	GasCost           *big.Int
+}
+
+// periodStatsQuery aggregates the batches, their responses and their proofs by period. Proofs
+// are only counted, and their gas cost only taken, for the batches with indexed proofs.
+const periodStatsQuery = `WITH created AS (
+		SELECT date_trunc($1, task_created_at AT TIME ZONE 'UTC') AS period, COUNT(*) AS batches
+		FROM batches WHERE task_created_at >= $2 AND task_created_at < $3 GROUP BY 1
+	), verified AS (
+		SELECT date_trunc($1, r.responded_at AT TIME ZONE 'UTC') AS period, COUNT(*) AS batches,
+			SUM(r.gas_used) AS gas_used, SUM(r.gas_used * r.effective_gas_price) AS gas_cost,
+			AVG(EXTRACT(EPOCH FROM r.responded_at - b.task_created_at)) AS average,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM r.responded_at - b.task_created_at)) AS median,
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM r.responded_at - b.task_created_at)) AS p95,
+			MAX(EXTRACT(EPOCH FROM r.responded_at - b.task_created_at)) AS max
+		FROM responses r JOIN batches b USING (batch_merkle_root, sender_address)
+		WHERE r.responded_at >= $2 AND r.responded_at < $3 GROUP BY 1
+	), indexed AS (
+		SELECT date_trunc($1, r.responded_at AT TIME ZONE 'UTC') AS period, SUM(p.proofs) AS proofs,
+			SUM(r.gas_used * r.effective_gas_price) AS gas_cost
+		FROM responses r JOIN (
+			SELECT batch_merkle_root, sender_address, COUNT(*) AS proofs FROM proofs GROUP BY 1, 2
+		) p USING (batch_merkle_root, sender_address)
+		WHERE r.responded_at >= $2 AND r.responded_at < $3 GROUP BY 1
+	)
+	SELECT period, COALESCE(c.batches, 0), COALESCE(v.batches, 0), COALESCE(i.proofs, 0)::BIGINT,
+		COALESCE(v.gas_used, 0)::BIGINT, COALESCE(v.gas_cost, 0)::TEXT, COALESCE(i.gas_cost, 0)::TEXT,
+		v.average, v.median, v.p95, v.max
+	FROM created c FULL JOIN verified v USING (period) FULL JOIN indexed i USING (period)
+	ORDER BY period`
+
+func (s *PostgresStore) PeriodStats(ctx context.Context, filter AnalyticsFilter) ([]PeriodStats, error) {
+	rows, err := s.db.QueryContext(ctx, periodStatsQuery, string(filter.Period), filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []PeriodStats
+	for rows.Next() {
+		var period PeriodStats
+		var created, verified, proofs, gasUsed int64
+		var gasCost, indexedGasCost string
+		var average, median, p95, max sql.NullFloat64
+		err := rows.Scan(&period.Start, &created, &verified, &proofs, &gasUsed, &gasCost, &indexedGasCost,
+			&average, &median, &p95, &max)
+		if err != nil {
+			return nil, err
+		}
+		period.Start = time.Date(period.Start.Year(), period.Start.Month(), period.Start.Day(), 0, 0, 0, 0, time.UTC)
+		period.BatchesCreated, period.BatchesVerified = uint64(created), uint64(verified)
+		period.ProofsVerified, period.GasUsed = uint64(proofs), uint64(gasUsed)
+		period.GasCost, _ = new(big.Int).SetString(gasCost, 10)
+		period.IndexedGasCost, _ = new(big.Int).SetString(indexedGasCost, 10)
+		if average.Valid {
+			period.QuorumTimes = &QuorumTimes{Average: average.Float64, Median: median.Float64, P95: p95.Float64, Max: max.Float64}
+		}
+		stats = append(stats, period)
+	}
+	return stats, rows.Err()
+}
+
+func (s *PostgresStore) AggregatorSpend(ctx context.Context, from time.Time, to time.Time) ([]AggregatorSpend, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT aggregator_address, COUNT(*), SUM(gas_used)::BIGINT,
+		SUM(gas_used * effective_gas_price)::TEXT
+		FROM responses WHERE responded_at >= $1 AND responded_at < $2
+		GROUP BY aggregator_address ORDER BY SUM(gas_used * effective_gas_price) DESC, aggregator_address`,
+		from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var spend []AggregatorSpend
+	for rows.Next() {
+		var aggregator AggregatorSpend
+		var address []byte
+		var responses, gasUsed int64
+		var gasCost string
+		if err := rows.Scan(&address, &responses, &gasUsed, &gasCost); err != nil {
+			return nil, err
+		}
+		aggregator.AggregatorAddress = common.BytesToAddress(address)
+		aggregator.Responses, aggregator.GasUsed = uint64(responses), uint64(gasUsed)
+		aggregator.GasCost, _ = new(big.Int).SetString(gasCost, 10)
+		spend = append(spend, aggregator)
+	}
+	return spend, rows.Err()
+}
+
+// periodStart returns the start of the period of the time
+func (p AnalyticsPeriod) periodStart(t time.Time) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if p == Week {
+		// weeks start on mondays
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	}
+	return start
+}
+
+func (p AnalyticsPeriod) days() int {
+	if p == Week {
+		return 7
+	}
+	return 1
+}
+
+func (p AnalyticsPeriod) next(start time.Time) time.Time {
+	return start.AddDate(0, 0, p.days())
+}
+
+// fillPeriods returns the stats of every period of the filter range, with empty stats for the
+// periods without activity
+func fillPeriods(filter AnalyticsFilter, stats []PeriodStats) []PeriodStats {
+	byStart := make(map[time.Time]PeriodStats, len(stats))
+	for _, period := range stats {
+		byStart[period.Start] = period
+	}
+	var filled []PeriodStats
+	for start := filter.Period.periodStart(filter.From); start.Before(filter.To); start = filter.Period.next(start) {
+		period, ok := byStart[start]
+		if !ok {
+			period = PeriodStats{Start: start, GasCost: new(big.Int), IndexedGasCost: new(big.Int)}
+		}
+		filled = append(filled, period)
+	}
+	return filled
+}
@@ -0,0 +1,1040 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/sdk/aligned"
+)
+
+const (
+	// DefaultPageSize is the number of batches listed if no limit is given
+	DefaultPageSize = 50
+	// MaxPageSize bounds the number of batches listed at once
+	MaxPageSize = 500
+	// MaxAnalyticsPeriods bounds the number of periods of the analytics endpoints
+	MaxAnalyticsPeriods = 366
+	// DefaultParticipationWindow is the window of the participation endpoints if none is given
+	DefaultParticipationWindow = 7 * 24 * time.Hour
+	// defaultAnalyticsPeriods is the number of periods of the analytics if no range is given
+	defaultAnalyticsPeriods = 30
+	// batchLeavesTimeout bounds the download of the leaves of a batch to list its proofs
+	batchLeavesTimeout = 30 * time.Second
+)
+
+// LeavesSource downloads the leaves of a batch, checking they build its merkle root
+type LeavesSource func(ctx context.Context, batchDataPointer string, batchMerkleRoot [32]byte) ([][32]byte, error)
+
+// API serves the indexed batches over HTTP, and the webhook subscriptions if their store is set
+type API struct {
+	logger        sdklogging.Logger
+	store         QueryStore
+	subscriptions SubscriptionStore
+	leaves        LeavesSource
+}
+
+func NewAPI(logger sdklogging.Logger, store QueryStore, subscriptions SubscriptionStore) *API {
+	client := &http.Client{Timeout: batchLeavesTimeout}
+	return newAPI(logger, store, subscriptions, func(ctx context.Context, batchDataPointer string, batchMerkleRoot [32]byte) ([][32]byte, error) {
+		return aligned.FetchBatchLeaves(ctx, client, batchDataPointer, batchMerkleRoot)
+	})
+}
+
+func newAPI(logger sdklogging.Logger, store QueryStore, subscriptions SubscriptionStore, leaves LeavesSource) *API {
+	return &API{logger: logger, store: store, subscriptions: subscriptions, leaves: leaves}
+}
+
+// BatchResponse is a batch served by the API, with the response to its task if responded. The
+// confirmations of its task creation and response are omitted before the first indexing round.
+type BatchResponse struct {
+	BatchMerkleRoot       string             `json:"batch_merkle_root"`
+	SenderAddress         string             `json:"sender_address"`
+	BatchDataPointer      string             `json:"batch_data_pointer"`
+	RespondToTaskFeeLimit string             `json:"respond_to_task_fee_limit"`
+	TaskCreatedBlock      uint64             `json:"task_created_block"`
+	TaskCreatedTx         string             `json:"task_created_tx"`
+	TaskCreatedAt         time.Time          `json:"task_created_at"`
+	Confirmations         *uint64            `json:"confirmations,omitempty"`
+	Finalized             bool               `json:"finalized"`
+	Response              *TaskResponseEntry `json:"response"`
+}
+
+// TaskResponseEntry is the response to the task of a batch served by the API
+type TaskResponseEntry struct {
+	BlockNumber       uint64    `json:"block_number"`
+	TxHash            string    `json:"tx_hash"`
+	AggregatorAddress string    `json:"aggregator_address"`
+	GasUsed           uint64    `json:"gas_used"`
+	EffectiveGasPrice string    `json:"effective_gas_price"`
+	RespondedAt       time.Time `json:"responded_at"`
+	Confirmations     *uint64   `json:"confirmations,omitempty"`
+	Finalized         bool      `json:"finalized"`
+}
+
+// BatchListResponse is the body of the /batches endpoint. NextOffset is the offset of the next
+// page, omitted on the last one.
+type BatchListResponse struct {
+	Batches    []BatchResponse `json:"batches"`
+	NextOffset *int            `json:"next_offset,omitempty"`
+}
+
+// ProofEntry is a proof of a batch, by the commitment hash the batch merkle tree has as leaf. The
+// proving system and generator address are omitted if the payload of the batch wasn't indexed, and
+// the batch in the proofs of a batch.
+type ProofEntry struct {
+	BatchMerkleRoot    string `json:"batch_merkle_root,omitempty"`
+	SenderAddress      string `json:"sender_address,omitempty"`
+	Index              uint64 `json:"index"`
+	Commitment         string `json:"commitment"`
+	ProvingSystem      string `json:"proving_system,omitempty"`
+	ProofGeneratorAddr string `json:"proof_generator_addr,omitempty"`
+}
+
+// ProofListResponse is the body of the /proofs endpoint. NextOffset is the offset of the next
+// page, omitted on the last one.
+type ProofListResponse struct {
+	Proofs     []ProofEntry `json:"proofs"`
+	NextOffset *int         `json:"next_offset,omitempty"`
+}
+
+// BatchProofsResponse is the body of the /batches/{root}/proofs endpoint
+type BatchProofsResponse struct {
+	BatchMerkleRoot string       `json:"batch_merkle_root"`
+	SenderAddress   string       `json:"sender_address"`
+	Proofs          []ProofEntry `json:"proofs"`
+}
+
+// LatencyResponse is the body of the /batches/{root}/latency endpoint, the time from the creation of
+// the task of a batch to its response. The latency is omitted while the batch isn't responded.
+type LatencyResponse struct {
+	BatchMerkleRoot string   `json:"batch_merkle_root"`
+	SenderAddress   string   `json:"sender_address"`
+	Responded       bool     `json:"responded"`
+	Blocks          *uint64  `json:"blocks,omitempty"`
+	Seconds         *float64 `json:"seconds,omitempty"`
+}
+
+// ParticipationResponse is the body of the /batches/{root}/participation endpoint, the operators
+// that didn't sign the response of a batch, by id
+type ParticipationResponse struct {
+	BatchMerkleRoot   string   `json:"batch_merkle_root"`
+	SenderAddress     string   `json:"sender_address"`
+	Responded         bool     `json:"responded"`
+	AggregatorAddress string   `json:"aggregator_address,omitempty"`
+	NonSigners        []string `json:"non_signers"`
+}
+
+// OperatorResponse is an operator of the directory served by the API
+type OperatorResponse struct {
+	Id                string             `json:"id"`
+	Address           string             `json:"address"`
+	Registered        bool               `json:"registered"`
+	RegistrationBlock uint64             `json:"registration_block"`
+	Socket            string             `json:"socket"`
+	PubkeyG1          string             `json:"pubkey_g1,omitempty"`
+	Stakes            []QuorumStakeEntry `json:"stakes"`
+}
+
+// QuorumStakeEntry is the stake of an operator in a quorum served by the API
+type QuorumStakeEntry struct {
+	QuorumNumber uint8  `json:"quorum_number"`
+	Stake        string `json:"stake"`
+}
+
+// OperatorListResponse is the body of the /operators endpoint. NextOffset is the offset of the
+// next page, omitted on the last one.
+type OperatorListResponse struct {
+	Operators  []OperatorResponse `json:"operators"`
+	NextOffset *int               `json:"next_offset,omitempty"`
+}
+
+// AnalyticsResponse is the body of the /analytics/daily and /analytics/weekly endpoints, with
+// every period of the range, including the ones without activity
+type AnalyticsResponse struct {
+	Period  AnalyticsPeriod       `json:"period"`
+	From    time.Time             `json:"from"`
+	To      time.Time             `json:"to"`
+	Periods []PeriodStatsResponse `json:"periods"`
+}
+
+// PeriodStatsResponse are the aggregates of a period, amounts in wei. The proofs are only counted,
+// and the average cost per proof only taken, over the batches whose proofs were indexed.
+type PeriodStatsResponse struct {
+	Start               time.Time            `json:"start"`
+	BatchesCreated      uint64               `json:"batches_created"`
+	BatchesVerified     uint64               `json:"batches_verified"`
+	ProofsVerified      uint64               `json:"proofs_verified"`
+	GasUsed             uint64               `json:"gas_used"`
+	GasCost             string               `json:"gas_cost"`
+	AverageCostPerProof string               `json:"average_cost_per_proof,omitempty"`
+	QuorumSeconds       *QuorumTimesResponse `json:"quorum_seconds,omitempty"`
+}
+
+// QuorumTimesResponse are the seconds the tasks of a period took to be responded
+type QuorumTimesResponse struct {
+	Average float64 `json:"average"`
+	Median  float64 `json:"median"`
+	P95     float64 `json:"p95"`
+	Max     float64 `json:"max"`
+}
+
+// AggregatorSpendResponse is the body of the /analytics/aggregators endpoint
+type AggregatorSpendResponse struct {
+	From        time.Time              `json:"from"`
+	To          time.Time              `json:"to"`
+	Aggregators []AggregatorSpendEntry `json:"aggregators"`
+}
+
+// AggregatorSpendEntry is the spend of an aggregator, in wei
+type AggregatorSpendEntry struct {
+	AggregatorAddress string `json:"aggregator_address"`
+	Responses         uint64 `json:"responses"`
+	GasUsed           uint64 `json:"gas_used"`
+	GasCost           string `json:"gas_cost"`
+}
+
+// ParticipationListResponse is the body of the /operators/participation endpoint, the participation
+// of the operators of the directory in the responses of the window
+type ParticipationListResponse struct {
+	From      time.Time                    `json:"from"`
+	To        time.Time                    `json:"to"`
+	Operators []OperatorParticipationEntry `json:"operators"`
+}
+
+// OperatorParticipationResponse is the body of the /operators/{id}/participation endpoint, with
+// the latest batches the operator missed
+type OperatorParticipationResponse struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	OperatorParticipationEntry
+	MissedBatches []MissedBatchEntry `json:"missed_batches"`
+}
+
+// OperatorParticipationEntry is how an operator took part in the responses of a window. The
+// responses are the ones to the tasks created while it was registered, and the latency is the one
+// of the tasks it signed, omitted if none.
+type OperatorParticipationEntry struct {
+	OperatorId            string   `json:"operator_id"`
+	OperatorAddress       string   `json:"operator_address"`
+	Responses             uint64   `json:"responses"`
+	Signed                uint64   `json:"signed"`
+	Missed                uint64   `json:"missed"`
+	SigningRate           float64  `json:"signing_rate"`
+	AverageLatencySeconds *float64 `json:"average_latency_seconds,omitempty"`
+}
+
+// MissedBatchEntry is a batch whose response an operator didn't sign
+type MissedBatchEntry struct {
+	BatchMerkleRoot string    `json:"batch_merkle_root"`
+	SenderAddress   string    `json:"sender_address"`
+	BlockNumber     uint64    `json:"block_number"`
+	RespondedAt     time.Time `json:"responded_at"`
+}
+
+// SubscriptionRequest is the body of the POST /subscriptions endpoint. One of commitment and
+// proof_generator_addr must be set.
+type SubscriptionRequest struct {
+	Url                string `json:"url"`
+	Commitment         string `json:"commitment,omitempty"`
+	ProofGeneratorAddr string `json:"proof_generator_addr,omitempty"`
+}
+
+// SubscriptionResponse is the body of the response to a subscription. The secret keys the
+// signatures of its webhooks, and must be sent as bearer token to delete it. It isn't shown again.
+type SubscriptionResponse struct {
+	Id     int64  `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// Handler returns the handler of the API endpoints. The batch endpoints take an optional sender
+// query parameter to pick the batch when more than one sender created a task for the merkle root.
+// The same data can be queried through the GraphQL schema at /graphql.
+func (a *API) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /batches", a.listBatches)
+	mux.HandleFunc("GET /batches/{root}", a.withBatch(func(w http.ResponseWriter, r *http.Request, record *BatchRecord) {
+		a.writeJson(w, newBatchResponse(record))
+	}))
+	mux.HandleFunc("GET /batches/{root}/proofs", a.withBatch(a.batchProofs))
+	mux.HandleFunc("GET /batches/{root}/latency", a.withBatch(func(w http.ResponseWriter, r *http.Request, record *BatchRecord) {
+		a.writeJson(w, newLatencyResponse(record))
+	}))
+	mux.HandleFunc("GET /batches/{root}/participation", a.withBatch(func(w http.ResponseWriter, r *http.Request, record *BatchRecord) {
+		a.writeJson(w, newParticipationResponse(record))
+	}))
+	mux.HandleFunc("GET /proofs", a.listProofs)
+	mux.HandleFunc("GET /operators", a.listOperators)
+	mux.HandleFunc("GET /operators/{id}", a.getOperator)
+	mux.HandleFunc("GET /operators/participation", a.listParticipation)
+	mux.HandleFunc("GET /operators/{id}/participation", a.getParticipation)
+	mux.HandleFunc("GET /analytics/daily", a.periodAnalytics(Day))
+	mux.HandleFunc("GET /analytics/weekly", a.periodAnalytics(Week))
+	mux.HandleFunc("GET /analytics/aggregators", a.aggregatorAnalytics)
+	mux.HandleFunc("GET /export/{dataset}", a.export)
+	mux.Handle("POST /graphql", a.graphqlHandler())
+	if a.subscriptions != nil {
+		mux.HandleFunc("POST /subscriptions", a.createSubscription)
+		mux.HandleFunc("DELETE /subscriptions/{id}", a.deleteSubscription)
+	}
+	return mux
+}
+
+// Serve listens on the address until the context is cancelled
+func (a *API) Serve(ctx context.Context, address string) error {
+	server := &http.Server{
+		Addr:              address,
+		Handler:           a.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	a.logger.Info("Serving indexer API", "address", address)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (a *API) listBatches(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseBatchFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	records, err := a.store.ListBatches(r.Context(), filter)
+	if err != nil {
+		a.logger.Warn("Could not list batches", "err", err)
+		http.Error(w, "could not list batches", http.StatusInternalServerError)
+		return
+	}
+
+	response := BatchListResponse{Batches: make([]BatchResponse, 0, len(records))}
+	for i := range records {
+		response.Batches = append(response.Batches, newBatchResponse(&records[i]))
+	}
+	if len(records) == filter.Limit {
+		nextOffset := filter.Offset + filter.Limit
+		response.NextOffset = &nextOffset
+	}
+	a.writeJson(w, response)
+}
+
+func (a *API) listOperators(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseOperatorFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	operators, err := a.store.ListOperators(r.Context(), filter)
+	if err != nil {
+		a.logger.Warn("Could not list operators", "err", err)
+		http.Error(w, "could not list operators", http.StatusInternalServerError)
+		return
+	}
+
+	response := OperatorListResponse{Operators: make([]OperatorResponse, 0, len(operators))}
+	for i := range operators {
+		response.Operators = append(response.Operators, newOperatorResponse(&operators[i]))
+	}
+	if len(operators) == filter.Limit {
+		nextOffset := filter.Offset + filter.Limit
+		response.NextOffset = &nextOffset
+	}
+	a.writeJson(w, response)
+}
+
+func (a *API) getOperator(w http.ResponseWriter, r *http.Request) {
+	operatorId, err := parseHash(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid operator id: %v", err), http.StatusBadRequest)
+		return
+	}
+	operator, err := a.store.OperatorById(r.Context(), operatorId)
+	if err != nil {
+		a.logger.Warn("Could not get operator", "id", hexBytes(operatorId[:]), "err", err)
+		http.Error(w, "could not get operator", http.StatusInternalServerError)
+		return
+	}
+	if operator == nil {
+		http.Error(w, "operator not found", http.StatusNotFound)
+		return
+	}
+	a.writeJson(w, newOperatorResponse(operator))
+}
+
+func (a *API) listParticipation(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseWindow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	participation, err := a.store.OperatorParticipation(r.Context(), from, to, nil)
+	if err != nil {
+		a.logger.Warn("Could not get operator participation", "err", err)
+		http.Error(w, "could not get operator participation", http.StatusInternalServerError)
+		return
+	}
+
+	response := ParticipationListResponse{From: from, To: to, Operators: make([]OperatorParticipationEntry, 0, len(participation))}
+	for i := range participation {
+		response.Operators = append(response.Operators, newOperatorParticipationEntry(&participation[i]))
+	}
+	a.writeJson(w, response)
+}
+
+func (a *API) getParticipation(w http.ResponseWriter, r *http.Request) {
+	operatorId, err := parseHash(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid operator id: %v", err), http.StatusBadRequest)
+		return
+	}
+	from, to, err := parseWindow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, _, err := parsePage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id := common.Hash(operatorId)
+	participation, err := a.store.OperatorParticipation(r.Context(), from, to, &id)
+	if err != nil {
+		a.logger.Warn("Could not get operator participation", "id", id.Hex(), "err", err)
+		http.Error(w, "could not get operator participation", http.StatusInternalServerError)
+		return
+	}
+	var entry OperatorParticipationEntry
+	if len(participation) == 0 {
+		// the operator wasn't expected to sign any response of the window
+		operator, err := a.store.OperatorById(r.Context(), id)
+		if err != nil {
+			a.logger.Warn("Could not get operator", "id", id.Hex(), "err", err)
+			http.Error(w, "could not get operator", http.StatusInternalServerError)
+			return
+		}
+		if operator == nil {
+			http.Error(w, "operator not found", http.StatusNotFound)
+			return
+		}
+		entry = newOperatorParticipationEntry(&OperatorParticipation{OperatorId: id, OperatorAddress: operator.Address})
+	} else {
+		entry = newOperatorParticipationEntry(&participation[0])
+	}
+
+	missed, err := a.store.MissedBatches(r.Context(), id, from, to, limit)
+	if err != nil {
+		a.logger.Warn("Could not get missed batches", "id", id.Hex(), "err", err)
+		http.Error(w, "could not get missed batches", http.StatusInternalServerError)
+		return
+	}
+	response := OperatorParticipationResponse{From: from, To: to, OperatorParticipationEntry: entry, MissedBatches: make([]MissedBatchEntry, 0, len(missed))}
+	for _, batch := range missed {
+		response.MissedBatches = append(response.MissedBatches, MissedBatchEntry{
+			BatchMerkleRoot: hexBytes(batch.BatchMerkleRoot[:]),
+			SenderAddress:   batch.SenderAddress.Hex(),
+			BlockNumber:     batch.BlockNumber,
+			RespondedAt:     batch.RespondedAt,
+		})
+	}
+	a.writeJson(w, response)
+}
+
+func (a *API) periodAnalytics(period AnalyticsPeriod) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseAnalyticsFilter(r, period)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		stats, err := a.store.PeriodStats(r.Context(), filter)
+		if err != nil {
+			a.logger.Warn("Could not get analytics", "period", period, "err", err)
+			http.Error(w, "could not get analytics", http.StatusInternalServerError)
+			return
+		}
+
+		response := AnalyticsResponse{Period: period, From: filter.From, To: filter.To, Periods: []PeriodStatsResponse{}}
+		for _, periodStats := range fillPeriods(filter, stats) {
+			response.Periods = append(response.Periods, newPeriodStatsResponse(&periodStats))
+		}
+		a.writeJson(w, response)
+	}
+}
+
+func (a *API) aggregatorAnalytics(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAnalyticsFilter(r, Day)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	spend, err := a.store.AggregatorSpend(r.Context(), filter.From, filter.To)
+	if err != nil {
+		a.logger.Warn("Could not get aggregator spend", "err", err)
+		http.Error(w, "could not get aggregator spend", http.StatusInternalServerError)
+		return
+	}
+
+	response := AggregatorSpendResponse{From: filter.From, To: filter.To, Aggregators: make([]AggregatorSpendEntry, 0, len(spend))}
+	for _, aggregator := range spend {
+		response.Aggregators = append(response.Aggregators, AggregatorSpendEntry{
+			AggregatorAddress: aggregator.AggregatorAddress.Hex(),
+			Responses:         aggregator.Responses,
+			GasUsed:           aggregator.GasUsed,
+			GasCost:           aggregator.GasCost.String(),
+		})
+	}
+	a.writeJson(w, response)
+}
+
+// export streams the rows of the dataset of the range, of at most MaxAnalyticsPeriods days, in the
+// format of the format query parameter, csv by default
+func (a *API) export(w http.ResponseWriter, r *http.Request) {
+	dataset, err := ParseExportDataset(r.PathValue("dataset"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	format := CSV
+	if value := r.URL.Query().Get("format"); value != "" {
+		if format, err = ParseExportFormat(value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	filter, err := parseAnalyticsFilter(r, Day)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contentType := "text/csv"
+	if format == Parquet {
+		contentType = "application/vnd.apache.parquet"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s-%s.%s"`,
+		dataset, filter.From.Format(time.DateOnly), filter.To.Format(time.DateOnly), format))
+	// the status is already sent once the rows are streamed, so the errors can only be logged
+	if err := Export(r.Context(), a.store, w, dataset, format, filter.From, filter.To); err != nil {
+		a.logger.Warn("Could not export rows", "dataset", dataset, "err", err)
+	}
+}
+
+func (a *API) createSubscription(w http.ResponseWriter, r *http.Request) {
+	var request SubscriptionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("invalid subscription: %v", err), http.StatusBadRequest)
+		return
+	}
+	subscription, err := newSubscription(request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if subscription.Secret, err = newSubscriptionSecret(); err != nil {
+		http.Error(w, "could not create subscription", http.StatusInternalServerError)
+		return
+	}
+	id, err := a.subscriptions.CreateSubscription(r.Context(), subscription)
+	if err != nil {
+		a.logger.Warn("Could not create subscription", "err", err)
+		http.Error(w, "could not create subscription", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(SubscriptionResponse{Id: id, Secret: subscription.Secret}); err != nil {
+		a.logger.Warn("Could not write API response", "err", err)
+	}
+}
+
+func (a *API) deleteSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid subscription id", http.StatusBadRequest)
+		return
+	}
+	secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || secret == "" {
+		http.Error(w, "missing subscription secret", http.StatusUnauthorized)
+		return
+	}
+	deleted, err := a.subscriptions.DeleteSubscription(r.Context(), id, secret)
+	if err != nil {
+		a.logger.Warn("Could not delete subscription", "id", id, "err", err)
+		http.Error(w, "could not delete subscription", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "subscription not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// withBatch looks up the batch of the root in the path and the sender in the query for the handler
+func (a *API) withBatch(handler func(w http.ResponseWriter, r *http.Request, record *BatchRecord)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		root, err := parseHash(r.PathValue("root"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid batch merkle root: %v", err), http.StatusBadRequest)
+			return
+		}
+		sender, err := parseAddressParam(r, "sender")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		record, err := a.store.BatchByRoot(r.Context(), root, sender)
+		if err != nil {
+			a.logger.Warn("Could not get batch", "root", hex.EncodeToString(root[:]), "err", err)
+			http.Error(w, "could not get batch", http.StatusInternalServerError)
+			return
+		}
+		if record == nil {
+			http.Error(w, "batch not found", http.StatusNotFound)
+			return
+		}
+		handler(w, r, record)
+	}
+}
+
+func (a *API) batchProofs(w http.ResponseWriter, r *http.Request, record *BatchRecord) {
+	proofs, err := a.proofsOfBatch(r.Context(), record)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	response := BatchProofsResponse{
+		BatchMerkleRoot: hexBytes(record.BatchMerkleRoot[:]),
+		SenderAddress:   record.SenderAddress.Hex(),
+		Proofs:          make([]ProofEntry, 0, len(proofs)),
+	}
+	for i := range proofs {
+		entry := newProofEntry(&proofs[i])
+		entry.BatchMerkleRoot, entry.SenderAddress = "", ""
+		response.Proofs = append(response.Proofs, entry)
+	}
+	a.writeJson(w, response)
+}
+
+// proofsOfBatch returns the indexed proofs of the batch, or the leaves of its merkle tree from the
+// data service if its payload wasn't indexed
+func (a *API) proofsOfBatch(ctx context.Context, record *BatchRecord) ([]Proof, error) {
+	proofs, indexed, err := a.store.BatchProofs(ctx, record.BatchMerkleRoot, record.SenderAddress)
+	if err != nil {
+		a.logger.Warn("Could not get batch proofs", "root", hexBytes(record.BatchMerkleRoot[:]), "err", err)
+	}
+	if err == nil && indexed {
+		return proofs, nil
+	}
+
+	leaves, err := a.leaves(ctx, record.BatchDataPointer, record.BatchMerkleRoot)
+	if err != nil {
+		a.logger.Warn("Could not get batch leaves", "root", hexBytes(record.BatchMerkleRoot[:]), "err", err)
+		return nil, errors.New("could not get the proofs of the batch")
+	}
+	proofs = make([]Proof, 0, len(leaves))
+	for i, leaf := range leaves {
+		proofs = append(proofs, Proof{BatchMerkleRoot: record.BatchMerkleRoot, SenderAddress: record.SenderAddress, IndexInBatch: uint64(i), Commitment: leaf})
+	}
+	return proofs, nil
+}
+
+func (a *API) listProofs(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseProofFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	proofs, err := a.store.ListProofs(r.Context(), filter)
+	if err != nil {
+		a.logger.Warn("Could not list proofs", "err", err)
+		http.Error(w, "could not list proofs", http.StatusInternalServerError)
+		return
+	}
+
+	response := ProofListResponse{Proofs: make([]ProofEntry, 0, len(proofs))}
+	for i := range proofs {
+		response.Proofs = append(response.Proofs, newProofEntry(&proofs[i]))
+	}
+	if len(proofs) == filter.Limit {
+		nextOffset := filter.Offset + filter.Limit
+		response.NextOffset = &nextOffset
+	}
+	a.writeJson(w, response)
+}
+
+func (a *API) writeJson(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		a.logger.Warn("Could not write API response", "err", err)
+	}
+}
+
+func newBatchResponse(record *BatchRecord) BatchResponse {
+	response := BatchResponse{
+		BatchMerkleRoot:       hexBytes(record.BatchMerkleRoot[:]),
+		SenderAddress:         record.SenderAddress.Hex(),
+		BatchDataPointer:      record.BatchDataPointer,
+		RespondToTaskFeeLimit: record.RespondToTaskFeeLimit.String(),
+		TaskCreatedBlock:      record.TaskCreatedBlock,
+		TaskCreatedTx:         record.TaskCreatedTx.Hex(),
+		TaskCreatedAt:         record.TaskCreatedAt,
+	}
+	if record.ChainHead != nil {
+		confirmations := record.ChainHead.Confirmations(record.TaskCreatedBlock)
+		response.Confirmations = &confirmations
+		response.Finalized = record.ChainHead.Finalized(record.TaskCreatedBlock)
+	}
+	if record.Response != nil {
+		response.Response = &TaskResponseEntry{
+			BlockNumber:       record.Response.BlockNumber,
+			TxHash:            record.Response.TxHash.Hex(),
+			AggregatorAddress: record.Response.AggregatorAddress.Hex(),
+			GasUsed:           record.Response.GasUsed,
+			EffectiveGasPrice: record.Response.EffectiveGasPrice.String(),
+			RespondedAt:       record.Response.RespondedAt,
+		}
+		if record.ChainHead != nil {
+			confirmations := record.ChainHead.Confirmations(record.Response.BlockNumber)
+			response.Response.Confirmations = &confirmations
+			response.Response.Finalized = record.ChainHead.Finalized(record.Response.BlockNumber)
+		}
+	}
+	return response
+}
+
+func newLatencyResponse(record *BatchRecord) LatencyResponse {
+	response := LatencyResponse{
+		BatchMerkleRoot: hexBytes(record.BatchMerkleRoot[:]),
+		SenderAddress:   record.SenderAddress.Hex(),
+		Responded:       record.Response != nil,
+	}
+	if record.Response != nil {
+		blocks := record.Response.BlockNumber - record.TaskCreatedBlock
+		seconds := record.Response.RespondedAt.Sub(record.TaskCreatedAt).Seconds()
+		response.Blocks, response.Seconds = &blocks, &seconds
+	}
+	return response
+}
+
+func newOperatorParticipationEntry(participation *OperatorParticipation) OperatorParticipationEntry {
+	return OperatorParticipationEntry{
+		OperatorId:            participation.OperatorId.Hex(),
+		OperatorAddress:       participation.OperatorAddress.Hex(),
+		Responses:             participation.Responses,
+		Signed:                participation.Responses - participation.Missed,
+		Missed:                participation.Missed,
+		SigningRate:           participation.SigningRate(),
+		AverageLatencySeconds: participation.AverageLatency,
+	}
+}
+
+func newPeriodStatsResponse(stats *PeriodStats) PeriodStatsResponse {
+	response := PeriodStatsResponse{
+		Start:           stats.Start,
+		BatchesCreated:  stats.BatchesCreated,
+		BatchesVerified: stats.BatchesVerified,
+		ProofsVerified:  stats.ProofsVerified,
+		GasUsed:         stats.GasUsed,
+		GasCost:         stats.GasCost.String(),
+	}
+	if costPerProof := stats.AverageCostPerProof(); costPerProof != nil {
+		response.AverageCostPerProof = costPerProof.String()
+	}
+	if stats.QuorumTimes != nil {
+		response.QuorumSeconds = &QuorumTimesResponse{
+			Average: stats.QuorumTimes.Average,
+			Median:  stats.QuorumTimes.Median,
+			P95:     stats.QuorumTimes.P95,
+			Max:     stats.QuorumTimes.Max,
+		}
+	}
+	return response
+}
+
+func newParticipationResponse(record *BatchRecord) ParticipationResponse {
+	response := ParticipationResponse{
+		BatchMerkleRoot: hexBytes(record.BatchMerkleRoot[:]),
+		SenderAddress:   record.SenderAddress.Hex(),
+		Responded:       record.Response != nil,
+		NonSigners:      []string{},
+	}
+	if record.Response != nil {
+		response.AggregatorAddress = record.Response.AggregatorAddress.Hex()
+		for _, operatorId := range record.Response.NonSigners {
+			response.NonSigners = append(response.NonSigners, operatorId.Hex())
+		}
+	}
+	return response
+}
+
+func newProofEntry(proof *Proof) ProofEntry {
+	entry := ProofEntry{
+		BatchMerkleRoot: hexBytes(proof.BatchMerkleRoot[:]),
+		SenderAddress:   proof.SenderAddress.Hex(),
+		Index:           proof.IndexInBatch,
+		Commitment:      hexBytes(proof.Commitment[:]),
+		ProvingSystem:   proof.ProvingSystem,
+	}
+	if proof.ProvingSystem != "" {
+		entry.ProofGeneratorAddr = proof.ProofGeneratorAddr.Hex()
+	}
+	return entry
+}
+
+func newOperatorResponse(operator *Operator) OperatorResponse {
+	response := OperatorResponse{
+		Id:                operator.Id.Hex(),
+		Address:           operator.Address.Hex(),
+		Registered:        operator.Registered,
+		RegistrationBlock: operator.RegistrationBlock,
+		Socket:            operator.Socket,
+		Stakes:            make([]QuorumStakeEntry, 0, len(operator.Stakes)),
+	}
+	if operator.PubkeyG1 != nil {
+		response.PubkeyG1 = hexBytes(operator.PubkeyG1)
+	}
+	for _, stake := range operator.Stakes {
+		response.Stakes = append(response.Stakes, QuorumStakeEntry{QuorumNumber: stake.QuorumNumber, Stake: stake.Stake.String()})
+	}
+	return response
+}
+
+// parseBatchFilter reads the filter of the /batches endpoint from the query parameters sender,
+// from_block, to_block, status (pending or responded), limit and offset
+func parseBatchFilter(r *http.Request) (BatchFilter, error) {
+	query := r.URL.Query()
+	var filter BatchFilter
+	var err error
+	if filter.SenderAddress, err = parseAddressParam(r, "sender"); err != nil {
+		return BatchFilter{}, err
+	}
+	if value := query.Get("from_block"); value != "" {
+		if filter.FromBlock, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return BatchFilter{}, fmt.Errorf("invalid from_block: %w", err)
+		}
+	}
+	if value := query.Get("to_block"); value != "" {
+		toBlock, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return BatchFilter{}, fmt.Errorf("invalid to_block: %w", err)
+		}
+		filter.ToBlock = &toBlock
+	}
+	switch status := query.Get("status"); status {
+	case "":
+	case "pending", "responded":
+		responded := status == "responded"
+		filter.Responded = &responded
+	default:
+		return BatchFilter{}, fmt.Errorf("invalid status %q, expected pending or responded", status)
+	}
+	if value := query.Get("finalized"); value != "" {
+		finalized, err := strconv.ParseBool(value)
+		if err != nil {
+			return BatchFilter{}, fmt.Errorf("invalid finalized %q, expected true or false", value)
+		}
+		filter.Finalized = &finalized
+	}
+	if filter.Limit, filter.Offset, err = parsePage(r); err != nil {
+		return BatchFilter{}, err
+	}
+	return filter, nil
+}
+
+func newSubscription(request SubscriptionRequest) (Subscription, error) {
+	if !validWebhookUrl(request.Url) {
+		return Subscription{}, errors.New("invalid webhook url, expected an http or https url")
+	}
+	if (request.Commitment == "") == (request.ProofGeneratorAddr == "") {
+		return Subscription{}, errors.New("expected either a commitment or a proof generator address")
+	}
+	subscription := Subscription{Url: request.Url}
+	if request.Commitment != "" {
+		commitment, err := parseHash(request.Commitment)
+		if err != nil {
+			return Subscription{}, fmt.Errorf("invalid commitment: %w", err)
+		}
+		subscription.Commitment = &commitment
+		return subscription, nil
+	}
+	var err error
+	subscription.ProofGeneratorAddr, err = parseAddress(&request.ProofGeneratorAddr)
+	return subscription, err
+}
+
+// parseProofFilter reads the filter of the /proofs endpoint from the query parameters commitment,
+// generator (the proof generator address), limit and offset
+func parseProofFilter(r *http.Request) (ProofFilter, error) {
+	var filter ProofFilter
+	if value := r.URL.Query().Get("commitment"); value != "" {
+		commitment, err := parseHash(value)
+		if err != nil {
+			return ProofFilter{}, fmt.Errorf("invalid commitment: %w", err)
+		}
+		filter.Commitment = &commitment
+	}
+	var err error
+	if filter.ProofGeneratorAddr, err = parseAddressParam(r, "generator"); err != nil {
+		return ProofFilter{}, err
+	}
+	if filter.Limit, filter.Offset, err = parsePage(r); err != nil {
+		return ProofFilter{}, err
+	}
+	return filter, nil
+}
+
+// parseAnalyticsFilter reads the range of the analytics endpoints from the query parameters from
+// and to, as dates or RFC 3339 times. The range defaults to the last defaultAnalyticsPeriods periods.
+func parseAnalyticsFilter(r *http.Request, period AnalyticsPeriod) (AnalyticsFilter, error) {
+	query := r.URL.Query()
+	filter := AnalyticsFilter{Period: period, To: time.Now().UTC()}
+	var err error
+	if value := query.Get("to"); value != "" {
+		if filter.To, err = parseTime(value); err != nil {
+			return AnalyticsFilter{}, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	filter.From = period.periodStart(filter.To).AddDate(0, 0, -(defaultAnalyticsPeriods-1)*period.days())
+	if value := query.Get("from"); value != "" {
+		if filter.From, err = parseTime(value); err != nil {
+			return AnalyticsFilter{}, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if !filter.From.Before(filter.To) {
+		return AnalyticsFilter{}, errors.New("invalid range, expected from before to")
+	}
+	periods := 0
+	for start := period.periodStart(filter.From); start.Before(filter.To); start = period.next(start) {
+		if periods++; periods > MaxAnalyticsPeriods {
+			return AnalyticsFilter{}, fmt.Errorf("invalid range, expected at most %d periods", MaxAnalyticsPeriods)
+		}
+	}
+	return filter, nil
+}
+
+// parseWindow reads the range of the participation endpoints from the query parameter window, a
+// duration with an optional d suffix for days, up to MaxAnalyticsPeriods days, ending now
+func parseWindow(r *http.Request) (time.Time, time.Time, error) {
+	window := DefaultParticipationWindow
+	if value := r.URL.Query().Get("window"); value != "" {
+		var err error
+		if days, ok := strings.CutSuffix(value, "d"); ok {
+			var count int
+			count, err = strconv.Atoi(days)
+			window = time.Duration(count) * 24 * time.Hour
+		} else {
+			window, err = time.ParseDuration(value)
+		}
+		if err != nil || window <= 0 || window > MaxAnalyticsPeriods*24*time.Hour {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid window %q, expected a duration like 24h or 7d of up to %d days", value, MaxAnalyticsPeriods)
+		}
+	}
+	to := time.Now().UTC()
+	return to.Add(-window), to, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("expected a date or an RFC 3339 time")
+	}
+	return t.UTC(), nil
+}
+
+// parseOperatorFilter reads the filter of the /operators endpoint from the query parameters status
+// (registered or deregistered), limit and offset
+func parseOperatorFilter(r *http.Request) (OperatorFilter, error) {
+	query := r.URL.Query()
+	var filter OperatorFilter
+	switch status := query.Get("status"); status {
+	case "":
+	case "registered", "deregistered":
+		registered := status == "registered"
+		filter.Registered = &registered
+	default:
+		return OperatorFilter{}, fmt.Errorf("invalid status %q, expected registered or deregistered", status)
+	}
+	var err error
+	if filter.Limit, filter.Offset, err = parsePage(r); err != nil {
+		return OperatorFilter{}, err
+	}
+	return filter, nil
+}
+
+// parsePage reads the limit and offset query parameters
+func parsePage(r *http.Request) (int, int, error) {
+	query := r.URL.Query()
+	limit, offset := DefaultPageSize, 0
+	var err error
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 || limit > MaxPageSize {
+			return 0, 0, fmt.Errorf("invalid limit, expected 1 to %d", MaxPageSize)
+		}
+	}
+	if value := query.Get("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			return 0, 0, errors.New("invalid offset")
+		}
+	}
+	return limit, offset, nil
+}
+
+func parseAddressParam(r *http.Request, name string) (*common.Address, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	return parseAddress(&value)
+}
+
+// parseAddress parses an optional address, returning nil if not given
+func parseAddress(value *string) (*common.Address, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if !common.IsHexAddress(*value) {
+		return nil, fmt.Errorf("invalid address %q", *value)
+	}
+	address := common.HexToAddress(*value)
+	return &address, nil
+}
+
+func parseHash(value string) ([32]byte, error) {
+	var hash [32]byte
+	decoded, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return hash, err
+	}
+	if len(decoded) != len(hash) {
+		return hash, fmt.Errorf("expected 32 bytes, got %d", len(decoded))
+	}
+	copy(hash[:], decoded)
+	return hash, nil
+}
+
+func hexBytes(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
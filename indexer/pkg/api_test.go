@@ -0,0 +1,402 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type fakeQueryStore struct {
+	records    []BatchRecord
+	operators  []Operator
+	proofs     []Proof
+	stats      []PeriodStats
+	spend      []AggregatorSpend
+	exported   []BatchExportRow
+	signing    []OperatorParticipation
+	missed     []MissedBatch
+	lastFilter BatchFilter
+	lastRange  AnalyticsFilter
+}
+
+func (s *fakeQueryStore) ListBatches(ctx context.Context, filter BatchFilter) ([]BatchRecord, error) {
+	s.lastFilter = filter
+	end := min(filter.Offset+filter.Limit, len(s.records))
+	if filter.Offset >= end {
+		return nil, nil
+	}
+	return s.records[filter.Offset:end], nil
+}
+
+func (s *fakeQueryStore) BatchByRoot(ctx context.Context, batchMerkleRoot [32]byte, senderAddress *common.Address) (*BatchRecord, error) {
+	for i := range s.records {
+		if s.records[i].BatchMerkleRoot == batchMerkleRoot && (senderAddress == nil || s.records[i].SenderAddress == *senderAddress) {
+			return &s.records[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fakeQueryStore) NonSigners(ctx context.Context, batchMerkleRoot [32]byte, senderAddress common.Address) ([]common.Hash, error) {
+	record, _ := s.BatchByRoot(ctx, batchMerkleRoot, &senderAddress)
+	if record == nil || record.Response == nil {
+		return nil, nil
+	}
+	return record.Response.NonSigners, nil
+}
+
+func (s *fakeQueryStore) ListOperators(ctx context.Context, filter OperatorFilter) ([]Operator, error) {
+	var operators []Operator
+	for _, operator := range s.operators {
+		if filter.Registered == nil || operator.Registered == *filter.Registered {
+			operators = append(operators, operator)
+		}
+	}
+	return operators[min(filter.Offset, len(operators)):min(filter.Offset+filter.Limit, len(operators))], nil
+}
+
+func (s *fakeQueryStore) OperatorById(ctx context.Context, operatorId common.Hash) (*Operator, error) {
+	for i := range s.operators {
+		if s.operators[i].Id == operatorId {
+			return &s.operators[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fakeQueryStore) ListProofs(ctx context.Context, filter ProofFilter) ([]Proof, error) {
+	var proofs []Proof
+	for _, proof := range s.proofs {
+		if (filter.Commitment == nil || proof.Commitment == *filter.Commitment) && (filter.ProofGeneratorAddr == nil || proof.ProofGeneratorAddr == *filter.ProofGeneratorAddr) {
+			proofs = append(proofs, proof)
+		}
+	}
+	return proofs[min(filter.Offset, len(proofs)):min(filter.Offset+filter.Limit, len(proofs))], nil
+}
+
+func (s *fakeQueryStore) BatchProofs(ctx context.Context, batchMerkleRoot [32]byte, senderAddress common.Address) ([]Proof, bool, error) {
+	var proofs []Proof
+	for _, proof := range s.proofs {
+		if proof.BatchMerkleRoot == batchMerkleRoot && proof.SenderAddress == senderAddress {
+			proofs = append(proofs, proof)
+		}
+	}
+	return proofs, len(proofs) > 0, nil
+}
+
+func (s *fakeQueryStore) PeriodStats(ctx context.Context, filter AnalyticsFilter) ([]PeriodStats, error) {
+	s.lastRange = filter
+	return s.stats, nil
+}
+
+func (s *fakeQueryStore) AggregatorSpend(ctx context.Context, from time.Time, to time.Time) ([]AggregatorSpend, error) {
+	s.lastRange = AnalyticsFilter{From: from, To: to}
+	return s.spend, nil
+}
+
+func (s *fakeQueryStore) ExportBatches(ctx context.Context, from time.Time, to time.Time, emit func(BatchExportRow) error) error {
+	s.lastRange = AnalyticsFilter{From: from, To: to}
+	for _, row := range s.exported {
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeQueryStore) ExportProofs(ctx context.Context, from time.Time, to time.Time, emit func(ProofExportRow) error) error {
+	return nil
+}
+
+func (s *fakeQueryStore) ExportParticipation(ctx context.Context, from time.Time, to time.Time, emit func(ParticipationExportRow) error) error {
+	return nil
+}
+
+func (s *fakeQueryStore) OperatorParticipation(ctx context.Context, from time.Time, to time.Time, operatorId *common.Hash) ([]OperatorParticipation, error) {
+	s.lastRange = AnalyticsFilter{From: from, To: to}
+	var participation []OperatorParticipation
+	for _, operator := range s.signing {
+		if operatorId == nil || operator.OperatorId == *operatorId {
+			participation = append(participation, operator)
+		}
+	}
+	return participation, nil
+}
+
+func (s *fakeQueryStore) MissedBatches(ctx context.Context, operatorId common.Hash, from time.Time, to time.Time, limit int) ([]MissedBatch, error) {
+	if len(s.signing) == 0 || s.signing[0].OperatorId != operatorId {
+		return nil, nil
+	}
+	return s.missed[:min(limit, len(s.missed))], nil
+}
+
+func newTestAPI(t *testing.T, store QueryStore, leaves LeavesSource) *httptest.Server {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %v", err)
+	}
+	server := httptest.NewServer(newAPI(logger, store, nil, leaves).Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func getJson(t *testing.T, url string, expectedStatus int, body interface{}) {
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("could not get %s: %v", url, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != expectedStatus {
+		t.Fatalf("expected status %d for %s, got %d", expectedStatus, url, response.StatusCode)
+	}
+	if body != nil {
+		if err := json.NewDecoder(response.Body).Decode(body); err != nil {
+			t.Fatalf("could not decode %s: %v", url, err)
+		}
+	}
+}
+
+func testBatchRecords() []BatchRecord {
+	createdAt := time.Unix(1_700_000_000, 0).UTC()
+	responded := BatchRecord{
+		Batch: Batch{BatchMerkleRoot: [32]byte{1}, SenderAddress: common.Address{0xa}, RespondToTaskFeeLimit: big.NewInt(100), TaskCreatedBlock: 10, TaskCreatedAt: createdAt},
+		Response: &Response{
+			BlockNumber:       13,
+			EffectiveGasPrice: big.NewInt(1),
+			RespondedAt:       createdAt.Add(36 * time.Second),
+			NonSigners:        []common.Hash{{0xff}},
+		},
+		ChainHead: &ChainHead{Number: 20, FinalityDepth: 8},
+	}
+	pending := BatchRecord{
+		Batch:     Batch{BatchMerkleRoot: [32]byte{2}, SenderAddress: common.Address{0xa}, RespondToTaskFeeLimit: big.NewInt(100), TaskCreatedBlock: 12, TaskCreatedAt: createdAt},
+		ChainHead: &ChainHead{Number: 20, FinalityDepth: 8},
+	}
+	return []BatchRecord{pending, responded}
+}
+
+func TestAPIListsBatchesInPages(t *testing.T) {
+	store := &fakeQueryStore{records: testBatchRecords()}
+	server := newTestAPI(t, store, nil)
+
+	var page BatchListResponse
+	getJson(t, server.URL+"/batches?limit=1&status=responded&sender="+common.Address{0xa}.Hex(), http.StatusOK, &page)
+	if len(page.Batches) != 1 || page.NextOffset == nil || *page.NextOffset != 1 {
+		t.Fatalf("unexpected first page %+v", page)
+	}
+	if store.lastFilter.Responded == nil || !*store.lastFilter.Responded || store.lastFilter.SenderAddress == nil {
+		t.Errorf("filter not parsed: %+v", store.lastFilter)
+	}
+
+	getJson(t, server.URL+"/batches?limit=1&offset=1&finalized=false", http.StatusOK, &page)
+	if len(page.Batches) != 1 || page.Batches[0].Response == nil || page.Batches[0].Response.BlockNumber != 13 {
+		t.Fatalf("unexpected second page %+v", page)
+	}
+	if store.lastFilter.Finalized == nil || *store.lastFilter.Finalized {
+		t.Errorf("finalized filter not parsed: %+v", store.lastFilter)
+	}
+	batch := page.Batches[0]
+	if batch.Confirmations == nil || *batch.Confirmations != 10 || !batch.Finalized ||
+		batch.Response.Confirmations == nil || *batch.Response.Confirmations != 7 || batch.Response.Finalized {
+		t.Errorf("expected the task creation finalized and the response not, got %+v and %+v", batch, batch.Response)
+	}
+
+	getJson(t, server.URL+"/batches?limit=1000", http.StatusBadRequest, nil)
+	getJson(t, server.URL+"/batches?status=done", http.StatusBadRequest, nil)
+	getJson(t, server.URL+"/batches?finalized=maybe", http.StatusBadRequest, nil)
+}
+
+func TestAPIServesBatchDetails(t *testing.T) {
+	leaves := [][32]byte{{0xc1}, {0xc2}}
+	server := newTestAPI(t, &fakeQueryStore{records: testBatchRecords()}, func(ctx context.Context, batchDataPointer string, batchMerkleRoot [32]byte) ([][32]byte, error) {
+		if batchMerkleRoot != ([32]byte{1}) {
+			return nil, errors.New("unexpected batch")
+		}
+		return leaves, nil
+	})
+	root := hexBytes([]byte{1}) + "00000000000000000000000000000000000000000000000000000000000000"
+
+	var latency LatencyResponse
+	getJson(t, server.URL+"/batches/"+root+"/latency", http.StatusOK, &latency)
+	if !latency.Responded || *latency.Blocks != 3 || *latency.Seconds != 36 {
+		t.Errorf("unexpected latency %+v", latency)
+	}
+
+	var participation ParticipationResponse
+	getJson(t, server.URL+"/batches/"+root+"/participation", http.StatusOK, &participation)
+	if len(participation.NonSigners) != 1 || participation.NonSigners[0] != (common.Hash{0xff}).Hex() {
+		t.Errorf("unexpected participation %+v", participation)
+	}
+
+	var proofs BatchProofsResponse
+	getJson(t, server.URL+"/batches/"+root+"/proofs", http.StatusOK, &proofs)
+	if len(proofs.Proofs) != 2 || proofs.Proofs[1].Commitment != hexBytes(leaves[1][:]) {
+		t.Errorf("unexpected proofs %+v", proofs)
+	}
+
+	var pendingLatency LatencyResponse
+	getJson(t, server.URL+"/batches/0x02"+root[4:]+"/latency", http.StatusOK, &pendingLatency)
+	if pendingLatency.Responded || pendingLatency.Seconds != nil {
+		t.Errorf("unexpected latency of pending batch %+v", pendingLatency)
+	}
+	getJson(t, server.URL+"/batches/0x03"+root[4:], http.StatusNotFound, nil)
+	getJson(t, server.URL+"/batches/0x03", http.StatusBadRequest, nil)
+}
+
+func testOperators() []Operator {
+	return []Operator{
+		{Id: common.Hash{0xff}, Address: common.Address{0xb}, Registered: true, RegistrationBlock: 2, Socket: "localhost:8080", Stakes: []QuorumStake{{QuorumNumber: 0, Stake: big.NewInt(1000)}}},
+		{Id: common.Hash{0xfe}, Address: common.Address{0xc}, RegistrationBlock: 7},
+	}
+}
+
+func TestAPIServesOperators(t *testing.T) {
+	server := newTestAPI(t, &fakeQueryStore{operators: testOperators()}, nil)
+
+	var page OperatorListResponse
+	getJson(t, server.URL+"/operators?status=registered", http.StatusOK, &page)
+	if len(page.Operators) != 1 || page.NextOffset != nil || page.Operators[0].Stakes[0].Stake != "1000" {
+		t.Fatalf("unexpected operators %+v", page)
+	}
+
+	var operator OperatorResponse
+	getJson(t, server.URL+"/operators/"+(common.Hash{0xfe}).Hex(), http.StatusOK, &operator)
+	if operator.Registered || operator.Address != (common.Address{0xc}).Hex() || operator.RegistrationBlock != 7 {
+		t.Errorf("unexpected operator %+v", operator)
+	}
+	getJson(t, server.URL+"/operators/"+(common.Hash{0xfd}).Hex(), http.StatusNotFound, nil)
+	getJson(t, server.URL+"/operators?status=active", http.StatusBadRequest, nil)
+}
+
+func TestAPIServesOperatorParticipation(t *testing.T) {
+	latency := 12.5
+	store := &fakeQueryStore{
+		operators: testOperators(),
+		signing: []OperatorParticipation{
+			{OperatorId: common.Hash{0xfe}, OperatorAddress: common.Address{0xc}, Responses: 4, Missed: 1, AverageLatency: &latency},
+		},
+		missed: []MissedBatch{{BatchMerkleRoot: [32]byte{1}, SenderAddress: common.Address{0xa}, BlockNumber: 13}},
+	}
+	server := newTestAPI(t, store, nil)
+
+	var list ParticipationListResponse
+	getJson(t, server.URL+"/operators/participation?window=30d", http.StatusOK, &list)
+	if len(list.Operators) != 1 || list.Operators[0].Signed != 3 || list.Operators[0].SigningRate != 0.75 ||
+		list.Operators[0].AverageLatencySeconds == nil || *list.Operators[0].AverageLatencySeconds != latency {
+		t.Fatalf("unexpected participation %+v", list)
+	}
+	if window := list.To.Sub(list.From); window != 30*24*time.Hour {
+		t.Errorf("expected a window of 30 days, got %v", window)
+	}
+
+	var operator OperatorParticipationResponse
+	getJson(t, server.URL+"/operators/"+(common.Hash{0xfe}).Hex()+"/participation?window=24h", http.StatusOK, &operator)
+	if operator.Missed != 1 || len(operator.MissedBatches) != 1 || operator.MissedBatches[0].BlockNumber != 13 {
+		t.Errorf("unexpected operator participation %+v", operator)
+	}
+
+	// operators of the directory not expected to sign in the window have no missed batches
+	for _, operator := range store.operators {
+		if operator.Id == (common.Hash{0xfe}) {
+			continue
+		}
+		var idle OperatorParticipationResponse
+		getJson(t, server.URL+"/operators/"+operator.Id.Hex()+"/participation", http.StatusOK, &idle)
+		if idle.Responses != 0 || idle.SigningRate != 1 || idle.AverageLatencySeconds != nil || len(idle.MissedBatches) != 0 {
+			t.Errorf("unexpected idle operator participation %+v", idle)
+		}
+	}
+
+	getJson(t, server.URL+"/operators/"+(common.Hash{0xfd}).Hex()+"/participation", http.StatusNotFound, nil)
+	getJson(t, server.URL+"/operators/participation?window=1y", http.StatusBadRequest, nil)
+	getJson(t, server.URL+"/operators/participation?window=400d", http.StatusBadRequest, nil)
+}
+
+func TestAPISearchesIndexedProofs(t *testing.T) {
+	proofs := []Proof{
+		{BatchMerkleRoot: [32]byte{1}, SenderAddress: common.Address{0xa}, IndexInBatch: 0, Commitment: [32]byte{0xc1}, ProvingSystem: "SP1", ProofGeneratorAddr: common.Address{0xa1}},
+		{BatchMerkleRoot: [32]byte{1}, SenderAddress: common.Address{0xa}, IndexInBatch: 1, Commitment: [32]byte{0xc2}, ProvingSystem: "Risc0", ProofGeneratorAddr: common.Address{0xa2}},
+	}
+	server := newTestAPI(t, &fakeQueryStore{records: testBatchRecords(), proofs: proofs}, func(ctx context.Context, batchDataPointer string, batchMerkleRoot [32]byte) ([][32]byte, error) {
+		return nil, errors.New("leaves not expected for indexed batches")
+	})
+
+	var page ProofListResponse
+	getJson(t, server.URL+"/proofs?generator="+(common.Address{0xa2}).Hex(), http.StatusOK, &page)
+	if len(page.Proofs) != 1 || page.Proofs[0].Index != 1 || page.Proofs[0].ProvingSystem != "Risc0" || page.Proofs[0].BatchMerkleRoot != hexBytes(proofs[0].BatchMerkleRoot[:]) {
+		t.Fatalf("unexpected proofs %+v", page)
+	}
+	getJson(t, server.URL+"/proofs?commitment="+hexBytes(proofs[0].Commitment[:]), http.StatusOK, &page)
+	if len(page.Proofs) != 1 || page.Proofs[0].ProofGeneratorAddr != (common.Address{0xa1}).Hex() {
+		t.Fatalf("unexpected proofs %+v", page)
+	}
+	getJson(t, server.URL+"/proofs?commitment=0x01", http.StatusBadRequest, nil)
+
+	var batchProofs BatchProofsResponse
+	getJson(t, server.URL+"/batches/"+hexBytes(proofs[0].BatchMerkleRoot[:])+"/proofs", http.StatusOK, &batchProofs)
+	if len(batchProofs.Proofs) != 2 || batchProofs.Proofs[1].ProvingSystem != "Risc0" || batchProofs.Proofs[1].BatchMerkleRoot != "" {
+		t.Errorf("unexpected batch proofs %+v", batchProofs)
+	}
+}
+
+func TestAPIAnalytics(t *testing.T) {
+	store := &fakeQueryStore{
+		stats: []PeriodStats{{
+			Start:           time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC),
+			BatchesCreated:  4,
+			BatchesVerified: 3,
+			ProofsVerified:  10,
+			GasUsed:         900000,
+			GasCost:         big.NewInt(9000),
+			IndexedGasCost:  big.NewInt(5000),
+			QuorumTimes:     &QuorumTimes{Average: 12, Median: 10, P95: 20, Max: 24},
+		}},
+		spend: []AggregatorSpend{{AggregatorAddress: common.Address{9}, Responses: 3, GasUsed: 900000, GasCost: big.NewInt(9000)}},
+	}
+	server := newTestAPI(t, store, nil)
+
+	var weekly AnalyticsResponse
+	getJson(t, server.URL+"/analytics/weekly?from=2024-05-29&to=2024-06-17", http.StatusOK, &weekly)
+	if len(weekly.Periods) != 3 {
+		t.Fatalf("expected the 3 weeks of the range, got %d", len(weekly.Periods))
+	}
+	if start := weekly.Periods[0].Start; !start.Equal(time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the first week to start on monday, got %v", start)
+	}
+	empty, active := weekly.Periods[0], weekly.Periods[1]
+	if empty.BatchesVerified != 0 || empty.GasCost != "0" || empty.AverageCostPerProof != "" || empty.QuorumSeconds != nil {
+		t.Errorf("expected an empty week, got %+v", empty)
+	}
+	if active.BatchesCreated != 4 || active.ProofsVerified != 10 || active.GasCost != "9000" ||
+		active.AverageCostPerProof != "500" || active.QuorumSeconds == nil || active.QuorumSeconds.P95 != 20 {
+		t.Errorf("unexpected week %+v", active)
+	}
+	if store.lastRange.Period != Week || !store.lastRange.From.Equal(time.Date(2024, 5, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected range %+v", store.lastRange)
+	}
+
+	var daily AnalyticsResponse
+	getJson(t, server.URL+"/analytics/daily?to=2024-06-17T12:00:00Z", http.StatusOK, &daily)
+	if len(daily.Periods) != defaultAnalyticsPeriods {
+		t.Errorf("expected %d days by default, got %d", defaultAnalyticsPeriods, len(daily.Periods))
+	}
+
+	var aggregators AggregatorSpendResponse
+	getJson(t, server.URL+"/analytics/aggregators?from=2024-06-01&to=2024-07-01", http.StatusOK, &aggregators)
+	if len(aggregators.Aggregators) != 1 || aggregators.Aggregators[0].GasCost != "9000" ||
+		aggregators.Aggregators[0].AggregatorAddress != (common.Address{9}).Hex() {
+		t.Errorf("unexpected aggregators %+v", aggregators)
+	}
+
+	getJson(t, server.URL+"/analytics/daily?from=2024-06-17&to=2024-06-01", http.StatusBadRequest, nil)
+	getJson(t, server.URL+"/analytics/daily?from=2020-01-01&to=2024-06-01", http.StatusBadRequest, nil)
+	getJson(t, server.URL+"/analytics/daily?from=yesterday", http.StatusBadRequest, nil)
+}
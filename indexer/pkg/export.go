@@ -0,0 +1,207 @@
+package pkg
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// ExportFormat is the file format of the exports
+type ExportFormat string
+
+const (
+	CSV     ExportFormat = "csv"
+	Parquet ExportFormat = "parquet"
+)
+
+// ExportDataset is the table of indexed rows exported
+type ExportDataset string
+
+const (
+	// BatchesDataset are the batches created in the range, with their responses
+	BatchesDataset ExportDataset = "batches"
+	// ProofsDataset are the indexed proofs of the batches created in the range
+	ProofsDataset ExportDataset = "proofs"
+	// ParticipationDataset are the operators that didn't sign the responses of the range, one row
+	// per response and operator
+	ParticipationDataset ExportDataset = "participation"
+)
+
+// exportRowGroupSize bounds the memory the parquet writer buffers before flushing a row group
+const exportRowGroupSize = 16 * 1024 * 1024
+
+// BatchExportRow is an exported batch. The response columns are empty while pending.
+type BatchExportRow struct {
+	BatchMerkleRoot       string  `parquet:"name=batch_merkle_root, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SenderAddress         string  `parquet:"name=sender_address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	BatchDataPointer      string  `parquet:"name=batch_data_pointer, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RespondToTaskFeeLimit string  `parquet:"name=respond_to_task_fee_limit, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TaskCreatedBlock      int64   `parquet:"name=task_created_block, type=INT64"`
+	TaskCreatedTx         string  `parquet:"name=task_created_tx, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TaskCreatedAt         int64   `parquet:"name=task_created_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	ResponseBlock         *int64  `parquet:"name=response_block, type=INT64, repetitiontype=OPTIONAL"`
+	ResponseTx            *string `parquet:"name=response_tx, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	AggregatorAddress     *string `parquet:"name=aggregator_address, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	GasUsed               *int64  `parquet:"name=gas_used, type=INT64, repetitiontype=OPTIONAL"`
+	EffectiveGasPrice     *string `parquet:"name=effective_gas_price, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	RespondedAt           *int64  `parquet:"name=responded_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	Finalized             bool    `parquet:"name=finalized, type=BOOLEAN"`
+}
+
+// ProofExportRow is an exported proof, with the times its batch was created and responded
+type ProofExportRow struct {
+	BatchMerkleRoot                string `parquet:"name=batch_merkle_root, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SenderAddress                  string `parquet:"name=sender_address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	IndexInBatch                   int64  `parquet:"name=index_in_batch, type=INT64"`
+	Commitment                     string `parquet:"name=commitment, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ProvingSystem                  string `parquet:"name=proving_system, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ProofGeneratorAddr             string `parquet:"name=proof_generator_addr, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ProofCommitment                string `parquet:"name=proof_commitment, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PubInputCommitment             string `parquet:"name=pub_input_commitment, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ProvingSystemAuxDataCommitment string `parquet:"name=proving_system_aux_data_commitment, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TaskCreatedAt                  int64  `parquet:"name=task_created_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	RespondedAt                    *int64 `parquet:"name=responded_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+}
+
+// ParticipationExportRow is an operator that didn't sign a response. The operator address is
+// empty for the operators that aren't in the directory.
+type ParticipationExportRow struct {
+	BatchMerkleRoot   string  `parquet:"name=batch_merkle_root, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SenderAddress     string  `parquet:"name=sender_address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ResponseBlock     int64   `parquet:"name=response_block, type=INT64"`
+	ResponseTx        string  `parquet:"name=response_tx, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AggregatorAddress string  `parquet:"name=aggregator_address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RespondedAt       int64   `parquet:"name=responded_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	OperatorId        string  `parquet:"name=operator_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	OperatorAddress   *string `parquet:"name=operator_address, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+}
+
+// ExportStore streams the indexed rows of a time range, from included and to excluded, in order.
+// The batches and proofs are selected by the creation of their task, and the participation by the
+// time of the responses.
+type ExportStore interface {
+	ExportBatches(ctx context.Context, from time.Time, to time.Time, emit func(BatchExportRow) error) error
+	ExportProofs(ctx context.Context, from time.Time, to time.Time, emit func(ProofExportRow) error) error
+	ExportParticipation(ctx context.Context, from time.Time, to time.Time, emit func(ParticipationExportRow) error) error
+}
+
+// ParseExportDataset checks the name is one of the exported datasets
+func ParseExportDataset(name string) (ExportDataset, error) {
+	switch dataset := ExportDataset(name); dataset {
+	case BatchesDataset, ProofsDataset, ParticipationDataset:
+		return dataset, nil
+	}
+	return "", fmt.Errorf("invalid dataset %q, expected batches, proofs or participation", name)
+}
+
+// ParseExportFormat checks the name is one of the export formats
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch format := ExportFormat(name); format {
+	case CSV, Parquet:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid format %q, expected csv or parquet", name)
+}
+
+// Export writes the rows of the dataset in the range to the writer, in the format
+func Export(ctx context.Context, store ExportStore, w io.Writer, dataset ExportDataset, format ExportFormat, from time.Time, to time.Time) error {
+	switch dataset {
+	case BatchesDataset:
+		return exportRows(w, format, func(emit func(BatchExportRow) error) error {
+			return store.ExportBatches(ctx, from, to, emit)
+		})
+	case ProofsDataset:
+		return exportRows(w, format, func(emit func(ProofExportRow) error) error {
+			return store.ExportProofs(ctx, from, to, emit)
+		})
+	case ParticipationDataset:
+		return exportRows(w, format, func(emit func(ParticipationExportRow) error) error {
+			return store.ExportParticipation(ctx, from, to, emit)
+		})
+	}
+	return fmt.Errorf("invalid dataset %q", dataset)
+}
+
+func exportRows[T any](w io.Writer, format ExportFormat, rows func(emit func(T) error) error) error {
+	switch format {
+	case CSV:
+		csvWriter := csv.NewWriter(w)
+		if err := csvWriter.Write(csvHeader(reflect.TypeFor[T]())); err != nil {
+			return err
+		}
+		err := rows(func(row T) error {
+			return csvWriter.Write(csvRecord(reflect.ValueOf(row)))
+		})
+		if err != nil {
+			return err
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	case Parquet:
+		parquetWriter, err := writer.NewParquetWriterFromWriter(w, new(T), 1)
+		if err != nil {
+			return err
+		}
+		parquetWriter.RowGroupSize = exportRowGroupSize
+		err = rows(func(row T) error {
+			return parquetWriter.Write(row)
+		})
+		if err != nil {
+			return err
+		}
+		return parquetWriter.WriteStop()
+	}
+	return fmt.Errorf("invalid format %q", format)
+}
+
+// csvHeader returns the column names of the parquet tags of the row fields
+func csvHeader(rowType reflect.Type) []string {
+	header := make([]string, rowType.NumField())
+	for i := range header {
+		header[i] = parquetTag(rowType.Field(i), "name")
+	}
+	return header
+}
+
+// csvRecord formats the fields of the row, the timestamps as RFC 3339 and the missing values empty
+func csvRecord(row reflect.Value) []string {
+	record := make([]string, row.NumField())
+	for i := range record {
+		field := row.Field(i)
+		if field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		switch field.Kind() {
+		case reflect.String:
+			record[i] = field.String()
+		case reflect.Bool:
+			record[i] = strconv.FormatBool(field.Bool())
+		case reflect.Int64:
+			if parquetTag(row.Type().Field(i), "convertedtype") == "TIMESTAMP_MILLIS" {
+				record[i] = time.UnixMilli(field.Int()).UTC().Format(time.RFC3339)
+			} else {
+				record[i] = strconv.FormatInt(field.Int(), 10)
+			}
+		}
+	}
+	return record
+}
+
+func parquetTag(field reflect.StructField, key string) string {
+	for _, option := range strings.Split(field.Tag.Get("parquet"), ",") {
+		if name, value, ok := strings.Cut(strings.TrimSpace(option), "="); ok && name == key {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (s *PostgresStore) ExportBatches(ctx context.Context, from time.Time, to time.Time, emit func(BatchExportRow) error) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+batchRecordColumns+`
+		WHERE b.task_created_at >= $1 AND b.task_created_at < $2
+		ORDER BY b.task_created_block, b.batch_merkle_root, b.sender_address`,
+		from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scanBatchRecord(rows)
+		if err != nil {
+			return err
+		}
+		row := BatchExportRow{
+			BatchMerkleRoot:       hexBytes(record.BatchMerkleRoot[:]),
+			SenderAddress:         record.SenderAddress.Hex(),
+			BatchDataPointer:      record.BatchDataPointer,
+			RespondToTaskFeeLimit: record.RespondToTaskFeeLimit.String(),
+			TaskCreatedBlock:      int64(record.TaskCreatedBlock),
+			TaskCreatedTx:         record.TaskCreatedTx.Hex(),
+			TaskCreatedAt:         record.TaskCreatedAt.UnixMilli(),
+			Finalized:             record.Finalized(),
+		}
+		if response := record.Response; response != nil {
+			responseBlock, gasUsed, respondedAt := int64(response.BlockNumber), int64(response.GasUsed), response.RespondedAt.UnixMilli()
+			responseTx, aggregator, gasPrice := response.TxHash.Hex(), response.AggregatorAddress.Hex(), response.EffectiveGasPrice.String()
+			row.ResponseBlock, row.ResponseTx, row.AggregatorAddress = &responseBlock, &responseTx, &aggregator
+			row.GasUsed, row.EffectiveGasPrice, row.RespondedAt = &gasUsed, &gasPrice, &respondedAt
+		}
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (s *PostgresStore) ExportProofs(ctx context.Context, from time.Time, to time.Time, emit func(ProofExportRow) error) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT p.batch_merkle_root, p.sender_address, p.index_in_batch, p.commitment,
+		p.proving_system, p.proof_generator_addr, p.proof_commitment, p.pub_input_commitment,
+		p.proving_system_aux_data_commitment, b.task_created_at, r.responded_at
+		FROM proofs p JOIN batches b USING (batch_merkle_root, sender_address)
+		LEFT JOIN responses r USING (batch_merkle_root, sender_address)
+		WHERE b.task_created_at >= $1 AND b.task_created_at < $2
+		ORDER BY b.task_created_block, p.batch_merkle_root, p.sender_address, p.index_in_batch`,
+		from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row ProofExportRow
+		var root, sender, commitment, generator, proofCommitment, pubInputCommitment, auxDataCommitment []byte
+		var createdAt time.Time
+		var respondedAt *time.Time
+		if err := rows.Scan(&root, &sender, &row.IndexInBatch, &commitment, &row.ProvingSystem, &generator,
+			&proofCommitment, &pubInputCommitment, &auxDataCommitment, &createdAt, &respondedAt); err != nil {
+			return err
+		}
+		row.BatchMerkleRoot = hexBytes(root)
+		row.SenderAddress = common.BytesToAddress(sender).Hex()
+		row.Commitment = hexBytes(commitment)
+		row.ProofGeneratorAddr = common.BytesToAddress(generator).Hex()
+		row.ProofCommitment = hexBytes(proofCommitment)
+		row.PubInputCommitment = hexBytes(pubInputCommitment)
+		row.ProvingSystemAuxDataCommitment = hexBytes(auxDataCommitment)
+		row.TaskCreatedAt = createdAt.UnixMilli()
+		if respondedAt != nil {
+			respondedAtMilli := respondedAt.UnixMilli()
+			row.RespondedAt = &respondedAtMilli
+		}
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (s *PostgresStore) ExportParticipation(ctx context.Context, from time.Time, to time.Time, emit func(ParticipationExportRow) error) error {
+	rows, err := s.db.QueryContext(ctx, `WITH operator_addresses AS (
+			SELECT DISTINCT ON (operator_id) operator_id, operator_address
+			FROM operator_registrations ORDER BY operator_id, block_number DESC, log_index DESC
+		)
+		SELECT n.batch_merkle_root, n.sender_address, r.block_number, r.tx_hash, r.aggregator_address,
+			r.responded_at, n.operator_id, o.operator_address
+		FROM response_non_signers n JOIN responses r USING (batch_merkle_root, sender_address)
+		LEFT JOIN operator_addresses o USING (operator_id)
+		WHERE r.responded_at >= $1 AND r.responded_at < $2
+		ORDER BY r.block_number, n.batch_merkle_root, n.sender_address, n.operator_id`,
+		from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row ParticipationExportRow
+		var root, sender, txHash, aggregator, operatorId, operatorAddress []byte
+		var respondedAt time.Time
+		if err := rows.Scan(&root, &sender, &row.ResponseBlock, &txHash, &aggregator, &respondedAt, &operatorId, &operatorAddress); err != nil {
+			return err
+		}
+		row.BatchMerkleRoot = hexBytes(root)
+		row.SenderAddress = common.BytesToAddress(sender).Hex()
+		row.ResponseTx = common.BytesToHash(txHash).Hex()
+		row.AggregatorAddress = common.BytesToAddress(aggregator).Hex()
+		row.RespondedAt = respondedAt.UnixMilli()
+		row.OperatorId = common.BytesToHash(operatorId).Hex()
+		if operatorAddress != nil {
+			address := common.BytesToAddress(operatorAddress).Hex()
+			row.OperatorAddress = &address
+		}
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
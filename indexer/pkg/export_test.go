@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func testExportRows() []BatchExportRow {
+	responseBlock, gasUsed, respondedAt := int64(13), int64(21000), time.Date(2024, 6, 3, 12, 0, 36, 0, time.UTC).UnixMilli()
+	responseTx, aggregator, gasPrice := "0x02", "0x03", "1000"
+	return []BatchExportRow{
+		{
+			BatchMerkleRoot:       "0x01",
+			SenderAddress:         "0x0a",
+			BatchDataPointer:      "http://batch/1.json",
+			RespondToTaskFeeLimit: "100",
+			TaskCreatedBlock:      10,
+			TaskCreatedTx:         "0x0b",
+			TaskCreatedAt:         time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC).UnixMilli(),
+			ResponseBlock:         &responseBlock,
+			ResponseTx:            &responseTx,
+			AggregatorAddress:     &aggregator,
+			GasUsed:               &gasUsed,
+			EffectiveGasPrice:     &gasPrice,
+			RespondedAt:           &respondedAt,
+			Finalized:             true,
+		},
+		{
+			BatchMerkleRoot:       "0x04",
+			SenderAddress:         "0x0a",
+			BatchDataPointer:      "http://batch/4.json",
+			RespondToTaskFeeLimit: "100",
+			TaskCreatedBlock:      12,
+			TaskCreatedTx:         "0x0c",
+			TaskCreatedAt:         time.Date(2024, 6, 3, 12, 0, 24, 0, time.UTC).UnixMilli(),
+		},
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	store := &fakeQueryStore{exported: testExportRows()}
+	var output bytes.Buffer
+	if err := Export(context.Background(), store, &output, BatchesDataset, CSV, time.Time{}, time.Now()); err != nil {
+		t.Fatalf("could not export: %v", err)
+	}
+
+	records, err := csv.NewReader(&output).ReadAll()
+	if err != nil {
+		t.Fatalf("could not read export: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected a header and 2 rows, got %d records", len(records))
+	}
+	if records[0][0] != "batch_merkle_root" || records[0][6] != "task_created_at" || records[0][13] != "finalized" {
+		t.Errorf("unexpected header %v", records[0])
+	}
+	if records[1][6] != "2024-06-03T12:00:00Z" || records[1][7] != "13" || records[1][12] != "2024-06-03T12:00:36Z" || records[1][13] != "true" {
+		t.Errorf("unexpected responded row %v", records[1])
+	}
+	if records[2][7] != "" || records[2][12] != "" || records[2][13] != "false" {
+		t.Errorf("expected the response columns of the pending batch to be empty, got %v", records[2])
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	store := &fakeQueryStore{exported: testExportRows()}
+	var output bytes.Buffer
+	if err := Export(context.Background(), store, &output, BatchesDataset, Parquet, time.Time{}, time.Now()); err != nil {
+		t.Fatalf("could not export: %v", err)
+	}
+
+	parquetReader, err := reader.NewParquetReader(buffer.NewBufferFileFromBytes(output.Bytes()), new(BatchExportRow), 1)
+	if err != nil {
+		t.Fatalf("could not read export: %v", err)
+	}
+	defer parquetReader.ReadStop()
+	rows := make([]BatchExportRow, parquetReader.GetNumRows())
+	if err := parquetReader.Read(&rows); err != nil {
+		t.Fatalf("could not read rows: %v", err)
+	}
+	if !reflect.DeepEqual(rows, testExportRows()) {
+		t.Errorf("expected the exported rows, got %+v", rows)
+	}
+}
+
+func TestAPIExports(t *testing.T) {
+	store := &fakeQueryStore{exported: testExportRows()}
+	server := newTestAPI(t, store, nil)
+
+	response, err := http.Get(server.URL + "/export/batches?format=csv&from=2024-06-01&to=2024-07-01")
+	if err != nil {
+		t.Fatalf("could not export: %v", err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "text/csv" {
+		t.Fatalf("unexpected response %d %s", response.StatusCode, response.Header.Get("Content-Type"))
+	}
+	if disposition := response.Header.Get("Content-Disposition"); disposition != `attachment; filename="batches-2024-06-01-2024-07-01.csv"` {
+		t.Errorf("unexpected content disposition %s", disposition)
+	}
+	if records, err := csv.NewReader(bytes.NewReader(body)).ReadAll(); err != nil || len(records) != 3 {
+		t.Errorf("expected the exported rows, got %s", body)
+	}
+	if !store.lastRange.From.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected range %+v", store.lastRange)
+	}
+
+	getJson(t, server.URL+"/export/operators", http.StatusNotFound, nil)
+	getJson(t, server.URL+"/export/batches?format=xlsx", http.StatusBadRequest, nil)
+	getJson(t, server.URL+"/export/batches?from=2020-01-01&to=2024-01-01", http.StatusBadRequest, nil)
+}
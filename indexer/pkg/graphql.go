@@ -0,0 +1,450 @@
+package pkg
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+const (
+	// maxGraphqlDepth bounds the nesting of the GraphQL queries
+	maxGraphqlDepth = 8
+	// maxGraphqlRequestSize bounds the size of the GraphQL requests
+	maxGraphqlRequestSize = 1 << 20
+)
+
+//go:embed schema.graphql
+var graphqlSchema string
+
+// graphqlHandler serves the GraphQL schema over the stores of the API
+func (a *API) graphqlHandler() http.Handler {
+	schema := graphql.MustParseSchema(graphqlSchema, &queryResolver{api: a}, graphql.MaxDepth(maxGraphqlDepth))
+	handler := &relay.Handler{Schema: schema}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxGraphqlRequestSize)
+		handler.ServeHTTP(w, r)
+	})
+}
+
+type queryResolver struct {
+	api *API
+}
+
+func (q *queryResolver) Batches(ctx context.Context, args struct {
+	Sender    *string
+	FromBlock *int32
+	ToBlock   *int32
+	Status    *string
+	Finalized *bool
+	First     int32
+	Skip      int32
+}) ([]*batchResolver, error) {
+	filter := BatchFilter{Limit: int(args.First), Offset: int(args.Skip)}
+	if filter.Limit <= 0 || filter.Limit > MaxPageSize {
+		return nil, fmt.Errorf("invalid first, expected 1 to %d", MaxPageSize)
+	}
+	if filter.Offset < 0 {
+		return nil, fmt.Errorf("invalid skip")
+	}
+	var err error
+	if filter.SenderAddress, err = parseAddress(args.Sender); err != nil {
+		return nil, err
+	}
+	if args.FromBlock != nil {
+		filter.FromBlock = uint64(max(*args.FromBlock, 0))
+	}
+	if args.ToBlock != nil {
+		toBlock := uint64(max(*args.ToBlock, 0))
+		filter.ToBlock = &toBlock
+	}
+	if args.Status != nil {
+		responded := *args.Status == "RESPONDED"
+		filter.Responded = &responded
+	}
+	filter.Finalized = args.Finalized
+
+	records, err := q.api.store.ListBatches(ctx, filter)
+	if err != nil {
+		q.api.logger.Warn("Could not list batches", "err", err)
+		return nil, fmt.Errorf("could not list batches")
+	}
+	resolvers := make([]*batchResolver, 0, len(records))
+	for i := range records {
+		resolvers = append(resolvers, &batchResolver{api: q.api, record: &records[i]})
+	}
+	return resolvers, nil
+}
+
+func (q *queryResolver) Batch(ctx context.Context, args struct {
+	MerkleRoot string
+	Sender     *string
+}) (*batchResolver, error) {
+	root, err := parseHash(args.MerkleRoot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid batch merkle root: %w", err)
+	}
+	sender, err := parseAddress(args.Sender)
+	if err != nil {
+		return nil, err
+	}
+	record, err := q.api.store.BatchByRoot(ctx, root, sender)
+	if err != nil {
+		q.api.logger.Warn("Could not get batch", "err", err)
+		return nil, fmt.Errorf("could not get batch")
+	}
+	if record == nil {
+		return nil, nil
+	}
+	return &batchResolver{api: q.api, record: record}, nil
+}
+
+func (q *queryResolver) Operators(ctx context.Context, args struct {
+	Status *string
+	First  int32
+	Skip   int32
+}) ([]*operatorResolver, error) {
+	filter := OperatorFilter{Limit: int(args.First), Offset: int(args.Skip)}
+	if filter.Limit <= 0 || filter.Limit > MaxPageSize {
+		return nil, fmt.Errorf("invalid first, expected 1 to %d", MaxPageSize)
+	}
+	if filter.Offset < 0 {
+		return nil, fmt.Errorf("invalid skip")
+	}
+	if args.Status != nil {
+		registered := *args.Status == "REGISTERED"
+		filter.Registered = &registered
+	}
+
+	operators, err := q.api.store.ListOperators(ctx, filter)
+	if err != nil {
+		q.api.logger.Warn("Could not list operators", "err", err)
+		return nil, fmt.Errorf("could not list operators")
+	}
+	resolvers := make([]*operatorResolver, 0, len(operators))
+	for i := range operators {
+		resolvers = append(resolvers, &operatorResolver{api: q.api, id: operators[i].Id, operator: &operators[i], loaded: true})
+	}
+	return resolvers, nil
+}
+
+func (q *queryResolver) Operator(ctx context.Context, args struct{ Id string }) (*operatorResolver, error) {
+	operatorId, err := parseHash(args.Id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operator id: %w", err)
+	}
+	resolver := &operatorResolver{api: q.api, id: operatorId}
+	operator, err := resolver.load(ctx)
+	if err != nil || operator == nil {
+		return nil, err
+	}
+	return resolver, nil
+}
+
+func (q *queryResolver) Proofs(ctx context.Context, args struct {
+	Commitment *string
+	Generator  *string
+	First      int32
+	Skip       int32
+}) ([]*proofResolver, error) {
+	filter := ProofFilter{Limit: int(args.First), Offset: int(args.Skip)}
+	if filter.Limit <= 0 || filter.Limit > MaxPageSize {
+		return nil, fmt.Errorf("invalid first, expected 1 to %d", MaxPageSize)
+	}
+	if filter.Offset < 0 {
+		return nil, fmt.Errorf("invalid skip")
+	}
+	if args.Commitment != nil {
+		commitment, err := parseHash(*args.Commitment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commitment: %w", err)
+		}
+		filter.Commitment = &commitment
+	}
+	var err error
+	if filter.ProofGeneratorAddr, err = parseAddress(args.Generator); err != nil {
+		return nil, err
+	}
+
+	proofs, err := q.api.store.ListProofs(ctx, filter)
+	if err != nil {
+		q.api.logger.Warn("Could not list proofs", "err", err)
+		return nil, fmt.Errorf("could not list proofs")
+	}
+	resolvers := make([]*proofResolver, 0, len(proofs))
+	for i := range proofs {
+		resolvers = append(resolvers, &proofResolver{api: q.api, proof: proofs[i]})
+	}
+	return resolvers, nil
+}
+
+type batchResolver struct {
+	api    *API
+	record *BatchRecord
+}
+
+func (b *batchResolver) MerkleRoot() string {
+	return hexBytes(b.record.BatchMerkleRoot[:])
+}
+
+func (b *batchResolver) SenderAddress() string {
+	return b.record.SenderAddress.Hex()
+}
+
+func (b *batchResolver) BatchDataPointer() string {
+	return b.record.BatchDataPointer
+}
+
+func (b *batchResolver) RespondToTaskFeeLimit() string {
+	return b.record.RespondToTaskFeeLimit.String()
+}
+
+func (b *batchResolver) TaskCreatedBlock() int32 {
+	return int32(b.record.TaskCreatedBlock)
+}
+
+func (b *batchResolver) TaskCreatedTx() string {
+	return b.record.TaskCreatedTx.Hex()
+}
+
+func (b *batchResolver) TaskCreatedAt() string {
+	return b.record.TaskCreatedAt.Format(time.RFC3339)
+}
+
+func (b *batchResolver) Confirmations() *int32 {
+	return confirmations(b.record.ChainHead, b.record.TaskCreatedBlock)
+}
+
+func (b *batchResolver) Finalized() bool {
+	return b.record.ChainHead != nil && b.record.ChainHead.Finalized(b.record.TaskCreatedBlock)
+}
+
+func (b *batchResolver) Response() *responseResolver {
+	if b.record.Response == nil {
+		return nil
+	}
+	return &responseResolver{api: b.api, record: b.record}
+}
+
+func (b *batchResolver) Proofs(ctx context.Context) ([]*proofResolver, error) {
+	proofs, err := b.api.proofsOfBatch(ctx, b.record)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*proofResolver, 0, len(proofs))
+	for i := range proofs {
+		resolvers = append(resolvers, &proofResolver{api: b.api, proof: proofs[i], batch: b})
+	}
+	return resolvers, nil
+}
+
+type responseResolver struct {
+	api    *API
+	record *BatchRecord
+}
+
+func (r *responseResolver) BlockNumber() int32 {
+	return int32(r.record.Response.BlockNumber)
+}
+
+func (r *responseResolver) TxHash() string {
+	return r.record.Response.TxHash.Hex()
+}
+
+func (r *responseResolver) AggregatorAddress() string {
+	return r.record.Response.AggregatorAddress.Hex()
+}
+
+func (r *responseResolver) GasUsed() int32 {
+	return int32(r.record.Response.GasUsed)
+}
+
+func (r *responseResolver) EffectiveGasPrice() string {
+	return r.record.Response.EffectiveGasPrice.String()
+}
+
+func (r *responseResolver) RespondedAt() string {
+	return r.record.Response.RespondedAt.Format(time.RFC3339)
+}
+
+func (r *responseResolver) Confirmations() *int32 {
+	return confirmations(r.record.ChainHead, r.record.Response.BlockNumber)
+}
+
+func (r *responseResolver) Finalized() bool {
+	return r.record.ChainHead != nil && r.record.ChainHead.Finalized(r.record.Response.BlockNumber)
+}
+
+func (r *responseResolver) LatencyBlocks() int32 {
+	return int32(r.record.Response.BlockNumber - r.record.TaskCreatedBlock)
+}
+
+func (r *responseResolver) LatencySeconds() float64 {
+	return r.record.Response.RespondedAt.Sub(r.record.TaskCreatedAt).Seconds()
+}
+
+// NonSigners are loaded on demand, as the listed batches are read without them
+func (r *responseResolver) NonSigners(ctx context.Context) ([]*operatorResolver, error) {
+	nonSigners, err := r.api.store.NonSigners(ctx, r.record.BatchMerkleRoot, r.record.SenderAddress)
+	if err != nil {
+		r.api.logger.Warn("Could not get non signers", "err", err)
+		return nil, fmt.Errorf("could not get the non signers of the response")
+	}
+	resolvers := make([]*operatorResolver, 0, len(nonSigners))
+	for _, operatorId := range nonSigners {
+		resolvers = append(resolvers, &operatorResolver{api: r.api, id: operatorId})
+	}
+	return resolvers, nil
+}
+
+// proofResolver resolves a proof, loading its batch on demand if listed by itself
+type proofResolver struct {
+	api   *API
+	proof Proof
+	batch *batchResolver
+}
+
+func (p *proofResolver) Batch(ctx context.Context) (*batchResolver, error) {
+	if p.batch != nil {
+		return p.batch, nil
+	}
+	record, err := p.api.store.BatchByRoot(ctx, p.proof.BatchMerkleRoot, &p.proof.SenderAddress)
+	if err != nil || record == nil {
+		p.api.logger.Warn("Could not get batch of proof", "root", hexBytes(p.proof.BatchMerkleRoot[:]), "err", err)
+		return nil, fmt.Errorf("could not get the batch of the proof")
+	}
+	return &batchResolver{api: p.api, record: record}, nil
+}
+
+func (p *proofResolver) Index() int32 {
+	return int32(p.proof.IndexInBatch)
+}
+
+func (p *proofResolver) Commitment() string {
+	return hexBytes(p.proof.Commitment[:])
+}
+
+func (p *proofResolver) ProvingSystem() *string {
+	if p.proof.ProvingSystem == "" {
+		return nil
+	}
+	return &p.proof.ProvingSystem
+}
+
+func (p *proofResolver) ProofGeneratorAddr() *string {
+	if p.proof.ProvingSystem == "" {
+		return nil
+	}
+	generator := p.proof.ProofGeneratorAddr.Hex()
+	return &generator
+}
+
+// operatorResolver resolves an operator by id, loading it from the directory on demand for the
+// non signers of the responses
+type operatorResolver struct {
+	api      *API
+	id       common.Hash
+	mu       sync.Mutex
+	operator *Operator
+	loaded   bool
+}
+
+func (o *operatorResolver) load(ctx context.Context) (*Operator, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.loaded {
+		return o.operator, nil
+	}
+	operator, err := o.api.store.OperatorById(ctx, o.id)
+	if err != nil {
+		o.api.logger.Warn("Could not get operator", "id", o.id.Hex(), "err", err)
+		return nil, fmt.Errorf("could not get operator")
+	}
+	o.operator, o.loaded = operator, true
+	return operator, nil
+}
+
+func (o *operatorResolver) Id() string {
+	return o.id.Hex()
+}
+
+func (o *operatorResolver) Address(ctx context.Context) (*string, error) {
+	operator, err := o.load(ctx)
+	if err != nil || operator == nil {
+		return nil, err
+	}
+	address := operator.Address.Hex()
+	return &address, nil
+}
+
+func (o *operatorResolver) Registered(ctx context.Context) (*bool, error) {
+	operator, err := o.load(ctx)
+	if err != nil || operator == nil {
+		return nil, err
+	}
+	return &operator.Registered, nil
+}
+
+func (o *operatorResolver) RegistrationBlock(ctx context.Context) (*int32, error) {
+	operator, err := o.load(ctx)
+	if err != nil || operator == nil {
+		return nil, err
+	}
+	registrationBlock := int32(operator.RegistrationBlock)
+	return &registrationBlock, nil
+}
+
+func (o *operatorResolver) Socket(ctx context.Context) (*string, error) {
+	operator, err := o.load(ctx)
+	if err != nil || operator == nil {
+		return nil, err
+	}
+	return &operator.Socket, nil
+}
+
+func (o *operatorResolver) PubkeyG1(ctx context.Context) (*string, error) {
+	operator, err := o.load(ctx)
+	if err != nil || operator == nil || operator.PubkeyG1 == nil {
+		return nil, err
+	}
+	pubkeyG1 := hexBytes(operator.PubkeyG1)
+	return &pubkeyG1, nil
+}
+
+func (o *operatorResolver) Stakes(ctx context.Context) (*[]*quorumStakeResolver, error) {
+	operator, err := o.load(ctx)
+	if err != nil || operator == nil {
+		return nil, err
+	}
+	resolvers := make([]*quorumStakeResolver, 0, len(operator.Stakes))
+	for _, stake := range operator.Stakes {
+		resolvers = append(resolvers, &quorumStakeResolver{stake: stake})
+	}
+	return &resolvers, nil
+}
+
+type quorumStakeResolver struct {
+	stake QuorumStake
+}
+
+func (q *quorumStakeResolver) QuorumNumber() int32 {
+	return int32(q.stake.QuorumNumber)
+}
+
+func (q *quorumStakeResolver) Stake() string {
+	return q.stake.Stake.String()
+}
+
+func confirmations(head *ChainHead, block uint64) *int32 {
+	if head == nil {
+		return nil
+	}
+	confirmations := int32(head.Confirmations(block))
+	return &confirmations
+}
@@ -0,0 +1,111 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func postGraphql(t *testing.T, url string, query string, variables map[string]interface{}, data interface{}) {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatalf("could not encode query: %v", err)
+	}
+	response, err := http.Post(url+"/graphql", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("could not post query: %v", err)
+	}
+	defer response.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage
+		Errors []struct{ Message string }
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("query failed: %+v", result.Errors)
+	}
+	if err := json.Unmarshal(result.Data, data); err != nil {
+		t.Fatalf("could not decode data: %v", err)
+	}
+}
+
+func TestGraphqlQueriesBatches(t *testing.T) {
+	store := &fakeQueryStore{records: testBatchRecords()}
+	server := newTestAPI(t, store, func(ctx context.Context, batchDataPointer string, batchMerkleRoot [32]byte) ([][32]byte, error) {
+		return [][32]byte{{0xc1}}, nil
+	})
+
+	var data struct {
+		Batches []struct {
+			MerkleRoot string
+			Response   *struct {
+				LatencySeconds float64
+				NonSigners     []struct{ Id string }
+			}
+			Proofs []struct{ Commitment string }
+		}
+	}
+	postGraphql(t, server.URL, `query($sender: String) {
+		batches(sender: $sender, status: RESPONDED, first: 10) {
+			merkleRoot
+			response { latencySeconds nonSigners { id } }
+			proofs { commitment }
+		}
+	}`, map[string]interface{}{"sender": common.Address{0xa}.Hex()}, &data)
+
+	if store.lastFilter.Responded == nil || !*store.lastFilter.Responded || store.lastFilter.Limit != 10 {
+		t.Errorf("filter not parsed: %+v", store.lastFilter)
+	}
+	responded := data.Batches[1]
+	if responded.Response == nil || responded.Response.LatencySeconds != 36 || len(responded.Proofs) != 1 {
+		t.Fatalf("unexpected batch %+v", responded)
+	}
+	if len(responded.Response.NonSigners) != 1 || responded.Response.NonSigners[0].Id != (common.Hash{0xff}).Hex() {
+		t.Errorf("unexpected non signers %+v", responded.Response.NonSigners)
+	}
+	if data.Batches[0].Response != nil {
+		t.Errorf("expected the pending batch without response, got %+v", data.Batches[0])
+	}
+
+	var missing struct{ Batch *struct{ MerkleRoot string } }
+	postGraphql(t, server.URL, `{ batch(merkleRoot: "0x0300000000000000000000000000000000000000000000000000000000000000") { merkleRoot } }`, nil, &missing)
+	if missing.Batch != nil {
+		t.Errorf("expected no batch, got %+v", missing.Batch)
+	}
+}
+
+func TestGraphqlResolvesNonSignersFromTheDirectory(t *testing.T) {
+	server := newTestAPI(t, &fakeQueryStore{records: testBatchRecords(), operators: testOperators()}, nil)
+
+	var data struct {
+		Batch struct {
+			Response struct {
+				NonSigners []struct {
+					Id     string
+					Socket *string
+					Stakes []struct{ Stake string }
+				}
+			}
+		}
+		Operators []struct{ Id string }
+	}
+	postGraphql(t, server.URL, `query($root: String!) {
+		batch(merkleRoot: $root) { response { nonSigners { id socket stakes { stake } } } }
+		operators(status: DEREGISTERED) { id }
+	}`, map[string]interface{}{"root": hexBytes([]byte{1}) + "00000000000000000000000000000000000000000000000000000000000000"}, &data)
+
+	nonSigners := data.Batch.Response.NonSigners
+	if len(nonSigners) != 1 || nonSigners[0].Socket == nil || *nonSigners[0].Socket != "localhost:8080" || nonSigners[0].Stakes[0].Stake != "1000" {
+		t.Errorf("unexpected non signers %+v", nonSigners)
+	}
+	if len(data.Operators) != 1 || data.Operators[0].Id != (common.Hash{0xfe}).Hex() {
+		t.Errorf("unexpected operators %+v", data.Operators)
+	}
+}
@@ -0,0 +1,329 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/sdk/aligned"
+)
+
+// Chain reads the blocks, events and transactions the indexer needs, as the eth clients of the config do
+type Chain interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Contracts are the addresses of the contracts whose events the indexer reads
+type Contracts struct {
+	ServiceManager      common.Address
+	RegistryCoordinator common.Address
+	BlsApkRegistry      common.Address
+	StakeRegistry       common.Address
+}
+
+// Indexer stores the batches of the service manager and the responses to their tasks, and the
+// registrations, keys, stakes and sockets of the operators. It polls the events of the chain in
+// ranges, and on each round checks the last block it saved is still in the chain, rolling back what
+// it indexed from a block replaced by a reorg. The rows finalityDepth blocks behind the head of the
+// chain are considered finalized.
+type Indexer struct {
+	logger            sdklogging.Logger
+	chain             Chain
+	store             Store
+	contracts         Contracts
+	filterer          *servicemanager.ContractAlignedLayerServiceManagerFilterer
+	operatorFilterers *operatorFilterers
+	// topics are the ids of the events indexed, by name
+	topics        map[string]common.Hash
+	fromBlock     uint64
+	blockRange    uint64
+	pollInterval  time.Duration
+	finalityDepth uint64
+}
+
+// NewIndexer creates an indexer of the contracts of the deployment of the config, getting the
+// addresses of the registries from the registry coordinator
+func NewIndexer(indexerConfig config.IndexerConfig, store Store) (*Indexer, error) {
+	baseConfig := indexerConfig.BaseConfig
+	deploymentConfig := baseConfig.AlignedLayerDeploymentConfig
+	registryCoordinator, err := regcoord.NewContractRegistryCoordinatorCaller(deploymentConfig.AlignedLayerRegistryCoordinatorAddr, &baseConfig.EthRpcClient)
+	if err != nil {
+		return nil, err
+	}
+	blsApkRegistry, err := registryCoordinator.BlsApkRegistry(nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not get BLS apk registry address: %w", err)
+	}
+	stakeRegistry, err := registryCoordinator.StakeRegistry(nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not get stake registry address: %w", err)
+	}
+	contracts := Contracts{
+		ServiceManager:      deploymentConfig.AlignedLayerServiceManagerAddr,
+		RegistryCoordinator: deploymentConfig.AlignedLayerRegistryCoordinatorAddr,
+		BlsApkRegistry:      blsApkRegistry,
+		StakeRegistry:       stakeRegistry,
+	}
+	return newIndexer(baseConfig.Logger, &baseConfig.EthRpcClient, store, contracts,
+		indexerConfig.Indexer.FromBlock, indexerConfig.Indexer.BlockRange, indexerConfig.Indexer.PollInterval, indexerConfig.Indexer.FinalityDepth)
+}
+
+func newIndexer(logger sdklogging.Logger, chain Chain, store Store, contracts Contracts, fromBlock uint64, blockRange uint64, pollInterval time.Duration, finalityDepth uint64) (*Indexer, error) {
+	filterer, err := servicemanager.NewContractAlignedLayerServiceManagerFilterer(contracts.ServiceManager, nil)
+	if err != nil {
+		return nil, err
+	}
+	operatorFilterers, err := newOperatorFilterers(contracts)
+	if err != nil {
+		return nil, err
+	}
+	serviceManagerAbi, err := servicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	topics, err := operatorEventTopics()
+	if err != nil {
+		return nil, err
+	}
+	topics["NewBatchV3"] = serviceManagerAbi.Events["NewBatchV3"].ID
+	topics["BatchVerified"] = serviceManagerAbi.Events["BatchVerified"].ID
+	return &Indexer{
+		logger:            logger,
+		chain:             chain,
+		store:             store,
+		contracts:         contracts,
+		filterer:          filterer,
+		operatorFilterers: operatorFilterers,
+		topics:            topics,
+		fromBlock:         fromBlock,
+		blockRange:        max(blockRange, 1),
+		pollInterval:      pollInterval,
+		finalityDepth:     finalityDepth,
+	}, nil
+}
+
+// Start indexes the chain until the context is done, retrying the failed rounds on the next poll
+func (i *Indexer) Start(ctx context.Context) error {
+	for {
+		if err := i.IndexUntilLatest(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			i.logger.Warn("Could not index blocks, retrying", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(i.pollInterval):
+		}
+	}
+}
+
+// IndexUntilLatest indexes the blocks from the one after the last saved to the latest one, saving
+// the latest one as head of the chain
+func (i *Indexer) IndexUntilLatest(ctx context.Context) error {
+	latest, err := i.chain.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get block number: %w", err)
+	}
+	from, err := i.resumeBlock(ctx, latest)
+	if err != nil {
+		return err
+	}
+	if err := i.store.SaveChainHead(ctx, ChainHead{Number: latest, FinalityDepth: i.finalityDepth}); err != nil {
+		return fmt.Errorf("could not save chain head: %w", err)
+	}
+	for from <= latest {
+		to := min(from+i.blockRange-1, latest)
+		indexedRange, err := i.indexRange(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		if err := i.store.SaveRange(ctx, indexedRange); err != nil {
+			return fmt.Errorf("could not save blocks %d to %d: %w", from, to, err)
+		}
+		i.logger.Debug("Indexed blocks", "from", from, "to", to, "batches", len(indexedRange.Batches), "responses", len(indexedRange.Responses))
+		from = to + 1
+	}
+	return nil
+}
+
+// resumeBlock returns the block to index from, the one after the last saved block if it's still in
+// the chain. Otherwise, a reorg replaced it, so it's rolled back with what was indexed after it, and
+// the previous saved block is checked.
+func (i *Indexer) resumeBlock(ctx context.Context, latest uint64) (uint64, error) {
+	for {
+		last, err := i.store.LastIndexedBlock(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("could not get last indexed block: %w", err)
+		}
+		if last == nil {
+			return i.fromBlock, nil
+		}
+		header, err := i.chain.HeaderByNumber(ctx, new(big.Int).SetUint64(last.Number))
+		if err != nil {
+			return 0, fmt.Errorf("could not get block %d: %w", last.Number, err)
+		}
+		if header.Hash() == last.Hash {
+			return last.Number + 1, nil
+		}
+		i.logger.Warn("Block replaced by a reorg, rolling it back", "block", last.Number, "hash", last.Hash.Hex(), "newHash", header.Hash().Hex())
+		if head := (ChainHead{Number: latest, FinalityDepth: i.finalityDepth}); head.Finalized(last.Number) {
+			i.logger.Error("Reorg deeper than the finality depth, rows served as finalized are rolled back", "block", last.Number, "finalityDepth", i.finalityDepth)
+		}
+		if err := i.store.RollbackFrom(ctx, last.Number); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// indexRange reads the batches, responses and operator events of the blocks
+func (i *Indexer) indexRange(ctx context.Context, from uint64, to uint64) (IndexedRange, error) {
+	topics := make([]common.Hash, 0, len(i.topics))
+	for _, topic := range i.topics {
+		topics = append(topics, topic)
+	}
+	logs, err := i.chain.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{i.contracts.ServiceManager, i.contracts.RegistryCoordinator, i.contracts.BlsApkRegistry, i.contracts.StakeRegistry},
+		Topics:    [][]common.Hash{topics},
+	})
+	if err != nil {
+		return IndexedRange{}, fmt.Errorf("could not get logs of blocks %d to %d: %w", from, to, err)
+	}
+
+	var indexedRange IndexedRange
+	blocks := make(map[uint64]Block)
+	block := func(number uint64) (Block, error) {
+		if block, ok := blocks[number]; ok {
+			return block, nil
+		}
+		header, err := i.chain.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return Block{}, fmt.Errorf("could not get block %d: %w", number, err)
+		}
+		block := Block{Number: number, Hash: header.Hash(), Timestamp: time.Unix(int64(header.Time), 0).UTC()}
+		blocks[number] = block
+		indexedRange.Blocks = append(indexedRange.Blocks, block)
+		return block, nil
+	}
+
+	for _, log := range logs {
+		if log.Removed || len(log.Topics) == 0 {
+			continue
+		}
+		logBlock, err := block(log.BlockNumber)
+		if err != nil {
+			return IndexedRange{}, err
+		}
+		// the logs are read by block number, so they must be of the block saved
+		if log.BlockHash != (common.Hash{}) && log.BlockHash != logBlock.Hash {
+			return IndexedRange{}, fmt.Errorf("block %d changed while indexing it", log.BlockNumber)
+		}
+
+		if ok, err := i.indexOperatorLog(log, &indexedRange); ok {
+			if err != nil {
+				i.logger.Warn("Could not parse operator event", "tx", log.TxHash.Hex(), "err", err)
+			}
+			continue
+		}
+		if log.Address != i.contracts.ServiceManager {
+			continue
+		}
+		switch log.Topics[0] {
+		case i.topics["NewBatchV3"]:
+			newBatch, err := i.filterer.ParseNewBatchV3(log)
+			if err != nil {
+				i.logger.Warn("Could not parse NewBatchV3 event", "tx", log.TxHash.Hex(), "err", err)
+				continue
+			}
+			indexedRange.Batches = append(indexedRange.Batches, Batch{
+				BatchMerkleRoot:       newBatch.BatchMerkleRoot,
+				SenderAddress:         newBatch.SenderAddress,
+				BatchDataPointer:      newBatch.BatchDataPointer,
+				RespondToTaskFeeLimit: newBatch.RespondToTaskFeeLimit,
+				TaskCreatedBlock:      log.BlockNumber,
+				TaskCreatedTx:         log.TxHash,
+				TaskCreatedAt:         logBlock.Timestamp,
+			})
+		case i.topics["BatchVerified"]:
+			batchVerified, err := i.filterer.ParseBatchVerified(log)
+			if err != nil {
+				i.logger.Warn("Could not parse BatchVerified event", "tx", log.TxHash.Hex(), "err", err)
+				continue
+			}
+			response, err := i.response(ctx, log)
+			if err != nil {
+				return IndexedRange{}, err
+			}
+			response.BatchMerkleRoot = batchVerified.BatchMerkleRoot
+			response.SenderAddress = batchVerified.SenderAddress
+			response.RespondedAt = logBlock.Timestamp
+			indexedRange.Responses = append(indexedRange.Responses, response)
+		}
+	}
+
+	// the last block of the range is saved to resume from it
+	if _, err := block(to); err != nil {
+		return IndexedRange{}, err
+	}
+	return indexedRange, nil
+}
+
+// response reads the receipt and sender of the transaction of the response
+func (i *Indexer) response(ctx context.Context, log types.Log) (Response, error) {
+	receipt, err := i.chain.TransactionReceipt(ctx, log.TxHash)
+	if err != nil {
+		return Response{}, fmt.Errorf("could not get receipt of %s: %w", log.TxHash.Hex(), err)
+	}
+	tx, _, err := i.chain.TransactionByHash(ctx, log.TxHash)
+	if err != nil {
+		return Response{}, fmt.Errorf("could not get transaction %s: %w", log.TxHash.Hex(), err)
+	}
+	aggregator, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return Response{}, fmt.Errorf("could not get sender of %s: %w", log.TxHash.Hex(), err)
+	}
+	effectiveGasPrice := receipt.EffectiveGasPrice
+	if effectiveGasPrice == nil {
+		effectiveGasPrice = tx.GasPrice()
+	}
+	return Response{
+		BlockNumber:       log.BlockNumber,
+		TxHash:            log.TxHash,
+		AggregatorAddress: aggregator,
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: effectiveGasPrice,
+		NonSigners:        i.nonSigners(tx),
+	}, nil
+}
+
+// nonSigners returns the ids of the operators that didn't sign the response, nil if the response
+// wasn't sent directly to the service manager and its calldata can't be decoded
+func (i *Indexer) nonSigners(tx *types.Transaction) []common.Hash {
+	taskResponse, err := aligned.ParseTaskResponse(tx.Data())
+	if err != nil {
+		i.logger.Debug("Could not decode task response, skipping its non signers", "tx", tx.Hash().Hex(), "err", err)
+		return nil
+	}
+	var operatorIds []common.Hash
+	for _, pubkey := range taskResponse.NonSignerStakesAndSignature.NonSignerPubkeys {
+		operatorIds = append(operatorIds, crypto.Keccak256Hash(common.LeftPadBytes(pubkey.X.Bytes(), 32), common.LeftPadBytes(pubkey.Y.Bytes(), 32)))
+	}
+	return operatorIds
+}
@@ -0,0 +1,358 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	blsapkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	stakereg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/StakeRegistry"
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+var testContracts = Contracts{
+	ServiceManager:      common.HexToAddress("0x851356ae760d987E095750cCeb3bC6014560891C"),
+	RegistryCoordinator: common.HexToAddress("0xf5059a5D33d5853360D16C683c16e67980206f36"),
+	BlsApkRegistry:      common.HexToAddress("0x70e0bA845a1A0F2DA3359C97E0285013525FFC49"),
+	StakeRegistry:       common.HexToAddress("0x998abeb3E57409262aE5b751f60747921B33613E"),
+}
+
+// fakeChain is a chain of empty blocks with the service manager events added to it, which can be
+// reorganized from a block
+type fakeChain struct {
+	t        *testing.T
+	headers  []*types.Header
+	logs     []types.Log
+	txs      map[common.Hash]*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	fork     byte
+}
+
+func newFakeChain(t *testing.T, blocks int) *fakeChain {
+	chain := &fakeChain{t: t, txs: make(map[common.Hash]*types.Transaction), receipts: make(map[common.Hash]*types.Receipt)}
+	chain.mine(blocks)
+	return chain
+}
+
+func (c *fakeChain) mine(blocks int) {
+	for n := 0; n < blocks; n++ {
+		header := &types.Header{Number: big.NewInt(int64(len(c.headers))), Time: uint64(1_700_000_000 + 12*len(c.headers)), Extra: []byte{c.fork}}
+		if len(c.headers) > 0 {
+			header.ParentHash = c.headers[len(c.headers)-1].Hash()
+		}
+		c.headers = append(c.headers, header)
+	}
+}
+
+// reorg replaces the blocks from the block on, dropping their events
+func (c *fakeChain) reorg(from uint64) {
+	blocks := len(c.headers) - int(from)
+	c.headers = c.headers[:from]
+	var logs []types.Log
+	for _, log := range c.logs {
+		if log.BlockNumber < from {
+			logs = append(logs, log)
+		}
+	}
+	c.logs = logs
+	c.fork++
+	c.mine(blocks)
+}
+
+// addEvent adds the event of the service manager in a transaction sent by the key to the block
+func (c *fakeChain) addEvent(block uint64, name string, root [32]byte, key *testKey, args ...interface{}) common.Hash {
+	serviceManagerAbi, err := servicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		c.t.Fatalf("could not get abi: %v", err)
+	}
+	return c.addLog(block, testContracts.ServiceManager, serviceManagerAbi.Events[name], []common.Hash{root}, key, args...)
+}
+
+// addLog adds the event of the contract with the indexed and non indexed arguments, in a transaction
+// sent by the key to the block
+func (c *fakeChain) addLog(block uint64, address common.Address, event abi.Event, indexed []common.Hash, key *testKey, args ...interface{}) common.Hash {
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		c.t.Fatalf("could not pack %s: %v", event.Name, err)
+	}
+	tx := key.sign(c.t, uint64(len(c.txs)))
+	c.txs[tx.Hash()] = tx
+	c.receipts[tx.Hash()] = &types.Receipt{GasUsed: 21000, EffectiveGasPrice: big.NewInt(1_000_000_000)}
+	c.logs = append(c.logs, types.Log{
+		Address:     address,
+		Topics:      append([]common.Hash{event.ID}, indexed...),
+		Data:        data,
+		BlockNumber: block,
+		BlockHash:   c.headers[block].Hash(),
+		TxHash:      tx.Hash(),
+		Index:       uint(len(c.logs)),
+	})
+	return tx.Hash()
+}
+
+func (c *fakeChain) BlockNumber(ctx context.Context) (uint64, error) {
+	return uint64(len(c.headers) - 1), nil
+}
+
+func (c *fakeChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number.Uint64() >= uint64(len(c.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return c.headers[number.Uint64()], nil
+}
+
+func (c *fakeChain) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for _, log := range c.logs {
+		if log.BlockNumber >= query.FromBlock.Uint64() && log.BlockNumber <= query.ToBlock.Uint64() {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (c *fakeChain) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	tx, ok := c.txs[hash]
+	if !ok {
+		return nil, false, ethereum.NotFound
+	}
+	return tx, false, nil
+}
+
+func (c *fakeChain) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, ok := c.receipts[txHash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+type testKey struct {
+	address common.Address
+	sign    func(t *testing.T, nonce uint64) *types.Transaction
+}
+
+func newTestKey(t *testing.T) *testKey {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	signer := types.LatestSignerForChainID(big.NewInt(31337))
+	return &testKey{
+		address: crypto.PubkeyToAddress(privateKey.PublicKey),
+		sign: func(t *testing.T, nonce uint64) *types.Transaction {
+			tx, err := types.SignNewTx(privateKey, signer, &types.DynamicFeeTx{ChainID: big.NewInt(31337), Nonce: nonce, To: &testContracts.ServiceManager})
+			if err != nil {
+				t.Fatalf("could not sign transaction: %v", err)
+			}
+			return tx
+		},
+	}
+}
+
+// memoryStore keeps the indexed rows in memory, as the Postgres store keeps them in its tables
+type memoryStore struct {
+	blocks    map[uint64]Block
+	batches   []Batch
+	responses []Response
+	operators IndexedRange
+	head      ChainHead
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{blocks: make(map[uint64]Block)}
+}
+
+func (s *memoryStore) LastIndexedBlock(ctx context.Context) (*Block, error) {
+	var last *Block
+	for _, block := range s.blocks {
+		if last == nil || block.Number > last.Number {
+			block := block
+			last = &block
+		}
+	}
+	return last, nil
+}
+
+func (s *memoryStore) SaveRange(ctx context.Context, indexedRange IndexedRange) error {
+	for _, block := range indexedRange.Blocks {
+		s.blocks[block.Number] = block
+	}
+	s.batches = append(s.batches, indexedRange.Batches...)
+	s.responses = append(s.responses, indexedRange.Responses...)
+	s.operators.Registrations = append(s.operators.Registrations, indexedRange.Registrations...)
+	s.operators.Pubkeys = append(s.operators.Pubkeys, indexedRange.Pubkeys...)
+	s.operators.Stakes = append(s.operators.Stakes, indexedRange.Stakes...)
+	s.operators.Sockets = append(s.operators.Sockets, indexedRange.Sockets...)
+	return nil
+}
+
+func (s *memoryStore) SaveChainHead(ctx context.Context, head ChainHead) error {
+	s.head = head
+	return nil
+}
+
+func (s *memoryStore) RollbackFrom(ctx context.Context, block uint64) error {
+	for number := range s.blocks {
+		if number >= block {
+			delete(s.blocks, number)
+		}
+	}
+	var batches []Batch
+	for _, batch := range s.batches {
+		if batch.TaskCreatedBlock < block {
+			batches = append(batches, batch)
+		}
+	}
+	var responses []Response
+	for _, response := range s.responses {
+		if response.BlockNumber < block {
+			responses = append(responses, response)
+		}
+	}
+	s.batches, s.responses = batches, responses
+	s.operators.Registrations = keepBefore(s.operators.Registrations, block, func(r OperatorRegistration) uint64 { return r.BlockNumber })
+	s.operators.Pubkeys = keepBefore(s.operators.Pubkeys, block, func(p OperatorPubkey) uint64 { return p.BlockNumber })
+	s.operators.Stakes = keepBefore(s.operators.Stakes, block, func(s OperatorStake) uint64 { return s.BlockNumber })
+	s.operators.Sockets = keepBefore(s.operators.Sockets, block, func(s OperatorSocket) uint64 { return s.BlockNumber })
+	return nil
+}
+
+func keepBefore[T any](events []T, block uint64, blockNumber func(T) uint64) []T {
+	var kept []T
+	for _, event := range events {
+		if blockNumber(event) < block {
+			kept = append(kept, event)
+		}
+	}
+	return kept
+}
+
+func newTestIndexer(t *testing.T, chain Chain, store Store) *Indexer {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %v", err)
+	}
+	indexer, err := newIndexer(logger, chain, store, testContracts, 1, 4, time.Millisecond, 3)
+	if err != nil {
+		t.Fatalf("could not create indexer: %v", err)
+	}
+	return indexer
+}
+
+func TestIndexerIndexesBatchesAndResponses(t *testing.T) {
+	batcherKey, aggregatorKey := newTestKey(t), newTestKey(t)
+	chain := newFakeChain(t, 12)
+	createdTx := chain.addEvent(3, "NewBatchV3", [32]byte{1}, batcherKey, batcherKey.address, uint32(3), "http://batch/1.json", big.NewInt(100))
+	respondedTx := chain.addEvent(9, "BatchVerified", [32]byte{1}, aggregatorKey, batcherKey.address)
+
+	store := newMemoryStore()
+	if err := newTestIndexer(t, chain, store).IndexUntilLatest(context.Background()); err != nil {
+		t.Fatalf("could not index: %v", err)
+	}
+
+	if len(store.batches) != 1 || len(store.responses) != 1 {
+		t.Fatalf("expected a batch and a response, got %+v, %+v", store.batches, store.responses)
+	}
+	batch := store.batches[0]
+	if batch.SenderAddress != batcherKey.address || batch.TaskCreatedBlock != 3 || batch.TaskCreatedTx != createdTx || batch.BatchDataPointer != "http://batch/1.json" {
+		t.Errorf("unexpected batch %+v", batch)
+	}
+	response := store.responses[0]
+	if response.AggregatorAddress != aggregatorKey.address || response.BlockNumber != 9 || response.TxHash != respondedTx || response.GasUsed != 21000 {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if last, _ := store.LastIndexedBlock(context.Background()); last == nil || last.Number != 11 {
+		t.Errorf("expected the last block to be indexed, got %+v", last)
+	}
+	if store.head != (ChainHead{Number: 11, FinalityDepth: 3}) {
+		t.Errorf("expected the chain head to be saved, got %+v", store.head)
+	}
+	if !store.head.Finalized(batch.TaskCreatedBlock) || store.head.Finalized(response.BlockNumber) {
+		t.Errorf("expected only the batch to be finalized, %d blocks behind the head", store.head.Confirmations(batch.TaskCreatedBlock))
+	}
+}
+
+func TestIndexerRollsBackReorgs(t *testing.T) {
+	batcherKey, aggregatorKey := newTestKey(t), newTestKey(t)
+	chain := newFakeChain(t, 12)
+	chain.addEvent(3, "NewBatchV3", [32]byte{1}, batcherKey, batcherKey.address, uint32(3), "http://batch/1.json", big.NewInt(100))
+	chain.addEvent(9, "BatchVerified", [32]byte{1}, aggregatorKey, batcherKey.address)
+
+	store := newMemoryStore()
+	indexer := newTestIndexer(t, chain, store)
+	if err := indexer.IndexUntilLatest(context.Background()); err != nil {
+		t.Fatalf("could not index: %v", err)
+	}
+
+	// the response is moved to a later block by the reorg
+	chain.reorg(8)
+	chain.addEvent(10, "BatchVerified", [32]byte{1}, aggregatorKey, batcherKey.address)
+	if err := indexer.IndexUntilLatest(context.Background()); err != nil {
+		t.Fatalf("could not index after reorg: %v", err)
+	}
+
+	if len(store.batches) != 1 || len(store.responses) != 1 || store.responses[0].BlockNumber != 10 {
+		t.Fatalf("expected the response of the new chain, got %+v", store.responses)
+	}
+	var numbers []int
+	for number, block := range store.blocks {
+		numbers = append(numbers, int(number))
+		if block.Hash != chain.headers[number].Hash() {
+			t.Errorf("block %d of the old chain not rolled back", number)
+		}
+	}
+	sort.Ints(numbers)
+	if numbers[len(numbers)-1] != 11 {
+		t.Errorf("expected the last block to be indexed, got %v", numbers)
+	}
+}
+
+func TestIndexerIndexesOperatorEvents(t *testing.T) {
+	operatorKey := newTestKey(t)
+	registryCoordinatorAbi, _ := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	blsApkRegistryAbi, _ := blsapkreg.ContractBLSApkRegistryMetaData.GetAbi()
+	stakeRegistryAbi, _ := stakereg.ContractStakeRegistryMetaData.GetAbi()
+
+	pubkeyG1 := blsapkreg.BN254G1Point{X: big.NewInt(1), Y: big.NewInt(2)}
+	pubkeyG2 := blsapkreg.BN254G2Point{X: [2]*big.Int{big.NewInt(3), big.NewInt(4)}, Y: [2]*big.Int{big.NewInt(5), big.NewInt(6)}}
+	operatorId := crypto.Keccak256Hash(common.LeftPadBytes([]byte{1}, 32), common.LeftPadBytes([]byte{2}, 32))
+	operatorTopic := common.BytesToHash(operatorKey.address.Bytes())
+
+	chain := newFakeChain(t, 12)
+	chain.addLog(2, testContracts.BlsApkRegistry, blsApkRegistryAbi.Events["NewPubkeyRegistration"], []common.Hash{operatorTopic}, operatorKey, pubkeyG1, pubkeyG2)
+	chain.addLog(2, testContracts.RegistryCoordinator, registryCoordinatorAbi.Events["OperatorSocketUpdate"], []common.Hash{operatorId}, operatorKey, "localhost:8080")
+	chain.addLog(2, testContracts.StakeRegistry, stakeRegistryAbi.Events["OperatorStakeUpdate"], []common.Hash{operatorId}, operatorKey, uint8(0), big.NewInt(1000))
+	chain.addLog(2, testContracts.RegistryCoordinator, registryCoordinatorAbi.Events["OperatorRegistered"], []common.Hash{operatorTopic, operatorId}, operatorKey)
+	chain.addLog(7, testContracts.RegistryCoordinator, registryCoordinatorAbi.Events["OperatorDeregistered"], []common.Hash{operatorTopic, operatorId}, operatorKey)
+	// events of other contracts with the same ids are ignored
+	chain.addLog(7, testContracts.ServiceManager, stakeRegistryAbi.Events["OperatorStakeUpdate"], []common.Hash{operatorId}, operatorKey, uint8(0), big.NewInt(1))
+
+	store := newMemoryStore()
+	if err := newTestIndexer(t, chain, store).IndexUntilLatest(context.Background()); err != nil {
+		t.Fatalf("could not index: %v", err)
+	}
+
+	operators := store.operators
+	if len(operators.Pubkeys) != 1 || operators.Pubkeys[0].OperatorId != operatorId || operators.Pubkeys[0].OperatorAddress != operatorKey.address || len(operators.Pubkeys[0].PubkeyG2) != 128 {
+		t.Errorf("unexpected pubkeys %+v", operators.Pubkeys)
+	}
+	if len(operators.Registrations) != 2 || !operators.Registrations[0].Registered || operators.Registrations[1].Registered || operators.Registrations[1].BlockNumber != 7 {
+		t.Errorf("unexpected registrations %+v", operators.Registrations)
+	}
+	if len(operators.Stakes) != 1 || operators.Stakes[0].Stake.Int64() != 1000 {
+		t.Errorf("unexpected stakes %+v", operators.Stakes)
+	}
+	if len(operators.Sockets) != 1 || operators.Sockets[0].Socket != "localhost:8080" {
+		t.Errorf("unexpected sockets %+v", operators.Sockets)
+	}
+}
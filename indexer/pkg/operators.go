@@ -0,0 +1,189 @@
+package pkg
+
+import (
+	"math/big"
+
+	blsapkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	stakereg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/StakeRegistry"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// OperatorRegistration is the registration or deregistration of an operator in the registry coordinator
+type OperatorRegistration struct {
+	OperatorId      common.Hash
+	OperatorAddress common.Address
+	Registered      bool
+	BlockNumber     uint64
+	LogIndex        uint
+	TxHash          common.Hash
+}
+
+// OperatorPubkey is the BLS public key an operator registered in the BLS apk registry. The G1 key
+// is encoded as X and Y, and the G2 one as X and Y with their two coordinates each, 32 bytes per
+// coordinate as in the contracts.
+type OperatorPubkey struct {
+	OperatorId      common.Hash
+	OperatorAddress common.Address
+	PubkeyG1        []byte
+	PubkeyG2        []byte
+	BlockNumber     uint64
+}
+
+// OperatorStake is an update of the stake of an operator in a quorum of the stake registry
+type OperatorStake struct {
+	OperatorId   common.Hash
+	QuorumNumber uint8
+	Stake        *big.Int
+	BlockNumber  uint64
+	LogIndex     uint
+}
+
+// OperatorSocket is an update of the socket of an operator in the registry coordinator
+type OperatorSocket struct {
+	OperatorId  common.Hash
+	Socket      string
+	BlockNumber uint64
+	LogIndex    uint
+}
+
+// Operator is an operator of the directory, as of its last indexed events
+type Operator struct {
+	Id         common.Hash
+	Address    common.Address
+	Registered bool
+	// RegistrationBlock is the block of the last registration or deregistration of the operator
+	RegistrationBlock uint64
+	Socket            string
+	// PubkeyG1 is nil if the operator registered its key before the indexed blocks
+	PubkeyG1 []byte
+	Stakes   []QuorumStake
+}
+
+// QuorumStake is the stake of an operator in a quorum
+type QuorumStake struct {
+	QuorumNumber uint8
+	Stake        *big.Int
+}
+
+// operatorFilterers parse the events of the registries of the operators
+type operatorFilterers struct {
+	registryCoordinator *regcoord.ContractRegistryCoordinatorFilterer
+	blsApkRegistry      *blsapkreg.ContractBLSApkRegistryFilterer
+	stakeRegistry       *stakereg.ContractStakeRegistryFilterer
+}
+
+func newOperatorFilterers(contracts Contracts) (*operatorFilterers, error) {
+	registryCoordinator, err := regcoord.NewContractRegistryCoordinatorFilterer(contracts.RegistryCoordinator, nil)
+	if err != nil {
+		return nil, err
+	}
+	blsApkRegistry, err := blsapkreg.NewContractBLSApkRegistryFilterer(contracts.BlsApkRegistry, nil)
+	if err != nil {
+		return nil, err
+	}
+	stakeRegistry, err := stakereg.NewContractStakeRegistryFilterer(contracts.StakeRegistry, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &operatorFilterers{registryCoordinator: registryCoordinator, blsApkRegistry: blsApkRegistry, stakeRegistry: stakeRegistry}, nil
+}
+
+// operatorEventTopics returns the ids of the events of the registries the indexer reads, by name
+func operatorEventTopics() (map[string]common.Hash, error) {
+	registryCoordinatorAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	blsApkRegistryAbi, err := blsapkreg.ContractBLSApkRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	stakeRegistryAbi, err := stakereg.ContractStakeRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]common.Hash{
+		"OperatorRegistered":    registryCoordinatorAbi.Events["OperatorRegistered"].ID,
+		"OperatorDeregistered":  registryCoordinatorAbi.Events["OperatorDeregistered"].ID,
+		"OperatorSocketUpdate":  registryCoordinatorAbi.Events["OperatorSocketUpdate"].ID,
+		"NewPubkeyRegistration": blsApkRegistryAbi.Events["NewPubkeyRegistration"].ID,
+		"OperatorStakeUpdate":   stakeRegistryAbi.Events["OperatorStakeUpdate"].ID,
+	}, nil
+}
+
+// indexOperatorLog adds the operator event of the log to the range, returning false if the log
+// isn't one
+func (i *Indexer) indexOperatorLog(log types.Log, indexedRange *IndexedRange) (bool, error) {
+	switch {
+	case log.Address == i.contracts.RegistryCoordinator && log.Topics[0] == i.topics["OperatorRegistered"]:
+		registered, err := i.operatorFilterers.registryCoordinator.ParseOperatorRegistered(log)
+		if err != nil {
+			return true, err
+		}
+		indexedRange.Registrations = append(indexedRange.Registrations, newOperatorRegistration(log, registered.OperatorId, registered.Operator, true))
+	case log.Address == i.contracts.RegistryCoordinator && log.Topics[0] == i.topics["OperatorDeregistered"]:
+		deregistered, err := i.operatorFilterers.registryCoordinator.ParseOperatorDeregistered(log)
+		if err != nil {
+			return true, err
+		}
+		indexedRange.Registrations = append(indexedRange.Registrations, newOperatorRegistration(log, deregistered.OperatorId, deregistered.Operator, false))
+	case log.Address == i.contracts.RegistryCoordinator && log.Topics[0] == i.topics["OperatorSocketUpdate"]:
+		socketUpdate, err := i.operatorFilterers.registryCoordinator.ParseOperatorSocketUpdate(log)
+		if err != nil {
+			return true, err
+		}
+		indexedRange.Sockets = append(indexedRange.Sockets, OperatorSocket{
+			OperatorId:  socketUpdate.OperatorId,
+			Socket:      socketUpdate.Socket,
+			BlockNumber: log.BlockNumber,
+			LogIndex:    log.Index,
+		})
+	case log.Address == i.contracts.BlsApkRegistry && log.Topics[0] == i.topics["NewPubkeyRegistration"]:
+		pubkeyRegistration, err := i.operatorFilterers.blsApkRegistry.ParseNewPubkeyRegistration(log)
+		if err != nil {
+			return true, err
+		}
+		g1, g2 := pubkeyRegistration.PubkeyG1, pubkeyRegistration.PubkeyG2
+		pubkeyG1 := append(common.LeftPadBytes(g1.X.Bytes(), 32), common.LeftPadBytes(g1.Y.Bytes(), 32)...)
+		var pubkeyG2 []byte
+		for _, coordinate := range []*big.Int{g2.X[0], g2.X[1], g2.Y[0], g2.Y[1]} {
+			pubkeyG2 = append(pubkeyG2, common.LeftPadBytes(coordinate.Bytes(), 32)...)
+		}
+		indexedRange.Pubkeys = append(indexedRange.Pubkeys, OperatorPubkey{
+			OperatorId:      crypto.Keccak256Hash(pubkeyG1),
+			OperatorAddress: pubkeyRegistration.Operator,
+			PubkeyG1:        pubkeyG1,
+			PubkeyG2:        pubkeyG2,
+			BlockNumber:     log.BlockNumber,
+		})
+	case log.Address == i.contracts.StakeRegistry && log.Topics[0] == i.topics["OperatorStakeUpdate"]:
+		stakeUpdate, err := i.operatorFilterers.stakeRegistry.ParseOperatorStakeUpdate(log)
+		if err != nil {
+			return true, err
+		}
+		indexedRange.Stakes = append(indexedRange.Stakes, OperatorStake{
+			OperatorId:   stakeUpdate.OperatorId,
+			QuorumNumber: stakeUpdate.QuorumNumber,
+			Stake:        stakeUpdate.Stake,
+			BlockNumber:  log.BlockNumber,
+			LogIndex:     log.Index,
+		})
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func newOperatorRegistration(log types.Log, operatorId [32]byte, operatorAddress common.Address, registered bool) OperatorRegistration {
+	return OperatorRegistration{
+		OperatorId:      operatorId,
+		OperatorAddress: operatorAddress,
+		Registered:      registered,
+		BlockNumber:     log.BlockNumber,
+		LogIndex:        log.Index,
+		TxHash:          log.TxHash,
+	}
+}
@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OperatorParticipation is how an operator took part in the responses of a time range. An operator
+// is expected to sign the responses to the tasks created while it was registered, so only the
+// operators of the directory are tracked.
+type OperatorParticipation struct {
+	OperatorId      common.Hash
+	OperatorAddress common.Address
+	// Responses is the number of responses the operator was expected to sign
+	Responses uint64
+	// Missed is the number of those responses the operator didn't sign
+	Missed uint64
+	// AverageLatency is the seconds from the creation of the tasks the operator signed to their
+	// responses, nil if it signed none. Operators sign off-chain, so it's the latency of the quorums
+	// the operator was part of.
+	AverageLatency *float64
+}
+
+// SigningRate returns the fraction of the expected responses the operator signed, 1 if none were
+func (p *OperatorParticipation) SigningRate() float64 {
+	if p.Responses == 0 {
+		return 1
+	}
+	return float64(p.Responses-p.Missed) / float64(p.Responses)
+}
+
+// MissedBatch is a batch whose response an operator didn't sign
+type MissedBatch struct {
+	BatchMerkleRoot [32]byte
+	SenderAddress   common.Address
+	BlockNumber     uint64
+	RespondedAt     time.Time
+}
+
+// expectedSignersQuery selects, for each response of the range, the operators registered when its
+// task was created, and whether they didn't sign it, optionally for a single operator
+const expectedSignersQuery = `WITH expected AS (
+		SELECT r.batch_merkle_root, r.sender_address, r.block_number, r.responded_at,
+			EXTRACT(EPOCH FROM r.responded_at - b.task_created_at) AS latency, o.operator_id, o.operator_address
+		FROM responses r JOIN batches b USING (batch_merkle_root, sender_address)
+		JOIN LATERAL (
+			SELECT DISTINCT ON (operator_id) operator_id, operator_address, registered
+			FROM operator_registrations WHERE block_number < b.task_created_block
+			ORDER BY operator_id, block_number DESC, log_index DESC
+		) o ON o.registered
+		WHERE r.responded_at >= $1 AND r.responded_at < $2 AND ($3::BYTEA IS NULL OR o.operator_id = $3)
+	)
+	SELECT e.*, n.operator_id IS NOT NULL AS missed FROM expected e
+	LEFT JOIN response_non_signers n USING (batch_merkle_root, sender_address, operator_id)`
+
+func (s *PostgresStore) OperatorParticipation(ctx context.Context, from time.Time, to time.Time, operatorId *common.Hash) ([]OperatorParticipation, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT operator_id, operator_address, COUNT(*), COUNT(*) FILTER (WHERE missed),
+		AVG(latency) FILTER (WHERE NOT missed)
+		FROM (`+expectedSignersQuery+`) participation GROUP BY operator_id, operator_address ORDER BY operator_id`,
+		from, to, hashParam(operatorId))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participation []OperatorParticipation
+	for rows.Next() {
+		var operator OperatorParticipation
+		var id, address []byte
+		var responses, missed int64
+		var latency sql.NullFloat64
+		if err := rows.Scan(&id, &address, &responses, &missed, &latency); err != nil {
+			return nil, err
+		}
+		operator.OperatorId = common.BytesToHash(id)
+		operator.OperatorAddress = common.BytesToAddress(address)
+		operator.Responses, operator.Missed = uint64(responses), uint64(missed)
+		if latency.Valid {
+			operator.AverageLatency = &latency.Float64
+		}
+		participation = append(participation, operator)
+	}
+	return participation, rows.Err()
+}
+
+func (s *PostgresStore) MissedBatches(ctx context.Context, operatorId common.Hash, from time.Time, to time.Time, limit int) ([]MissedBatch, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT batch_merkle_root, sender_address, block_number, responded_at
+		FROM (`+expectedSignersQuery+`) participation WHERE missed
+		ORDER BY block_number DESC, batch_merkle_root LIMIT $4`,
+		from, to, operatorId.Bytes(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missed []MissedBatch
+	for rows.Next() {
+		var batch MissedBatch
+		var root, sender []byte
+		var blockNumber int64
+		if err := rows.Scan(&root, &sender, &blockNumber, &batch.RespondedAt); err != nil {
+			return nil, err
+		}
+		copy(batch.BatchMerkleRoot[:], root)
+		batch.SenderAddress = common.BytesToAddress(sender)
+		batch.BlockNumber = uint64(blockNumber)
+		missed = append(missed, batch)
+	}
+	return missed, rows.Err()
+}
+
+func hashParam(hash *common.Hash) interface{} {
+	if hash == nil {
+		return nil
+	}
+	return hash.Bytes()
+}
@@ -0,0 +1,139 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ProofFilter selects the proofs listed, of the latest batches first
+type ProofFilter struct {
+	Commitment         *[32]byte
+	ProofGeneratorAddr *common.Address
+	Limit              int
+	Offset             int
+}
+
+func (s *PostgresStore) BatchesWithoutProofs(ctx context.Context, limit int, maxAttempts int) ([]Batch, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT b.batch_merkle_root, b.sender_address, b.batch_data_pointer,
+		b.respond_to_task_fee_limit::TEXT, b.task_created_block, b.task_created_tx, b.task_created_at
+		FROM batches b LEFT JOIN batch_payloads p USING (batch_merkle_root, sender_address)
+		WHERE p.indexed_at IS NULL AND COALESCE(p.attempts, 0) < $2
+		ORDER BY b.task_created_block LIMIT $1`, limit, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []Batch
+	for rows.Next() {
+		var batch Batch
+		var root, sender, createdTx []byte
+		var feeLimit string
+		var createdBlock int64
+		if err := rows.Scan(&root, &sender, &batch.BatchDataPointer, &feeLimit, &createdBlock, &createdTx, &batch.TaskCreatedAt); err != nil {
+			return nil, err
+		}
+		copy(batch.BatchMerkleRoot[:], root)
+		batch.SenderAddress = common.BytesToAddress(sender)
+		batch.RespondToTaskFeeLimit, _ = new(big.Int).SetString(feeLimit, 10)
+		batch.TaskCreatedBlock = uint64(createdBlock)
+		batch.TaskCreatedTx = common.BytesToHash(createdTx)
+		batches = append(batches, batch)
+	}
+	return batches, rows.Err()
+}
+
+func (s *PostgresStore) SaveBatchProofs(ctx context.Context, batch Batch, proofs []Proof) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, proof := range proofs {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO proofs (batch_merkle_root, sender_address, index_in_batch, commitment, proving_system, proof_generator_addr,
+			proof_commitment, pub_input_commitment, proving_system_aux_data_commitment)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT DO NOTHING`,
+			proof.BatchMerkleRoot[:], proof.SenderAddress.Bytes(), int64(proof.IndexInBatch), proof.Commitment[:], proof.ProvingSystem,
+			proof.ProofGeneratorAddr.Bytes(), proof.ProofCommitment[:], proof.PubInputCommitment[:], proof.ProvingSystemAuxDataCommitment[:])
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO batch_payloads (batch_merkle_root, sender_address, indexed_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (batch_merkle_root, sender_address) DO UPDATE SET indexed_at = NOW(), last_error = NULL`,
+		batch.BatchMerkleRoot[:], batch.SenderAddress.Bytes())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) SaveBatchProofsFailure(ctx context.Context, batch Batch, reason string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO batch_payloads (batch_merkle_root, sender_address, attempts, last_error) VALUES ($1, $2, 1, $3)
+		ON CONFLICT (batch_merkle_root, sender_address) DO UPDATE SET attempts = batch_payloads.attempts + 1, last_error = EXCLUDED.last_error`,
+		batch.BatchMerkleRoot[:], batch.SenderAddress.Bytes(), reason)
+	return err
+}
+
+const proofColumns = `batch_merkle_root, sender_address, index_in_batch, commitment, proving_system, proof_generator_addr,
+	proof_commitment, pub_input_commitment, proving_system_aux_data_commitment`
+
+func (s *PostgresStore) ListProofs(ctx context.Context, filter ProofFilter) ([]Proof, error) {
+	var commitment interface{}
+	if filter.Commitment != nil {
+		commitment = filter.Commitment[:]
+	}
+	return s.queryProofs(ctx, `SELECT `+proofColumns+` FROM proofs JOIN batches USING (batch_merkle_root, sender_address)
+		WHERE ($1::BYTEA IS NULL OR commitment = $1) AND ($2::BYTEA IS NULL OR proof_generator_addr = $2)
+		ORDER BY task_created_block DESC, batch_merkle_root, index_in_batch LIMIT $3 OFFSET $4`,
+		commitment, addressParam(filter.ProofGeneratorAddr), filter.Limit, filter.Offset)
+}
+
+func (s *PostgresStore) BatchProofs(ctx context.Context, batchMerkleRoot [32]byte, senderAddress common.Address) ([]Proof, bool, error) {
+	var indexed bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM batch_payloads
+		WHERE batch_merkle_root = $1 AND sender_address = $2 AND indexed_at IS NOT NULL)`,
+		batchMerkleRoot[:], senderAddress.Bytes()).Scan(&indexed)
+	if err != nil || !indexed {
+		return nil, false, err
+	}
+	proofs, err := s.queryProofs(ctx, `SELECT `+proofColumns+` FROM proofs
+		WHERE batch_merkle_root = $1 AND sender_address = $2 ORDER BY index_in_batch`,
+		batchMerkleRoot[:], senderAddress.Bytes())
+	return proofs, err == nil, err
+}
+
+func (s *PostgresStore) queryProofs(ctx context.Context, query string, args ...interface{}) ([]Proof, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var proofs []Proof
+	for rows.Next() {
+		var proof Proof
+		var root, sender, commitment, generator, proofCommitment, pubInputCommitment, auxDataCommitment []byte
+		var index int64
+		if err := rows.Scan(&root, &sender, &index, &commitment, &proof.ProvingSystem, &generator,
+			&proofCommitment, &pubInputCommitment, &auxDataCommitment); err != nil {
+			return nil, err
+		}
+		copy(proof.BatchMerkleRoot[:], root)
+		proof.SenderAddress = common.BytesToAddress(sender)
+		proof.IndexInBatch = uint64(index)
+		copy(proof.Commitment[:], commitment)
+		proof.ProofGeneratorAddr = common.BytesToAddress(generator)
+		copy(proof.ProofCommitment[:], proofCommitment)
+		copy(proof.PubInputCommitment[:], pubInputCommitment)
+		copy(proof.ProvingSystemAuxDataCommitment[:], auxDataCommitment)
+		proofs = append(proofs, proof)
+	}
+	return proofs, rows.Err()
+}
@@ -0,0 +1,187 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/sdk/aligned"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+const (
+	// MaxBatchPayloadAttempts is the number of times the payload of a batch is downloaded before
+	// giving up on indexing its proofs
+	MaxBatchPayloadAttempts = 5
+	// proofBatchesPerRound is the number of batches whose proofs are indexed on each round
+	proofBatchesPerRound = 10
+	// batchPayloadTimeout bounds the download of the payload of a batch
+	batchPayloadTimeout = 5 * time.Minute
+)
+
+// Proof is a proof of a batch, from the payload of the batch. ProofGeneratorAddr is the address
+// the sender of the proof attributed it to, part of its commitment.
+type Proof struct {
+	BatchMerkleRoot                [32]byte
+	SenderAddress                  common.Address
+	IndexInBatch                   uint64
+	Commitment                     [32]byte
+	ProvingSystem                  string
+	ProofGeneratorAddr             common.Address
+	ProofCommitment                [32]byte
+	PubInputCommitment             [32]byte
+	ProvingSystemAuxDataCommitment [32]byte
+}
+
+// ProofStore persists the proofs of the indexed batches
+type ProofStore interface {
+	// BatchesWithoutProofs returns the batches whose proofs weren't indexed, that failed less than
+	// maxAttempts times, the oldest first
+	BatchesWithoutProofs(ctx context.Context, limit int, maxAttempts int) ([]Batch, error)
+	SaveBatchProofs(ctx context.Context, batch Batch, proofs []Proof) error
+	// SaveBatchProofsFailure records a failed attempt to index the proofs of the batch
+	SaveBatchProofsFailure(ctx context.Context, batch Batch, reason string) error
+}
+
+// PayloadSource downloads the payload of a batch
+type PayloadSource func(ctx context.Context, batchDataPointer string) ([]byte, error)
+
+// ProofIndexer indexes the proofs of the indexed batches, downloading their payloads from the data
+// service. It's separate from the event indexer so slow or missing payloads don't hold it back.
+type ProofIndexer struct {
+	logger       sdklogging.Logger
+	store        ProofStore
+	payload      PayloadSource
+	pollInterval time.Duration
+}
+
+func NewProofIndexer(indexerConfig config.IndexerConfig, store ProofStore) *ProofIndexer {
+	client := &http.Client{Timeout: batchPayloadTimeout}
+	maxBatchSize := indexerConfig.Indexer.MaxBatchSize
+	return newProofIndexer(indexerConfig.BaseConfig.Logger, store, func(ctx context.Context, batchDataPointer string) ([]byte, error) {
+		return downloadBatchPayload(ctx, client, batchDataPointer, maxBatchSize)
+	}, indexerConfig.Indexer.PollInterval)
+}
+
+func newProofIndexer(logger sdklogging.Logger, store ProofStore, payload PayloadSource, pollInterval time.Duration) *ProofIndexer {
+	return &ProofIndexer{logger: logger, store: store, payload: payload, pollInterval: pollInterval}
+}
+
+// Start indexes the proofs of the batches until the context is done
+func (p *ProofIndexer) Start(ctx context.Context) error {
+	for {
+		indexed, err := p.IndexPendingBatches(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			p.logger.Warn("Could not index proofs, retrying", "err", err)
+		}
+		// keep going while there are batches left, waiting for new ones otherwise
+		if indexed == proofBatchesPerRound {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.pollInterval):
+		}
+	}
+}
+
+// IndexPendingBatches indexes the proofs of the next batches without them, returning the number of
+// batches processed
+func (p *ProofIndexer) IndexPendingBatches(ctx context.Context) (int, error) {
+	batches, err := p.store.BatchesWithoutProofs(ctx, proofBatchesPerRound, MaxBatchPayloadAttempts)
+	if err != nil {
+		return 0, fmt.Errorf("could not get batches without proofs: %w", err)
+	}
+	for _, batch := range batches {
+		proofs, err := p.batchProofs(ctx, batch)
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			p.logger.Warn("Could not index proofs of batch", "root", hexBytes(batch.BatchMerkleRoot[:]), "err", err)
+			if err := p.store.SaveBatchProofsFailure(ctx, batch, err.Error()); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err := p.store.SaveBatchProofs(ctx, batch, proofs); err != nil {
+			return 0, fmt.Errorf("could not save proofs of batch %x: %w", batch.BatchMerkleRoot, err)
+		}
+		p.logger.Debug("Indexed proofs of batch", "root", hexBytes(batch.BatchMerkleRoot[:]), "proofs", len(proofs))
+	}
+	return len(batches), nil
+}
+
+// batchProofs downloads and decodes the payload of the batch, checking its proofs build the batch
+// merkle root
+func (p *ProofIndexer) batchProofs(ctx context.Context, batch Batch) ([]Proof, error) {
+	payload, err := p.payload(ctx, batch.BatchDataPointer)
+	if err != nil {
+		return nil, err
+	}
+	verificationData, err := batcher.DecodeBatch(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(verificationData) == 0 {
+		return nil, errors.New("empty batch")
+	}
+
+	proofs := make([]Proof, 0, len(verificationData))
+	leaves := make([][32]byte, 0, len(verificationData))
+	for i := range verificationData {
+		commitment := verificationData[i].Commitment()
+		leaf := commitment.Hash()
+		proofs = append(proofs, Proof{
+			BatchMerkleRoot:                batch.BatchMerkleRoot,
+			SenderAddress:                  batch.SenderAddress,
+			IndexInBatch:                   uint64(i),
+			Commitment:                     leaf,
+			ProvingSystem:                  verificationData[i].ProvingSystem.String(),
+			ProofGeneratorAddr:             commitment.ProofGeneratorAddr,
+			ProofCommitment:                commitment.ProofCommitment,
+			PubInputCommitment:             commitment.PubInputCommitment,
+			ProvingSystemAuxDataCommitment: commitment.ProvingSystemAuxDataCommitment,
+		})
+		leaves = append(leaves, leaf)
+	}
+	if aligned.BatchMerkleRoot(leaves) != batch.BatchMerkleRoot {
+		return nil, errors.New("payload doesn't match the batch merkle root")
+	}
+	return proofs, nil
+}
+
+// downloadBatchPayload downloads the payload of a batch of up to maxBatchSize bytes
+func downloadBatchPayload(ctx context.Context, client *http.Client, batchDataPointer string, maxBatchSize int64) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, batchDataPointer, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", response.Status)
+	}
+	payload, err := io.ReadAll(io.LimitReader(response.Body, maxBatchSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(payload)) > maxBatchSize {
+		return nil, fmt.Errorf("batch larger than %d bytes", maxBatchSize)
+	}
+	return payload, nil
+}
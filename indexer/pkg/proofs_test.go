@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	alignedcommon "github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/sdk/aligned"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+type fakeProofStore struct {
+	batches  []Batch
+	proofs   map[[32]byte][]Proof
+	failures map[[32]byte][]string
+}
+
+func (s *fakeProofStore) BatchesWithoutProofs(ctx context.Context, limit int, maxAttempts int) ([]Batch, error) {
+	var batches []Batch
+	for _, batch := range s.batches {
+		if _, ok := s.proofs[batch.BatchMerkleRoot]; !ok && len(s.failures[batch.BatchMerkleRoot]) < maxAttempts && len(batches) < limit {
+			batches = append(batches, batch)
+		}
+	}
+	return batches, nil
+}
+
+func (s *fakeProofStore) SaveBatchProofs(ctx context.Context, batch Batch, proofs []Proof) error {
+	s.proofs[batch.BatchMerkleRoot] = proofs
+	return nil
+}
+
+func (s *fakeProofStore) SaveBatchProofsFailure(ctx context.Context, batch Batch, reason string) error {
+	s.failures[batch.BatchMerkleRoot] = append(s.failures[batch.BatchMerkleRoot], reason)
+	return nil
+}
+
+func testVerificationData() []batcher.VerificationData {
+	return []batcher.VerificationData{
+		{ProvingSystem: alignedcommon.SP1, Proof: []byte{1}, VmProgramCode: []byte{2}, ProofGeneratorAddr: common.Address{0xa1}},
+		{ProvingSystem: alignedcommon.Groth16Bn254, Proof: []byte{3}, PubInput: []byte{4}, VerificationKey: []byte{5}, ProofGeneratorAddr: common.Address{0xa2}},
+		{ProvingSystem: alignedcommon.GnarkPlonkBn254, Proof: []byte{6}, PubInput: []byte{7}, VerificationKey: []byte{8}, ProofGeneratorAddr: common.Address{0xa1}},
+	}
+}
+
+func TestProofIndexerIndexesBatchPayloads(t *testing.T) {
+	verificationData := testVerificationData()
+	payload, err := batcher.EncodeBatch(verificationData)
+	if err != nil {
+		t.Fatalf("could not encode batch: %v", err)
+	}
+	var leaves [][32]byte
+	for i := range verificationData {
+		commitment := verificationData[i].Commitment()
+		leaves = append(leaves, commitment.Hash())
+	}
+	valid := Batch{BatchMerkleRoot: aligned.BatchMerkleRoot(leaves), SenderAddress: common.Address{0xb}, BatchDataPointer: "http://storage/valid.json"}
+	mismatching := Batch{BatchMerkleRoot: [32]byte{1}, SenderAddress: common.Address{0xb}, BatchDataPointer: "http://storage/mismatching.json"}
+	missing := Batch{BatchMerkleRoot: [32]byte{2}, SenderAddress: common.Address{0xb}, BatchDataPointer: "http://storage/missing.json"}
+
+	store := &fakeProofStore{batches: []Batch{valid, mismatching, missing}, proofs: make(map[[32]byte][]Proof), failures: make(map[[32]byte][]string)}
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %v", err)
+	}
+	proofIndexer := newProofIndexer(logger, store, func(ctx context.Context, batchDataPointer string) ([]byte, error) {
+		if batchDataPointer == missing.BatchDataPointer {
+			return nil, errors.New("not found")
+		}
+		return payload, nil
+	}, time.Millisecond)
+
+	for round := 0; round < MaxBatchPayloadAttempts+1; round++ {
+		if _, err := proofIndexer.IndexPendingBatches(context.Background()); err != nil {
+			t.Fatalf("could not index proofs: %v", err)
+		}
+	}
+
+	proofs := store.proofs[valid.BatchMerkleRoot]
+	if len(proofs) != 3 {
+		t.Fatalf("expected the proofs of the batch, got %+v", proofs)
+	}
+	if proofs[1].IndexInBatch != 1 || proofs[1].Commitment != leaves[1] || proofs[1].ProvingSystem != "Groth16Bn254" || proofs[1].ProofGeneratorAddr != (common.Address{0xa2}) {
+		t.Errorf("unexpected proof %+v", proofs[1])
+	}
+	for _, batch := range []Batch{mismatching, missing} {
+		if _, ok := store.proofs[batch.BatchMerkleRoot]; ok {
+			t.Errorf("proofs of %s indexed", batch.BatchDataPointer)
+		}
+		if len(store.failures[batch.BatchMerkleRoot]) != MaxBatchPayloadAttempts {
+			t.Errorf("expected %d attempts for %s, got %v", MaxBatchPayloadAttempts, batch.BatchDataPointer, store.failures[batch.BatchMerkleRoot])
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lib/pq"
+)
+
+// BatchRecord is an indexed batch with the response to its task, nil if not responded yet, and the
+// head of the chain its finality is relative to, nil before the first indexing round
+type BatchRecord struct {
+	Batch
+	Response  *Response
+	ChainHead *ChainHead
+}
+
+// Finalized returns whether the creation of the task of the batch, and its response if any, are finalized
+func (r *BatchRecord) Finalized() bool {
+	if r.ChainHead == nil {
+		return false
+	}
+	if r.Response != nil {
+		return r.ChainHead.Finalized(r.Response.BlockNumber)
+	}
+	return r.ChainHead.Finalized(r.TaskCreatedBlock)
+}
+
+// BatchFilter selects the batches listed, the latest created first
+type BatchFilter struct {
+	SenderAddress *common.Address
+	FromBlock     uint64
+	ToBlock       *uint64
+	// Responded selects the responded batches if true, the pending ones if false, and both if nil
+	Responded *bool
+	// Finalized selects the finalized batches if true, the ones that can still be reorged if false,
+	// and both if nil
+	Finalized *bool
+	Limit     int
+	Offset    int
+}
+
+// QueryStore reads the indexed batches
+type QueryStore interface {
+	ListBatches(ctx context.Context, filter BatchFilter) ([]BatchRecord, error)
+	// BatchByRoot returns the latest batch with the merkle root, of the sender if not nil, with the
+	// non signers of its response. It returns nil if there is none.
+	BatchByRoot(ctx context.Context, batchMerkleRoot [32]byte, senderAddress *common.Address) (*BatchRecord, error)
+	// NonSigners returns the ids of the operators that didn't sign the response of the batch
+	NonSigners(ctx context.Context, batchMerkleRoot [32]byte, senderAddress common.Address) ([]common.Hash, error)
+	ListOperators(ctx context.Context, filter OperatorFilter) ([]Operator, error)
+	// OperatorById returns the operator of the directory with the id, nil if there is none
+	OperatorById(ctx context.Context, operatorId common.Hash) (*Operator, error)
+	ListProofs(ctx context.Context, filter ProofFilter) ([]Proof, error)
+	// BatchProofs returns the proofs of the batch, and false if they weren't indexed yet
+	BatchProofs(ctx context.Context, batchMerkleRoot [32]byte, senderAddress common.Address) ([]Proof, bool, error)
+	// PeriodStats returns the aggregates of the periods of the range with activity
+	PeriodStats(ctx context.Context, filter AnalyticsFilter) ([]PeriodStats, error)
+	// AggregatorSpend returns the spend of the aggregators responding in the range, the highest first
+	AggregatorSpend(ctx context.Context, from time.Time, to time.Time) ([]AggregatorSpend, error)
+	// OperatorParticipation returns how the operators of the directory, or the one with the id if
+	// not nil, took part in the responses of the range, by id
+	OperatorParticipation(ctx context.Context, from time.Time, to time.Time, operatorId *common.Hash) ([]OperatorParticipation, error)
+	// MissedBatches returns the latest batches of the range whose response the operator didn't sign
+	MissedBatches(ctx context.Context, operatorId common.Hash, from time.Time, to time.Time, limit int) ([]MissedBatch, error)
+	ExportStore
+}
+
+// OperatorFilter selects the operators listed, by id
+type OperatorFilter struct {
+	// Registered selects the registered operators if true, the deregistered ones if false, and both if nil
+	Registered *bool
+	Limit      int
+	Offset     int
+}
+
+const batchRecordColumns = `b.batch_merkle_root, b.sender_address, b.batch_data_pointer, b.respond_to_task_fee_limit::TEXT,
+	b.task_created_block, b.task_created_tx, b.task_created_at, r.block_number, r.tx_hash, r.aggregator_address,
+	r.gas_used, r.effective_gas_price::TEXT, r.responded_at, h.head_block, h.finality_depth
+	FROM batches b LEFT JOIN responses r USING (batch_merkle_root, sender_address) LEFT JOIN chain_head h ON TRUE`
+
+// batchFinalizedCondition is true for the batches whose last event, the response if any, is
+// finality_depth blocks behind the head
+const batchFinalizedCondition = `COALESCE(COALESCE(r.block_number, b.task_created_block) + h.finality_depth <= h.head_block, FALSE)`
+
+func (s *PostgresStore) ListBatches(ctx context.Context, filter BatchFilter) ([]BatchRecord, error) {
+	var toBlock, responded, finalized interface{}
+	if filter.ToBlock != nil {
+		toBlock = int64(*filter.ToBlock)
+	}
+	if filter.Responded != nil {
+		responded = *filter.Responded
+	}
+	if filter.Finalized != nil {
+		finalized = *filter.Finalized
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT `+batchRecordColumns+`
+		WHERE ($1::BYTEA IS NULL OR b.sender_address = $1) AND b.task_created_block >= $2
+		AND ($3::BIGINT IS NULL OR b.task_created_block <= $3) AND ($4::BOOLEAN IS NULL OR (r.tx_hash IS NOT NULL) = $4)
+		AND ($7::BOOLEAN IS NULL OR `+batchFinalizedCondition+` = $7)
+		ORDER BY b.task_created_block DESC, b.batch_merkle_root LIMIT $5 OFFSET $6`,
+		addressParam(filter.SenderAddress), int64(filter.FromBlock), toBlock, responded, filter.Limit, filter.Offset, finalized)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []BatchRecord
+	for rows.Next() {
+		record, err := scanBatchRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (s *PostgresStore) BatchByRoot(ctx context.Context, batchMerkleRoot [32]byte, senderAddress *common.Address) (*BatchRecord, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+batchRecordColumns+`
+		WHERE b.batch_merkle_root = $1 AND ($2::BYTEA IS NULL OR b.sender_address = $2)
+		ORDER BY b.task_created_block DESC LIMIT 1`,
+		batchMerkleRoot[:], addressParam(senderAddress))
+	record, err := scanBatchRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.Response != nil {
+		if record.Response.NonSigners, err = s.NonSigners(ctx, record.BatchMerkleRoot, record.SenderAddress); err != nil {
+			return nil, err
+		}
+	}
+	return &record, nil
+}
+
+func (s *PostgresStore) NonSigners(ctx context.Context, batchMerkleRoot [32]byte, senderAddress common.Address) ([]common.Hash, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT operator_id FROM response_non_signers
+		WHERE batch_merkle_root = $1 AND sender_address = $2 ORDER BY operator_id`,
+		batchMerkleRoot[:], senderAddress.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nonSigners []common.Hash
+	for rows.Next() {
+		var operatorId []byte
+		if err := rows.Scan(&operatorId); err != nil {
+			return nil, err
+		}
+		nonSigners = append(nonSigners, common.BytesToHash(operatorId))
+	}
+	return nonSigners, rows.Err()
+}
+
+// operatorsQuery selects the operators of the directory as of their last registration and socket
+// events, filtered by registration status and id
+const operatorsQuery = `WITH last_registrations AS (
+		SELECT DISTINCT ON (operator_id) operator_id, operator_address, registered, block_number
+		FROM operator_registrations ORDER BY operator_id, block_number DESC, log_index DESC
+	), last_sockets AS (
+		SELECT DISTINCT ON (operator_id) operator_id, socket
+		FROM operator_sockets ORDER BY operator_id, block_number DESC, log_index DESC
+	)
+	SELECT r.operator_id, r.operator_address, r.registered, r.block_number, COALESCE(s.socket, ''), p.pubkey_g1
+	FROM last_registrations r LEFT JOIN last_sockets s USING (operator_id) LEFT JOIN operator_pubkeys p USING (operator_id)
+	WHERE ($1::BOOLEAN IS NULL OR r.registered = $1) AND ($2::BYTEA IS NULL OR r.operator_id = $2)
+	ORDER BY r.operator_id LIMIT $3 OFFSET $4`
+
+func (s *PostgresStore) ListOperators(ctx context.Context, filter OperatorFilter) ([]Operator, error) {
+	var registered interface{}
+	if filter.Registered != nil {
+		registered = *filter.Registered
+	}
+	return s.queryOperators(ctx, registered, nil, filter.Limit, filter.Offset)
+}
+
+func (s *PostgresStore) OperatorById(ctx context.Context, operatorId common.Hash) (*Operator, error) {
+	operators, err := s.queryOperators(ctx, nil, operatorId.Bytes(), 1, 0)
+	if err != nil || len(operators) == 0 {
+		return nil, err
+	}
+	return &operators[0], nil
+}
+
+func (s *PostgresStore) queryOperators(ctx context.Context, registered interface{}, operatorId interface{}, limit int, offset int) ([]Operator, error) {
+	rows, err := s.db.QueryContext(ctx, operatorsQuery, registered, operatorId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var operators []Operator
+	var operatorIds [][]byte
+	for rows.Next() {
+		var operator Operator
+		var id, address []byte
+		var registrationBlock int64
+		if err := rows.Scan(&id, &address, &operator.Registered, &registrationBlock, &operator.Socket, &operator.PubkeyG1); err != nil {
+			return nil, err
+		}
+		operator.Id = common.BytesToHash(id)
+		operator.Address = common.BytesToAddress(address)
+		operator.RegistrationBlock = uint64(registrationBlock)
+		operators = append(operators, operator)
+		operatorIds = append(operatorIds, id)
+	}
+	if err := rows.Err(); err != nil || len(operators) == 0 {
+		return operators, err
+	}
+
+	stakes, err := s.operatorStakes(ctx, operatorIds)
+	if err != nil {
+		return nil, err
+	}
+	for i := range operators {
+		operators[i].Stakes = stakes[operators[i].Id]
+	}
+	return operators, nil
+}
+
+// operatorStakes returns the last stake of the operators in each quorum
+func (s *PostgresStore) operatorStakes(ctx context.Context, operatorIds [][]byte) (map[common.Hash][]QuorumStake, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT ON (operator_id, quorum_number) operator_id, quorum_number, stake::TEXT
+		FROM operator_stakes WHERE operator_id = ANY($1)
+		ORDER BY operator_id, quorum_number, block_number DESC, log_index DESC`,
+		pq.ByteaArray(operatorIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stakes := make(map[common.Hash][]QuorumStake)
+	for rows.Next() {
+		var id []byte
+		var quorumNumber int16
+		var stake string
+		if err := rows.Scan(&id, &quorumNumber, &stake); err != nil {
+			return nil, err
+		}
+		operatorId := common.BytesToHash(id)
+		stakeValue, _ := new(big.Int).SetString(stake, 10)
+		stakes[operatorId] = append(stakes[operatorId], QuorumStake{QuorumNumber: uint8(quorumNumber), Stake: stakeValue})
+	}
+	return stakes, rows.Err()
+}
+
+func addressParam(address *common.Address) interface{} {
+	if address == nil {
+		return nil
+	}
+	return address.Bytes()
+}
+
+func scanBatchRecord(row interface{ Scan(...interface{}) error }) (BatchRecord, error) {
+	var record BatchRecord
+	var root, sender, createdTx, respondedTx, aggregator []byte
+	var feeLimit string
+	var createdBlock int64
+	var respondedBlock, gasUsed, headBlock, finalityDepth sql.NullInt64
+	var effectiveGasPrice sql.NullString
+	var respondedAt sql.NullTime
+	err := row.Scan(&root, &sender, &record.BatchDataPointer, &feeLimit, &createdBlock, &createdTx, &record.TaskCreatedAt,
+		&respondedBlock, &respondedTx, &aggregator, &gasUsed, &effectiveGasPrice, &respondedAt, &headBlock, &finalityDepth)
+	if err != nil {
+		return BatchRecord{}, err
+	}
+	copy(record.BatchMerkleRoot[:], root)
+	record.SenderAddress = common.BytesToAddress(sender)
+	record.RespondToTaskFeeLimit, _ = new(big.Int).SetString(feeLimit, 10)
+	record.TaskCreatedBlock = uint64(createdBlock)
+	record.TaskCreatedTx = common.BytesToHash(createdTx)
+	if respondedBlock.Valid {
+		gasPrice, _ := new(big.Int).SetString(effectiveGasPrice.String, 10)
+		record.Response = &Response{
+			BatchMerkleRoot:   record.BatchMerkleRoot,
+			SenderAddress:     record.SenderAddress,
+			BlockNumber:       uint64(respondedBlock.Int64),
+			TxHash:            common.BytesToHash(respondedTx),
+			AggregatorAddress: common.BytesToAddress(aggregator),
+			GasUsed:           uint64(gasUsed.Int64),
+			EffectiveGasPrice: gasPrice,
+			RespondedAt:       respondedAt.Time,
+		}
+	}
+	if headBlock.Valid {
+		record.ChainHead = &ChainHead{Number: uint64(headBlock.Int64), FinalityDepth: uint64(finalityDepth.Int64)}
+	}
+	return record, nil
+}
@@ -0,0 +1,150 @@
+-- Blocks the indexer went through, with events or at the end of a range, to detect reorgs
+CREATE TABLE IF NOT EXISTS blocks (
+    number      BIGINT PRIMARY KEY,
+    hash        BYTEA NOT NULL,
+    "timestamp" TIMESTAMPTZ NOT NULL
+);
+
+-- Head of the chain on the last indexing round, the rows of the blocks at least finality_depth
+-- blocks behind it being finalized
+CREATE TABLE IF NOT EXISTS chain_head (
+    id             BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    head_block     BIGINT NOT NULL,
+    finality_depth BIGINT NOT NULL
+);
+
+-- Batches whose task was created, from the NewBatchV3 events
+CREATE TABLE IF NOT EXISTS batches (
+    batch_merkle_root         BYTEA NOT NULL,
+    sender_address            BYTEA NOT NULL,
+    batch_data_pointer        TEXT NOT NULL,
+    respond_to_task_fee_limit NUMERIC(78, 0) NOT NULL,
+    task_created_block        BIGINT NOT NULL,
+    task_created_tx           BYTEA NOT NULL,
+    task_created_at           TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (batch_merkle_root, sender_address)
+);
+CREATE INDEX IF NOT EXISTS batches_task_created_block ON batches (task_created_block);
+
+-- Responses to the tasks of the batches, from the BatchVerified events and their receipts
+CREATE TABLE IF NOT EXISTS responses (
+    batch_merkle_root   BYTEA NOT NULL,
+    sender_address      BYTEA NOT NULL,
+    block_number        BIGINT NOT NULL,
+    tx_hash             BYTEA NOT NULL,
+    aggregator_address  BYTEA NOT NULL,
+    gas_used            BIGINT NOT NULL,
+    effective_gas_price NUMERIC(78, 0) NOT NULL,
+    responded_at        TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (batch_merkle_root, sender_address)
+);
+CREATE INDEX IF NOT EXISTS responses_block_number ON responses (block_number);
+
+-- Operators that didn't sign the responses, from their calldata, removed with their response
+CREATE TABLE IF NOT EXISTS response_non_signers (
+    batch_merkle_root BYTEA NOT NULL,
+    sender_address    BYTEA NOT NULL,
+    operator_id       BYTEA NOT NULL,
+    PRIMARY KEY (batch_merkle_root, sender_address, operator_id),
+    FOREIGN KEY (batch_merkle_root, sender_address) REFERENCES responses ON DELETE CASCADE
+);
+
+-- Registrations and deregistrations of the operators in the registry coordinator
+CREATE TABLE IF NOT EXISTS operator_registrations (
+    block_number     BIGINT NOT NULL,
+    log_index        BIGINT NOT NULL,
+    operator_id      BYTEA NOT NULL,
+    operator_address BYTEA NOT NULL,
+    registered       BOOLEAN NOT NULL,
+    tx_hash          BYTEA NOT NULL,
+    PRIMARY KEY (block_number, log_index)
+);
+CREATE INDEX IF NOT EXISTS operator_registrations_operator_id ON operator_registrations (operator_id, block_number);
+
+-- BLS public keys of the operators, registered once per operator in the BLS apk registry
+CREATE TABLE IF NOT EXISTS operator_pubkeys (
+    operator_id      BYTEA PRIMARY KEY,
+    operator_address BYTEA NOT NULL,
+    pubkey_g1        BYTEA NOT NULL,
+    pubkey_g2        BYTEA NOT NULL,
+    block_number     BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS operator_pubkeys_block_number ON operator_pubkeys (block_number);
+
+-- Stake updates of the operators in the quorums of the stake registry
+CREATE TABLE IF NOT EXISTS operator_stakes (
+    block_number  BIGINT NOT NULL,
+    log_index     BIGINT NOT NULL,
+    operator_id   BYTEA NOT NULL,
+    quorum_number SMALLINT NOT NULL,
+    stake         NUMERIC(78, 0) NOT NULL,
+    PRIMARY KEY (block_number, log_index)
+);
+CREATE INDEX IF NOT EXISTS operator_stakes_operator_id ON operator_stakes (operator_id, quorum_number, block_number);
+
+-- Socket updates of the operators in the registry coordinator
+CREATE TABLE IF NOT EXISTS operator_sockets (
+    block_number BIGINT NOT NULL,
+    log_index    BIGINT NOT NULL,
+    operator_id  BYTEA NOT NULL,
+    socket       TEXT NOT NULL,
+    PRIMARY KEY (block_number, log_index)
+);
+CREATE INDEX IF NOT EXISTS operator_sockets_operator_id ON operator_sockets (operator_id, block_number);
+
+-- Proofs of the batches, from their payloads, removed with their batch
+CREATE TABLE IF NOT EXISTS proofs (
+    batch_merkle_root                  BYTEA NOT NULL,
+    sender_address                     BYTEA NOT NULL,
+    index_in_batch                     BIGINT NOT NULL,
+    commitment                         BYTEA NOT NULL,
+    proving_system                     TEXT NOT NULL,
+    proof_generator_addr               BYTEA NOT NULL,
+    proof_commitment                   BYTEA NOT NULL,
+    pub_input_commitment               BYTEA NOT NULL,
+    proving_system_aux_data_commitment BYTEA NOT NULL,
+    PRIMARY KEY (batch_merkle_root, sender_address, index_in_batch),
+    FOREIGN KEY (batch_merkle_root, sender_address) REFERENCES batches ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS proofs_commitment ON proofs (commitment);
+CREATE INDEX IF NOT EXISTS proofs_proof_generator_addr ON proofs (proof_generator_addr);
+
+-- Attempts to index the proofs of the batches, indexed_at being set once they are
+CREATE TABLE IF NOT EXISTS batch_payloads (
+    batch_merkle_root BYTEA NOT NULL,
+    sender_address    BYTEA NOT NULL,
+    attempts          INTEGER NOT NULL DEFAULT 0,
+    last_error        TEXT,
+    indexed_at        TIMESTAMPTZ,
+    PRIMARY KEY (batch_merkle_root, sender_address),
+    FOREIGN KEY (batch_merkle_root, sender_address) REFERENCES batches ON DELETE CASCADE
+);
+
+-- Webhooks called when a proof of the commitment, or attributed to the proof generator address, is verified
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id                   BIGSERIAL PRIMARY KEY,
+    url                  TEXT NOT NULL,
+    commitment           BYTEA,
+    proof_generator_addr BYTEA,
+    secret               TEXT NOT NULL,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((commitment IS NULL) <> (proof_generator_addr IS NULL))
+);
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_commitment ON webhook_subscriptions (commitment);
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_proof_generator_addr ON webhook_subscriptions (proof_generator_addr);
+
+-- Webhooks to send for the verified proofs, retried until delivered or out of attempts
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id                BIGSERIAL PRIMARY KEY,
+    subscription_id   BIGINT NOT NULL REFERENCES webhook_subscriptions ON DELETE CASCADE,
+    batch_merkle_root BYTEA NOT NULL,
+    sender_address    BYTEA NOT NULL,
+    index_in_batch    BIGINT NOT NULL,
+    attempts          INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at      TIMESTAMPTZ,
+    last_error        TEXT,
+    UNIQUE (subscription_id, batch_merkle_root, sender_address, index_in_batch),
+    FOREIGN KEY (batch_merkle_root, sender_address, index_in_batch) REFERENCES proofs ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE delivered_at IS NULL;
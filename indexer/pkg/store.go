@@ -0,0 +1,246 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	_ "github.com/lib/pq"
+)
+
+//go:embed schema.sql
+var schema string
+
+// Block is a block the indexer went through
+type Block struct {
+	Number    uint64
+	Hash      common.Hash
+	Timestamp time.Time
+}
+
+// Batch is a batch whose task was created in the service manager
+type Batch struct {
+	BatchMerkleRoot       [32]byte
+	SenderAddress         common.Address
+	BatchDataPointer      string
+	RespondToTaskFeeLimit *big.Int
+	TaskCreatedBlock      uint64
+	TaskCreatedTx         common.Hash
+	TaskCreatedAt         time.Time
+}
+
+// Response is the response of the aggregator to the task of a batch
+type Response struct {
+	BatchMerkleRoot   [32]byte
+	SenderAddress     common.Address
+	BlockNumber       uint64
+	TxHash            common.Hash
+	AggregatorAddress common.Address
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	RespondedAt       time.Time
+	// NonSigners are the ids of the operators that didn't sign the batch, from the calldata of the response
+	NonSigners []common.Hash
+}
+
+// ChainHead is the head of the chain on the last indexing round, and the depth past which the
+// indexed rows are finalized
+type ChainHead struct {
+	Number        uint64
+	FinalityDepth uint64
+}
+
+// Confirmations returns the number of blocks on top of the block
+func (h *ChainHead) Confirmations(block uint64) uint64 {
+	if block > h.Number {
+		return 0
+	}
+	return h.Number - block
+}
+
+// Finalized returns whether the block is deep enough not to be replaced by a reorg
+func (h *ChainHead) Finalized(block uint64) bool {
+	return block <= h.Number && h.Confirmations(block) >= h.FinalityDepth
+}
+
+// IndexedRange is what the indexer read from a range of blocks, saved at once
+type IndexedRange struct {
+	// Blocks are the blocks with events and the last block of the range
+	Blocks        []Block
+	Batches       []Batch
+	Responses     []Response
+	Registrations []OperatorRegistration
+	Pubkeys       []OperatorPubkey
+	Stakes        []OperatorStake
+	Sockets       []OperatorSocket
+}
+
+// Store persists the indexed events
+type Store interface {
+	// LastIndexedBlock returns the last block saved, nil if none was
+	LastIndexedBlock(ctx context.Context) (*Block, error)
+	SaveRange(ctx context.Context, indexedRange IndexedRange) error
+	// RollbackFrom removes what was indexed from the block on
+	RollbackFrom(ctx context.Context, block uint64) error
+	SaveChainHead(ctx context.Context, head ChainHead) error
+}
+
+// PostgresStore stores the indexed events in Postgres
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore connects to the database and creates the tables of the indexer if missing
+func NewPostgresStore(ctx context.Context, databaseUrl string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", databaseUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not create tables: %w", err)
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *PostgresStore) LastIndexedBlock(ctx context.Context) (*Block, error) {
+	var block Block
+	var number int64
+	var hash []byte
+	err := s.db.QueryRowContext(ctx, `SELECT number, hash, "timestamp" FROM blocks ORDER BY number DESC LIMIT 1`).Scan(&number, &hash, &block.Timestamp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	block.Number = uint64(number)
+	block.Hash = common.BytesToHash(hash)
+	return &block, nil
+}
+
+func (s *PostgresStore) SaveRange(ctx context.Context, indexedRange IndexedRange) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, block := range indexedRange.Blocks {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO blocks (number, hash, "timestamp") VALUES ($1, $2, $3)
+			ON CONFLICT (number) DO UPDATE SET hash = EXCLUDED.hash, "timestamp" = EXCLUDED."timestamp"`,
+			int64(block.Number), block.Hash.Bytes(), block.Timestamp)
+		if err != nil {
+			return fmt.Errorf("could not save block %d: %w", block.Number, err)
+		}
+	}
+	for _, batch := range indexedRange.Batches {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO batches (batch_merkle_root, sender_address, batch_data_pointer, respond_to_task_fee_limit, task_created_block, task_created_tx, task_created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT DO NOTHING`,
+			batch.BatchMerkleRoot[:], batch.SenderAddress.Bytes(), batch.BatchDataPointer, batch.RespondToTaskFeeLimit.String(),
+			int64(batch.TaskCreatedBlock), batch.TaskCreatedTx.Bytes(), batch.TaskCreatedAt)
+		if err != nil {
+			return fmt.Errorf("could not save batch %x: %w", batch.BatchMerkleRoot, err)
+		}
+	}
+	for _, response := range indexedRange.Responses {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO responses (batch_merkle_root, sender_address, block_number, tx_hash, aggregator_address, gas_used, effective_gas_price, responded_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT DO NOTHING`,
+			response.BatchMerkleRoot[:], response.SenderAddress.Bytes(), int64(response.BlockNumber), response.TxHash.Bytes(),
+			response.AggregatorAddress.Bytes(), int64(response.GasUsed), response.EffectiveGasPrice.String(), response.RespondedAt)
+		if err != nil {
+			return fmt.Errorf("could not save response of batch %x: %w", response.BatchMerkleRoot, err)
+		}
+	}
+	if err := saveOperatorEvents(ctx, tx, indexedRange); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveOperatorEvents(ctx context.Context, tx *sql.Tx, indexedRange IndexedRange) error {
+	for _, registration := range indexedRange.Registrations {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO operator_registrations (block_number, log_index, operator_id, operator_address, registered, tx_hash)
+			VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT DO NOTHING`,
+			int64(registration.BlockNumber), int64(registration.LogIndex), registration.OperatorId.Bytes(),
+			registration.OperatorAddress.Bytes(), registration.Registered, registration.TxHash.Bytes())
+		if err != nil {
+			return fmt.Errorf("could not save registration of operator %s: %w", registration.OperatorId.Hex(), err)
+		}
+	}
+	for _, pubkey := range indexedRange.Pubkeys {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO operator_pubkeys (operator_id, operator_address, pubkey_g1, pubkey_g2, block_number)
+			VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+			pubkey.OperatorId.Bytes(), pubkey.OperatorAddress.Bytes(), pubkey.PubkeyG1, pubkey.PubkeyG2, int64(pubkey.BlockNumber))
+		if err != nil {
+			return fmt.Errorf("could not save pubkey of operator %s: %w", pubkey.OperatorId.Hex(), err)
+		}
+	}
+	for _, stake := range indexedRange.Stakes {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO operator_stakes (block_number, log_index, operator_id, quorum_number, stake)
+			VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+			int64(stake.BlockNumber), int64(stake.LogIndex), stake.OperatorId.Bytes(), int16(stake.QuorumNumber), stake.Stake.String())
+		if err != nil {
+			return fmt.Errorf("could not save stake of operator %s: %w", stake.OperatorId.Hex(), err)
+		}
+	}
+	for _, socket := range indexedRange.Sockets {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO operator_sockets (block_number, log_index, operator_id, socket) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+			int64(socket.BlockNumber), int64(socket.LogIndex), socket.OperatorId.Bytes(), socket.Socket)
+		if err != nil {
+			return fmt.Errorf("could not save socket of operator %s: %w", socket.OperatorId.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func (s *PostgresStore) SaveChainHead(ctx context.Context, head ChainHead) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO chain_head (head_block, finality_depth) VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET head_block = EXCLUDED.head_block, finality_depth = EXCLUDED.finality_depth`,
+		int64(head.Number), int64(head.FinalityDepth))
+	return err
+}
+
+func (s *PostgresStore) RollbackFrom(ctx context.Context, block uint64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	statements := []string{
+		`DELETE FROM responses WHERE block_number >= $1`,
+		`DELETE FROM batches WHERE task_created_block >= $1`,
+		`DELETE FROM operator_registrations WHERE block_number >= $1`,
+		`DELETE FROM operator_pubkeys WHERE block_number >= $1`,
+		`DELETE FROM operator_stakes WHERE block_number >= $1`,
+		`DELETE FROM operator_sockets WHERE block_number >= $1`,
+		`DELETE FROM blocks WHERE number >= $1`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, int64(block)); err != nil {
+			return fmt.Errorf("could not roll back from block %d: %w", block, err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,92 @@
+package pkg
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (s *PostgresStore) CreateSubscription(ctx context.Context, subscription Subscription) (int64, error) {
+	var commitment interface{}
+	if subscription.Commitment != nil {
+		commitment = subscription.Commitment[:]
+	}
+	var id int64
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO webhook_subscriptions (url, commitment, proof_generator_addr, secret) VALUES ($1, $2, $3, $4) RETURNING id`,
+		subscription.Url, commitment, addressParam(subscription.ProofGeneratorAddr), subscription.Secret).Scan(&id)
+	return id, err
+}
+
+func (s *PostgresStore) DeleteSubscription(ctx context.Context, id int64, secret string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1 AND secret = $2`, id, secret)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := result.RowsAffected()
+	return deleted > 0, err
+}
+
+// EnqueueWebhooks matches the subscriptions with the indexed proofs of the batches responded after
+// they were created
+func (s *PostgresStore) EnqueueWebhooks(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO webhook_deliveries (subscription_id, batch_merkle_root, sender_address, index_in_batch)
+		SELECT ws.id, p.batch_merkle_root, p.sender_address, p.index_in_batch
+		FROM webhook_subscriptions ws
+		JOIN proofs p ON p.commitment = ws.commitment OR p.proof_generator_addr = ws.proof_generator_addr
+		JOIN responses r USING (batch_merkle_root, sender_address)
+		WHERE r.responded_at >= ws.created_at
+		ON CONFLICT DO NOTHING`)
+	return err
+}
+
+func (s *PostgresStore) DueWebhooks(ctx context.Context, limit int, maxAttempts int) ([]WebhookDelivery, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT d.id, d.subscription_id, ws.url, ws.secret, d.attempts,
+		p.batch_merkle_root, p.sender_address, p.index_in_batch, p.commitment, p.proof_generator_addr,
+		r.block_number, r.tx_hash, r.responded_at
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions ws ON ws.id = d.subscription_id
+		JOIN proofs p USING (batch_merkle_root, sender_address, index_in_batch)
+		JOIN responses r USING (batch_merkle_root, sender_address)
+		WHERE d.delivered_at IS NULL AND d.attempts < $2 AND d.next_attempt_at <= NOW()
+		ORDER BY d.next_attempt_at LIMIT $1`, limit, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		var delivery WebhookDelivery
+		var root, sender, commitment, generator, txHash []byte
+		var index, blockNumber int64
+		err := rows.Scan(&delivery.Id, &delivery.SubscriptionId, &delivery.Url, &delivery.Secret, &delivery.Attempts,
+			&root, &sender, &index, &commitment, &generator, &blockNumber, &txHash, &delivery.Response.RespondedAt)
+		if err != nil {
+			return nil, err
+		}
+		copy(delivery.Proof.BatchMerkleRoot[:], root)
+		delivery.Proof.SenderAddress = common.BytesToAddress(sender)
+		delivery.Proof.IndexInBatch = uint64(index)
+		copy(delivery.Proof.Commitment[:], commitment)
+		delivery.Proof.ProofGeneratorAddr = common.BytesToAddress(generator)
+		delivery.Response.BatchMerkleRoot = delivery.Proof.BatchMerkleRoot
+		delivery.Response.SenderAddress = delivery.Proof.SenderAddress
+		delivery.Response.BlockNumber = uint64(blockNumber)
+		delivery.Response.TxHash = common.BytesToHash(txHash)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (s *PostgresStore) SaveWebhookDelivered(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE webhook_deliveries SET attempts = attempts + 1, delivered_at = NOW(), last_error = NULL WHERE id = $1`, id)
+	return err
+}
+
+func (s *PostgresStore) SaveWebhookFailure(ctx context.Context, id int64, reason string, nextAttempt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE webhook_deliveries SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3 WHERE id = $1`,
+		id, nextAttempt, reason)
+	return err
+}
@@ -0,0 +1,211 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// SignatureHeader is the header of the webhook requests with the HMAC-SHA256 of their body,
+	// keyed with the secret of the subscription, as sha256=<hex>
+	SignatureHeader = "X-Aligned-Signature"
+	// DeliveryHeader is the header of the webhook requests with the id of the delivery, the same
+	// on its retries
+	DeliveryHeader = "X-Aligned-Delivery"
+	// MaxWebhookAttempts is the number of times a webhook is sent before giving up on it
+	MaxWebhookAttempts = 8
+	// webhookRetryBase and webhookRetryMax bound the exponential backoff between webhook attempts
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = time.Hour
+	// webhookTimeout bounds each webhook request
+	webhookTimeout = 10 * time.Second
+	// webhooksPerRound is the number of webhooks sent on each round
+	webhooksPerRound = 100
+)
+
+// Subscription is a webhook called when a batch with a proof of the commitment, or attributed to
+// the proof generator address, is verified. One of them is set.
+type Subscription struct {
+	Id                 int64
+	Url                string
+	Commitment         *[32]byte
+	ProofGeneratorAddr *common.Address
+	// Secret keys the signatures of the webhook requests
+	Secret    string
+	CreatedAt time.Time
+}
+
+// WebhookDelivery is a webhook to send for a verified proof, with what was attempted so far
+type WebhookDelivery struct {
+	Id             int64
+	SubscriptionId int64
+	Url            string
+	Secret         string
+	Proof          Proof
+	Response       Response
+	Attempts       int
+}
+
+// WebhookPayload is the body of the webhook requests
+type WebhookPayload struct {
+	Event               string    `json:"event"`
+	SubscriptionId      int64     `json:"subscription_id"`
+	BatchMerkleRoot     string    `json:"batch_merkle_root"`
+	SenderAddress       string    `json:"sender_address"`
+	Commitment          string    `json:"commitment"`
+	ProofGeneratorAddr  string    `json:"proof_generator_addr"`
+	IndexInBatch        uint64    `json:"index_in_batch"`
+	ResponseTxHash      string    `json:"response_tx_hash"`
+	ResponseBlockNumber uint64    `json:"response_block_number"`
+	RespondedAt         time.Time `json:"responded_at"`
+}
+
+// SubscriptionStore persists the webhook subscriptions and their deliveries
+type SubscriptionStore interface {
+	CreateSubscription(ctx context.Context, subscription Subscription) (int64, error)
+	// DeleteSubscription deletes the subscription if the secret is its own, returning false otherwise
+	DeleteSubscription(ctx context.Context, id int64, secret string) (bool, error)
+	// EnqueueWebhooks creates the deliveries of the proofs verified after their subscription
+	EnqueueWebhooks(ctx context.Context) error
+	// DueWebhooks returns the deliveries to attempt, not delivered yet and due for a retry
+	DueWebhooks(ctx context.Context, limit int, maxAttempts int) ([]WebhookDelivery, error)
+	SaveWebhookDelivered(ctx context.Context, id int64) error
+	SaveWebhookFailure(ctx context.Context, id int64, reason string, nextAttempt time.Time) error
+}
+
+// WebhookNotifier sends the webhooks of the subscriptions whose proofs were verified
+type WebhookNotifier struct {
+	logger       sdklogging.Logger
+	store        SubscriptionStore
+	client       *http.Client
+	pollInterval time.Duration
+}
+
+func NewWebhookNotifier(logger sdklogging.Logger, store SubscriptionStore, pollInterval time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{logger: logger, store: store, client: &http.Client{Timeout: webhookTimeout}, pollInterval: pollInterval}
+}
+
+// Start sends the webhooks until the context is done
+func (n *WebhookNotifier) Start(ctx context.Context) error {
+	for {
+		if err := n.NotifyPending(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			n.logger.Warn("Could not send webhooks, retrying", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(n.pollInterval):
+		}
+	}
+}
+
+// NotifyPending enqueues the webhooks of the newly verified proofs and sends the due ones
+func (n *WebhookNotifier) NotifyPending(ctx context.Context) error {
+	if err := n.store.EnqueueWebhooks(ctx); err != nil {
+		return fmt.Errorf("could not enqueue webhooks: %w", err)
+	}
+	deliveries, err := n.store.DueWebhooks(ctx, webhooksPerRound, MaxWebhookAttempts)
+	if err != nil {
+		return fmt.Errorf("could not get due webhooks: %w", err)
+	}
+	for _, delivery := range deliveries {
+		if err := n.send(ctx, delivery); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			nextAttempt := time.Now().Add(webhookBackoff(delivery.Attempts + 1))
+			n.logger.Debug("Could not send webhook", "subscription", delivery.SubscriptionId, "delivery", delivery.Id, "err", err)
+			if err := n.store.SaveWebhookFailure(ctx, delivery.Id, err.Error(), nextAttempt); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := n.store.SaveWebhookDelivered(ctx, delivery.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *WebhookNotifier) send(ctx context.Context, delivery WebhookDelivery) error {
+	body, err := json.Marshal(WebhookPayload{
+		Event:               "proof_verified",
+		SubscriptionId:      delivery.SubscriptionId,
+		BatchMerkleRoot:     hexBytes(delivery.Proof.BatchMerkleRoot[:]),
+		SenderAddress:       delivery.Proof.SenderAddress.Hex(),
+		Commitment:          hexBytes(delivery.Proof.Commitment[:]),
+		ProofGeneratorAddr:  delivery.Proof.ProofGeneratorAddr.Hex(),
+		IndexInBatch:        delivery.Proof.IndexInBatch,
+		ResponseTxHash:      delivery.Response.TxHash.Hex(),
+		ResponseBlockNumber: delivery.Response.BlockNumber,
+		RespondedAt:         delivery.Response.RespondedAt,
+	})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, SignWebhookPayload(delivery.Secret, body))
+	request.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.Id, 10))
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the signature header of a webhook body, for receivers to check it
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff returns the time to wait before the attempt, doubling on each one
+func webhookBackoff(attempt int) time.Duration {
+	backoff := webhookRetryBase
+	for i := 1; i < attempt && backoff < webhookRetryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookRetryMax)
+}
+
+func newSubscriptionSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func validWebhookUrl(rawUrl string) bool {
+	webhookUrl, err := url.Parse(rawUrl)
+	return err == nil && (webhookUrl.Scheme == "http" || webhookUrl.Scheme == "https") && webhookUrl.Host != ""
+}
@@ -0,0 +1,235 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type fakeSubscriptionStore struct {
+	subscriptions map[int64]Subscription
+	deliveries    []WebhookDelivery
+	delivered     map[int64]bool
+	failures      map[int64][]time.Time
+}
+
+func newFakeSubscriptionStore() *fakeSubscriptionStore {
+	return &fakeSubscriptionStore{
+		subscriptions: make(map[int64]Subscription),
+		delivered:     make(map[int64]bool),
+		failures:      make(map[int64][]time.Time),
+	}
+}
+
+func (s *fakeSubscriptionStore) CreateSubscription(ctx context.Context, subscription Subscription) (int64, error) {
+	subscription.Id = int64(len(s.subscriptions) + 1)
+	s.subscriptions[subscription.Id] = subscription
+	return subscription.Id, nil
+}
+
+func (s *fakeSubscriptionStore) DeleteSubscription(ctx context.Context, id int64, secret string) (bool, error) {
+	subscription, ok := s.subscriptions[id]
+	if !ok || subscription.Secret != secret {
+		return false, nil
+	}
+	delete(s.subscriptions, id)
+	return true, nil
+}
+
+func (s *fakeSubscriptionStore) EnqueueWebhooks(ctx context.Context) error {
+	return nil
+}
+
+func (s *fakeSubscriptionStore) DueWebhooks(ctx context.Context, limit int, maxAttempts int) ([]WebhookDelivery, error) {
+	var due []WebhookDelivery
+	for _, delivery := range s.deliveries {
+		delivery.Attempts = len(s.failures[delivery.Id])
+		if !s.delivered[delivery.Id] && delivery.Attempts < maxAttempts && len(due) < limit {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (s *fakeSubscriptionStore) SaveWebhookDelivered(ctx context.Context, id int64) error {
+	s.delivered[id] = true
+	return nil
+}
+
+func (s *fakeSubscriptionStore) SaveWebhookFailure(ctx context.Context, id int64, reason string, nextAttempt time.Time) error {
+	s.failures[id] = append(s.failures[id], nextAttempt)
+	return nil
+}
+
+func newTestLogger(t *testing.T) sdklogging.Logger {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %v", err)
+	}
+	return logger
+}
+
+func TestWebhookNotifierSignsAndRetries(t *testing.T) {
+	var received []WebhookPayload
+	failures := 1
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != SignWebhookPayload("secret", body) {
+			t.Errorf("unexpected signature %s", r.Header.Get(SignatureHeader))
+		}
+		if r.Header.Get(DeliveryHeader) != "1" {
+			t.Errorf("unexpected delivery id %s", r.Header.Get(DeliveryHeader))
+		}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var payload WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("could not decode webhook: %v", err)
+		}
+		received = append(received, payload)
+	}))
+	defer receiver.Close()
+
+	store := newFakeSubscriptionStore()
+	proof := Proof{BatchMerkleRoot: [32]byte{1}, SenderAddress: common.Address{2}, Commitment: [32]byte{3}, ProofGeneratorAddr: common.Address{4}, IndexInBatch: 5}
+	store.deliveries = []WebhookDelivery{{
+		Id:             1,
+		SubscriptionId: 7,
+		Url:            receiver.URL,
+		Secret:         "secret",
+		Proof:          proof,
+		Response:       Response{TxHash: common.Hash{6}, BlockNumber: 10},
+	}}
+	notifier := NewWebhookNotifier(newTestLogger(t), store, time.Second)
+
+	before := time.Now()
+	if err := notifier.NotifyPending(context.Background()); err != nil {
+		t.Fatalf("could not notify: %v", err)
+	}
+	if store.delivered[1] || len(store.failures[1]) != 1 {
+		t.Fatalf("expected a failed attempt, got %v", store.failures)
+	}
+	if retry := store.failures[1][0].Sub(before); retry < webhookRetryBase {
+		t.Errorf("expected a retry after at least %v, got %v", webhookRetryBase, retry)
+	}
+
+	if err := notifier.NotifyPending(context.Background()); err != nil {
+		t.Fatalf("could not notify: %v", err)
+	}
+	if !store.delivered[1] || len(received) != 1 {
+		t.Fatalf("expected the webhook to be delivered on retry")
+	}
+	payload := received[0]
+	if payload.Event != "proof_verified" || payload.SubscriptionId != 7 || payload.IndexInBatch != 5 ||
+		payload.Commitment != hexBytes(proof.Commitment[:]) || payload.ProofGeneratorAddr != proof.ProofGeneratorAddr.Hex() ||
+		payload.ResponseBlockNumber != 10 {
+		t.Errorf("unexpected webhook payload %+v", payload)
+	}
+}
+
+func TestWebhookNotifierGivesUp(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	store := newFakeSubscriptionStore()
+	store.deliveries = []WebhookDelivery{{Id: 1, Url: receiver.URL, Secret: "secret"}}
+	notifier := NewWebhookNotifier(newTestLogger(t), store, time.Second)
+	for i := 0; i < MaxWebhookAttempts+2; i++ {
+		if err := notifier.NotifyPending(context.Background()); err != nil {
+			t.Fatalf("could not notify: %v", err)
+		}
+	}
+	if len(store.failures[1]) != MaxWebhookAttempts {
+		t.Errorf("expected %d attempts, got %d", MaxWebhookAttempts, len(store.failures[1]))
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	expected := map[int]time.Duration{
+		1:  webhookRetryBase,
+		2:  2 * webhookRetryBase,
+		3:  4 * webhookRetryBase,
+		20: webhookRetryMax,
+	}
+	for attempt, backoff := range expected {
+		if got := webhookBackoff(attempt); got != backoff {
+			t.Errorf("expected a backoff of %v for attempt %d, got %v", backoff, attempt, got)
+		}
+	}
+}
+
+func TestAPISubscriptions(t *testing.T) {
+	store := newFakeSubscriptionStore()
+	server := httptest.NewServer(newAPI(newTestLogger(t), &fakeQueryStore{}, store, nil).Handler())
+	defer server.Close()
+
+	subscribe := func(body string) *http.Response {
+		response, err := http.Post(server.URL+"/subscriptions", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("could not subscribe: %v", err)
+		}
+		return response
+	}
+
+	invalid := []string{
+		`{"url": "ftp://example.com", "proof_generator_addr": "0x0000000000000000000000000000000000000004"}`,
+		`{"url": "https://example.com/hook"}`,
+		`{"url": "https://example.com/hook", "commitment": "0x01", "proof_generator_addr": "0x0000000000000000000000000000000000000004"}`,
+		`{"url": "https://example.com/hook", "commitment": "0x01"}`,
+	}
+	for _, body := range invalid {
+		response := subscribe(body)
+		response.Body.Close()
+		if response.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected status %d for %s, got %d", http.StatusBadRequest, body, response.StatusCode)
+		}
+	}
+
+	response := subscribe(`{"url": "https://example.com/hook", "proof_generator_addr": "0x0000000000000000000000000000000000000004"}`)
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, response.StatusCode)
+	}
+	var created SubscriptionResponse
+	if err := json.NewDecoder(response.Body).Decode(&created); err != nil {
+		t.Fatalf("could not decode subscription: %v", err)
+	}
+	subscription := store.subscriptions[created.Id]
+	if created.Secret == "" || subscription.Secret != created.Secret || subscription.ProofGeneratorAddr == nil ||
+		*subscription.ProofGeneratorAddr != common.HexToAddress("0x04") || subscription.Commitment != nil {
+		t.Fatalf("unexpected subscription %+v", subscription)
+	}
+
+	unsubscribe := func(id string, secret string) int {
+		request, _ := http.NewRequest(http.MethodDelete, server.URL+"/subscriptions/"+id, nil)
+		request.Header.Set("Authorization", "Bearer "+secret)
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("could not unsubscribe: %v", err)
+		}
+		response.Body.Close()
+		return response.StatusCode
+	}
+	if status := unsubscribe("1", "wrong"); status != http.StatusNotFound {
+		t.Errorf("expected status %d with a wrong secret, got %d", http.StatusNotFound, status)
+	}
+	if status := unsubscribe("1", created.Secret); status != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, status)
+	}
+	if len(store.subscriptions) != 0 {
+		t.Errorf("expected the subscription to be deleted")
+	}
+}
@@ -3,50 +3,179 @@ package metrics
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
 )
 
 type Metrics struct {
 	ipPortAddress                          string
 	logger                                 logging.Logger
-	numAggregatedResponses                 prometheus.Counter
-	numAggregatorReceivedTasks             prometheus.Counter
+	numAggregatedResponses                 *prometheus.CounterVec
+	numAggregatorReceivedTasks             *prometheus.CounterVec
+	numAggregatorUnauthorizedTasks         prometheus.Counter
+	aggregatorThrottledRequests            *prometheus.CounterVec
+	numAggregatorIpBans                    prometheus.Counter
+	aggregatorSkippedResponses             *prometheus.CounterVec
+	aggregatorUnfundedBatches              *prometheus.CounterVec
+	aggregatorLostTasks                    *prometheus.CounterVec
+	numDroppedNewBatches                   prometheus.Counter
+	aggregatorTasks                        *prometheus.GaugeVec
+	aggregatorTaskTransitions              *prometheus.CounterVec
 	numOperatorTaskResponses               prometheus.Counter
 	aggregatorGasCostPaidForBatcherTotal   prometheus.Gauge
 	aggregatorNumTimesPaidForBatcher       prometheus.Counter
 	numBumpedGasPriceForAggregatedResponse prometheus.Counter
 	aggregatorGasCostPaidTotal             prometheus.Counter
-	aggregatorRespondToTaskLatency         prometheus.Gauge
-	aggregatorTaskQuorumReachedLatency     prometheus.Gauge
+	aggregatorFeeRecoveredTotal            prometheus.Counter
+	aggregatorNetMargin                    *prometheus.GaugeVec
+	aggregatorLastBatchNetMargin           prometheus.Gauge
+	aggregatorRespondToTaskLatency         *prometheus.GaugeVec
+	aggregatorTaskQuorumReachedLatency     *prometheus.GaugeVec
+	aggregatorRespondToTaskDuration        *prometheus.HistogramVec
+	aggregatorTaskQuorumReachedDuration    *prometheus.HistogramVec
+	aggregatorBatchesFinalizable           *prometheus.CounterVec
+	aggregatorBatchesFinalizedWithinSlo    *prometheus.CounterVec
+	aggregatorFinalizationSlo              prometheus.Gauge
+	finalizationSlo                        time.Duration
+	operatorVerifications                  *prometheus.CounterVec
+	operatorVerificationDuration           *prometheus.HistogramVec
+	operatorVerificationPathDuration       *prometheus.HistogramVec
+	operatorGpuFallbacks                   *prometheus.CounterVec
+	operatorVerificationFailures           *prometheus.CounterVec
+	operatorVersionStatus                  prometheus.Gauge
+	commandRuns                            *prometheus.CounterVec
+	commandDuration                        *prometheus.GaugeVec
+	commandLastSuccess                     *prometheus.GaugeVec
+	pushgateway                            PushgatewayConfig
+	server                                 *http.Server
 }
 
 const alignedNamespace = "aligned"
 
+// TraceIdExemplarLabel is the exemplar label of the telemetry trace ids, Grafana links it to the traces
+const TraceIdExemplarLabel = "trace_id"
+
+// Config customizes the metrics of a component
+type Config struct {
+	// HistogramBuckets are the bucket boundaries of the histograms, by histogram name without the
+	// namespace, e.g. "aggregator_task_quorum_reached_duration_seconds". The histograms not set
+	// use their default buckets.
+	HistogramBuckets map[string][]float64
+	Pushgateway      PushgatewayConfig
+	// FinalizationSlo is the time a batch should be finalized in, from the aggregator receiving it
+	// until its response is confirmed. DefaultFinalizationSlo if unset.
+	FinalizationSlo time.Duration
+}
+
+const DefaultFinalizationSlo = 3 * time.Minute
+
+// PushgatewayConfig is the Pushgateway the metrics of the components that don't run long enough to
+// be scraped are pushed to. Pushing is disabled without a Url.
+type PushgatewayConfig struct {
+	Url string
+	// Job is the job label of the pushed metrics, "aligned" by default
+	Job string
+	// Instance is the instance label of the pushed metrics, the hostname by default
+	Instance string
+}
+
 func NewMetrics(ipPortAddress string, reg prometheus.Registerer, logger logging.Logger) *Metrics {
-	return &Metrics{
-		ipPortAddress: ipPortAddress,
-		logger:        logger,
-		numAggregatedResponses: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+	return NewMetricsWithConfig(ipPortAddress, reg, logger, Config{})
+}
+
+func NewMetricsWithConfig(ipPortAddress string, reg prometheus.Registerer, logger logging.Logger, config Config) *Metrics {
+	// buckets returns the configured buckets of the histogram if valid, or else the default ones
+	buckets := func(name string, defaultBuckets []float64) []float64 {
+		configured, ok := config.HistogramBuckets[name]
+		if !ok {
+			return defaultBuckets
+		}
+		if err := validateBuckets(configured); err != nil {
+			logger.Error("Invalid histogram buckets, using the default ones", "histogram", name, "err", err)
+			return defaultBuckets
+		}
+		return configured
+	}
+
+	finalizationSlo := config.FinalizationSlo
+	if finalizationSlo <= 0 {
+		finalizationSlo = DefaultFinalizationSlo
+	}
+
+	m := &Metrics{
+		ipPortAddress:   ipPortAddress,
+		logger:          logger,
+		pushgateway:     config.Pushgateway,
+		finalizationSlo: finalizationSlo,
+		numAggregatedResponses: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 			Namespace: alignedNamespace,
 			Name:      "aggregated_responses_count",
-			Help:      "Number of aggregated responses sent to the Aligned Service Manager",
-		}),
+			Help:      "Number of aggregated responses sent to the Aligned Service Manager, by quorum",
+		}, []string{"quorum"}),
 		numOperatorTaskResponses: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Namespace: alignedNamespace,
 			Name:      "operator_responses_count",
 			Help:      "Number of proof verified by the operator and sent to the Aligned Service Manager",
 		}),
-		numAggregatorReceivedTasks: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+		numAggregatorReceivedTasks: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 			Namespace: alignedNamespace,
 			Name:      "aggregator_received_tasks_count",
-			Help:      "Number of tasks received by the Service Manager",
+			Help:      "Number of tasks received by the Service Manager, by quorum",
+		}, []string{"quorum"}),
+		numAggregatorUnauthorizedTasks: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_unauthorized_tasks_count",
+			Help:      "Number of tasks ignored by the aggregator because their sender isn't an authorized batcher",
 		}),
+		aggregatorThrottledRequests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_throttled_requests_count",
+			Help:      "Number of requests to the aggregator operators server rejected by IP, by reason",
+		}, []string{"reason"}),
+		numAggregatorIpBans: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_ip_bans_count",
+			Help:      "Number of times an IP was temporarily banned from the aggregator operators server for exceeding the rate limit",
+		}),
+		aggregatorSkippedResponses: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_skipped_responses_count",
+			Help:      "Number of batches that reached quorum the aggregator didn't respond to because it was done by another replica, by reason",
+		}, []string{"reason"}),
+		aggregatorUnfundedBatches: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_unfunded_batches_count",
+			Help:      "Number of batches whose response wouldn't be compensated by the batcher, by reason and policy applied",
+		}, []string{"reason", "policy"}),
+		aggregatorLostTasks: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_lost_tasks_count",
+			Help:      "Number of tasks the aggregator couldn't respond to, by reason",
+		}, []string{"reason"}),
+		numDroppedNewBatches: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "new_batches_dropped_count",
+			Help:      "Number of new batches dropped because they weren't taken from the new tasks channel in time",
+		}),
+		aggregatorTasks: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_tasks",
+			Help:      "Number of tasks tracked by the aggregator, by lifecycle state",
+		}, []string{"state"}),
+		aggregatorTaskTransitions: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_task_transitions_count",
+			Help:      "Number of task lifecycle state transitions in the aggregator, by state transitioned from and to",
+		}, []string{"from", "to"}),
 		aggregatorGasCostPaidForBatcherTotal: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
 			Namespace: alignedNamespace,
 			Name:      "aggregator_gas_cost_paid_for_batcher_sum",
@@ -62,22 +191,113 @@ func NewMetrics(ipPortAddress string, reg prometheus.Registerer, logger logging.
 			Name:      "aggregator_gas_cost_paid_total_count",
 			Help:      "Total amount of gas paid by the aggregator while responding to tasks",
 		}),
+		aggregatorFeeRecoveredTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_fee_recovered_total_count",
+			Help:      "Total amount of ETH the aggregator recovered from the batchers for responding to tasks",
+		}),
+		aggregatorNetMargin: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_net_margin_sum",
+			Help:      "Accumulated ETH recovered from the batchers minus the gas cost paid responding to tasks, negative when operating at a loss, by whether the gas price was bumped",
+		}, []string{"gas_price_bumped"}),
+		aggregatorLastBatchNetMargin: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_last_batch_net_margin",
+			Help:      "ETH recovered from the batcher minus the gas cost paid responding to the last task",
+		}),
 		numBumpedGasPriceForAggregatedResponse: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Namespace: alignedNamespace,
 			Name:      "respond_to_task_gas_price_bumped_count",
 			Help:      "Number of times gas price was bumped while sending aggregated response",
 		}),
-		aggregatorRespondToTaskLatency: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+		aggregatorRespondToTaskLatency: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: alignedNamespace,
 			Name:      "aggregator_respond_to_task_latency",
-			Help:      "Latency of last call to respondToTask on Aligned Service Manager",
-		}),
-		aggregatorTaskQuorumReachedLatency: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Help:      "Latency of last call to respondToTask on Aligned Service Manager, by quorum",
+		}, []string{"quorum"}),
+		aggregatorTaskQuorumReachedLatency: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: alignedNamespace,
 			Name:      "aggregator_task_quorum_reached_latency",
-			Help:      "Time it takes for a task to reach quorum",
+			Help:      "Time it takes for a task to reach quorum, by quorum",
+		}, []string{"quorum"}),
+		aggregatorRespondToTaskDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_respond_to_task_duration_seconds",
+			Help:      "Time it takes to respond to a task on the Aligned Service Manager, gas price bumps included, by quorum",
+			Buckets:   buckets("aggregator_respond_to_task_duration_seconds", []float64{1, 2.5, 5, 12, 24, 36, 60, 120, 300, 600}),
+		}, []string{"quorum"}),
+		aggregatorTaskQuorumReachedDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_task_quorum_reached_duration_seconds",
+			Help:      "Time it takes for a task to reach quorum, by quorum",
+			Buckets:   buckets("aggregator_task_quorum_reached_duration_seconds", []float64{5, 10, 20, 30, 60, 120, 180, 300, 600, 900}),
+		}, []string{"quorum"}),
+		aggregatorBatchesFinalizable: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_slo_batches_count",
+			Help:      "Number of batches the aggregator finished working on that count towards the finalization SLO, by quorum",
+		}, []string{"quorum"}),
+		aggregatorBatchesFinalizedWithinSlo: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_slo_batches_finalized_within_slo_count",
+			Help:      "Number of batches finalized within the finalization SLO, by quorum",
+		}, []string{"quorum"}),
+		aggregatorFinalizationSlo: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_slo_finalization_seconds",
+			Help:      "Time a batch should be finalized in to meet the finalization SLO",
 		}),
+		operatorVerifications: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_verifications_count",
+			Help:      "Number of proofs verified by the operator, by proving system and result",
+		}, []string{"proving_system", "result"}),
+		operatorVerificationDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_verification_duration_seconds",
+			Help:      "Time it takes the operator to verify a proof, by proving system",
+			Buckets:   buckets("operator_verification_duration_seconds", []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60}),
+		}, []string{"proving_system"}),
+		operatorVerificationPathDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_verification_path_duration_seconds",
+			Help:      "Time it takes the operator to verify a proof, by proving system and path (cpu or gpu)",
+			Buckets:   buckets("operator_verification_path_duration_seconds", []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}),
+		}, []string{"proving_system", "path"}),
+		operatorGpuFallbacks: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_gpu_fallbacks_count",
+			Help:      "Number of proofs that couldn't be verified on the GPU and were verified on the CPU, by proving system",
+		}, []string{"proving_system"}),
+		operatorVerificationFailures: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_verification_failures_count",
+			Help:      "Number of proofs the operator didn't verify as valid, by proving system and reason",
+		}, []string{"proving_system", "reason"}),
+		operatorVersionStatus: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_version_outdated",
+			Help:      "Whether the operator version is outdated: 0 if up to date, 1 if older than the recommended version, 2 if older than the minimum version",
+		}),
+		commandRuns: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "command_runs_count",
+			Help:      "Number of runs of a one-off command, by command and result",
+		}, []string{"command", "result"}),
+		commandDuration: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "command_last_duration_seconds",
+			Help:      "Time the last run of a one-off command took, by command",
+		}, []string{"command"}),
+		commandLastSuccess: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "command_last_success_timestamp_seconds",
+			Help:      "Unix time of the last successful run of a one-off command, by command",
+		}, []string{"command"}),
 	}
+	m.aggregatorFinalizationSlo.Set(finalizationSlo.Seconds())
+	return m
 }
 
 // Start creates a http handler for reg and starts the prometheus server in a goroutine, listening at m.ipPortAddress.
@@ -86,7 +306,7 @@ func (m *Metrics) Start(ctx context.Context, reg prometheus.Gatherer) <-chan err
 	m.logger.Infof("Starting metrics server at port %v", m.ipPortAddress)
 	errC := make(chan error, 1)
 
-	server := http.Server{
+	server := &http.Server{
 		Addr:           m.ipPortAddress,
 		Handler:        http.NewServeMux(),
 		ReadTimeout:    10 * time.Second,
@@ -97,12 +317,15 @@ func (m *Metrics) Start(ctx context.Context, reg prometheus.Gatherer) <-chan err
 
 	server.Handler.(*http.ServeMux).Handle("/metrics", promhttp.HandlerFor(
 		reg,
-		promhttp.HandlerOpts{},
+		// exemplars are only exposed in the OpenMetrics format
+		promhttp.HandlerOpts{EnableOpenMetrics: true},
 	))
 
+	m.server = server
+
 	go func() {
 		err := server.ListenAndServe()
-		if err != nil {
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errC <- errors.New("prometheus server failed")
 		} else {
 			errC <- nil
@@ -111,12 +334,90 @@ func (m *Metrics) Start(ctx context.Context, reg prometheus.Gatherer) <-chan err
 	return errC
 }
 
-func (m *Metrics) IncAggregatorReceivedTasks() {
-	m.numAggregatorReceivedTasks.Inc()
+// Push pushes the metrics of reg to the Pushgateway, replacing the ones previously pushed with the
+// same job and instance. It does nothing if no Pushgateway is configured.
+func (m *Metrics) Push(reg prometheus.Gatherer) error {
+	if m.pushgateway.Url == "" {
+		return nil
+	}
+	job := m.pushgateway.Job
+	if job == "" {
+		job = "aligned"
+	}
+	instance := m.pushgateway.Instance
+	if instance == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("could not get the instance: %w", err)
+		}
+		instance = hostname
+	}
+	m.logger.Info("Pushing metrics", "pushgateway", m.pushgateway.Url, "job", job, "instance", instance)
+	return push.New(m.pushgateway.Url, job).Gatherer(reg).Grouping("instance", instance).Push()
+}
+
+// Shutdown stops the prometheus server started with Start, waiting for the requests in progress to finish.
+func (m *Metrics) Shutdown(ctx context.Context) error {
+	if m.server == nil {
+		return nil
+	}
+	return m.server.Shutdown(ctx)
+}
+
+// IncAggregatorReceivedTasks counts a task initialized in the quorum
+func (m *Metrics) IncAggregatorReceivedTasks(quorumNumber uint8) {
+	m.numAggregatorReceivedTasks.WithLabelValues(quorumLabel(quorumNumber)).Inc()
+}
+
+func (m *Metrics) IncAggregatorUnauthorizedTasks() {
+	m.numAggregatorUnauthorizedTasks.Inc()
 }
 
-func (m *Metrics) IncAggregatedResponses() {
-	m.numAggregatedResponses.Inc()
+// IncAggregatorThrottledRequests counts a request rejected by IP, reason being "rate_limited" or "banned".
+func (m *Metrics) IncAggregatorThrottledRequests(reason string) {
+	m.aggregatorThrottledRequests.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) IncAggregatorIpBans() {
+	m.numAggregatorIpBans.Inc()
+}
+
+// IncAggregatorSkippedResponses counts a batch left to another replica, reason being "responded" or "claimed".
+func (m *Metrics) IncAggregatorSkippedResponses(reason string) {
+	m.aggregatorSkippedResponses.WithLabelValues(reason).Inc()
+}
+
+// IncAggregatorUnfundedBatches counts a batch whose response wouldn't be compensated, reason being "batcher_balance" or "fee_limit".
+func (m *Metrics) IncAggregatorUnfundedBatches(reason string, policy string) {
+	m.aggregatorUnfundedBatches.WithLabelValues(reason, policy).Inc()
+}
+
+// IncAggregatorLostTasks counts a task the aggregator couldn't respond to, reason being "expired" if it
+// didn't reach quorum in time or "send_failed" if its response couldn't be sent.
+func (m *Metrics) IncAggregatorLostTasks(reason string) {
+	m.aggregatorLostTasks.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) IncDroppedNewBatches() {
+	m.numDroppedNewBatches.Inc()
+}
+
+// ObserveAggregatorTaskTransition moves a task between the lifecycle states, from being empty for a
+// new task and to being empty for a removed one.
+func (m *Metrics) ObserveAggregatorTaskTransition(from string, to string) {
+	if from != "" {
+		m.aggregatorTasks.WithLabelValues(from).Dec()
+	}
+	if to != "" {
+		m.aggregatorTasks.WithLabelValues(to).Inc()
+	}
+	if from != "" && to != "" {
+		m.aggregatorTaskTransitions.WithLabelValues(from, to).Inc()
+	}
+}
+
+func (m *Metrics) IncAggregatedResponses(quorumNumber uint8) {
+	m.numAggregatedResponses.WithLabelValues(quorumLabel(quorumNumber)).Inc()
 }
 
 func (m *Metrics) IncOperatorTaskResponses() {
@@ -135,14 +436,111 @@ func (m *Metrics) AddAggregatorGasCostPaidTotal(value float64) {
 	m.aggregatorGasCostPaidTotal.Add(value)
 }
 
+// ObserveAggregatorBatchMargin records the ETH recovered from the batcher for responding to a task
+// against the gas cost paid, gasPriceBumped being whether the response was sent again with a
+// bumped gas price.
+func (m *Metrics) ObserveAggregatorBatchMargin(feeRecovered float64, gasCost float64, gasPriceBumped bool) {
+	m.aggregatorFeeRecoveredTotal.Add(feeRecovered)
+	m.aggregatorNetMargin.WithLabelValues(strconv.FormatBool(gasPriceBumped)).Add(feeRecovered - gasCost)
+	m.aggregatorLastBatchNetMargin.Set(feeRecovered - gasCost)
+}
+
 func (m *Metrics) IncBumpedGasPriceForAggregatedResponse() {
 	m.numBumpedGasPriceForAggregatedResponse.Inc()
 }
 
-func (m *Metrics) ObserveLatencyForRespondToTask(elapsed time.Duration) {
-	m.aggregatorRespondToTaskLatency.Set(elapsed.Seconds())
+// ObserveLatencyForRespondToTask records the response time of a task. traceId is the telemetry trace
+// of the task, attached as an exemplar if not empty.
+func (m *Metrics) ObserveLatencyForRespondToTask(quorumNumber uint8, elapsed time.Duration, traceId string) {
+	m.aggregatorRespondToTaskLatency.WithLabelValues(quorumLabel(quorumNumber)).Set(elapsed.Seconds())
+	observeWithTraceId(m.aggregatorRespondToTaskDuration.WithLabelValues(quorumLabel(quorumNumber)), elapsed.Seconds(), traceId)
+}
+
+// ObserveTaskQuorumReached records the time a task took to reach quorum. traceId is the telemetry
+// trace of the task, attached as an exemplar if not empty.
+func (m *Metrics) ObserveTaskQuorumReached(quorumNumber uint8, elapsed time.Duration, traceId string) {
+	m.aggregatorTaskQuorumReachedLatency.WithLabelValues(quorumLabel(quorumNumber)).Set(elapsed.Seconds())
+	observeWithTraceId(m.aggregatorTaskQuorumReachedDuration.WithLabelValues(quorumLabel(quorumNumber)), elapsed.Seconds(), traceId)
+}
+
+// ObserveBatchFinalization records a batch the aggregator finished working on: finalized if its
+// response was confirmed, after elapsed since the aggregator received it. Batches that weren't
+// finalized count as missing the finalization SLO.
+func (m *Metrics) ObserveBatchFinalization(quorumNumber uint8, finalized bool, elapsed time.Duration) {
+	m.aggregatorBatchesFinalizable.WithLabelValues(quorumLabel(quorumNumber)).Inc()
+	withinSlo := m.aggregatorBatchesFinalizedWithinSlo.WithLabelValues(quorumLabel(quorumNumber))
+	if finalized && elapsed <= m.finalizationSlo {
+		withinSlo.Inc()
+	} else {
+		// initializes the series, so the ratio is defined before any batch meets the SLO
+		withinSlo.Add(0)
+	}
+}
+
+// observeWithTraceId observes the value with the trace as exemplar, so the bucket links to the trace
+func observeWithTraceId(observer prometheus.Observer, value float64, traceId string) {
+	exemplarObserver, ok := observer.(prometheus.ExemplarObserver)
+	if traceId == "" || !ok {
+		observer.Observe(value)
+		return
+	}
+	exemplarObserver.ObserveWithExemplar(value, prometheus.Labels{TraceIdExemplarLabel: traceId})
+}
+
+// IncOperatorVerifications counts a proof verification, result being one of "valid", "invalid", "failed" or "disabled".
+// Failed verifications are the ones that couldn't be completed, e.g. due to exceeding the proving system limits.
+func (m *Metrics) IncOperatorVerifications(provingSystem string, result string) {
+	m.operatorVerifications.WithLabelValues(provingSystem, result).Inc()
+}
+
+func (m *Metrics) ObserveOperatorVerificationDuration(provingSystem string, elapsed time.Duration) {
+	m.operatorVerificationDuration.WithLabelValues(provingSystem).Observe(elapsed.Seconds())
 }
 
-func (m *Metrics) ObserveTaskQuorumReached(elapsed time.Duration) {
-	m.aggregatorTaskQuorumReachedLatency.Set(elapsed.Seconds())
+// ObserveOperatorVerificationPathDuration records the time a verification took on the given path, "cpu" or "gpu".
+func (m *Metrics) ObserveOperatorVerificationPathDuration(provingSystem string, path string, elapsed time.Duration) {
+	m.operatorVerificationPathDuration.WithLabelValues(provingSystem, path).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) IncOperatorGpuFallbacks(provingSystem string) {
+	m.operatorGpuFallbacks.WithLabelValues(provingSystem).Inc()
+}
+
+// IncOperatorVerificationFailures counts a proof that wasn't verified as valid, reason being why, e.g. "invalid_proof" or "timeout".
+func (m *Metrics) IncOperatorVerificationFailures(provingSystem string, reason string) {
+	m.operatorVerificationFailures.WithLabelValues(provingSystem, reason).Inc()
+}
+
+// ObserveCommand records a run of a one-off command, which failed if err isn't nil.
+func (m *Metrics) ObserveCommand(command string, elapsed time.Duration, err error) {
+	m.commandDuration.WithLabelValues(command).Set(elapsed.Seconds())
+	if err != nil {
+		m.commandRuns.WithLabelValues(command, "failed").Inc()
+		return
+	}
+	m.commandRuns.WithLabelValues(command, "succeeded").Inc()
+	m.commandLastSuccess.WithLabelValues(command).SetToCurrentTime()
+}
+
+// SetOperatorVersionOutdated sets how outdated the operator version is, see the operator_version_outdated help.
+func (m *Metrics) SetOperatorVersionOutdated(outdated int) {
+	m.operatorVersionStatus.Set(float64(outdated))
+}
+
+// quorumLabel is the quorum label value of the task metrics
+func quorumLabel(quorumNumber uint8) string {
+	return strconv.Itoa(int(quorumNumber))
+}
+
+// validateBuckets checks the bucket boundaries are strictly increasing, as prometheus requires
+func validateBuckets(buckets []float64) error {
+	if len(buckets) == 0 {
+		return errors.New("no buckets")
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return fmt.Errorf("bucket %v is not greater than the previous one %v", buckets[i], buckets[i-1])
+		}
+	}
+	return nil
 }
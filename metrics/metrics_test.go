@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func histogramBuckets(t *testing.T, reg *prometheus.Registry, name string) []float64 {
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			var buckets []float64
+			for _, bucket := range family.GetMetric()[0].GetHistogram().GetBucket() {
+				buckets = append(buckets, bucket.GetUpperBound())
+			}
+			return buckets
+		}
+	}
+	t.Fatalf("Histogram %s not found", name)
+	return nil
+}
+
+func TestConfiguredHistogramBuckets(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	reg := prometheus.NewRegistry()
+	m := NewMetricsWithConfig("", reg, logger, Config{HistogramBuckets: map[string][]float64{
+		"aggregator_task_quorum_reached_duration_seconds": {60, 300},
+		"aggregator_respond_to_task_duration_seconds":     {10, 5},
+	}})
+	m.ObserveTaskQuorumReached(0, 2*time.Minute, "")
+	m.ObserveLatencyForRespondToTask(0, time.Second, "")
+
+	if buckets := histogramBuckets(t, reg, "aligned_aggregator_task_quorum_reached_duration_seconds"); len(buckets) != 2 || buckets[1] != 300 {
+		t.Errorf("Expected the configured buckets, got %v", buckets)
+	}
+	if buckets := histogramBuckets(t, reg, "aligned_aggregator_respond_to_task_duration_seconds"); len(buckets) != 10 {
+		t.Errorf("Expected the default buckets instead of the decreasing ones, got %v", buckets)
+	}
+}
+
+func TestPushToPushgateway(t *testing.T) {
+	var pushedPath string
+	var pushedBody []byte
+	pushgateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushedPath = r.URL.Path
+		pushedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer pushgateway.Close()
+
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	reg := prometheus.NewRegistry()
+	m := NewMetricsWithConfig("", reg, logger, Config{Pushgateway: PushgatewayConfig{Url: pushgateway.URL, Instance: "operator-1"}})
+	m.ObserveCommand("register", time.Second, nil)
+	if err := m.Push(reg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if pushedPath != "/metrics/job/aligned/instance/operator-1" {
+		t.Errorf("Expected the metrics to be pushed with the job and instance labels, got %s", pushedPath)
+	}
+	if !bytes.Contains(pushedBody, []byte("aligned_command_runs_count")) {
+		t.Errorf("Expected the command metrics to be pushed")
+	}
+
+	if err := NewMetrics("", prometheus.NewRegistry(), logger).Push(reg); err != nil {
+		t.Errorf("Expected pushing without a Pushgateway to do nothing, got %v", err)
+	}
+}
+
+func TestTaskMetricsByQuorum(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	reg := prometheus.NewRegistry()
+	m := NewMetrics("", reg, logger)
+	m.IncAggregatorReceivedTasks(0)
+	m.IncAggregatorReceivedTasks(1)
+	m.IncAggregatorReceivedTasks(1)
+
+	families, _ := reg.Gather()
+	received := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "aligned_aggregator_received_tasks_count" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			received[metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+		}
+	}
+	if received["0"] != 1 || received["1"] != 2 {
+		t.Errorf("Expected the received tasks to be counted by quorum, got %v", received)
+	}
+}
+
+func TestLatencyExemplars(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	reg := prometheus.NewRegistry()
+	m := NewMetrics("", reg, logger)
+	m.ObserveTaskQuorumReached(0, 5*time.Second, "4bf92f3577b34da6a3ce929d0e0e4736")
+	m.ObserveTaskQuorumReached(0, 10*time.Minute, "")
+
+	families, _ := reg.Gather()
+	var traceIds []string
+	for _, family := range families {
+		if family.GetName() != "aligned_aggregator_task_quorum_reached_duration_seconds" {
+			continue
+		}
+		for _, bucket := range family.GetMetric()[0].GetHistogram().GetBucket() {
+			if exemplar := bucket.GetExemplar(); exemplar != nil {
+				for _, label := range exemplar.GetLabel() {
+					if label.GetName() == TraceIdExemplarLabel {
+						traceIds = append(traceIds, label.GetValue())
+					}
+				}
+			}
+		}
+	}
+	if len(traceIds) != 1 || traceIds[0] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected only the traced observation to have an exemplar, got %v", traceIds)
+	}
+}
+
+func TestBatchFinalizationSlo(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	reg := prometheus.NewRegistry()
+	m := NewMetricsWithConfig("", reg, logger, Config{FinalizationSlo: time.Minute})
+	m.ObserveBatchFinalization(0, true, 30*time.Second)
+	m.ObserveBatchFinalization(0, true, 2*time.Minute)
+	m.ObserveBatchFinalization(0, false, 10*time.Second)
+
+	counters := make(map[string]float64)
+	families, _ := reg.Gather()
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if metric.GetCounter() != nil {
+				counters[family.GetName()] = metric.GetCounter().GetValue()
+			}
+			if metric.GetGauge() != nil {
+				counters[family.GetName()] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+	if counters["aligned_aggregator_slo_batches_count"] != 3 {
+		t.Errorf("Expected 3 batches counted towards the SLO, got %v", counters["aligned_aggregator_slo_batches_count"])
+	}
+	if counters["aligned_aggregator_slo_batches_finalized_within_slo_count"] != 1 {
+		t.Errorf("Expected 1 batch finalized within the SLO, got %v", counters["aligned_aggregator_slo_batches_finalized_within_slo_count"])
+	}
+	if counters["aligned_aggregator_slo_finalization_seconds"] != 60 {
+		t.Errorf("Expected the SLO to be exported, got %v", counters["aligned_aggregator_slo_finalization_seconds"])
+	}
+}
+
+func TestAggregatorBatchMargin(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	reg := prometheus.NewRegistry()
+	m := NewMetrics("", reg, logger)
+	m.ObserveAggregatorBatchMargin(0.01, 0.008, false)
+	m.ObserveAggregatorBatchMargin(0.01, 0.015, true)
+
+	margins := make(map[string]float64)
+	families, _ := reg.Gather()
+	for _, family := range families {
+		if family.GetName() != "aligned_aggregator_net_margin_sum" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			margins[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+		}
+	}
+	if margins["false"] < 0.0019 || margins["false"] > 0.0021 {
+		t.Errorf("Expected a positive margin without gas price bumps, got %v", margins["false"])
+	}
+	if margins["true"] > -0.0049 || margins["true"] < -0.0051 {
+		t.Errorf("Expected a loss with gas price bumps, got %v", margins["true"])
+	}
+}
@@ -0,0 +1,48 @@
+package binius
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libbinius_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -lbinius_verifier_ffi
+
+#include "lib/binius.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// VerifyBiniusProof verifies a Binius proof of the given constraint system, serialized as the verification key.
+// The public input holds the boundary values of the constraint system flushes.
+// Binius support is experimental, operators only verify these proofs if it's enabled in their config.
+func VerifyBiniusProof(proofBuffer []byte, pubInputBuffer []byte, verificationKeyBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(pubInputBuffer) == 0 || len(verificationKeyBuffer) == 0 {
+		return isVerified, err
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying Binius proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	pubInputPtr := (*C.uchar)(unsafe.Pointer(&pubInputBuffer[0]))
+	verificationKeyPtr := (*C.uchar)(unsafe.Pointer(&verificationKeyBuffer[0]))
+
+	r := (C.int32_t)(C.verify_binius_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), pubInputPtr, (C.uint32_t)(len(pubInputBuffer)), verificationKeyPtr, (C.uint32_t)(len(verificationKeyBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying Binius proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
@@ -0,0 +1,35 @@
+package binius_test
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/binius"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/binius/binius_fibonacci.proof"
+
+const PubInputFilePath = "../../scripts/test_files/binius/binius_fibonacci.pub"
+
+const ConstraintSystemFilePath = "../../scripts/test_files/binius/binius_fibonacci.cs"
+
+// readTestFiles reads the proof, boundaries and constraint system generated with
+// make generate_binius_fibonacci_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_binius_fibonacci_proof", ProofFilePath, PubInputFilePath, ConstraintSystemFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return binius.VerifyBiniusProof(inputs[0], inputs[1], inputs[2])
+}
+
+func TestBiniusProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestBiniusProofWithTamperedConstraintSystemDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[2][len(inputs[2])/2] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with another constraint system")
+}
@@ -0,0 +1,126 @@
+use binius_core::constraint_system::{self, channel::Boundary, ConstraintSystem, Proof};
+use binius_core::fiat_shamir::HasherChallenger;
+use binius_field::arch::OptimalUnderlier;
+use binius_field::tower::CanonicalTowerFamily;
+use binius_field::BinaryField128b;
+use binius_hash::compress::Groestl256ByteCompression;
+use binius_utils::serialization::{DeserializeBytes, SerializationMode};
+use groestl_crypto::Groestl256;
+use log::error;
+
+// Proofs are expected to use the canonical tower with Groestl as hash, and the
+// default rate and security of the Binius examples
+const LOG_INV_RATE: usize = 1;
+const SECURITY_BITS: usize = 100;
+
+type F = BinaryField128b;
+
+fn inner_verify_binius_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+    constraint_system_bytes: *const u8,
+    constraint_system_len: u32,
+) -> bool {
+    if proof_bytes.is_null() || pub_input_bytes.is_null() || constraint_system_bytes.is_null() {
+        error!("Input buffer null");
+        return false;
+    }
+
+    if proof_len == 0 || pub_input_len == 0 || constraint_system_len == 0 {
+        error!("Input buffer length zero size");
+        return false;
+    }
+
+    let proof_bytes = unsafe { std::slice::from_raw_parts(proof_bytes, proof_len as usize) };
+
+    let mut pub_input_bytes =
+        unsafe { std::slice::from_raw_parts(pub_input_bytes, pub_input_len as usize) };
+
+    let mut constraint_system_bytes = unsafe {
+        std::slice::from_raw_parts(constraint_system_bytes, constraint_system_len as usize)
+    };
+
+    let Ok(constraint_system) = ConstraintSystem::<F>::deserialize(
+        &mut constraint_system_bytes,
+        SerializationMode::CanonicalTower,
+    ) else {
+        error!("Could not deserialize Binius constraint system");
+        return false;
+    };
+
+    let Ok(boundaries) =
+        Vec::<Boundary<F>>::deserialize(&mut pub_input_bytes, SerializationMode::CanonicalTower)
+    else {
+        error!("Could not deserialize Binius boundaries");
+        return false;
+    };
+
+    let proof = Proof {
+        transcript: proof_bytes.to_vec(),
+    };
+
+    constraint_system::verify::<
+        OptimalUnderlier,
+        CanonicalTowerFamily,
+        Groestl256,
+        Groestl256ByteCompression,
+        HasherChallenger<Groestl256>,
+    >(
+        &constraint_system,
+        LOG_INV_RATE,
+        SECURITY_BITS,
+        boundaries,
+        proof,
+    )
+    .is_ok()
+}
+
+#[no_mangle]
+pub extern "C" fn verify_binius_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+    constraint_system_bytes: *const u8,
+    constraint_system_len: u32,
+) -> i32 {
+    let result = std::panic::catch_unwind(|| {
+        inner_verify_binius_proof_ffi(
+            proof_bytes,
+            proof_len,
+            pub_input_bytes,
+            pub_input_len,
+            constraint_system_bytes,
+            constraint_system_len,
+        )
+    });
+
+    match result {
+        Ok(v) => v as i32,
+        Err(_) => -1,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn verify_binius_fails_with_malformed_proof() {
+        let proof = [1u8, 2, 3, 4];
+        let pub_input = [5u8, 6, 7, 8];
+        let constraint_system = [9u8, 10, 11, 12];
+
+        let result = verify_binius_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            pub_input.as_ptr(),
+            pub_input.len() as u32,
+            constraint_system.as_ptr(),
+            constraint_system.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+}
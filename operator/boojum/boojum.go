@@ -0,0 +1,54 @@
+package boojum
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libboojum_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -lboojum_verifier_ffi
+
+#include "lib/boojum.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// FeltSize is the size of a little endian encoded Goldilocks field element.
+const FeltSize = 8
+
+// VerifyBoojumProof verifies a zkSync era Boojum recursion layer proof against its verification key.
+// Only recursion tip and scheduler proofs are accepted, and their public inputs must match the given ones,
+// each a little endian encoded Goldilocks field element.
+func VerifyBoojumProof(proofBuffer []byte, pubInputBuffer []byte, verificationKeyBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(pubInputBuffer) == 0 || len(verificationKeyBuffer) == 0 {
+		return isVerified, err
+	}
+	if len(pubInputBuffer)%FeltSize != 0 {
+		return isVerified, fmt.Errorf("Boojum public input size %d is not a multiple of the felt size", len(pubInputBuffer))
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying Boojum proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	pubInputPtr := (*C.uchar)(unsafe.Pointer(&pubInputBuffer[0]))
+	verificationKeyPtr := (*C.uchar)(unsafe.Pointer(&verificationKeyBuffer[0]))
+
+	r := (C.int32_t)(C.verify_boojum_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), pubInputPtr, (C.uint32_t)(len(pubInputBuffer)), verificationKeyPtr, (C.uint32_t)(len(verificationKeyBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying Boojum proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
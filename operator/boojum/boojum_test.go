@@ -0,0 +1,56 @@
+package boojum_test
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/boojum"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/boojum/boojum_recursion_tip.proof"
+
+const PubInputFilePath = "../../scripts/test_files/boojum/boojum_recursion_tip.pub"
+
+const VerificationKeyFilePath = "../../scripts/test_files/boojum/boojum_recursion_tip.vk"
+
+// readTestFiles reads the recursion tip proof, public input and verification key written with
+// make generate_boojum_recursion_tip_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_boojum_recursion_tip_proof", ProofFilePath, PubInputFilePath, VerificationKeyFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return boojum.VerifyBoojumProof(inputs[0], inputs[1], inputs[2])
+}
+
+func TestBoojumRecursionTipProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestBoojumProofWithWrongPubInputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1][0] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with other public inputs")
+}
+
+func TestBoojumProofWithMissingPubInputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1] = inputs[1][:len(inputs[1])-boojum.FeltSize]
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify without all its public inputs")
+}
+
+func TestBoojumProofWithTamperedVerificationKeyDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[2][len(inputs[2])/2] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with a tampered verification key")
+}
+
+func TestUnalignedBoojumPubInputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1] = inputs[1][:len(inputs[1])-1]
+	verifiertest.ExpectRejected(t, verify, inputs, "public input not made of felts should not verify")
+}
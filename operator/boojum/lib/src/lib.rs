@@ -0,0 +1,143 @@
+use circuit_definitions::boojum::cs::implementations::pow::NoPow;
+use circuit_definitions::boojum::field::PrimeField;
+use circuit_definitions::circuit_definitions::recursion_layer::{
+    ZkSyncRecursionLayerProof, ZkSyncRecursionLayerStorageType, ZkSyncRecursionLayerVerificationKey,
+};
+use log::error;
+use zkevm_test_harness::prover_utils::verify_recursion_layer_proof_for_type;
+
+const FELT_SIZE: usize = 8;
+
+/// Only the recursion layer proofs that cover a whole batch are accepted.
+fn is_supported_circuit_type(circuit_type: u8) -> bool {
+    circuit_type == ZkSyncRecursionLayerStorageType::RecursionTipCircuit as u8
+        || circuit_type == ZkSyncRecursionLayerStorageType::SchedulerCircuit as u8
+}
+
+fn inner_verify_boojum_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+    vk_bytes: *const u8,
+    vk_len: u32,
+) -> bool {
+    if proof_bytes.is_null() || pub_input_bytes.is_null() || vk_bytes.is_null() {
+        error!("Input buffer null");
+        return false;
+    }
+
+    if proof_len == 0 || pub_input_len == 0 || vk_len == 0 {
+        error!("Input buffer length zero size");
+        return false;
+    }
+
+    let proof_bytes = unsafe { std::slice::from_raw_parts(proof_bytes, proof_len as usize) };
+
+    let pub_input_bytes =
+        unsafe { std::slice::from_raw_parts(pub_input_bytes, pub_input_len as usize) };
+
+    let vk_bytes = unsafe { std::slice::from_raw_parts(vk_bytes, vk_len as usize) };
+
+    if pub_input_bytes.len() % FELT_SIZE != 0 {
+        error!("Public input is not a sequence of felts");
+        return false;
+    }
+
+    let Ok(vk) = bincode::deserialize::<ZkSyncRecursionLayerVerificationKey>(vk_bytes) else {
+        error!("Could not deserialize Boojum verification key");
+        return false;
+    };
+
+    let Ok(proof) = bincode::deserialize::<ZkSyncRecursionLayerProof>(proof_bytes) else {
+        error!("Could not deserialize Boojum proof");
+        return false;
+    };
+
+    let circuit_type = vk.numeric_circuit_type();
+    if !is_supported_circuit_type(circuit_type) || proof.numeric_circuit_type() != circuit_type {
+        error!("Unsupported Boojum circuit type {}", circuit_type);
+        return false;
+    }
+
+    let expected_public_inputs: Vec<u64> = pub_input_bytes
+        .chunks_exact(FELT_SIZE)
+        .map(|chunk| u64::from_le_bytes(chunk.try_into().unwrap()))
+        .collect();
+    let public_inputs: Vec<u64> = proof
+        .clone()
+        .into_inner()
+        .public_inputs
+        .iter()
+        .map(|input| input.as_u64_reduced())
+        .collect();
+    if public_inputs != expected_public_inputs {
+        return false;
+    }
+
+    let Ok(storage_type) = ZkSyncRecursionLayerStorageType::try_from(circuit_type) else {
+        return false;
+    };
+    verify_recursion_layer_proof_for_type::<NoPow>(storage_type, &proof, &vk)
+}
+
+#[no_mangle]
+pub extern "C" fn verify_boojum_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+    vk_bytes: *const u8,
+    vk_len: u32,
+) -> i32 {
+    let result = std::panic::catch_unwind(|| {
+        inner_verify_boojum_proof_ffi(
+            proof_bytes,
+            proof_len,
+            pub_input_bytes,
+            pub_input_len,
+            vk_bytes,
+            vk_len,
+        )
+    });
+
+    match result {
+        Ok(v) => v as i32,
+        Err(_) => -1,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn verify_boojum_fails_with_malformed_proof() {
+        let proof = [1u8, 2, 3, 4];
+        let pub_input = [0u8; FELT_SIZE];
+        let vk = [5u8, 6, 7, 8];
+
+        let result = verify_boojum_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            pub_input.as_ptr(),
+            pub_input.len() as u32,
+            vk.as_ptr(),
+            vk.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+
+    #[test]
+    fn only_recursion_tip_and_scheduler_are_supported() {
+        assert!(is_supported_circuit_type(
+            ZkSyncRecursionLayerStorageType::RecursionTipCircuit as u8
+        ));
+        assert!(is_supported_circuit_type(
+            ZkSyncRecursionLayerStorageType::SchedulerCircuit as u8
+        ));
+        assert!(!is_supported_circuit_type(
+            ZkSyncRecursionLayerStorageType::NodeLayerCircuit as u8
+        ));
+    }
+}
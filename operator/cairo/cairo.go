@@ -0,0 +1,66 @@
+package cairo
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libcairo_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -lcairo_verifier_ffi
+
+#include "lib/cairo.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+const (
+	// MaxProofSize is the max size of a Stone proof. Stone proofs are JSON encoded and, depending on the
+	// layout and the FRI parameters, are usually a few hundred KiB, so bigger ones are rejected before parsing.
+	MaxProofSize = 16 * 1024 * 1024
+	// MaxPubInputSize is the max size of the public input, bounding the number of program output felts.
+	MaxPubInputSize = 1024 * 1024
+	// FeltSize is the size of a big endian encoded felt in the public input.
+	FeltSize = 32
+)
+
+// VerifyCairoProof verifies a Stone prover proof of a Cairo program execution.
+// The public input is the program hash followed by the program output, each felt encoded as 32 big endian bytes,
+// and must match the one the proof commits to.
+func VerifyCairoProof(proofBuffer []byte, pubInputBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(pubInputBuffer) == 0 {
+		return isVerified, err
+	}
+	if len(proofBuffer) > MaxProofSize {
+		return isVerified, fmt.Errorf("Cairo proof size %d exceeds max size %d", len(proofBuffer), MaxProofSize)
+	}
+	if len(pubInputBuffer) > MaxPubInputSize {
+		return isVerified, fmt.Errorf("Cairo public input size %d exceeds max size %d", len(pubInputBuffer), MaxPubInputSize)
+	}
+	if len(pubInputBuffer)%FeltSize != 0 {
+		return isVerified, fmt.Errorf("Cairo public input size %d is not a multiple of the felt size", len(pubInputBuffer))
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying Cairo proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	pubInputPtr := (*C.uchar)(unsafe.Pointer(&pubInputBuffer[0]))
+
+	r := (C.int32_t)(C.verify_cairo_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), pubInputPtr, (C.uint32_t)(len(pubInputBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying Cairo proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
@@ -0,0 +1,76 @@
+package cairo_test
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/cairo"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/cairo/cairo_fibonacci.proof"
+
+const LowSecurityProofFilePath = "../../scripts/test_files/cairo/cairo_fibonacci_low_security.proof"
+
+const PubInputFilePath = "../../scripts/test_files/cairo/cairo_fibonacci.pub"
+
+// readTestFiles reads the proof and public input generated with make generate_cairo_fibonacci_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_cairo_fibonacci_proof", ProofFilePath, PubInputFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return cairo.VerifyCairoProof(inputs[0], inputs[1])
+}
+
+func TestCairoProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestLowSecurityCairoProofDoesNotVerify(t *testing.T) {
+	inputs := verifiertest.ReadTestFiles(t, "generate_cairo_fibonacci_proof", LowSecurityProofFilePath, PubInputFilePath)
+
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof with a single FRI query should not verify")
+}
+
+func TestCairoProofWithWrongOutputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the last felt is the program output
+	inputs[1][len(inputs[1])-1] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with another program output")
+}
+
+func TestCairoProofWithNonCanonicalOutputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the most significant byte of the program output, no felt is that big
+	inputs[1][len(inputs[1])-cairo.FeltSize] = 0xff
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with a non canonical program output")
+}
+
+func TestCairoProofWithWrongProgramHashDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1][cairo.FeltSize-1] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with another program hash")
+}
+
+func TestCairoProofWithMissingOutputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1] = inputs[1][:len(inputs[1])-cairo.FeltSize]
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify without its program output")
+}
+
+func TestOversizedCairoProofDoesNotVerify(t *testing.T) {
+	inputs := [][]byte{make([]byte, cairo.MaxProofSize+1), make([]byte, cairo.FeltSize)}
+
+	verifiertest.ExpectRejected(t, verify, inputs, "oversized proof should not verify")
+}
+
+func TestUnalignedCairoPubInputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1] = append(inputs[1], 0)
+	verifiertest.ExpectRejected(t, verify, inputs, "public input not made of felts should not verify")
+}
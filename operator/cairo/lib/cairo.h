@@ -0,0 +1,5 @@
+#include <stdbool.h>
+#include <stdint.h>
+
+int32_t verify_cairo_proof_ffi(unsigned char *proof_buffer, uint32_t proof_len,
+                               unsigned char *pub_input_buffer, uint32_t pub_input_len);
@@ -0,0 +1,139 @@
+use log::error;
+use starknet_crypto::Felt;
+use swiftness::{parse, types::StarkProof, TransformTo};
+use swiftness_air::layout::recursive::Layout;
+
+// Keep in sync with the limits of the Go bindings
+const MAX_PROOF_SIZE: usize = 16 * 1024 * 1024;
+const MAX_PUB_INPUT_SIZE: usize = 1024 * 1024;
+const FELT_SIZE: usize = 32;
+// Cairo has no verification key, so nothing but the proof sets its config. Proofs below this
+// security level are rejected instead of trusting the one the proof declares
+const MIN_SECURITY_BITS: u64 = 96;
+
+fn read_public_input(bytes: &[u8]) -> Option<Vec<Felt>> {
+    bytes
+        .chunks_exact(FELT_SIZE)
+        .map(|chunk| {
+            let felt = Felt::from_bytes_be_slice(chunk);
+            // Only canonical encodings are accepted
+            (felt.to_bytes_be() == chunk).then_some(felt)
+        })
+        .collect()
+}
+
+fn inner_verify_cairo_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+) -> bool {
+    if proof_bytes.is_null() || pub_input_bytes.is_null() {
+        error!("Input buffer null");
+        return false;
+    }
+
+    if proof_len == 0 || pub_input_len == 0 {
+        error!("Input buffer length zero size");
+        return false;
+    }
+
+    if proof_len as usize > MAX_PROOF_SIZE || pub_input_len as usize > MAX_PUB_INPUT_SIZE {
+        error!("Input buffer exceeds max size");
+        return false;
+    }
+
+    if pub_input_len as usize % FELT_SIZE != 0 {
+        error!("Public input is not a sequence of felts");
+        return false;
+    }
+
+    let proof_bytes = unsafe { std::slice::from_raw_parts(proof_bytes, proof_len as usize) };
+
+    let pub_input_bytes =
+        unsafe { std::slice::from_raw_parts(pub_input_bytes, pub_input_len as usize) };
+
+    // The public input is the program hash followed by the program output
+    let Some(expected) = read_public_input(pub_input_bytes) else {
+        error!("Public input has non canonical felts");
+        return false;
+    };
+
+    let Ok(proof_json) = std::str::from_utf8(proof_bytes) else {
+        error!("Cairo proof is not valid UTF-8");
+        return false;
+    };
+
+    let Ok(proof) = parse(proof_json.to_string()) else {
+        error!("Could not parse Cairo proof");
+        return false;
+    };
+    let proof: StarkProof = proof.transform_to();
+
+    let min_security_bits = Felt::from(MIN_SECURITY_BITS);
+    if proof.config.security_bits() < min_security_bits {
+        error!("Cairo proof security is below {} bits", MIN_SECURITY_BITS);
+        return false;
+    }
+    let Ok((program_hash, output)) = proof.verify::<Layout>(min_security_bits) else {
+        return false;
+    };
+
+    expected.len() == output.len() + 1 && expected[0] == program_hash && expected[1..] == output[..]
+}
+
+#[no_mangle]
+pub extern "C" fn verify_cairo_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+) -> i32 {
+    let result = std::panic::catch_unwind(|| {
+        inner_verify_cairo_proof_ffi(proof_bytes, proof_len, pub_input_bytes, pub_input_len)
+    });
+
+    match result {
+        Ok(v) => v as i32,
+        Err(_) => -1,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn verify_cairo_fails_with_malformed_proof() {
+        let proof = [1u8, 2, 3, 4];
+        let pub_input = [0u8; FELT_SIZE];
+
+        let result = verify_cairo_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            pub_input.as_ptr(),
+            pub_input.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+
+    #[test]
+    fn read_public_input_rejects_non_canonical_felts() {
+        assert!(read_public_input(&[0u8; FELT_SIZE]).is_some());
+        assert!(read_public_input(&[0xFFu8; FELT_SIZE]).is_none());
+    }
+
+    #[test]
+    fn verify_cairo_fails_with_unaligned_pub_input() {
+        let proof = [1u8, 2, 3, 4];
+        let pub_input = [0u8; FELT_SIZE + 1];
+
+        let result = verify_cairo_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            pub_input.as_ptr(),
+            pub_input.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+}
@@ -1 +1,32 @@
 package actions
+
+import (
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+// withPushedMetrics runs the action of a one-off command, pushing its run to the Pushgateway of the
+// metrics config, since the command doesn't run long enough to be scraped
+func withPushedMetrics(command string, action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		start := time.Now()
+		err := action(ctx)
+
+		logger, loggerErr := sdklogging.NewZapLogger(sdklogging.Production)
+		if loggerErr != nil {
+			return err
+		}
+		reg := prometheus.NewRegistry()
+		commandMetrics := metrics.NewMetricsWithConfig("", reg, logger, config.NewMetricsConfig(ctx.String(config.ConfigFileFlag.Name)))
+		commandMetrics.ObserveCommand(command, time.Since(start), err)
+		if pushErr := commandMetrics.Push(reg); pushErr != nil {
+			logger.Warn("Could not push the command metrics", "command", command, "err", pushErr)
+		}
+		return err
+	}
+}
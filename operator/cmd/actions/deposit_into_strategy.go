@@ -34,7 +34,7 @@ var DepositIntoStrategyCommand = &cli.Command{
 	Name:        "deposit-into-strategy",
 	Description: "CLI command to deposit into a given strategy",
 	Flags:       depositFlags,
-	Action:      depositIntoStrategyMain,
+	Action:      withPushedMetrics("deposit-into-strategy", depositIntoStrategyMain),
 }
 
 var depositFlags = []cli.Flag{
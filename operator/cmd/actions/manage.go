@@ -0,0 +1,165 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
+)
+
+var (
+	quorumsFlag = &cli.IntSliceFlag{
+		Name:  "quorums",
+		Usage: "Quorum numbers to act on",
+		Value: cli.NewIntSlice(0),
+	}
+	socketFlag = &cli.StringFlag{
+		Name:     "socket",
+		Required: true,
+		Usage:    "New socket of the operator",
+	}
+)
+
+var DeregisterCommand = &cli.Command{
+	Name:        "deregister",
+	Usage:       "Deregister operator from Aligned Layer",
+	Description: "CLI command to deregister the operator from the given quorums, and from Aligned Layer once it's in none",
+	Flags:       []cli.Flag{config.ConfigFileFlag, quorumsFlag},
+	Action:      withPushedMetrics("deregister", deregisterOperatorMain),
+}
+
+var UpdateSocketCommand = &cli.Command{
+	Name:        "update-socket",
+	Usage:       "Update the socket the operator registered with",
+	Description: "CLI command to update the registered socket of the operator",
+	Flags:       []cli.Flag{config.ConfigFileFlag, socketFlag},
+	Action:      withPushedMetrics("update-socket", updateSocketMain),
+}
+
+var QuorumsCommand = &cli.Command{
+	Name:  "quorums",
+	Usage: "Opt in to or out of quorums",
+	Subcommands: []*cli.Command{
+		{
+			Name:        "opt-in",
+			Usage:       "Register the operator in the given quorums",
+			Description: "CLI command to register the operator in quorums it isn't registered in yet",
+			Flags:       []cli.Flag{config.ConfigFileFlag, quorumsFlag},
+			Action:      withPushedMetrics("quorums-opt-in", optInQuorumsMain),
+		},
+		{
+			Name:        "opt-out",
+			Usage:       "Deregister the operator from the given quorums",
+			Description: "CLI command to deregister the operator from the given quorums",
+			Flags:       []cli.Flag{config.ConfigFileFlag, quorumsFlag},
+			Action:      withPushedMetrics("quorums-opt-out", deregisterOperatorMain),
+		},
+	},
+}
+
+var StakeCommand = &cli.Command{
+	Name:        "stake",
+	Usage:       "Show the operator stake and weight in each quorum",
+	Description: "CLI command to query the current stake of the operator, and its share of the quorum total stake",
+	Flags:       []cli.Flag{config.ConfigFileFlag},
+	Action:      stakeMain,
+}
+
+func quorumNumbers(ctx *cli.Context) (eigentypes.QuorumNums, error) {
+	quorums := ctx.IntSlice(quorumsFlag.Name)
+	if len(quorums) == 0 {
+		return nil, errors.New("no quorums given")
+	}
+	quorumNumbers := make(eigentypes.QuorumNums, 0, len(quorums))
+	for _, quorum := range quorums {
+		if quorum < 0 || quorum > 255 {
+			return nil, fmt.Errorf("invalid quorum number %d", quorum)
+		}
+		quorumNumbers = append(quorumNumbers, eigentypes.QuorumNum(quorum))
+	}
+	return quorumNumbers, nil
+}
+
+func deregisterOperatorMain(ctx *cli.Context) error {
+	operatorConfig := config.NewOperatorConfig(ctx.String(config.ConfigFileFlag.Name))
+	ecdsaConfig := config.NewEcdsaConfig(ctx.String(config.ConfigFileFlag.Name), operatorConfig.BaseConfig.ChainId)
+	quorums, err := quorumNumbers(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = operator.DeregisterOperator(context.Background(), operatorConfig, ecdsaConfig, operatorConfig.BlsConfig.PublicKeyG1(), quorums)
+	if err != nil {
+		return err
+	}
+	operatorConfig.BaseConfig.Logger.Info("Operator deregistered", "quorums", quorums)
+	return nil
+}
+
+func optInQuorumsMain(ctx *cli.Context) error {
+	operatorConfig := config.NewOperatorConfig(ctx.String(config.ConfigFileFlag.Name))
+	ecdsaConfig := config.NewEcdsaConfig(ctx.String(config.ConfigFileFlag.Name), operatorConfig.BaseConfig.ChainId)
+	quorums, err := quorumNumbers(ctx)
+	if err != nil {
+		return err
+	}
+	// the BLS private key is needed in case the operator isn't registered yet
+	if operatorConfig.BlsConfig.KeyPair == nil {
+		return errors.New("opting in to quorums requires the BLS private key store, it can't be done with a remote signer")
+	}
+
+	err = operator.RegisterOperatorInQuorums(context.Background(), operatorConfig, ecdsaConfig, quorums)
+	if err != nil {
+		return err
+	}
+	operatorConfig.BaseConfig.Logger.Info("Operator registered", "quorums", quorums)
+	return nil
+}
+
+func updateSocketMain(ctx *cli.Context) error {
+	operatorConfig := config.NewOperatorConfig(ctx.String(config.ConfigFileFlag.Name))
+	ecdsaConfig := config.NewEcdsaConfig(ctx.String(config.ConfigFileFlag.Name), operatorConfig.BaseConfig.ChainId)
+
+	err := operator.UpdateOperatorSocket(context.Background(), operatorConfig, ecdsaConfig, ctx.String(socketFlag.Name))
+	if err != nil {
+		return err
+	}
+	operatorConfig.BaseConfig.Logger.Info("Operator socket updated", "socket", ctx.String(socketFlag.Name))
+	return nil
+}
+
+func stakeMain(ctx *cli.Context) error {
+	operatorConfig := config.NewOperatorConfig(ctx.String(config.ConfigFileFlag.Name))
+	avsReader, err := chainio.NewAvsReaderFromConfig(operatorConfig.BaseConfig)
+	if err != nil {
+		return err
+	}
+
+	stakes, err := avsReader.GetOperatorQuorumStakes(operatorConfig.Operator.Address)
+	if err != nil {
+		return err
+	}
+	if len(stakes) == 0 {
+		fmt.Printf("Operator %s is not registered in any quorum\n", operatorConfig.Operator.Address)
+		return nil
+	}
+	for _, stake := range stakes {
+		fmt.Printf("Quorum %d: stake %s of %s (weight %s%%)\n", stake.Quorum, stake.Stake, stake.TotalStake, stakeWeight(stake.Stake, stake.TotalStake))
+	}
+	return nil
+}
+
+// stakeWeight returns the percentage of the total stake that stake is, with 2 decimals.
+func stakeWeight(stake *big.Int, totalStake *big.Int) string {
+	if totalStake.Sign() == 0 {
+		return "0.00"
+	}
+	weight := new(big.Rat).SetFrac(new(big.Int).Mul(stake, big.NewInt(100)), totalStake)
+	return weight.FloatString(2)
+}
@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"errors"
 	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
 	"time"
 
@@ -19,13 +20,18 @@ var RegisterCommand = &cli.Command{
 	Usage:       "Register operator with Aligned Layer",
 	Description: "CLI command to register opeartor with Aligned Layer",
 	Flags:       registerFlags,
-	Action:      registerOperatorMain,
+	Action:      withPushedMetrics("register", registerOperatorMain),
 }
 
 func registerOperatorMain(ctx *cli.Context) error {
 	operatorConfig := config.NewOperatorConfig(ctx.String(config.ConfigFileFlag.Name))
 	ecdsaConfig := config.NewEcdsaConfig(ctx.String(config.ConfigFileFlag.Name), operatorConfig.BaseConfig.ChainId)
 
+	// the BLS private key is needed to prove its ownership on registration
+	if operatorConfig.BlsConfig.KeyPair == nil {
+		return errors.New("registering the operator requires the BLS private key store, it can't be done with a remote signer")
+	}
+
 	quorumNumbers := []byte{0}
 
 	// Generate salt and expiry
@@ -0,0 +1,103 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
+)
+
+var forceFlag = &cli.BoolFlag{
+	Name:  "force",
+	Usage: "Retire the previous operator before the overlap window ends",
+}
+
+// RotateKeysCommand rotates the operator keys. Since the keys of a registered operator can't be
+// changed, the new keys are registered as a new operator, both sign task responses until the
+// overlap window configured in key_rotation ends, and then the previous operator is retired.
+var RotateKeysCommand = &cli.Command{
+	Name:        "rotate-keys",
+	Usage:       "Rotate the operator BLS and ECDSA keys",
+	Description: "CLI commands to register the new operator keys and retire the previous ones, configured in key_rotation",
+	Subcommands: []*cli.Command{
+		{
+			Name:        "register",
+			Usage:       "Register the new operator keys",
+			Description: "Registers the operator with the keys of the config, while the previous operator keeps signing",
+			Flags:       []cli.Flag{config.ConfigFileFlag},
+			Action:      withPushedMetrics("rotate-keys-register", registerRotatedKeysMain),
+		},
+		{
+			Name:        "retire",
+			Usage:       "Deregister the previous operator once the overlap window ended",
+			Description: "Deregisters the previous operator of the key_rotation config with its ECDSA key",
+			Flags:       []cli.Flag{config.ConfigFileFlag, forceFlag},
+			Action:      withPushedMetrics("rotate-keys-retire", retirePreviousKeysMain),
+		},
+	},
+}
+
+func registerRotatedKeysMain(ctx *cli.Context) error {
+	operatorConfig := config.NewOperatorConfig(ctx.String(config.ConfigFileFlag.Name))
+	if operatorConfig.KeyRotation == nil {
+		return errors.New("key_rotation is not set in the config")
+	}
+	if err := checkOperatorRegistered(operatorConfig, operatorConfig.KeyRotation.PreviousOperatorAddress); err != nil {
+		return err
+	}
+
+	if err := registerOperatorMain(ctx); err != nil {
+		return err
+	}
+	operatorConfig.BaseConfig.Logger.Infof("New operator keys registered. Restart the operator with this config to sign with both keys until %s, and then run rotate-keys retire",
+		operatorConfig.KeyRotation.OverlapUntil)
+	return nil
+}
+
+func retirePreviousKeysMain(ctx *cli.Context) error {
+	operatorConfig := config.NewOperatorConfig(ctx.String(config.ConfigFileFlag.Name))
+	keyRotation := operatorConfig.KeyRotation
+	if keyRotation == nil {
+		return errors.New("key_rotation is not set in the config")
+	}
+	if time.Now().Before(keyRotation.OverlapUntil) && !ctx.Bool(forceFlag.Name) {
+		return fmt.Errorf("the overlap window ends at %s, retiring the previous operator before may miss signing windows. Use --force to retire it anyway", keyRotation.OverlapUntil)
+	}
+	// the previous operator can only be retired once the new one is signing in its place
+	if err := checkOperatorRegistered(operatorConfig, operatorConfig.Operator.Address); err != nil {
+		return err
+	}
+
+	previousEcdsaConfig, err := keyRotation.PreviousEcdsaConfig(operatorConfig.BaseConfig.ChainId)
+	if err != nil {
+		return err
+	}
+	err = operator.DeregisterOperator(context.Background(), operatorConfig, previousEcdsaConfig, keyRotation.PreviousBlsKeyPair.GetPubKeyG1(), eigentypes.QuorumNums{0})
+	if err != nil {
+		return err
+	}
+	operatorConfig.BaseConfig.Logger.Infof("Previous operator %s retired, key_rotation can now be removed from the config", keyRotation.PreviousOperatorAddress)
+	return nil
+}
+
+func checkOperatorRegistered(operatorConfig *config.OperatorConfig, address ethcommon.Address) error {
+	avsReader, err := chainio.NewAvsReaderFromConfig(operatorConfig.BaseConfig)
+	if err != nil {
+		return err
+	}
+	registered, err := avsReader.IsOperatorRegistered(address)
+	if err != nil {
+		return err
+	}
+	if !registered {
+		return fmt.Errorf("operator %s is not registered", address)
+	}
+	return nil
+}
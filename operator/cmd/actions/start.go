@@ -3,6 +3,9 @@ package actions
 import (
 	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/urfave/cli/v2"
 	"github.com/yetanotherco/aligned_layer/core/config"
@@ -39,8 +42,12 @@ func operatorMain(ctx *cli.Context) error {
 		return err
 	}
 
+	// On SIGTERM or SIGINT the operator stops accepting batches and finishes the ones in progress
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
 	operator.Logger.Info("Operator starting...")
-	err = operator.Start(context.Background())
+	err = operator.Start(signalCtx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,28 @@
+package actions
+
+import (
+	"os"
+
+	"github.com/urfave/cli/v2"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
+)
+
+var MaxMemoryFlag = &cli.Int64Flag{
+	Name:  operator.SandboxedVerifierMaxMemoryFlag,
+	Usage: "Max memory of the verifier process in bytes, 0 means no limit",
+}
+
+// VerifyProofCommand is run by the operator itself to verify proofs in a sandboxed subprocess.
+var VerifyProofCommand = &cli.Command{
+	Name:        operator.SandboxedVerifierCommand,
+	Description: "CLI command to verify a single proof read from stdin in a sandboxed process, only meant to be run by the operator",
+	Flags:       []cli.Flag{MaxMemoryFlag},
+	Action:      verifyProofMain,
+	Hidden:      true,
+}
+
+func verifyProofMain(ctx *cli.Context) error {
+	result := os.NewFile(operator.SandboxedVerifierResultFd, "result")
+	defer result.Close()
+	return operator.RunSandboxedVerifier(ctx.Int64(operator.SandboxedVerifierMaxMemoryFlag), os.Stdin, result)
+}
@@ -18,8 +18,14 @@ func main() {
 		Name: "Aligned Layer Node Operator",
 		Commands: []*cli.Command{
 			actions.RegisterCommand,
+			actions.DeregisterCommand,
+			actions.UpdateSocketCommand,
+			actions.QuorumsCommand,
+			actions.StakeCommand,
+			actions.RotateKeysCommand,
 			actions.StartCommand,
 			actions.DepositIntoStrategyCommand,
+			actions.VerifyProofCommand,
 		},
 		Version: Version,
 	}
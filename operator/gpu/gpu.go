@@ -0,0 +1,15 @@
+// Package gpu offloads the heavy parts of proof verification to a CUDA GPU using icicle.
+//
+// It is only available when the operator is built with the `gpu` build tag, which requires the
+// icicle library to be installed (see `make build_operator_gpu`). Otherwise every function returns
+// ErrUnavailable, so callers can always fall back to the CPU verifiers.
+package gpu
+
+import "errors"
+
+var (
+	// ErrUnavailable is returned when the operator was built without GPU support or no GPU is found.
+	ErrUnavailable = errors.New("gpu verification is not available")
+	// ErrUnsupported is returned for proofs the GPU verifier can't handle, which must be verified on the CPU.
+	ErrUnsupported = errors.New("proof is not supported by the gpu verifier")
+)
@@ -0,0 +1,23 @@
+//go:build !gpu
+
+package gpu_test
+
+import (
+	"errors"
+	"testing"
+
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/yetanotherco/aligned_layer/operator/gpu"
+)
+
+func TestGpuIsUnavailableWithoutBuildTag(t *testing.T) {
+	if gpu.Enabled {
+		t.Fatalf("GPU verification should be disabled without the gpu build tag")
+	}
+	if err := gpu.Init(); !errors.Is(err, gpu.ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+	if _, err := gpu.VerifyGroth16Bn254(&groth16_bn254.Proof{}, &groth16_bn254.VerifyingKey{}, nil); !errors.Is(err, gpu.ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+//go:build gpu
+
+package gpu
+
+import (
+	"fmt"
+	"unsafe"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	goicicle "github.com/ingonyama-zk/icicle/goicicle"
+	icicle "github.com/ingonyama-zk/icicle/goicicle/curves/bn254"
+	iciclegnark "github.com/ingonyama-zk/iciclegnark/curves/bn254"
+)
+
+// Enabled is true when the operator is built with GPU support.
+const Enabled = true
+
+// Init checks a GPU can be used for verification.
+func Init() error {
+	devicePtr, err := goicicle.CudaMalloc(fp.Bytes)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	goicicle.CudaFree(devicePtr)
+	return nil
+}
+
+// VerifyGroth16Bn254 verifies a Groth16 BN254 proof computing the public input multi scalar multiplication on the GPU.
+// It follows groth16.Verify from gnark, checking e(Ar, Bs) · e(Krs, -δ) · e(Σx·K, -γ) · e(-α, β) == 1.
+// Verifying keys with commitments (e.g. circuits using gnark's Commit) are not supported and return ErrUnsupported.
+func VerifyGroth16Bn254(proof *groth16_bn254.Proof, vk *groth16_bn254.VerifyingKey, publicWitness fr.Vector) (bool, error) {
+	if len(vk.PublicAndCommitmentCommitted) != 0 || len(proof.Commitments) != 0 {
+		return false, ErrUnsupported
+	}
+	if len(publicWitness) != len(vk.G1.K)-1 {
+		return false, nil
+	}
+	if !proof.Ar.IsInSubGroup() || !proof.Krs.IsInSubGroup() || !proof.Bs.IsInSubGroup() {
+		return false, nil
+	}
+
+	kSum, err := msmOnDevice(vk.G1.K[1:], publicWitness)
+	if err != nil {
+		return false, err
+	}
+	kSum.AddMixed(&vk.G1.K[0])
+
+	var kSumAff, alphaNeg curve.G1Affine
+	kSumAff.FromJacobian(&kSum)
+	alphaNeg.Neg(&vk.G1.Alpha)
+
+	var deltaNeg, gammaNeg curve.G2Affine
+	deltaNeg.Neg(&vk.G2.Delta)
+	gammaNeg.Neg(&vk.G2.Gamma)
+
+	return curve.PairingCheck(
+		[]curve.G1Affine{proof.Ar, proof.Krs, kSumAff, alphaNeg},
+		[]curve.G2Affine{proof.Bs, deltaNeg, gammaNeg, vk.G2.Beta},
+	)
+}
+
+func msmOnDevice(points []curve.G1Affine, scalars fr.Vector) (curve.G1Jac, error) {
+	var result curve.G1Jac
+	if len(scalars) == 0 {
+		return result, nil
+	}
+	// icicle doesn't handle points at infinity, see https://github.com/ingonyama-zk/icicle/issues/169
+	for i := range points {
+		if points[i].IsInfinity() {
+			return result, ErrUnsupported
+		}
+	}
+
+	copyDone := make(chan unsafe.Pointer, 1)
+	iciclegnark.CopyPointsToDevice(points, len(points)*fp.Bytes*2, copyDone)
+	pointsDevice := <-copyDone
+	if pointsDevice == nil {
+		return result, ErrUnavailable
+	}
+	defer iciclegnark.FreeDevicePointer(pointsDevice)
+
+	iciclegnark.CopyToDevice(scalars, len(scalars)*fr.Bytes, copyDone)
+	scalarsDevice := <-copyDone
+	if scalarsDevice == nil {
+		return result, ErrUnavailable
+	}
+	defer iciclegnark.FreeDevicePointer(scalarsDevice)
+
+	_, resultDevice, err := iciclegnark.MsmOnDevice(scalarsDevice, pointsDevice, len(scalars), false)
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer iciclegnark.FreeDevicePointer(resultDevice)
+
+	resultHost := make([]icicle.G1ProjectivePoint, 1)
+	goicicle.CudaMemCpyDtoH[icicle.G1ProjectivePoint](resultHost, resultDevice, fp.Bytes*3)
+	return *iciclegnark.G1ProjectivePointToGnarkJac(&resultHost[0]), nil
+}
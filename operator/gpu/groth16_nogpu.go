@@ -0,0 +1,21 @@
+//go:build !gpu
+
+package gpu
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+)
+
+// Enabled is true when the operator is built with GPU support.
+const Enabled = false
+
+// Init checks a GPU can be used for verification.
+func Init() error {
+	return ErrUnavailable
+}
+
+// VerifyGroth16Bn254 verifies a Groth16 BN254 proof computing the public input multi scalar multiplication on the GPU.
+func VerifyGroth16Bn254(proof *groth16_bn254.Proof, vk *groth16_bn254.VerifyingKey, publicWitness fr.Vector) (bool, error) {
+	return false, ErrUnavailable
+}
@@ -0,0 +1,50 @@
+package halo2
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libhalo2_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -lhalo2_verifier_ffi
+
+#include "lib/halo2.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// VerifyHalo2Proof verifies a Halo2 proof against its parametrized verification key, see ParseHalo2Params.
+// The public input holds the circuit instance columns.
+func VerifyHalo2Proof(proofBuffer []byte, pubInputBuffer []byte, verificationKeyBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(pubInputBuffer) == 0 || len(verificationKeyBuffer) == 0 {
+		return isVerified, err
+	}
+	if _, err = ParseHalo2Params(verificationKeyBuffer); err != nil {
+		return isVerified, err
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying Halo2 proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	pubInputPtr := (*C.uchar)(unsafe.Pointer(&pubInputBuffer[0]))
+	verificationKeyPtr := (*C.uchar)(unsafe.Pointer(&verificationKeyBuffer[0]))
+
+	r := (C.int32_t)(C.verify_halo2_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), pubInputPtr, (C.uint32_t)(len(pubInputBuffer)), verificationKeyPtr, (C.uint32_t)(len(verificationKeyBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying Halo2 proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
@@ -0,0 +1,104 @@
+package halo2_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/halo2"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/halo2/halo2_fibonacci.proof"
+
+const PubInputFilePath = "../../scripts/test_files/halo2/halo2_fibonacci.pub"
+
+const ParamsFilePath = "../../scripts/test_files/halo2/halo2_fibonacci.params"
+
+// readTestFiles reads the KZG over bn256 proof, instances and params generated with
+// make generate_halo2_fibonacci_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_halo2_fibonacci_proof", ProofFilePath, PubInputFilePath, ParamsFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return halo2.VerifyHalo2Proof(inputs[0], inputs[1], inputs[2])
+}
+
+func TestHalo2KzgBn256ProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestHalo2ProofWithWrongInstanceDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the last instance is the Fibonacci result, its first byte the least significant one
+	inputs[1][len(inputs[1])-32] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with another instance")
+}
+
+func TestHalo2ProofWithOtherCommitmentSchemeDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[2][1] = byte(halo2.IPA)
+	verifiertest.ExpectNotVerified(t, verify, inputs, "KZG proof should not verify as an IPA one")
+}
+
+func TestHalo2ProofWithTamperedVerifyingKeyDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	params, err := halo2.ParseHalo2Params(inputs[2])
+	if err != nil {
+		t.Fatalf("Unexpected error parsing params: %v", err)
+	}
+	// the verifying key shares the params buffer
+	params.VerifyingKey[len(params.VerifyingKey)-1] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with a tampered verifying key")
+}
+
+func TestHalo2ProofWithInvalidParamsDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[2] = inputs[2][:10]
+	verifiertest.ExpectRejected(t, verify, inputs, "proof with invalid params should not verify")
+}
+
+func TestParseHalo2Params(t *testing.T) {
+	params, err := halo2.ParseHalo2Params(paramsBlob(halo2.IPA, halo2.Pasta, 12))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing params: %v", err)
+	}
+	if params.CommitmentScheme != halo2.IPA || params.Curve != halo2.Pasta || params.K != 12 {
+		t.Errorf("Unexpected params header: %+v", params)
+	}
+	if string(params.ConstraintSystem) != "cs" || string(params.VerifyingKey) != "vk" || string(params.VerifierParams) != "params" {
+		t.Errorf("Unexpected params contents: %+v", params)
+	}
+}
+
+func TestParseHalo2ParamsRejectsKzgOverPasta(t *testing.T) {
+	if _, err := halo2.ParseHalo2Params(paramsBlob(halo2.KZG, halo2.Pasta, 10)); err == nil {
+		t.Errorf("KZG over pasta should be rejected")
+	}
+}
+
+func TestParseHalo2ParamsRejectsInvalidK(t *testing.T) {
+	if _, err := halo2.ParseHalo2Params(paramsBlob(halo2.KZG, halo2.Bn256, halo2.MaxK+1)); err == nil {
+		t.Errorf("k bigger than the max should be rejected")
+	}
+}
+
+func TestParseHalo2ParamsRejectsTruncatedBlob(t *testing.T) {
+	blob := paramsBlob(halo2.KZG, halo2.Bn256, 10)
+	if _, err := halo2.ParseHalo2Params(blob[:10]); err == nil {
+		t.Errorf("truncated params should be rejected")
+	}
+}
+
+func paramsBlob(commitmentScheme halo2.CommitmentScheme, curve halo2.Curve, k uint8) []byte {
+	blob := []byte{halo2.ParamsVersion, byte(commitmentScheme), byte(curve), k}
+	blob = binary.LittleEndian.AppendUint32(blob, 2)
+	blob = append(blob, "cs"...)
+	blob = binary.LittleEndian.AppendUint32(blob, 2)
+	blob = append(blob, "vk"...)
+	return append(blob, "params"...)
+}
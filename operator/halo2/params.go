@@ -0,0 +1,99 @@
+package halo2
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// CommitmentScheme is the polynomial commitment scheme a Halo2 circuit is proven with.
+type CommitmentScheme uint8
+
+const (
+	KZG CommitmentScheme = iota
+	IPA
+)
+
+// Curve is the curve a Halo2 circuit is defined over.
+type Curve uint8
+
+const (
+	Bn256 Curve = iota
+	Pasta
+)
+
+const (
+	// ParamsVersion is the only supported version of the parameter blob.
+	ParamsVersion = 1
+	// MaxK is the max log2 of the number of rows of a circuit, the biggest public KZG setups are of this size.
+	MaxK = 26
+	// paramsHeaderSize is the size of the version, commitment scheme, curve and k fields.
+	paramsHeaderSize = 4
+)
+
+// Halo2Params is the parameter blob sent as the verification key of Halo2 proofs.
+// Its encoding is:
+//
+//	version (1 byte) | commitment scheme (1 byte) | curve (1 byte) | k (1 byte) |
+//	constraint system length (4 bytes LE) | constraint system |
+//	verifying key length (4 bytes LE) | verifying key | verifier params
+type Halo2Params struct {
+	CommitmentScheme CommitmentScheme
+	Curve            Curve
+	K                uint8
+	ConstraintSystem []byte
+	VerifyingKey     []byte
+	VerifierParams   []byte
+}
+
+// ParseHalo2Params decodes and validates a Halo2 parameter blob.
+// KZG is only supported over bn256, since the pasta curves are not pairing friendly, and IPA over both curves.
+func ParseHalo2Params(blob []byte) (*Halo2Params, error) {
+	if len(blob) < paramsHeaderSize {
+		return nil, fmt.Errorf("params blob too short")
+	}
+	if blob[0] != ParamsVersion {
+		return nil, fmt.Errorf("unsupported params version %d", blob[0])
+	}
+
+	params := &Halo2Params{
+		CommitmentScheme: CommitmentScheme(blob[1]),
+		Curve:            Curve(blob[2]),
+		K:                blob[3],
+	}
+	switch {
+	case params.CommitmentScheme == KZG && params.Curve == Bn256:
+	case params.CommitmentScheme == IPA && (params.Curve == Bn256 || params.Curve == Pasta):
+	default:
+		return nil, fmt.Errorf("unsupported commitment scheme %d over curve %d", params.CommitmentScheme, params.Curve)
+	}
+	if params.K == 0 || params.K > MaxK {
+		return nil, fmt.Errorf("k must be between 1 and %d, got %d", MaxK, params.K)
+	}
+
+	rest := blob[paramsHeaderSize:]
+	var err error
+	if params.ConstraintSystem, rest, err = readLengthPrefixed(rest); err != nil {
+		return nil, fmt.Errorf("invalid constraint system: %v", err)
+	}
+	if params.VerifyingKey, rest, err = readLengthPrefixed(rest); err != nil {
+		return nil, fmt.Errorf("invalid verifying key: %v", err)
+	}
+	if len(rest) == 0 {
+		return nil, fmt.Errorf("missing verifier params")
+	}
+	params.VerifierParams = rest
+
+	return params, nil
+}
+
+func readLengthPrefixed(buffer []byte) ([]byte, []byte, error) {
+	if len(buffer) < 4 {
+		return nil, nil, fmt.Errorf("missing length")
+	}
+	length := binary.LittleEndian.Uint32(buffer[:4])
+	buffer = buffer[4:]
+	if length == 0 || uint64(length) > uint64(len(buffer)) {
+		return nil, nil, fmt.Errorf("invalid length %d", length)
+	}
+	return buffer[:length], buffer[length:], nil
+}
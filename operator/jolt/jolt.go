@@ -0,0 +1,83 @@
+package jolt
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libjolt_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -ljolt_verifier_ffi
+
+#include "lib/jolt.h"
+*/
+import "C"
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"unsafe"
+)
+
+const (
+	MaxProofSize     = 64 * 1024 * 1024
+	MaxElfSize       = 16 * 1024 * 1024
+	MaxProgramIoSize = 1024 * 1024
+)
+
+// ValidateJoltInput checks the sizes of the inputs and that the ELF is a 32 bit RISC-V executable,
+// the only target Jolt proves, so obviously invalid inputs never reach the verifier.
+func ValidateJoltInput(proofBuffer []byte, elfBuffer []byte, programIoBuffer []byte) error {
+	if len(proofBuffer) > MaxProofSize {
+		return fmt.Errorf("proof size %d exceeds max size %d", len(proofBuffer), MaxProofSize)
+	}
+	if len(elfBuffer) > MaxElfSize {
+		return fmt.Errorf("ELF size %d exceeds max size %d", len(elfBuffer), MaxElfSize)
+	}
+	if len(programIoBuffer) > MaxProgramIoSize {
+		return fmt.Errorf("program io size %d exceeds max size %d", len(programIoBuffer), MaxProgramIoSize)
+	}
+
+	elfFile, err := elf.NewFile(bytes.NewReader(elfBuffer))
+	if err != nil {
+		return fmt.Errorf("invalid ELF: %v", err)
+	}
+	defer elfFile.Close()
+	if elfFile.Class != elf.ELFCLASS32 || elfFile.Machine != elf.EM_RISCV || elfFile.Type != elf.ET_EXEC {
+		return fmt.Errorf("ELF is not a 32 bit RISC-V executable")
+	}
+
+	return nil
+}
+
+// VerifyJoltProof verifies a Jolt proof of the execution of the given RISC-V ELF.
+// The program io holds the program inputs and outputs, so it's the public input of the proof.
+func VerifyJoltProof(proofBuffer []byte, elfBuffer []byte, programIoBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(elfBuffer) == 0 || len(programIoBuffer) == 0 {
+		return isVerified, err
+	}
+	if err = ValidateJoltInput(proofBuffer, elfBuffer, programIoBuffer); err != nil {
+		return isVerified, err
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying Jolt proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	elfPtr := (*C.uchar)(unsafe.Pointer(&elfBuffer[0]))
+	programIoPtr := (*C.uchar)(unsafe.Pointer(&programIoBuffer[0]))
+
+	r := (C.int32_t)(C.verify_jolt_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), elfPtr, (C.uint32_t)(len(elfBuffer)), programIoPtr, (C.uint32_t)(len(programIoBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying Jolt proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
@@ -0,0 +1,98 @@
+package jolt_test
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/jolt"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/jolt/jolt_fibonacci.proof"
+
+const ElfFilePath = "../../scripts/test_files/jolt/jolt_fibonacci.elf"
+
+const ProgramIoFilePath = "../../scripts/test_files/jolt/jolt_fibonacci.io"
+
+// readTestFiles reads the proof, guest ELF and program io generated with
+// make generate_jolt_fibonacci_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_jolt_fibonacci_proof", ProofFilePath, ElfFilePath, ProgramIoFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return jolt.VerifyJoltProof(inputs[0], inputs[1], inputs[2])
+}
+
+func TestJoltProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestJoltProofWithWrongProgramIoDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the program io ends with the serialized memory layout, so the byte flipped is in the
+	// inputs or outputs, which are serialized first
+	inputs[2][len(inputs[2])/4] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof with a wrong program io should not verify")
+}
+
+func TestJoltProofWithWrongElfDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	elfFile, err := elf.NewFile(bytes.NewReader(inputs[1]))
+	if err != nil {
+		t.Fatalf("could not parse test ELF: %s", err)
+	}
+	text := elfFile.Section(".text")
+	if text == nil || text.Size == 0 {
+		t.Fatalf("test ELF has no .text section")
+	}
+	// changing an instruction makes it the ELF of another program
+	inputs[1][text.Offset+text.Size/2] ^= 1
+	verified, err := verify(inputs)
+	if verified {
+		t.Errorf("proof with the ELF of another program should not verify, err: %v", err)
+	}
+}
+
+func TestJoltProofWithInvalidElfDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1] = []byte{5, 6, 7, 8}
+	verifiertest.ExpectRejected(t, verify, inputs, "proof with an invalid ELF should not verify")
+}
+
+func TestValidateJoltInputAcceptsGeneratedFiles(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	if err := jolt.ValidateJoltInput(inputs[0], inputs[1], inputs[2]); err != nil {
+		t.Errorf("generated files should be accepted: %s", err)
+	}
+}
+
+func TestValidateJoltInputRejectsNonRiscvElf(t *testing.T) {
+	// x86-64 machine id
+	if err := jolt.ValidateJoltInput([]byte{1}, riscvElfHeader(0x3E), []byte{1}); err == nil {
+		t.Errorf("non RISC-V ELF should be rejected")
+	}
+}
+
+func TestValidateJoltInputRejectsOversizedProgramIo(t *testing.T) {
+	if err := jolt.ValidateJoltInput([]byte{1}, riscvElfHeader(0xF3), make([]byte, jolt.MaxProgramIoSize+1)); err == nil {
+		t.Errorf("oversized program io should be rejected")
+	}
+}
+
+// riscvElfHeader builds a minimal 32 bit little endian executable ELF header for the given machine.
+func riscvElfHeader(machine uint16) []byte {
+	header := make([]byte, 52)
+	copy(header, []byte{0x7f, 'E', 'L', 'F', 1, 1, 1})
+	binary.LittleEndian.PutUint16(header[16:], 2) // ET_EXEC
+	binary.LittleEndian.PutUint16(header[18:], machine)
+	binary.LittleEndian.PutUint32(header[20:], 1) // EV_CURRENT
+	binary.LittleEndian.PutUint16(header[40:], 52)
+	return header
+}
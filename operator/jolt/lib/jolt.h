@@ -0,0 +1,6 @@
+#include <stdbool.h>
+#include <stdint.h>
+
+int32_t verify_jolt_proof_ffi(unsigned char *proof_buffer, uint32_t proof_len,
+                              unsigned char *elf_buffer, uint32_t elf_len,
+                              unsigned char *program_io_buffer, uint32_t program_io_len);
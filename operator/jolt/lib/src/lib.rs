@@ -0,0 +1,113 @@
+use jolt_core::jolt::vm::rv32i_vm::RV32IJoltVM;
+use jolt_core::jolt::vm::Jolt;
+use jolt_sdk::host_utils::JoltHyperKZGProof;
+use jolt_sdk::{JoltDevice, Serializable};
+use log::error;
+
+// Proofs are expected to be generated with the default jolt-sdk limits
+const MAX_BYTECODE_SIZE: usize = 1 << 20;
+const MAX_MEMORY_SIZE: usize = 1 << 24;
+const MAX_TRACE_LENGTH: usize = 1 << 24;
+
+fn inner_verify_jolt_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    elf_bytes: *const u8,
+    elf_len: u32,
+    program_io_bytes: *const u8,
+    program_io_len: u32,
+) -> bool {
+    if proof_bytes.is_null() || elf_bytes.is_null() || program_io_bytes.is_null() {
+        error!("Input buffer null");
+        return false;
+    }
+
+    if proof_len == 0 || elf_len == 0 || program_io_len == 0 {
+        error!("Input buffer length zero size");
+        return false;
+    }
+
+    let proof_bytes = unsafe { std::slice::from_raw_parts(proof_bytes, proof_len as usize) };
+
+    let elf_bytes = unsafe { std::slice::from_raw_parts(elf_bytes, elf_len as usize) };
+
+    let program_io_bytes =
+        unsafe { std::slice::from_raw_parts(program_io_bytes, program_io_len as usize) };
+
+    let Ok(program_io) = JoltDevice::deserialize_from_bytes(program_io_bytes) else {
+        error!("Could not deserialize Jolt program io");
+        return false;
+    };
+
+    let Ok(proof) = JoltHyperKZGProof::deserialize_from_bytes(proof_bytes) else {
+        error!("Could not deserialize Jolt proof");
+        return false;
+    };
+
+    let (bytecode, memory_init) = tracer::decode(elf_bytes);
+    let preprocessing = RV32IJoltVM::verifier_preprocess(
+        bytecode,
+        program_io.memory_layout.clone(),
+        memory_init,
+        MAX_BYTECODE_SIZE,
+        MAX_MEMORY_SIZE,
+        MAX_TRACE_LENGTH,
+    );
+
+    RV32IJoltVM::verify(
+        preprocessing,
+        proof.proof,
+        proof.commitments,
+        program_io,
+        None,
+    )
+    .is_ok()
+}
+
+#[no_mangle]
+pub extern "C" fn verify_jolt_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    elf_bytes: *const u8,
+    elf_len: u32,
+    program_io_bytes: *const u8,
+    program_io_len: u32,
+) -> i32 {
+    let result = std::panic::catch_unwind(|| {
+        inner_verify_jolt_proof_ffi(
+            proof_bytes,
+            proof_len,
+            elf_bytes,
+            elf_len,
+            program_io_bytes,
+            program_io_len,
+        )
+    });
+
+    match result {
+        Ok(v) => v as i32,
+        Err(_) => -1,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn verify_jolt_fails_with_malformed_proof() {
+        let proof = [1u8, 2, 3, 4];
+        let elf = [5u8, 6, 7, 8];
+        let program_io = [9u8, 10, 11, 12];
+
+        let result = verify_jolt_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            elf.as_ptr(),
+            elf.len() as u32,
+            program_io.as_ptr(),
+            program_io.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+}
@@ -0,0 +1,48 @@
+package kimchi
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libkimchi_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -lkimchi_verifier_ffi
+
+#include "lib/kimchi.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// VerifyKimchiProof verifies a Kimchi proof over the Vesta curve, as the ones wrapping Mina Pickles proofs.
+// The public input must be encoded as 32 bytes little endian field elements, see types.NormalizeMinaPublicInput,
+// and the verification key is the msgpack serialized verifier index.
+func VerifyKimchiProof(proofBuffer []byte, pubInputBuffer []byte, verificationKeyBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(pubInputBuffer) == 0 || len(verificationKeyBuffer) == 0 {
+		return isVerified, err
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying Kimchi proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	pubInputPtr := (*C.uchar)(unsafe.Pointer(&pubInputBuffer[0]))
+	verificationKeyPtr := (*C.uchar)(unsafe.Pointer(&verificationKeyBuffer[0]))
+
+	r := (C.int32_t)(C.verify_kimchi_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), pubInputPtr, (C.uint32_t)(len(pubInputBuffer)), verificationKeyPtr, (C.uint32_t)(len(verificationKeyBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying Kimchi proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
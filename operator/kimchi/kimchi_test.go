@@ -0,0 +1,51 @@
+package kimchi_test
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/kimchi"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/kimchi/kimchi_fibonacci.proof"
+
+const PubInputFilePath = "../../scripts/test_files/kimchi/kimchi_fibonacci.pub"
+
+const VerificationKeyFilePath = "../../scripts/test_files/kimchi/kimchi_fibonacci.vk"
+
+// readTestFiles reads the proof, public input and verifier index generated with
+// make generate_kimchi_fibonacci_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_kimchi_fibonacci_proof", ProofFilePath, PubInputFilePath, VerificationKeyFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return kimchi.VerifyKimchiProof(inputs[0], inputs[1], inputs[2])
+}
+
+func TestKimchiProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestKimchiProofWithWrongPubInputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the last element is the Fibonacci result, its first byte the least significant one
+	inputs[1][len(inputs[1])-32] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with another public input")
+}
+
+func TestKimchiProofWithNonCanonicalPubInputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the most significant byte of the last element, no element is that big
+	inputs[1][len(inputs[1])-1] = 0xff
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with a non canonical public input")
+}
+
+func TestKimchiProofWithTamperedVerificationKeyDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[2][len(inputs[2])/2] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with a tampered verifier index")
+}
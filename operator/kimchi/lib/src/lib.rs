@@ -0,0 +1,156 @@
+use std::sync::Arc;
+
+use ark_ff::PrimeField;
+use groupmap::GroupMap;
+use kimchi::linearization::expr_linearization;
+use kimchi::proof::ProverProof;
+use kimchi::verifier::verify;
+use kimchi::verifier_index::VerifierIndex;
+use log::error;
+use mina_curves::pasta::{Fp, Vesta, VestaParameters};
+use mina_poseidon::constants::PlonkSpongeConstantsKimchi;
+use mina_poseidon::sponge::{DefaultFqSponge, DefaultFrSponge};
+use poly_commitment::commitment::CommitmentCurve;
+use poly_commitment::evaluation_proof::OpeningProof;
+use poly_commitment::srs::SRS;
+
+type BaseSponge = DefaultFqSponge<VestaParameters, PlonkSpongeConstantsKimchi>;
+type ScalarSponge = DefaultFrSponge<Fp, PlonkSpongeConstantsKimchi>;
+
+const FIELD_ELEMENT_SIZE: usize = 32;
+
+fn read_public_input(bytes: &[u8]) -> Option<Vec<Fp>> {
+    if bytes.len() % FIELD_ELEMENT_SIZE != 0 {
+        return None;
+    }
+    bytes
+        .chunks_exact(FIELD_ELEMENT_SIZE)
+        .map(|chunk| {
+            let element = Fp::from_le_bytes_mod_order(chunk);
+            // Only canonical encodings are accepted
+            (element.into_bigint().to_bytes_le() == chunk).then_some(element)
+        })
+        .collect()
+}
+
+/// The verifier index doesn't serialize its SRS nor its linearization, so they are rebuilt here.
+fn read_verifier_index(bytes: &[u8]) -> Option<VerifierIndex<Vesta, OpeningProof<Vesta>>> {
+    let mut verifier_index: VerifierIndex<Vesta, OpeningProof<Vesta>> =
+        rmp_serde::from_slice(bytes).ok()?;
+
+    let mut srs = SRS::<Vesta>::create(verifier_index.max_poly_size);
+    srs.add_lagrange_basis(verifier_index.domain);
+    verifier_index.srs = Arc::new(srs);
+
+    let (linearization, powers_of_alpha) =
+        expr_linearization(Some(&verifier_index.feature_flags), true);
+    verifier_index.linearization = linearization;
+    verifier_index.powers_of_alpha = powers_of_alpha;
+
+    Some(verifier_index)
+}
+
+fn inner_verify_kimchi_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+    vk_bytes: *const u8,
+    vk_len: u32,
+) -> bool {
+    if proof_bytes.is_null() || pub_input_bytes.is_null() || vk_bytes.is_null() {
+        error!("Input buffer null");
+        return false;
+    }
+
+    if proof_len == 0 || pub_input_len == 0 || vk_len == 0 {
+        error!("Input buffer length zero size");
+        return false;
+    }
+
+    let proof_bytes = unsafe { std::slice::from_raw_parts(proof_bytes, proof_len as usize) };
+
+    let pub_input_bytes =
+        unsafe { std::slice::from_raw_parts(pub_input_bytes, pub_input_len as usize) };
+
+    let vk_bytes = unsafe { std::slice::from_raw_parts(vk_bytes, vk_len as usize) };
+
+    let Some(public_input) = read_public_input(pub_input_bytes) else {
+        error!("Could not decode Kimchi public input");
+        return false;
+    };
+
+    let Some(verifier_index) = read_verifier_index(vk_bytes) else {
+        error!("Could not deserialize Kimchi verifier index");
+        return false;
+    };
+
+    let Ok(proof) = rmp_serde::from_slice::<ProverProof<Vesta, OpeningProof<Vesta>>>(proof_bytes)
+    else {
+        error!("Could not deserialize Kimchi proof");
+        return false;
+    };
+
+    let group_map = <Vesta as CommitmentCurve>::Map::setup();
+    verify::<Vesta, BaseSponge, ScalarSponge, OpeningProof<Vesta>>(
+        &group_map,
+        &verifier_index,
+        &proof,
+        &public_input,
+    )
+    .is_ok()
+}
+
+#[no_mangle]
+pub extern "C" fn verify_kimchi_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+    vk_bytes: *const u8,
+    vk_len: u32,
+) -> i32 {
+    let result = std::panic::catch_unwind(|| {
+        inner_verify_kimchi_proof_ffi(
+            proof_bytes,
+            proof_len,
+            pub_input_bytes,
+            pub_input_len,
+            vk_bytes,
+            vk_len,
+        )
+    });
+
+    match result {
+        Ok(v) => v as i32,
+        Err(_) => -1,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn verify_kimchi_fails_with_malformed_proof() {
+        let proof = [1u8, 2, 3, 4];
+        let pub_input = [0u8; FIELD_ELEMENT_SIZE];
+        let vk = [5u8, 6, 7, 8];
+
+        let result = verify_kimchi_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            pub_input.as_ptr(),
+            pub_input.len() as u32,
+            vk.as_ptr(),
+            vk.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+
+    #[test]
+    fn read_public_input_rejects_non_canonical_elements() {
+        assert!(read_public_input(&[0u8; FIELD_ELEMENT_SIZE]).is_some());
+        assert!(read_public_input(&[0xFFu8; FIELD_ELEMENT_SIZE]).is_none());
+    }
+}
@@ -0,0 +1,6 @@
+#include <stdbool.h>
+#include <stdint.h>
+
+int32_t verify_miden_proof_ffi(unsigned char *proof_buffer, uint32_t proof_len,
+                               unsigned char *program_hash_buffer, uint32_t program_hash_len,
+                               unsigned char *pub_input_buffer, uint32_t pub_input_len);
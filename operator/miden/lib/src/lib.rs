@@ -0,0 +1,150 @@
+use log::error;
+use miden_core::crypto::hash::RpoDigest;
+use miden_core::Felt;
+use miden_verifier::{verify, ExecutionProof, Kernel, ProgramInfo, StackInputs, StackOutputs};
+
+const FELT_SIZE: usize = 8;
+const PROGRAM_HASH_SIZE: usize = 4 * FELT_SIZE;
+
+fn felts_from_bytes(bytes: &[u8]) -> Option<Vec<Felt>> {
+    bytes
+        .chunks_exact(FELT_SIZE)
+        .map(|chunk| {
+            let value = u64::from_le_bytes(chunk.try_into().ok()?);
+            // Non canonical encodings are rejected, so a public input has a single encoding
+            (value < Felt::MODULUS).then(|| Felt::new(value))
+        })
+        .collect()
+}
+
+/// Decodes the program hash and the stack inputs and outputs, following the encoding of the Go bindings.
+fn decode_public_input(
+    program_hash: &[u8],
+    pub_input: &[u8],
+) -> Option<(ProgramInfo, StackInputs, StackOutputs)> {
+    if program_hash.len() != PROGRAM_HASH_SIZE {
+        return None;
+    }
+    let program_hash: [Felt; 4] = felts_from_bytes(program_hash)?.try_into().ok()?;
+    let program_info = ProgramInfo::new(RpoDigest::new(program_hash), Kernel::default());
+
+    if pub_input.len() < 4 || (pub_input.len() - 4) % FELT_SIZE != 0 {
+        return None;
+    }
+    let num_inputs = u32::from_le_bytes(pub_input[..4].try_into().ok()?) as usize;
+    let felts = felts_from_bytes(&pub_input[4..])?;
+    if num_inputs > felts.len() {
+        return None;
+    }
+    let (inputs, outputs) = felts.split_at(num_inputs);
+
+    let stack_inputs = StackInputs::new(inputs.to_vec()).ok()?;
+    let stack_outputs = StackOutputs::new(outputs.to_vec()).ok()?;
+
+    Some((program_info, stack_inputs, stack_outputs))
+}
+
+fn inner_verify_miden_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    program_hash_bytes: *const u8,
+    program_hash_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+) -> bool {
+    if proof_bytes.is_null() || program_hash_bytes.is_null() || pub_input_bytes.is_null() {
+        error!("Input buffer null");
+        return false;
+    }
+
+    if proof_len == 0 || program_hash_len == 0 || pub_input_len == 0 {
+        error!("Input buffer length zero size");
+        return false;
+    }
+
+    let proof_bytes = unsafe { std::slice::from_raw_parts(proof_bytes, proof_len as usize) };
+
+    let program_hash_bytes =
+        unsafe { std::slice::from_raw_parts(program_hash_bytes, program_hash_len as usize) };
+
+    let pub_input_bytes =
+        unsafe { std::slice::from_raw_parts(pub_input_bytes, pub_input_len as usize) };
+
+    let Some((program_info, stack_inputs, stack_outputs)) =
+        decode_public_input(program_hash_bytes, pub_input_bytes)
+    else {
+        error!("Could not decode Miden program hash and public input");
+        return false;
+    };
+
+    if let Ok(proof) = ExecutionProof::from_bytes(proof_bytes) {
+        return verify(program_info, stack_inputs, stack_outputs, proof).is_ok();
+    }
+
+    false
+}
+
+#[no_mangle]
+pub extern "C" fn verify_miden_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    program_hash_bytes: *const u8,
+    program_hash_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+) -> i32 {
+    let result = std::panic::catch_unwind(|| {
+        inner_verify_miden_proof_ffi(
+            proof_bytes,
+            proof_len,
+            program_hash_bytes,
+            program_hash_len,
+            pub_input_bytes,
+            pub_input_len,
+        )
+    });
+
+    match result {
+        Ok(v) => v as i32,
+        Err(_) => -1,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn verify_miden_fails_with_malformed_proof() {
+        let proof = [1u8, 2, 3, 4];
+        let program_hash = [0u8; PROGRAM_HASH_SIZE];
+        let pub_input = [0u8; 4];
+
+        let result = verify_miden_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            program_hash.as_ptr(),
+            program_hash.len() as u32,
+            pub_input.as_ptr(),
+            pub_input.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+
+    #[test]
+    fn decode_public_input_splits_stack_inputs_and_outputs() {
+        let program_hash = [0u8; PROGRAM_HASH_SIZE];
+        let mut pub_input = vec![1u8, 0, 0, 0];
+        pub_input.extend_from_slice(&7u64.to_le_bytes());
+        pub_input.extend_from_slice(&8u64.to_le_bytes());
+
+        assert!(decode_public_input(&program_hash, &pub_input).is_some());
+    }
+
+    #[test]
+    fn decode_public_input_rejects_non_canonical_felts() {
+        let program_hash = [0xFFu8; PROGRAM_HASH_SIZE];
+
+        assert!(decode_public_input(&program_hash, &[0u8; 4]).is_none());
+    }
+}
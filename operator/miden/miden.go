@@ -0,0 +1,100 @@
+package miden
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libmiden_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -lmiden_verifier_ffi
+
+#include "lib/miden.h"
+*/
+import "C"
+import (
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+)
+
+const (
+	// FeltSize is the size of a little endian encoded Miden field element.
+	FeltSize = 8
+	// ProgramHashSize is the size of a program hash, an RPO digest of 4 field elements.
+	ProgramHashSize = 4 * FeltSize
+	// MaxStackSize is the max number of stack inputs or outputs, the depth of the Miden operand stack.
+	MaxStackSize = 16
+	// fieldModulus is the modulus of the Miden field, 2^64 - 2^32 + 1.
+	fieldModulus = 0xFFFFFFFF00000001
+)
+
+// ValidateMidenInput checks that the program hash and the public input are well formed.
+// The public input is the number of stack inputs as a 4 byte little endian integer, followed by the
+// stack inputs and then the stack outputs, each a little endian encoded field element.
+func ValidateMidenInput(programHashBuffer []byte, pubInputBuffer []byte) error {
+	if len(programHashBuffer) != ProgramHashSize {
+		return fmt.Errorf("program hash size is %d, expected %d", len(programHashBuffer), ProgramHashSize)
+	}
+	if err := validateFelts(programHashBuffer); err != nil {
+		return fmt.Errorf("invalid program hash: %v", err)
+	}
+
+	if len(pubInputBuffer) < 4 || (len(pubInputBuffer)-4)%FeltSize != 0 {
+		return fmt.Errorf("public input size %d is not valid", len(pubInputBuffer))
+	}
+	numInputs := int(binary.LittleEndian.Uint32(pubInputBuffer[:4]))
+	numFelts := (len(pubInputBuffer) - 4) / FeltSize
+	if numInputs > MaxStackSize || numInputs > numFelts {
+		return fmt.Errorf("invalid number of stack inputs %d", numInputs)
+	}
+	if numFelts-numInputs > MaxStackSize {
+		return fmt.Errorf("invalid number of stack outputs %d", numFelts-numInputs)
+	}
+	if err := validateFelts(pubInputBuffer[4:]); err != nil {
+		return fmt.Errorf("invalid public input: %v", err)
+	}
+
+	return nil
+}
+
+func validateFelts(buffer []byte) error {
+	for i := 0; i < len(buffer); i += FeltSize {
+		if binary.LittleEndian.Uint64(buffer[i:i+FeltSize]) >= fieldModulus {
+			return fmt.Errorf("element %d is not a canonical field element", i/FeltSize)
+		}
+	}
+	return nil
+}
+
+// VerifyMidenProof verifies a Miden VM execution proof of the program with the given hash.
+// The public input holds the stack inputs and outputs of the execution, see ValidateMidenInput for its encoding.
+func VerifyMidenProof(proofBuffer []byte, programHashBuffer []byte, pubInputBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(programHashBuffer) == 0 || len(pubInputBuffer) == 0 {
+		return isVerified, err
+	}
+	if err = ValidateMidenInput(programHashBuffer, pubInputBuffer); err != nil {
+		return isVerified, err
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying Miden proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	programHashPtr := (*C.uchar)(unsafe.Pointer(&programHashBuffer[0]))
+	pubInputPtr := (*C.uchar)(unsafe.Pointer(&pubInputBuffer[0]))
+
+	r := (C.int32_t)(C.verify_miden_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), programHashPtr, (C.uint32_t)(len(programHashBuffer)), pubInputPtr, (C.uint32_t)(len(pubInputBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying Miden proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
@@ -0,0 +1,76 @@
+package miden_test
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/miden"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/miden/miden_fibonacci.proof"
+
+const ProgramHashFilePath = "../../scripts/test_files/miden/miden_fibonacci.hash"
+
+const PubInputFilePath = "../../scripts/test_files/miden/miden_fibonacci.pub"
+
+// readTestFiles reads the proof, program hash and public input generated with
+// make generate_miden_fibonacci_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_miden_fibonacci_proof", ProofFilePath, ProgramHashFilePath, PubInputFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return miden.VerifyMidenProof(inputs[0], inputs[1], inputs[2])
+}
+
+func TestMidenProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestMidenProofOfAnotherProgramDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1][0] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with another program hash")
+}
+
+func TestMidenProofWithWrongStackInputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the first stack input is right after the number of stack inputs
+	inputs[2][4] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with other stack inputs")
+}
+
+func TestMidenProofWithInvalidProgramHashDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1] = inputs[1][:miden.FeltSize]
+	verifiertest.ExpectRejected(t, verify, inputs, "proof with an invalid program hash should not verify")
+}
+
+func TestValidateMidenInputRejectsNonCanonicalFelts(t *testing.T) {
+	programHash := make([]byte, miden.ProgramHashSize)
+	for i := range programHash[:miden.FeltSize] {
+		programHash[i] = 0xFF
+	}
+	if err := miden.ValidateMidenInput(programHash, []byte{0, 0, 0, 0}); err == nil {
+		t.Errorf("program hash with a non canonical field element should be rejected")
+	}
+}
+
+func TestValidateMidenInputRejectsTooManyStackInputs(t *testing.T) {
+	pubInput := make([]byte, 4+(miden.MaxStackSize+1)*miden.FeltSize)
+	pubInput[0] = miden.MaxStackSize + 1
+	if err := miden.ValidateMidenInput(make([]byte, miden.ProgramHashSize), pubInput); err == nil {
+		t.Errorf("public input with too many stack inputs should be rejected")
+	}
+}
+
+func TestValidateMidenInputAcceptsStackInputsAndOutputs(t *testing.T) {
+	pubInput := make([]byte, 4+3*miden.FeltSize)
+	pubInput[0] = 1
+	if err := miden.ValidateMidenInput(make([]byte, miden.ProgramHashSize), pubInput); err != nil {
+		t.Errorf("valid public input should be accepted: %v", err)
+	}
+}
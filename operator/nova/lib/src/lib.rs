@@ -0,0 +1,127 @@
+use log::error;
+use nova_snark::provider::{hyperkzg, ipa_pc, Bn256EngineKZG, GrumpkinEngine};
+use nova_snark::spartan::snark::RelaxedR1CSSNARK;
+use nova_snark::traits::circuit::TrivialCircuit;
+use nova_snark::traits::Engine;
+use nova_snark::{CompressedSNARK, VerifierKey};
+use serde::Deserialize;
+
+// Proofs are expected to use the BN254/Grumpkin cycle, with HyperKZG on the primary curve
+// so the final proof can also be verified on Ethereum, and IPA on the secondary one.
+// The step circuits are only type markers for the verifier, so trivial circuits are used.
+type E1 = Bn256EngineKZG;
+type E2 = GrumpkinEngine;
+type S1 = RelaxedR1CSSNARK<E1, hyperkzg::EvaluationEngine<E1>>;
+type S2 = RelaxedR1CSSNARK<E2, ipa_pc::EvaluationEngine<E2>>;
+type C1 = TrivialCircuit<<E1 as Engine>::Scalar>;
+type C2 = TrivialCircuit<<E2 as Engine>::Scalar>;
+
+#[derive(Deserialize)]
+struct NovaPublicInput {
+    num_steps: usize,
+    z0_primary: Vec<<E1 as Engine>::Scalar>,
+    z0_secondary: Vec<<E2 as Engine>::Scalar>,
+    zn_primary: Vec<<E1 as Engine>::Scalar>,
+    zn_secondary: Vec<<E2 as Engine>::Scalar>,
+}
+
+fn inner_verify_nova_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+    vk_bytes: *const u8,
+    vk_len: u32,
+) -> bool {
+    if proof_bytes.is_null() || pub_input_bytes.is_null() || vk_bytes.is_null() {
+        error!("Input buffer null");
+        return false;
+    }
+
+    if proof_len == 0 || pub_input_len == 0 || vk_len == 0 {
+        error!("Input buffer length zero size");
+        return false;
+    }
+
+    let proof_bytes = unsafe { std::slice::from_raw_parts(proof_bytes, proof_len as usize) };
+
+    let pub_input_bytes =
+        unsafe { std::slice::from_raw_parts(pub_input_bytes, pub_input_len as usize) };
+
+    let vk_bytes = unsafe { std::slice::from_raw_parts(vk_bytes, vk_len as usize) };
+
+    let Ok(vk) = bincode::deserialize::<VerifierKey<E1, E2, C1, C2, S1, S2>>(vk_bytes) else {
+        error!("Could not deserialize Nova verifier key");
+        return false;
+    };
+
+    let Ok(pub_input) = bincode::deserialize::<NovaPublicInput>(pub_input_bytes) else {
+        error!("Could not deserialize Nova public input");
+        return false;
+    };
+
+    if let Ok(proof) = bincode::deserialize::<CompressedSNARK<E1, E2, C1, C2, S1, S2>>(proof_bytes)
+    {
+        return match proof.verify(
+            &vk,
+            pub_input.num_steps,
+            &pub_input.z0_primary,
+            &pub_input.z0_secondary,
+        ) {
+            Ok((zn_primary, zn_secondary)) => {
+                zn_primary == pub_input.zn_primary && zn_secondary == pub_input.zn_secondary
+            }
+            Err(_) => false,
+        };
+    }
+
+    false
+}
+
+#[no_mangle]
+pub extern "C" fn verify_nova_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+    vk_bytes: *const u8,
+    vk_len: u32,
+) -> i32 {
+    let result = std::panic::catch_unwind(|| {
+        inner_verify_nova_proof_ffi(
+            proof_bytes,
+            proof_len,
+            pub_input_bytes,
+            pub_input_len,
+            vk_bytes,
+            vk_len,
+        )
+    });
+
+    match result {
+        Ok(v) => v as i32,
+        Err(_) => -1,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn verify_nova_fails_with_malformed_proof() {
+        let proof = [1u8, 2, 3, 4];
+        let pub_input = [5u8, 6, 7, 8];
+        let vk = [9u8, 10, 11, 12];
+
+        let result = verify_nova_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            pub_input.as_ptr(),
+            pub_input.len() as u32,
+            vk.as_ptr(),
+            vk.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+}
@@ -0,0 +1,48 @@
+package nova
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libnova_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -lnova_verifier_ffi
+
+#include "lib/nova.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// VerifyNovaProof verifies a Nova compressed SNARK, the final proof of an IVC computation.
+// The public input holds the number of folded steps and the initial and final IVC state, and the verification key
+// is the compressed SNARK verifier key of the step circuits.
+func VerifyNovaProof(proofBuffer []byte, pubInputBuffer []byte, verificationKeyBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(pubInputBuffer) == 0 || len(verificationKeyBuffer) == 0 {
+		return isVerified, err
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying Nova proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	pubInputPtr := (*C.uchar)(unsafe.Pointer(&pubInputBuffer[0]))
+	verificationKeyPtr := (*C.uchar)(unsafe.Pointer(&verificationKeyBuffer[0]))
+
+	r := (C.int32_t)(C.verify_nova_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), pubInputPtr, (C.uint32_t)(len(pubInputBuffer)), verificationKeyPtr, (C.uint32_t)(len(verificationKeyBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying Nova proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
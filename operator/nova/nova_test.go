@@ -0,0 +1,51 @@
+package nova_test
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/nova"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/nova/nova_fibonacci.proof"
+
+const PubInputFilePath = "../../scripts/test_files/nova/nova_fibonacci.pub"
+
+const VerificationKeyFilePath = "../../scripts/test_files/nova/nova_fibonacci.vk"
+
+// readTestFiles reads the proof, public input and verification key generated with
+// make generate_nova_fibonacci_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_nova_fibonacci_proof", ProofFilePath, PubInputFilePath, VerificationKeyFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return nova.VerifyNovaProof(inputs[0], inputs[1], inputs[2])
+}
+
+func TestNovaProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestNovaProofWithWrongNumStepsDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the public input starts with the number of steps, as a little endian u64
+	inputs[1][0]++
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with another number of steps")
+}
+
+func TestNovaProofWithWrongFinalStateDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the public input ends with the final state of the secondary circuit
+	inputs[1][len(inputs[1])-32] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with another final state")
+}
+
+func TestNovaProofWithTamperedVerificationKeyDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[2][len(inputs[2])/2] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with a tampered verification key")
+}
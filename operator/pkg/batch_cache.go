@@ -0,0 +1,294 @@
+package operator
+
+import (
+	"container/list"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultBatchCacheSize = 10 * 1024 * 1024 * 1024 // 10 GiB
+
+const (
+	batchCacheFileExtension = ".batch"
+	batchCacheTmpExtension  = ".tmp"
+)
+
+type batchCacheEntry struct {
+	merkleRoot [32]byte
+	size       int64
+}
+
+// BatchCache stores downloaded batches on disk, keyed by their merkle root, so batches are not downloaded
+// again when the operator restarts or processes the same batch after a reorg. Batches are only added once
+// their merkle root was checked, and the least recently used ones are evicted when the cache exceeds its size.
+//
+// A nil *BatchCache is a disabled cache.
+type BatchCache struct {
+	mu      sync.Mutex
+	dir     string
+	maxSize int64
+	size    int64
+	entries map[[32]byte]*list.Element
+	order   *list.List
+}
+
+// NewBatchCache creates a cache in dir holding up to maxSize bytes of batches. If maxSize is not
+// positive, DefaultBatchCacheSize is used. Batches already in dir are loaded, the least recently
+// used being the ones with the oldest modification time.
+func NewBatchCache(dir string, maxSize int64) (*BatchCache, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultBatchCacheSize
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create batch cache directory: %v", err)
+	}
+
+	cache := &BatchCache{
+		dir:     dir,
+		maxSize: maxSize,
+		entries: make(map[[32]byte]*list.Element),
+		order:   list.New(),
+	}
+	if err := cache.load(); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+func (c *BatchCache) load() error {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read batch cache directory: %v", err)
+	}
+
+	type cachedFile struct {
+		entry   batchCacheEntry
+		modTime time.Time
+	}
+	var cachedFiles []cachedFile
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasSuffix(name, batchCacheTmpExtension) {
+			// batches that were being written when the operator stopped
+			_ = os.Remove(filepath.Join(c.dir, name))
+			continue
+		}
+		merkleRoot, ok := batchCacheMerkleRoot(name)
+		if !ok {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		cachedFiles = append(cachedFiles, cachedFile{batchCacheEntry{merkleRoot, info.Size()}, info.ModTime()})
+	}
+
+	sort.Slice(cachedFiles, func(i, j int) bool {
+		return cachedFiles[i].modTime.Before(cachedFiles[j].modTime)
+	})
+	for _, cachedFile := range cachedFiles {
+		c.add(cachedFile.entry)
+	}
+	return nil
+}
+
+func batchCacheMerkleRoot(fileName string) ([32]byte, bool) {
+	var merkleRoot [32]byte
+	encodedRoot, found := strings.CutSuffix(fileName, batchCacheFileExtension)
+	if !found {
+		return merkleRoot, false
+	}
+	decodedRoot, err := hex.DecodeString(encodedRoot)
+	if err != nil || len(decodedRoot) != len(merkleRoot) {
+		return merkleRoot, false
+	}
+	copy(merkleRoot[:], decodedRoot)
+	return merkleRoot, true
+}
+
+func (c *BatchCache) path(merkleRoot [32]byte) string {
+	return filepath.Join(c.dir, hex.EncodeToString(merkleRoot[:])+batchCacheFileExtension)
+}
+
+// Open returns the cached batch with the given merkle root, if any. The caller must close the file.
+func (c *BatchCache) Open(merkleRoot [32]byte) (*os.File, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[merkleRoot]
+	if !ok {
+		return nil, false
+	}
+	file, err := os.Open(c.path(merkleRoot))
+	if err != nil {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	// the modification time keeps the LRU order across restarts
+	now := time.Now()
+	_ = os.Chtimes(c.path(merkleRoot), now, now)
+	return file, true
+}
+
+// Get returns the content of the cached batch with the given merkle root, if any.
+func (c *BatchCache) Get(merkleRoot [32]byte) ([]byte, bool) {
+	file, ok := c.Open(merkleRoot)
+	if !ok {
+		return nil, false
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, false
+	}
+	batch := make([]byte, stat.Size())
+	if _, err = file.ReadAt(batch, 0); err != nil {
+		return nil, false
+	}
+	return batch, true
+}
+
+// Put adds a batch whose merkle root was checked to the cache.
+func (c *BatchCache) Put(merkleRoot [32]byte, batch []byte) error {
+	writer, err := c.NewWriter(merkleRoot)
+	if err != nil || writer == nil {
+		return err
+	}
+	if _, err = writer.Write(batch); err != nil {
+		writer.Abort()
+		return err
+	}
+	return writer.Commit()
+}
+
+// Remove deletes the batch with the given merkle root from the cache, used when a cached batch is corrupted.
+func (c *BatchCache) Remove(merkleRoot [32]byte) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[merkleRoot]; ok {
+		c.remove(element)
+	}
+}
+
+// Size returns the total size of the cached batches.
+func (c *BatchCache) Size() int64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.size
+}
+
+func (c *BatchCache) add(entry batchCacheEntry) {
+	if element, ok := c.entries[entry.merkleRoot]; ok {
+		c.size -= element.Value.(batchCacheEntry).size
+		c.order.Remove(element)
+	}
+	c.entries[entry.merkleRoot] = c.order.PushFront(entry)
+	c.size += entry.size
+
+	for c.size > c.maxSize && c.order.Len() > 0 {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *BatchCache) remove(element *list.Element) {
+	entry := element.Value.(batchCacheEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.merkleRoot)
+	c.size -= entry.size
+	_ = os.Remove(c.path(entry.merkleRoot))
+}
+
+// BatchCacheWriter writes a batch to the cache while it's downloaded. The batch is only added
+// to the cache on Commit, which must be called once its merkle root was checked.
+type BatchCacheWriter struct {
+	cache      *BatchCache
+	merkleRoot [32]byte
+	file       *os.File
+	size       int64
+	err        error
+	committed  bool
+}
+
+// NewWriter starts writing the batch with the given merkle root to the cache.
+// It returns a nil writer, which discards the batch, if the cache is disabled.
+func (c *BatchCache) NewWriter(merkleRoot [32]byte) (*BatchCacheWriter, error) {
+	if c == nil {
+		return nil, nil
+	}
+	file, err := os.CreateTemp(c.dir, hex.EncodeToString(merkleRoot[:])+"-*"+batchCacheTmpExtension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch cache file: %v", err)
+	}
+	return &BatchCacheWriter{cache: c, merkleRoot: merkleRoot, file: file}, nil
+}
+
+// Write never fails, so a full disk can't interrupt the download the batch is read from.
+// Write errors are returned by Commit instead.
+func (w *BatchCacheWriter) Write(p []byte) (int, error) {
+	if w == nil || w.err != nil {
+		return len(p), nil
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	w.err = err
+	return len(p), nil
+}
+
+// Commit adds the written batch to the cache. Batches larger than the cache are discarded.
+func (w *BatchCacheWriter) Commit() error {
+	if w == nil {
+		return nil
+	}
+	if w.err != nil {
+		w.Abort()
+		return fmt.Errorf("failed to write batch cache file: %v", w.err)
+	}
+	if err := w.file.Close(); err != nil {
+		_ = os.Remove(w.file.Name())
+		return fmt.Errorf("failed to write batch cache file: %v", err)
+	}
+	if w.size > w.cache.maxSize {
+		_ = os.Remove(w.file.Name())
+		return nil
+	}
+
+	w.cache.mu.Lock()
+	defer w.cache.mu.Unlock()
+	if err := os.Rename(w.file.Name(), w.cache.path(w.merkleRoot)); err != nil {
+		_ = os.Remove(w.file.Name())
+		return fmt.Errorf("failed to write batch cache file: %v", err)
+	}
+	w.cache.add(batchCacheEntry{w.merkleRoot, w.size})
+	w.committed = true
+	return nil
+}
+
+// Abort discards the written batch, unless it was already committed.
+func (w *BatchCacheWriter) Abort() {
+	if w == nil || w.committed {
+		return
+	}
+	_ = w.file.Close()
+	_ = os.Remove(w.file.Name())
+}
@@ -0,0 +1,105 @@
+package operator
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestBatchCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache, err := NewBatchCache(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("Unexpected error creating cache: %v", err)
+	}
+
+	first, second, third := [32]byte{1}, [32]byte{2}, [32]byte{3}
+	for _, root := range [][32]byte{first, second} {
+		if err = cache.Put(root, []byte("batch")); err != nil {
+			t.Fatalf("Unexpected error adding batch: %v", err)
+		}
+	}
+	// first becomes the most recently used, so second is evicted
+	if batch, ok := cache.Get(first); !ok || string(batch) != "batch" {
+		t.Errorf("Expected first batch to be cached")
+	}
+	if err = cache.Put(third, []byte("batch")); err != nil {
+		t.Fatalf("Unexpected error adding batch: %v", err)
+	}
+
+	if _, ok := cache.Get(second); ok {
+		t.Errorf("Expected second batch to be evicted")
+	}
+	if _, ok := cache.Get(third); !ok {
+		t.Errorf("Expected third batch to be cached")
+	}
+	if cache.Size() != 10 {
+		t.Errorf("Expected cache size 10, got %d", cache.Size())
+	}
+}
+
+func TestBatchCacheIsLoadedFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewBatchCache(dir, 20)
+	if err != nil {
+		t.Fatalf("Unexpected error creating cache: %v", err)
+	}
+	first, second := [32]byte{1}, [32]byte{2}
+	_ = cache.Put(first, []byte("first"))
+	_ = cache.Put(second, []byte("second"))
+	// a batch whose download was interrupted
+	if _, err = cache.NewWriter([32]byte{3}); err != nil {
+		t.Fatalf("Unexpected error creating writer: %v", err)
+	}
+	// first is the least recently used
+	past := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(cache.path(first), past, past)
+
+	reloaded, err := NewBatchCache(dir, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error loading cache: %v", err)
+	}
+	if _, ok := reloaded.Get(first); ok {
+		t.Errorf("Expected first batch to be evicted on load, the cache is now smaller")
+	}
+	if batch, ok := reloaded.Get(second); !ok || string(batch) != "second" {
+		t.Errorf("Expected second batch to be loaded")
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("Expected only the second batch to be left in the cache directory, found %d files", len(files))
+	}
+}
+
+func TestBatchCacheWriterOnlyAddsCommittedBatches(t *testing.T) {
+	cache, err := NewBatchCache(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("Unexpected error creating cache: %v", err)
+	}
+
+	aborted, _ := cache.NewWriter([32]byte{1})
+	_, _ = aborted.Write([]byte("batch"))
+	aborted.Abort()
+	if _, ok := cache.Get([32]byte{1}); ok {
+		t.Errorf("Expected aborted batch not to be cached")
+	}
+
+	committed, _ := cache.NewWriter([32]byte{2})
+	_, _ = committed.Write([]byte("bat"))
+	_, _ = committed.Write([]byte("ch"))
+	if err = committed.Commit(); err != nil {
+		t.Fatalf("Unexpected error committing batch: %v", err)
+	}
+	committed.Abort()
+	if batch, ok := cache.Get([32]byte{2}); !ok || !bytes.Equal(batch, []byte("batch")) {
+		t.Errorf("Expected committed batch to be cached")
+	}
+
+	var disabled *BatchCache
+	if _, ok := disabled.Get([32]byte{2}); ok {
+		t.Errorf("Expected disabled cache to be empty")
+	}
+	if err = disabled.Put([32]byte{2}, []byte("batch")); err != nil {
+		t.Errorf("Expected disabled cache to ignore batches, got %v", err)
+	}
+}
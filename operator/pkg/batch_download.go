@@ -0,0 +1,156 @@
+package operator
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BatchDownloadMaxResumes is the max number of times a batch download is resumed after the connection drops.
+const BatchDownloadMaxResumes = 5
+
+const (
+	s3ChecksumModeHeader   = "x-amz-checksum-mode"
+	s3ChecksumSha256Header = "x-amz-checksum-sha256"
+)
+
+var errBatchChecksumMismatch = errors.New("batch checksum mismatch")
+
+// batchDownload reads a batch from the data service. If the connection drops or the body ends
+// before the expected size, the download is resumed with a ranged request from the last byte read,
+// instead of downloading the whole batch again.
+//
+// Once the whole batch is read, its content is checked against the checksum returned by S3:
+// the SHA-256 checksum when the object has one, or the ETag, which is the MD5 of single part uploads.
+type batchDownload struct {
+	operator   *Operator
+	ctx        context.Context
+	url        string
+	maxRetries int
+	retryDelay time.Duration
+
+	body    io.ReadCloser
+	offset  int64
+	size    int64
+	etag    string
+	resumes int
+
+	sha256         hash.Hash
+	md5            hash.Hash
+	checksumSha256 string
+}
+
+func (o *Operator) newBatchDownload(ctx context.Context, batchURL string, maxRetries int, retryDelay time.Duration) (*batchDownload, error) {
+	resp, err := o.fetchBatch(ctx, batchURL, 0, "", maxRetries, retryDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchDownload{
+		operator:       o,
+		ctx:            ctx,
+		url:            batchURL,
+		maxRetries:     maxRetries,
+		retryDelay:     retryDelay,
+		body:           resp.Body,
+		size:           resp.ContentLength,
+		etag:           resp.Header.Get("ETag"),
+		sha256:         sha256.New(),
+		md5:            md5.New(),
+		checksumSha256: resp.Header.Get(s3ChecksumSha256Header),
+	}, nil
+}
+
+func (d *batchDownload) Read(p []byte) (int, error) {
+	for {
+		if d.body == nil {
+			if err := d.resume(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := d.body.Read(p)
+		d.offset += int64(n)
+		d.sha256.Write(p[:n])
+		d.md5.Write(p[:n])
+
+		if err == io.EOF && (d.size < 0 || d.offset >= d.size) {
+			if checksumErr := d.verifyChecksum(); checksumErr != nil {
+				return n, checksumErr
+			}
+			return n, io.EOF
+		}
+		if err == nil {
+			return n, nil
+		}
+
+		// The connection dropped or the body is truncated, the rest of the batch is read on the next call
+		d.operator.Logger.Warnf("Batch download interrupted at byte %d of %d: %v", d.offset, d.size, err)
+		closeBody(d.body)
+		d.body = nil
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// resume requests the batch from the last byte read.
+func (d *batchDownload) resume() error {
+	if d.ctx.Err() != nil {
+		return d.ctx.Err()
+	}
+	if d.resumes >= BatchDownloadMaxResumes {
+		return fmt.Errorf("batch download interrupted more than %d times", BatchDownloadMaxResumes)
+	}
+	d.resumes++
+
+	resp, err := d.operator.fetchBatch(d.ctx, d.url, d.offset, d.etag, d.maxRetries, d.retryDelay)
+	if err != nil {
+		return err
+	}
+
+	// Servers not supporting ranges send the whole batch again, the bytes already read are skipped
+	if resp.StatusCode == http.StatusOK && d.offset > 0 {
+		if _, err = io.CopyN(io.Discard, resp.Body, d.offset); err != nil {
+			closeBody(resp.Body)
+			return fmt.Errorf("error skipping already downloaded batch bytes: %w", err)
+		}
+	}
+	d.body = resp.Body
+	return nil
+}
+
+func (d *batchDownload) verifyChecksum() error {
+	if d.checksumSha256 != "" {
+		checksum := base64.StdEncoding.EncodeToString(d.sha256.Sum(nil))
+		if checksum != d.checksumSha256 {
+			return fmt.Errorf("%w: SHA-256 is %s, expected %s", errBatchChecksumMismatch, checksum, d.checksumSha256)
+		}
+		return nil
+	}
+
+	// Multipart uploads ETags are not the MD5 of the object, they end with the number of parts
+	etag := strings.Trim(d.etag, "\"")
+	if len(etag) == md5.Size*2 && !strings.Contains(etag, "-") {
+		checksum := hex.EncodeToString(d.md5.Sum(nil))
+		if !strings.EqualFold(checksum, etag) {
+			return fmt.Errorf("%w: MD5 is %s, expected %s", errBatchChecksumMismatch, checksum, etag)
+		}
+	}
+	return nil
+}
+
+func (d *batchDownload) Close() {
+	if d.body != nil {
+		closeBody(d.body)
+	}
+}
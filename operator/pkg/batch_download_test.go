@@ -0,0 +1,150 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/klauspost/compress/zstd"
+)
+
+// truncatingWriter aborts the response after limit bytes of the body are written
+type truncatingWriter struct {
+	http.ResponseWriter
+	limit int
+}
+
+func (w *truncatingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		p = p[:w.limit]
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.limit -= n
+	if w.limit <= 0 {
+		w.ResponseWriter.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	return n, err
+}
+
+func newDownloadTestOperator(t *testing.T) *Operator {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	operator := &Operator{Logger: logger}
+	operator.Config.Operator.MaxBatchSize = 1 << 20
+	return operator
+}
+
+// newBatchServer serves batch, dropping the connection of the first truncatedRequests requests
+// after writing a fraction of the body
+func newBatchServer(batch []byte, etag string, checksumSha256 string, truncatedRequests int) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", etag)
+		if checksumSha256 != "" && r.Header.Get(s3ChecksumModeHeader) == "ENABLED" {
+			w.Header().Set(s3ChecksumSha256Header, checksumSha256)
+		}
+		if requests <= truncatedRequests {
+			w = &truncatingWriter{ResponseWriter: w, limit: len(batch) / 16}
+		}
+		http.ServeContent(w, r, "batch", time.Time{}, bytes.NewReader(batch))
+	}))
+	return server, &requests
+}
+
+func testBatch() []byte {
+	batch := make([]byte, 64*1024)
+	for i := range batch {
+		batch[i] = byte(i * 7)
+	}
+	return batch
+}
+
+func md5ETag(batch []byte) string {
+	sum := md5.Sum(batch)
+	return "\"" + hex.EncodeToString(sum[:]) + "\""
+}
+
+func TestDownloadBatchResumesInterruptedDownloads(t *testing.T) {
+	batch := testBatch()
+	server, requests := newBatchServer(batch, md5ETag(batch), "", 2)
+	defer server.Close()
+
+	downloaded, err := newDownloadTestOperator(t).downloadBatch(context.Background(), server.URL, nil, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Error downloading batch: %v", err)
+	}
+	if !bytes.Equal(downloaded, batch) {
+		t.Errorf("Downloaded batch doesn't match the served one")
+	}
+	if *requests != 3 {
+		t.Errorf("Expected the download to be resumed twice, got %d requests", *requests)
+	}
+}
+
+func TestDownloadBatchChecksSha256(t *testing.T) {
+	batch := testBatch()
+	sum := sha256.Sum256([]byte("another batch"))
+	server, _ := newBatchServer(batch, md5ETag(batch), base64.StdEncoding.EncodeToString(sum[:]), 0)
+	defer server.Close()
+
+	_, err := newDownloadTestOperator(t).downloadBatch(context.Background(), server.URL, nil, 3, time.Millisecond)
+	if !errors.Is(err, errBatchChecksumMismatch) {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+}
+
+func TestDownloadBatchChecksETag(t *testing.T) {
+	batch := testBatch()
+	server, _ := newBatchServer(batch, md5ETag([]byte("another batch")), "", 1)
+	defer server.Close()
+
+	_, err := newDownloadTestOperator(t).downloadBatch(context.Background(), server.URL, nil, 3, time.Millisecond)
+	if !errors.Is(err, errBatchChecksumMismatch) {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+}
+
+func TestDownloadBatchFailsAfterMaxResumes(t *testing.T) {
+	batch := testBatch()
+	server, _ := newBatchServer(batch, md5ETag(batch), "", BatchDownloadMaxResumes+1)
+	defer server.Close()
+
+	download, err := newDownloadTestOperator(t).newBatchDownload(context.Background(), server.URL, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Error starting batch download: %v", err)
+	}
+	defer download.Close()
+	if _, err = io.ReadAll(download); err == nil {
+		t.Errorf("Expected the download to fail after %d resumes", BatchDownloadMaxResumes)
+	}
+}
+
+func TestDownloadBatchDecompressesZstdBatches(t *testing.T) {
+	batch := testBatch()
+	zstdEncoder, _ := zstd.NewWriter(nil)
+	compressed := zstdEncoder.EncodeAll(batch, nil)
+	server, _ := newBatchServer(compressed, md5ETag(compressed), "", 0)
+	defer server.Close()
+
+	downloaded, err := newDownloadTestOperator(t).downloadBatch(context.Background(), server.URL, nil, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Error downloading batch: %v", err)
+	}
+	if !bytes.Equal(downloaded, batch) {
+		t.Errorf("Expected the downloaded batch to be decompressed")
+	}
+}
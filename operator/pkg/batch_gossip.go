@@ -0,0 +1,483 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	p2pcrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+const (
+	// BatchGossipTopic is the pubsub topic operators announce the batches they can serve on
+	BatchGossipTopic = "/aligned/batches/1.0.0"
+	// BatchGossipProtocol is the protocol operators request batches from each other with
+	BatchGossipProtocol = protocol.ID("/aligned/batch/1.0.0")
+
+	// Max number of recent batches whose providers are remembered
+	batchGossipMaxAnnouncedBatches = 256
+	// Max number of providers remembered for a batch
+	batchGossipMaxProviders = 8
+	// Max time to get a batch from peers, which are all requested at once
+	batchGossipFetchTimeout = 5 * time.Second
+	// Max time to serve a batch to a peer
+	batchGossipServeTimeout = 30 * time.Second
+	// Time the registration of an operator that announced a batch is remembered, so announcements
+	// don't query the chain each time
+	batchGossipRegistrationTTL = 10 * time.Minute
+
+	batchGossipFound    byte = 1
+	batchGossipNotFound byte = 0
+)
+
+// batchAnnouncementDomain separates the announcement signatures from any other signature of the operator BLS key
+const batchAnnouncementDomain = "aligned batch announcement"
+
+// batchAnnouncement is published by an operator once it verified and cached a batch.
+// It includes the operator addresses, since announcements can be relayed by other peers.
+// It's signed with the BLS key of the operator over the merkle root and the peer id of the
+// operator, so only registered operators can announce batches, and other peers can't replay
+// their announcements.
+type batchAnnouncement struct {
+	MerkleRoot [32]byte `json:"merkle_root"`
+	Addrs      []string `json:"addrs"`
+	PubkeyG1   []byte   `json:"pubkey_g1"`
+	PubkeyG2   []byte   `json:"pubkey_g2"`
+	Signature  []byte   `json:"signature"`
+}
+
+// batchAnnouncementHash is the message signed in the announcement of a batch by the given peer
+func batchAnnouncementHash(merkleRoot [32]byte, provider peer.ID) [32]byte {
+	return crypto.Keccak256Hash([]byte(batchAnnouncementDomain), merkleRoot[:], []byte(provider))
+}
+
+// OperatorRegistry tells if an operator is registered, by its id. It's implemented by the AVS reader.
+type OperatorRegistry interface {
+	IsOperatorIdRegistered(ctx context.Context, operatorId eigentypes.OperatorId) (bool, error)
+}
+
+type operatorRegistration struct {
+	registered bool
+	expiresAt  time.Time
+}
+
+// BatchGossip is an optional peer-to-peer layer where operators share the batches they downloaded.
+// Operators announce the batches they have in their batch cache, and other operators request them
+// before falling back to the batch data URL. Batches received from peers are only used if their
+// merkle root is the expected one, so peers don't need to be trusted.
+//
+// A nil *BatchGossip is a disabled gossip layer.
+type BatchGossip struct {
+	host     host.Host
+	topic    *pubsub.Topic
+	cache    *BatchCache
+	signer   BlsSigner
+	registry OperatorRegistry
+	logger   sdklogging.Logger
+
+	mu             sync.Mutex
+	providers      map[[32]byte][]peer.ID
+	announcedOrder [][32]byte
+	registrations  map[eigentypes.OperatorId]operatorRegistration
+}
+
+// NewBatchGossip starts a libp2p host listening on listenAddrs, connects to the bootstrap peers and joins
+// the batch gossip topic. Batches are served from cache, which must be enabled. If keyPath is not empty,
+// the host identity is read from it, or created and written to it, so the peer id is stable across restarts.
+// Announcements are signed with signer, and only the ones of operators registered in registry are accepted.
+func NewBatchGossip(ctx context.Context, listenAddrs []string, bootstrapPeers []string, keyPath string, cache *BatchCache, signer BlsSigner, registry OperatorRegistry, logger sdklogging.Logger) (*BatchGossip, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("batch gossip requires the batch cache to be enabled")
+	}
+	if signer == nil || registry == nil {
+		return nil, fmt.Errorf("batch gossip requires the operator BLS key and registry")
+	}
+	privKey, err := loadOrCreateP2PKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	p2pHost, err := libp2p.New(libp2p.ListenAddrStrings(listenAddrs...), libp2p.Identity(privKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create p2p host: %v", err)
+	}
+
+	gossip := &BatchGossip{
+		host:          p2pHost,
+		cache:         cache,
+		signer:        signer,
+		registry:      registry,
+		logger:        logger,
+		providers:     make(map[[32]byte][]peer.ID),
+		registrations: make(map[eigentypes.OperatorId]operatorRegistration),
+	}
+	p2pHost.SetStreamHandler(BatchGossipProtocol, gossip.handleBatchRequest)
+
+	for _, bootstrapPeer := range bootstrapPeers {
+		addrInfo, err := peer.AddrInfoFromString(bootstrapPeer)
+		if err != nil {
+			_ = p2pHost.Close()
+			return nil, fmt.Errorf("invalid p2p bootstrap peer %s: %v", bootstrapPeer, err)
+		}
+		if err = p2pHost.Connect(ctx, *addrInfo); err != nil {
+			logger.Warnf("Could not connect to p2p bootstrap peer %s: %v", bootstrapPeer, err)
+		}
+	}
+
+	gossipSub, err := pubsub.NewGossipSub(ctx, p2pHost)
+	if err != nil {
+		_ = p2pHost.Close()
+		return nil, fmt.Errorf("failed to create gossipsub: %v", err)
+	}
+	if err = gossipSub.RegisterTopicValidator(BatchGossipTopic, gossip.validateBatchAnnouncement); err != nil {
+		_ = p2pHost.Close()
+		return nil, err
+	}
+	gossip.topic, err = gossipSub.Join(BatchGossipTopic)
+	if err != nil {
+		_ = p2pHost.Close()
+		return nil, fmt.Errorf("failed to join batch gossip topic: %v", err)
+	}
+	subscription, err := gossip.topic.Subscribe()
+	if err != nil {
+		_ = p2pHost.Close()
+		return nil, fmt.Errorf("failed to subscribe to batch gossip topic: %v", err)
+	}
+	go gossip.handleAnnouncements(ctx, subscription)
+
+	logger.Infof("Batch gossip started, peer id: %s, addresses: %v", p2pHost.ID(), p2pHost.Addrs())
+	return gossip, nil
+}
+
+func loadOrCreateP2PKey(keyPath string) (p2pcrypto.PrivKey, error) {
+	if keyPath != "" {
+		encodedKey, err := os.ReadFile(keyPath)
+		if err == nil {
+			return p2pcrypto.UnmarshalPrivateKey(encodedKey)
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read p2p key: %v", err)
+		}
+	}
+
+	privKey, _, err := p2pcrypto.GenerateEd25519Key(nil)
+	if err != nil {
+		return nil, err
+	}
+	if keyPath != "" {
+		encodedKey, err := p2pcrypto.MarshalPrivateKey(privKey)
+		if err != nil {
+			return nil, err
+		}
+		if err = os.WriteFile(keyPath, encodedKey, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write p2p key: %v", err)
+		}
+	}
+	return privKey, nil
+}
+
+// validateBatchAnnouncement accepts announcements signed by a registered operator for the peer that
+// published them, so only the peers of registered operators can be providers of a batch.
+func (g *BatchGossip) validateBatchAnnouncement(ctx context.Context, _ peer.ID, message *pubsub.Message) bool {
+	var announcement batchAnnouncement
+	if err := json.Unmarshal(message.Data, &announcement); err != nil {
+		return false
+	}
+	pubkeyG1, err := utils.DecompressG1Point("announcement public key", announcement.PubkeyG1)
+	if err != nil {
+		return false
+	}
+	pubkeyG2, err := utils.DecompressG2Point("announcement public key", announcement.PubkeyG2)
+	if err != nil {
+		return false
+	}
+	signature, err := utils.DecompressG1Point("announcement signature", announcement.Signature)
+	if err != nil {
+		return false
+	}
+	// the operator id is the hash of the G1 public key, while signatures are checked with the G2 one
+	if equivalent, err := pubkeyG1.VerifyEquivalence(pubkeyG2); err != nil || !equivalent {
+		return false
+	}
+	hash := batchAnnouncementHash(announcement.MerkleRoot, message.GetFrom())
+	if valid, err := (&bls.Signature{G1Point: signature}).Verify(pubkeyG2, hash); err != nil || !valid {
+		return false
+	}
+	return g.isOperatorRegistered(ctx, eigentypes.OperatorIdFromG1Pubkey(pubkeyG1))
+}
+
+func (g *BatchGossip) isOperatorRegistered(ctx context.Context, operatorId eigentypes.OperatorId) bool {
+	g.mu.Lock()
+	registration, known := g.registrations[operatorId]
+	g.mu.Unlock()
+	if known && time.Now().Before(registration.expiresAt) {
+		return registration.registered
+	}
+
+	registered, err := g.registry.IsOperatorIdRegistered(ctx, operatorId)
+	if err != nil {
+		g.logger.Warnf("Could not check if the operator 0x%x that announced a batch is registered: %v", operatorId, err)
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for id, registration := range g.registrations {
+		if time.Now().After(registration.expiresAt) {
+			delete(g.registrations, id)
+		}
+	}
+	g.registrations[operatorId] = operatorRegistration{registered: registered, expiresAt: time.Now().Add(batchGossipRegistrationTTL)}
+	return registered
+}
+
+func (g *BatchGossip) handleAnnouncements(ctx context.Context, subscription *pubsub.Subscription) {
+	for {
+		message, err := subscription.Next(ctx)
+		if err != nil {
+			return
+		}
+		provider := message.GetFrom()
+		if provider == g.host.ID() {
+			continue
+		}
+
+		var announcement batchAnnouncement
+		if err = json.Unmarshal(message.Data, &announcement); err != nil {
+			continue
+		}
+		for _, encodedAddr := range announcement.Addrs {
+			if addr, err := multiaddr.NewMultiaddr(encodedAddr); err == nil {
+				g.host.Peerstore().AddAddr(provider, addr, time.Hour)
+			}
+		}
+		g.addProvider(announcement.MerkleRoot, provider)
+	}
+}
+
+func (g *BatchGossip) addProvider(merkleRoot [32]byte, provider peer.ID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	providers, known := g.providers[merkleRoot]
+	if !known {
+		g.announcedOrder = append(g.announcedOrder, merkleRoot)
+		if len(g.announcedOrder) > batchGossipMaxAnnouncedBatches {
+			delete(g.providers, g.announcedOrder[0])
+			g.announcedOrder = g.announcedOrder[1:]
+		}
+	}
+	for _, knownProvider := range providers {
+		if knownProvider == provider {
+			return
+		}
+	}
+	if len(providers) < batchGossipMaxProviders {
+		g.providers[merkleRoot] = append(providers, provider)
+	}
+}
+
+func (g *BatchGossip) providersOf(merkleRoot [32]byte) []peer.ID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return append([]peer.ID(nil), g.providers[merkleRoot]...)
+}
+
+// Announce tells the other operators this operator can serve the batch with the given merkle root.
+func (g *BatchGossip) Announce(ctx context.Context, merkleRoot [32]byte) {
+	if g == nil {
+		return
+	}
+	signature, err := g.signer.Sign(ctx, batchAnnouncementHash(merkleRoot, g.host.ID()))
+	if err != nil {
+		g.logger.Warnf("Could not sign announcement of batch 0x%x: %v", merkleRoot, err)
+		return
+	}
+	pubkeyG1 := utils.CompressG1Point(g.signer.PublicKeyG1())
+	pubkeyG2 := utils.CompressG2Point(g.signer.PublicKeyG2())
+	compressedSignature := utils.CompressG1Point(signature.G1Point)
+	announcement := batchAnnouncement{
+		MerkleRoot: merkleRoot,
+		PubkeyG1:   pubkeyG1[:],
+		PubkeyG2:   pubkeyG2[:],
+		Signature:  compressedSignature[:],
+	}
+	for _, addr := range g.host.Addrs() {
+		announcement.Addrs = append(announcement.Addrs, addr.String())
+	}
+	data, err := json.Marshal(announcement)
+	if err != nil {
+		return
+	}
+	if err = g.topic.Publish(ctx, data); err != nil {
+		g.logger.Warnf("Could not announce batch 0x%x: %v", merkleRoot, err)
+	}
+}
+
+// handleBatchRequest serves a batch from the cache. Requests are the batch merkle root, responses
+// a found or not found byte followed by the batch size and the batch, if found.
+func (g *BatchGossip) handleBatchRequest(stream network.Stream) {
+	defer stream.Close()
+	_ = stream.SetDeadline(time.Now().Add(batchGossipServeTimeout))
+
+	var merkleRoot [32]byte
+	if _, err := io.ReadFull(stream, merkleRoot[:]); err != nil {
+		_ = stream.Reset()
+		return
+	}
+
+	batch, ok := g.cache.Open(merkleRoot)
+	if !ok {
+		_, _ = stream.Write([]byte{batchGossipNotFound})
+		return
+	}
+	defer batch.Close()
+	stat, err := batch.Stat()
+	if err != nil {
+		_, _ = stream.Write([]byte{batchGossipNotFound})
+		return
+	}
+
+	header := make([]byte, 9)
+	header[0] = batchGossipFound
+	binary.BigEndian.PutUint64(header[1:], uint64(stat.Size()))
+	if _, err = stream.Write(header); err != nil {
+		_ = stream.Reset()
+		return
+	}
+	if _, err = io.Copy(stream, batch); err != nil {
+		_ = stream.Reset()
+	}
+}
+
+// Fetch requests the batch with the given merkle root to all the operators that announced it at once,
+// returning the first one for which verify succeeds. Batches are verified one at a time, and the
+// peers are given batchGossipFetchTimeout in total, so unresponsive ones can't hold the batch back.
+func (g *BatchGossip) Fetch(ctx context.Context, merkleRoot [32]byte, maxSize int64, verify func([]byte) bool) ([]byte, error) {
+	if g == nil {
+		return nil, fmt.Errorf("batch gossip is disabled")
+	}
+
+	providers := g.providersOf(merkleRoot)
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no peer announced the batch")
+	}
+	ctx, cancel := context.WithTimeout(ctx, batchGossipFetchTimeout)
+	defer cancel()
+
+	type peerBatch struct {
+		provider peer.ID
+		batch    []byte
+		err      error
+	}
+	batches := make(chan peerBatch, len(providers))
+	for _, provider := range providers {
+		go func(provider peer.ID) {
+			batch, err := g.fetchFrom(ctx, provider, merkleRoot, maxSize)
+			batches <- peerBatch{provider: provider, batch: batch, err: err}
+		}(provider)
+	}
+
+	var errs []error
+	for range providers {
+		result := <-batches
+		if result.err == nil && !verify(result.batch) {
+			result.err = fmt.Errorf("batch doesn't match its merkle root")
+		}
+		if result.err == nil {
+			return result.batch, nil
+		}
+		g.logger.Warnf("Could not get batch 0x%x from peer %s: %v", merkleRoot, result.provider, result.err)
+		errs = append(errs, result.err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (g *BatchGossip) fetchFrom(ctx context.Context, provider peer.ID, merkleRoot [32]byte, maxSize int64) ([]byte, error) {
+	stream, err := g.host.NewStream(ctx, provider, BatchGossipProtocol)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = stream.SetDeadline(deadline)
+	}
+
+	if _, err = stream.Write(merkleRoot[:]); err != nil {
+		return nil, err
+	}
+	if err = stream.CloseWrite(); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 9)
+	if _, err = io.ReadFull(stream, header[:1]); err != nil {
+		return nil, err
+	}
+	if header[0] != batchGossipFound {
+		return nil, fmt.Errorf("peer doesn't have the batch")
+	}
+	if _, err = io.ReadFull(stream, header[1:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint64(header[1:])
+	if size > uint64(maxSize) {
+		return nil, fmt.Errorf("batch size %d exceeds max batch size %d", size, maxSize)
+	}
+
+	// The batch grows as it's received instead of being allocated from the size the peer declared
+	batch, err := io.ReadAll(io.LimitReader(stream, int64(size)))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(batch)) != size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return batch, nil
+}
+
+// Close stops the libp2p host.
+func (g *BatchGossip) Close() error {
+	if g == nil {
+		return nil
+	}
+	return g.host.Close()
+}
+
+// verifyBatchBytesMerkleRoot checks the merkle root of a batch, which can be compressed.
+func verifyBatchBytesMerkleRoot(batch []byte, expectedMerkleRoot [32]byte, maxBatchSize int64) bool {
+	decoder, err := NewBatchStreamDecoder(bytes.NewReader(batch), maxBatchSize)
+	if err != nil {
+		return false
+	}
+	defer decoder.Close()
+	for {
+		if _, err = decoder.Next(); err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false
+		}
+	}
+	merkleRoot, err := decoder.MerkleRoot()
+	return err == nil && merkleRoot == expectedMerkleRoot
+}
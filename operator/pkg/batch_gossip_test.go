@@ -0,0 +1,237 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// testOperatorRegistry considers registered the operators it was created with
+type testOperatorRegistry map[eigentypes.OperatorId]bool
+
+func (r testOperatorRegistry) IsOperatorIdRegistered(_ context.Context, operatorId eigentypes.OperatorId) (bool, error) {
+	return r[operatorId], nil
+}
+
+func newTestBlsSigner(t *testing.T) *LocalBlsSigner {
+	keyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Unexpected error generating BLS keys: %v", err)
+	}
+	return &LocalBlsSigner{keyPair: keyPair}
+}
+
+func newTestBatchGossip(t *testing.T, ctx context.Context, bootstrapPeers []string, signer BlsSigner, registry OperatorRegistry) (*BatchGossip, *BatchCache) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+	cache, err := NewBatchCache(t.TempDir(), testMaxBatchSize)
+	if err != nil {
+		t.Fatalf("Unexpected error creating cache: %v", err)
+	}
+	gossip, err := NewBatchGossip(ctx, []string{"/ip4/127.0.0.1/tcp/0"}, bootstrapPeers, "", cache, signer, registry, logger)
+	if err != nil {
+		t.Fatalf("Unexpected error starting batch gossip: %v", err)
+	}
+	t.Cleanup(func() { _ = gossip.Close() })
+	return gossip, cache
+}
+
+func TestBatchGossipSharesAnnouncedBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batch, root := readBatchFixture(t)
+
+	providerSigner, requesterSigner := newTestBlsSigner(t), newTestBlsSigner(t)
+	registry := testOperatorRegistry{
+		eigentypes.OperatorIdFromG1Pubkey(providerSigner.PublicKeyG1()):  true,
+		eigentypes.OperatorIdFromG1Pubkey(requesterSigner.PublicKeyG1()): true,
+	}
+	provider, providerCache := newTestBatchGossip(t, ctx, nil, providerSigner, registry)
+	providerAddr := provider.host.Addrs()[0].String() + "/p2p/" + provider.host.ID().String()
+	requester, _ := newTestBatchGossip(t, ctx, []string{providerAddr}, requesterSigner, registry)
+
+	if err := providerCache.Put(root, batch); err != nil {
+		t.Fatalf("Unexpected error adding batch: %v", err)
+	}
+	// the gossipsub mesh takes a few heartbeats to form, so the batch is announced until it's received
+	deadline := time.Now().Add(10 * time.Second)
+	for len(requester.providersOf(root)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Batch announcement was not received")
+		}
+		provider.Announce(ctx, root)
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	verify := func(b []byte) bool { return verifyBatchBytesMerkleRoot(b, root, testMaxBatchSize) }
+	received, err := requester.Fetch(ctx, root, testMaxBatchSize, verify)
+	if err != nil {
+		t.Fatalf("Unexpected error fetching batch: %v", err)
+	}
+	if len(received) != len(batch) {
+		t.Errorf("Expected batch of size %d, got %d", len(batch), len(received))
+	}
+
+	if _, err = requester.Fetch(ctx, root, testMaxBatchSize, func([]byte) bool { return false }); err == nil {
+		t.Errorf("Expected batch not matching its merkle root to be rejected")
+	}
+	if _, err = requester.Fetch(ctx, root, 10, verify); err == nil {
+		t.Errorf("Expected batch exceeding the max batch size to be rejected")
+	}
+	if _, err = requester.Fetch(ctx, [32]byte{1}, testMaxBatchSize, verify); err == nil {
+		t.Errorf("Expected error fetching a batch no peer announced")
+	}
+}
+
+func TestBatchFromPeersDoesNotWaitForDataService(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batch, root := readBatchFixture(t)
+
+	providerSigner, requesterSigner := newTestBlsSigner(t), newTestBlsSigner(t)
+	registry := testOperatorRegistry{
+		eigentypes.OperatorIdFromG1Pubkey(providerSigner.PublicKeyG1()):  true,
+		eigentypes.OperatorIdFromG1Pubkey(requesterSigner.PublicKeyG1()): true,
+	}
+	provider, providerCache := newTestBatchGossip(t, ctx, nil, providerSigner, registry)
+	providerAddr := provider.host.Addrs()[0].String() + "/p2p/" + provider.host.ID().String()
+	requester, _ := newTestBatchGossip(t, ctx, []string{providerAddr}, requesterSigner, registry)
+	if err := providerCache.Put(root, batch); err != nil {
+		t.Fatalf("Unexpected error adding batch: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for len(requester.providersOf(root)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Batch announcement was not received")
+		}
+		provider.Announce(ctx, root)
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	// the data service never answers, so the batch can only come from peers
+	dataServiceCancelled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(dataServiceCancelled)
+	}))
+	defer server.Close()
+	operator := newDownloadTestOperator(t)
+	operator.Config.Operator.MaxBatchSize = testMaxBatchSize
+	operator.batchGossip = requester
+
+	received, err := operator.getVerifiedBatchBytes(ctx, server.URL, root, 1, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error getting batch: %v", err)
+	}
+	if !bytes.Equal(received, batch) {
+		t.Errorf("Expected the batch sent by peers")
+	}
+	select {
+	case <-dataServiceCancelled:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected the data service download to be cancelled")
+	}
+}
+
+func TestBatchGossipAcceptsOnlyAnnouncementsOfRegisteredOperators(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signer := newTestBlsSigner(t)
+	operatorId := eigentypes.OperatorIdFromG1Pubkey(signer.PublicKeyG1())
+	registry := testOperatorRegistry{operatorId: true}
+	gossip, _ := newTestBatchGossip(t, ctx, nil, signer, registry)
+	root := [32]byte{1}
+
+	signedAnnouncement := func(signer BlsSigner) batchAnnouncement {
+		signature, err := signer.Sign(ctx, batchAnnouncementHash(root, gossip.host.ID()))
+		if err != nil {
+			t.Fatalf("Unexpected error signing announcement: %v", err)
+		}
+		pubkeyG1 := utils.CompressG1Point(signer.PublicKeyG1())
+		pubkeyG2 := utils.CompressG2Point(signer.PublicKeyG2())
+		compressedSignature := utils.CompressG1Point(signature.G1Point)
+		return batchAnnouncement{MerkleRoot: root, PubkeyG1: pubkeyG1[:], PubkeyG2: pubkeyG2[:], Signature: compressedSignature[:]}
+	}
+	validate := func(announcement batchAnnouncement, from []byte) bool {
+		data, err := json.Marshal(announcement)
+		if err != nil {
+			t.Fatalf("Unexpected error encoding announcement: %v", err)
+		}
+		message := &pubsub.Message{Message: &pubsubpb.Message{Data: data, From: from}}
+		return gossip.validateBatchAnnouncement(ctx, gossip.host.ID(), message)
+	}
+	from := []byte(gossip.host.ID())
+
+	if !validate(signedAnnouncement(signer), from) {
+		t.Errorf("Expected announcement signed by a registered operator to be accepted")
+	}
+	if validate(batchAnnouncement{MerkleRoot: root}, from) {
+		t.Errorf("Expected unsigned announcement to be rejected")
+	}
+	if validate(signedAnnouncement(newTestBlsSigner(t)), from) {
+		t.Errorf("Expected announcement signed by an unregistered operator to be rejected")
+	}
+	// signed for the peer of the operator, so it can't be replayed by another peer
+	if validate(signedAnnouncement(signer), []byte("another peer")) {
+		t.Errorf("Expected announcement relayed from another peer to be rejected")
+	}
+	forged := signedAnnouncement(signer)
+	forged.MerkleRoot = [32]byte{2}
+	if validate(forged, from) {
+		t.Errorf("Expected announcement of another batch to be rejected")
+	}
+	mismatched := signedAnnouncement(signer)
+	otherSigner := newTestBlsSigner(t)
+	otherPubkeyG1 := utils.CompressG1Point(otherSigner.PublicKeyG1())
+	mismatched.PubkeyG1 = otherPubkeyG1[:]
+	registry[eigentypes.OperatorIdFromG1Pubkey(otherSigner.PublicKeyG1())] = true
+	if validate(mismatched, from) {
+		t.Errorf("Expected announcement with a G1 key not matching the signing key to be rejected")
+	}
+}
+
+func TestBatchGossipKeepsPeerIdWithKeyPath(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "p2p_key")
+	first, err := loadOrCreateP2PKey(keyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error creating key: %v", err)
+	}
+	second, err := loadOrCreateP2PKey(keyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error loading key: %v", err)
+	}
+	if !first.Equals(second) {
+		t.Errorf("Expected the same key to be loaded")
+	}
+}
+
+func TestBatchGossipRequiresBatchCache(t *testing.T) {
+	if _, err := NewBatchGossip(context.Background(), []string{"/ip4/127.0.0.1/tcp/0"}, nil, "", nil, newTestBlsSigner(t), testOperatorRegistry{}, nil); err == nil {
+		t.Errorf("Expected error starting batch gossip without batch cache")
+	}
+}
+
+func TestDisabledBatchGossip(t *testing.T) {
+	var gossip *BatchGossip
+	gossip.Announce(context.Background(), [32]byte{1})
+	if _, err := gossip.Fetch(context.Background(), [32]byte{1}, testMaxBatchSize, nil); err == nil {
+		t.Errorf("Expected error fetching from disabled batch gossip")
+	}
+	if err := gossip.Close(); err != nil {
+		t.Errorf("Unexpected error closing disabled batch gossip: %v", err)
+	}
+}
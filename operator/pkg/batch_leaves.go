@@ -0,0 +1,119 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	batchFileExtension       = ".json"
+	batchLeavesFileExtension = ".leaves"
+)
+
+var errBatchLeafMismatch = errors.New("batch proof doesn't match the batch merkle tree")
+
+// batchLeavesURL returns the URL of the batch leaves, the commitment of every proof of the batch,
+// which the batcher uploads next to the batch. Only batches stored in the data service have one.
+func batchLeavesURL(batchDataPointer string) (string, bool) {
+	pointerUrl, err := url.Parse(batchDataPointer)
+	if err != nil || (pointerUrl.Scheme != "http" && pointerUrl.Scheme != "https") {
+		return "", false
+	}
+	if _, isIpfs := ipfsPathFromPointer(batchDataPointer); isIpfs {
+		return "", false
+	}
+	if !strings.HasSuffix(pointerUrl.Path, batchFileExtension) {
+		return "", false
+	}
+	pointerUrl.Path = strings.TrimSuffix(pointerUrl.Path, batchFileExtension) + batchLeavesFileExtension
+	return pointerUrl.String(), true
+}
+
+// fetchBatchLeaves downloads the batch leaves and checks they build the expected merkle root,
+// so each proof can be checked against its leaf as soon as it's downloaded.
+func (o *Operator) fetchBatchLeaves(ctx context.Context, batchDataPointer string, expectedMerkleRoot [32]byte) ([][32]byte, error) {
+	leavesURL, ok := batchLeavesURL(batchDataPointer)
+	if !ok {
+		return nil, fmt.Errorf("batch has no leaves file")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", leavesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	release, err := o.downloadLimiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = o.downloadLimiter.limitBody(ctx, resp.Body, release)
+	defer closeBody(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error getting batch leaves: %s", resp.Status)
+	}
+
+	limit := o.Config.Operator.MaxBatchSize
+	encodedLeaves, err := io.ReadAll(&batchSizeLimitedReader{R: resp.Body, N: limit, max: limit})
+	if err != nil {
+		return nil, err
+	}
+	return decodeBatchLeaves(encodedLeaves, expectedMerkleRoot)
+}
+
+// decodeBatchLeaves splits the concatenated 32 byte leaves, checking their merkle root.
+func decodeBatchLeaves(encodedLeaves []byte, expectedMerkleRoot [32]byte) ([][32]byte, error) {
+	if len(encodedLeaves) == 0 || len(encodedLeaves)%32 != 0 {
+		return nil, fmt.Errorf("invalid batch leaves size %d", len(encodedLeaves))
+	}
+	leaves := make([][32]byte, len(encodedLeaves)/32)
+	for i := range leaves {
+		copy(leaves[i][:], encodedLeaves[i*32:])
+	}
+
+	merkleRoot, err := batchMerkleRoot(leaves)
+	if err != nil {
+		return nil, err
+	}
+	if merkleRoot != expectedMerkleRoot {
+		return nil, fmt.Errorf("batch leaves don't match the batch merkle root")
+	}
+	return leaves, nil
+}
+
+// readBatchValidatingLeaves reads the whole batch, decoding its proofs while it's downloaded
+// so the download stops at the first proof that doesn't match its leaf.
+func readBatchValidatingLeaves(download io.Reader, leaves [][32]byte, maxBatchSize int64) ([]byte, error) {
+	var batch bytes.Buffer
+	reader := io.TeeReader(&batchSizeLimitedReader{R: download, N: maxBatchSize, max: maxBatchSize}, &batch)
+
+	decoder, err := NewBatchStreamDecoder(reader, maxBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer decoder.Close()
+	decoder.ExpectLeaves(leaves)
+
+	for {
+		if _, err = decoder.Next(); err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	// the rest of the stream, such as the end of a compressed batch, wasn't needed by the decoder
+	if _, err = io.Copy(io.Discard, reader); err != nil {
+		return nil, err
+	}
+	return batch.Bytes(), nil
+}
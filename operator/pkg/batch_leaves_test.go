@@ -0,0 +1,146 @@
+package operator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// countingReader counts the bytes read from R
+type countingReader struct {
+	R *bytes.Reader
+	N int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.R.Read(p)
+	c.N += n
+	return n, err
+}
+
+func readBatchFixtureLeaves(t *testing.T, batch []byte) [][32]byte {
+	verificationDataBatch, _, err := decodeBatchStream(t, batch, testMaxBatchSize)
+	if err != nil {
+		t.Fatalf("Unexpected error decoding batch: %v", err)
+	}
+	leaves := make([][32]byte, len(verificationDataBatch))
+	for i, verificationData := range verificationDataBatch {
+		if leaves[i], err = verificationDataCommitmentHash(verificationData); err != nil {
+			t.Fatalf("Unexpected error computing leaf: %v", err)
+		}
+	}
+	return leaves
+}
+
+func TestBatchLeavesMatchTheSdkCommitments(t *testing.T) {
+	batch, root := readBatchFixture(t)
+	verificationDataBatch, _, err := decodeBatchStream(t, batch, testMaxBatchSize)
+	if err != nil {
+		t.Fatalf("Unexpected error decoding batch: %v", err)
+	}
+
+	leaves := readBatchFixtureLeaves(t, batch)
+	for i, verificationData := range verificationDataBatch {
+		sdkVerificationData := batcher.VerificationData{
+			ProvingSystem:      verificationData.ProvingSystemId,
+			Proof:              verificationData.Proof,
+			PubInput:           verificationData.PubInput,
+			VerificationKey:    verificationData.VerificationKey,
+			VmProgramCode:      verificationData.VmProgramCode,
+			ProofGeneratorAddr: ethcommon.HexToAddress(verificationData.ProofGeneratorAddr),
+		}
+		commitment := sdkVerificationData.Commitment()
+		if commitment.Hash() != leaves[i] {
+			t.Errorf("Expected the leaf of proof %d to be the hash of its sdk commitment", i)
+		}
+	}
+
+	// the batch was built by the batcher, so its leaves must lead to its root
+	batchRoot, err := merkle.Root(merkle.CurrentVersion, leaves)
+	if err != nil || batchRoot != root {
+		t.Errorf("Expected the leaves to lead to the batcher merkle root %x, got %x and %v", root, batchRoot, err)
+	}
+}
+
+func TestBatchLeavesURL(t *testing.T) {
+	cases := map[string]string{
+		"https://storage.alignedlayer.com/abcd.json":          "https://storage.alignedlayer.com/abcd.leaves",
+		"http://localhost:4566/aligned.storage/abcd.json?x=1": "http://localhost:4566/aligned.storage/abcd.leaves?x=1",
+		"https://storage.alignedlayer.com/abcd":               "",
+		"https://ipfs.io/ipfs/bafybeigdyrzt/batch.json":       "",
+		"ipfs://bafybeigdyrzt":                                "",
+		"celestia://1/000000616c69676e6564/00":                "",
+	}
+	for pointer, expected := range cases {
+		leavesURL, ok := batchLeavesURL(pointer)
+		if ok != (expected != "") || leavesURL != expected {
+			t.Errorf("Expected leaves URL %q for %s, got %q", expected, pointer, leavesURL)
+		}
+	}
+}
+
+func TestDecodeBatchLeavesChecksMerkleRoot(t *testing.T) {
+	batch, root := readBatchFixture(t)
+	leaves := readBatchFixtureLeaves(t, batch)
+	var encodedLeaves []byte
+	for _, leaf := range leaves {
+		encodedLeaves = append(encodedLeaves, leaf[:]...)
+	}
+
+	decodedLeaves, err := decodeBatchLeaves(encodedLeaves, root)
+	if err != nil {
+		t.Fatalf("Unexpected error decoding leaves: %v", err)
+	}
+	if len(decodedLeaves) != fixtureBatchNumProofs {
+		t.Errorf("Expected %d leaves, got %d", fixtureBatchNumProofs, len(decodedLeaves))
+	}
+
+	if _, err = decodeBatchLeaves(encodedLeaves[32:], root); err == nil {
+		t.Errorf("Expected leaves not matching the merkle root to be rejected")
+	}
+	if _, err = decodeBatchLeaves(encodedLeaves[1:], root); err == nil {
+		t.Errorf("Expected leaves of invalid size to be rejected")
+	}
+}
+
+func TestReadBatchValidatingLeaves(t *testing.T) {
+	batch, _ := readBatchFixture(t)
+	leaves := readBatchFixtureLeaves(t, batch)
+
+	read, err := readBatchValidatingLeaves(bytes.NewReader(batch), leaves, testMaxBatchSize)
+	if err != nil {
+		t.Fatalf("Unexpected error reading batch: %v", err)
+	}
+	if !bytes.Equal(read, batch) {
+		t.Errorf("Expected the read batch to match the original one")
+	}
+}
+
+func TestReadBatchValidatingLeavesStopsAtFirstBadProof(t *testing.T) {
+	batch, _ := readBatchFixture(t)
+	leaves := readBatchFixtureLeaves(t, batch)
+	leaves[1] = [32]byte{1}
+
+	download := &countingReader{R: bytes.NewReader(batch)}
+	_, err := readBatchValidatingLeaves(download, leaves, testMaxBatchSize)
+	if !errors.Is(err, errBatchLeafMismatch) {
+		t.Fatalf("Expected leaf mismatch error, got %v", err)
+	}
+	if download.N >= len(batch) {
+		t.Errorf("Expected the download to stop before the end of the batch, read %d of %d bytes", download.N, len(batch))
+	}
+}
+
+func TestReadBatchValidatingLeavesRejectsExtraProofs(t *testing.T) {
+	batch, _ := readBatchFixture(t)
+	leaves := readBatchFixtureLeaves(t, batch)
+
+	_, err := readBatchValidatingLeaves(bytes.NewReader(batch), leaves[:len(leaves)-1], testMaxBatchSize)
+	if !errors.Is(err, errBatchLeafMismatch) {
+		t.Errorf("Expected leaf mismatch error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package operator
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Consecutive failures after which a batch source is considered unhealthy
+	batchSourceMaxFailures = 3
+	// Time an unhealthy batch source is tried last, doubled on every further failure
+	batchSourceCooldown    = time.Minute
+	batchSourceMaxCooldown = 30 * time.Minute
+)
+
+// batchMirrorSources returns the URLs of the batch in the configured mirrors, which serve
+// the same files as the data service. Batches stored in IPFS or DA layers have no mirrors.
+func batchMirrorSources(batchDataPointer string, mirrors []string) []string {
+	pointerUrl, err := url.Parse(batchDataPointer)
+	if err != nil || (pointerUrl.Scheme != "http" && pointerUrl.Scheme != "https") {
+		return nil
+	}
+	if _, isIpfs := ipfsPathFromPointer(batchDataPointer); isIpfs {
+		return nil
+	}
+	fileName := path.Base(pointerUrl.Path)
+	if fileName == "/" || fileName == "." {
+		return nil
+	}
+
+	var sources []string
+	for _, mirror := range mirrors {
+		sources = append(sources, strings.TrimSuffix(mirror, "/")+"/"+fileName)
+	}
+	return sources
+}
+
+type batchSourceState struct {
+	failures       int
+	unhealthyUntil time.Time
+}
+
+// BatchSourceHealth tracks the batch sources that recently failed, by host, so failing mirrors
+// and gateways are tried after the healthy ones instead of delaying every download.
+// Sources are never skipped, an unhealthy source is still tried if all the others fail.
+//
+// A nil *BatchSourceHealth doesn't track sources.
+type BatchSourceHealth struct {
+	mu      sync.Mutex
+	sources map[string]*batchSourceState
+	now     func() time.Time
+}
+
+func NewBatchSourceHealth() *BatchSourceHealth {
+	return &BatchSourceHealth{sources: make(map[string]*batchSourceState), now: time.Now}
+}
+
+func batchSourceHost(source string) string {
+	sourceUrl, err := url.Parse(source)
+	if err != nil {
+		return source
+	}
+	return sourceUrl.Scheme + "://" + sourceUrl.Host
+}
+
+// ReportSuccess marks the host of the source as healthy.
+func (h *BatchSourceHealth) ReportSuccess(source string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.sources, batchSourceHost(source))
+}
+
+// ReportFailure counts a failed download from the source, marking its host as unhealthy
+// once it failed batchSourceMaxFailures times in a row.
+func (h *BatchSourceHealth) ReportFailure(source string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	host := batchSourceHost(source)
+	state, ok := h.sources[host]
+	if !ok {
+		state = &batchSourceState{}
+		h.sources[host] = state
+	}
+	state.failures++
+	if state.failures < batchSourceMaxFailures {
+		return
+	}
+
+	cooldown := batchSourceCooldown << (state.failures - batchSourceMaxFailures)
+	if cooldown > batchSourceMaxCooldown || cooldown <= 0 {
+		cooldown = batchSourceMaxCooldown
+	}
+	state.unhealthyUntil = h.now().Add(cooldown)
+}
+
+// Healthy returns whether the host of the source isn't in its cooldown.
+func (h *BatchSourceHealth) Healthy(source string) bool {
+	if h == nil {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.sources[batchSourceHost(source)]
+	return !ok || !h.now().Before(state.unhealthyUntil)
+}
+
+// Order moves the unhealthy sources after the healthy ones, keeping the configured order otherwise.
+func (h *BatchSourceHealth) Order(sources []string) []string {
+	ordered := append([]string(nil), sources...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return h.Healthy(ordered[i]) && !h.Healthy(ordered[j])
+	})
+	return ordered
+}
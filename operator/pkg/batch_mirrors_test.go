@@ -0,0 +1,98 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBatchMirrorSources(t *testing.T) {
+	mirrors := []string{"https://mirror-1.example.com/", "https://mirror-2.example.com/batches"}
+
+	sources := batchMirrorSources("https://storage.alignedlayer.com/abcd.json", mirrors)
+	expected := []string{"https://mirror-1.example.com/abcd.json", "https://mirror-2.example.com/batches/abcd.json"}
+	if !reflect.DeepEqual(sources, expected) {
+		t.Errorf("Expected sources %v, got %v", expected, sources)
+	}
+
+	for _, pointer := range []string{"ipfs://bafkqaaa", "https://ipfs.io/ipfs/bafkqaaa", "celestia://1/000000616c69676e6564/00", "https://storage.alignedlayer.com/"} {
+		if sources = batchMirrorSources(pointer, mirrors); sources != nil {
+			t.Errorf("Expected no mirrors for %s, got %v", pointer, sources)
+		}
+	}
+}
+
+func TestBatchSourceHealthMovesFailingSourcesLast(t *testing.T) {
+	now := time.Now()
+	health := NewBatchSourceHealth()
+	health.now = func() time.Time { return now }
+	sources := []string{"https://storage.alignedlayer.com/abcd.json", "https://mirror.example.com/abcd.json"}
+
+	for i := 0; i < batchSourceMaxFailures-1; i++ {
+		health.ReportFailure(sources[0])
+	}
+	if ordered := health.Order(sources); !reflect.DeepEqual(ordered, sources) {
+		t.Errorf("Expected sources to keep their order before reaching the max failures, got %v", ordered)
+	}
+
+	health.ReportFailure(sources[0])
+	expected := []string{sources[1], sources[0]}
+	if ordered := health.Order(sources); !reflect.DeepEqual(ordered, expected) {
+		t.Errorf("Expected the failing source to be tried last, got %v", ordered)
+	}
+
+	now = now.Add(batchSourceCooldown)
+	if !health.Healthy(sources[0]) {
+		t.Errorf("Expected the source to be tried again after its cooldown")
+	}
+	// failing again doubles the cooldown
+	health.ReportFailure(sources[0])
+	now = now.Add(batchSourceCooldown)
+	if health.Healthy(sources[0]) {
+		t.Errorf("Expected the source cooldown to be doubled")
+	}
+
+	health.ReportSuccess(sources[0])
+	if !health.Healthy(sources[0]) {
+		t.Errorf("Expected the source to be healthy after a success")
+	}
+}
+
+func TestOpenBatchDownloadFailsOverToMirror(t *testing.T) {
+	batch := testBatch()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	mirror, _ := newBatchServer(batch, md5ETag(batch), "", 0)
+	defer mirror.Close()
+
+	operator := newDownloadTestOperator(t)
+	operator.Config.Operator.BatchMirrors = []string{mirror.URL}
+	operator.batchSourceHealth = NewBatchSourceHealth()
+
+	for i := 0; i < batchSourceMaxFailures; i++ {
+		download, err := operator.openBatchDownload(context.Background(), failing.URL+"/abcd.json", 1, time.Millisecond)
+		if err != nil {
+			t.Fatalf("Unexpected error downloading batch: %v", err)
+		}
+		downloaded, err := io.ReadAll(download)
+		download.Close()
+		if err != nil || !bytes.Equal(downloaded, batch) {
+			t.Fatalf("Expected the batch to be downloaded from the mirror, got error %v", err)
+		}
+	}
+
+	sources, err := operator.batchSources(failing.URL + "/abcd.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sources[0] != mirror.URL+"/abcd.json" {
+		t.Errorf("Expected the mirror to be tried first once the data service is unhealthy, got %v", sources)
+	}
+}
@@ -0,0 +1,175 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const ipfsScheme = "ipfs://"
+
+// ipfsPathFromPointer returns the IPFS path of the batch, "<cid>" or "<cid>/<path>", if the batch
+// data pointer references one. Pointers can be IPFS URIs, such as ipfs://<cid>, or URLs of
+// path (https://<gateway>/ipfs/<cid>) or subdomain (https://<cid>.ipfs.<gateway>) gateways.
+func ipfsPathFromPointer(batchDataPointer string) (string, bool) {
+	if strings.HasPrefix(batchDataPointer, ipfsScheme) {
+		return validIpfsPath(strings.TrimPrefix(batchDataPointer, ipfsScheme))
+	}
+
+	pointerUrl, err := url.Parse(batchDataPointer)
+	if err != nil || (pointerUrl.Scheme != "http" && pointerUrl.Scheme != "https") {
+		return "", false
+	}
+	if path, found := strings.CutPrefix(pointerUrl.Path, "/ipfs/"); found {
+		return validIpfsPath(path)
+	}
+	if cid, _, found := strings.Cut(pointerUrl.Hostname(), ".ipfs."); found {
+		return validIpfsPath(cid + pointerUrl.Path)
+	}
+	return "", false
+}
+
+// validIpfsPath checks the CID is made of base32 or base58 characters, so it can't change the gateway URL.
+func validIpfsPath(path string) (string, bool) {
+	path = strings.TrimSuffix(path, "/")
+	cid, _, _ := strings.Cut(path, "/")
+	if cid == "" {
+		return "", false
+	}
+	for _, c := range cid {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return "", false
+		}
+	}
+	if strings.Contains(path, "..") {
+		return "", false
+	}
+	return path, true
+}
+
+// batchSources returns the URLs the batch can be downloaded from, in the order they are tried.
+// Batches stored in IPFS are fetched from the configured gateways first, falling back to the
+// batch data pointer itself when it's an HTTP URL, and then to the configured mirrors.
+// Sources that recently failed are tried last.
+func (o *Operator) batchSources(batchDataPointer string) ([]string, error) {
+	var sources []string
+	if ipfsPath, ok := ipfsPathFromPointer(batchDataPointer); ok {
+		for _, gateway := range o.Config.Operator.IpfsGateways {
+			sources = append(sources, strings.TrimSuffix(gateway, "/")+"/ipfs/"+ipfsPath)
+		}
+	}
+	if !strings.HasPrefix(batchDataPointer, ipfsScheme) {
+		sources = append(sources, batchDataPointer)
+	}
+	sources = append(sources, batchMirrorSources(batchDataPointer, o.Config.Operator.BatchMirrors)...)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("batch is stored in IPFS but no IPFS gateways are configured")
+	}
+	return o.batchSourceHealth.Order(sources), nil
+}
+
+// batchReader is the content of a batch being downloaded.
+type batchReader interface {
+	io.Reader
+	Close()
+}
+
+// memoryBatch is a batch retrieved from a DA layer, which is read from memory.
+type memoryBatch struct {
+	*bytes.Reader
+}
+
+func (memoryBatch) Close() {}
+
+// openBatchDownload starts downloading the batch from the first of its sources that responds.
+// The batch merkle root is always checked after the download, so gateways don't need to be trusted.
+func (o *Operator) openBatchDownload(ctx context.Context, batchDataPointer string, maxRetries int, retryDelay time.Duration) (batchReader, error) {
+	if strings.HasPrefix(batchDataPointer, celestiaScheme) {
+		return o.getCelestiaBatch(ctx, batchDataPointer, maxRetries, retryDelay)
+	}
+	if strings.HasPrefix(batchDataPointer, eigenDAScheme) {
+		return o.getEigenDABatch(ctx, batchDataPointer, maxRetries, retryDelay)
+	}
+
+	sources, err := o.batchSources(batchDataPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, source := range sources {
+		download, err := o.newBatchDownload(ctx, source, maxRetries, retryDelay)
+		if err == nil {
+			o.batchSourceHealth.ReportSuccess(source)
+			return download, nil
+		}
+		o.Logger.Warnf("Could not download batch from %s: %v", source, err)
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+		o.batchSourceHealth.ReportFailure(source)
+	}
+	return nil, fmt.Errorf("could not download batch from any source: %w", errors.Join(errs...))
+}
+
+// getCelestiaBatch retrieves a batch posted to Celestia, verifying its inclusion in the referenced block.
+func (o *Operator) getCelestiaBatch(ctx context.Context, batchDataPointer string, maxRetries int, retryDelay time.Duration) (batchReader, error) {
+	if o.celestiaClient == nil {
+		return nil, fmt.Errorf("batch is stored in Celestia but no Celestia node is configured")
+	}
+	pointer, err := parseCelestiaBlobPointer(batchDataPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	o.Logger.Infof("Getting batch from Celestia, height: %d, namespace: %x", pointer.Height, pointer.Namespace)
+	return o.getDABatch(ctx, "Celestia", maxRetries, retryDelay, func() ([]byte, error) {
+		return o.celestiaClient.GetBlob(ctx, pointer)
+	})
+}
+
+// getEigenDABatch retrieves a batch dispersed to EigenDA, verifying its blob certificate.
+func (o *Operator) getEigenDABatch(ctx context.Context, batchDataPointer string, maxRetries int, retryDelay time.Duration) (batchReader, error) {
+	if o.eigenDAClient == nil {
+		return nil, fmt.Errorf("batch is stored in EigenDA but no EigenDA proxy is configured")
+	}
+	certificate, err := parseEigenDABlobPointer(batchDataPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	o.Logger.Infof("Getting batch from EigenDA, certificate: 0x%x", certificate)
+	return o.getDABatch(ctx, "EigenDA", maxRetries, retryDelay, func() ([]byte, error) {
+		return o.eigenDAClient.GetBlob(ctx, certificate, o.Config.Operator.MaxBatchSize)
+	})
+}
+
+// getDABatch retrieves a batch from a DA layer with getBlob, retrying with exponential backoff.
+func (o *Operator) getDABatch(ctx context.Context, daLayer string, maxRetries int, retryDelay time.Duration, getBlob func() ([]byte, error)) (batchReader, error) {
+	var err error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			o.Logger.Infof("Waiting for %s before retrying %s fetch (attempt %d of %d)", retryDelay, daLayer, attempt+1, maxRetries)
+			select {
+			case <-time.After(retryDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			retryDelay *= 2
+		}
+
+		var blob []byte
+		blob, err = getBlob()
+		if err == nil {
+			return memoryBatch{bytes.NewReader(blob)}, nil
+		}
+		o.Logger.Warnf("Error fetching batch from %s - (attempt %d): %v", daLayer, attempt+1, err)
+	}
+	return nil, err
+}
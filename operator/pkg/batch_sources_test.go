@@ -0,0 +1,56 @@
+package operator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIpfsPathFromPointer(t *testing.T) {
+	cid := "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+	pointers := map[string]string{
+		"ipfs://" + cid:                                  cid,
+		"ipfs://" + cid + "/batch.json":                  cid + "/batch.json",
+		"https://ipfs.io/ipfs/" + cid:                    cid,
+		"https://ipfs.io/ipfs/" + cid + "/batch.json":    cid + "/batch.json",
+		"https://" + cid + ".ipfs.dweb.link/batch.json":  cid + "/batch.json",
+		"https://storage.alignedlayer.com/batch.json":    "",
+		"ipfs://../batch.json":                           "",
+		"ipfs://" + cid + "/../batch.json":               "",
+		"https://ipfs.io/ipfs/" + cid + "@evil.com/path": "",
+	}
+
+	for pointer, expected := range pointers {
+		path, ok := ipfsPathFromPointer(pointer)
+		if ok != (expected != "") || path != expected {
+			t.Errorf("Expected IPFS path %q for %s, got %q", expected, pointer, path)
+		}
+	}
+}
+
+func TestBatchSources(t *testing.T) {
+	operator := &Operator{}
+	operator.Config.Operator.IpfsGateways = []string{"http://127.0.0.1:8080/", "https://ipfs.io"}
+
+	sources, err := operator.batchSources("ipfs://bafkqaaa/batch.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"http://127.0.0.1:8080/ipfs/bafkqaaa/batch.json", "https://ipfs.io/ipfs/bafkqaaa/batch.json"}
+	if !reflect.DeepEqual(sources, expected) {
+		t.Errorf("Expected sources %v, got %v", expected, sources)
+	}
+
+	sources, err = operator.batchSources("https://dweb.link/ipfs/bafkqaaa")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = []string{"http://127.0.0.1:8080/ipfs/bafkqaaa", "https://ipfs.io/ipfs/bafkqaaa", "https://dweb.link/ipfs/bafkqaaa"}
+	if !reflect.DeepEqual(sources, expected) {
+		t.Errorf("Expected sources %v, got %v", expected, sources)
+	}
+
+	operator.Config.Operator.IpfsGateways = nil
+	if _, err = operator.batchSources("ipfs://bafkqaaa"); err == nil {
+		t.Errorf("Expected an error for an IPFS batch without gateways")
+	}
+}
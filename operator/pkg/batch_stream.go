@@ -0,0 +1,343 @@
+package operator
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ugorji/go/codec"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+const (
+	cborMajorTypeArray        = 4
+	cborIndefiniteLength      = 31
+	cborBreak                 = 0xff
+	jsonArrayStart       byte = '['
+)
+
+// BatchStreamDecoder decodes the proofs of a batch one at a time from a byte stream, so the
+// operator never holds more than the proofs being verified in memory. The stream can be
+// a CBOR or JSON encoded batch, optionally gzip or zstd compressed.
+//
+// While decoding, it computes the commitment of every proof, which is later used to check
+// the batch merkle root, see BatchStreamDecoder.MerkleRoot.
+type BatchStreamDecoder struct {
+	reader *bufio.Reader
+	closer func()
+
+	cborDecoder *cbor.Decoder
+	jsonDecoder *json.Decoder
+
+	// remaining is the number of elements left in a definite length CBOR array, or -1
+	remaining int64
+	leaves    [][32]byte
+	done      bool
+
+	// expectedLeaves, when known, are checked against the leaf of every proof as it's decoded
+	expectedLeaves [][32]byte
+}
+
+// NewBatchStreamDecoder creates a decoder reading the batch from r. The decompressed stream
+// is limited to maxBatchSize bytes, so a compressed batch can't exceed the operator limits.
+func NewBatchStreamDecoder(r io.Reader, maxBatchSize int64) (*BatchStreamDecoder, error) {
+	decompressed, closer, err := newBatchDecompressor(r)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := &BatchStreamDecoder{
+		reader: bufio.NewReader(&batchSizeLimitedReader{R: decompressed, N: maxBatchSize, max: maxBatchSize}),
+		closer: closer,
+	}
+	if err = decoder.readHeader(); err != nil {
+		closer()
+		return nil, err
+	}
+	return decoder, nil
+}
+
+// newBatchDecompressor returns a reader of the decompressed batch, detecting gzip and zstd
+// batches by their magic number. Uncompressed batches are read as they are.
+//
+// Compression is transparent to the batch hashing rules: the batch merkle root is built from the
+// commitments of the decoded proofs, so it's the same for a batch and its compressed versions.
+// The data service checksum and ETag are instead of the stored object, so they are checked
+// against the downloaded bytes before decompressing them.
+func newBatchDecompressor(r io.Reader) (io.Reader, func(), error) {
+	reader := bufio.NewReader(r)
+
+	magic, err := reader.Peek(len(zstdMagic))
+	if err != nil && len(magic) == 0 {
+		return nil, nil, fmt.Errorf("error reading batch: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating gzip reader: %w", err)
+		}
+		return gzipReader, func() { _ = gzipReader.Close() }, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zstdReader, err := zstd.NewReader(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating zstd reader: %w", err)
+		}
+		return zstdReader, zstdReader.Close, nil
+	}
+	return reader, func() {}, nil
+}
+
+// decompressBatch returns the decompressed content of a batch, failing if it exceeds maxBatchSize.
+// Uncompressed batches are returned as they are.
+func decompressBatch(batch []byte, maxBatchSize int64) ([]byte, error) {
+	if !bytes.HasPrefix(batch, gzipMagic) && !bytes.HasPrefix(batch, zstdMagic) {
+		return batch, nil
+	}
+	decompressed, closer, err := newBatchDecompressor(bytes.NewReader(batch))
+	if err != nil {
+		return nil, err
+	}
+	defer closer()
+	decompressedBatch, err := io.ReadAll(&batchSizeLimitedReader{R: decompressed, N: maxBatchSize, max: maxBatchSize})
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing batch: %w", err)
+	}
+	return decompressedBatch, nil
+}
+
+// readHeader reads the start of the batch array and sets up the decoder of its elements.
+// A JSON batch always starts with '[', which is never the start of a valid CBOR batch.
+func (d *BatchStreamDecoder) readHeader() error {
+	first, err := d.reader.ReadByte()
+	if err != nil {
+		return fmt.Errorf("error reading batch: %w", err)
+	}
+
+	if first == jsonArrayStart {
+		if err = d.reader.UnreadByte(); err != nil {
+			return err
+		}
+		d.jsonDecoder = json.NewDecoder(d.reader)
+		if _, err = d.jsonDecoder.Token(); err != nil {
+			return fmt.Errorf("error decoding batch as JSON: %w", err)
+		}
+		return nil
+	}
+
+	if first>>5 != cborMajorTypeArray {
+		return fmt.Errorf("error decoding batch as CBOR: expected an array, found initial byte 0x%x", first)
+	}
+	d.remaining, err = d.readCborLength(first & 0x1f)
+	if err != nil {
+		return err
+	}
+
+	decMode, err := createDecoderMode()
+	if err != nil {
+		return fmt.Errorf("error creating CBOR decoder: %s", err)
+	}
+	d.cborDecoder = decMode.NewDecoder(d.reader)
+	return nil
+}
+
+func (d *BatchStreamDecoder) readCborLength(additionalInfo byte) (int64, error) {
+	if additionalInfo < 24 {
+		return int64(additionalInfo), nil
+	}
+	if additionalInfo == cborIndefiniteLength {
+		return -1, nil
+	}
+	if additionalInfo > 27 {
+		return 0, fmt.Errorf("error decoding batch as CBOR: invalid array length encoding %d", additionalInfo)
+	}
+
+	lengthBytes := make([]byte, 8)
+	size := 1 << (additionalInfo - 24)
+	if _, err := io.ReadFull(d.reader, lengthBytes[8-size:]); err != nil {
+		return 0, fmt.Errorf("error reading batch length: %w", err)
+	}
+	length := binary.BigEndian.Uint64(lengthBytes)
+	if length > 2147483647 {
+		return 0, fmt.Errorf("error decoding batch as CBOR: array of %d elements is too long", length)
+	}
+	return int64(length), nil
+}
+
+// Next returns the next proof of the batch, or io.EOF once all of them were read.
+func (d *BatchStreamDecoder) Next() (VerificationData, error) {
+	var verificationData VerificationData
+	if d.done {
+		return verificationData, io.EOF
+	}
+
+	var err error
+	if d.jsonDecoder != nil {
+		err = d.nextJson(&verificationData)
+	} else {
+		err = d.nextCbor(&verificationData)
+	}
+	if err == io.EOF {
+		d.done = true
+		return verificationData, io.EOF
+	}
+	if err != nil {
+		return verificationData, err
+	}
+
+	leaf, err := verificationDataCommitmentHash(verificationData)
+	if err != nil {
+		return verificationData, err
+	}
+	if d.expectedLeaves != nil {
+		i := len(d.leaves)
+		if i >= len(d.expectedLeaves) || d.expectedLeaves[i] != leaf {
+			return verificationData, fmt.Errorf("%w: proof %d", errBatchLeafMismatch, i)
+		}
+	}
+	d.leaves = append(d.leaves, leaf)
+	return verificationData, nil
+}
+
+// ExpectLeaves makes Next fail as soon as a proof doesn't match its leaf, instead of only
+// detecting a corrupted batch once the merkle root of the whole batch is computed.
+// The leaves must have been checked against the expected merkle root.
+func (d *BatchStreamDecoder) ExpectLeaves(leaves [][32]byte) {
+	d.expectedLeaves = leaves
+}
+
+func (d *BatchStreamDecoder) nextCbor(verificationData *VerificationData) error {
+	if d.remaining == 0 {
+		return io.EOF
+	}
+	if d.remaining < 0 {
+		// Indefinite length arrays end with a break byte, which isn't a valid data item
+		next, err := d.peekCbor()
+		if err != nil {
+			return err
+		}
+		if next == cborBreak {
+			return io.EOF
+		}
+	}
+
+	if err := d.cborDecoder.Decode(verificationData); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("error decoding batch as CBOR: %w", err)
+	}
+	if d.remaining > 0 {
+		d.remaining--
+	}
+	return nil
+}
+
+// peekCbor returns the next byte to be decoded. The CBOR decoder buffers the stream,
+// so the byte is read from its buffer when there is one.
+func (d *BatchStreamDecoder) peekCbor() (byte, error) {
+	buffered := make([]byte, 1)
+	n, _ := d.cborDecoder.Buffered().Read(buffered)
+	if n == 1 {
+		return buffered[0], nil
+	}
+	next, err := d.reader.Peek(1)
+	if err != nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return next[0], nil
+}
+
+func (d *BatchStreamDecoder) nextJson(verificationData *VerificationData) error {
+	if !d.jsonDecoder.More() {
+		if _, err := d.jsonDecoder.Token(); err != nil {
+			return fmt.Errorf("error decoding batch as JSON: %w", err)
+		}
+		return io.EOF
+	}
+
+	var element json.RawMessage
+	if err := d.jsonDecoder.Decode(&element); err != nil {
+		return fmt.Errorf("error decoding batch as JSON: %w", err)
+	}
+	decoder := codec.NewDecoderBytes(element, new(codec.JsonHandle))
+	if err := decoder.Decode(verificationData); err != nil {
+		return fmt.Errorf("error decoding batch as JSON: %w", err)
+	}
+	return nil
+}
+
+// MerkleRoot returns the merkle root of the proofs read so far. It must be called once
+// Next returned io.EOF to get the root of the whole batch.
+func (d *BatchStreamDecoder) MerkleRoot() ([32]byte, error) {
+	return batchMerkleRoot(d.leaves)
+}
+
+// Close releases the resources of the decompressor, if any.
+func (d *BatchStreamDecoder) Close() {
+	d.closer()
+}
+
+// verificationDataCommitmentHash returns the merkle tree leaf of a proof, the hash of its commitment
+// as computed by merkle.NewVerificationDataCommitment.
+func verificationDataCommitmentHash(verificationData VerificationData) ([32]byte, error) {
+	if !ethcommon.IsHexAddress(verificationData.ProofGeneratorAddr) {
+		return [32]byte{}, fmt.Errorf("invalid proof generator address %q", verificationData.ProofGeneratorAddr)
+	}
+	commitment := merkle.NewVerificationDataCommitment(
+		byte(verificationData.ProvingSystemId),
+		verificationData.Proof,
+		verificationData.PubInput,
+		verificationData.VerificationKey,
+		verificationData.VmProgramCode,
+		ethcommon.HexToAddress(verificationData.ProofGeneratorAddr),
+	)
+	return commitment.Hash(), nil
+}
+
+// batchMerkleRoot builds the batch merkle tree the same way the batcher does
+func batchMerkleRoot(leaves [][32]byte) ([32]byte, error) {
+	if len(leaves) == 0 {
+		return [32]byte{}, fmt.Errorf("batch is empty")
+	}
+	return merkle.Root(merkle.CurrentVersion, leaves)
+}
+
+// batchSizeLimitedReader fails once more than max bytes are read, instead of silently
+// ending the stream as io.LimitedReader does.
+type batchSizeLimitedReader struct {
+	R   io.Reader
+	N   int64
+	max int64
+}
+
+func (l *batchSizeLimitedReader) Read(p []byte) (int, error) {
+	if l.N <= 0 {
+		// The stream can end right at the limit, only fail if there is more data to read
+		var next [1]byte
+		n, err := l.R.Read(next[:])
+		if n == 0 {
+			return 0, err
+		}
+		return 0, fmt.Errorf("batch size exceeds max batch size %d", l.max)
+	}
+	if int64(len(p)) > l.N {
+		p = p[:l.N]
+	}
+	n, err := l.R.Read(p)
+	l.N -= int64(n)
+	return n, err
+}
@@ -0,0 +1,169 @@
+package operator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	batchFixturePath      = "../merkle_tree/lib/test_files/merkle_tree_batch.bin"
+	batchRootFixturePath  = "../merkle_tree/lib/test_files/merkle_root.bin"
+	testMaxBatchSize      = 1 << 20
+	fixtureBatchNumProofs = 35
+)
+
+func readBatchFixture(t *testing.T) ([]byte, [32]byte) {
+	batch, err := os.ReadFile(batchFixturePath)
+	if err != nil {
+		t.Fatalf("Error reading batch fixture: %v", err)
+	}
+	rootHex, err := os.ReadFile(batchRootFixturePath)
+	if err != nil {
+		t.Fatalf("Error reading merkle root fixture: %v", err)
+	}
+	var root [32]byte
+	if _, err = hex.Decode(root[:], bytes.TrimSpace(rootHex)); err != nil {
+		t.Fatalf("Error decoding merkle root fixture: %v", err)
+	}
+	return batch, root
+}
+
+func decodeBatchStream(t *testing.T, stream []byte, maxBatchSize int64) ([]VerificationData, [32]byte, error) {
+	decoder, err := NewBatchStreamDecoder(bytes.NewReader(stream), maxBatchSize)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	defer decoder.Close()
+
+	var batch []VerificationData
+	for {
+		verificationData, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, [32]byte{}, err
+		}
+		batch = append(batch, verificationData)
+	}
+	root, err := decoder.MerkleRoot()
+	return batch, root, err
+}
+
+func TestBatchStreamDecoderComputesMerkleRoot(t *testing.T) {
+	batch, expectedRoot := readBatchFixture(t)
+
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	_, _ = gzipWriter.Write(batch)
+	_ = gzipWriter.Close()
+
+	zstdEncoder, _ := zstd.NewWriter(nil)
+	zstdCompressed := zstdEncoder.EncodeAll(batch, nil)
+
+	streams := map[string][]byte{"plain": batch, "gzip": gzipped.Bytes(), "zstd": zstdCompressed}
+	for name, stream := range streams {
+		t.Run(name, func(t *testing.T) {
+			decoded, root, err := decodeBatchStream(t, stream, testMaxBatchSize)
+			if err != nil {
+				t.Fatalf("Unexpected error decoding batch: %v", err)
+			}
+			if len(decoded) != fixtureBatchNumProofs {
+				t.Errorf("Expected %d proofs, got %d", fixtureBatchNumProofs, len(decoded))
+			}
+			if root != expectedRoot {
+				t.Errorf("Expected merkle root %x, got %x", expectedRoot, root)
+			}
+		})
+	}
+}
+
+func TestBatchStreamDecoderMatchesFullDecoding(t *testing.T) {
+	batch, _ := readBatchFixture(t)
+
+	decoderMode, err := createDecoderMode()
+	if err != nil {
+		t.Fatalf("Error creating decoder: %v", err)
+	}
+	var expected []VerificationData
+	if err = decoderMode.Unmarshal(batch, &expected); err != nil {
+		t.Fatalf("Error decoding batch: %v", err)
+	}
+
+	decoded, _, err := decodeBatchStream(t, batch, testMaxBatchSize)
+	if err != nil {
+		t.Fatalf("Unexpected error decoding batch: %v", err)
+	}
+	for i := range expected {
+		if expected[i].ProvingSystemId != decoded[i].ProvingSystemId || !bytes.Equal(expected[i].Proof, decoded[i].Proof) ||
+			!bytes.Equal(expected[i].PubInput, decoded[i].PubInput) || expected[i].ProofGeneratorAddr != decoded[i].ProofGeneratorAddr {
+			t.Errorf("Proof %d differs from the one decoded from the full batch", i)
+		}
+	}
+}
+
+func TestBatchStreamDecoderRespectsMaxBatchSize(t *testing.T) {
+	batch, _ := readBatchFixture(t)
+
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	_, _ = gzipWriter.Write(batch)
+	_ = gzipWriter.Close()
+
+	// The compressed batch fits in the limit, but the decompressed one doesn't
+	maxBatchSize := int64(gzipped.Len() + 1)
+	if _, _, err := decodeBatchStream(t, gzipped.Bytes(), maxBatchSize); err == nil {
+		t.Errorf("Expected an error decoding a batch larger than the max batch size")
+	}
+
+	if _, _, err := decodeBatchStream(t, batch, int64(len(batch))); err != nil {
+		t.Errorf("Unexpected error decoding a batch of exactly the max batch size: %v", err)
+	}
+}
+
+func TestDecompressBatch(t *testing.T) {
+	batch, _ := readBatchFixture(t)
+
+	zstdEncoder, _ := zstd.NewWriter(nil)
+	zstdCompressed := zstdEncoder.EncodeAll(batch, nil)
+
+	for name, stream := range map[string][]byte{"plain": batch, "zstd": zstdCompressed} {
+		decompressed, err := decompressBatch(stream, testMaxBatchSize)
+		if err != nil {
+			t.Fatalf("Unexpected error decompressing %s batch: %v", name, err)
+		}
+		if !bytes.Equal(decompressed, batch) {
+			t.Errorf("Expected the decompressed %s batch to match the original one", name)
+		}
+	}
+
+	if _, err := decompressBatch(zstdCompressed, int64(len(batch)-1)); err == nil {
+		t.Errorf("Expected an error decompressing a batch larger than the max batch size")
+	}
+}
+
+func TestBatchStreamDecoderRejectsTruncatedBatch(t *testing.T) {
+	batch, _ := readBatchFixture(t)
+
+	if _, _, err := decodeBatchStream(t, batch[:len(batch)/2], testMaxBatchSize); err == nil {
+		t.Errorf("Expected an error decoding a truncated batch")
+	}
+}
+
+func TestBatchMerkleRootOfSingleLeaf(t *testing.T) {
+	leaf := [32]byte{1, 2, 3}
+	root, err := batchMerkleRoot([][32]byte{leaf})
+	if err != nil || root != leaf {
+		t.Errorf("Expected the root of a single leaf tree to be the leaf, got %x, %v", root, err)
+	}
+
+	if _, err = batchMerkleRoot(nil); err == nil {
+		t.Errorf("Expected an error for an empty batch")
+	}
+}
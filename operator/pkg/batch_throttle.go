@@ -0,0 +1,95 @@
+package operator
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// Max bytes read from a throttled download at once, so small rate limits still allow reads
+const maxThrottledReadSize = 1024 * 1024
+
+// BatchDownloadLimiter caps the bandwidth and number of connections used by batch downloads,
+// so they don't saturate links shared with the operator's other infrastructure, such as an
+// Ethereum node on the same host. The limits are shared by all the downloads in progress.
+//
+// A nil *BatchDownloadLimiter doesn't limit downloads.
+type BatchDownloadLimiter struct {
+	bandwidth   *rate.Limiter
+	connections chan struct{}
+}
+
+// NewBatchDownloadLimiter creates a limiter allowing bytesPerSecond of download bandwidth and
+// maxConnections connections at the same time. Limits that aren't positive are not enforced,
+// and nil is returned when none is.
+func NewBatchDownloadLimiter(bytesPerSecond int64, maxConnections int) *BatchDownloadLimiter {
+	if bytesPerSecond <= 0 && maxConnections <= 0 {
+		return nil
+	}
+
+	limiter := &BatchDownloadLimiter{}
+	if bytesPerSecond > 0 {
+		burst := bytesPerSecond
+		if burst > maxThrottledReadSize {
+			burst = maxThrottledReadSize
+		}
+		limiter.bandwidth = rate.NewLimiter(rate.Limit(bytesPerSecond), int(burst))
+	}
+	if maxConnections > 0 {
+		limiter.connections = make(chan struct{}, maxConnections)
+	}
+	return limiter
+}
+
+// acquire waits for a connection to be available. The returned function releases it.
+func (l *BatchDownloadLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil || l.connections == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.connections <- struct{}{}:
+		return func() { <-l.connections }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// limitBody throttles the reads of a response body, releasing its connection once it's closed.
+func (l *BatchDownloadLimiter) limitBody(ctx context.Context, body io.ReadCloser, release func()) io.ReadCloser {
+	if l == nil {
+		return body
+	}
+	return &throttledBody{ctx: ctx, body: body, bandwidth: l.bandwidth, release: release}
+}
+
+type throttledBody struct {
+	ctx       context.Context
+	body      io.ReadCloser
+	bandwidth *rate.Limiter
+	release   func()
+	closeOnce sync.Once
+}
+
+func (b *throttledBody) Read(p []byte) (int, error) {
+	if b.bandwidth == nil {
+		return b.body.Read(p)
+	}
+	if len(p) > b.bandwidth.Burst() {
+		p = p[:b.bandwidth.Burst()]
+	}
+	n, err := b.body.Read(p)
+	if n > 0 {
+		if waitErr := b.bandwidth.WaitN(b.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (b *throttledBody) Close() error {
+	err := b.body.Close()
+	b.closeOnce.Do(b.release)
+	return err
+}
@@ -0,0 +1,65 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBatchDownloadLimiterDisabledWithoutLimits(t *testing.T) {
+	if NewBatchDownloadLimiter(0, 0) != nil {
+		t.Errorf("Expected no limiter without limits")
+	}
+}
+
+func TestBatchDownloadLimiterCapsConnections(t *testing.T) {
+	limiter := NewBatchDownloadLimiter(0, 1)
+	release, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error acquiring connection: %v", err)
+	}
+	body := limiter.limitBody(context.Background(), io.NopCloser(bytes.NewReader(nil)), release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err = limiter.acquire(ctx); err == nil {
+		t.Fatalf("Expected to wait for a free connection")
+	}
+
+	// closing the body twice must only release its connection once
+	_ = body.Close()
+	_ = body.Close()
+	if _, err = limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("Unexpected error acquiring released connection: %v", err)
+	}
+	if len(limiter.connections) != 1 {
+		t.Errorf("Expected 1 connection in use, got %d", len(limiter.connections))
+	}
+}
+
+func TestBatchDownloadLimiterThrottlesBandwidth(t *testing.T) {
+	const bytesPerSecond = 10 * 1024
+	limiter := NewBatchDownloadLimiter(bytesPerSecond, 0)
+	release, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error acquiring connection: %v", err)
+	}
+	data := make([]byte, 2*bytesPerSecond)
+	body := limiter.limitBody(context.Background(), io.NopCloser(bytes.NewReader(data)), release)
+	defer body.Close()
+
+	// the first second of bandwidth is available right away, the rest takes about a second
+	start := time.Now()
+	read, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("Unexpected error reading body: %v", err)
+	}
+	if len(read) != len(data) {
+		t.Errorf("Expected %d bytes, got %d", len(data), len(read))
+	}
+	if elapsed := time.Since(start); elapsed < 800*time.Millisecond {
+		t.Errorf("Expected the read to be throttled, took %s", elapsed)
+	}
+}
@@ -0,0 +1,45 @@
+package operator
+
+import (
+	"context"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+// BlsSigner signs the messages the operator sends to the aggregator, such as task responses.
+type BlsSigner interface {
+	Sign(ctx context.Context, message [32]byte) (*bls.Signature, error)
+	PublicKeyG1() *bls.G1Point
+	PublicKeyG2() *bls.G2Point
+	Close() error
+}
+
+// NewBlsSigner returns a signer using the remote signer of the config if set, or the local BLS key otherwise.
+func NewBlsSigner(blsConfig *config.BlsConfig) (BlsSigner, error) {
+	if blsConfig.RemoteSigner != nil {
+		return NewRemoteBlsSigner(blsConfig.RemoteSigner)
+	}
+	return &LocalBlsSigner{keyPair: blsConfig.KeyPair}, nil
+}
+
+// LocalBlsSigner signs with a BLS key loaded in the operator.
+type LocalBlsSigner struct {
+	keyPair *bls.KeyPair
+}
+
+func (s *LocalBlsSigner) Sign(ctx context.Context, message [32]byte) (*bls.Signature, error) {
+	return s.keyPair.SignMessage(message), nil
+}
+
+func (s *LocalBlsSigner) PublicKeyG1() *bls.G1Point {
+	return s.keyPair.GetPubKeyG1()
+}
+
+func (s *LocalBlsSigner) PublicKeyG2() *bls.G2Point {
+	return s.keyPair.GetPubKeyG2()
+}
+
+func (s *LocalBlsSigner) Close() error {
+	return nil
+}
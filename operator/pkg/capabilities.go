@@ -0,0 +1,71 @@
+package operator
+
+import (
+	"context"
+
+	"github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// verifierVersions is the version of the verifier library used for each proving system.
+// It must be updated along with the verifier dependencies, since the aggregator can pin them.
+var verifierVersions = map[common.ProvingSystemId]string{
+	common.GnarkPlonkBls12_381: "gnark-v0.10.0",
+	common.GnarkPlonkBn254:     "gnark-v0.10.0",
+	common.Groth16Bn254:        "gnark-v0.10.0",
+	common.Groth16Bls12_381:    "gnark-v0.10.0",
+	common.SP1:                 "sp1-v3.0.0",
+	common.Risc0:               "risc0-v1.1.2",
+	common.Plonky2:             "plonky2-0.2.2",
+	common.Cairo:               "swiftness-v0.0.9",
+	common.Nova:                "nova-snark-0.37.0",
+	common.Jolt:                "jolt-0369981",
+	common.Miden:               "miden-verifier-0.10.5",
+	common.Binius:              "binius-a1d3ec4",
+	common.Halo2:               "halo2-v0.4.0",
+	common.Boojum:              "zkevm_test_harness-v1.5.0",
+	common.Valida:              "valida-v0.5.0-alpha",
+	common.Kimchi:              "kimchi-5bdeab3",
+	common.WasmPlugin:          "wasm-abi-v1",
+}
+
+// EnabledProvingSystems returns the verifier version of every proving system this operator verifies,
+// given its disabled and experimental proving systems configuration.
+func EnabledProvingSystems(disabledProvingSystems map[common.ProvingSystemId]bool, experimentalProvingSystems map[common.ProvingSystemId]bool) map[string]string {
+	enabled := make(map[string]string)
+	for provingSystem, version := range verifierVersions {
+		if disabledProvingSystems[provingSystem] {
+			continue
+		}
+		if IsExperimentalProvingSystem(provingSystem) && !experimentalProvingSystems[provingSystem] {
+			continue
+		}
+		enabled[provingSystem.String()] = version
+	}
+	return enabled
+}
+
+// Capabilities returns the signed capabilities this operator advertises to the aggregator.
+func (o *Operator) Capabilities() (*types.OperatorCapabilities, error) {
+	capabilities := &types.OperatorCapabilities{
+		OperatorId:       o.OperatorId,
+		VerifierVersions: o.enabledVerifierVersions(),
+	}
+	signature, err := o.blsSigner.Sign(context.Background(), capabilities.Digest())
+	if err != nil {
+		return nil, err
+	}
+	capabilities.BlsSignature = *signature
+	return capabilities, nil
+}
+
+// enabledVerifierVersions returns the proving systems this operator verifies and their verifier versions.
+func (o *Operator) enabledVerifierVersions() map[string]string {
+	verifierVersions := EnabledProvingSystems(o.disabledProvingSystems, o.experimentalProvingSystems)
+	// WasmPlugin proofs can only be verified by operators that loaded plugins
+	if o.wasmPlugins.Len() == 0 {
+		wasmPlugin := common.WasmPlugin
+		delete(verifierVersions, wasmPlugin.String())
+	}
+	return verifierVersions
+}
@@ -0,0 +1,36 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+func TestEveryProvingSystemHasVerifierVersion(t *testing.T) {
+	for provingSystem := common.ProvingSystemId(0); ; provingSystem++ {
+		if _, err := common.ProvingSystemIdToString(provingSystem); err != nil {
+			break
+		}
+		if verifierVersions[provingSystem] == "" {
+			t.Errorf("Proving system %s has no verifier version", provingSystem.String())
+		}
+	}
+}
+
+func TestEnabledProvingSystems(t *testing.T) {
+	enabled := EnabledProvingSystems(map[common.ProvingSystemId]bool{common.SP1: true}, nil)
+	if _, ok := enabled["SP1"]; ok {
+		t.Errorf("Disabled proving system should not be advertised")
+	}
+	if _, ok := enabled["Binius"]; ok {
+		t.Errorf("Experimental proving system should not be advertised unless enabled")
+	}
+	if enabled["Risc0"] != verifierVersions[common.Risc0] {
+		t.Errorf("Expected Risc0 to be advertised with version %s, got %s", verifierVersions[common.Risc0], enabled["Risc0"])
+	}
+
+	enabled = EnabledProvingSystems(nil, map[common.ProvingSystemId]bool{common.Binius: true})
+	if _, ok := enabled["Binius"]; !ok {
+		t.Errorf("Enabled experimental proving system should be advertised")
+	}
+}
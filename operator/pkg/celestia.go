@@ -0,0 +1,161 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const celestiaScheme = "celestia://"
+
+const (
+	// celestiaNamespaceSize is the size of a namespace, its version followed by its id
+	celestiaNamespaceSize = 29
+	// celestiaNamespaceV0IdSize is the size of the user specified part of version 0 namespace ids,
+	// which are left padded with zeros
+	celestiaNamespaceV0IdSize = 10
+	celestiaCommitmentSize    = 32
+)
+
+// celestiaBlobPointer references a blob posted to Celestia, with a batch data pointer
+// of the form celestia://<height>/<namespace>/<commitment>, the namespace and commitment hex encoded.
+type celestiaBlobPointer struct {
+	Height     uint64
+	Namespace  []byte
+	Commitment []byte
+}
+
+func parseCelestiaBlobPointer(batchDataPointer string) (celestiaBlobPointer, error) {
+	var pointer celestiaBlobPointer
+	parts := strings.Split(strings.TrimPrefix(batchDataPointer, celestiaScheme), "/")
+	if !strings.HasPrefix(batchDataPointer, celestiaScheme) || len(parts) != 3 {
+		return pointer, fmt.Errorf("invalid Celestia batch pointer %s, expected celestia://<height>/<namespace>/<commitment>", batchDataPointer)
+	}
+
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || height == 0 {
+		return pointer, fmt.Errorf("invalid Celestia block height %s", parts[0])
+	}
+
+	namespace, err := hex.DecodeString(strings.TrimPrefix(parts[1], "0x"))
+	if err != nil {
+		return pointer, fmt.Errorf("invalid Celestia namespace %s: %w", parts[1], err)
+	}
+	if len(namespace) == celestiaNamespaceV0IdSize {
+		namespace = append(make([]byte, celestiaNamespaceSize-celestiaNamespaceV0IdSize), namespace...)
+	}
+	if len(namespace) != celestiaNamespaceSize {
+		return pointer, fmt.Errorf("invalid Celestia namespace size %d", len(namespace))
+	}
+
+	commitment, err := hex.DecodeString(strings.TrimPrefix(parts[2], "0x"))
+	if err != nil || len(commitment) != celestiaCommitmentSize {
+		return pointer, fmt.Errorf("invalid Celestia blob commitment %s", parts[2])
+	}
+
+	return celestiaBlobPointer{Height: height, Namespace: namespace, Commitment: commitment}, nil
+}
+
+// celestiaBlob is a blob as returned by the Celestia node API. Binary fields are base64 encoded.
+type celestiaBlob struct {
+	Namespace    []byte `json:"namespace"`
+	Data         []byte `json:"data"`
+	ShareVersion uint32 `json:"share_version"`
+	Commitment   []byte `json:"commitment"`
+}
+
+// CelestiaClient retrieves batches posted to Celestia through the API of a Celestia node.
+// The node should be a light node, which samples the data availability of every block,
+// so the operator doesn't rely on a third party to know the batch was published.
+type CelestiaClient struct {
+	rpcUrl    string
+	authToken string
+	client    *http.Client
+}
+
+func NewCelestiaClient(rpcUrl string, authToken string) *CelestiaClient {
+	return &CelestiaClient{rpcUrl: rpcUrl, authToken: authToken, client: http.DefaultClient}
+}
+
+// GetBlob retrieves the blob and verifies it was included in the block at the pointer height,
+// checking the inclusion proof of its shares against the block data root.
+func (c *CelestiaClient) GetBlob(ctx context.Context, pointer celestiaBlobPointer) ([]byte, error) {
+	var blob celestiaBlob
+	if err := c.call(ctx, "blob.Get", &blob, pointer.Height, pointer.Namespace, pointer.Commitment); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(blob.Namespace, pointer.Namespace) || !bytes.Equal(blob.Commitment, pointer.Commitment) {
+		return nil, fmt.Errorf("Celestia node returned a blob with a different namespace or commitment")
+	}
+
+	var proof json.RawMessage
+	if err := c.call(ctx, "blob.GetProof", &proof, pointer.Height, pointer.Namespace, pointer.Commitment); err != nil {
+		return nil, err
+	}
+	var included bool
+	if err := c.call(ctx, "blob.Included", &included, pointer.Height, pointer.Namespace, proof, pointer.Commitment); err != nil {
+		return nil, err
+	}
+	if !included {
+		return nil, fmt.Errorf("blob %x is not included in Celestia block %d", pointer.Commitment, pointer.Height)
+	}
+
+	return blob.Data, nil
+}
+
+type celestiaRpcRequest struct {
+	JsonRpc string        `json:"jsonrpc"`
+	Id      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type celestiaRpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *CelestiaClient) call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	body, err := json.Marshal(celestiaRpcRequest{JsonRpc: "2.0", Id: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.rpcUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling Celestia node %s: %w", method, err)
+	}
+	defer closeBody(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error calling Celestia node %s: %s", method, resp.Status)
+	}
+
+	var rpcResponse celestiaRpcResponse
+	if err = json.NewDecoder(resp.Body).Decode(&rpcResponse); err != nil {
+		return fmt.Errorf("error decoding Celestia node %s response: %w", method, err)
+	}
+	if rpcResponse.Error != nil {
+		return fmt.Errorf("Celestia node %s failed: %s", method, rpcResponse.Error.Message)
+	}
+	if err = json.Unmarshal(rpcResponse.Result, result); err != nil {
+		return fmt.Errorf("error decoding Celestia node %s result: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testCelestiaPointer = "celestia://1234/00000000000000000000000000000000000000000000616c69676e6564/" +
+	"0102030405060708091011121314151617181920212223242526272829303132"
+
+func TestParseCelestiaBlobPointer(t *testing.T) {
+	pointer, err := parseCelestiaBlobPointer(testCelestiaPointer)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing pointer: %v", err)
+	}
+	if pointer.Height != 1234 || len(pointer.Namespace) != celestiaNamespaceSize || pointer.Commitment[0] != 1 {
+		t.Errorf("Unexpected pointer %+v", pointer)
+	}
+
+	// Version 0 namespaces can be given by their id only
+	shortPointer, err := parseCelestiaBlobPointer("celestia://1234/000000616c69676e6564/" + strings.Repeat("01", celestiaCommitmentSize))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing pointer: %v", err)
+	}
+	if !bytes.Equal(shortPointer.Namespace, pointer.Namespace) {
+		t.Errorf("Expected namespace %x, got %x", pointer.Namespace, shortPointer.Namespace)
+	}
+
+	for _, invalid := range []string{
+		"celestia://1234/616c69676e6564",
+		"celestia://0/000000616c69676e6564/" + strings.Repeat("01", celestiaCommitmentSize),
+		"celestia://1234/00616c69676e6564/" + strings.Repeat("01", celestiaCommitmentSize),
+		"celestia://1234/000000616c69676e6564/0102",
+	} {
+		if _, err = parseCelestiaBlobPointer(invalid); err == nil {
+			t.Errorf("Expected an error parsing %s", invalid)
+		}
+	}
+}
+
+// newCelestiaNodeServer mocks the blob API of a Celestia node
+func newCelestiaNodeServer(t *testing.T, blob celestiaBlob, included bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var request celestiaRpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Invalid request: %v", err)
+		}
+
+		var result interface{}
+		switch request.Method {
+		case "blob.Get":
+			result = blob
+		case "blob.GetProof":
+			result = []map[string]interface{}{{"start": 0, "end": 1, "nodes": []string{}}}
+		case "blob.Included":
+			if len(request.Params) != 4 {
+				t.Errorf("Expected blob.Included to be called with the proof")
+			}
+			result = included
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.Id, "result": result})
+	}))
+}
+
+func TestCelestiaClientGetBlob(t *testing.T) {
+	pointer, _ := parseCelestiaBlobPointer(testCelestiaPointer)
+	blob := celestiaBlob{Namespace: pointer.Namespace, Data: []byte("batch"), Commitment: pointer.Commitment}
+
+	server := newCelestiaNodeServer(t, blob, true)
+	defer server.Close()
+	data, err := NewCelestiaClient(server.URL, "token").GetBlob(context.Background(), pointer)
+	if err != nil {
+		t.Fatalf("Unexpected error getting blob: %v", err)
+	}
+	if string(data) != "batch" {
+		t.Errorf("Unexpected blob data %s", data)
+	}
+
+	notIncludedServer := newCelestiaNodeServer(t, blob, false)
+	defer notIncludedServer.Close()
+	if _, err = NewCelestiaClient(notIncludedServer.URL, "token").GetBlob(context.Background(), pointer); err == nil {
+		t.Errorf("Expected an error for a blob not included in the block")
+	}
+
+	blob.Commitment = bytes.Repeat([]byte{9}, celestiaCommitmentSize)
+	otherBlobServer := newCelestiaNodeServer(t, blob, true)
+	defer otherBlobServer.Close()
+	if _, err = NewCelestiaClient(otherBlobServer.URL, "token").GetBlob(context.Background(), pointer); err == nil {
+		t.Errorf("Expected an error for a blob with a different commitment")
+	}
+}
@@ -0,0 +1,65 @@
+package operator
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const eigenDAScheme = "eigenda://"
+
+// eigenDARequestTimeout bounds a single blob retrieval, which the proxy serves from the EigenDA operators
+const eigenDARequestTimeout = 30 * time.Second
+
+// parseEigenDABlobPointer returns the certificate of a blob dispersed to EigenDA, from a batch
+// data pointer of the form eigenda://<certificate>, the certificate hex encoded.
+func parseEigenDABlobPointer(batchDataPointer string) ([]byte, error) {
+	encodedCertificate, found := strings.CutPrefix(batchDataPointer, eigenDAScheme)
+	if !found {
+		return nil, fmt.Errorf("invalid EigenDA batch pointer %s, expected eigenda://<certificate>", batchDataPointer)
+	}
+	certificate, err := hex.DecodeString(strings.TrimPrefix(encodedCertificate, "0x"))
+	if err != nil || len(certificate) == 0 {
+		return nil, fmt.Errorf("invalid EigenDA blob certificate %s", encodedCertificate)
+	}
+	return certificate, nil
+}
+
+// EigenDAClient retrieves batches dispersed to EigenDA through an EigenDA proxy.
+// The proxy must run with certificate verification enabled: it checks the blob certificate
+// against the EigenDA service manager on Ethereum and the blob data against its KZG commitment,
+// so a blob it returns is the one the certificate attests was made available.
+type EigenDAClient struct {
+	proxyUrl string
+	client   *http.Client
+}
+
+func NewEigenDAClient(proxyUrl string) *EigenDAClient {
+	return &EigenDAClient{proxyUrl: strings.TrimSuffix(proxyUrl, "/"), client: &http.Client{Timeout: eigenDARequestTimeout}}
+}
+
+// GetBlob retrieves the blob of the given certificate, failing if it's larger than maxSize.
+func (c *EigenDAClient) GetBlob(ctx context.Context, certificate []byte, maxSize int64) ([]byte, error) {
+	url := fmt.Sprintf("%s/get/0x%s?commitment_mode=simple", c.proxyUrl, hex.EncodeToString(certificate))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blob from EigenDA proxy: %w", err)
+	}
+	defer closeBody(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		// The proxy explains why a certificate is rejected in the body
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("error getting blob from EigenDA proxy: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return io.ReadAll(&batchSizeLimitedReader{R: resp.Body, N: maxSize, max: maxSize})
+}
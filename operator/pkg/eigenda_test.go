@@ -0,0 +1,57 @@
+package operator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseEigenDABlobPointer(t *testing.T) {
+	certificate, err := parseEigenDABlobPointer("eigenda://0x00f901")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing pointer: %v", err)
+	}
+	if len(certificate) != 3 || certificate[1] != 0xf9 {
+		t.Errorf("Unexpected certificate %x", certificate)
+	}
+
+	for _, invalid := range []string{"eigenda://", "eigenda://zz", "https://eigenda/0x00"} {
+		if _, err = parseEigenDABlobPointer(invalid); err == nil {
+			t.Errorf("Expected an error parsing %s", invalid)
+		}
+	}
+}
+
+func TestEigenDAClientGetBlob(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("commitment_mode") != "simple" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/get/0x00f901":
+			_, _ = w.Write([]byte("batch"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("failed to verify certificate"))
+		}
+	}))
+	defer server.Close()
+	client := NewEigenDAClient(server.URL + "/")
+
+	blob, err := client.GetBlob(context.Background(), []byte{0x00, 0xf9, 0x01}, 1024)
+	if err != nil {
+		t.Fatalf("Unexpected error getting blob: %v", err)
+	}
+	if string(blob) != "batch" {
+		t.Errorf("Unexpected blob %s", blob)
+	}
+
+	if _, err = client.GetBlob(context.Background(), []byte{0x00, 0xf9, 0x01}, 2); err == nil {
+		t.Errorf("Expected an error for a blob larger than the max size")
+	}
+	if _, err = client.GetBlob(context.Background(), []byte{0x01}, 1024); err == nil {
+		t.Errorf("Expected an error for a rejected certificate")
+	}
+}
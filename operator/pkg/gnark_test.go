@@ -0,0 +1,63 @@
+package operator
+
+import (
+	"os"
+	"testing"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+const groth16Bls12_381Dir = "../../scripts/test_files/gnark_groth16_bls12_381_script/"
+
+const groth16Bn254Dir = "../../scripts/test_files/gnark_groth16_bn254_script/"
+
+func readGroth16TestFiles(t *testing.T, dir string) ([]byte, []byte, []byte) {
+	proof, err := os.ReadFile(dir + "groth16.proof")
+	if err != nil {
+		t.Fatalf("could not open proof file: %s", err)
+	}
+	pubInput, err := os.ReadFile(dir + "groth16.pub")
+	if err != nil {
+		t.Fatalf("could not open public input file: %s", err)
+	}
+	vk, err := os.ReadFile(dir + "groth16.vk")
+	if err != nil {
+		t.Fatalf("could not open verification key file: %s", err)
+	}
+	return proof, pubInput, vk
+}
+
+func newTestOperator(t *testing.T) *Operator {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	return &Operator{Logger: logger}
+}
+
+func TestGroth16Bls12_381ProofVerifies(t *testing.T) {
+	operator := newTestOperator(t)
+	proof, pubInput, vk := readGroth16TestFiles(t, groth16Bls12_381Dir)
+
+	if !operator.verifyGroth16ProofBLS12_381(proof, pubInput, vk) {
+		t.Errorf("proof did not verify")
+	}
+}
+
+func TestGroth16Bls12_381RejectsTrailingBytes(t *testing.T) {
+	operator := newTestOperator(t)
+	proof, pubInput, vk := readGroth16TestFiles(t, groth16Bls12_381Dir)
+
+	if operator.verifyGroth16ProofBLS12_381(append(proof, 0), pubInput, vk) {
+		t.Errorf("proof with trailing bytes should not verify")
+	}
+}
+
+func TestGroth16Bls12_381RejectsBn254Proof(t *testing.T) {
+	operator := newTestOperator(t)
+	proof, pubInput, vk := readGroth16TestFiles(t, groth16Bn254Dir)
+
+	if operator.verifyGroth16ProofBLS12_381(proof, pubInput, vk) {
+		t.Errorf("BN254 proof should not verify as BLS12-381")
+	}
+}
@@ -0,0 +1,63 @@
+package operator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/yetanotherco/aligned_layer/operator/gpu"
+)
+
+const (
+	verificationPathCpu = "cpu"
+	verificationPathGpu = "gpu"
+)
+
+// verifyGroth16Bn254WithGpu verifies a BN254 Groth16 proof on the GPU, falling back to the CPU verifier
+// if the GPU can't verify it. With gpu_verification_benchmark enabled, the proof is verified on both paths
+// so their durations can be compared, and the CPU result is used if they differ.
+func (o *Operator) verifyGroth16Bn254WithGpu(proof groth16.Proof, verificationKey groth16.VerifyingKey, pubInput witness.Witness) bool {
+	const provingSystem = "Groth16Bn254"
+
+	start := time.Now()
+	verified, err := verifyGroth16Bn254OnGpu(proof, verificationKey, pubInput)
+	if err != nil {
+		if !errors.Is(err, gpu.ErrUnsupported) {
+			o.Logger.Warnf("Could not verify Groth16 proof on the GPU, verifying on the CPU: %v", err)
+		}
+		o.metrics.IncOperatorGpuFallbacks(provingSystem)
+		return o.verifyGroth16OnCpu(provingSystem, proof, verificationKey, pubInput)
+	}
+
+	o.metrics.ObserveOperatorVerificationPathDuration(provingSystem, verificationPathGpu, time.Since(start))
+
+	if o.Config.Operator.GpuVerificationBenchmark {
+		cpuVerified := o.verifyGroth16OnCpu(provingSystem, proof, verificationKey, pubInput)
+		if cpuVerified != verified {
+			o.Logger.Errorf("GPU and CPU Groth16 verification results differ, GPU: %t, CPU: %t", verified, cpuVerified)
+			return cpuVerified
+		}
+	}
+
+	return verified
+}
+
+func verifyGroth16Bn254OnGpu(proof groth16.Proof, verificationKey groth16.VerifyingKey, pubInput witness.Witness) (bool, error) {
+	bn254Proof, isBn254Proof := proof.(*groth16_bn254.Proof)
+	bn254VerificationKey, isBn254VerificationKey := verificationKey.(*groth16_bn254.VerifyingKey)
+	publicWitness, isBn254Witness := pubInput.Vector().(fr.Vector)
+	if !isBn254Proof || !isBn254VerificationKey || !isBn254Witness {
+		return false, gpu.ErrUnsupported
+	}
+	return gpu.VerifyGroth16Bn254(bn254Proof, bn254VerificationKey, publicWitness)
+}
+
+func (o *Operator) verifyGroth16OnCpu(provingSystem string, proof groth16.Proof, verificationKey groth16.VerifyingKey, pubInput witness.Witness) bool {
+	start := time.Now()
+	err := groth16.Verify(proof, verificationKey, pubInput)
+	o.metrics.ObserveOperatorVerificationPathDuration(provingSystem, verificationPathCpu, time.Since(start))
+	return err == nil
+}
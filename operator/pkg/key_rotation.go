@@ -0,0 +1,68 @@
+package operator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// previousIdentity is the operator identity being retired while rotating keys. Until the overlap
+// window ends, task responses are also signed and sent with it, so the operator keeps signing
+// with the stake of the previous identity until the new one has been delegated to.
+type previousIdentity struct {
+	address      ethcommon.Address
+	operatorId   eigentypes.OperatorId
+	signer       BlsSigner
+	overlapUntil time.Time
+	now          func() time.Time
+
+	endedOnce sync.Once
+}
+
+func newPreviousIdentity(keyRotation *config.KeyRotationConfig) *previousIdentity {
+	if keyRotation == nil {
+		return nil
+	}
+	return &previousIdentity{
+		address:      keyRotation.PreviousOperatorAddress,
+		operatorId:   eigentypes.OperatorIdFromKeyPair(keyRotation.PreviousBlsKeyPair),
+		signer:       &LocalBlsSigner{keyPair: keyRotation.PreviousBlsKeyPair},
+		overlapUntil: keyRotation.OverlapUntil,
+		now:          time.Now,
+	}
+}
+
+// active returns whether the previous identity is still signing task responses.
+func (p *previousIdentity) active() bool {
+	return p != nil && p.now().Before(p.overlapUntil)
+}
+
+// sendPreviousIdentityResponse signs and sends the task response again with the previous identity,
+// if the key rotation overlap window hasn't ended.
+func (o *Operator) sendPreviousIdentityResponse(signedTaskResponse types.SignedTaskResponse) {
+	if o.previousIdentity == nil {
+		return
+	}
+	if !o.previousIdentity.active() {
+		o.previousIdentity.endedOnce.Do(func() {
+			o.Logger.Warnf("Key rotation overlap window ended at %s, no longer signing with operator %s. Retire it with the rotate-keys retire command",
+				o.previousIdentity.overlapUntil, o.previousIdentity.address)
+		})
+		return
+	}
+
+	signature, err := o.previousIdentity.signer.Sign(context.Background(), signedTaskResponse.BatchIdentifierHash)
+	if err != nil {
+		o.Logger.Errorf("Could not sign task response of batch %x with the previous operator key: %v", signedTaskResponse.BatchMerkleRoot, err)
+		return
+	}
+	signedTaskResponse.BlsSignature = *signature
+	signedTaskResponse.OperatorId = o.previousIdentity.operatorId
+	o.Logger.Infof("Sending task response of batch %x signed with the previous operator key", signedTaskResponse.BatchMerkleRoot)
+	o.aggRpcClient.SendSignedTaskResponseToAggregator(&signedTaskResponse)
+}
@@ -0,0 +1,61 @@
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+func TestPreviousIdentitySignsUntilOverlapEnds(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	previousKeyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Error generating BLS keys: %v", err)
+	}
+	aggregator := &fakeAggregator{}
+	server := startFakeAggregatorServer(t, "127.0.0.1:0", aggregator)
+	defer server.stop()
+	client, err := NewAggregatorRpcClient([]string{server.listener.Addr().String()}, logger)
+	if err != nil {
+		t.Fatalf("Error connecting to aggregator: %v", err)
+	}
+
+	now := time.Now()
+	previous := newPreviousIdentity(&config.KeyRotationConfig{
+		PreviousBlsKeyPair: previousKeyPair,
+		OverlapUntil:       now.Add(time.Hour),
+	})
+	previous.now = func() time.Time { return now }
+	operator := &Operator{Logger: logger, aggRpcClient: client, previousIdentity: previous}
+
+	response := types.SignedTaskResponse{BatchIdentifierHash: [32]byte{1}, BatchMerkleRoot: [32]byte{2}, OperatorId: eigentypes.OperatorId{3}}
+	operator.sendPreviousIdentityResponse(response)
+
+	aggregator.mu.Lock()
+	signers := append([]eigentypes.OperatorId(nil), aggregator.signers...)
+	aggregator.mu.Unlock()
+	if len(signers) != 1 || signers[0] != eigentypes.OperatorIdFromKeyPair(previousKeyPair) {
+		t.Fatalf("Expected the response to be sent with the previous operator id, got %v", signers)
+	}
+
+	// once the overlap window ends, only the new identity signs
+	now = now.Add(time.Hour)
+	operator.sendPreviousIdentityResponse(response)
+	if responses, _ := aggregator.received(); len(responses) != 1 {
+		t.Errorf("Expected no responses from the previous identity after the overlap window, got %d", len(responses))
+	}
+}
+
+func TestNoPreviousIdentityWithoutKeyRotation(t *testing.T) {
+	if previous := newPreviousIdentity(nil); previous.active() {
+		t.Errorf("Expected no previous identity without key rotation")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
@@ -16,6 +17,16 @@ import (
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/operator/binius"
+	"github.com/yetanotherco/aligned_layer/operator/boojum"
+	"github.com/yetanotherco/aligned_layer/operator/cairo"
+	"github.com/yetanotherco/aligned_layer/operator/gpu"
+	"github.com/yetanotherco/aligned_layer/operator/halo2"
+	"github.com/yetanotherco/aligned_layer/operator/jolt"
+	"github.com/yetanotherco/aligned_layer/operator/kimchi"
+	"github.com/yetanotherco/aligned_layer/operator/miden"
+	"github.com/yetanotherco/aligned_layer/operator/nova"
+	"github.com/yetanotherco/aligned_layer/operator/plonky2"
 	"github.com/yetanotherco/aligned_layer/operator/risc_zero"
 	"github.com/yetanotherco/aligned_layer/operator/risc_zero_old"
 	"golang.org/x/crypto/sha3"
@@ -25,6 +36,8 @@ import (
 
 	"github.com/yetanotherco/aligned_layer/operator/sp1"
 	"github.com/yetanotherco/aligned_layer/operator/sp1_old"
+	"github.com/yetanotherco/aligned_layer/operator/valida"
+	"github.com/yetanotherco/aligned_layer/operator/wasm_plugin"
 
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
@@ -40,25 +53,47 @@ import (
 	"github.com/yetanotherco/aligned_layer/core/types"
 
 	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
 )
 
 type Operator struct {
-	Config                    config.OperatorConfig
-	Address                   ethcommon.Address
-	Socket                    string
-	Timeout                   time.Duration
-	KeyPair                   *bls.KeyPair
-	OperatorId                eigentypes.OperatorId
-	avsSubscriber             chainio.AvsSubscriber
-	avsReader                 chainio.AvsReader
-	NewTaskCreatedChanV2      chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2
-	NewTaskCreatedChanV3      chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3
-	Logger                    logging.Logger
-	aggRpcClient              AggregatorRpcClient
-	metricsReg                *prometheus.Registry
-	metrics                   *metrics.Metrics
-	lastProcessedBatch        OperatorLastProcessedBatch
-	lastProcessedBatchLogFile string
+	Config                     config.OperatorConfig
+	Version                    string
+	Address                    ethcommon.Address
+	Socket                     string
+	Timeout                    time.Duration
+	KeyPair                    *bls.KeyPair
+	blsSigner                  BlsSigner
+	previousIdentity           *previousIdentity
+	OperatorId                 eigentypes.OperatorId
+	avsSubscriber              chainio.AvsSubscriber
+	avsReader                  chainio.AvsReader
+	NewTaskCreatedChanV2       chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2
+	NewTaskCreatedChanV3       chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3
+	Logger                     logging.Logger
+	aggRpcClient               *AggregatorRpcClient
+	metricsReg                 *prometheus.Registry
+	metrics                    *metrics.Metrics
+	lastProcessedBatch         OperatorLastProcessedBatch
+	lastProcessedBatchLogFile  string
+	verificationPool           *VerificationPool
+	verificationCache          *VerificationCache
+	verificationLimiter        *VerificationLimiter
+	verificationSandbox        *VerificationSandbox
+	disabledProvingSystems     map[common.ProvingSystemId]bool
+	experimentalProvingSystems map[common.ProvingSystemId]bool
+	gpuVerification            bool
+	wasmPlugins                *wasm_plugin.Plugins
+	celestiaClient             *CelestiaClient
+	eigenDAClient              *EigenDAClient
+	batchCache                 *BatchCache
+	batchGossip                *BatchGossip
+	downloadLimiter            *BatchDownloadLimiter
+	batchSourceHealth          *BatchSourceHealth
+	inFlightBatches            sync.WaitGroup
+	status                     OperatorStatus
+	statusServer               *http.Server
+	telemetry                  *Telemetry
 	//Socket  string
 	//Timeout time.Duration
 }
@@ -86,19 +121,42 @@ func NewOperatorFromConfig(configuration config.OperatorConfig) (*Operator, erro
 		log.Fatal("Operator not registered")
 	}
 
-	avsSubscriber, err := chainio.NewAvsSubscriberFromConfig(configuration.BaseConfig)
+	previousIdentity := newPreviousIdentity(configuration.KeyRotation)
+	if previousIdentity.active() {
+		registered, err = avsReader.IsOperatorRegistered(previousIdentity.address)
+		if err != nil {
+			log.Fatalf("Could not check if previous operator is registered")
+		}
+		if !registered {
+			logger.Warnf("Previous operator %s is not registered, its key won't be used to sign", previousIdentity.address)
+			previousIdentity = nil
+		} else {
+			logger.Infof("Rotating operator keys, signing with previous operator %s too until %s", previousIdentity.address, previousIdentity.overlapUntil)
+		}
+	}
+
+	// Metrics
+	reg := prometheus.NewRegistry()
+	operatorMetrics := metrics.NewMetricsWithConfig(configuration.Operator.MetricsIpPortAddress, reg, logger, configuration.MetricsConfig)
+
+	avsSubscriber, err := chainio.NewAvsSubscriberFromConfig(configuration.BaseConfig, operatorMetrics)
 	if err != nil {
 		log.Fatalf("Could not create AVS subscriber")
 	}
 	newTaskCreatedChanV2 := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2)
 	newTaskCreatedChanV3 := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3)
 
-	rpcClient, err := NewAggregatorRpcClient(configuration.Operator.AggregatorServerIpPortAddress, logger)
+	aggregatorIpPortAddrs := append([]string{configuration.Operator.AggregatorServerIpPortAddress}, configuration.Operator.AggregatorServerFallbackIpPortAddresses...)
+	rpcClient, err := NewAggregatorRpcClient(aggregatorIpPortAddrs, logger)
 	if err != nil {
 		return nil, fmt.Errorf("could not create RPC client: %s. Is aggregator running?", err)
 	}
 
-	operatorId := eigentypes.OperatorIdFromKeyPair(configuration.BlsConfig.KeyPair)
+	blsSigner, err := NewBlsSigner(configuration.BlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create BLS signer: %w", err)
+	}
+	operatorId := eigentypes.OperatorIdFromG1Pubkey(blsSigner.PublicKeyG1())
 	address := configuration.Operator.Address
 	lastProcessedBatchLogFile := configuration.Operator.LastProcessedBatchFilePath
 
@@ -106,23 +164,112 @@ func NewOperatorFromConfig(configuration config.OperatorConfig) (*Operator, erro
 		logger.Fatalf("Config file field: `last_processed_batch_filepath` not provided.")
 	}
 
-	// Metrics
-	reg := prometheus.NewRegistry()
-	operatorMetrics := metrics.NewMetrics(configuration.Operator.MetricsIpPortAddress, reg, logger)
+	verificationPool, err := NewVerificationPool(configuration.Operator.MaxVerificationWorkers, configuration.Operator.ProvingSystemWorkerLimits)
+	if err != nil {
+		logger.Fatalf("Invalid verification workers configuration: %v", err)
+	}
+
+	verificationLimiter, err := NewVerificationLimiter(configuration.Operator.VerificationLimits, configuration.Operator.SandboxVerifiers)
+	if err != nil {
+		logger.Fatalf("Invalid verification limits configuration: %v", err)
+	}
+
+	var verificationSandbox *VerificationSandbox
+	if configuration.Operator.SandboxVerifiers {
+		verificationSandbox, err = NewVerificationSandbox(verificationLimiter, logger)
+		if err != nil {
+			logger.Fatalf("Could not create verification sandbox: %v", err)
+		}
+	}
+
+	disabledProvingSystems, err := ProvingSystemsFromStrings(configuration.Operator.DisabledProvingSystems)
+	if err != nil {
+		logger.Fatalf("Invalid disabled proving systems configuration: %v", err)
+	}
+
+	experimentalProvingSystems, err := ExperimentalProvingSystemsFromStrings(configuration.Operator.ExperimentalProvingSystems)
+	if err != nil {
+		logger.Fatalf("Invalid experimental proving systems configuration: %v", err)
+	}
+
+	gpuVerification := configuration.Operator.GpuVerification
+	if gpuVerification {
+		if err = gpu.Init(); err != nil {
+			logger.Warnf("GPU verification is enabled but can't be used, proofs will be verified on the CPU: %v", err)
+			gpuVerification = false
+		} else {
+			logger.Infof("GPU verification enabled")
+		}
+	}
+
+	wasmPlugins, err := wasm_plugin.LoadPlugins(configuration.Operator.WasmPlugins)
+	if err != nil {
+		logger.Fatalf("Invalid WASM plugins configuration: %v", err)
+	}
+	if wasmPlugins.Len() > 0 {
+		logger.Infof("Loaded %d WASM verifier plugins", wasmPlugins.Len())
+	}
+
+	var celestiaClient *CelestiaClient
+	if configuration.Operator.CelestiaRpcUrl != "" {
+		celestiaClient = NewCelestiaClient(configuration.Operator.CelestiaRpcUrl, configuration.Operator.CelestiaAuthToken)
+	}
+	var eigenDAClient *EigenDAClient
+	if configuration.Operator.EigenDAProxyUrl != "" {
+		eigenDAClient = NewEigenDAClient(configuration.Operator.EigenDAProxyUrl)
+	}
+
+	var batchCache *BatchCache
+	if configuration.Operator.BatchCacheDir != "" {
+		batchCache, err = NewBatchCache(configuration.Operator.BatchCacheDir, configuration.Operator.BatchCacheSize)
+		if err != nil {
+			logger.Fatalf("Error while loading batch cache: %v. This is probably related to the `batch_cache_dir` field passed in the config file", err)
+		}
+	}
+
+	var batchGossip *BatchGossip
+	if len(configuration.Operator.P2PListenAddresses) > 0 {
+		batchGossip, err = NewBatchGossip(context.Background(), configuration.Operator.P2PListenAddresses, configuration.Operator.P2PBootstrapPeers, configuration.Operator.P2PKeyPath, batchCache, blsSigner, avsReader, logger)
+		if err != nil {
+			logger.Fatalf("Error while starting batch gossip: %v. This is probably related to the `p2p_*` or `batch_cache_dir` fields passed in the config file", err)
+		}
+	}
+
+	verificationCache, err := NewVerificationCache(configuration.Operator.VerificationCacheSize, configuration.Operator.VerificationCacheFilePath)
+	if err != nil {
+		logger.Fatalf("Error while loading verification cache: %v. This is probably related to the `verification_cache_filepath` field passed in the config file", err)
+	}
 
 	operator := &Operator{
-		Config:                    configuration,
-		Logger:                    logger,
-		avsSubscriber:             *avsSubscriber,
-		avsReader:                 *avsReader,
-		Address:                   address,
-		NewTaskCreatedChanV2:      newTaskCreatedChanV2,
-		NewTaskCreatedChanV3:      newTaskCreatedChanV3,
-		aggRpcClient:              *rpcClient,
-		OperatorId:                operatorId,
-		metricsReg:                reg,
-		metrics:                   operatorMetrics,
-		lastProcessedBatchLogFile: lastProcessedBatchLogFile,
+		Config:                     configuration,
+		Logger:                     logger,
+		avsSubscriber:              *avsSubscriber,
+		avsReader:                  *avsReader,
+		Address:                    address,
+		NewTaskCreatedChanV2:       newTaskCreatedChanV2,
+		NewTaskCreatedChanV3:       newTaskCreatedChanV3,
+		aggRpcClient:               rpcClient,
+		OperatorId:                 operatorId,
+		blsSigner:                  blsSigner,
+		previousIdentity:           previousIdentity,
+		metricsReg:                 reg,
+		metrics:                    operatorMetrics,
+		lastProcessedBatchLogFile:  lastProcessedBatchLogFile,
+		verificationPool:           verificationPool,
+		verificationCache:          verificationCache,
+		verificationLimiter:        verificationLimiter,
+		verificationSandbox:        verificationSandbox,
+		disabledProvingSystems:     disabledProvingSystems,
+		experimentalProvingSystems: experimentalProvingSystems,
+		gpuVerification:            gpuVerification,
+		wasmPlugins:                wasmPlugins,
+		celestiaClient:             celestiaClient,
+		eigenDAClient:              eigenDAClient,
+		batchCache:                 batchCache,
+		batchGossip:                batchGossip,
+		downloadLimiter:            NewBatchDownloadLimiter(configuration.Operator.BatchDownloadRateLimit, configuration.Operator.BatchDownloadMaxConnections),
+		batchSourceHealth:          NewBatchSourceHealth(),
+		telemetry:                  NewTelemetry(configuration.Operator.TelemetryIpPortAddress, configuration.Operator.TelemetryApiToken, operatorId, logger),
 		lastProcessedBatch: OperatorLastProcessedBatch{
 			BlockNumber:        0,
 			batchProcessedChan: make(chan uint32),
@@ -136,6 +283,7 @@ func NewOperatorFromConfig(configuration config.OperatorConfig) (*Operator, erro
 	if err != nil {
 		logger.Fatalf("Error while loading last process batch: %v. This is probably related to the `last_processed_batch_filepath` field passed in the config file", err)
 	}
+	operator.status.batchProcessed(operator.lastProcessedBatch.BlockNumber)
 
 	return operator, nil
 }
@@ -186,6 +334,7 @@ func (o *Operator) UpdateLastProcessBatch(blockNumber uint32) error {
 	}
 
 	o.lastProcessedBatch.BlockNumber = blockNumber
+	o.status.batchProcessed(blockNumber)
 
 	// write to a file so it can be recovered in case of operator outage
 	json, err := json.Marshal(o.lastProcessedBatch)
@@ -217,20 +366,41 @@ func (o *Operator) Start(ctx context.Context) error {
 
 	var metricsErrChan <-chan error
 	if o.Config.Operator.EnableMetrics {
-		metricsErrChan = o.metrics.Start(ctx, o.metricsReg)
+		metricsErrChan = o.metrics.Start(ctx, prometheus.Gatherers{o.metricsReg, o.Config.BaseConfig.EthRpcMetricsRegistry})
 	} else {
 		metricsErrChan = make(chan error, 1)
 	}
 
+	// Let the aggregator know which proving systems this operator verifies
+	capabilities, err := o.Capabilities()
+	if err != nil {
+		o.Logger.Errorf("Could not sign operator capabilities: %v", err)
+	} else {
+		go o.aggRpcClient.SendOperatorCapabilitiesToAggregator(capabilities)
+	}
+
 	go o.ProcessMissedBatchesWhileOffline()
 
+	if o.Config.Operator.VersionCheckUrl != "" {
+		go o.runVersionCheck(ctx)
+	}
+
+	var statusErrChan <-chan error
+	if o.Config.Operator.StatusApiIpPortAddress != "" {
+		statusErrChan = o.startStatusServer(o.Config.Operator.StatusApiIpPortAddress)
+	} else {
+		statusErrChan = make(chan error, 1)
+	}
+
 	for {
 		select {
-		case <-context.Background().Done():
+		case <-ctx.Done():
 			o.Logger.Info("Operator shutting down...")
-			return nil
+			return o.shutdown()
 		case err := <-metricsErrChan:
 			o.Logger.Errorf("Metrics server failed", "err", err)
+		case err := <-statusErrChan:
+			o.Logger.Errorf("Status server failed", "err", err)
 		case err := <-subV2:
 			o.Logger.Infof("Error in websocket subscription", "err", err)
 			subV2, err = o.SubscribeToNewTasksV2()
@@ -244,9 +414,9 @@ func (o *Operator) Start(ctx context.Context) error {
 				o.Logger.Fatal("Could not subscribe to new tasks V3")
 			}
 		case newBatchLogV2 := <-o.NewTaskCreatedChanV2:
-			go o.handleNewBatchLogV2(newBatchLogV2)
+			o.handleBatchInBackground(func() { o.handleNewBatchLogV2(newBatchLogV2) })
 		case newBatchLogV3 := <-o.NewTaskCreatedChanV3:
-			go o.handleNewBatchLogV3(newBatchLogV3)
+			o.handleBatchInBackground(func() { o.handleNewBatchLogV3(newBatchLogV3) })
 		case blockNumber := <-o.lastProcessedBatch.batchProcessedChan:
 			err = o.UpdateLastProcessBatch(blockNumber)
 			if err != nil {
@@ -290,7 +460,7 @@ func (o *Operator) ProcessMissedBatchesWhileOffline() {
 
 	o.Logger.Infof("Starting to verify missed batches while offline")
 	for _, logEntry := range logs {
-		go o.handleNewBatchLogV3(&logEntry)
+		o.handleBatchInBackground(func() { o.handleNewBatchLogV3(&logEntry) })
 	}
 	o.Logger.Info("Finished verifying all batches missed while offline")
 }
@@ -310,33 +480,58 @@ func (o *Operator) handleNewBatchLogV2(newBatchLog *servicemanager.ContractAlign
 	defer func() { o.afterHandlingBatchV2(newBatchLog, err == nil) }()
 
 	o.Logger.Info("Received new batch log V2")
-	err = o.ProcessNewBatchLogV2(newBatchLog)
+	o.status.batchSeen(newBatchLog.BatchMerkleRoot, newBatchLog.Raw.BlockNumber)
+	batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, newBatchLog.BatchMerkleRoot, newBatchLog.SenderAddress)
+	batchTrace := o.telemetry.NewBatchTrace(newBatchLog.BatchMerkleRoot)
+	defer o.sendBatchTrace(batchTrace, batchIdentifierHash)
+	err = o.ProcessNewBatchLogV2(newBatchLog, batchTrace)
 	if err != nil {
 		o.Logger.Infof("batch %x did not verify. Err: %v", newBatchLog.BatchMerkleRoot, err)
 		return
 	}
 
-	batchIdentifier := append(newBatchLog.BatchMerkleRoot[:], newBatchLog.SenderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
-	responseSignature := o.SignTaskResponse(batchIdentifierHash)
+	responseSignature, err := o.SignTaskResponse(batchIdentifierHash)
+	if err != nil {
+		o.Logger.Errorf("Could not sign task response of batch %x: %v", newBatchLog.BatchMerkleRoot, err)
+		return
+	}
+	o.status.batchSigned(newBatchLog.BatchMerkleRoot, newBatchLog.Raw.BlockNumber)
 	o.Logger.Debugf("responseSignature about to send: %x", responseSignature)
 
-	signedTaskResponse := types.SignedTaskResponse{
-		BatchIdentifierHash: batchIdentifierHash,
-		BatchMerkleRoot:     newBatchLog.BatchMerkleRoot,
-		SenderAddress:       newBatchLog.SenderAddress,
-		BlsSignature:        *responseSignature,
-		OperatorId:          o.OperatorId,
-	}
+	signedTaskResponse := types.NewSignedTaskResponse(batchIdentifierHash, newBatchLog.BatchMerkleRoot,
+		newBatchLog.SenderAddress, *responseSignature, o.OperatorId)
 	o.Logger.Infof("Signed Task Response to send: BatchIdentifierHash=%s, BatchMerkleRoot=%s, SenderAddress=%s",
 		hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
 		hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
 		hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
 	)
 
+	submissionStart := time.Now()
 	o.aggRpcClient.SendSignedTaskResponseToAggregator(&signedTaskResponse)
+	batchTrace.ObserveSignatureSubmission(time.Since(submissionStart))
+	o.sendPreviousIdentityResponse(signedTaskResponse)
 }
-func (o *Operator) ProcessNewBatchLogV2(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2) error {
+
+// sendBatchTrace sends the trace of the batch once the aggregator trace context of its task is
+// fetched, so it's attached to the aggregator span. Without it, the trace is still sent and the
+// telemetry API falls back to the merkle root.
+func (o *Operator) sendBatchTrace(batchTrace *BatchTrace, batchIdentifierHash [32]byte) {
+	if batchTrace == nil {
+		return
+	}
+	batchTrace.Finish()
+	go func() {
+		traceContext, err := o.aggRpcClient.GetTaskTraceContext(batchIdentifierHash)
+		if err != nil {
+			o.Logger.Debug("Could not get the trace context of the task from the aggregator", "err", err)
+		} else {
+			batchTrace.SetTraceParent(traceContext.TraceParent())
+		}
+		o.telemetry.SendBatchTrace(batchTrace)
+	}()
+}
+
+func (o *Operator) ProcessNewBatchLogV2(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2, batchTrace *BatchTrace) error {
 
 	o.Logger.Info("Received new batch with proofs to verify",
 		"batch merkle root", "0x"+hex.EncodeToString(newBatchLog.BatchMerkleRoot[:]),
@@ -346,44 +541,23 @@ func (o *Operator) ProcessNewBatchLogV2(newBatchLog *servicemanager.ContractAlig
 	ctx, cancel := context.WithTimeout(context.Background(), BatchDownloadTimeout)
 	defer cancel()
 
-	verificationDataBatch, err := o.getBatchFromDataService(ctx, newBatchLog.BatchDataPointer, newBatchLog.BatchMerkleRoot, BatchDownloadMaxRetries, BatchDownloadRetryDelay)
-	if err != nil {
-		o.Logger.Errorf("Could not get proofs from S3 bucket: %v", err)
+	if o.Config.Operator.StreamBatches {
+		err := o.streamBatchFromDataService(ctx, newBatchLog.BatchDataPointer, newBatchLog.BatchMerkleRoot, BatchDownloadMaxRetries, BatchDownloadRetryDelay, batchTrace)
+		if err != nil {
+			o.Logger.Errorf("Could not verify streamed batch: %v", err)
+		}
 		return err
 	}
 
-	verificationDataBatchLen := len(verificationDataBatch)
-	results := make(chan bool, verificationDataBatchLen)
-	var wg sync.WaitGroup
-	wg.Add(verificationDataBatchLen)
-
-	disabledVerifiersBitmap, err := o.avsReader.DisabledVerifiers()
+	downloadStart := time.Now()
+	verificationDataBatch, err := o.getBatchFromDataService(ctx, newBatchLog.BatchDataPointer, newBatchLog.BatchMerkleRoot, BatchDownloadMaxRetries, BatchDownloadRetryDelay)
+	batchTrace.ObserveDownload(time.Since(downloadStart))
 	if err != nil {
-		o.Logger.Errorf("Could not check verifiers status: %s", err)
-		results <- false
+		o.Logger.Errorf("Could not get proofs from S3 bucket: %v", err)
 		return err
 	}
 
-	for _, verificationData := range verificationDataBatch {
-		go func(data VerificationData) {
-			defer wg.Done()
-			o.verify(data, disabledVerifiersBitmap, results)
-			o.metrics.IncOperatorTaskResponses()
-		}(verificationData)
-	}
-
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	for result := range results {
-		if !result {
-			return fmt.Errorf("invalid proof")
-		}
-	}
-
-	return nil
+	return o.verifyBatch(verificationDataBatch, batchTrace)
 }
 
 // Process of handling batches from V3 events:
@@ -391,33 +565,38 @@ func (o *Operator) handleNewBatchLogV3(newBatchLog *servicemanager.ContractAlign
 	var err error
 	defer func() { o.afterHandlingBatchV3(newBatchLog, err == nil) }()
 	o.Logger.Infof("Received new batch log V3")
-	err = o.ProcessNewBatchLogV3(newBatchLog)
+	o.status.batchSeen(newBatchLog.BatchMerkleRoot, newBatchLog.Raw.BlockNumber)
+	batchIdentifierHash := merkle.BatchIdentifierHash(merkle.CurrentVersion, newBatchLog.BatchMerkleRoot, newBatchLog.SenderAddress)
+	batchTrace := o.telemetry.NewBatchTrace(newBatchLog.BatchMerkleRoot)
+	defer o.sendBatchTrace(batchTrace, batchIdentifierHash)
+	err = o.ProcessNewBatchLogV3(newBatchLog, batchTrace)
 	if err != nil {
 		o.Logger.Infof("batch %x did not verify. Err: %v", newBatchLog.BatchMerkleRoot, err)
 		return
 	}
 
-	batchIdentifier := append(newBatchLog.BatchMerkleRoot[:], newBatchLog.SenderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
-	responseSignature := o.SignTaskResponse(batchIdentifierHash)
+	responseSignature, err := o.SignTaskResponse(batchIdentifierHash)
+	if err != nil {
+		o.Logger.Errorf("Could not sign task response of batch %x: %v", newBatchLog.BatchMerkleRoot, err)
+		return
+	}
+	o.status.batchSigned(newBatchLog.BatchMerkleRoot, newBatchLog.Raw.BlockNumber)
 	o.Logger.Debugf("responseSignature about to send: %x", responseSignature)
 
-	signedTaskResponse := types.SignedTaskResponse{
-		BatchIdentifierHash: batchIdentifierHash,
-		BatchMerkleRoot:     newBatchLog.BatchMerkleRoot,
-		SenderAddress:       newBatchLog.SenderAddress,
-		BlsSignature:        *responseSignature,
-		OperatorId:          o.OperatorId,
-	}
+	signedTaskResponse := types.NewSignedTaskResponse(batchIdentifierHash, newBatchLog.BatchMerkleRoot,
+		newBatchLog.SenderAddress, *responseSignature, o.OperatorId)
 	o.Logger.Infof("Signed Task Response to send: BatchIdentifierHash=%s, BatchMerkleRoot=%s, SenderAddress=%s",
 		hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
 		hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
 		hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
 	)
 
+	submissionStart := time.Now()
 	o.aggRpcClient.SendSignedTaskResponseToAggregator(&signedTaskResponse)
+	batchTrace.ObserveSignatureSubmission(time.Since(submissionStart))
+	o.sendPreviousIdentityResponse(signedTaskResponse)
 }
-func (o *Operator) ProcessNewBatchLogV3(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3) error {
+func (o *Operator) ProcessNewBatchLogV3(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3, batchTrace *BatchTrace) error {
 
 	o.Logger.Info("Received new batch with proofs to verify",
 		"batch merkle root", "0x"+hex.EncodeToString(newBatchLog.BatchMerkleRoot[:]),
@@ -427,81 +606,212 @@ func (o *Operator) ProcessNewBatchLogV3(newBatchLog *servicemanager.ContractAlig
 	ctx, cancel := context.WithTimeout(context.Background(), BatchDownloadTimeout)
 	defer cancel()
 
+	if o.Config.Operator.StreamBatches {
+		err := o.streamBatchFromDataService(ctx, newBatchLog.BatchDataPointer, newBatchLog.BatchMerkleRoot, BatchDownloadMaxRetries, BatchDownloadRetryDelay, batchTrace)
+		if err != nil {
+			o.Logger.Errorf("Could not verify streamed batch: %v", err)
+		}
+		return err
+	}
+
+	downloadStart := time.Now()
 	verificationDataBatch, err := o.getBatchFromDataService(ctx, newBatchLog.BatchDataPointer, newBatchLog.BatchMerkleRoot, BatchDownloadMaxRetries, BatchDownloadRetryDelay)
+	batchTrace.ObserveDownload(time.Since(downloadStart))
 	if err != nil {
 		o.Logger.Errorf("Could not get proofs from S3 bucket: %v", err)
 		return err
 	}
 
-	verificationDataBatchLen := len(verificationDataBatch)
-	results := make(chan bool, verificationDataBatchLen)
-	var wg sync.WaitGroup
-	wg.Add(verificationDataBatchLen)
+	return o.verifyBatch(verificationDataBatch, batchTrace)
+}
+
+func (o *Operator) afterHandlingBatchV2(log *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2, succeeded bool) {
+	if succeeded {
+		o.lastProcessedBatch.batchProcessedChan <- uint32(log.Raw.BlockNumber)
+	}
+}
+
+func (o *Operator) afterHandlingBatchV3(log *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3, succeeded bool) {
+	if succeeded {
+		o.lastProcessedBatch.batchProcessedChan <- uint32(log.Raw.BlockNumber)
+	}
+}
+
+// verifyBatch verifies every proof of the batch using the operator verification pool, adding the
+// verification times to the batch trace.
+// It returns an error if any of the proofs is invalid or if the verifiers status can't be checked.
+func (o *Operator) verifyBatch(verificationDataBatch []VerificationData, batchTrace *BatchTrace) error {
 	disabledVerifiersBitmap, err := o.avsReader.DisabledVerifiers()
 	if err != nil {
 		o.Logger.Errorf("Could not check verifiers status: %s", err)
-		results <- false
 		return err
 	}
-	for _, verificationData := range verificationDataBatch {
-		go func(data VerificationData) {
-			defer wg.Done()
-			o.verify(data, disabledVerifiersBitmap, results)
-			o.metrics.IncOperatorTaskResponses()
-		}(verificationData)
-	}
 
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	verified := o.verificationPool.VerifyBatch(verificationDataBatch, func(data VerificationData) bool {
+		defer o.metrics.IncOperatorTaskResponses()
+		return o.verify(data, disabledVerifiersBitmap, batchTrace)
+	})
+	if !verified {
+		return fmt.Errorf("invalid proof")
+	}
 
-	for result := range results {
-		if !result {
-			return fmt.Errorf("invalid proof")
-		}
+	if err = o.verificationCache.Persist(); err != nil {
+		o.Logger.Warnf("Could not persist verification cache: %v", err)
 	}
 
 	return nil
 }
 
-func (o *Operator) afterHandlingBatchV2(log *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2, succeeded bool) {
-	if succeeded {
-		o.lastProcessedBatch.batchProcessedChan <- uint32(log.Raw.BlockNumber)
+// verifyBatchStream is like verifyBatch, but the proofs are requested to next as workers become free,
+// until it returns io.EOF.
+func (o *Operator) verifyBatchStream(next func() (VerificationData, error), batchTrace *BatchTrace) error {
+	disabledVerifiersBitmap, err := o.avsReader.DisabledVerifiers()
+	if err != nil {
+		o.Logger.Errorf("Could not check verifiers status: %s", err)
+		return err
+	}
+
+	verified, err := o.verificationPool.VerifyStream(next, func(data VerificationData) bool {
+		defer o.metrics.IncOperatorTaskResponses()
+		return o.verify(data, disabledVerifiersBitmap, batchTrace)
+	})
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return fmt.Errorf("invalid proof")
 	}
+
+	if err = o.verificationCache.Persist(); err != nil {
+		o.Logger.Warnf("Could not persist verification cache: %v", err)
+	}
+
+	return nil
 }
 
-func (o *Operator) afterHandlingBatchV3(log *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3, succeeded bool) {
-	if succeeded {
-		o.lastProcessedBatch.batchProcessedChan <- uint32(log.Raw.BlockNumber)
+// Reasons a proof isn't verified as valid, reported by the verification failures metric
+const (
+	VerificationFailureDisabledOnChain  = "disabled_on_chain"
+	VerificationFailureDisabledInConfig = "disabled_in_config"
+	VerificationFailureExperimental     = "experimental_not_enabled"
+	VerificationFailureInputTooLarge    = "input_too_large"
+	VerificationFailureTimeout          = "timeout"
+	VerificationFailureInvalidProof     = "invalid_proof"
+	VerificationFailureError            = "error"
+)
+
+// verificationFailureReason returns the verification failure reason of a verification error.
+func verificationFailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrVerificationInputTooLarge):
+		return VerificationFailureInputTooLarge
+	case errors.Is(err, ErrVerificationTimedOut):
+		return VerificationFailureTimeout
+	default:
+		return VerificationFailureError
 	}
 }
 
-func (o *Operator) verify(verificationData VerificationData, disabledVerifiersBitmap *big.Int, results chan bool) {
+func (o *Operator) verify(verificationData VerificationData, disabledVerifiersBitmap *big.Int, batchTrace *BatchTrace) bool {
+	provingSystem := verificationData.ProvingSystemId.String()
 	IsVerifierDisabled := IsVerifierDisabled(disabledVerifiersBitmap, verificationData.ProvingSystemId)
 	if IsVerifierDisabled {
-		o.Logger.Infof("Verifier %s is disabled. Returning false", verificationData.ProvingSystemId.String())
-		results <- false
+		o.metrics.IncOperatorVerifications(provingSystem, "disabled")
+		o.metrics.IncOperatorVerificationFailures(provingSystem, VerificationFailureDisabledOnChain)
+		o.Logger.Infof("Verifier %s is disabled. Returning false", provingSystem)
+		return false
+	}
+	if o.disabledProvingSystems[verificationData.ProvingSystemId] {
+		o.metrics.IncOperatorVerifications(provingSystem, "disabled")
+		o.metrics.IncOperatorVerificationFailures(provingSystem, VerificationFailureDisabledInConfig)
+		o.Logger.Infof("Verifier %s is disabled in the operator config. Returning false", provingSystem)
+		return false
+	}
+	if IsExperimentalProvingSystem(verificationData.ProvingSystemId) && !o.experimentalProvingSystems[verificationData.ProvingSystemId] {
+		o.metrics.IncOperatorVerifications(provingSystem, "disabled")
+		o.metrics.IncOperatorVerificationFailures(provingSystem, VerificationFailureExperimental)
+		o.Logger.Infof("Verifier %s is experimental and not enabled in the operator config. Returning false", provingSystem)
+		return false
+	}
+
+	cacheKey := VerificationCacheKeyFor(verificationData)
+	if o.verificationCache.Contains(cacheKey) {
+		o.Logger.Infof("%s proof already verified, skipping verification", provingSystem)
+		return true
+	}
+
+	verifyFunc := o.verifyProof
+	// WASM plugins already run isolated from the operator, and the sandboxed subprocess doesn't load them
+	if o.verificationSandbox != nil && verificationData.ProvingSystemId != common.WasmPlugin {
+		verifyFunc = o.verificationSandbox.Verify
+	}
+
+	start := time.Now()
+	verificationResult, err := o.verificationLimiter.Verify(verificationData, verifyFunc)
+	verificationDuration := time.Since(start)
+	o.metrics.ObserveOperatorVerificationDuration(provingSystem, verificationDuration)
+	batchTrace.ObserveVerification(provingSystem, verificationDuration)
+	if err != nil {
+		o.metrics.IncOperatorVerifications(provingSystem, "failed")
+		o.metrics.IncOperatorVerificationFailures(provingSystem, verificationFailureReason(err))
+		o.Logger.Errorf("%s proof verification failed: %v", provingSystem, err)
+		o.observeZkVmProof(verificationData, verificationDuration, "failed", batchTrace)
+		return false
+	}
+	if verificationResult {
+		o.metrics.IncOperatorVerifications(provingSystem, "valid")
+		o.verificationCache.Add(cacheKey)
+		o.observeZkVmProof(verificationData, verificationDuration, "valid", batchTrace)
+	} else {
+		o.metrics.IncOperatorVerifications(provingSystem, "invalid")
+		o.metrics.IncOperatorVerificationFailures(provingSystem, VerificationFailureInvalidProof)
+		o.observeZkVmProof(verificationData, verificationDuration, "invalid", batchTrace)
+	}
+	return verificationResult
+}
+
+// observeZkVmProof records each Jolt verification along with the hash of the program it proves,
+// so verifications can be traced back to their program. It's done here rather than next to the
+// verifier call, which runs in the sandbox subprocess when it's enabled.
+func (o *Operator) observeZkVmProof(verificationData VerificationData, duration time.Duration, result string, batchTrace *BatchTrace) {
+	if verificationData.ProvingSystemId != common.Jolt {
 		return
 	}
+	programHash := crypto.Keccak256Hash(verificationData.VmProgramCode).Hex()
+	o.Logger.Info("Jolt proof verified",
+		"program_hash", programHash,
+		"proof_size", len(verificationData.Proof),
+		"program_io_size", len(verificationData.PubInput),
+		"elapsed", duration,
+		"result", result)
+	batchTrace.ObserveProof(verificationData.ProvingSystemId.String(), programHash, duration, result)
+}
+
+func (o *Operator) verifyProof(verificationData VerificationData) bool {
 	switch verificationData.ProvingSystemId {
 	case common.GnarkPlonkBls12_381:
 		verificationResult := o.verifyPlonkProofBLS12_381(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
 		o.Logger.Infof("PLONK BLS12-381 proof verification result: %t", verificationResult)
 
-		results <- verificationResult
+		return verificationResult
 
 	case common.GnarkPlonkBn254:
 		verificationResult := o.verifyPlonkProofBN254(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
 		o.Logger.Infof("PLONK BN254 proof verification result: %t", verificationResult)
 
-		results <- verificationResult
+		return verificationResult
 
 	case common.Groth16Bn254:
 		verificationResult := o.verifyGroth16ProofBN254(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
 		o.Logger.Infof("GROTH16 BN254 proof verification result: %t", verificationResult)
 
-		results <- verificationResult
+		return verificationResult
+
+	case common.Groth16Bls12_381:
+		verificationResult := o.verifyGroth16ProofBLS12_381(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
+		o.Logger.Infof("GROTH16 BLS12-381 proof verification result: %t", verificationResult)
+
+		return verificationResult
 
 	case common.SP1:
 		verificationResult, err := sp1.VerifySp1Proof(verificationData.Proof, verificationData.VmProgramCode)
@@ -513,7 +823,7 @@ func (o *Operator) verify(verificationData VerificationData, disabledVerifiersBi
 			}
 		}
 		o.Logger.Infof("SP1 proof verification result: %t", verificationResult)
-		o.handleVerificationResult(results, verificationResult, err, "SP1 proof verification")
+		return o.handleVerificationResult(verificationResult, err, "SP1 proof verification")
 
 	case common.Risc0:
 		verificationResult, err := risc_zero.VerifyRiscZeroReceipt(verificationData.Proof,
@@ -526,21 +836,72 @@ func (o *Operator) verify(verificationData VerificationData, disabledVerifiersBi
 			}
 		}
 		o.Logger.Infof("Risc0 proof verification result: %t", verificationResult)
-		o.handleVerificationResult(results, verificationResult, err, "Risc0 proof verification")
+		return o.handleVerificationResult(verificationResult, err, "Risc0 proof verification")
+
+	case common.Plonky2:
+		verificationResult, err := plonky2.VerifyPlonky2Proof(verificationData.Proof, verificationData.VerificationKey)
+		return o.handleVerificationResult(verificationResult, err, "Plonky2 proof verification")
+
+	case common.Cairo:
+		verificationResult, err := cairo.VerifyCairoProof(verificationData.Proof, verificationData.PubInput)
+		return o.handleVerificationResult(verificationResult, err, "Cairo proof verification")
+
+	case common.Nova:
+		verificationResult, err := nova.VerifyNovaProof(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
+		return o.handleVerificationResult(verificationResult, err, "Nova proof verification")
+
+	case common.Jolt:
+		verificationResult, err := jolt.VerifyJoltProof(verificationData.Proof, verificationData.VmProgramCode, verificationData.PubInput)
+		return o.handleVerificationResult(verificationResult, err, "Jolt proof verification")
+
+	case common.Miden:
+		verificationResult, err := miden.VerifyMidenProof(verificationData.Proof, verificationData.VmProgramCode, verificationData.PubInput)
+		return o.handleVerificationResult(verificationResult, err, "Miden proof verification")
+
+	case common.Binius:
+		verificationResult, err := binius.VerifyBiniusProof(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
+		return o.handleVerificationResult(verificationResult, err, "Binius proof verification")
+
+	case common.Halo2:
+		verificationResult, err := halo2.VerifyHalo2Proof(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
+		return o.handleVerificationResult(verificationResult, err, "Halo2 proof verification")
+
+	case common.Boojum:
+		verificationResult, err := boojum.VerifyBoojumProof(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
+		return o.handleVerificationResult(verificationResult, err, "Boojum proof verification")
+
+	case common.Valida:
+		verificationResult, err := valida.VerifyValidaProof(verificationData.Proof, verificationData.VmProgramCode, verificationData.PubInput)
+		return o.handleVerificationResult(verificationResult, err, "Valida proof verification")
+
+	case common.Kimchi:
+		pubInput, err := types.NormalizeMinaPublicInput(verificationData.PubInput)
+		if err != nil {
+			return o.handleVerificationResult(false, err, "Kimchi proof verification")
+		}
+		verificationResult, err := kimchi.VerifyKimchiProof(verificationData.Proof, pubInput, verificationData.VerificationKey)
+		return o.handleVerificationResult(verificationResult, err, "Kimchi proof verification")
+
+	case common.WasmPlugin:
+		if o.wasmPlugins == nil {
+			return o.handleVerificationResult(false, fmt.Errorf("no WASM plugins loaded"), "WASM plugin proof verification")
+		}
+		verificationResult, err := o.wasmPlugins.Verify(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
+		return o.handleVerificationResult(verificationResult, err, "WASM plugin proof verification")
+
 	default:
 		o.Logger.Error("Unrecognized proving system ID")
-		results <- false
+		return false
 	}
 }
 
-func (o *Operator) handleVerificationResult(results chan bool, isVerified bool, err error, name string) {
+func (o *Operator) handleVerificationResult(isVerified bool, err error, name string) bool {
 	if err != nil {
 		o.Logger.Errorf("%v failed %v", name, err)
-		results <- false
-	} else {
-		o.Logger.Infof("%v result: %t", name, isVerified)
-		results <- isVerified
+		return false
 	}
+	o.Logger.Infof("%v result: %t", name, isVerified)
+	return isVerified
 }
 
 // VerifyPlonkProofBLS12_381 verifies a PLONK proof using BLS12-381 curve.
@@ -558,6 +919,11 @@ func (o *Operator) verifyGroth16ProofBN254(proofBytes []byte, pubInputBytes []by
 	return o.verifyGroth16Proof(proofBytes, pubInputBytes, verificationKeyBytes, ecc.BN254)
 }
 
+// VerifyGroth16ProofBLS12_381 verifies a GROTH16 proof using BLS12-381 curve.
+func (o *Operator) verifyGroth16ProofBLS12_381(proofBytes []byte, pubInputBytes []byte, verificationKeyBytes []byte) bool {
+	return o.verifyGroth16Proof(proofBytes, pubInputBytes, verificationKeyBytes, ecc.BLS12_381)
+}
+
 // verifyPlonkProof contains the common proof verification logic.
 func (o *Operator) verifyPlonkProof(proofBytes []byte, pubInputBytes []byte, verificationKeyBytes []byte, curve ecc.ID) bool {
 	proofReader := bytes.NewReader(proofBytes)
@@ -616,27 +982,47 @@ func (o *Operator) verifyGroth16Proof(proofBytes []byte, pubInputBytes []byte, v
 		return false
 	}
 
+	// BLS12-381 proofs must be encoded exactly, so data serialized for another curve is rejected.
+	// BN254 keeps its original behaviour so proofs that were accepted before are not rejected now.
+	if curve == ecc.BLS12_381 && (proofReader.Len() != 0 || verificationKeyReader.Len() != 0) {
+		o.Logger.Infof("Groth16 proof or verifying key have trailing bytes, they are probably not encoded for %s", curve)
+		return false
+	}
+
+	if curve == ecc.BN254 && o.gpuVerification {
+		return o.verifyGroth16Bn254WithGpu(proof, verificationKey, pubInput)
+	}
+
 	err = groth16.Verify(proof, verificationKey, pubInput)
 	return err == nil
 }
 
-func (o *Operator) SignTaskResponse(batchIdentifierHash [32]byte) *bls.Signature {
-	responseSignature := *o.Config.BlsConfig.KeyPair.SignMessage(batchIdentifierHash)
-	return &responseSignature
+func (o *Operator) SignTaskResponse(batchIdentifierHash [32]byte) (*bls.Signature, error) {
+	return o.blsSigner.Sign(context.Background(), batchIdentifierHash)
 }
 
 func (o *Operator) SendTelemetryData(ctx *cli.Context) error {
+	o.Version = ctx.App.Version
+	return o.sendVersionTelemetry(ctx.Context, "")
+}
+
+// sendVersionTelemetry sends the operator version to the operator tracker, along with the version
+// status reported by the version check, if known.
+func (o *Operator) sendVersionTelemetry(ctx context.Context, versionStatus VersionStatus) error {
 	// hash version
 	hash := sha3.NewLegacyKeccak256()
-	hash.Write([]byte(ctx.App.Version))
+	hash.Write([]byte(o.Version))
 
 	// get hash
 	var version [32]byte // All zeroed initially
 	copy(version[:], hash.Sum(nil))
 
 	// sign version
-	signature := o.Config.BlsConfig.KeyPair.SignMessage(version)
-	public_key_g2 := o.Config.BlsConfig.KeyPair.GetPubKeyG2()
+	signature, err := o.blsSigner.Sign(ctx, version)
+	if err != nil {
+		return err
+	}
+	public_key_g2 := o.blsSigner.PublicKeyG2()
 	ethRpcUrl, err := BaseUrlOnly(o.Config.BaseConfig.EthRpcUrl)
 	if err != nil {
 		return err
@@ -660,10 +1046,13 @@ func (o *Operator) SendTelemetryData(ctx *cli.Context) error {
 		"eth_ws_url":           ethWsUrl,
 		"eth_ws_url_fallback":  ethWsUrlFallback,
 		"address":              o.Address,
-		"version":              ctx.App.Version,
+		"version":              o.Version,
 		"signature":            signature.Bytes(),
 		"pub_key_g2":           public_key_g2.Bytes(),
 	}
+	if versionStatus != "" {
+		body["version_status"] = versionStatus
+	}
 
 	bodyBuffer := new(bytes.Buffer)
 
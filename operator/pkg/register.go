@@ -2,7 +2,10 @@ package operator
 
 import (
 	"context"
+	"math/big"
 
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/yetanotherco/aligned_layer/core/chainio"
 	"github.com/yetanotherco/aligned_layer/core/config"
@@ -18,19 +21,49 @@ func RegisterOperator(
 	ecdsaConfig *config.EcdsaConfig,
 	operatorToAvsRegistrationSigSalt [32]byte,
 ) error {
-	writer, err := chainio.NewAvsWriterFromConfig(configuration.BaseConfig, ecdsaConfig, nil)
+	return RegisterOperatorInQuorums(ctx, configuration, ecdsaConfig, types.QuorumNums{0})
+}
+
+// RegisterOperatorInQuorums registers the operator in the given quorums, which opts a registered
+// operator in to the quorums it isn't registered in yet, keeping its registered socket.
+func RegisterOperatorInQuorums(
+	ctx context.Context,
+	configuration *config.OperatorConfig,
+	ecdsaConfig *config.EcdsaConfig,
+	quorumNumbers types.QuorumNums,
+) error {
+	writer, err := chainio.NewAvsWriterFromConfig(configuration.BaseConfig, ecdsaConfig, nil, nil)
 	if err != nil {
 		configuration.BaseConfig.Logger.Error("Failed to create AVS writer", "err", err)
 		return err
 	}
 
-	socket := "Not Needed"
-
-	quorumNumbers := types.QuorumNums{0}
+	socket := types.Socket("Not Needed")
+	avsReader, err := chainio.NewAvsReaderFromConfig(configuration.BaseConfig)
+	if err != nil {
+		configuration.BaseConfig.Logger.Error("Failed to create AVS reader", "err", err)
+		return err
+	}
+	registered, err := avsReader.IsOperatorRegistered(configuration.Operator.Address)
+	if err != nil {
+		configuration.BaseConfig.Logger.Error("Failed to check if the operator is registered", "err", err)
+		return err
+	}
+	if registered {
+		// registering emits the socket again, keep the one the operator set with update-socket
+		registeredSocket, err := avsReader.GetOperatorSocket(ctx, configuration.Operator.Address)
+		if err != nil {
+			configuration.BaseConfig.Logger.Error("Failed to get the operator socket", "err", err)
+			return err
+		}
+		if registeredSocket != "" {
+			socket = registeredSocket
+		}
+	}
 
 	_, err = writer.RegisterOperator(ctx, ecdsaConfig.PrivateKey,
 		configuration.BlsConfig.KeyPair,
-		quorumNumbers, socket, true)
+		quorumNumbers, string(socket), true)
 
 	if err != nil {
 		configuration.BaseConfig.Logger.Error("Failed to register operator", "err", err)
@@ -39,3 +72,54 @@ func RegisterOperator(
 
 	return nil
 }
+
+// DeregisterOperator deregisters the operator of the given keys from the given quorums,
+// deregistering it from the AVS once it isn't in any quorum.
+func DeregisterOperator(
+	ctx context.Context,
+	configuration *config.OperatorConfig,
+	ecdsaConfig *config.EcdsaConfig,
+	blsPubkeyG1 *bls.G1Point,
+	quorumNumbers types.QuorumNums,
+) error {
+	writer, err := chainio.NewAvsWriterFromConfig(configuration.BaseConfig, ecdsaConfig, nil, nil)
+	if err != nil {
+		configuration.BaseConfig.Logger.Error("Failed to create AVS writer", "err", err)
+		return err
+	}
+
+	pubkey := regcoord.BN254G1Point{
+		X: blsPubkeyG1.X.BigInt(new(big.Int)),
+		Y: blsPubkeyG1.Y.BigInt(new(big.Int)),
+	}
+
+	_, err = writer.DeregisterOperator(ctx, quorumNumbers, pubkey, true)
+	if err != nil {
+		configuration.BaseConfig.Logger.Error("Failed to deregister operator", "err", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateOperatorSocket updates the socket the operator registered with.
+func UpdateOperatorSocket(
+	ctx context.Context,
+	configuration *config.OperatorConfig,
+	ecdsaConfig *config.EcdsaConfig,
+	socket string,
+) error {
+	writer, err := chainio.NewAvsWriterFromConfig(configuration.BaseConfig, ecdsaConfig, nil, nil)
+	if err != nil {
+		configuration.BaseConfig.Logger.Error("Failed to create AVS writer", "err", err)
+		return err
+	}
+
+	_, err = writer.UpdateSocket(ctx, types.Socket(socket), true)
+	if err != nil {
+		configuration.BaseConfig.Logger.Error("Failed to update operator socket", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package operator
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// remoteSignerSignMethod is the Cerberus signer gRPC method signing arbitrary 32 byte messages
+	remoteSignerSignMethod = "/signer.v1.Signer/SignGeneric"
+	RemoteSignerTimeout    = 10 * time.Second
+)
+
+// RemoteBlsSigner delegates signing to a Cerberus compatible remote signer over gRPC,
+// so the BLS private key doesn't have to be in the operator host.
+//
+// Signatures are checked against the configured public key before being used, so a
+// misconfigured signer can't make the operator send invalid responses to the aggregator.
+type RemoteBlsSigner struct {
+	conn           *grpc.ClientConn
+	publicKeyG1Hex string
+	publicKeyG1    *bls.G1Point
+	publicKeyG2    *bls.G2Point
+	password       string
+	apiKey         string
+}
+
+// NewRemoteBlsSigner connects to the remote signer, over TLS if a CA certificate is configured.
+// The config loading makes sure credentials are only sent without TLS to a loopback signer.
+func NewRemoteBlsSigner(signerConfig *config.RemoteBlsSignerConfig) (*RemoteBlsSigner, error) {
+	transportCredentials := insecure.NewCredentials()
+	if signerConfig.TlsCaCertPath != "" {
+		caCert, err := os.ReadFile(signerConfig.TlsCaCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read remote signer CA certificate: %w", err)
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("invalid remote signer CA certificate")
+		}
+		transportCredentials = credentials.NewTLS(&tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12})
+	}
+
+	conn, err := grpc.Dial(signerConfig.Url, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to remote signer: %w", err)
+	}
+
+	return &RemoteBlsSigner{
+		conn:           conn,
+		publicKeyG1Hex: signerConfig.PublicKeyG1Hex,
+		publicKeyG1:    signerConfig.PublicKeyG1,
+		publicKeyG2:    signerConfig.PublicKeyG2,
+		password:       signerConfig.Password,
+		apiKey:         signerConfig.ApiKey,
+	}, nil
+}
+
+func (s *RemoteBlsSigner) Sign(ctx context.Context, message [32]byte) (*bls.Signature, error) {
+	ctx, cancel := context.WithTimeout(ctx, RemoteSignerTimeout)
+	defer cancel()
+	if s.apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.apiKey)
+	}
+
+	request := &signGenericRequest{publicKeyG1: s.publicKeyG1Hex, data: message[:], password: s.password}
+	response := &signGenericResponse{}
+	if err := s.conn.Invoke(ctx, remoteSignerSignMethod, request, response, grpc.ForceCodec(remoteSignerCodec{})); err != nil {
+		return nil, fmt.Errorf("remote signer failed to sign: %w", err)
+	}
+
+	// the signature is the G1 point serialized as its big endian X and Y coordinates
+	signaturePoint, err := utils.DeserializeG1Point("remote signer signature", response.signature)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid signature: %w", err)
+	}
+	signature := &bls.Signature{G1Point: signaturePoint}
+	valid, err := signature.Verify(s.publicKeyG2, message)
+	if err != nil || !valid {
+		return nil, errors.New("remote signer returned a signature that doesn't match the operator public key")
+	}
+	return signature, nil
+}
+
+func (s *RemoteBlsSigner) PublicKeyG1() *bls.G1Point {
+	return s.publicKeyG1
+}
+
+func (s *RemoteBlsSigner) PublicKeyG2() *bls.G2Point {
+	return s.publicKeyG2
+}
+
+func (s *RemoteBlsSigner) Close() error {
+	return s.conn.Close()
+}
+
+// signGenericRequest and signGenericResponse are the protobuf messages of the signer SignGeneric method.
+// They are encoded by hand to avoid depending on the signer generated code for a single call.
+type signGenericRequest struct {
+	publicKeyG1 string
+	data        []byte
+	password    string
+}
+
+type signGenericResponse struct {
+	signature []byte
+}
+
+func (r *signGenericRequest) marshal() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, r.publicKeyG1)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendBytes(b, r.data)
+	if r.password != "" {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendString(b, r.password)
+	}
+	return b
+}
+
+func (r *signGenericRequest) unmarshal(b []byte) error {
+	return unmarshalProtoFields(b, func(num protowire.Number, value []byte) {
+		switch num {
+		case 1:
+			r.publicKeyG1 = string(value)
+		case 2:
+			r.data = value
+		case 3:
+			r.password = string(value)
+		}
+	})
+}
+
+func (r *signGenericResponse) marshal() []byte {
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, r.signature)
+}
+
+func (r *signGenericResponse) unmarshal(b []byte) error {
+	return unmarshalProtoFields(b, func(num protowire.Number, value []byte) {
+		if num == 1 {
+			r.signature = value
+		}
+	})
+}
+
+// unmarshalProtoFields calls field with the length delimited fields of a protobuf message, skipping the others.
+func unmarshalProtoFields(b []byte, field func(num protowire.Number, value []byte)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		field(num, append([]byte(nil), value...))
+		b = b[n:]
+	}
+	return nil
+}
+
+type remoteSignerMessage interface {
+	marshal() []byte
+	unmarshal([]byte) error
+}
+
+// remoteSignerCodec encodes the hand written signer messages as protobuf
+type remoteSignerCodec struct{}
+
+func (remoteSignerCodec) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(remoteSignerMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected remote signer message %T", v)
+	}
+	return message.marshal(), nil
+}
+
+func (remoteSignerCodec) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(remoteSignerMessage)
+	if !ok {
+		return fmt.Errorf("unexpected remote signer message %T", v)
+	}
+	return message.unmarshal(data)
+}
+
+func (remoteSignerCodec) Name() string {
+	return "proto"
+}
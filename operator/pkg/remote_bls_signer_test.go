@@ -0,0 +1,121 @@
+package operator
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// startFakeRemoteSigner serves the signer SignGeneric method, signing with keyPair
+func startFakeRemoteSigner(t *testing.T, keyPair *bls.KeyPair, apiKey string) string {
+	return startFakeRemoteSignerWithEncoding(t, keyPair, apiKey, (*bls.Signature).Serialize)
+}
+
+// startFakeRemoteSignerWithEncoding serves the signer SignGeneric method, encoding the signatures with encode
+func startFakeRemoteSignerWithEncoding(t *testing.T, keyPair *bls.KeyPair, apiKey string, encode func(*bls.Signature) []byte) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	server := grpc.NewServer(
+		grpc.ForceServerCodec(remoteSignerCodec{}),
+		grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+			if method, _ := grpc.MethodFromServerStream(stream); method != remoteSignerSignMethod {
+				t.Errorf("Unexpected remote signer method %s", method)
+			}
+			md, _ := metadata.FromIncomingContext(stream.Context())
+			if authorization := md.Get("authorization"); len(authorization) != 1 || authorization[0] != "Bearer "+apiKey {
+				t.Errorf("Expected the api key to be sent, got %v", authorization)
+			}
+			request := &signGenericRequest{}
+			if err := stream.RecvMsg(request); err != nil {
+				return err
+			}
+			if request.publicKeyG1 != hex.EncodeToString(keyPair.GetPubKeyG1().Serialize()) || request.password != "secret" {
+				t.Errorf("Unexpected sign request for key %s", request.publicKeyG1)
+			}
+			signature := keyPair.SignMessage(*(*[32]byte)(request.data))
+			return stream.SendMsg(&signGenericResponse{signature: encode(signature)})
+		}),
+	)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func newTestRemoteSigner(t *testing.T, url string, keyPair *bls.KeyPair) *RemoteBlsSigner {
+	signer, err := NewRemoteBlsSigner(&config.RemoteBlsSignerConfig{
+		Url:            url,
+		PublicKeyG1Hex: hex.EncodeToString(keyPair.GetPubKeyG1().Serialize()),
+		PublicKeyG1:    keyPair.GetPubKeyG1(),
+		PublicKeyG2:    keyPair.GetPubKeyG2(),
+		Password:       "secret",
+		ApiKey:         "api-key",
+	})
+	if err != nil {
+		t.Fatalf("Error creating remote signer: %v", err)
+	}
+	t.Cleanup(func() { _ = signer.Close() })
+	return signer
+}
+
+func TestRemoteBlsSignerSigns(t *testing.T) {
+	keyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Error generating BLS keys: %v", err)
+	}
+	signer := newTestRemoteSigner(t, startFakeRemoteSigner(t, keyPair, "api-key"), keyPair)
+
+	message := [32]byte{1, 2, 3}
+	signature, err := signer.Sign(context.Background(), message)
+	if err != nil {
+		t.Fatalf("Error signing: %v", err)
+	}
+	if valid, err := signature.Verify(keyPair.GetPubKeyG2(), message); err != nil || !valid {
+		t.Errorf("Expected a valid signature")
+	}
+}
+
+func TestRemoteBlsSignerRejectsSignaturesOfOtherKeys(t *testing.T) {
+	keyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Error generating BLS keys: %v", err)
+	}
+	otherKeyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Error generating BLS keys: %v", err)
+	}
+	// the signer signs with a different key than the operator one
+	signer := newTestRemoteSigner(t, startFakeRemoteSigner(t, otherKeyPair, "api-key"), otherKeyPair)
+	signer.publicKeyG2 = keyPair.GetPubKeyG2()
+
+	if _, err = signer.Sign(context.Background(), [32]byte{1}); err == nil {
+		t.Errorf("Expected the signature of another key to be rejected")
+	}
+}
+
+func TestRemoteBlsSignerRejectsNonCanonicalSignatures(t *testing.T) {
+	keyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Error generating BLS keys: %v", err)
+	}
+	// X plus the modulus, which is the same signature once reduced
+	nonCanonical := func(signature *bls.Signature) []byte {
+		serialized := signature.Serialize()
+		x := new(big.Int).SetBytes(serialized[:fp.Bytes])
+		return append(x.Add(x, fp.Modulus()).FillBytes(make([]byte, fp.Bytes)), serialized[fp.Bytes:]...)
+	}
+	signer := newTestRemoteSigner(t, startFakeRemoteSignerWithEncoding(t, keyPair, "api-key", nonCanonical), keyPair)
+
+	if _, err = signer.Sign(context.Background(), [32]byte{1}); err == nil {
+		t.Errorf("Expected the non-canonical signature to be rejected")
+	}
+}
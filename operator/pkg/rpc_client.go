@@ -1,64 +1,344 @@
 package operator
 
 import (
+	"context"
 	"errors"
+	"io"
+	"math/rand"
+	"net"
 	"net/rpc"
+	"sync"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/yetanotherco/aligned_layer/core/types"
 )
 
-// AggregatorRpcClient is the client to communicate with the aggregator via RPC
+// AggregatorRpcClient is the client to communicate with the aggregator via RPC.
+//
+// When the connection to the aggregator is lost, the client reconnects in the background with
+// exponential backoff and jitter, so operators don't reconnect all at once when the aggregator
+// restarts. Signed responses sent while disconnected are queued and sent once reconnected.
+//
+// The client can have standby aggregators, which it connects to when the primary one can't be
+// reached. Every connection attempt tries the primary aggregator first, so the client switches
+// back to it once it's available and the standby connection is lost.
+//
+// Close stops reconnecting and retrying calls, so the client must be closed once it's not used.
 type AggregatorRpcClient struct {
-	rpcClient            *rpc.Client
-	aggregatorIpPortAddr string
-	logger               logging.Logger
+	// aggregatorIpPortAddrs are the primary aggregator address followed by the standby ones
+	aggregatorIpPortAddrs []string
+	logger                logging.Logger
+
+	mu        sync.Mutex
+	rpcClient *rpc.Client
+	// capabilities are sent again every time the client reconnects, since the aggregator may have restarted
+	capabilities *types.OperatorCapabilities
+	// pendingResponses are the signed responses to send once reconnected, oldest first
+	pendingResponses []*types.SignedTaskResponse
+	reconnecting     bool
+	// ctx is cancelled by Close, stopping the reconnection and the retries of calls
+	ctx        context.Context
+	cancel     context.CancelFunc
+	reconnects sync.WaitGroup
+
+	reconnectMinDelay time.Duration
+	reconnectMaxDelay time.Duration
 }
 
 const (
 	MaxRetries    = 10
 	RetryInterval = 10 * time.Second
+
+	ReconnectMinDelay = 1 * time.Second
+	ReconnectMaxDelay = 1 * time.Minute
+	// MaxPendingResponses is the max number of signed responses queued while disconnected,
+	// the oldest ones are dropped once exceeded since their tasks are likely expired
+	MaxPendingResponses = 100
 )
 
-func NewAggregatorRpcClient(aggregatorIpPortAddr string, logger logging.Logger) (*AggregatorRpcClient, error) {
-	client, err := rpc.DialHTTP("tcp", aggregatorIpPortAddr)
+// NewAggregatorRpcClient connects to the first reachable aggregator of aggregatorIpPortAddrs,
+// the primary aggregator followed by the standby ones.
+func NewAggregatorRpcClient(aggregatorIpPortAddrs []string, logger logging.Logger) (*AggregatorRpcClient, error) {
+	c := &AggregatorRpcClient{
+		aggregatorIpPortAddrs: aggregatorIpPortAddrs,
+		logger:                logger,
+		reconnectMinDelay:     ReconnectMinDelay,
+		reconnectMaxDelay:     ReconnectMaxDelay,
+	}
+	client, err := c.dial()
 	if err != nil {
 		return nil, err
 	}
+	c.rpcClient = client
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	return c, nil
+}
+
+// Close stops reconnecting to the aggregator, waiting for the reconnection in progress to stop,
+// and closes the connection. Queued responses that weren't sent yet are dropped.
+func (c *AggregatorRpcClient) Close() error {
+	if c == nil {
+		return nil
+	}
+	c.cancel()
+	c.reconnects.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rpcClient == nil {
+		return nil
+	}
+	err := c.rpcClient.Close()
+	c.rpcClient = nil
+	return err
+}
+
+// sleep waits for d, returning false if the client is closed meanwhile.
+func (c *AggregatorRpcClient) sleep(d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-c.ctx.Done():
+		return false
+	}
+}
+
+// dial connects to the first reachable aggregator, in order.
+func (c *AggregatorRpcClient) dial() (*rpc.Client, error) {
+	var errs []error
+	for i, aggregatorIpPortAddr := range c.aggregatorIpPortAddrs {
+		client, err := c.dialAggregator(aggregatorIpPortAddr)
+		if err == nil {
+			if i > 0 {
+				c.logger.Warnf("Primary aggregator unreachable, connected to standby aggregator %s", aggregatorIpPortAddr)
+			}
+			return client, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no aggregator address configured")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// dialAggregator connects to the aggregator with the protobuf messages, falling back to gob for the
+// aggregators of previous releases.
+func (c *AggregatorRpcClient) dialAggregator(aggregatorIpPortAddr string) (*rpc.Client, error) {
+	client, err := types.DialProtoRpc(aggregatorIpPortAddr)
+	if errors.Is(err, types.ErrProtoRpcUnsupported) {
+		c.logger.Warnf("Aggregator %s doesn't support protobuf messages, falling back to gob", aggregatorIpPortAddr)
+		return rpc.DialHTTP("tcp", aggregatorIpPortAddr)
+	}
+	return client, err
+}
+
+// Connected returns whether the client is connected to an aggregator.
+func (c *AggregatorRpcClient) Connected() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rpcClient != nil
+}
 
-	return &AggregatorRpcClient{
-		rpcClient:            client,
-		aggregatorIpPortAddr: aggregatorIpPortAddr,
-		logger:               logger,
-	}, nil
+// isConnectionError returns whether err means the connection to the aggregator was lost,
+// as opposed to the aggregator rejecting the call.
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, rpc.ErrShutdown) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
+
+// call calls the aggregator, failing with rpc.ErrShutdown while disconnected.
+func (c *AggregatorRpcClient) call(method string, args interface{}, reply interface{}) error {
+	c.mu.Lock()
+	client := c.rpcClient
+	c.mu.Unlock()
+	if client == nil {
+		return rpc.ErrShutdown
+	}
+	return client.Call(method, args, reply)
 }
 
 // SendSignedTaskResponseToAggregator is the method called by operators via RPC to send
-// their signed task response.
+// their signed task response. If the connection is lost, the response is queued and sent
+// once the client reconnects.
 func (c *AggregatorRpcClient) SendSignedTaskResponseToAggregator(signedTaskResponse *types.SignedTaskResponse) {
 	var reply uint8
 	for retries := 0; retries < MaxRetries; retries++ {
-		err := c.rpcClient.Call("Aggregator.ProcessOperatorSignedTaskResponseV2", signedTaskResponse, &reply)
+		err := c.call("Aggregator.ProcessOperatorSignedTaskResponseV2", signedTaskResponse, &reply)
+		if err == nil {
+			c.logger.Info("Signed task response header accepted by aggregator.", "reply", reply)
+			return
+		}
+		c.logger.Error("Received error from aggregator", "err", err)
+		if isConnectionError(err) {
+			c.logger.Error("Connection to aggregator lost. Queueing signed task response until reconnected...")
+			c.queueResponse(signedTaskResponse)
+			c.reconnectInBackground()
+			return
+		}
+		c.logger.Infof("Received error from aggregator: %s. Retrying ProcessOperatorSignedTaskResponseV2 RPC call...", err)
+		if !c.sleep(RetryInterval) {
+			return
+		}
+	}
+}
+
+// SendOperatorCapabilitiesToAggregator advertises the proving systems the operator verifies to the aggregator.
+// The capabilities are kept to be sent again when the client reconnects.
+func (c *AggregatorRpcClient) SendOperatorCapabilitiesToAggregator(capabilities *types.OperatorCapabilities) {
+	c.mu.Lock()
+	c.capabilities = capabilities
+	c.mu.Unlock()
+
+	var reply uint8
+	for retries := 0; retries < MaxRetries; retries++ {
+		err := c.call("Aggregator.ProcessOperatorCapabilities", capabilities, &reply)
+		if err == nil {
+			c.logger.Info("Operator capabilities accepted by aggregator.", "reply", reply)
+			return
+		}
+		c.logger.Error("Received error from aggregator", "err", err)
+		if isConnectionError(err) {
+			// the capabilities are sent once reconnected
+			c.logger.Error("Connection to aggregator lost. Reconnecting...")
+			c.reconnectInBackground()
+			return
+		}
+		c.logger.Infof("Received error from aggregator: %s. Retrying ProcessOperatorCapabilities RPC call...", err)
+		if !c.sleep(RetryInterval) {
+			return
+		}
+	}
+}
+
+// GetTaskTraceContext gets the trace context of the task from the aggregator, so the operator trace
+// of the batch is attached to the aggregator one. It's not retried, since the trace is optional.
+func (c *AggregatorRpcClient) GetTaskTraceContext(batchIdentifierHash [32]byte) (types.TaskTraceContext, error) {
+	var reply types.TaskTraceContext
+	err := c.call("Aggregator.GetTaskTraceContext", &batchIdentifierHash, &reply)
+	return reply, err
+}
+
+func (c *AggregatorRpcClient) queueResponse(signedTaskResponse *types.SignedTaskResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pendingResponses = append(c.pendingResponses, signedTaskResponse)
+	if len(c.pendingResponses) > MaxPendingResponses {
+		dropped := c.pendingResponses[0]
+		c.pendingResponses = c.pendingResponses[1:]
+		c.logger.Warnf("Too many signed task responses queued, dropping the one of batch %x", dropped.BatchMerkleRoot)
+	}
+}
+
+// reconnectInBackground starts reconnecting to the aggregator, unless it's already being done
+// or the client is closed.
+func (c *AggregatorRpcClient) reconnectInBackground() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.reconnecting || c.ctx.Err() != nil {
+		return
+	}
+	c.reconnecting = true
+	if c.rpcClient != nil {
+		_ = c.rpcClient.Close()
+		c.rpcClient = nil
+	}
+	c.reconnects.Add(1)
+	go func() {
+		defer c.reconnects.Done()
+		c.reconnect()
+	}()
+}
+
+// reconnect dials the aggregator until it succeeds, waiting an exponentially growing and
+// jittered delay between attempts. Once connected, it sends the operator capabilities and
+// the queued responses, starting over if the connection is lost again meanwhile.
+// It stops once the client is closed.
+func (c *AggregatorRpcClient) reconnect() {
+	delay := c.reconnectMinDelay
+	for {
+		// the jitter spreads the reconnections of all the operators after an aggregator restart
+		jitteredDelay := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		c.logger.Infof("Reconnecting to aggregator in %s", jitteredDelay)
+		if !c.sleep(jitteredDelay) {
+			c.logger.Info("Stopped reconnecting to aggregator, the client was closed")
+			c.mu.Lock()
+			c.reconnecting = false
+			c.mu.Unlock()
+			return
+		}
+		delay *= 2
+		if delay > c.reconnectMaxDelay {
+			delay = c.reconnectMaxDelay
+		}
+
+		client, err := c.dial()
 		if err != nil {
-			c.logger.Error("Received error from aggregator", "err", err)
-			if errors.Is(err, rpc.ErrShutdown) {
-				c.logger.Error("Aggregator is shutdown. Reconnecting...")
-				client, err := rpc.DialHTTP("tcp", c.aggregatorIpPortAddr)
-				if err != nil {
-					c.logger.Error("Could not reconnect to aggregator", "err", err)
-					time.Sleep(RetryInterval)
-				} else {
-					c.rpcClient = client
-					c.logger.Info("Reconnected to aggregator")
-				}
-			} else {
-				c.logger.Infof("Received error from aggregator: %s. Retrying ProcessOperatorSignedTaskResponseV2 RPC call...", err)
-				time.Sleep(RetryInterval)
+			c.logger.Error("Could not reconnect to aggregator", "err", err)
+			continue
+		}
+		c.logger.Info("Reconnected to aggregator")
+
+		if err = c.flush(client); err != nil {
+			c.logger.Error("Connection to aggregator lost while sending queued messages", "err", err)
+			_ = client.Close()
+			continue
+		}
+		return
+	}
+}
+
+// flush sends the capabilities and queued responses through the new connection,
+// making it the client connection once there is nothing left to send.
+func (c *AggregatorRpcClient) flush(client *rpc.Client) error {
+	var reply uint8
+	c.mu.Lock()
+	capabilities := c.capabilities
+	c.mu.Unlock()
+	if capabilities != nil {
+		if err := client.Call("Aggregator.ProcessOperatorCapabilities", capabilities, &reply); err != nil {
+			if isConnectionError(err) {
+				return err
 			}
+			c.logger.Warn("Could not send operator capabilities to aggregator", "err", err)
+		}
+	}
+
+	for {
+		c.mu.Lock()
+		if c.ctx.Err() != nil {
+			c.mu.Unlock()
+			return c.ctx.Err()
+		}
+		if len(c.pendingResponses) == 0 {
+			c.rpcClient = client
+			c.reconnecting = false
+			c.mu.Unlock()
+			return nil
+		}
+		signedTaskResponse := c.pendingResponses[0]
+		c.mu.Unlock()
+
+		err := client.Call("Aggregator.ProcessOperatorSignedTaskResponseV2", signedTaskResponse, &reply)
+		if err != nil && isConnectionError(err) {
+			return err
+		}
+		if err != nil {
+			c.logger.Warn("Aggregator rejected queued signed task response", "err", err)
 		} else {
-			c.logger.Info("Signed task response header accepted by aggregator.", "reply", reply)
-			return
+			c.logger.Info("Queued signed task response accepted by aggregator.", "reply", reply)
+		}
+
+		c.mu.Lock()
+		if len(c.pendingResponses) > 0 && c.pendingResponses[0] == signedTaskResponse {
+			c.pendingResponses = c.pendingResponses[1:]
 		}
+		c.mu.Unlock()
 	}
 }
@@ -0,0 +1,254 @@
+package operator
+
+import (
+	"net"
+	"net/http"
+	"net/rpc"
+	"sync"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// fakeAggregator records the responses and capabilities it receives
+type fakeAggregator struct {
+	mu           sync.Mutex
+	responses    [][32]byte
+	signers      []eigentypes.OperatorId
+	capabilities int
+}
+
+func (a *fakeAggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *types.SignedTaskResponse, reply *uint8) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.responses = append(a.responses, signedTaskResponse.BatchMerkleRoot)
+	a.signers = append(a.signers, signedTaskResponse.OperatorId)
+	*reply = 0
+	return nil
+}
+
+func (a *fakeAggregator) ProcessOperatorCapabilities(capabilities *types.OperatorCapabilities, reply *uint8) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.capabilities++
+	*reply = 0
+	return nil
+}
+
+func (a *fakeAggregator) received() ([][32]byte, int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([][32]byte(nil), a.responses...), a.capabilities
+}
+
+// fakeAggregatorServer serves a fakeAggregator, keeping its connections so they can be dropped,
+// since RPC over HTTP hijacks them
+type fakeAggregatorServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	conns    []net.Conn
+}
+
+func startFakeAggregatorServer(t *testing.T, addr string, aggregator *fakeAggregator) *fakeAggregatorServer {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("Aggregator", aggregator); err != nil {
+		t.Fatalf("Error registering aggregator: %v", err)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+
+	server := &fakeAggregatorServer{listener: listener}
+	mux := http.NewServeMux()
+	mux.Handle(rpc.DefaultRPCPath, rpcServer)
+	httpServer := &http.Server{
+		Handler: mux,
+		ConnState: func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				server.mu.Lock()
+				server.conns = append(server.conns, conn)
+				server.mu.Unlock()
+			}
+		},
+	}
+	go func() { _ = httpServer.Serve(listener) }()
+	return server
+}
+
+func (s *fakeAggregatorServer) stop() {
+	_ = s.listener.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+func TestAggregatorRpcClientQueuesResponsesWhileDisconnected(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	aggregator := &fakeAggregator{}
+	server := startFakeAggregatorServer(t, "127.0.0.1:0", aggregator)
+	addr := server.listener.Addr().String()
+
+	client, err := NewAggregatorRpcClient([]string{addr}, logger)
+	if err != nil {
+		t.Fatalf("Error connecting to aggregator: %v", err)
+	}
+	client.reconnectMinDelay = 10 * time.Millisecond
+	client.reconnectMaxDelay = 50 * time.Millisecond
+	client.SendOperatorCapabilitiesToAggregator(&types.OperatorCapabilities{})
+	client.SendSignedTaskResponseToAggregator(&types.SignedTaskResponse{BatchMerkleRoot: [32]byte{1}})
+
+	// the aggregator restarts, responses signed meanwhile are sent once it's back
+	server.stop()
+	client.SendSignedTaskResponseToAggregator(&types.SignedTaskResponse{BatchMerkleRoot: [32]byte{2}})
+	client.SendSignedTaskResponseToAggregator(&types.SignedTaskResponse{BatchMerkleRoot: [32]byte{3}})
+	time.Sleep(100 * time.Millisecond)
+	server = startFakeAggregatorServer(t, addr, aggregator)
+	defer server.stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		responses, capabilities := aggregator.received()
+		if len(responses) == 3 {
+			if responses[1] != [32]byte{2} || responses[2] != [32]byte{3} {
+				t.Errorf("Expected queued responses to be sent in order, got %x", responses)
+			}
+			if capabilities != 2 {
+				t.Errorf("Expected capabilities to be sent again after reconnecting, got %d", capabilities)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected queued responses to be sent after reconnecting, got %d responses", len(responses))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	client.SendSignedTaskResponseToAggregator(&types.SignedTaskResponse{BatchMerkleRoot: [32]byte{4}})
+	if responses, _ := aggregator.received(); len(responses) != 4 {
+		t.Errorf("Expected responses to be sent directly once reconnected, got %d responses", len(responses))
+	}
+}
+
+func TestAggregatorRpcClientDropsOldestQueuedResponses(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	client := &AggregatorRpcClient{logger: logger}
+	for i := 0; i <= MaxPendingResponses; i++ {
+		client.queueResponse(&types.SignedTaskResponse{BatchMerkleRoot: [32]byte{byte(i)}})
+	}
+	if len(client.pendingResponses) != MaxPendingResponses {
+		t.Fatalf("Expected %d queued responses, got %d", MaxPendingResponses, len(client.pendingResponses))
+	}
+	if client.pendingResponses[0].BatchMerkleRoot != [32]byte{1} {
+		t.Errorf("Expected the oldest response to be dropped")
+	}
+}
+
+func TestAggregatorRpcClientFailsOverToStandbyAggregator(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	// the primary aggregator address is reserved, but nothing listens on it yet
+	unused, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	primaryAddr := unused.Addr().String()
+	_ = unused.Close()
+
+	standbyAggregator := &fakeAggregator{}
+	standby := startFakeAggregatorServer(t, "127.0.0.1:0", standbyAggregator)
+
+	client, err := NewAggregatorRpcClient([]string{primaryAddr, standby.listener.Addr().String()}, logger)
+	if err != nil {
+		t.Fatalf("Expected to connect to the standby aggregator, got %v", err)
+	}
+	client.reconnectMinDelay = 10 * time.Millisecond
+	client.reconnectMaxDelay = 50 * time.Millisecond
+	client.SendSignedTaskResponseToAggregator(&types.SignedTaskResponse{BatchMerkleRoot: [32]byte{1}})
+	if responses, _ := standbyAggregator.received(); len(responses) != 1 {
+		t.Fatalf("Expected the response to be sent to the standby aggregator, got %d responses", len(responses))
+	}
+
+	// once the primary aggregator is back, the client switches to it when the standby connection is lost
+	primaryAggregator := &fakeAggregator{}
+	primary := startFakeAggregatorServer(t, primaryAddr, primaryAggregator)
+	defer primary.stop()
+	standby.stop()
+	client.SendSignedTaskResponseToAggregator(&types.SignedTaskResponse{BatchMerkleRoot: [32]byte{2}})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if responses, _ := primaryAggregator.received(); len(responses) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the queued response to be sent to the primary aggregator")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAggregatorRpcClientStopsReconnectingOnClose(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	aggregator := &fakeAggregator{}
+	server := startFakeAggregatorServer(t, "127.0.0.1:0", aggregator)
+
+	client, err := NewAggregatorRpcClient([]string{server.listener.Addr().String()}, logger)
+	if err != nil {
+		t.Fatalf("Error connecting to aggregator: %v", err)
+	}
+	client.reconnectMinDelay = time.Hour
+	client.reconnectMaxDelay = time.Hour
+	server.stop()
+	client.SendSignedTaskResponseToAggregator(&types.SignedTaskResponse{BatchMerkleRoot: [32]byte{1}})
+
+	// the reconnection waits an hour, so it only returns in time if Close stops it
+	closed := make(chan error)
+	go func() { closed <- client.Close() }()
+	select {
+	case err = <-closed:
+		if err != nil {
+			t.Errorf("Unexpected error closing client: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected Close to stop the reconnection")
+	}
+	if client.Connected() {
+		t.Errorf("Expected the client to be disconnected once closed")
+	}
+
+	// once closed, lost connections aren't reconnected
+	client.reconnectInBackground()
+	client.mu.Lock()
+	reconnecting := client.reconnecting
+	client.mu.Unlock()
+	if reconnecting {
+		t.Errorf("Expected a closed client not to reconnect")
+	}
+}
+
+func TestNewAggregatorRpcClientFailsWithoutReachableAggregator(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	if _, err = NewAggregatorRpcClient(nil, logger); err == nil {
+		t.Errorf("Expected an error without aggregator addresses")
+	}
+}
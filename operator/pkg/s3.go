@@ -1,18 +1,25 @@
 package operator
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ugorji/go/codec"
 	"github.com/yetanotherco/aligned_layer/operator/merkle_tree"
 )
 
-func (o *Operator) getBatchFromDataService(ctx context.Context, batchURL string, expectedMerkleRoot [32]byte, maxRetries int, retryDelay time.Duration) ([]VerificationData, error) {
-	o.Logger.Infof("Getting batch from data service, batchURL: %s", batchURL)
+// fetchBatch requests the batch from the data service starting at offset, retrying with exponential backoff.
+// When resuming a download, etag makes sure the rest of the batch comes from the same object.
+// The caller must close the body of the returned response.
+func (o *Operator) fetchBatch(ctx context.Context, batchURL string, offset int64, etag string, maxRetries int, retryDelay time.Duration) (*http.Response, error) {
+	o.Logger.Infof("Getting batch from data service, batchURL: %s, offset: %d", batchURL, offset)
 
 	var resp *http.Response
 	var err error
@@ -34,9 +41,28 @@ func (o *Operator) getBatchFromDataService(ctx context.Context, batchURL string,
 		if err != nil {
 			return nil, err
 		}
+		// S3 only returns the object checksum when asked to
+		req.Header.Set(s3ChecksumModeHeader, "ENABLED")
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+		if etag != "" {
+			req.Header.Set("If-Match", etag)
+		}
 
+		// Downloads wait for a free connection, which is released once the body is closed
+		var release func()
+		release, err = o.downloadLimiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
 		resp, err = http.DefaultClient.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
+		if err != nil {
+			release()
+		} else {
+			resp.Body = o.downloadLimiter.limitBody(ctx, resp.Body, release)
+		}
+		if err == nil && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPartialContent) {
 			break // Successful request, exit retry loop
 		}
 
@@ -45,6 +71,9 @@ func (o *Operator) getBatchFromDataService(ctx context.Context, batchURL string,
 			if err != nil {
 				return nil, err
 			}
+			if resp.StatusCode == http.StatusPreconditionFailed {
+				return nil, fmt.Errorf("batch changed while it was downloaded")
+			}
 		}
 
 		o.Logger.Warnf("Error fetching batch from data service - (attempt %d): %v", attempt+1, err)
@@ -56,36 +85,143 @@ func (o *Operator) getBatchFromDataService(ctx context.Context, batchURL string,
 
 	// At this point, the HTTP request was successfull.
 
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			fmt.Println("error closing body: ", err)
-		}
-	}(resp.Body)
-
 	// Check if the response is OK
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		closeBody(resp.Body)
 		return nil, fmt.Errorf("error getting batch from data service: %s", resp.Status)
 	}
 
-	contentLength := resp.ContentLength
-	if contentLength > o.Config.Operator.MaxBatchSize {
+	batchSize := batchSizeFromResponse(resp)
+	if batchSize > o.Config.Operator.MaxBatchSize {
+		closeBody(resp.Body)
 		return nil, fmt.Errorf("proof size %d exceeds max batch size %d",
-			contentLength, o.Config.Operator.MaxBatchSize)
+			batchSize, o.Config.Operator.MaxBatchSize)
 	}
 
-	// Use io.LimitReader to limit the size of the response body
-	// This is to prevent the operator from downloading a larger than expected file
-	// + 1 is added to the contentLength to check if the response body is larger than expected
-	reader := io.LimitedReader{R: resp.Body, N: contentLength + 1}
-	batchBytes, err := io.ReadAll(&reader)
+	return resp, nil
+}
+
+// batchSizeFromResponse returns the size of the whole batch, which is in the Content-Range
+// header of partial responses, or -1 if unknown.
+func batchSizeFromResponse(resp *http.Response) int64 {
+	if resp.StatusCode != http.StatusPartialContent {
+		return resp.ContentLength
+	}
+	contentRange := resp.Header.Get("Content-Range")
+	slash := strings.LastIndex(contentRange, "/")
+	if slash < 0 {
+		return -1
+	}
+	size, err := strconv.ParseInt(contentRange[slash+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+func closeBody(body io.ReadCloser) {
+	err := body.Close()
+	if err != nil {
+		fmt.Println("error closing body: ", err)
+	}
+}
+
+// downloadBatch reads the whole batch, failing if it exceeds the max batch size, and decompresses it.
+// When the batch leaves are known, the download stops at the first proof not matching its leaf.
+func (o *Operator) downloadBatch(ctx context.Context, batchURL string, leaves [][32]byte, maxRetries int, retryDelay time.Duration) ([]byte, error) {
+	download, err := o.openBatchDownload(ctx, batchURL, maxRetries, retryDelay)
 	if err != nil {
 		return nil, err
 	}
+	defer download.Close()
 
-	// Check if the response body is larger than expected
-	if reader.N <= 0 {
-		return nil, fmt.Errorf("batch size exceeds max batch size %d", o.Config.Operator.MaxBatchSize)
+	limit := o.Config.Operator.MaxBatchSize
+	var batch []byte
+	if leaves != nil {
+		batch, err = readBatchValidatingLeaves(download, leaves, limit)
+	} else {
+		batch, err = io.ReadAll(&batchSizeLimitedReader{R: download, N: limit, max: limit})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decompressBatch(batch, limit)
+}
+
+// batchLeaves returns the leaves of the batch, or nil if they aren't available,
+// in which case the batch is only checked once it's fully downloaded.
+func (o *Operator) batchLeaves(ctx context.Context, batchURL string, expectedMerkleRoot [32]byte) [][32]byte {
+	if _, ok := batchLeavesURL(batchURL); !ok {
+		return nil
+	}
+	leaves, err := o.fetchBatchLeaves(ctx, batchURL, expectedMerkleRoot)
+	if err != nil {
+		o.Logger.Infof("Batch leaves not available, the batch will be checked once downloaded: %v", err)
+		return nil
+	}
+	return leaves
+}
+
+// getVerifiedBatchBytes returns the batch with the expected merkle root, from the batch cache if it's there.
+func (o *Operator) getVerifiedBatchBytes(ctx context.Context, batchURL string, expectedMerkleRoot [32]byte, maxRetries int, retryDelay time.Duration) ([]byte, error) {
+	// Batches cached or shared by peers while streaming are kept as downloaded, so they can be compressed
+	verify := func(batch []byte) ([]byte, bool) {
+		batch, err := decompressBatch(batch, o.Config.Operator.MaxBatchSize)
+		if err != nil {
+			return nil, false
+		}
+		merkleRootCheck, err := merkle_tree.VerifyMerkleTreeBatch(batch, expectedMerkleRoot)
+		return batch, err == nil && merkleRootCheck
+	}
+
+	if cachedBatch, ok := o.batchCache.Get(expectedMerkleRoot); ok {
+		o.Logger.Infof("Batch found in cache, verifying batch merkle tree...")
+		if batchBytes, ok := verify(cachedBatch); ok {
+			o.Logger.Infof("Batch merkle tree verified")
+			return batchBytes, nil
+		}
+		o.Logger.Warnf("Cached batch is corrupted, downloading it again")
+		o.batchCache.Remove(expectedMerkleRoot)
+	}
+
+	// Only read if the batch comes from peers, since the peer fetch may still be running otherwise
+	var peerBatchBytes []byte
+	fetchCtx, cancelFetch := context.WithCancel(ctx)
+	defer cancelFetch()
+	batchBytes, peerBatch, err := raceBatchWithPeers(ctx, o, expectedMerkleRoot, func(batch []byte) bool {
+		var ok bool
+		peerBatchBytes, ok = verify(batch)
+		return ok
+	}, func() ([]byte, error) {
+		return o.downloadVerifiedBatch(fetchCtx, batchURL, expectedMerkleRoot, maxRetries, retryDelay)
+	}, cancelFetch, func([]byte) {})
+	if err != nil {
+		return nil, err
+	}
+	if peerBatch != nil {
+		o.cacheAndAnnounceBatch(ctx, expectedMerkleRoot, peerBatch)
+		return peerBatchBytes, nil
+	}
+
+	o.cacheAndAnnounceBatch(ctx, expectedMerkleRoot, batchBytes)
+	return batchBytes, nil
+}
+
+// downloadVerifiedBatch downloads the batch from the data service and checks its merkle root.
+func (o *Operator) downloadVerifiedBatch(ctx context.Context, batchURL string, expectedMerkleRoot [32]byte, maxRetries int, retryDelay time.Duration) ([]byte, error) {
+	leaves := o.batchLeaves(ctx, batchURL, expectedMerkleRoot)
+	var batchBytes []byte
+	var err error
+	// A batch not matching its checksum or leaves is downloaded again, since it was corrupted on the way
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		batchBytes, err = o.downloadBatch(ctx, batchURL, leaves, maxRetries, retryDelay)
+		if !errors.Is(err, errBatchChecksumMismatch) && !errors.Is(err, errBatchLeafMismatch) {
+			break
+		}
+		o.Logger.Warnf("Downloaded batch is corrupted - (attempt %d): %v", attempt+1, err)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	// Checks if downloaded merkle root is the same as the expected one
@@ -95,6 +231,91 @@ func (o *Operator) getBatchFromDataService(ctx context.Context, batchURL string,
 		return nil, fmt.Errorf("Error while verifying merkle tree batch")
 	}
 	o.Logger.Infof("Batch merkle tree verified")
+	return batchBytes, nil
+}
+
+// raceBatchWithPeers requests the batch to the operators that announced it while fetch gets it from the
+// data service, so peers can only make the batch arrive sooner. The first one to succeed wins, and the
+// batch sent by peers is returned if they do. In that case, cancelFetch, which must cancel the context
+// used by fetch, stops the data service, and discard releases its result if it still succeeds.
+func raceBatchWithPeers[T any](ctx context.Context, o *Operator, expectedMerkleRoot [32]byte, verify func([]byte) bool, fetch func() (T, error), cancelFetch func(), discard func(T)) (T, []byte, error) {
+	if o.batchGossip == nil {
+		result, err := fetch()
+		return result, nil, err
+	}
+
+	type fetchResult struct {
+		value T
+		err   error
+	}
+	fetched := make(chan fetchResult, 1)
+	go func() {
+		value, err := fetch()
+		fetched <- fetchResult{value: value, err: err}
+	}()
+
+	peerCtx, cancelPeers := context.WithCancel(ctx)
+	defer cancelPeers()
+	peerBatches := make(chan []byte, 1)
+	go func() {
+		batch, _ := o.fetchBatchFromPeers(peerCtx, expectedMerkleRoot, verify)
+		peerBatches <- batch
+	}()
+
+	var none T
+	select {
+	case result := <-fetched:
+		if result.err == nil {
+			return result.value, nil, nil
+		}
+		// The batch can still come from peers if the data service failed
+		if batch := <-peerBatches; batch != nil {
+			return none, batch, nil
+		}
+		return none, nil, result.err
+	case batch := <-peerBatches:
+		if batch == nil {
+			result := <-fetched
+			return result.value, nil, result.err
+		}
+		cancelFetch()
+		go func() {
+			if result := <-fetched; result.err == nil {
+				discard(result.value)
+			}
+		}()
+		return none, batch, nil
+	}
+}
+
+// fetchBatchFromPeers gets the batch from the operators that announced it, if batch gossip is enabled.
+func (o *Operator) fetchBatchFromPeers(ctx context.Context, expectedMerkleRoot [32]byte, verify func([]byte) bool) ([]byte, bool) {
+	if o.batchGossip == nil {
+		return nil, false
+	}
+	batchBytes, err := o.batchGossip.Fetch(ctx, expectedMerkleRoot, o.Config.Operator.MaxBatchSize, verify)
+	if err != nil {
+		o.Logger.Debugf("Could not get batch from peers: %v", err)
+		return nil, false
+	}
+	o.Logger.Infof("Batch received from peers, merkle tree verified")
+	return batchBytes, true
+}
+
+// cacheAndAnnounceBatch adds a verified batch to the batch cache, and lets the other operators know they can request it.
+func (o *Operator) cacheAndAnnounceBatch(ctx context.Context, merkleRoot [32]byte, batchBytes []byte) {
+	if err := o.batchCache.Put(merkleRoot, batchBytes); err != nil {
+		o.Logger.Warnf("Could not add batch to the batch cache: %v", err)
+		return
+	}
+	o.batchGossip.Announce(ctx, merkleRoot)
+}
+
+func (o *Operator) getBatchFromDataService(ctx context.Context, batchURL string, expectedMerkleRoot [32]byte, maxRetries int, retryDelay time.Duration) ([]VerificationData, error) {
+	batchBytes, err := o.getVerifiedBatchBytes(ctx, batchURL, expectedMerkleRoot, maxRetries, retryDelay)
+	if err != nil {
+		return nil, err
+	}
 
 	var batch []VerificationData
 
@@ -116,3 +337,90 @@ func (o *Operator) getBatchFromDataService(ctx context.Context, batchURL string,
 
 	return batch, nil
 }
+
+// streamBatchFromDataService downloads the batch and verifies its proofs while they are decoded,
+// without holding the whole batch in memory. The batch merkle root is checked once the stream ends,
+// so the batch is only considered verified if both the root and every proof are valid.
+// When the batch leaves are available, each proof is also checked against its leaf as it's decoded.
+// The time spent waiting for the proofs to be downloaded is added to the batch trace.
+func (o *Operator) streamBatchFromDataService(ctx context.Context, batchURL string, expectedMerkleRoot [32]byte, maxRetries int, retryDelay time.Duration, batchTrace *BatchTrace) error {
+	var batch io.Reader
+	var cacheWriter *BatchCacheWriter
+	var leaves [][32]byte
+	cachedBatch, cached := o.batchCache.Open(expectedMerkleRoot)
+	if cached {
+		o.Logger.Infof("Batch found in cache")
+		defer cachedBatch.Close()
+		batch = cachedBatch
+	} else {
+		// The download must outlive the race, so it's only cancelled once the batch is verified or peers win
+		fetchCtx, cancelFetch := context.WithCancel(ctx)
+		defer cancelFetch()
+		var downloadLeaves [][32]byte
+		download, peerBatch, err := raceBatchWithPeers(ctx, o, expectedMerkleRoot, func(batch []byte) bool {
+			return verifyBatchBytesMerkleRoot(batch, expectedMerkleRoot, o.Config.Operator.MaxBatchSize)
+		}, func() (batchReader, error) {
+			downloadLeaves = o.batchLeaves(fetchCtx, batchURL, expectedMerkleRoot)
+			return o.openBatchDownload(fetchCtx, batchURL, maxRetries, retryDelay)
+		}, cancelFetch, func(download batchReader) { download.Close() })
+		if err != nil {
+			return err
+		}
+		if peerBatch != nil {
+			o.cacheAndAnnounceBatch(ctx, expectedMerkleRoot, peerBatch)
+			batch = bytes.NewReader(peerBatch)
+		} else {
+			leaves = downloadLeaves
+			defer download.Close()
+
+			// The batch is written to the cache while it's downloaded, and only kept if its merkle root is valid
+			cacheWriter, err = o.batchCache.NewWriter(expectedMerkleRoot)
+			if err != nil {
+				o.Logger.Warnf("Could not add batch to the batch cache: %v", err)
+			}
+			defer cacheWriter.Abort()
+			batch = io.TeeReader(download, cacheWriter)
+		}
+	}
+
+	// Compressed batches can't be checked against the content length until they are decompressed,
+	// so the decoder limits the decompressed size instead
+	limit := o.Config.Operator.MaxBatchSize
+	decoder, err := NewBatchStreamDecoder(io.LimitReader(batch, limit), limit)
+	if err != nil {
+		return err
+	}
+	defer decoder.Close()
+	if leaves != nil {
+		// A corrupted download fails at its first bad proof, instead of once the whole batch is read
+		decoder.ExpectLeaves(leaves)
+	}
+
+	next := func() (VerificationData, error) {
+		start := time.Now()
+		defer func() { batchTrace.ObserveDownload(time.Since(start)) }()
+		return decoder.Next()
+	}
+	if err = o.verifyBatchStream(next, batchTrace); err != nil {
+		return err
+	}
+
+	o.Logger.Infof("Verifying batch merkle tree...")
+	merkleRoot, err := decoder.MerkleRoot()
+	if err != nil || merkleRoot != expectedMerkleRoot {
+		if cached {
+			o.batchCache.Remove(expectedMerkleRoot)
+		}
+		return fmt.Errorf("Error while verifying merkle tree batch")
+	}
+	o.Logger.Infof("Batch merkle tree verified")
+
+	if cacheWriter != nil {
+		if err = cacheWriter.Commit(); err != nil {
+			o.Logger.Warnf("Could not add batch to the batch cache: %v", err)
+		} else {
+			o.batchGossip.Announce(ctx, expectedMerkleRoot)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+// SandboxedVerifierCommand is the hidden operator subcommand that verifies a single proof.
+// The operator runs it in a short-lived subprocess so a crash or memory blowup in a verifier
+// can't take down the operator process, which holds the BLS and ECDSA keys.
+const SandboxedVerifierCommand = "verify-proof"
+
+// SandboxedVerifierMaxMemoryFlag is the flag used to pass the max memory (in bytes) of the subprocess.
+const SandboxedVerifierMaxMemoryFlag = "max-memory"
+
+// SandboxedVerifierResultFd is the file descriptor the subprocess writes its result to.
+// Stdout is not used, since it's where the verifiers logs go.
+const SandboxedVerifierResultFd = 3
+
+type sandboxedVerificationResult struct {
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"`
+}
+
+// VerificationSandbox verifies proofs by re-executing the operator binary with SandboxedVerifierCommand.
+// The verification data is sent through the subprocess stdin and the result is read from SandboxedVerifierResultFd.
+type VerificationSandbox struct {
+	executable string
+	limiter    *VerificationLimiter
+	logger     sdklogging.Logger
+}
+
+func NewVerificationSandbox(limiter *VerificationLimiter, logger sdklogging.Logger) (*VerificationSandbox, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("could not find operator executable: %v", err)
+	}
+
+	return &VerificationSandbox{
+		executable: executable,
+		limiter:    limiter,
+		logger:     logger,
+	}, nil
+}
+
+// Verify verifies the proof in a subprocess, killing it if it exceeds the proving system timeout.
+func (s *VerificationSandbox) Verify(verificationData VerificationData) bool {
+	limits := s.limiter.LimitsFor(verificationData.ProvingSystemId)
+
+	ctx := context.Background()
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+
+	input, err := json.Marshal(verificationData)
+	if err != nil {
+		s.logger.Errorf("Could not encode verification data for sandboxed verifier: %v", err)
+		return false
+	}
+
+	cmd := exec.CommandContext(ctx, s.executable, SandboxedVerifierCommand,
+		"--"+SandboxedVerifierMaxMemoryFlag, strconv.FormatInt(limits.MaxMemory, 10))
+	// the subprocess doesn't inherit the environment, so it can't be tampered with
+	// through variables such as RISC0_DEV_MODE
+	cmd.Env = []string{}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	resultReader, resultWriter, err := os.Pipe()
+	if err != nil {
+		s.logger.Errorf("Could not create sandboxed verifier pipe: %v", err)
+		return false
+	}
+	defer resultReader.Close()
+	// ExtraFiles[0] is SandboxedVerifierResultFd in the subprocess
+	cmd.ExtraFiles = []*os.File{resultWriter}
+
+	err = cmd.Start()
+	// the subprocess has its own copy of the write end, closing ours lets the read below end when it exits
+	resultWriter.Close()
+	if err != nil {
+		s.logger.Errorf("Could not start sandboxed verifier: %v", err)
+		return false
+	}
+
+	var result sandboxedVerificationResult
+	decodeErr := json.NewDecoder(resultReader).Decode(&result)
+
+	if err = cmd.Wait(); err != nil {
+		s.logger.Errorf("Sandboxed %s verifier failed: %v", verificationData.ProvingSystemId.String(), err)
+		return false
+	}
+	if decodeErr != nil {
+		s.logger.Errorf("Could not decode sandboxed verifier result: %v", decodeErr)
+		return false
+	}
+	if result.Error != "" {
+		s.logger.Errorf("Sandboxed %s verifier failed: %s", verificationData.ProvingSystemId.String(), result.Error)
+		return false
+	}
+
+	return result.Verified
+}
+
+// RunSandboxedVerifier is the entrypoint of the sandboxed verifier subprocess.
+// It restricts the process resources, reads the verification data from input,
+// verifies it and writes the result to output.
+func RunSandboxedVerifier(maxMemory int64, input io.Reader, output io.Writer) error {
+	logger, err := config.NewLogger(sdklogging.Production)
+	if err != nil {
+		return err
+	}
+
+	// restrictions are applied before reading any untrusted input
+	if err = applySandboxRestrictions(maxMemory); err != nil {
+		return fmt.Errorf("could not apply sandbox restrictions: %v", err)
+	}
+
+	var result sandboxedVerificationResult
+	var verificationData VerificationData
+	if err = json.NewDecoder(input).Decode(&verificationData); err != nil {
+		result.Error = fmt.Sprintf("could not decode verification data: %v", err)
+	} else {
+		// the subprocess has no access to the operator configuration, only to the verifiers
+		sandboxedOperator := &Operator{Logger: logger}
+		result.Verified = sandboxedOperator.verifyProof(verificationData)
+	}
+
+	return json.NewEncoder(output).Encode(result)
+}
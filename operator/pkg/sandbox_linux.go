@@ -0,0 +1,116 @@
+//go:build linux
+
+package operator
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Syscalls a verifier has no reason to use. They are denied in the sandboxed verifier so
+// a compromised verifier can't reach the network, spawn programs or inspect other processes.
+var sandboxDeniedSyscalls = []uint32{
+	unix.SYS_SOCKET,
+	unix.SYS_SOCKETPAIR,
+	unix.SYS_CONNECT,
+	unix.SYS_BIND,
+	unix.SYS_LISTEN,
+	unix.SYS_ACCEPT,
+	unix.SYS_ACCEPT4,
+	unix.SYS_EXECVE,
+	unix.SYS_EXECVEAT,
+	unix.SYS_PTRACE,
+	unix.SYS_PROCESS_VM_READV,
+	unix.SYS_PROCESS_VM_WRITEV,
+}
+
+func applySandboxRestrictions(maxMemory int64) error {
+	if err := applySandboxRlimits(maxMemory); err != nil {
+		return err
+	}
+
+	// no core dumps nor other processes of the same user attaching to the verifier
+	if err := unix.Prctl(unix.PR_SET_DUMPABLE, 0, 0, 0, 0); err != nil {
+		return fmt.Errorf("PR_SET_DUMPABLE: %v", err)
+	}
+	// required to install the seccomp filter without privileges
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("PR_SET_NO_NEW_PRIVS: %v", err)
+	}
+
+	return installSeccompFilter()
+}
+
+func applySandboxRlimits(maxMemory int64) error {
+	if maxMemory > 0 {
+		if err := unix.Setrlimit(unix.RLIMIT_AS, &unix.Rlimit{Cur: uint64(maxMemory), Max: uint64(maxMemory)}); err != nil {
+			return fmt.Errorf("RLIMIT_AS: %v", err)
+		}
+	}
+	if err := unix.Setrlimit(unix.RLIMIT_CORE, &unix.Rlimit{Cur: 0, Max: 0}); err != nil {
+		return fmt.Errorf("RLIMIT_CORE: %v", err)
+	}
+	return nil
+}
+
+func installSeccompFilter() error {
+	var auditArch uint32
+	switch runtime.GOARCH {
+	case "amd64":
+		auditArch = unix.AUDIT_ARCH_X86_64
+	case "arm64":
+		auditArch = unix.AUDIT_ARCH_AARCH64
+	default:
+		// syscall numbers can't be checked on unknown architectures, rlimits still apply
+		return nil
+	}
+
+	// offsets of the fields of struct seccomp_data
+	const syscallNrOffset = 0
+	const archOffset = 4
+	// syscalls of the x32 ABI have this bit set, they are denied so the filter can't be bypassed through them
+	const x32SyscallBit = 0x40000000
+
+	deny := unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM)
+
+	filter := []unix.SockFilter{
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, archOffset),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, auditArch, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_KILL_PROCESS),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, syscallNrOffset),
+		bpfJump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, x32SyscallBit, 0, 1),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, deny),
+	}
+	for _, syscallNr := range sandboxDeniedSyscalls {
+		filter = append(filter,
+			bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, syscallNr, 0, 1),
+			bpfStmt(unix.BPF_RET|unix.BPF_K, deny),
+		)
+	}
+	filter = append(filter, bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ALLOW))
+
+	program := unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+
+	// TSYNC applies the filter to every thread of the process, not only the calling one
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER,
+		unix.SECCOMP_FILTER_FLAG_TSYNC, uintptr(unsafe.Pointer(&program)))
+	if errno != 0 {
+		return fmt.Errorf("seccomp: %v", errno)
+	}
+
+	return nil
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt uint8, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
@@ -0,0 +1,36 @@
+//go:build linux
+
+package operator
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// TestSandboxRestrictionsHelper is not a real test, it's run in a subprocess by
+// TestSandboxRestrictionsDenyNetwork, since restrictions can't be lifted once applied.
+func TestSandboxRestrictionsHelper(t *testing.T) {
+	if os.Getenv("SANDBOX_RESTRICTIONS_HELPER") != "1" {
+		t.Skip("only run as a subprocess")
+	}
+
+	if err := applySandboxRestrictions(0); err != nil {
+		t.Fatalf("Could not apply sandbox restrictions: %v", err)
+	}
+
+	_, err := unix.Socket(unix.AF_INET, unix.SOCK_STREAM, 0)
+	if err != unix.EPERM {
+		t.Fatalf("Expected socket creation to be denied, got %v", err)
+	}
+}
+
+func TestSandboxRestrictionsDenyNetwork(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSandboxRestrictionsHelper$")
+	cmd.Env = append(os.Environ(), "SANDBOX_RESTRICTIONS_HELPER=1")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("Sandboxed subprocess failed: %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package operator
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Only rlimits are available outside Linux, there is no seccomp equivalent.
+func applySandboxRestrictions(maxMemory int64) error {
+	if maxMemory > 0 {
+		if err := syscall.Setrlimit(syscall.RLIMIT_AS, &syscall.Rlimit{Cur: uint64(maxMemory), Max: uint64(maxMemory)}); err != nil {
+			return fmt.Errorf("RLIMIT_AS: %v", err)
+		}
+	}
+	if err := syscall.Setrlimit(syscall.RLIMIT_CORE, &syscall.Rlimit{Cur: 0, Max: 0}); err != nil {
+		return fmt.Errorf("RLIMIT_CORE: %v", err)
+	}
+	return nil
+}
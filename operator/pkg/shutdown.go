@@ -0,0 +1,93 @@
+package operator
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultShutdownDrainTimeout is the max time the operator waits for the batches it's
+// verifying to be signed before shutting down, if not set in the config.
+const DefaultShutdownDrainTimeout = 2 * time.Minute
+
+// handleBatchInBackground handles a batch in a new goroutine, tracking it so the operator
+// can wait for it to finish before shutting down.
+func (o *Operator) handleBatchInBackground(handle func()) {
+	o.inFlightBatches.Add(1)
+	o.status.batchesInProgress.Add(1)
+	go func() {
+		defer o.inFlightBatches.Done()
+		defer o.status.batchesInProgress.Add(-1)
+		handle()
+	}()
+}
+
+// shutdown stops the operator once no new batches are accepted. The batches in progress are
+// given up to the drain timeout to be verified and signed, so the operator doesn't miss their
+// signatures, and then the operator state is flushed and its servers are stopped.
+func (o *Operator) shutdown() error {
+	drainTimeout := o.Config.Operator.ShutdownDrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultShutdownDrainTimeout
+	}
+	if o.drain(drainTimeout) {
+		o.Logger.Info("Finished handling the batches in progress")
+	} else {
+		o.Logger.Warnf("Batches in progress didn't finish in %s, shutting down anyway", drainTimeout)
+	}
+
+	if err := o.verificationCache.Persist(); err != nil {
+		o.Logger.Warnf("Could not persist verification cache: %v", err)
+	}
+	if err := o.aggRpcClient.Close(); err != nil {
+		o.Logger.Warnf("Could not close aggregator connection: %v", err)
+	}
+	if err := o.batchGossip.Close(); err != nil {
+		o.Logger.Warnf("Could not stop batch gossip: %v", err)
+	}
+	if o.blsSigner != nil {
+		if err := o.blsSigner.Close(); err != nil {
+			o.Logger.Warnf("Could not close BLS signer: %v", err)
+		}
+	}
+	if o.statusServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := o.statusServer.Shutdown(ctx); err != nil {
+			o.Logger.Warnf("Could not stop status server: %v", err)
+		}
+	}
+	if o.Config.Operator.EnableMetrics {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := o.metrics.Shutdown(ctx); err != nil {
+			o.Logger.Warnf("Could not stop metrics server: %v", err)
+		}
+	}
+
+	o.Logger.Info("Operator stopped")
+	return nil
+}
+
+// drain waits up to timeout for the batches in progress to finish, returning whether they did.
+// Processed batches are still recorded meanwhile, so they aren't processed again after a restart.
+func (o *Operator) drain(timeout time.Duration) bool {
+	drained := make(chan struct{})
+	go func() {
+		o.inFlightBatches.Wait()
+		close(drained)
+	}()
+
+	deadline := time.After(timeout)
+	for {
+		select {
+		case <-drained:
+			return true
+		case <-deadline:
+			return false
+		case blockNumber := <-o.lastProcessedBatch.batchProcessedChan:
+			if err := o.UpdateLastProcessBatch(blockNumber); err != nil {
+				o.Logger.Errorf("Error while updating last process batch", "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package operator
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func newShutdownTestOperator(t *testing.T) *Operator {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	verificationCache, err := NewVerificationCache(10, "")
+	if err != nil {
+		t.Fatalf("could not create verification cache: %s", err)
+	}
+	operator := &Operator{
+		Logger:                    logger,
+		verificationCache:         verificationCache,
+		lastProcessedBatchLogFile: filepath.Join(t.TempDir(), "operator.last_processed_batch.json"),
+		lastProcessedBatch:        OperatorLastProcessedBatch{batchProcessedChan: make(chan uint32)},
+	}
+	return operator
+}
+
+func TestShutdownWaitsForBatchesInProgress(t *testing.T) {
+	operator := newShutdownTestOperator(t)
+	operator.Config.Operator.ShutdownDrainTimeout = 5 * time.Second
+
+	finished := make(chan struct{})
+	operator.handleBatchInBackground(func() {
+		time.Sleep(100 * time.Millisecond)
+		// the processed batch is recorded while draining
+		operator.lastProcessedBatch.batchProcessedChan <- 10
+		close(finished)
+	})
+
+	if err := operator.shutdown(); err != nil {
+		t.Fatalf("Unexpected error shutting down: %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Fatalf("Expected shutdown to wait for the batch in progress")
+	}
+	if operator.lastProcessedBatch.BlockNumber != 10 {
+		t.Errorf("Expected the processed batch to be recorded, got block %d", operator.lastProcessedBatch.BlockNumber)
+	}
+}
+
+func TestShutdownStopsAfterDrainTimeout(t *testing.T) {
+	operator := newShutdownTestOperator(t)
+	operator.Config.Operator.ShutdownDrainTimeout = 50 * time.Millisecond
+
+	release := make(chan struct{})
+	defer close(release)
+	operator.handleBatchInBackground(func() { <-release })
+
+	start := time.Now()
+	if err := operator.shutdown(); err != nil {
+		t.Fatalf("Unexpected error shutting down: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected shutdown to stop waiting after the drain timeout, took %s", elapsed)
+	}
+}
@@ -0,0 +1,145 @@
+package operator
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// statusChainHeadTimeout bounds the time spent getting the chain head for a status request
+const statusChainHeadTimeout = 5 * time.Second
+
+// OperatorStatus keeps track of the batches the operator handles, to be served by the status API.
+type OperatorStatus struct {
+	mu                 sync.Mutex
+	lastBatchSeen      *BatchStatus
+	lastBatchSigned    *BatchStatus
+	lastProcessedBlock uint32
+	// batchesInProgress is the number of batches being downloaded or verified
+	batchesInProgress atomic.Int64
+}
+
+// BatchStatus identifies a batch and when the operator handled it.
+type BatchStatus struct {
+	MerkleRoot  string    `json:"merkle_root"`
+	BlockNumber uint64    `json:"block_number"`
+	Time        time.Time `json:"time"`
+}
+
+// StatusResponse is the body of the status API /status endpoint.
+type StatusResponse struct {
+	OperatorId            string            `json:"operator_id"`
+	LastBatchSeen         *BatchStatus      `json:"last_batch_seen"`
+	LastBatchSigned       *BatchStatus      `json:"last_batch_signed"`
+	BatchesInProgress     int64             `json:"batches_in_progress"`
+	EnabledProvingSystems map[string]string `json:"enabled_proving_systems"`
+	AggregatorConnected   bool              `json:"aggregator_connected"`
+	LastProcessedBlock    uint32            `json:"last_processed_block"`
+	// ChainHead and SyncLagBlocks are omitted if the chain head couldn't be fetched
+	ChainHead     *uint64 `json:"chain_head,omitempty"`
+	SyncLagBlocks *uint64 `json:"sync_lag_blocks,omitempty"`
+}
+
+func newBatchStatus(batchMerkleRoot [32]byte, blockNumber uint64) *BatchStatus {
+	return &BatchStatus{
+		MerkleRoot:  "0x" + hex.EncodeToString(batchMerkleRoot[:]),
+		BlockNumber: blockNumber,
+		Time:        time.Now(),
+	}
+}
+
+func (s *OperatorStatus) batchSeen(batchMerkleRoot [32]byte, blockNumber uint64) {
+	batch := newBatchStatus(batchMerkleRoot, blockNumber)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastBatchSeen = batch
+}
+
+func (s *OperatorStatus) batchSigned(batchMerkleRoot [32]byte, blockNumber uint64) {
+	batch := newBatchStatus(batchMerkleRoot, blockNumber)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastBatchSigned = batch
+}
+
+func (s *OperatorStatus) batchProcessed(blockNumber uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if blockNumber > s.lastProcessedBlock {
+		s.lastProcessedBlock = blockNumber
+	}
+}
+
+// startStatusServer serves the operator status API at ipPortAddress. The /status endpoint
+// returns the operator state as JSON and /health returns 200 while the operator is running.
+func (o *Operator) startStatusServer(ipPortAddress string) <-chan error {
+	o.Logger.Infof("Starting status server at %v", ipPortAddress)
+	errC := make(chan error, 1)
+
+	o.statusServer = &http.Server{
+		Addr:           ipPortAddress,
+		Handler:        o.statusHandler(o.avsReader.BlockNumber),
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		IdleTimeout:    120 * time.Second,
+		MaxHeaderBytes: 1 << 20, // This is 1MB
+	}
+
+	go func() {
+		err := o.statusServer.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errC <- err
+		}
+	}()
+	return errC
+}
+
+// statusHandler returns the status API handler, getting the chain head with chainHead.
+func (o *Operator) statusHandler(chainHead func(context.Context) (uint64, error)) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), statusChainHeadTimeout)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(o.currentStatus(ctx, chainHead)); err != nil {
+			o.Logger.Warn("Could not write status response", "err", err)
+		}
+	})
+	return mux
+}
+
+func (o *Operator) currentStatus(ctx context.Context, chainHead func(context.Context) (uint64, error)) StatusResponse {
+	o.status.mu.Lock()
+	status := StatusResponse{
+		OperatorId:            "0x" + hex.EncodeToString(o.OperatorId[:]),
+		LastBatchSeen:         o.status.lastBatchSeen,
+		LastBatchSigned:       o.status.lastBatchSigned,
+		BatchesInProgress:     o.status.batchesInProgress.Load(),
+		EnabledProvingSystems: o.enabledVerifierVersions(),
+		AggregatorConnected:   o.aggRpcClient.Connected(),
+		LastProcessedBlock:    o.status.lastProcessedBlock,
+	}
+	o.status.mu.Unlock()
+
+	head, err := chainHead(ctx)
+	if err != nil {
+		o.Logger.Warn("Could not get chain head for status", "err", err)
+		return status
+	}
+	status.ChainHead = &head
+	syncLag := uint64(0)
+	if head > uint64(status.LastProcessedBlock) {
+		syncLag = head - uint64(status.LastProcessedBlock)
+	}
+	status.SyncLagBlocks = &syncLag
+	return status
+}
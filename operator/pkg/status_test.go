@@ -0,0 +1,92 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func TestStatusEndpoint(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	operator := &Operator{Logger: logger}
+	operator.status.batchProcessed(90)
+	operator.status.batchSeen([32]byte{1}, 95)
+	operator.status.batchSigned([32]byte{2}, 92)
+	release := make(chan struct{})
+	defer close(release)
+	operator.handleBatchInBackground(func() { <-release })
+
+	server := httptest.NewServer(operator.statusHandler(func(context.Context) (uint64, error) { return 100, nil }))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/status")
+	if err != nil {
+		t.Fatalf("Error getting status: %v", err)
+	}
+	defer resp.Body.Close()
+	var status StatusResponse
+	if err = json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("Error decoding status: %v", err)
+	}
+
+	if status.LastBatchSeen == nil || status.LastBatchSeen.BlockNumber != 95 {
+		t.Errorf("Expected the last batch seen at block 95, got %+v", status.LastBatchSeen)
+	}
+	if status.LastBatchSigned == nil || status.LastBatchSigned.MerkleRoot != "0x0200000000000000000000000000000000000000000000000000000000000000" {
+		t.Errorf("Expected the last batch signed to be 0x02.., got %+v", status.LastBatchSigned)
+	}
+	if status.BatchesInProgress != 1 {
+		t.Errorf("Expected 1 batch in progress, got %d", status.BatchesInProgress)
+	}
+	if status.SyncLagBlocks == nil || *status.SyncLagBlocks != 10 {
+		t.Errorf("Expected a sync lag of 10 blocks, got %v", status.SyncLagBlocks)
+	}
+	if len(status.EnabledProvingSystems) == 0 {
+		t.Errorf("Expected the enabled proving systems to be listed")
+	}
+	if status.AggregatorConnected {
+		t.Errorf("Expected the aggregator to be disconnected")
+	}
+}
+
+func TestStatusEndpointWithoutChainHead(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	operator := &Operator{Logger: logger}
+	server := httptest.NewServer(operator.statusHandler(func(context.Context) (uint64, error) {
+		return 0, errors.New("node unreachable")
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/status")
+	if err != nil {
+		t.Fatalf("Error getting status: %v", err)
+	}
+	defer resp.Body.Close()
+	var status StatusResponse
+	if err = json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("Error decoding status: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || status.ChainHead != nil || status.SyncLagBlocks != nil {
+		t.Errorf("Expected the status without the chain head, got %d %+v", resp.StatusCode, status)
+	}
+
+	health, err := http.Get(server.URL + "/health")
+	if err != nil {
+		t.Fatalf("Error getting health: %v", err)
+	}
+	health.Body.Close()
+	if health.StatusCode != http.StatusOK {
+		t.Errorf("Expected health check to succeed, got %d", health.StatusCode)
+	}
+}
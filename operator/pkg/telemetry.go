@@ -0,0 +1,241 @@
+package operator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+)
+
+const (
+	// BatchTraceEndpoint is the telemetry API endpoint the batch traces are sent to
+	BatchTraceEndpoint = "/api/operatorBatchTrace"
+	// telemetryRequestTimeout bounds the time spent sending a batch trace
+	telemetryRequestTimeout = 10 * time.Second
+)
+
+// Telemetry reports the time the operator spends on each batch to the telemetry API, where it's
+// joined with the aggregator trace of the batch by its merkle root. A nil telemetry reports nothing.
+type Telemetry struct {
+	baseUrl    string
+	apiToken   string
+	operatorId eigentypes.OperatorId
+	client     *http.Client
+	logger     logging.Logger
+}
+
+// BatchTrace is the time spent downloading the batch, verifying its proofs of each proving system
+// and submitting its signature to the aggregator. Its methods can be called concurrently, and do
+// nothing on a nil trace.
+type BatchTrace struct {
+	mutex      sync.Mutex
+	merkleRoot [32]byte
+	start      time.Time
+	// end is set once the operator is done with the batch, the trace may be sent later
+	end      time.Time
+	download time.Duration
+	// verification is the time spent verifying the proofs of each proving system, added up
+	verification map[string]time.Duration
+	proofs       map[string]int
+	// proofTraces are the per proof records of the zkVM proofs, which the time per proving system
+	// doesn't tell apart
+	proofTraces         []ProofTraceMessage
+	signatureSubmission time.Duration
+	signed              bool
+	// traceParent is the W3C traceparent of the aggregator span of the batch, if known
+	traceParent string
+}
+
+// BatchTraceMessage is the body of a batch trace sent to the telemetry API, with times in milliseconds
+type BatchTraceMessage struct {
+	MerkleRoot              string              `json:"merkle_root"`
+	OperatorId              string              `json:"operator_id"`
+	TotalTime               int64               `json:"total_time_ms"`
+	DownloadTime            int64               `json:"download_time_ms"`
+	VerificationTime        map[string]int64    `json:"verification_time_ms"`
+	Proofs                  map[string]int      `json:"proofs"`
+	SignatureSubmissionTime int64               `json:"signature_submission_time_ms"`
+	Signed                  bool                `json:"signed"`
+	TraceParent             string              `json:"traceparent,omitempty"`
+	ProofTraces             []ProofTraceMessage `json:"proof_traces,omitempty"`
+}
+
+// ProofTraceMessage is the verification of a single zkVM proof, identified by the hash of its program
+type ProofTraceMessage struct {
+	ProvingSystem    string `json:"proving_system"`
+	ProgramHash      string `json:"program_hash"`
+	VerificationTime int64  `json:"verification_time_ms"`
+	Result           string `json:"result"`
+}
+
+// NewTelemetry creates the telemetry of the operator, nil if the telemetry API address is empty.
+// The batch traces are authenticated with the API token, if not empty.
+func NewTelemetry(baseUrl string, apiToken string, operatorId eigentypes.OperatorId, logger logging.Logger) *Telemetry {
+	if baseUrl == "" {
+		return nil
+	}
+	return &Telemetry{
+		baseUrl:    baseUrl,
+		apiToken:   apiToken,
+		operatorId: operatorId,
+		client:     &http.Client{Timeout: telemetryRequestTimeout},
+		logger:     logger,
+	}
+}
+
+// NewBatchTrace starts the trace of the batch, nil if the telemetry is disabled
+func (t *Telemetry) NewBatchTrace(batchMerkleRoot [32]byte) *BatchTrace {
+	if t == nil {
+		return nil
+	}
+	return &BatchTrace{
+		merkleRoot:   batchMerkleRoot,
+		start:        time.Now(),
+		verification: make(map[string]time.Duration),
+		proofs:       make(map[string]int),
+	}
+}
+
+// ObserveDownload adds time spent waiting for the batch to be downloaded
+func (b *BatchTrace) ObserveDownload(duration time.Duration) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.download += duration
+}
+
+// ObserveVerification adds the time spent verifying a proof of the proving system
+func (b *BatchTrace) ObserveVerification(provingSystem string, duration time.Duration) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.verification[provingSystem] += duration
+	b.proofs[provingSystem]++
+}
+
+// ObserveProof records the verification of a single proof of the program with the given hash
+func (b *BatchTrace) ObserveProof(provingSystem string, programHash string, duration time.Duration, result string) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.proofTraces = append(b.proofTraces, ProofTraceMessage{
+		ProvingSystem:    provingSystem,
+		ProgramHash:      programHash,
+		VerificationTime: duration.Milliseconds(),
+		Result:           result,
+	})
+}
+
+// ObserveSignatureSubmission sets the time spent sending the signature of the batch to the aggregator
+func (b *BatchTrace) ObserveSignatureSubmission(duration time.Duration) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.signatureSubmission = duration
+	b.signed = true
+}
+
+// SetTraceParent sets the W3C traceparent of the aggregator span of the batch, so the operator
+// span is attached to it instead of being looked up by merkle root
+func (b *BatchTrace) SetTraceParent(traceParent string) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.traceParent = traceParent
+}
+
+// Finish stops the total time of the trace, which is otherwise measured until it's sent
+func (b *BatchTrace) Finish() {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.end.IsZero() {
+		b.end = time.Now()
+	}
+}
+
+func (b *BatchTrace) message(operatorId eigentypes.OperatorId) BatchTraceMessage {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	verificationTime := make(map[string]int64, len(b.verification))
+	for provingSystem, duration := range b.verification {
+		verificationTime[provingSystem] = duration.Milliseconds()
+	}
+	proofs := make(map[string]int, len(b.proofs))
+	for provingSystem, count := range b.proofs {
+		proofs[provingSystem] = count
+	}
+	end := b.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return BatchTraceMessage{
+		MerkleRoot:              "0x" + hex.EncodeToString(b.merkleRoot[:]),
+		OperatorId:              "0x" + hex.EncodeToString(operatorId[:]),
+		TotalTime:               end.Sub(b.start).Milliseconds(),
+		DownloadTime:            b.download.Milliseconds(),
+		VerificationTime:        verificationTime,
+		Proofs:                  proofs,
+		SignatureSubmissionTime: b.signatureSubmission.Milliseconds(),
+		Signed:                  b.signed,
+		TraceParent:             b.traceParent,
+		ProofTraces:             append([]ProofTraceMessage(nil), b.proofTraces...),
+	}
+}
+
+// SendBatchTrace sends the trace of the batch in the background. Failing to send it doesn't
+// affect the batch, it's only logged.
+func (t *Telemetry) SendBatchTrace(batchTrace *BatchTrace) {
+	if t == nil || batchTrace == nil {
+		return
+	}
+	message := batchTrace.message(t.operatorId)
+	go func() {
+		if err := t.send(message); err != nil {
+			t.logger.Warn("Could not send batch trace to the telemetry API", "merkle_root", message.MerkleRoot, "err", err)
+		}
+	}()
+}
+
+func (t *Telemetry) send(message BatchTraceMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, t.baseUrl+BatchTraceEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.apiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+t.apiToken)
+	}
+	res, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("telemetry API returned status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package operator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+)
+
+func TestTelemetrySendsBatchTrace(t *testing.T) {
+	messages := make(chan BatchTraceMessage, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer operator-token" {
+			t.Errorf("Expected the batch trace to be authenticated, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path != BatchTraceEndpoint {
+			t.Errorf("Expected the batch trace to be sent to %s, got %s", BatchTraceEndpoint, r.URL.Path)
+		}
+		var message BatchTraceMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("Could not decode batch trace: %v", err)
+		}
+		messages <- message
+	}))
+	defer server.Close()
+
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	telemetry := NewTelemetry(server.URL, "operator-token", eigentypes.OperatorId{2}, logger)
+
+	batchTrace := telemetry.NewBatchTrace([32]byte{1})
+	batchTrace.ObserveDownload(300 * time.Millisecond)
+	batchTrace.ObserveVerification("SP1", 100*time.Millisecond)
+	batchTrace.ObserveVerification("SP1", 200*time.Millisecond)
+	batchTrace.ObserveVerification("Risc0", 50*time.Millisecond)
+	batchTrace.ObserveProof("Jolt", "0x01", 40*time.Millisecond, "valid")
+	batchTrace.ObserveSignatureSubmission(20 * time.Millisecond)
+	telemetry.SendBatchTrace(batchTrace)
+
+	var message BatchTraceMessage
+	select {
+	case message = <-messages:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the batch trace to be sent")
+	}
+	if message.MerkleRoot != "0x0100000000000000000000000000000000000000000000000000000000000000" {
+		t.Errorf("Expected the batch trace to be keyed by the merkle root, got %s", message.MerkleRoot)
+	}
+	if message.DownloadTime != 300 || message.SignatureSubmissionTime != 20 || !message.Signed {
+		t.Errorf("Unexpected download or signature submission times: %+v", message)
+	}
+	if message.VerificationTime["SP1"] != 300 || message.VerificationTime["Risc0"] != 50 {
+		t.Errorf("Expected the verification times to be added up per proving system, got %v", message.VerificationTime)
+	}
+	if message.Proofs["SP1"] != 2 || message.Proofs["Risc0"] != 1 {
+		t.Errorf("Expected the proofs to be counted per proving system, got %v", message.Proofs)
+	}
+	expectedProofTrace := ProofTraceMessage{ProvingSystem: "Jolt", ProgramHash: "0x01", VerificationTime: 40, Result: "valid"}
+	if len(message.ProofTraces) != 1 || message.ProofTraces[0] != expectedProofTrace {
+		t.Errorf("Expected the Jolt proof to be traced with its program hash, got %+v", message.ProofTraces)
+	}
+}
+
+func TestTelemetryDisabled(t *testing.T) {
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	telemetry := NewTelemetry("", "", eigentypes.OperatorId{}, logger)
+	if telemetry != nil {
+		t.Fatalf("Expected no telemetry without an address")
+	}
+	// a nil telemetry and its nil traces do nothing
+	batchTrace := telemetry.NewBatchTrace([32]byte{1})
+	batchTrace.ObserveVerification("SP1", time.Second)
+	telemetry.SendBatchTrace(batchTrace)
+}
+
+func TestTelemetrySendsTraceParent(t *testing.T) {
+	messages := make(chan BatchTraceMessage, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message BatchTraceMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("Could not decode batch trace: %v", err)
+		}
+		messages <- message
+	}))
+	defer server.Close()
+
+	logger, _ := sdklogging.NewZapLogger(sdklogging.Development)
+	telemetry := NewTelemetry(server.URL, "", eigentypes.OperatorId{2}, logger)
+
+	batchTrace := telemetry.NewBatchTrace([32]byte{1})
+	batchTrace.Finish()
+	time.Sleep(50 * time.Millisecond)
+	traceParent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	batchTrace.SetTraceParent(traceParent)
+	telemetry.SendBatchTrace(batchTrace)
+
+	var message BatchTraceMessage
+	select {
+	case message = <-messages:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the batch trace to be sent")
+	}
+	if message.TraceParent != traceParent {
+		t.Errorf("Expected the batch trace to have traceparent %s, got %s", traceParent, message.TraceParent)
+	}
+	if message.TotalTime >= 50 {
+		t.Errorf("Expected the total time to stop once finished, got %dms", message.TotalTime)
+	}
+}
@@ -1,6 +1,8 @@
 package operator
 
 import (
+	"fmt"
+
 	"github.com/yetanotherco/aligned_layer/common"
 	"math/big"
 	"net/url"
@@ -15,6 +17,44 @@ func IsVerifierDisabled(disabledVerifiersBitmap *big.Int, verifierId common.Prov
 	return bit != 0
 }
 
+// ProvingSystemsFromStrings parses a list of proving system names into a set of proving system ids.
+func ProvingSystemsFromStrings(provingSystems []string) (map[common.ProvingSystemId]bool, error) {
+	provingSystemIds := make(map[common.ProvingSystemId]bool, len(provingSystems))
+	for _, provingSystem := range provingSystems {
+		provingSystemId, err := common.ProvingSystemIdFromString(provingSystem)
+		if err != nil {
+			return nil, err
+		}
+		provingSystemIds[provingSystemId] = true
+	}
+	return provingSystemIds, nil
+}
+
+// experimentalProvingSystems are the proving systems whose verifiers are still being evaluated.
+// Operators don't verify their proofs unless they are enabled in the operator config.
+var experimentalProvingSystems = map[common.ProvingSystemId]bool{
+	common.Binius: true,
+}
+
+func IsExperimentalProvingSystem(provingSystemId common.ProvingSystemId) bool {
+	return experimentalProvingSystems[provingSystemId]
+}
+
+// ExperimentalProvingSystemsFromStrings parses a list of experimental proving system names into a set of proving system ids.
+// It fails if any of them is not experimental, since those are always enabled.
+func ExperimentalProvingSystemsFromStrings(provingSystems []string) (map[common.ProvingSystemId]bool, error) {
+	provingSystemIds, err := ProvingSystemsFromStrings(provingSystems)
+	if err != nil {
+		return nil, err
+	}
+	for provingSystemId := range provingSystemIds {
+		if !IsExperimentalProvingSystem(provingSystemId) {
+			return nil, fmt.Errorf("%s is not an experimental proving system", provingSystemId.String())
+		}
+	}
+	return provingSystemIds, nil
+}
+
 func BaseUrlOnly(input string) (string, error) {
 	// https://gobyexample.com/url-parsing
 	u, err := url.Parse(input)
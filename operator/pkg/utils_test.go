@@ -4,7 +4,11 @@ import (
 	"math/big"
 	"testing"
 
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/metrics"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
 )
 
 func TestIsVerifierDisabled(t *testing.T) {
@@ -88,3 +92,74 @@ func TestBaseUrlOnlyHappyPath(t *testing.T) {
 		}
 	}
 }
+
+func TestProvingSystemsFromStrings(t *testing.T) {
+	provingSystems, err := ProvingSystemsFromStrings([]string{"Groth16Bls12_381", "SP1"})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing proving systems: %v", err)
+	}
+	if !provingSystems[common.Groth16Bls12_381] || !provingSystems[common.SP1] || provingSystems[common.Risc0] {
+		t.Errorf("Unexpected proving systems: %v", provingSystems)
+	}
+
+	if _, err = ProvingSystemsFromStrings([]string{"Unknown"}); err == nil {
+		t.Errorf("Expected an error for an unknown proving system")
+	}
+}
+
+func TestExperimentalProvingSystemsFromStrings(t *testing.T) {
+	provingSystems, err := ExperimentalProvingSystemsFromStrings([]string{"Binius"})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing experimental proving systems: %v", err)
+	}
+	if !provingSystems[common.Binius] {
+		t.Errorf("Unexpected experimental proving systems: %v", provingSystems)
+	}
+
+	if _, err = ExperimentalProvingSystemsFromStrings([]string{"SP1"}); err == nil {
+		t.Errorf("Expected an error for a proving system that is not experimental")
+	}
+}
+
+func TestBiniusProofVerifiesOnlyWhenEnabled(t *testing.T) {
+	files := verifiertest.ReadTestFiles(t, "generate_binius_fibonacci_proof",
+		"../../scripts/test_files/binius/binius_fibonacci.proof",
+		"../../scripts/test_files/binius/binius_fibonacci.pub",
+		"../../scripts/test_files/binius/binius_fibonacci.cs",
+	)
+	verificationData := VerificationData{
+		ProvingSystemId: common.Binius,
+		Proof:           files[0],
+		PubInput:        files[1],
+		VerificationKey: files[2],
+	}
+
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	limiter, err := NewVerificationLimiter(nil, false)
+	if err != nil {
+		t.Fatalf("Unexpected error creating limiter: %v", err)
+	}
+	newOperator := func(experimentalProvingSystems map[common.ProvingSystemId]bool) *Operator {
+		verificationCache, err := NewVerificationCache(10, "")
+		if err != nil {
+			t.Fatalf("could not create verification cache: %s", err)
+		}
+		return &Operator{
+			Logger:                     logger,
+			metrics:                    metrics.NewMetrics("", prometheus.NewRegistry(), logger),
+			verificationLimiter:        limiter,
+			verificationCache:          verificationCache,
+			experimentalProvingSystems: experimentalProvingSystems,
+		}
+	}
+
+	if newOperator(nil).verify(verificationData, big.NewInt(0), nil) {
+		t.Errorf("Binius proof should not verify unless Binius is enabled")
+	}
+	if !newOperator(map[common.ProvingSystemId]bool{common.Binius: true}).verify(verificationData, big.NewInt(0), nil) {
+		t.Errorf("Binius proof should verify once Binius is enabled")
+	}
+}
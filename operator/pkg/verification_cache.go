@@ -0,0 +1,175 @@
+package operator
+
+import (
+	"container/list"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const DefaultVerificationCacheSize = 10000
+
+type VerificationCacheKey [32]byte
+
+// VerificationCache is a bounded LRU set of proofs that were already verified successfully.
+// Only valid proofs are stored: a failed verification may come from a transient error, and
+// batches with invalid proofs are not signed anyway, so there is little to gain by caching them.
+type VerificationCache struct {
+	mu       sync.Mutex
+	size     int
+	entries  map[VerificationCacheKey]*list.Element
+	order    *list.List
+	filePath string
+}
+
+// NewVerificationCache creates a cache holding up to size entries. If size is not positive,
+// DefaultVerificationCacheSize is used. If filePath is not empty, the cache is loaded from it
+// and Persist writes the cache back to it.
+func NewVerificationCache(size int, filePath string) (*VerificationCache, error) {
+	if size <= 0 {
+		size = DefaultVerificationCacheSize
+	}
+
+	cache := &VerificationCache{
+		size:     size,
+		entries:  make(map[VerificationCacheKey]*list.Element),
+		order:    list.New(),
+		filePath: filePath,
+	}
+
+	if err := cache.load(); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// VerificationCacheKeyFor returns the cache key of a proof, built from the proving system
+// and the hashes of the proof, public input, verification key and program code.
+func VerificationCacheKeyFor(verificationData VerificationData) VerificationCacheKey {
+	var key VerificationCacheKey
+	hash := crypto.Keccak256(
+		[]byte{byte(verificationData.ProvingSystemId)},
+		crypto.Keccak256(verificationData.Proof),
+		crypto.Keccak256(verificationData.PubInput),
+		crypto.Keccak256(verificationData.VerificationKey),
+		crypto.Keccak256(verificationData.VmProgramCode),
+	)
+	copy(key[:], hash)
+	return key
+}
+
+// Contains returns true if the proof with the given key was already verified.
+func (c *VerificationCache) Contains(key VerificationCacheKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if ok {
+		c.order.MoveToFront(element)
+	}
+	return ok
+}
+
+// Add marks the proof with the given key as verified, evicting the least recently used entry if the cache is full.
+func (c *VerificationCache) Add(key VerificationCacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.add(key)
+}
+
+func (c *VerificationCache) add(key VerificationCacheKey) {
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(key)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(VerificationCacheKey))
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *VerificationCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Persist writes the cache to its file, if one was configured.
+// Entries are stored from least to most recently used, so loading them back keeps the LRU order.
+func (c *VerificationCache) Persist() error {
+	if c.filePath == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	keys := make([]string, 0, c.order.Len())
+	for element := c.order.Back(); element != nil; element = element.Prev() {
+		key := element.Value.(VerificationCacheKey)
+		keys = append(keys, hex.EncodeToString(key[:]))
+	}
+	c.mu.Unlock()
+
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("failed to marshal verification cache: %v", err)
+	}
+
+	// write to a temporary file first so a crash can't leave a truncated cache behind
+	tmpFilePath := c.filePath + ".tmp"
+	if err = os.WriteFile(tmpFilePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write verification cache: %v", err)
+	}
+	if err = os.Rename(tmpFilePath, c.filePath); err != nil {
+		return fmt.Errorf("failed to write verification cache: %v", err)
+	}
+
+	return nil
+}
+
+func (c *VerificationCache) load() error {
+	if c.filePath == "" {
+		return nil
+	}
+
+	// check if the directory exist, the file itself gets created on the first Persist
+	if _, err := os.Stat(filepath.Dir(c.filePath)); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(c.filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	if err = json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("failed to unmarshal verification cache: %v", err)
+	}
+
+	for _, encodedKey := range keys {
+		decodedKey, err := hex.DecodeString(encodedKey)
+		if err != nil || len(decodedKey) != len(VerificationCacheKey{}) {
+			return fmt.Errorf("invalid verification cache entry: %s", encodedKey)
+		}
+		var key VerificationCacheKey
+		copy(key[:], decodedKey)
+		c.add(key)
+	}
+
+	return nil
+}
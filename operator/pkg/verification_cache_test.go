@@ -0,0 +1,63 @@
+package operator
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+func TestVerificationCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache, err := NewVerificationCache(2, "")
+	if err != nil {
+		t.Fatalf("Unexpected error creating cache: %v", err)
+	}
+
+	first := VerificationCacheKeyFor(VerificationData{ProvingSystemId: common.SP1, Proof: []byte{1}})
+	second := VerificationCacheKeyFor(VerificationData{ProvingSystemId: common.SP1, Proof: []byte{2}})
+	third := VerificationCacheKeyFor(VerificationData{ProvingSystemId: common.SP1, Proof: []byte{3}})
+
+	cache.Add(first)
+	cache.Add(second)
+	// first becomes the most recently used, so second is evicted
+	cache.Contains(first)
+	cache.Add(third)
+
+	if !cache.Contains(first) || !cache.Contains(third) {
+		t.Errorf("Expected first and third entries to be cached")
+	}
+	if cache.Contains(second) {
+		t.Errorf("Expected second entry to be evicted")
+	}
+}
+
+func TestVerificationCacheKeyDependsOnProvingSystem(t *testing.T) {
+	sp1Key := VerificationCacheKeyFor(VerificationData{ProvingSystemId: common.SP1, Proof: []byte{1}})
+	risc0Key := VerificationCacheKeyFor(VerificationData{ProvingSystemId: common.Risc0, Proof: []byte{1}})
+
+	if sp1Key == risc0Key {
+		t.Errorf("Same proof for different proving systems should have different keys")
+	}
+}
+
+func TestVerificationCachePersistence(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "verification_cache.json")
+	key := VerificationCacheKeyFor(VerificationData{ProvingSystemId: common.Groth16Bn254, Proof: []byte{1}})
+
+	cache, err := NewVerificationCache(10, filePath)
+	if err != nil {
+		t.Fatalf("Unexpected error creating cache: %v", err)
+	}
+	cache.Add(key)
+	if err = cache.Persist(); err != nil {
+		t.Fatalf("Unexpected error persisting cache: %v", err)
+	}
+
+	loadedCache, err := NewVerificationCache(10, filePath)
+	if err != nil {
+		t.Fatalf("Unexpected error loading cache: %v", err)
+	}
+	if !loadedCache.Contains(key) {
+		t.Errorf("Expected persisted entry to be loaded")
+	}
+}
@@ -10,4 +10,6 @@ type VerificationData struct {
 	PubInput        []byte                 `json:"pub_input"`
 	VerificationKey []byte                 `json:"verification_key"`
 	VmProgramCode   []byte                 `json:"vm_program_code"`
+	// ProofGeneratorAddr is the hex encoded address of the proof generator, part of the proof commitment
+	ProofGeneratorAddr string `json:"proof_generator_addr"`
 }
@@ -0,0 +1,84 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+var (
+	ErrVerificationInputTooLarge = errors.New("verification input too large")
+	ErrVerificationTimedOut      = errors.New("verification timed out")
+)
+
+// VerificationLimiter enforces the configured per proving system limits on proof verifications,
+// so a single pathological proof can't hang the operator, which refuses the batch of the proof instead.
+type VerificationLimiter struct {
+	limits map[common.ProvingSystemId]config.VerificationLimits
+}
+
+// NewVerificationLimiter creates a limiter from a map of proving system name
+// (as in common.ProvingSystemIdFromString) to its verification limits.
+// The max memory can only be enforced on sandboxed verifiers, so it's an error to set it otherwise.
+func NewVerificationLimiter(limits map[string]config.VerificationLimits, sandboxed bool) (*VerificationLimiter, error) {
+	provingSystemLimits := make(map[common.ProvingSystemId]config.VerificationLimits)
+	for provingSystemName, limit := range limits {
+		provingSystemId, err := common.ProvingSystemIdFromString(provingSystemName)
+		if err != nil {
+			return nil, err
+		}
+		if limit.Timeout < 0 || limit.MaxInputSize < 0 || limit.MaxMemory < 0 {
+			return nil, fmt.Errorf("verification limits for %s must not be negative", provingSystemName)
+		}
+		if limit.MaxMemory > 0 && !sandboxed {
+			return nil, fmt.Errorf("max memory of %s is only enforced with sandbox_verifiers enabled", provingSystemName)
+		}
+		provingSystemLimits[provingSystemId] = limit
+	}
+
+	return &VerificationLimiter{limits: provingSystemLimits}, nil
+}
+
+// LimitsFor returns the verification limits of the given proving system.
+func (l *VerificationLimiter) LimitsFor(provingSystemId common.ProvingSystemId) config.VerificationLimits {
+	return l.limits[provingSystemId]
+}
+
+// Verify runs verifyFunc within the limits of the proof proving system.
+// Proofs whose input is bigger than the max input size are rejected without being verified.
+// If the verification doesn't finish within the timeout, the proof is considered invalid, so the
+// operator refuses to sign its whole batch instead of hanging on it, even if the proof is valid.
+// Note that FFI verifiers can't be interrupted, so unless they are sandboxed,
+// a timed out verification keeps running in the background.
+func (l *VerificationLimiter) Verify(data VerificationData, verifyFunc func(VerificationData) bool) (bool, error) {
+	limit, ok := l.limits[data.ProvingSystemId]
+	if !ok {
+		return verifyFunc(data), nil
+	}
+
+	if limit.MaxInputSize > 0 {
+		inputSize := int64(len(data.Proof) + len(data.PubInput) + len(data.VerificationKey) + len(data.VmProgramCode))
+		if inputSize > limit.MaxInputSize {
+			return false, fmt.Errorf("%w: input size %d exceeds max input size %d", ErrVerificationInputTooLarge, inputSize, limit.MaxInputSize)
+		}
+	}
+
+	if limit.Timeout == 0 {
+		return verifyFunc(data), nil
+	}
+
+	result := make(chan bool, 1)
+	go func() {
+		result <- verifyFunc(data)
+	}()
+
+	select {
+	case verified := <-result:
+		return verified, nil
+	case <-time.After(limit.Timeout):
+		return false, fmt.Errorf("%w after %v", ErrVerificationTimedOut, limit.Timeout)
+	}
+}
@@ -0,0 +1,115 @@
+package operator
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+func TestVerificationLimiterTimesOut(t *testing.T) {
+	limiter, err := NewVerificationLimiter(map[string]config.VerificationLimits{
+		"SP1": {Timeout: 10 * time.Millisecond},
+	}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error creating limiter: %v", err)
+	}
+
+	verified, err := limiter.Verify(VerificationData{ProvingSystemId: common.SP1}, func(VerificationData) bool {
+		time.Sleep(time.Second)
+		return true
+	})
+	if verified || !errors.Is(err, ErrVerificationTimedOut) {
+		t.Errorf("Expected verification to time out, got %v", err)
+	}
+}
+
+func TestVerificationLimiterRejectsBigInputs(t *testing.T) {
+	limiter, err := NewVerificationLimiter(map[string]config.VerificationLimits{
+		"SP1": {MaxInputSize: 4},
+	}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error creating limiter: %v", err)
+	}
+
+	verifyFunc := func(VerificationData) bool { return true }
+
+	verified, err := limiter.Verify(VerificationData{ProvingSystemId: common.SP1, Proof: make([]byte, 5)}, verifyFunc)
+	if verified || !errors.Is(err, ErrVerificationInputTooLarge) {
+		t.Errorf("Expected proof bigger than the max input size to be rejected, got %v", err)
+	}
+
+	// limits only apply to the configured proving system
+	verified, err = limiter.Verify(VerificationData{ProvingSystemId: common.Risc0, Proof: make([]byte, 5)}, verifyFunc)
+	if !verified || err != nil {
+		t.Errorf("Expected Risc0 proof to be verified, got %t: %v", verified, err)
+	}
+}
+
+func TestVerifyCountsFailuresByReason(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	limiter, err := NewVerificationLimiter(map[string]config.VerificationLimits{
+		"SP1": {MaxInputSize: 4},
+	}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error creating limiter: %v", err)
+	}
+	verificationCache, err := NewVerificationCache(10, "")
+	if err != nil {
+		t.Fatalf("could not create verification cache: %s", err)
+	}
+	reg := prometheus.NewRegistry()
+	operator := &Operator{
+		Logger:                 logger,
+		metrics:                metrics.NewMetrics("", reg, logger),
+		verificationLimiter:    limiter,
+		verificationCache:      verificationCache,
+		disabledProvingSystems: map[common.ProvingSystemId]bool{common.Risc0: true},
+	}
+
+	operator.verify(VerificationData{ProvingSystemId: common.SP1, Proof: make([]byte, 5)}, big.NewInt(0), nil)
+	operator.verify(VerificationData{ProvingSystemId: common.Risc0}, big.NewInt(0), nil)
+
+	failures := map[[2]string]float64{}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Error gathering metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "aligned_operator_verification_failures_count" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			failures[[2]string{labels["proving_system"], labels["reason"]}] = metric.GetCounter().GetValue()
+		}
+	}
+	if failures[[2]string{"SP1", VerificationFailureInputTooLarge}] != 1 {
+		t.Errorf("Expected an SP1 input too large failure, got %v", failures)
+	}
+	if failures[[2]string{"Risc0", VerificationFailureDisabledInConfig}] != 1 {
+		t.Errorf("Expected a Risc0 disabled in config failure, got %v", failures)
+	}
+}
+
+func TestVerificationLimiterRequiresSandboxForMaxMemory(t *testing.T) {
+	limits := map[string]config.VerificationLimits{"SP1": {MaxMemory: 1 << 30}}
+	if _, err := NewVerificationLimiter(limits, false); err == nil {
+		t.Errorf("Expected the max memory to be rejected without the sandbox")
+	}
+	if _, err := NewVerificationLimiter(limits, true); err != nil {
+		t.Errorf("Expected the max memory to be accepted with the sandbox, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package operator
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+// VerificationPool verifies the proofs of a batch concurrently using a bounded number of workers.
+// Besides the global limit, each proving system can have its own concurrency cap, so heavy
+// verifiers (e.g. SP1 or Risc0) can't take over every core while lighter proofs wait.
+type VerificationPool struct {
+	maxWorkers           int
+	provingSystemWorkers map[common.ProvingSystemId]chan struct{}
+}
+
+// NewVerificationPool creates a pool with maxWorkers workers. If maxWorkers is not positive,
+// the number of available CPUs is used. provingSystemLimits maps a proving system name
+// (as in common.ProvingSystemIdFromString) to the max amount of proofs of that system
+// that can be verified at the same time.
+func NewVerificationPool(maxWorkers int, provingSystemLimits map[string]int) (*VerificationPool, error) {
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.NumCPU()
+	}
+
+	provingSystemWorkers := make(map[common.ProvingSystemId]chan struct{})
+	for provingSystemName, limit := range provingSystemLimits {
+		provingSystemId, err := common.ProvingSystemIdFromString(provingSystemName)
+		if err != nil {
+			return nil, err
+		}
+		if limit <= 0 {
+			return nil, fmt.Errorf("worker limit for %s must be greater than 0", provingSystemName)
+		}
+		provingSystemWorkers[provingSystemId] = make(chan struct{}, limit)
+	}
+
+	return &VerificationPool{
+		maxWorkers:           maxWorkers,
+		provingSystemWorkers: provingSystemWorkers,
+	}, nil
+}
+
+// VerifyBatch runs verifyFunc for every element of the batch and returns true only if all of them verified.
+// Once a proof fails to verify, no new verifications are started, since the batch won't be signed anyway.
+func (p *VerificationPool) VerifyBatch(batch []VerificationData, verifyFunc func(VerificationData) bool) bool {
+	numWorkers := p.maxWorkers
+	if len(batch) < numWorkers {
+		numWorkers = len(batch)
+	}
+
+	next := 0
+	verified, _ := p.verifyAll(numWorkers, func() (VerificationData, error) {
+		if next == len(batch) {
+			return VerificationData{}, io.EOF
+		}
+		next++
+		return batch[next-1], nil
+	}, verifyFunc)
+	return verified
+}
+
+// VerifyStream runs verifyFunc for every element returned by next, until it returns io.EOF.
+// Elements are only requested when a worker is free, so at most one element per worker is held in memory.
+// Like VerifyBatch, it stops requesting elements once a proof fails to verify. An error is returned
+// if next fails, in which case the result is false.
+func (p *VerificationPool) VerifyStream(next func() (VerificationData, error), verifyFunc func(VerificationData) bool) (bool, error) {
+	return p.verifyAll(p.maxWorkers, next, verifyFunc)
+}
+
+func (p *VerificationPool) verifyAll(numWorkers int, next func() (VerificationData, error), verifyFunc func(VerificationData) bool) (bool, error) {
+	jobs := make(chan VerificationData)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for data := range jobs {
+				if failed.Load() {
+					continue
+				}
+				if !p.verify(data, verifyFunc) {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	var err error
+	for !failed.Load() {
+		var data VerificationData
+		data, err = next()
+		if err != nil {
+			break
+		}
+		jobs <- data
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err == io.EOF {
+		err = nil
+	}
+	return err == nil && !failed.Load(), err
+}
+
+func (p *VerificationPool) verify(data VerificationData, verifyFunc func(VerificationData) bool) bool {
+	if workers, ok := p.provingSystemWorkers[data.ProvingSystemId]; ok {
+		workers <- struct{}{}
+		defer func() { <-workers }()
+	}
+	return verifyFunc(data)
+}
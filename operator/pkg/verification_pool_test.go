@@ -0,0 +1,106 @@
+package operator
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+func TestVerificationPoolRespectsProvingSystemLimits(t *testing.T) {
+	pool, err := NewVerificationPool(8, map[string]int{"SP1": 2})
+	if err != nil {
+		t.Fatalf("Unexpected error creating pool: %v", err)
+	}
+
+	batch := make([]VerificationData, 10)
+	for i := range batch {
+		batch[i] = VerificationData{ProvingSystemId: common.SP1}
+	}
+
+	var running, maxRunning atomic.Int32
+	verified := pool.VerifyBatch(batch, func(data VerificationData) bool {
+		current := running.Add(1)
+		for {
+			max := maxRunning.Load()
+			if current <= max || maxRunning.CompareAndSwap(max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		return true
+	})
+
+	if !verified {
+		t.Errorf("Batch should have been verified")
+	}
+	if maxRunning.Load() > 2 {
+		t.Errorf("Expected at most 2 SP1 proofs verified concurrently, got %d", maxRunning.Load())
+	}
+}
+
+func TestVerificationPoolStopsAfterInvalidProof(t *testing.T) {
+	pool, err := NewVerificationPool(1, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating pool: %v", err)
+	}
+
+	batch := make([]VerificationData, 10)
+	var calls atomic.Int32
+	verified := pool.VerifyBatch(batch, func(data VerificationData) bool {
+		calls.Add(1)
+		return false
+	})
+
+	if verified {
+		t.Errorf("Batch with an invalid proof should not be verified")
+	}
+	if calls.Load() > 2 {
+		t.Errorf("Expected verification to stop after the first invalid proof, got %d calls", calls.Load())
+	}
+}
+
+func TestVerificationPoolRejectsUnknownProvingSystem(t *testing.T) {
+	_, err := NewVerificationPool(1, map[string]int{"Unknown": 1})
+	if err == nil {
+		t.Errorf("Expected an error for an unknown proving system")
+	}
+}
+
+func TestVerificationPoolStreamReturnsSourceError(t *testing.T) {
+	pool, err := NewVerificationPool(2, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating pool: %v", err)
+	}
+
+	sourceErr := errors.New("truncated batch")
+	requested := 0
+	verified, err := pool.VerifyStream(func() (VerificationData, error) {
+		requested++
+		if requested > 3 {
+			return VerificationData{}, sourceErr
+		}
+		return VerificationData{ProvingSystemId: common.SP1}, nil
+	}, func(data VerificationData) bool { return true })
+
+	if verified || err != sourceErr {
+		t.Errorf("Expected the stream error, got verified=%v, err=%v", verified, err)
+	}
+
+	requested = 0
+	verified, err = pool.VerifyStream(func() (VerificationData, error) {
+		requested++
+		if requested > 3 {
+			return VerificationData{}, io.EOF
+		}
+		return VerificationData{ProvingSystemId: common.SP1}, nil
+	}, func(data VerificationData) bool { return true })
+
+	if !verified || err != nil {
+		t.Errorf("Expected the stream to verify, got verified=%v, err=%v", verified, err)
+	}
+}
@@ -0,0 +1,170 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	DefaultVersionCheckInterval = 1 * time.Hour
+	versionCheckTimeout         = 30 * time.Second
+	maxVersionPolicySize        = 1 << 16
+)
+
+// VersionStatus is how the operator version compares to the published version policy
+type VersionStatus string
+
+const (
+	VersionUpToDate    VersionStatus = "up_to_date"
+	VersionOutdated    VersionStatus = "outdated"
+	VersionUnsupported VersionStatus = "unsupported"
+)
+
+// VersionPolicy is the minimum and recommended operator versions published by the AVS, signed
+// so that operators can check them regardless of where they are served from.
+type VersionPolicy struct {
+	MinimumVersion     string `json:"minimum_version"`
+	RecommendedVersion string `json:"recommended_version"`
+	// Signature is the EIP-191 signature of the VersionPolicy message
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Message returns the message the version policy signature signs.
+func (p *VersionPolicy) Message() []byte {
+	return []byte(fmt.Sprintf("Aligned operator versions\nminimum: %s\nrecommended: %s", p.MinimumVersion, p.RecommendedVersion))
+}
+
+// Verify checks the version policy is well formed and signed by signer.
+func (p *VersionPolicy) Verify(signer ethcommon.Address) error {
+	if !semver.IsValid(p.MinimumVersion) || !semver.IsValid(p.RecommendedVersion) {
+		return fmt.Errorf("invalid versions %q and %q", p.MinimumVersion, p.RecommendedVersion)
+	}
+	if len(p.Signature) != crypto.SignatureLength {
+		return errors.New("invalid signature length")
+	}
+	signature := make([]byte, crypto.SignatureLength)
+	copy(signature, p.Signature)
+	// signatures from wallets have a recovery id of 27 or 28
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	publicKey, err := crypto.SigToPub(accounts.TextHash(p.Message()), signature)
+	if err != nil {
+		return err
+	}
+	if crypto.PubkeyToAddress(*publicKey) != signer {
+		return errors.New("version policy not signed by the expected signer")
+	}
+	return nil
+}
+
+// Status returns how version compares to the version policy.
+func (p *VersionPolicy) Status(version string) VersionStatus {
+	switch {
+	case semver.Compare(version, p.MinimumVersion) < 0:
+		return VersionUnsupported
+	case semver.Compare(version, p.RecommendedVersion) < 0:
+		return VersionOutdated
+	default:
+		return VersionUpToDate
+	}
+}
+
+// versionOutdatedMetric maps a version status to the operator_version_outdated metric value.
+func versionOutdatedMetric(status VersionStatus) int {
+	switch status {
+	case VersionOutdated:
+		return 1
+	case VersionUnsupported:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func fetchVersionPolicy(ctx context.Context, url string, signer ethcommon.Address) (*VersionPolicy, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var policy VersionPolicy
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxVersionPolicySize)).Decode(&policy); err != nil {
+		return nil, err
+	}
+	if err = policy.Verify(signer); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// runVersionCheck periodically compares the operator version with the version policy published at
+// the configured URL, warning and reporting it in the metrics and to the operator tracker when outdated.
+func (o *Operator) runVersionCheck(ctx context.Context) {
+	interval := o.Config.Operator.VersionCheckInterval
+	if interval <= 0 {
+		interval = DefaultVersionCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastStatus VersionStatus
+	for {
+		status, err := o.checkVersion(ctx)
+		if err != nil {
+			o.Logger.Warnf("Could not check the operator version: %v", err)
+		} else if status != lastStatus {
+			lastStatus = status
+			if err = o.sendVersionTelemetry(ctx, status); err != nil {
+				o.Logger.Warnf("Could not send the version status to the operator tracker: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *Operator) checkVersion(ctx context.Context) (VersionStatus, error) {
+	if !semver.IsValid(o.Version) {
+		return "", fmt.Errorf("operator version %q is not a semantic version", o.Version)
+	}
+	policy, err := fetchVersionPolicy(ctx, o.Config.Operator.VersionCheckUrl, o.Config.Operator.VersionCheckSignerAddress)
+	if err != nil {
+		return "", err
+	}
+
+	status := policy.Status(o.Version)
+	o.metrics.SetOperatorVersionOutdated(versionOutdatedMetric(status))
+	switch status {
+	case VersionUnsupported:
+		o.Logger.Errorf("Operator version %s is older than the minimum supported version %s, upgrade it as soon as possible", o.Version, policy.MinimumVersion)
+	case VersionOutdated:
+		o.Logger.Warnf("Operator version %s is older than the recommended version %s, consider upgrading it", o.Version, policy.RecommendedVersion)
+	}
+	return status, nil
+}
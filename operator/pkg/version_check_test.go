@@ -0,0 +1,90 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+func TestVersionPolicyVerify(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	policy := &VersionPolicy{MinimumVersion: "v0.12.0", RecommendedVersion: "v0.14.0"}
+	policy.Signature, err = crypto.Sign(accounts.TextHash(policy.Message()), key)
+	if err != nil {
+		t.Fatalf("Error signing: %v", err)
+	}
+	// wallets sign with a recovery id of 27 or 28
+	policy.Signature[crypto.RecoveryIDOffset] += 27
+
+	if err = policy.Verify(crypto.PubkeyToAddress(key.PublicKey)); err != nil {
+		t.Errorf("Expected the version policy to be verified, got %v", err)
+	}
+	otherKey, _ := crypto.GenerateKey()
+	if err = policy.Verify(crypto.PubkeyToAddress(otherKey.PublicKey)); err == nil {
+		t.Errorf("Expected the version policy of another signer to be rejected")
+	}
+	policy.RecommendedVersion = "v0.11.0"
+	if err = policy.Verify(crypto.PubkeyToAddress(key.PublicKey)); err == nil {
+		t.Errorf("Expected a tampered version policy to be rejected")
+	}
+}
+
+func TestVersionPolicyStatus(t *testing.T) {
+	policy := &VersionPolicy{MinimumVersion: "v0.12.0", RecommendedVersion: "v0.14.0"}
+	for version, expected := range map[string]VersionStatus{
+		"v0.11.9": VersionUnsupported,
+		"v0.12.0": VersionOutdated,
+		"v0.13.5": VersionOutdated,
+		"v0.14.0": VersionUpToDate,
+		"v0.15.0": VersionUpToDate,
+	} {
+		if status := policy.Status(version); status != expected {
+			t.Errorf("Expected version %s to be %s, got %s", version, expected, status)
+		}
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %s", err)
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	policy := &VersionPolicy{MinimumVersion: "v0.12.0", RecommendedVersion: "v0.14.0"}
+	policy.Signature, err = crypto.Sign(accounts.TextHash(policy.Message()), key)
+	if err != nil {
+		t.Fatalf("Error signing: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(policy)
+	}))
+	defer server.Close()
+
+	operator := &Operator{Logger: logger, Version: "v0.13.0", metrics: metrics.NewMetrics("", prometheus.NewRegistry(), logger)}
+	operator.Config.Operator.VersionCheckUrl = server.URL
+	operator.Config.Operator.VersionCheckSignerAddress = crypto.PubkeyToAddress(key.PublicKey)
+
+	status, err := operator.checkVersion(context.Background())
+	if err != nil || status != VersionOutdated {
+		t.Errorf("Expected the operator version to be outdated, got %s: %v", status, err)
+	}
+
+	operator.Version = "dev"
+	if _, err = operator.checkVersion(context.Background()); err == nil {
+		t.Errorf("Expected versions that aren't semantic versions to not be checked")
+	}
+}
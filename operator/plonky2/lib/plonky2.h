@@ -0,0 +1,5 @@
+#include <stdbool.h>
+#include <stdint.h>
+
+int32_t verify_plonky2_proof_ffi(unsigned char *proof_buffer, uint32_t proof_len,
+                                 unsigned char *verifier_data_buffer, uint32_t verifier_data_len);
@@ -0,0 +1,89 @@
+use log::error;
+use plonky2::plonk::circuit_data::VerifierCircuitData;
+use plonky2::plonk::config::{GenericConfig, PoseidonGoldilocksConfig};
+use plonky2::plonk::proof::ProofWithPublicInputs;
+use plonky2::util::serialization::DefaultGateSerializer;
+
+// Proofs are expected to use the default recursion friendly configuration
+const D: usize = 2;
+type C = PoseidonGoldilocksConfig;
+type F = <C as GenericConfig<D>>::F;
+
+fn inner_verify_plonky2_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    verifier_data_bytes: *const u8,
+    verifier_data_len: u32,
+) -> bool {
+    if proof_bytes.is_null() || verifier_data_bytes.is_null() {
+        error!("Input buffer null");
+        return false;
+    }
+
+    if proof_len == 0 || verifier_data_len == 0 {
+        error!("Input buffer length zero size");
+        return false;
+    }
+
+    let proof_bytes = unsafe { std::slice::from_raw_parts(proof_bytes, proof_len as usize) };
+
+    let verifier_data_bytes =
+        unsafe { std::slice::from_raw_parts(verifier_data_bytes, verifier_data_len as usize) };
+
+    let Ok(verifier_data) = VerifierCircuitData::<F, C, D>::from_bytes(
+        verifier_data_bytes.to_vec(),
+        &DefaultGateSerializer,
+    ) else {
+        error!("Could not deserialize verifier circuit data");
+        return false;
+    };
+
+    if let Ok(proof) =
+        ProofWithPublicInputs::<F, C, D>::from_bytes(proof_bytes.to_vec(), &verifier_data.common)
+    {
+        return verifier_data.verify(proof).is_ok();
+    }
+
+    false
+}
+
+#[no_mangle]
+pub extern "C" fn verify_plonky2_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    verifier_data_bytes: *const u8,
+    verifier_data_len: u32,
+) -> i32 {
+    let result = std::panic::catch_unwind(|| {
+        inner_verify_plonky2_proof_ffi(
+            proof_bytes,
+            proof_len,
+            verifier_data_bytes,
+            verifier_data_len,
+        )
+    });
+
+    match result {
+        Ok(v) => v as i32,
+        Err(_) => -1,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn verify_plonky2_fails_with_malformed_proof() {
+        let proof = [1u8, 2, 3, 4];
+        let verifier_data = [5u8, 6, 7, 8];
+
+        let result = verify_plonky2_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            verifier_data.as_ptr(),
+            verifier_data.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+}
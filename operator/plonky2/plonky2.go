@@ -0,0 +1,46 @@
+package plonky2
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libplonky2_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -lplonky2_verifier_ffi
+
+#include "lib/plonky2.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// VerifyPlonky2Proof verifies a serialized ProofWithPublicInputs against the serialized VerifierCircuitData of its circuit.
+// Public inputs are part of the proof, so they are not passed separately.
+func VerifyPlonky2Proof(proofBuffer []byte, verifierDataBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(verifierDataBuffer) == 0 {
+		return isVerified, err
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying plonky2 proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	verifierDataPtr := (*C.uchar)(unsafe.Pointer(&verifierDataBuffer[0]))
+
+	r := (C.int32_t)(C.verify_plonky2_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), verifierDataPtr, (C.uint32_t)(len(verifierDataBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying plonky2 proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
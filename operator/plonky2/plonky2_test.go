@@ -0,0 +1,34 @@
+package plonky2_test
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/plonky2"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/plonky2/plonky2_fibonacci.proof"
+
+const VerifierDataFilePath = "../../scripts/test_files/plonky2/plonky2_fibonacci.vd"
+
+// readTestFiles reads the proof and verifier data generated with make generate_plonky2_fibonacci_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_plonky2_fibonacci_proof", ProofFilePath, VerifierDataFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return plonky2.VerifyPlonky2Proof(inputs[0], inputs[1])
+}
+
+func TestPlonky2ProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestPlonky2ProofWithTamperedVerifierDataDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the verifier data starts with the length of the merkle cap of the circuit constants and
+	// sigmas, followed by its hashes
+	inputs[1][8] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with other verifier data")
+}
@@ -0,0 +1,53 @@
+use p3_baby_bear::BabyBear;
+use p3_challenger::DuplexChallenger;
+use p3_dft::Radix2DitParallel;
+use p3_field::extension::BinomialExtensionField;
+use p3_field::Field;
+use p3_fri::{FriConfig, TwoAdicFriPcs, TwoAdicFriPcsConfig};
+use p3_keccak::Keccak256Hash;
+use p3_mds::coset_mds::CosetMds;
+use p3_merkle_tree::FieldMerkleTreeMmcs;
+use p3_poseidon::Poseidon;
+use p3_symmetric::{CompressionFunctionFromHasher, SerializingHasher32};
+use rand::SeedableRng;
+use rand_pcg::Pcg64;
+use valida_machine::StarkConfigImpl;
+
+// This is the configuration of the Valida CLI prover, proofs generated with a different one won't verify
+pub type Val = BabyBear;
+type Challenge = BinomialExtensionField<Val, 5>;
+type PackedChallenge = BinomialExtensionField<<Val as Field>::Packing, 5>;
+type Mds16 = CosetMds<Val, 16>;
+type Perm16 = Poseidon<Val, Mds16, 16, 5>;
+type MyHash = SerializingHasher32<Keccak256Hash>;
+type MyCompress = CompressionFunctionFromHasher<Val, MyHash, 2, 8>;
+type ValMmcs = FieldMerkleTreeMmcs<Val, MyHash, MyCompress, 8>;
+type ChallengeMmcs = p3_commit::ExtensionMmcs<Val, Challenge, ValMmcs>;
+type Dft = Radix2DitParallel;
+type Challenger = DuplexChallenger<Val, Perm16, 16>;
+type Pcs =
+    TwoAdicFriPcs<TwoAdicFriPcsConfig<Val, Challenge, Challenger, Dft, ValMmcs, ChallengeMmcs>>;
+pub type ValidaStarkConfig = StarkConfigImpl<Val, Challenge, PackedChallenge, Pcs, Challenger>;
+
+pub fn stark_config() -> ValidaStarkConfig {
+    // The Poseidon constants are derived from a fixed seed, the same one the prover uses
+    let mut rng = Pcg64::seed_from_u64(1);
+    let mds16 = Mds16::default();
+    let perm16 = Perm16::new_from_rng(4, 22, mds16, &mut rng);
+
+    let hash = MyHash::new(Keccak256Hash {});
+    let compress = MyCompress::new(hash);
+    let val_mmcs = ValMmcs::new(hash, compress);
+    let challenge_mmcs = ChallengeMmcs::new(val_mmcs.clone());
+
+    let fri_config = FriConfig {
+        log_blowup: 1,
+        num_queries: 40,
+        proof_of_work_bits: 8,
+        mmcs: challenge_mmcs,
+    };
+    let pcs = Pcs::new(fri_config, Dft::default(), val_mmcs);
+
+    let challenger = Challenger::new(perm16);
+    ValidaStarkConfig::new(pcs, challenger)
+}
@@ -0,0 +1,98 @@
+mod config;
+
+use config::{stark_config, Val, ValidaStarkConfig};
+use log::error;
+use valida_basic::BasicMachine;
+use valida_machine::{Machine, MachineProof};
+use valida_program::ProgramROM;
+
+fn inner_verify_valida_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    program_bytes: *const u8,
+    program_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+) -> bool {
+    if proof_bytes.is_null() || program_bytes.is_null() || pub_input_bytes.is_null() {
+        error!("Input buffer null");
+        return false;
+    }
+
+    if proof_len == 0 || program_len == 0 || pub_input_len == 0 {
+        error!("Input buffer length zero size");
+        return false;
+    }
+
+    let proof_bytes = unsafe { std::slice::from_raw_parts(proof_bytes, proof_len as usize) };
+
+    let program_bytes = unsafe { std::slice::from_raw_parts(program_bytes, program_len as usize) };
+
+    let pub_input_bytes =
+        unsafe { std::slice::from_raw_parts(pub_input_bytes, pub_input_len as usize) };
+
+    let Ok(proof) = ciborium::from_reader::<MachineProof<ValidaStarkConfig>, _>(proof_bytes) else {
+        error!("Could not deserialize Valida proof");
+        return false;
+    };
+
+    let mut machine = BasicMachine::<Val>::default();
+    let rom = ProgramROM::from_machine_code(program_bytes);
+    machine.program_mut().set_program_rom(&rom);
+    // The program output is a public value of the proof
+    machine.output_mut().values = pub_input_bytes
+        .iter()
+        .enumerate()
+        .map(|(i, byte)| (i as u32, *byte))
+        .collect();
+
+    machine.verify(&stark_config(), &proof).is_ok()
+}
+
+#[no_mangle]
+pub extern "C" fn verify_valida_proof_ffi(
+    proof_bytes: *const u8,
+    proof_len: u32,
+    program_bytes: *const u8,
+    program_len: u32,
+    pub_input_bytes: *const u8,
+    pub_input_len: u32,
+) -> i32 {
+    let result = std::panic::catch_unwind(|| {
+        inner_verify_valida_proof_ffi(
+            proof_bytes,
+            proof_len,
+            program_bytes,
+            program_len,
+            pub_input_bytes,
+            pub_input_len,
+        )
+    });
+
+    match result {
+        Ok(v) => v as i32,
+        Err(_) => -1,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn verify_valida_fails_with_malformed_proof() {
+        let proof = [1u8, 2, 3, 4];
+        let program = [0u8; 24];
+        let pub_input = [5u8, 6, 7, 8];
+
+        let result = verify_valida_proof_ffi(
+            proof.as_ptr(),
+            proof.len() as u32,
+            program.as_ptr(),
+            program.len() as u32,
+            pub_input.as_ptr(),
+            pub_input.len() as u32,
+        );
+        assert_eq!(result, 0)
+    }
+}
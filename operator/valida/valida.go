@@ -0,0 +1,53 @@
+package valida
+
+/*
+#cgo linux LDFLAGS: ${SRCDIR}/lib/libvalida_verifier_ffi.so -ldl -lrt -lm -Wl,--allow-multiple-definition
+#cgo darwin LDFLAGS: -L./lib -lvalida_verifier_ffi
+
+#include "lib/valida.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// InstructionSize is the size of a Valida machine code instruction, an opcode and five operands of 4 bytes each.
+const InstructionSize = 24
+
+// VerifyValidaProof verifies a Valida zkVM proof of the execution of the given program, in Valida machine code.
+// The public input is the output the program wrote during the execution.
+func VerifyValidaProof(proofBuffer []byte, programBuffer []byte, pubInputBuffer []byte) (isVerified bool, err error) {
+	// Here we define the return value on failure
+	isVerified = false
+	err = nil
+	if len(proofBuffer) == 0 || len(programBuffer) == 0 || len(pubInputBuffer) == 0 {
+		return isVerified, err
+	}
+	if len(programBuffer)%InstructionSize != 0 {
+		return isVerified, fmt.Errorf("Valida program size %d is not a multiple of the instruction size", len(programBuffer))
+	}
+
+	// This will catch any go panic
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("Panic was caught while verifying Valida proof: %s", rec)
+		}
+	}()
+
+	proofPtr := (*C.uchar)(unsafe.Pointer(&proofBuffer[0]))
+	programPtr := (*C.uchar)(unsafe.Pointer(&programBuffer[0]))
+	pubInputPtr := (*C.uchar)(unsafe.Pointer(&pubInputBuffer[0]))
+
+	r := (C.int32_t)(C.verify_valida_proof_ffi(proofPtr, (C.uint32_t)(len(proofBuffer)), programPtr, (C.uint32_t)(len(programBuffer)), pubInputPtr, (C.uint32_t)(len(pubInputBuffer))))
+
+	if r == -1 {
+		err = fmt.Errorf("Panic happened on FFI while verifying Valida proof")
+		return isVerified, err
+	}
+
+	isVerified = (r == 1)
+
+	return isVerified, err
+}
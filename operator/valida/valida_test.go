@@ -0,0 +1,49 @@
+package valida_test
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/operator/valida"
+	"github.com/yetanotherco/aligned_layer/operator/verifiertest"
+)
+
+const ProofFilePath = "../../scripts/test_files/valida/valida_fibonacci.proof"
+
+const ProgramFilePath = "../../scripts/test_files/valida/valida_fibonacci.bin"
+
+const PubInputFilePath = "../../scripts/test_files/valida/valida_fibonacci.pub"
+
+// readTestFiles reads the proof, program and output generated with make generate_valida_fibonacci_proof
+func readTestFiles(t *testing.T) [][]byte {
+	return verifiertest.ReadTestFiles(t, "generate_valida_fibonacci_proof", ProofFilePath, ProgramFilePath, PubInputFilePath)
+}
+
+func verify(inputs [][]byte) (bool, error) {
+	return valida.VerifyValidaProof(inputs[0], inputs[1], inputs[2])
+}
+
+func TestValidaProofVerification(t *testing.T) {
+	verifiertest.TestProofVerification(t, verify, readTestFiles(t))
+}
+
+func TestValidaProofOfAnotherProgramDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	// the first operand of the first instruction
+	inputs[1][4] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify for another program")
+}
+
+func TestValidaProofWithWrongOutputDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[2][0] ^= 1
+	verifiertest.ExpectNotVerified(t, verify, inputs, "proof should not verify with another output")
+}
+
+func TestValidaProofWithTruncatedProgramDoesNotVerify(t *testing.T) {
+	inputs := readTestFiles(t)
+
+	inputs[1] = inputs[1][:len(inputs[1])-1]
+	verifiertest.ExpectRejected(t, verify, inputs, "proof of a truncated program should not verify")
+}
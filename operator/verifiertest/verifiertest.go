@@ -0,0 +1,86 @@
+// Package verifiertest holds the checks shared by the tests of the verifier bindings, which run
+// against the proofs generated in scripts/test_files.
+package verifiertest
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// VerifyFunc verifies the proof in inputs[0] with the rest of the inputs, in the order the
+// verifier binding takes them.
+type VerifyFunc func(inputs [][]byte) (bool, error)
+
+// ReadTestFiles reads the files generated with the given make target, failing the test if any of
+// them is missing.
+func ReadTestFiles(t *testing.T, makeTarget string, paths ...string) [][]byte {
+	t.Helper()
+	files := make([][]byte, len(paths))
+	for i, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("could not open test file, generate it with make %s: %s", makeTarget, err)
+		}
+		files[i] = data
+	}
+	return files
+}
+
+// Clone returns a deep copy of the inputs, so a test can tamper one of them.
+func Clone(inputs [][]byte) [][]byte {
+	cloned := make([][]byte, len(inputs))
+	for i, input := range inputs {
+		cloned[i] = bytes.Clone(input)
+	}
+	return cloned
+}
+
+// TestProofVerification checks that the proof in inputs[0] verifies, and that it doesn't once
+// tampered, truncated or emptied.
+func TestProofVerification(t *testing.T, verify VerifyFunc, inputs [][]byte) {
+	t.Run("Verifies", func(t *testing.T) {
+		verified, err := verify(Clone(inputs))
+		if err != nil || !verified {
+			t.Errorf("proof did not verify: %v", err)
+		}
+	})
+
+	t.Run("TamperedProof", func(t *testing.T) {
+		tampered := Clone(inputs)
+		tampered[0][len(tampered[0])/2] ^= 1
+		ExpectNotVerified(t, verify, tampered, "tampered proof should not verify")
+	})
+
+	t.Run("TruncatedProof", func(t *testing.T) {
+		truncated := Clone(inputs)
+		truncated[0] = truncated[0][:len(truncated[0])/2]
+		ExpectNotVerified(t, verify, truncated, "truncated proof should not verify")
+	})
+
+	t.Run("EmptyProof", func(t *testing.T) {
+		empty := Clone(inputs)
+		empty[0] = []byte{}
+		ExpectNotVerified(t, verify, empty, "empty proof should not verify")
+	})
+}
+
+// ExpectNotVerified fails the test with the given message if the inputs verify or the verifier
+// returns an error, which it only does for inputs it rejects before verifying.
+func ExpectNotVerified(t *testing.T, verify VerifyFunc, inputs [][]byte, message string) {
+	t.Helper()
+	verified, err := verify(inputs)
+	if err != nil || verified {
+		t.Errorf("%s, err: %v", message, err)
+	}
+}
+
+// ExpectRejected fails the test with the given message unless the verifier rejects the inputs
+// with an error.
+func ExpectRejected(t *testing.T, verify VerifyFunc, inputs [][]byte, message string) {
+	t.Helper()
+	verified, err := verify(inputs)
+	if err == nil || verified {
+		t.Errorf("%s", message)
+	}
+}
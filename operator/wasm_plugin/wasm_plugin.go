@@ -0,0 +1,180 @@
+// Package wasm_plugin runs verifiers compiled to WASM, so operators can verify new proving
+// systems without recompiling the operator.
+//
+// Plugins must export `memory`, `alloc(len i32) i32`, `aligned_abi_version() i32` and
+// `verify(proof_ptr, proof_len, pub_input_ptr, pub_input_len, vk_ptr, vk_len i32) i32`,
+// which returns 1 for valid proofs, 0 for invalid ones and a negative value on errors.
+// Plugins can't import anything from the host.
+package wasm_plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+// AbiVersion is the version of the ABI plugins must declare through their aligned_abi_version export.
+const AbiVersion = 1
+
+// HashSize is the size of the plugin module hash the verification key of WasmPlugin proofs starts with.
+const HashSize = 32
+
+const (
+	// Max time a plugin can take to verify a single proof
+	verificationTimeout = 2 * time.Minute
+	// Max memory of a plugin instance, 4 GiB in 64 KiB pages
+	maxMemoryPages = 65536
+)
+
+// Plugins holds the compiled verifier plugins, indexed by the keccak256 hash of their module.
+type Plugins struct {
+	runtime wazero.Runtime
+	modules map[[HashSize]byte]wazero.CompiledModule
+}
+
+// LoadPlugins compiles the given plugins, failing if any of them doesn't match its allowlisted hash.
+func LoadPlugins(pluginConfigs []config.WasmPluginConfig) (*Plugins, error) {
+	ctx := context.Background()
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(maxMemoryPages))
+
+	plugins := &Plugins{runtime: runtime, modules: make(map[[HashSize]byte]wazero.CompiledModule)}
+	for _, pluginConfig := range pluginConfigs {
+		module, err := os.ReadFile(pluginConfig.Path)
+		if err != nil {
+			plugins.Close()
+			return nil, fmt.Errorf("error reading WASM plugin %s: %w", pluginConfig.Path, err)
+		}
+		if err = plugins.add(ctx, module, pluginConfig.Hash); err != nil {
+			plugins.Close()
+			return nil, fmt.Errorf("error loading WASM plugin %s: %w", pluginConfig.Path, err)
+		}
+	}
+	return plugins, nil
+}
+
+func (p *Plugins) add(ctx context.Context, module []byte, allowlistedHash string) error {
+	expectedHash, err := hex.DecodeString(strings.TrimPrefix(allowlistedHash, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid hash %s: %w", allowlistedHash, err)
+	}
+	var hash [HashSize]byte
+	copy(hash[:], crypto.Keccak256(module))
+	if !bytes.Equal(hash[:], expectedHash) {
+		return fmt.Errorf("module hash 0x%x doesn't match the allowlisted hash %s", hash, allowlistedHash)
+	}
+
+	compiled, err := p.runtime.CompileModule(ctx, module)
+	if err != nil {
+		return fmt.Errorf("error compiling module: %w", err)
+	}
+	if len(compiled.ImportedFunctions()) != 0 || len(compiled.ImportedMemories()) != 0 {
+		return fmt.Errorf("module must not import host functions or memories")
+	}
+	for _, export := range []string{"alloc", "aligned_abi_version", "verify"} {
+		if _, ok := compiled.ExportedFunctions()[export]; !ok {
+			return fmt.Errorf("module doesn't export %s", export)
+		}
+	}
+	if _, ok := compiled.ExportedMemories()["memory"]; !ok {
+		return fmt.Errorf("module doesn't export memory")
+	}
+
+	p.modules[hash] = compiled
+	return nil
+}
+
+// Len returns the number of loaded plugins.
+func (p *Plugins) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.modules)
+}
+
+// Verify verifies a proof with the plugin whose hash is at the start of the verification key,
+// the rest of the verification key being passed to the plugin. Each verification runs in a
+// fresh instance of the plugin, so plugins can't keep state between proofs.
+func (p *Plugins) Verify(proof []byte, pubInput []byte, verificationKey []byte) (bool, error) {
+	if len(verificationKey) < HashSize {
+		return false, fmt.Errorf("verification key doesn't include the WASM plugin hash")
+	}
+	var hash [HashSize]byte
+	copy(hash[:], verificationKey[:HashSize])
+	compiled, ok := p.modules[hash]
+	if !ok {
+		return false, fmt.Errorf("WASM plugin 0x%x is not enabled", hash)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verificationTimeout)
+	defer cancel()
+
+	// Instances are anonymous so a plugin can verify several proofs in parallel
+	module, err := p.runtime.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return false, fmt.Errorf("error instantiating WASM plugin 0x%x: %w", hash, err)
+	}
+	defer module.Close(ctx)
+
+	result, err := module.ExportedFunction("aligned_abi_version").Call(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error calling aligned_abi_version: %w", err)
+	}
+	if abiVersion := api.DecodeI32(result[0]); abiVersion != AbiVersion {
+		return false, fmt.Errorf("unsupported WASM plugin ABI version %d", abiVersion)
+	}
+
+	args := make([]uint64, 0, 6)
+	for _, input := range [][]byte{proof, pubInput, verificationKey[HashSize:]} {
+		ptr, err := writeInput(ctx, module, input)
+		if err != nil {
+			return false, err
+		}
+		args = append(args, api.EncodeI32(ptr), api.EncodeI32(int32(len(input))))
+	}
+
+	result, err = module.ExportedFunction("verify").Call(ctx, args...)
+	if err != nil {
+		return false, fmt.Errorf("error calling verify: %w", err)
+	}
+	switch code := api.DecodeI32(result[0]); code {
+	case 1:
+		return true, nil
+	case 0:
+		return false, nil
+	default:
+		return false, fmt.Errorf("verify returned error code %d", code)
+	}
+}
+
+// writeInput copies input to a buffer allocated by the plugin and returns its pointer.
+func writeInput(ctx context.Context, module api.Module, input []byte) (int32, error) {
+	if len(input) > math.MaxInt32 {
+		return 0, fmt.Errorf("input of %d bytes is too large for a WASM plugin", len(input))
+	}
+	result, err := module.ExportedFunction("alloc").Call(ctx, api.EncodeI32(int32(len(input))))
+	if err != nil {
+		return 0, fmt.Errorf("error calling alloc: %w", err)
+	}
+	ptr := api.DecodeI32(result[0])
+	if !module.Memory().Write(uint32(ptr), input) {
+		return 0, fmt.Errorf("alloc returned out of range buffer 0x%x of %d bytes", ptr, len(input))
+	}
+	return ptr, nil
+}
+
+// Close releases the compiled plugins.
+func (p *Plugins) Close() {
+	_ = p.runtime.Close(context.Background())
+}
@@ -0,0 +1,86 @@
+package wasm_plugin
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+// testPlugin is a minimal plugin following the ABI. Proofs are valid if their first byte
+// matches the first byte of the plugin verification key, and an empty key is an error.
+var testPlugin = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	// Types: () -> i32, (i32) -> i32, (i32 x 6) -> i32
+	0x01, 0x14, 0x03,
+	0x60, 0x00, 0x01, 0x7f,
+	0x60, 0x01, 0x7f, 0x01, 0x7f,
+	0x60, 0x06, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x01, 0x7f,
+	// Functions
+	0x03, 0x04, 0x03, 0x00, 0x01, 0x02,
+	// Memory of one page
+	0x05, 0x03, 0x01, 0x00, 0x01,
+	// Next free address of the bump allocator, starting at 1024
+	0x06, 0x07, 0x01, 0x7f, 0x01, 0x41, 0x80, 0x08, 0x0b,
+	// Exports
+	0x07, 0x31, 0x04,
+	0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+	0x05, 'a', 'l', 'l', 'o', 'c', 0x00, 0x01,
+	0x13, 'a', 'l', 'i', 'g', 'n', 'e', 'd', '_', 'a', 'b', 'i', '_', 'v', 'e', 'r', 's', 'i', 'o', 'n', 0x00, 0x00,
+	0x06, 'v', 'e', 'r', 'i', 'f', 'y', 0x00, 0x02,
+	// Code
+	0x0a, 0x29, 0x03,
+	// aligned_abi_version: return 1
+	0x04, 0x00, 0x41, 0x01, 0x0b,
+	// alloc: return next and add len to it
+	0x0b, 0x00, 0x23, 0x00, 0x23, 0x00, 0x20, 0x00, 0x6a, 0x24, 0x00, 0x0b,
+	// verify: if vk_len == 0 return -1, else return proof[0] == vk[0]
+	0x16, 0x00, 0x20, 0x05, 0x45, 0x04, 0x7f, 0x41, 0x7f, 0x05,
+	0x20, 0x00, 0x2d, 0x00, 0x00, 0x20, 0x04, 0x2d, 0x00, 0x00, 0x46, 0x0b, 0x0b,
+}
+
+func loadTestPlugin(t *testing.T, hash string) (*Plugins, error) {
+	path := filepath.Join(t.TempDir(), "plugin.wasm")
+	if err := os.WriteFile(path, testPlugin, 0644); err != nil {
+		t.Fatalf("Error writing test plugin: %v", err)
+	}
+	return LoadPlugins([]config.WasmPluginConfig{{Path: path, Hash: hash}})
+}
+
+func TestVerify(t *testing.T) {
+	hash := crypto.Keccak256(testPlugin)
+	plugins, err := loadTestPlugin(t, "0x"+hex.EncodeToString(hash))
+	if err != nil {
+		t.Fatalf("Error loading test plugin: %v", err)
+	}
+	defer plugins.Close()
+
+	verified, err := plugins.Verify([]byte{7, 1}, nil, append(hash, 7))
+	if err != nil || !verified {
+		t.Errorf("Expected valid proof, got %t, %v", verified, err)
+	}
+
+	verified, err = plugins.Verify([]byte{8, 1}, []byte{1}, append(hash, 7))
+	if err != nil || verified {
+		t.Errorf("Expected invalid proof, got %t, %v", verified, err)
+	}
+
+	if _, err = plugins.Verify([]byte{7}, nil, hash); err == nil {
+		t.Errorf("Expected an error for a plugin error code")
+	}
+
+	unknownHash := crypto.Keccak256([]byte("unknown"))
+	if _, err = plugins.Verify([]byte{7}, nil, append(unknownHash, 7)); err == nil {
+		t.Errorf("Expected an error for a plugin that is not loaded")
+	}
+}
+
+func TestLoadPluginsRejectsHashMismatch(t *testing.T) {
+	hash := crypto.Keccak256([]byte("another plugin"))
+	if _, err := loadTestPlugin(t, hex.EncodeToString(hash)); err == nil {
+		t.Errorf("Expected an error for a plugin not matching its allowlisted hash")
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/reference_batcher/pkg"
+)
+
+var (
+	// Version is the version of the binary.
+	Version   string
+	GitCommit string
+	GitDate   string
+)
+
+var flags = []cli.Flag{
+	config.ConfigFileFlag,
+}
+
+var (
+	sourceEthRpcUrlFlag = &cli.StringFlag{
+		Name:     "source-eth-rpc-url",
+		Required: true,
+		Usage:    "Eth rpc url of the network to replay the batches of",
+	}
+	sourceServiceManagerFlag = &cli.StringFlag{
+		Name:  "source-service-manager-address",
+		Value: "0xeF2A435e5EE44B2041100EF8cbC8ae035166606c",
+		Usage: "Address of the AlignedLayerServiceManager of the network to replay, mainnet by default",
+	}
+	fromBlockFlag = &cli.Uint64Flag{
+		Name:     "from-block",
+		Required: true,
+		Usage:    "First block to replay the batches of",
+	}
+	toBlockFlag = &cli.Uint64Flag{
+		Name:  "to-block",
+		Usage: "Last block to replay the batches of, the latest block if not set",
+	}
+	blockRangeFlag = &cli.Uint64Flag{
+		Name:  "block-range",
+		Value: 5000,
+		Usage: "Max number of blocks to query events of at once",
+	}
+	maxBatchesFlag = &cli.IntFlag{
+		Name:  "max-batches",
+		Usage: "Max number of batches to replay, all of them if not set",
+	}
+	speedFlag = &cli.Float64Flag{
+		Name:  "speed",
+		Value: 1,
+		Usage: "Factor to speed up the replay by, keeping the relative time between batches, or 0 to replay them as fast as possible",
+	}
+)
+
+var replayFlags = []cli.Flag{
+	config.ConfigFileFlag,
+	sourceEthRpcUrlFlag,
+	sourceServiceManagerFlag,
+	fromBlockFlag,
+	toBlockFlag,
+	blockRangeFlag,
+	maxBatchesFlag,
+	speedFlag,
+}
+
+func main() {
+	app := cli.NewApp()
+
+	app.Flags = flags
+	app.Version = fmt.Sprintf("%s-%s-%s", Version, GitCommit, GitDate)
+	app.Name = "aligned-layer-reference-batcher"
+	app.Usage = "Aligned Layer Reference Batcher"
+	app.Description = "Minimal batcher to run local stacks without the batcher, with fixed fees and local batch storage."
+	app.Action = referenceBatcherMain
+	app.Commands = []*cli.Command{
+		{
+			Name:   "replay",
+			Usage:  "Replay the batches of another network, creating their tasks in this one",
+			Flags:  replayFlags,
+			Action: replayMain,
+		},
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Fatalln("Application failed.", "Message:", err)
+	}
+}
+
+func referenceBatcherMain(ctx *cli.Context) error {
+	configFilePath := ctx.String(config.ConfigFileFlag.Name)
+	referenceBatcherConfig := config.NewReferenceBatcherConfig(configFilePath)
+
+	batcher, err := pkg.NewReferenceBatcher(*referenceBatcherConfig)
+	if err != nil {
+		referenceBatcherConfig.BaseConfig.Logger.Error("Cannot create reference batcher", "err", err)
+		return err
+	}
+
+	go func() {
+		err := batcher.Start(context.Background())
+		referenceBatcherConfig.BaseConfig.Logger.Fatal("Batch loop stopped", "err", err)
+	}()
+
+	return batcher.Serve(context.Background(), referenceBatcherConfig.ReferenceBatcher.ServerIpPortAddress)
+}
+
+// replayMain serves the replayed batches from the storage dir while creating their tasks, and
+// keeps serving them once done until interrupted, so operators can still download them
+func replayMain(ctx *cli.Context) error {
+	configFilePath := ctx.String(config.ConfigFileFlag.Name)
+	referenceBatcherConfig := config.NewReferenceBatcherConfig(configFilePath)
+	logger := referenceBatcherConfig.BaseConfig.Logger
+
+	batcher, err := pkg.NewReferenceBatcher(*referenceBatcherConfig)
+	if err != nil {
+		logger.Error("Cannot create reference batcher", "err", err)
+		return err
+	}
+
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- batcher.Serve(runCtx, referenceBatcherConfig.ReferenceBatcher.ServerIpPortAddress)
+	}()
+
+	batches, err := pkg.FetchHistoricalBatches(runCtx, pkg.ReplayParams{
+		SourceEthRpcUrl:          ctx.String(sourceEthRpcUrlFlag.Name),
+		SourceServiceManagerAddr: common.HexToAddress(ctx.String(sourceServiceManagerFlag.Name)),
+		FromBlock:                ctx.Uint64(fromBlockFlag.Name),
+		ToBlock:                  ctx.Uint64(toBlockFlag.Name),
+		BlockRange:               ctx.Uint64(blockRangeFlag.Name),
+		MaxBatches:               ctx.Int(maxBatchesFlag.Name),
+	})
+	if err != nil {
+		logger.Error("Cannot fetch batches to replay", "err", err)
+		return err
+	}
+	logger.Info("Replaying batches", "batches", len(batches))
+
+	if err := batcher.Replay(runCtx, batches, ctx.Float64(speedFlag.Name)); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+	logger.Info("Replay done, serving the batches until interrupted")
+	if err := <-serveErr; !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// Percentage of the fee for the aggregator it can spend responding to the task, as in the batcher
+const respondToTaskFeeLimitPercentage = 250
+
+// ReferenceBatcher is a minimal batcher for local stacks. It speaks the batcher protocol, but
+// doesn't pre verify the proofs, replace queued proofs, nor estimate the fees, which are fixed by
+// its configuration. Batches are stored in a local directory it serves to the operators.
+type ReferenceBatcher struct {
+	logger             sdklogging.Logger
+	paymentService     paymentService
+	serviceManager     taskCreator
+	chainId            *big.Int
+	paymentServiceAddr common.Address
+	storageDir         string
+	downloadEndpoint   string
+	batchInterval      time.Duration
+	maxBatchProofQty   int
+	maxProofSize       int
+	feePerProof        *big.Int
+	feeForAggregator   *big.Int
+
+	// mu guards the queue, and the next nonce and proofs not yet paid of each sender
+	mu      sync.Mutex
+	queue   []queueEntry
+	nonces  map[common.Address]*big.Int
+	pending map[common.Address]int64
+	// batchFull wakes up the batch loop before the batch interval when the queue fills a batch
+	batchFull chan struct{}
+}
+
+// queueEntry is a proof waiting for its batch, and how to answer its sender
+type queueEntry struct {
+	verificationData batcher.NoncedVerificationData
+	sender           common.Address
+	respond          func([]byte)
+}
+
+func NewReferenceBatcher(referenceBatcherConfig config.ReferenceBatcherConfig) (*ReferenceBatcher, error) {
+	baseConfig := referenceBatcherConfig.BaseConfig
+	paymentServiceAddr := baseConfig.AlignedLayerDeploymentConfig.BatcherPaymentServiceAddr
+	paymentService, err := newPaymentServiceContract(paymentServiceAddr, &baseConfig.EthRpcClient, referenceBatcherConfig.EcdsaConfig.PrivateKey, baseConfig.ChainId)
+	if err != nil {
+		return nil, fmt.Errorf("could not bind batcher payment service: %w", err)
+	}
+	serviceManager, err := newServiceManagerContract(baseConfig.AlignedLayerDeploymentConfig.AlignedLayerServiceManagerAddr, &baseConfig.EthRpcClient, referenceBatcherConfig.EcdsaConfig.PrivateKey, baseConfig.ChainId)
+	if err != nil {
+		return nil, fmt.Errorf("could not bind service manager: %w", err)
+	}
+	if err := os.MkdirAll(referenceBatcherConfig.ReferenceBatcher.StorageDir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create storage dir: %w", err)
+	}
+
+	c := referenceBatcherConfig.ReferenceBatcher
+	b := newReferenceBatcher(baseConfig.Logger, paymentService, baseConfig.ChainId, paymentServiceAddr, c.StorageDir, c.DownloadEndpoint,
+		c.BatchInterval, c.MaxBatchProofQty, c.MaxProofSize, new(big.Int).SetUint64(c.FeePerProof), new(big.Int).SetUint64(c.FeeForAggregator))
+	b.serviceManager = serviceManager
+	return b, nil
+}
+
+func newReferenceBatcher(logger sdklogging.Logger, paymentService paymentService, chainId *big.Int, paymentServiceAddr common.Address, storageDir, downloadEndpoint string,
+	batchInterval time.Duration, maxBatchProofQty, maxProofSize int, feePerProof, feeForAggregator *big.Int) *ReferenceBatcher {
+	return &ReferenceBatcher{
+		logger:             logger,
+		paymentService:     paymentService,
+		chainId:            chainId,
+		paymentServiceAddr: paymentServiceAddr,
+		storageDir:         storageDir,
+		downloadEndpoint:   downloadEndpoint,
+		batchInterval:      batchInterval,
+		maxBatchProofQty:   maxBatchProofQty,
+		maxProofSize:       maxProofSize,
+		feePerProof:        feePerProof,
+		feeForAggregator:   feeForAggregator,
+		nonces:             make(map[common.Address]*big.Int),
+		pending:            make(map[common.Address]int64),
+		batchFull:          make(chan struct{}, 1),
+	}
+}
+
+// nextNonce returns the nonce of the next proof of the sender, which is its nonce in the
+// BatcherPaymentService plus its proofs in the queue. It must be called with mu held.
+func (b *ReferenceBatcher) nextNonce(ctx context.Context, sender common.Address) (*big.Int, error) {
+	if nonce, ok := b.nonces[sender]; ok {
+		return nonce, nil
+	}
+	nonce, err := b.paymentService.UserNonce(ctx, sender)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not get nonce: %v", batcher.ErrEthRpc, err)
+	}
+	b.nonces[sender] = nonce
+	return nonce, nil
+}
+
+// GetNonce returns the nonce the next proof of the sender must have
+func (b *ReferenceBatcher) GetNonce(ctx context.Context, sender common.Address) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	nonce, err := b.nextNonce(ctx, sender)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(nonce), nil
+}
+
+// AddProof validates the proof and queues it for the next batch, returning its sender.
+// Once the batch is created or fails, the response for the sender is passed to respond.
+func (b *ReferenceBatcher) AddProof(ctx context.Context, message *batcher.SubmitProofMessage, respond func([]byte)) (common.Address, error) {
+	data := message.VerificationData
+	sender, err := data.RecoverSigner(message.Signature)
+	if err != nil {
+		return common.Address{}, batcher.ErrInvalidSignature
+	}
+	if len(data.VerificationData.Proof) > b.maxProofSize {
+		return sender, batcher.ErrProofTooLarge
+	}
+	if data.ChainId.Cmp(b.chainId) != 0 {
+		return sender, batcher.ErrInvalidChainId
+	}
+	if data.PaymentServiceAddr != b.paymentServiceAddr {
+		return sender, fmt.Errorf("%w: expected %s", batcher.ErrInvalidPaymentServiceAddress, b.paymentServiceAddr)
+	}
+	if data.MaxFee.Cmp(b.feePerProof) < 0 {
+		return sender, batcher.ErrInvalidMaxFee
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	nonce, err := b.nextNonce(ctx, sender)
+	if err != nil {
+		return sender, err
+	}
+	if data.Nonce.Cmp(nonce) != 0 {
+		return sender, batcher.ErrInvalidNonce
+	}
+	// The balance must pay for the proofs of the sender not yet paid too
+	balance, err := b.paymentService.UserBalance(ctx, sender)
+	if err != nil {
+		return sender, fmt.Errorf("%w: could not get balance: %v", batcher.ErrEthRpc, err)
+	}
+	if balance.Cmp(new(big.Int).Mul(b.feePerProof, big.NewInt(b.pending[sender]+1))) < 0 {
+		return sender, batcher.ErrInsufficientBalance
+	}
+
+	b.queue = append(b.queue, queueEntry{verificationData: data, sender: sender, respond: respond})
+	b.nonces[sender] = new(big.Int).Add(nonce, big.NewInt(1))
+	b.pending[sender]++
+	if len(b.queue) >= b.maxBatchProofQty {
+		select {
+		case b.batchFull <- struct{}{}:
+		default:
+		}
+	}
+	return sender, nil
+}
+
+// Start creates a batch of the queued proofs each batch interval, or as soon as a batch is
+// full, until the context is cancelled
+func (b *ReferenceBatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(b.batchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-b.batchFull:
+		}
+		// A full queue may fill several batches
+		for b.createBatch(ctx) {
+		}
+	}
+}
+
+// createBatch stores the next batch, creates its task and responds to its senders.
+// It returns whether another full batch is queued.
+func (b *ReferenceBatcher) createBatch(ctx context.Context) bool {
+	b.mu.Lock()
+	batchLen := min(len(b.queue), b.maxBatchProofQty)
+	batch := b.queue[:batchLen:batchLen]
+	b.queue = b.queue[batchLen:]
+	b.mu.Unlock()
+	if len(batch) == 0 {
+		return false
+	}
+
+	batchMerkleRoot, proofs, err := b.submitBatch(ctx, batch)
+	if err != nil {
+		b.logger.Error("Could not create task, resetting the queue", "batchMerkleRoot", hex.EncodeToString(batchMerkleRoot[:]), "err", err)
+		b.resetQueue(batch, batchMerkleRoot, err)
+		return false
+	}
+	b.logger.Info("Task created", "batchMerkleRoot", hex.EncodeToString(batchMerkleRoot[:]), "proofs", len(batch))
+
+	for i, entry := range batch {
+		response, err := batcher.EncodeBatchInclusionData(&batcher.BatchInclusionData{
+			BatchMerkleRoot:     batchMerkleRoot,
+			BatchInclusionProof: proofs[i],
+			IndexInBatch:        uint64(i),
+			UserNonce:           entry.verificationData.Nonce,
+		})
+		if err != nil {
+			b.logger.Error("Could not serialize inclusion data", "err", err)
+			continue
+		}
+		entry.respond(response)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, entry := range batch {
+		b.pending[entry.sender]--
+	}
+	return len(b.queue) >= b.maxBatchProofQty
+}
+
+// submitBatch stores the batch and its leaves, and creates its task, returning its merkle root
+// and the inclusion proofs of its proofs
+func (b *ReferenceBatcher) submitBatch(ctx context.Context, batch []queueEntry) ([32]byte, [][][32]byte, error) {
+	verificationData := make([]batcher.VerificationData, len(batch))
+	leaves := make([][32]byte, len(batch))
+	proofSubmitters := make([]common.Address, len(batch))
+	for i, entry := range batch {
+		verificationData[i] = entry.verificationData.VerificationData
+		commitment := verificationData[i].Commitment()
+		leaves[i] = commitment.Hash()
+		proofSubmitters[i] = entry.sender
+	}
+	tree, err := merkle.NewTree(merkle.CurrentVersion, leaves)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+	batchMerkleRoot, proofs := tree.Root(), tree.Paths()
+
+	batchBytes, err := batcher.EncodeBatch(verificationData)
+	if err != nil {
+		return batchMerkleRoot, nil, fmt.Errorf("could not serialize batch: %w", err)
+	}
+	leavesBytes := make([]byte, 0, len(leaves)*32)
+	for _, leaf := range leaves {
+		leavesBytes = append(leavesBytes, leaf[:]...)
+	}
+	// Operators download the leaves next to the batch, named after its merkle root
+	batchMerkleRootHex := hex.EncodeToString(batchMerkleRoot[:])
+	fileName := batchMerkleRootHex + ".json"
+	if err := os.WriteFile(filepath.Join(b.storageDir, batchMerkleRootHex+".leaves"), leavesBytes, 0o644); err != nil {
+		return batchMerkleRoot, nil, fmt.Errorf("could not store batch leaves: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(b.storageDir, fileName), batchBytes, 0o644); err != nil {
+		return batchMerkleRoot, nil, fmt.Errorf("could not store batch: %w", err)
+	}
+
+	respondToTaskFeeLimit := new(big.Int).Mul(b.feeForAggregator, big.NewInt(respondToTaskFeeLimitPercentage))
+	respondToTaskFeeLimit.Div(respondToTaskFeeLimit, big.NewInt(100))
+	err = b.paymentService.CreateNewTask(ctx, batchMerkleRoot, b.downloadEndpoint+"/"+fileName, proofSubmitters, b.feeForAggregator, b.feePerProof, respondToTaskFeeLimit)
+	if err != nil {
+		return batchMerkleRoot, nil, err
+	}
+	return batchMerkleRoot, proofs, nil
+}
+
+// resetQueue responds to the proofs of a failed batch with its error, and flushes the queue, as
+// the nonces of the proofs queued after it can't be paid anymore
+func (b *ReferenceBatcher) resetQueue(batch []queueEntry, batchMerkleRoot [32]byte, err error) {
+	b.mu.Lock()
+	flushed := b.queue
+	b.queue = nil
+	b.nonces = make(map[common.Address]*big.Int)
+	b.pending = make(map[common.Address]int64)
+	b.mu.Unlock()
+
+	if response, err := batcher.EncodeCreateNewTaskError(batchMerkleRoot, err); err == nil {
+		for _, entry := range batch {
+			entry.respond(response)
+		}
+	}
+	for _, entry := range flushed {
+		if response, err := batcher.EncodeSubmitProofError(batcher.ErrProofQueueFlushed, entry.sender); err == nil {
+			entry.respond(response)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	alignedcommon "github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// The batch and merkle root the operator merkle tree library is tested with
+const (
+	batchFilePath = "../../operator/merkle_tree/lib/test_files/merkle_tree_batch.bin"
+	rootFilePath  = "../../operator/merkle_tree/lib/test_files/merkle_root.bin"
+)
+
+var testPaymentServiceAddr = common.HexToAddress("0x7bc06c482DEAd17c0e297aFbC32f6e63d3846650")
+
+// fakePaymentService records the tasks created, failing them if failTasks is set
+type fakePaymentService struct {
+	mu        sync.Mutex
+	balance   *big.Int
+	failTasks bool
+	tasks     []string
+}
+
+func (p *fakePaymentService) UserBalance(ctx context.Context, account common.Address) (*big.Int, error) {
+	return p.balance, nil
+}
+
+func (p *fakePaymentService) UserNonce(ctx context.Context, account common.Address) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *fakePaymentService) CreateNewTask(ctx context.Context, batchMerkleRoot [32]byte, batchDataPointer string, proofSubmitters []common.Address, feeForAggregator, feePerProof, respondToTaskFeeLimit *big.Int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failTasks {
+		return errors.New("execution reverted")
+	}
+	p.tasks = append(p.tasks, batchDataPointer)
+	return nil
+}
+
+func (p *fakePaymentService) createdTasks() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.tasks
+}
+
+func startTestBatcher(t *testing.T, paymentService *fakePaymentService, maxBatchProofQty int) (*batcher.Client, string) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %v", err)
+	}
+	storageDir := t.TempDir()
+	b := newReferenceBatcher(logger, paymentService, big.NewInt(31337), testPaymentServiceAddr, storageDir, "http://localhost/batches",
+		time.Hour, maxBatchProofQty, 1024, big.NewInt(100), big.NewInt(10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = b.Start(ctx) }()
+	server := httptest.NewServer(b.Handler())
+	t.Cleanup(server.Close)
+
+	client, err := batcher.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"))
+	if err != nil {
+		t.Fatalf("could not connect to batcher: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client, storageDir
+}
+
+func testSigner(t *testing.T) *batcher.Signer {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	return batcher.NewSigner(privateKey, big.NewInt(31337), testPaymentServiceAddr)
+}
+
+func testProofs(signer *batcher.Signer, n int) []batcher.VerificationData {
+	proofs := make([]batcher.VerificationData, n)
+	for i := range proofs {
+		proofs[i] = batcher.VerificationData{ProvingSystem: alignedcommon.SP1, Proof: []byte{byte(i)}, VmProgramCode: []byte{1}, ProofGeneratorAddr: signer.Address()}
+	}
+	return proofs
+}
+
+func TestBatcherCreatesTaskOfFullBatch(t *testing.T) {
+	paymentService := &fakePaymentService{balance: big.NewInt(1000)}
+	client, storageDir := startTestBatcher(t, paymentService, 3)
+	signer := testSigner(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	responses, err := client.Submit(ctx, signer, testProofs(signer, 3), big.NewInt(100), nil)
+	if err != nil {
+		t.Fatalf("could not submit proofs: %v", err)
+	}
+	tasks := paymentService.createdTasks()
+	if len(responses) != 3 || len(tasks) != 1 {
+		t.Fatalf("expected 3 proofs in 1 task, got %d proofs and tasks %v", len(responses), tasks)
+	}
+
+	fileName := common.Bytes2Hex(responses[0].BatchMerkleRoot[:]) + ".json"
+	if tasks[0] != "http://localhost/batches/"+fileName {
+		t.Errorf("unexpected batch data pointer %s", tasks[0])
+	}
+	stored, err := os.ReadFile(filepath.Join(storageDir, fileName))
+	if err != nil {
+		t.Fatalf("could not read stored batch: %v", err)
+	}
+	if batch, err := batcher.DecodeBatch(stored); err != nil || len(batch) != 3 {
+		t.Errorf("unexpected stored batch: %v, %v", batch, err)
+	}
+
+	nonce, err := client.GetNonce(ctx, signer.Address())
+	if err != nil || nonce.Int64() != 3 {
+		t.Errorf("unexpected nonce %v, %v", nonce, err)
+	}
+}
+
+func TestBatcherRejectsInvalidProofs(t *testing.T) {
+	client, _ := startTestBatcher(t, &fakePaymentService{balance: big.NewInt(150)}, 10)
+	signer := testSigner(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.Submit(ctx, signer, testProofs(signer, 1), big.NewInt(100), big.NewInt(1)); !errors.Is(err, batcher.ErrInvalidNonce) {
+		t.Errorf("expected invalid nonce, got %v", err)
+	}
+	if _, err := client.Submit(ctx, signer, testProofs(signer, 1), big.NewInt(99), big.NewInt(0)); !errors.Is(err, batcher.ErrInvalidMaxFee) {
+		t.Errorf("expected invalid max fee, got %v", err)
+	}
+	// the balance pays for a single proof
+	if _, err := client.Submit(ctx, signer, testProofs(signer, 2), big.NewInt(100), big.NewInt(0)); !errors.Is(err, batcher.ErrInsufficientBalance) {
+		t.Errorf("expected insufficient balance, got %v", err)
+	}
+}
+
+func TestBatcherRespondsToFailedTasks(t *testing.T) {
+	client, _ := startTestBatcher(t, &fakePaymentService{balance: big.NewInt(1000), failTasks: true}, 1)
+	signer := testSigner(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.Submit(ctx, signer, testProofs(signer, 1), big.NewInt(100), nil); !errors.Is(err, batcher.ErrCreateNewTask) {
+		t.Errorf("expected create new task error, got %v", err)
+	}
+}
+
+func TestBatcherServesHttp(t *testing.T) {
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %v", err)
+	}
+	b := newReferenceBatcher(logger, &fakePaymentService{}, big.NewInt(31337), testPaymentServiceAddr, t.TempDir(), "http://localhost/batches",
+		time.Hour, 1, 1024, big.NewInt(100), big.NewInt(10))
+	server := httptest.NewServer(b.Handler())
+	defer server.Close()
+
+	response, err := http.Post(server.URL, "application/cbor", strings.NewReader("not cbor"))
+	if err != nil {
+		t.Fatalf("could not post message: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "application/cbor" {
+		t.Errorf("unexpected response %d %s", response.StatusCode, response.Header.Get("Content-Type"))
+	}
+}
+
+func TestBatchTreeMatchesBatcherRoot(t *testing.T) {
+	batch, err := os.ReadFile(batchFilePath)
+	if err != nil {
+		t.Fatalf("could not read batch: %v", err)
+	}
+	hexRoot, err := os.ReadFile(rootFilePath)
+	if err != nil {
+		t.Fatalf("could not read root: %v", err)
+	}
+	verificationData, err := batcher.DecodeBatch(batch)
+	if err != nil {
+		t.Fatalf("could not decode batch: %v", err)
+	}
+
+	leaves := make([][32]byte, len(verificationData))
+	for i := range verificationData {
+		commitment := verificationData[i].Commitment()
+		leaves[i] = commitment.Hash()
+	}
+	tree, err := merkle.NewTree(merkle.CurrentVersion, leaves)
+	if err != nil {
+		t.Fatalf("could not build tree: %v", err)
+	}
+	root := tree.Root()
+	if hex.EncodeToString(root[:]) != string(hexRoot) {
+		t.Errorf("unexpected root %x, expected %s", root, hexRoot)
+	}
+
+	for i, proof := range tree.Paths() {
+		inclusionData := batcher.BatchInclusionData{BatchMerkleRoot: root, BatchInclusionProof: proof, IndexInBatch: uint64(i)}
+		if !inclusionData.VerifyInclusion(verificationData[i].Commitment()) {
+			t.Errorf("inclusion proof of leaf %d doesn't verify", i)
+		}
+	}
+}
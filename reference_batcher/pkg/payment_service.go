@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// The BatcherPaymentService functions used by the reference batcher
+const batcherPaymentServiceAbi = `[
+	{"type":"function","name":"createNewTask","stateMutability":"nonpayable","inputs":[
+		{"name":"batchMerkleRoot","type":"bytes32"},
+		{"name":"batchDataPointer","type":"string"},
+		{"name":"proofSubmitters","type":"address[]"},
+		{"name":"feeForAggregator","type":"uint256"},
+		{"name":"feePerProof","type":"uint256"},
+		{"name":"respondToTaskFeeLimit","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"user_balances","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"user_nonces","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// paymentService is the BatcherPaymentService contract, holding the balances and nonces of the
+// proof senders, and creating the tasks of the batches they pay for
+type paymentService interface {
+	UserBalance(ctx context.Context, account common.Address) (*big.Int, error)
+	UserNonce(ctx context.Context, account common.Address) (*big.Int, error)
+	CreateNewTask(ctx context.Context, batchMerkleRoot [32]byte, batchDataPointer string, proofSubmitters []common.Address, feeForAggregator, feePerProof, respondToTaskFeeLimit *big.Int) error
+}
+
+type paymentServiceContract struct {
+	contract   *bind.BoundContract
+	backend    bind.DeployBackend
+	privateKey *ecdsa.PrivateKey
+	chainId    *big.Int
+}
+
+type contractBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+func newPaymentServiceContract(address common.Address, backend contractBackend, privateKey *ecdsa.PrivateKey, chainId *big.Int) (*paymentServiceContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(batcherPaymentServiceAbi))
+	if err != nil {
+		return nil, err
+	}
+	return &paymentServiceContract{
+		contract:   bind.NewBoundContract(address, parsed, backend, backend, backend),
+		backend:    backend,
+		privateKey: privateKey,
+		chainId:    chainId,
+	}, nil
+}
+
+func (p *paymentServiceContract) call(ctx context.Context, method string, account common.Address) (*big.Int, error) {
+	var out []interface{}
+	if err := p.contract.Call(&bind.CallOpts{Context: ctx}, &out, method, account); err != nil {
+		return nil, err
+	}
+	return abi.ConvertType(out[0], new(big.Int)).(*big.Int), nil
+}
+
+func (p *paymentServiceContract) UserBalance(ctx context.Context, account common.Address) (*big.Int, error) {
+	return p.call(ctx, "user_balances", account)
+}
+
+func (p *paymentServiceContract) UserNonce(ctx context.Context, account common.Address) (*big.Int, error) {
+	return p.call(ctx, "user_nonces", account)
+}
+
+// CreateNewTask sends the createNewTask transaction and waits for it to be mined. Unlike the
+// batcher, it doesn't bump the gas price of transactions taking long to be included.
+func (p *paymentServiceContract) CreateNewTask(ctx context.Context, batchMerkleRoot [32]byte, batchDataPointer string, proofSubmitters []common.Address, feeForAggregator, feePerProof, respondToTaskFeeLimit *big.Int) error {
+	opts, err := bind.NewKeyedTransactorWithChainID(p.privateKey, p.chainId)
+	if err != nil {
+		return err
+	}
+	opts.Context = ctx
+	tx, err := p.contract.Transact(opts, "createNewTask", batchMerkleRoot, batchDataPointer, proofSubmitters, feeForAggregator, feePerProof, respondToTaskFeeLimit)
+	if err != nil {
+		return err
+	}
+	receipt, err := bind.WaitMined(ctx, p.backend, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return nil
+}
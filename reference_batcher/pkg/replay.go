@@ -0,0 +1,181 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	csservicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+// ReplayParams selects the batches of a source network to replay
+type ReplayParams struct {
+	SourceEthRpcUrl          string
+	SourceServiceManagerAddr common.Address
+	FromBlock                uint64
+	// ToBlock is the last block to replay the batches of, the latest block if 0
+	ToBlock uint64
+	// BlockRange is the max number of blocks to query events of at once
+	BlockRange uint64
+	MaxBatches int
+}
+
+// HistoricalBatch is a batch created in the source network
+type HistoricalBatch struct {
+	BatchMerkleRoot  [32]byte
+	BatchDataPointer string
+	CreatedAt        time.Time
+}
+
+// FetchHistoricalBatches returns the batches created in the source network from the NewBatchV3
+// events of its service manager, with the time of the block they were created in
+func FetchHistoricalBatches(ctx context.Context, params ReplayParams) ([]HistoricalBatch, error) {
+	client, err := ethclient.DialContext(ctx, params.SourceEthRpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to source eth rpc: %w", err)
+	}
+	defer client.Close()
+	serviceManager, err := csservicemanager.NewContractAlignedLayerServiceManager(params.SourceServiceManagerAddr, client)
+	if err != nil {
+		return nil, err
+	}
+
+	toBlock := params.ToBlock
+	if toBlock == 0 {
+		if toBlock, err = client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("could not get source block number: %w", err)
+		}
+	}
+	blockRange := max(params.BlockRange, 1)
+
+	var batches []HistoricalBatch
+	blockTimes := make(map[uint64]time.Time)
+	for start := params.FromBlock; start <= toBlock; start += blockRange {
+		end := min(start+blockRange-1, toBlock)
+		logs, err := serviceManager.FilterNewBatchV3(&bind.FilterOpts{Start: start, End: &end, Context: ctx}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not get NewBatchV3 events of blocks %d to %d: %w", start, end, err)
+		}
+		for logs.Next() {
+			event := logs.Event
+			createdAt, ok := blockTimes[event.Raw.BlockNumber]
+			if !ok {
+				header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(event.Raw.BlockNumber))
+				if err != nil {
+					_ = logs.Close()
+					return nil, fmt.Errorf("could not get block %d: %w", event.Raw.BlockNumber, err)
+				}
+				createdAt = time.Unix(int64(header.Time), 0)
+				blockTimes[event.Raw.BlockNumber] = createdAt
+			}
+			batches = append(batches, HistoricalBatch{
+				BatchMerkleRoot:  event.BatchMerkleRoot,
+				BatchDataPointer: event.BatchDataPointer,
+				CreatedAt:        createdAt,
+			})
+			if params.MaxBatches > 0 && len(batches) >= params.MaxBatches {
+				_ = logs.Close()
+				return batches, nil
+			}
+		}
+		if err := logs.Error(); err != nil {
+			return nil, err
+		}
+		_ = logs.Close()
+	}
+	return batches, nil
+}
+
+// Replay downloads the batches into the storage dir and creates their tasks in the service
+// manager, keeping the time between them divided by the speed, or as fast as possible if 0, so operators and the aggregator
+// handle the same traffic as the source network. The tasks are paid by the batcher wallet, and
+// batches whose task the wallet already created are skipped.
+func (b *ReferenceBatcher) Replay(ctx context.Context, batches []HistoricalBatch, speed float64) error {
+	if len(batches) == 0 {
+		return nil
+	}
+	respondToTaskFeeLimit := new(big.Int).Mul(b.feeForAggregator, big.NewInt(respondToTaskFeeLimitPercentage))
+	respondToTaskFeeLimit.Div(respondToTaskFeeLimit, big.NewInt(100))
+
+	start := time.Now()
+	for i, batch := range batches {
+		if speed > 0 {
+			due := start.Add(time.Duration(float64(batch.CreatedAt.Sub(batches[0].CreatedAt)) / speed))
+			select {
+			case <-time.After(time.Until(due)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		batchMerkleRootHex := hex.EncodeToString(batch.BatchMerkleRoot[:])
+		fileName, err := b.downloadBatch(ctx, batch)
+		if err != nil {
+			b.logger.Warn("Could not download batch, skipping it", "batchMerkleRoot", batchMerkleRootHex, "err", err)
+			continue
+		}
+		err = b.serviceManager.CreateNewTask(ctx, batch.BatchMerkleRoot, b.downloadEndpoint+"/"+fileName, respondToTaskFeeLimit)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			b.logger.Warn("Could not create task, skipping batch", "batchMerkleRoot", batchMerkleRootHex, "err", err)
+			continue
+		}
+		b.logger.Info("Batch replayed", "batchMerkleRoot", batchMerkleRootHex, "batch", i+1, "batches", len(batches))
+	}
+	return nil
+}
+
+// downloadBatch stores the batch as uploaded by the source batcher, which may be compressed,
+// and its leaves if they were uploaded. It returns the file name of the batch.
+func (b *ReferenceBatcher) downloadBatch(ctx context.Context, batch HistoricalBatch) (string, error) {
+	batchMerkleRootHex := hex.EncodeToString(batch.BatchMerkleRoot[:])
+	fileName := batchMerkleRootHex + ".json"
+	if err := download(ctx, batch.BatchDataPointer, filepath.Join(b.storageDir, fileName)); err != nil {
+		return "", err
+	}
+
+	if leavesUrl, ok := strings.CutSuffix(batch.BatchDataPointer, ".json"); ok {
+		if err := download(ctx, leavesUrl+".leaves", filepath.Join(b.storageDir, batchMerkleRootHex+".leaves")); err != nil {
+			b.logger.Debug("Batch leaves not downloaded", "batchMerkleRoot", batchMerkleRootHex, "err", err)
+		}
+	}
+	return fileName, nil
+}
+
+func download(ctx context.Context, url string, path string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, response.Body); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,79 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// fakeTaskCreator records the batch data pointers of the tasks created
+type fakeTaskCreator struct {
+	mu       sync.Mutex
+	pointers []string
+	created  []time.Time
+}
+
+func (c *fakeTaskCreator) CreateNewTask(ctx context.Context, batchMerkleRoot [32]byte, batchDataPointer string, respondToTaskFeeLimit *big.Int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pointers = append(c.pointers, batchDataPointer)
+	c.created = append(c.created, time.Now())
+	return nil
+}
+
+func TestReplayKeepsTimeBetweenBatches(t *testing.T) {
+	source := http.NewServeMux()
+	source.HandleFunc("/first.json", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("first batch")) })
+	source.HandleFunc("/first.leaves", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("first leaves")) })
+	source.HandleFunc("/third.json", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("third batch")) })
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	logger, err := sdklogging.NewZapLogger(sdklogging.Development)
+	if err != nil {
+		t.Fatalf("could not create logger: %v", err)
+	}
+	storageDir := t.TempDir()
+	b := newReferenceBatcher(logger, &fakePaymentService{}, big.NewInt(31337), testPaymentServiceAddr, storageDir, "http://localhost/batches",
+		time.Hour, 1, 1024, big.NewInt(100), big.NewInt(10))
+	taskCreator := &fakeTaskCreator{}
+	b.serviceManager = taskCreator
+
+	createdAt := time.Unix(1700000000, 0)
+	batches := []HistoricalBatch{
+		{BatchMerkleRoot: [32]byte{1}, BatchDataPointer: server.URL + "/first.json", CreatedAt: createdAt},
+		// not available anymore, so skipped
+		{BatchMerkleRoot: [32]byte{2}, BatchDataPointer: server.URL + "/second.json", CreatedAt: createdAt.Add(time.Second)},
+		{BatchMerkleRoot: [32]byte{3}, BatchDataPointer: server.URL + "/third.json", CreatedAt: createdAt.Add(4 * time.Second)},
+	}
+	if err := b.Replay(context.Background(), batches, 20); err != nil {
+		t.Fatalf("could not replay batches: %v", err)
+	}
+
+	if len(taskCreator.pointers) != 2 {
+		t.Fatalf("expected 2 tasks, got %v", taskCreator.pointers)
+	}
+	firstName := "0100000000000000000000000000000000000000000000000000000000000000"
+	if taskCreator.pointers[0] != "http://localhost/batches/"+firstName+".json" {
+		t.Errorf("unexpected batch data pointer %s", taskCreator.pointers[0])
+	}
+	// 4 seconds at 20x
+	if elapsed := taskCreator.created[1].Sub(taskCreator.created[0]); elapsed < 150*time.Millisecond {
+		t.Errorf("batches replayed %s apart, expected 200ms", elapsed)
+	}
+
+	for name, expected := range map[string]string{firstName + ".json": "first batch", firstName + ".leaves": "first leaves"} {
+		stored, err := os.ReadFile(filepath.Join(storageDir, name))
+		if err != nil || string(stored) != expected {
+			t.Errorf("unexpected %s: %q, %v", name, stored, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// Max size of the messages received, enough for proofs of the max proof size
+const maxMessageSizeOverhead = 1 << 20
+
+// Handler serves the batcher protocol over websocket connections, and over HTTP posting one
+// message per request to /, besides the stored batches under /batches/
+func (b *ReferenceBatcher) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/batches/", http.StripPrefix("/batches/", http.FileServer(http.Dir(b.storageDir))))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			b.serveWebsocket(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		b.serveHttp(w, r)
+	})
+	return mux
+}
+
+// Serve listens on the address until the context is cancelled
+func (b *ReferenceBatcher) Serve(ctx context.Context, address string) error {
+	server := &http.Server{Addr: address, Handler: b.Handler()}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	b.logger.Info("Listening for proofs", "address", address)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return ctx.Err()
+}
+
+// handleMessage answers a message of a client. Nonce requests and rejected proofs are answered
+// right away, while queued proofs are answered once their batch is created.
+func (b *ReferenceBatcher) handleMessage(ctx context.Context, data []byte, respond func([]byte)) {
+	message, err := batcher.DecodeClientMessage(data)
+	if err != nil {
+		b.logger.Warn("Could not decode message", "err", err)
+		if response, err := batcher.EncodeSubmitProofError(err, common.Address{}); err == nil {
+			respond(response)
+		}
+		return
+	}
+
+	if message.GetNonceForAddress != nil {
+		nonce, err := b.GetNonce(ctx, *message.GetNonceForAddress)
+		if response, err := batcher.EncodeGetNonceResponse(nonce, err); err == nil {
+			respond(response)
+		}
+		return
+	}
+
+	sender, err := b.AddProof(ctx, message.SubmitProof, respond)
+	if err != nil {
+		b.logger.Info("Proof rejected", "sender", sender.Hex(), "nonce", message.SubmitProof.VerificationData.Nonce, "err", err)
+		if response, err := batcher.EncodeSubmitProofError(err, sender); err == nil {
+			respond(response)
+		}
+	}
+}
+
+func (b *ReferenceBatcher) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		b.logger.Warn("Could not upgrade connection", "err", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(int64(b.maxProofSize) + maxMessageSizeOverhead)
+
+	// Batches respond concurrently with the connection loop
+	var writeMutex sync.Mutex
+	respond := func(message []byte) {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+		_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := conn.WriteMessage(websocket.BinaryMessage, message); err != nil {
+			b.logger.Warn("Could not send response", "err", err)
+		}
+	}
+
+	version, err := batcher.EncodeProtocolVersion(batcher.ExpectedProtocolVersion)
+	if err != nil {
+		return
+	}
+	respond(version)
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType == websocket.BinaryMessage {
+			b.handleMessage(r.Context(), data, respond)
+		}
+	}
+}
+
+// serveHttp answers a message posted in the request body, waiting for the batch of proofs
+func (b *ReferenceBatcher) serveHttp(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(b.maxProofSize)+maxMessageSizeOverhead))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	responses := make(chan []byte, 1)
+	b.handleMessage(r.Context(), data, func(response []byte) { responses <- response })
+	select {
+	case response := <-responses:
+		w.Header().Set("Content-Type", "application/cbor")
+		_, _ = w.Write(response)
+	case <-r.Context().Done():
+	}
+}
@@ -0,0 +1,62 @@
+package pkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	csservicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+// taskCreator creates tasks directly in the AlignedLayerServiceManager, paying for them itself
+// instead of the proof senders
+type taskCreator interface {
+	CreateNewTask(ctx context.Context, batchMerkleRoot [32]byte, batchDataPointer string, respondToTaskFeeLimit *big.Int) error
+}
+
+type serviceManagerContract struct {
+	contract   *csservicemanager.ContractAlignedLayerServiceManager
+	backend    bind.DeployBackend
+	privateKey *ecdsa.PrivateKey
+	chainId    *big.Int
+}
+
+func newServiceManagerContract(address common.Address, backend contractBackend, privateKey *ecdsa.PrivateKey, chainId *big.Int) (*serviceManagerContract, error) {
+	contract, err := csservicemanager.NewContractAlignedLayerServiceManager(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &serviceManagerContract{
+		contract:   contract,
+		backend:    backend,
+		privateKey: privateKey,
+		chainId:    chainId,
+	}, nil
+}
+
+// CreateNewTask sends the createNewTask transaction depositing the respond to task fee limit,
+// and waits for it to be mined
+func (s *serviceManagerContract) CreateNewTask(ctx context.Context, batchMerkleRoot [32]byte, batchDataPointer string, respondToTaskFeeLimit *big.Int) error {
+	opts, err := bind.NewKeyedTransactorWithChainID(s.privateKey, s.chainId)
+	if err != nil {
+		return err
+	}
+	opts.Context = ctx
+	opts.Value = respondToTaskFeeLimit
+	tx, err := s.contract.CreateNewTask(opts, batchMerkleRoot, batchDataPointer, respondToTaskFeeLimit)
+	if err != nil {
+		return err
+	}
+	receipt, err := bind.WaitMined(ctx, s.backend, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return nil
+}
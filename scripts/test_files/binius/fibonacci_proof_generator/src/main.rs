@@ -0,0 +1,77 @@
+use anyhow::Result;
+use binius_circuits::builder::ConstraintSystemBuilder;
+use binius_core::constraint_system::{self, channel::Boundary};
+use binius_core::fiat_shamir::HasherChallenger;
+use binius_field::arch::OptimalUnderlier;
+use binius_field::tower::CanonicalTowerFamily;
+use binius_field::BinaryField128b;
+use binius_hal::make_portable_backend;
+use binius_hash::compress::Groestl256ByteCompression;
+use binius_math::DefaultEvaluationDomainFactory;
+use binius_utils::serialization::{SerializationMode, SerializeBytes};
+use groestl_crypto::Groestl256;
+
+// Same tower, hash, rate and security the verifier expects
+const LOG_INV_RATE: usize = 1;
+const SECURITY_BITS: usize = 100;
+// The circuit computes 2^LOG_SIZE elements of the Fibonacci sequence modulo 2^32
+const LOG_SIZE: usize = 10;
+
+type F = BinaryField128b;
+
+fn main() -> Result<()> {
+    let allocator = bumpalo::Bump::new();
+    let mut builder = ConstraintSystemBuilder::<OptimalUnderlier, F>::new_with_witness(&allocator);
+    binius_circuits::u32fib::u32fib(&mut builder, "u32fib", LOG_SIZE)?;
+
+    let witness = builder.take_witness()?;
+    let constraint_system = builder.build()?;
+    // The Fibonacci circuit doesn't flush to any channel, so there are no boundaries
+    let boundaries: Vec<Boundary<F>> = vec![];
+
+    let domain_factory = DefaultEvaluationDomainFactory::default();
+    let backend = make_portable_backend();
+    let proof = constraint_system::prove::<
+        OptimalUnderlier,
+        CanonicalTowerFamily,
+        _,
+        Groestl256,
+        Groestl256ByteCompression,
+        HasherChallenger<Groestl256>,
+        _,
+    >(
+        &constraint_system,
+        LOG_INV_RATE,
+        SECURITY_BITS,
+        &boundaries,
+        witness,
+        &domain_factory,
+        &backend,
+    )?;
+
+    constraint_system::verify::<
+        OptimalUnderlier,
+        CanonicalTowerFamily,
+        Groestl256,
+        Groestl256ByteCompression,
+        HasherChallenger<Groestl256>,
+    >(
+        &constraint_system,
+        LOG_INV_RATE,
+        SECURITY_BITS,
+        boundaries.clone(),
+        proof.clone(),
+    )?;
+
+    let mut constraint_system_bytes = Vec::new();
+    constraint_system.serialize(&mut constraint_system_bytes, SerializationMode::CanonicalTower)?;
+    let mut boundaries_bytes = Vec::new();
+    boundaries.serialize(&mut boundaries_bytes, SerializationMode::CanonicalTower)?;
+
+    std::fs::write("../binius_fibonacci.proof", &proof.transcript)?;
+    std::fs::write("../binius_fibonacci.pub", boundaries_bytes)?;
+    std::fs::write("../binius_fibonacci.cs", constraint_system_bytes)?;
+
+    println!("Binius Fibonacci proof, boundaries and constraint system generated");
+    Ok(())
+}
@@ -0,0 +1,58 @@
+//! Recursion tip proofs are only produced by a zkSync era prover, proving a whole batch, so instead
+//! of proving one this takes the recursion tip proof of a prover run and its verification key,
+//! checks the proof verifies and writes them as the operator expects them.
+//!
+//! Usage: cargo run --release -- <proof> <verification key>
+//! - proof: the recursion tip proof of the prover object store, a bincode FriProofWrapper
+//! - verification key: verification_recursion_tip_key.json of the prover keys of the same protocol version
+use anyhow::{bail, Context, Result};
+use circuit_definitions::boojum::cs::implementations::pow::NoPow;
+use circuit_definitions::boojum::field::PrimeField;
+use circuit_definitions::circuit_definitions::recursion_layer::{
+    ZkSyncRecursionLayerProof, ZkSyncRecursionLayerStorageType, ZkSyncRecursionLayerVerificationKey,
+};
+use zkevm_test_harness::prover_utils::verify_recursion_layer_proof_for_type;
+
+// Index of the recursive variant of the FriProofWrapper enum of the prover, bincode encodes it as a u32
+const FRI_PROOF_WRAPPER_RECURSIVE: u32 = 1;
+
+fn main() -> Result<()> {
+    let args: Vec<String> = std::env::args().collect();
+    if args.len() != 3 {
+        bail!("usage: {} <proof> <verification key>", args[0]);
+    }
+
+    let wrapped_proof = std::fs::read(&args[1]).context("could not read the proof")?;
+    let (variant, proof_bytes) = wrapped_proof.split_at(4);
+    if u32::from_le_bytes(variant.try_into()?) != FRI_PROOF_WRAPPER_RECURSIVE {
+        bail!("the proof is not a recursion layer proof");
+    }
+    let proof: ZkSyncRecursionLayerProof = bincode::deserialize(proof_bytes)?;
+
+    let vk: ZkSyncRecursionLayerVerificationKey =
+        serde_json::from_slice(&std::fs::read(&args[2]).context("could not read the verification key")?)?;
+
+    let storage_type = ZkSyncRecursionLayerStorageType::RecursionTipCircuit;
+    if vk.numeric_circuit_type() != storage_type as u8 || proof.numeric_circuit_type() != storage_type as u8 {
+        bail!("the proof and the verification key must be of the recursion tip circuit");
+    }
+    if !verify_recursion_layer_proof_for_type::<NoPow>(storage_type, &proof, &vk) {
+        bail!("the proof doesn't verify");
+    }
+
+    // The public input is each public input of the proof as a little endian u64
+    let pub_input: Vec<u8> = proof
+        .clone()
+        .into_inner()
+        .public_inputs
+        .iter()
+        .flat_map(|input| input.as_u64_reduced().to_le_bytes())
+        .collect();
+
+    std::fs::write("../boojum_recursion_tip.proof", bincode::serialize(&proof)?)?;
+    std::fs::write("../boojum_recursion_tip.pub", pub_input)?;
+    std::fs::write("../boojum_recursion_tip.vk", bincode::serialize(&vk)?)?;
+
+    println!("Boojum recursion tip proof, public input and verification key written");
+    Ok(())
+}
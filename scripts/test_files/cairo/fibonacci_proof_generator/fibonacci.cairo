@@ -0,0 +1,15 @@
+%builtins output
+
+// Outputs the 10th element of the Fibonacci sequence starting at 1, 1
+func main(output_ptr: felt*) -> (output_ptr: felt*) {
+    let res = fib(1, 1, 10);
+    assert output_ptr[0] = res;
+    return (output_ptr=output_ptr + 1);
+}
+
+func fib(first_element: felt, second_element: felt, n: felt) -> felt {
+    if (n == 0) {
+        return second_element;
+    }
+    return fib(second_element, first_element + second_element, n - 1);
+}
@@ -0,0 +1,85 @@
+#!/bin/bash
+# Generates the Stone proof of fibonacci.cairo with the recursive layout and keccak commitments,
+# which is the configuration the operator verifies, and its public input: the program hash
+# followed by the program output, each felt as 32 big endian bytes. It also proves it with a
+# single FRI query and no proof of work, which the operator must reject for its low security.
+# Requires cairo-lang and the Stone prover cpu_air_prover in the PATH.
+set -e
+
+cd "$(dirname "$0")"
+OUT_DIR=..
+
+cairo-compile fibonacci.cairo --output fibonacci_compiled.json --proof_mode
+cairo-run --program fibonacci_compiled.json --layout recursive --proof_mode --print_output \
+    --air_public_input air_public_input.json --air_private_input air_private_input.json \
+    --trace_file trace.bin --memory_file memory.bin > run_output.txt
+
+# Writes the Stone params for the given number of FRI queries, log of the blowup and proof of work
+# bits, the FRI steps depend on the number of steps of the execution
+write_params() {
+python3 - "$1" "$2" "$3" <<'PYTHON'
+import json, math, sys
+
+n_queries, log_n_cosets, proof_of_work_bits = (int(arg) for arg in sys.argv[1:])
+
+n_steps = json.load(open("air_public_input.json"))["n_steps"]
+last_layer_degree_bound = 64
+remaining = int(math.log2(n_steps)) + 4 - int(math.log2(last_layer_degree_bound))
+fri_step_list = [0] + [4] * (remaining // 4) + ([remaining % 4] if remaining % 4 else [])
+
+params = {
+    "field": "PrimeField0",
+    "channel_hash": "keccak256",
+    "commitment_hash": "keccak256_masked160_lsb",
+    "n_verifier_friendly_commitment_layers": 0,
+    "pow_hash": "keccak256",
+    "statement": {"page_hash": "pedersen"},
+    "stark": {
+        "fri": {
+            "fri_step_list": fri_step_list,
+            "last_layer_degree_bound": last_layer_degree_bound,
+            "n_queries": n_queries,
+            "proof_of_work_bits": proof_of_work_bits,
+        },
+        "log_n_cosets": log_n_cosets,
+    },
+    "use_extension_field": False,
+    "verifier_friendly_channel_updates": True,
+    "verifier_friendly_commitment_hash": "poseidon3",
+}
+json.dump(params, open("cpu_air_params.json", "w"), indent=4)
+PYTHON
+}
+
+prove() {
+cpu_air_prover \
+    --out_file=$1 \
+    --private_input_file=air_private_input.json \
+    --public_input_file=air_public_input.json \
+    --prover_config_file=cpu_air_prover_config.json \
+    --parameter_file=cpu_air_params.json \
+    --generate_annotations
+}
+
+# 18 queries with a blowup of 16 and 24 proof of work bits give the 96 bits the operator requires
+write_params 18 4 24
+prove $OUT_DIR/cairo_fibonacci.proof
+write_params 1 1 0
+prove $OUT_DIR/cairo_fibonacci_low_security.proof
+
+PROGRAM_HASH=$(cairo-hash-program --program fibonacci_compiled.json --use_poseidon true)
+
+python3 - "$PROGRAM_HASH" $OUT_DIR/cairo_fibonacci.pub <<'PYTHON'
+import sys
+
+program_hash, out_file = sys.argv[1], sys.argv[2]
+lines = open("run_output.txt").read().split("Program output:")[1].strip().splitlines()
+output = [int(line) for line in lines if line.strip()]
+
+with open(out_file, "wb") as f:
+    for felt in [int(program_hash, 16)] + output:
+        f.write((felt % (2**251 + 17 * 2**192 + 1)).to_bytes(32, "big"))
+PYTHON
+
+rm -f fibonacci_compiled.json air_public_input.json air_private_input.json trace.bin memory.bin run_output.txt cpu_air_params.json
+echo "Cairo Fibonacci proofs and public input generated in scripts/test_files/cairo folder"
@@ -0,0 +1,153 @@
+use anyhow::Result;
+use halo2_frontend::circuit::compile_circuit;
+use halo2_proofs::circuit::{Layouter, SimpleFloorPlanner, Value};
+use halo2_proofs::plonk::{
+    create_proof, keygen_pk, keygen_vk, Advice, Circuit, Column, ConstraintSystem, Error,
+    Instance, Selector,
+};
+use halo2_proofs::poly::commitment::ParamsProver;
+use halo2_proofs::poly::kzg::commitment::{KZGCommitmentScheme, ParamsKZG};
+use halo2_proofs::poly::kzg::multiopen::ProverSHPLONK;
+use halo2_proofs::poly::Rotation;
+use halo2_proofs::transcript::{Blake2bWrite, Challenge255, TranscriptWriterBuffer};
+use halo2_proofs::SerdeFormat;
+use halo2curves::bn256::{Bn256, Fr, G1Affine};
+use halo2curves::ff::PrimeField;
+use rand_core::OsRng;
+
+// Parameter blob header of KZG over bn256, see operator/halo2/params.go
+const PARAMS_VERSION: u8 = 1;
+const KZG: u8 = 0;
+const BN256: u8 = 0;
+const K: u32 = 4;
+// Number of additions of the Fibonacci sequence the circuit proves
+const STEPS: usize = 8;
+
+#[derive(Clone, Debug)]
+struct FibonacciConfig {
+    advice: [Column<Advice>; 3],
+    selector: Selector,
+    instance: Column<Instance>,
+}
+
+/// Proves the element STEPS + 2 of the Fibonacci sequence starting at the first two instances is
+/// the third instance
+#[derive(Clone, Default)]
+struct FibonacciCircuit;
+
+impl Circuit<Fr> for FibonacciCircuit {
+    type Config = FibonacciConfig;
+    type FloorPlanner = SimpleFloorPlanner;
+
+    fn without_witnesses(&self) -> Self {
+        Self
+    }
+
+    fn configure(meta: &mut ConstraintSystem<Fr>) -> FibonacciConfig {
+        let advice = [meta.advice_column(), meta.advice_column(), meta.advice_column()];
+        let instance = meta.instance_column();
+        let selector = meta.selector();
+        for column in advice {
+            meta.enable_equality(column);
+        }
+        meta.enable_equality(instance);
+
+        meta.create_gate("a + b = c", |meta| {
+            let s = meta.query_selector(selector);
+            let a = meta.query_advice(advice[0], Rotation::cur());
+            let b = meta.query_advice(advice[1], Rotation::cur());
+            let c = meta.query_advice(advice[2], Rotation::cur());
+            vec![s * (a + b - c)]
+        });
+
+        FibonacciConfig {
+            advice,
+            selector,
+            instance,
+        }
+    }
+
+    fn synthesize(&self, config: FibonacciConfig, mut layouter: impl Layouter<Fr>) -> Result<(), Error> {
+        let out = layouter.assign_region(
+            || "fibonacci",
+            |mut region| {
+                let mut a = region.assign_advice_from_instance(|| "a", config.instance, 0, config.advice[0], 0)?;
+                let mut b = region.assign_advice_from_instance(|| "b", config.instance, 1, config.advice[1], 0)?;
+                let mut c = None;
+                for row in 0..STEPS {
+                    config.selector.enable(&mut region, row)?;
+                    let value: Value<Fr> = a.value().copied() + b.value().copied();
+                    let cell = region.assign_advice(|| "c", config.advice[2], row, || value)?;
+                    if row + 1 < STEPS {
+                        a = b.copy_advice(|| "a", &mut region, config.advice[0], row + 1)?;
+                        b = cell.copy_advice(|| "b", &mut region, config.advice[1], row + 1)?;
+                    }
+                    c = Some(cell);
+                }
+                Ok(c.expect("at least one step"))
+            },
+        )?;
+        layouter.constrain_instance(out.cell(), config.instance, 2)
+    }
+}
+
+fn length_prefixed(bytes: &[u8]) -> Vec<u8> {
+    let mut prefixed = (bytes.len() as u32).to_le_bytes().to_vec();
+    prefixed.extend_from_slice(bytes);
+    prefixed
+}
+
+fn main() -> Result<()> {
+    let (mut a, mut b) = (Fr::from(1), Fr::from(1));
+    for _ in 0..STEPS {
+        (a, b) = (b, a + b);
+    }
+    let instances = vec![vec![Fr::from(1), Fr::from(1), b]];
+
+    let circuit = FibonacciCircuit;
+    let params = ParamsKZG::<Bn256>::setup(K, OsRng);
+    let vk = keygen_vk(&params, &circuit)?;
+    let pk = keygen_pk(&params, vk.clone(), &circuit)?;
+
+    let mut transcript = Blake2bWrite::<_, G1Affine, Challenge255<_>>::init(vec![]);
+    create_proof::<KZGCommitmentScheme<Bn256>, ProverSHPLONK<_>, _, _, _, _>(
+        &params,
+        &pk,
+        &[circuit.clone()],
+        &[instances.clone()],
+        OsRng,
+        &mut transcript,
+    )?;
+    let proof = transcript.finalize();
+
+    // keygen_vk compresses the selectors
+    let (compiled_circuit, _, _) = compile_circuit(K, &circuit, true)?;
+    let constraint_system = bincode::serialize(&compiled_circuit.cs)?;
+    let mut verifying_key = Vec::new();
+    vk.write(&mut verifying_key, SerdeFormat::RawBytes)?;
+    let mut verifier_params = Vec::new();
+    params
+        .verifier_params()
+        .write_custom(&mut verifier_params, SerdeFormat::RawBytes)?;
+
+    let mut params_blob = vec![PARAMS_VERSION, KZG, BN256, K as u8];
+    params_blob.extend(length_prefixed(&constraint_system));
+    params_blob.extend(length_prefixed(&verifying_key));
+    params_blob.extend(verifier_params);
+
+    // The public input is the number of instance columns, followed by each column length and values
+    let mut pub_input = (instances.len() as u32).to_le_bytes().to_vec();
+    for column in &instances {
+        pub_input.extend((column.len() as u32).to_le_bytes());
+        for value in column {
+            pub_input.extend(value.to_repr());
+        }
+    }
+
+    std::fs::write("../halo2_fibonacci.proof", proof)?;
+    std::fs::write("../halo2_fibonacci.pub", pub_input)?;
+    std::fs::write("../halo2_fibonacci.params", params_blob)?;
+
+    println!("Halo2 KZG Fibonacci proof, instances and params generated");
+    Ok(())
+}
@@ -0,0 +1,14 @@
+#![cfg_attr(feature = "guest", no_std)]
+#![no_main]
+
+#[jolt::provable]
+fn fib(n: u32) -> u128 {
+    let mut a: u128 = 0;
+    let mut b: u128 = 1;
+    for _ in 1..n {
+        let sum = a + b;
+        a = b;
+        b = sum;
+    }
+    b
+}
@@ -0,0 +1,54 @@
+use anyhow::{anyhow, Result};
+use jolt_core::jolt::vm::rv32i_vm::RV32IJoltVM;
+use jolt_core::jolt::vm::Jolt;
+use jolt_sdk::host::Program;
+use jolt_sdk::host_utils::JoltHyperKZGProof;
+use jolt_sdk::Serializable;
+
+// Same preprocessing bounds as the verifier
+const MAX_BYTECODE_SIZE: usize = 1 << 20;
+const MAX_MEMORY_SIZE: usize = 1 << 24;
+const MAX_TRACE_LENGTH: usize = 1 << 24;
+
+/// Proves the fib function of the guest for n = 50, writing the proof, the guest ELF and its
+/// program io, which holds the input and the output
+fn main() -> Result<()> {
+    let mut program = Program::new("fibonacci-guest");
+    program.set_func("fib");
+    program.set_input(&50u32);
+    program.build(jolt_sdk::host::DEFAULT_TARGET_DIR);
+    let elf_path = program
+        .elf
+        .clone()
+        .ok_or_else(|| anyhow!("the guest wasn't built"))?;
+
+    let (bytecode, memory_init) = program.decode();
+    let (program_io, trace) = program.trace();
+
+    let preprocessing = RV32IJoltVM::prover_preprocess(
+        bytecode,
+        program_io.memory_layout.clone(),
+        memory_init,
+        MAX_BYTECODE_SIZE,
+        MAX_MEMORY_SIZE,
+        MAX_TRACE_LENGTH,
+    );
+    let (proof, commitments, _) = RV32IJoltVM::prove(program_io.clone(), trace, preprocessing.clone());
+
+    RV32IJoltVM::verify(
+        preprocessing.shared,
+        proof.clone(),
+        commitments.clone(),
+        program_io.clone(),
+        None,
+    )
+    .map_err(|err| anyhow!("the proof doesn't verify: {err:?}"))?;
+
+    let proof = JoltHyperKZGProof { proof, commitments };
+    std::fs::write("../jolt_fibonacci.proof", proof.serialize_to_bytes()?)?;
+    std::fs::write("../jolt_fibonacci.elf", std::fs::read(elf_path)?)?;
+    std::fs::write("../jolt_fibonacci.io", program_io.serialize_to_bytes()?)?;
+
+    println!("Jolt Fibonacci proof, ELF and program io generated");
+    Ok(())
+}
@@ -0,0 +1,111 @@
+use std::array;
+use std::sync::Arc;
+
+use anyhow::{anyhow, Result};
+use ark_ff::{BigInteger, PrimeField, Zero};
+use groupmap::GroupMap;
+use kimchi::circuits::constraints::ConstraintSystem;
+use kimchi::circuits::gate::{CircuitGate, Connect};
+use kimchi::circuits::polynomials::generic::GenericGateSpec;
+use kimchi::circuits::wires::{Wire, COLUMNS};
+use kimchi::curve::KimchiCurve;
+use kimchi::proof::ProverProof;
+use kimchi::prover_index::ProverIndex;
+use kimchi::verifier::verify;
+use mina_curves::pasta::{Fp, Vesta, VestaParameters};
+use mina_poseidon::constants::PlonkSpongeConstantsKimchi;
+use mina_poseidon::sponge::{DefaultFqSponge, DefaultFrSponge};
+use poly_commitment::commitment::CommitmentCurve;
+use poly_commitment::evaluation_proof::OpeningProof;
+use poly_commitment::srs::SRS;
+
+// Same curve and sponges the verifier expects
+type BaseSponge = DefaultFqSponge<VestaParameters, PlonkSpongeConstantsKimchi>;
+type ScalarSponge = DefaultFrSponge<Fp, PlonkSpongeConstantsKimchi>;
+
+const NUM_PUBLIC: usize = 3;
+const STEPS: usize = 20;
+
+/// Proves the third public input is the element STEPS + 2 of the Fibonacci sequence starting at the
+/// first two. The first rows hold the public inputs, each of the next ones an addition.
+fn main() -> Result<()> {
+    let mut gates = Vec::new();
+    for row in 0..NUM_PUBLIC + STEPS {
+        let spec = if row < NUM_PUBLIC {
+            GenericGateSpec::Pub
+        } else {
+            GenericGateSpec::Add {
+                left_coeff: None,
+                right_coeff: None,
+                output_coeff: None,
+            }
+        };
+        gates.push(CircuitGate::create_generic_gadget(Wire::for_row(row), spec, None));
+    }
+
+    let mut witness: [Vec<Fp>; COLUMNS] = array::from_fn(|_| vec![Fp::zero(); gates.len()]);
+    let (mut a, mut b) = (Fp::from(1u64), Fp::from(1u64));
+    witness[0][0] = a;
+    witness[0][1] = b;
+    gates.connect_cell_pair((0, 0), (NUM_PUBLIC, 0));
+    gates.connect_cell_pair((1, 0), (NUM_PUBLIC, 1));
+    for step in 0..STEPS {
+        let row = NUM_PUBLIC + step;
+        witness[0][row] = a;
+        witness[1][row] = b;
+        witness[2][row] = a + b;
+        if step + 1 < STEPS {
+            gates.connect_cell_pair((row, 1), (row + 1, 0));
+            gates.connect_cell_pair((row, 2), (row + 1, 1));
+        }
+        (a, b) = (b, a + b);
+    }
+    witness[0][2] = b;
+    gates.connect_cell_pair((2, 0), (NUM_PUBLIC + STEPS - 1, 2));
+    let public_input = vec![witness[0][0], witness[0][1], witness[0][2]];
+
+    let cs = ConstraintSystem::<Fp>::create(gates)
+        .public(NUM_PUBLIC)
+        .build()
+        .map_err(|err| anyhow!("could not build the constraint system: {err:?}"))?;
+    // The verifier creates the SRS of the max poly size of the verifier index, the domain size
+    let mut srs = SRS::<Vesta>::create(cs.domain.d1.size());
+    srs.add_lagrange_basis(cs.domain.d1);
+    let index = ProverIndex::<Vesta, OpeningProof<Vesta>>::create(
+        cs,
+        *Vesta::other_curve_endo(),
+        Arc::new(srs),
+    );
+
+    let group_map = <Vesta as CommitmentCurve>::Map::setup();
+    let proof = ProverProof::create::<BaseSponge, ScalarSponge, _>(
+        &group_map,
+        witness,
+        &[],
+        &index,
+        &mut rand::rngs::OsRng,
+    )
+    .map_err(|err| anyhow!("could not prove: {err:?}"))?;
+
+    let verifier_index = index.verifier_index();
+    verify::<Vesta, BaseSponge, ScalarSponge, OpeningProof<Vesta>>(
+        &group_map,
+        &verifier_index,
+        &proof,
+        &public_input,
+    )
+    .map_err(|err| anyhow!("the proof doesn't verify: {err:?}"))?;
+
+    // The public input is each element as 32 bytes little endian
+    let pub_input: Vec<u8> = public_input
+        .iter()
+        .flat_map(|element| element.into_bigint().to_bytes_le())
+        .collect();
+
+    std::fs::write("../kimchi_fibonacci.proof", rmp_serde::to_vec(&proof)?)?;
+    std::fs::write("../kimchi_fibonacci.pub", pub_input)?;
+    std::fs::write("../kimchi_fibonacci.vk", rmp_serde::to_vec(&verifier_index)?)?;
+
+    println!("Kimchi Fibonacci proof, public input and verifier index generated");
+    Ok(())
+}
@@ -0,0 +1,46 @@
+use anyhow::{anyhow, Result};
+use miden_vm::math::{Felt, StarkField};
+use miden_vm::{prove, Assembler, DefaultHost, ProvingOptions, StackInputs};
+
+/// Takes the top two elements of the stack through ten steps of the Fibonacci sequence
+const PROGRAM: &str = "begin repeat.10 swap dup.1 add end end";
+
+fn felts_to_bytes(felts: &[Felt]) -> Vec<u8> {
+    felts
+        .iter()
+        .flat_map(|felt| felt.as_int().to_le_bytes())
+        .collect()
+}
+
+fn main() -> Result<()> {
+    let program = Assembler::default()
+        .assemble_program(PROGRAM)
+        .map_err(|err| anyhow!("could not assemble the program: {err}"))?;
+
+    let inputs = vec![Felt::new(0), Felt::new(1)];
+    let stack_inputs = StackInputs::new(inputs.clone())
+        .map_err(|err| anyhow!("invalid stack inputs: {err}"))?;
+
+    let (stack_outputs, proof) = prove(
+        &program,
+        stack_inputs,
+        DefaultHost::default(),
+        ProvingOptions::default(),
+    )
+    .map_err(|err| anyhow!("could not prove the program: {err}"))?;
+
+    // The public input is the number of stack inputs, the stack inputs and the stack outputs
+    let mut pub_input = (inputs.len() as u32).to_le_bytes().to_vec();
+    pub_input.extend(felts_to_bytes(&inputs));
+    pub_input.extend(felts_to_bytes(stack_outputs.stack()));
+
+    std::fs::write("../miden_fibonacci.proof", proof.to_bytes())?;
+    std::fs::write(
+        "../miden_fibonacci.hash",
+        felts_to_bytes(program.hash().as_elements()),
+    )?;
+    std::fs::write("../miden_fibonacci.pub", pub_input)?;
+
+    println!("Miden Fibonacci proof, program hash and public input generated");
+    Ok(())
+}
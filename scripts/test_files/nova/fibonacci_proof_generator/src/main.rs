@@ -0,0 +1,107 @@
+use anyhow::Result;
+use bellpepper_core::{num::AllocatedNum, ConstraintSystem, SynthesisError};
+use ff::PrimeField;
+use nova_snark::provider::{hyperkzg, ipa_pc, Bn256EngineKZG, GrumpkinEngine};
+use nova_snark::spartan::snark::RelaxedR1CSSNARK;
+use nova_snark::traits::circuit::{StepCircuit, TrivialCircuit};
+use nova_snark::traits::snark::RelaxedR1CSSNARKTrait;
+use nova_snark::traits::Engine;
+use nova_snark::{CompressedSNARK, PublicParams, RecursiveSNARK};
+use serde::Serialize;
+
+// Same curves and SNARKs the verifier expects
+type E1 = Bn256EngineKZG;
+type E2 = GrumpkinEngine;
+type S1 = RelaxedR1CSSNARK<E1, hyperkzg::EvaluationEngine<E1>>;
+type S2 = RelaxedR1CSSNARK<E2, ipa_pc::EvaluationEngine<E2>>;
+type C1 = FibonacciCircuit<<E1 as Engine>::Scalar>;
+type C2 = TrivialCircuit<<E2 as Engine>::Scalar>;
+
+const NUM_STEPS: usize = 10;
+
+/// Serialized as the public input the verifier deserializes
+#[derive(Serialize)]
+struct NovaPublicInput {
+    num_steps: usize,
+    z0_primary: Vec<<E1 as Engine>::Scalar>,
+    z0_secondary: Vec<<E2 as Engine>::Scalar>,
+    zn_primary: Vec<<E1 as Engine>::Scalar>,
+    zn_secondary: Vec<<E2 as Engine>::Scalar>,
+}
+
+/// Each step takes the last two elements of the Fibonacci sequence to the next two
+#[derive(Clone, Debug, Default)]
+struct FibonacciCircuit<F: PrimeField> {
+    _p: std::marker::PhantomData<F>,
+}
+
+impl<F: PrimeField> StepCircuit<F> for FibonacciCircuit<F> {
+    fn arity(&self) -> usize {
+        2
+    }
+
+    fn synthesize<CS: ConstraintSystem<F>>(
+        &self,
+        cs: &mut CS,
+        z: &[AllocatedNum<F>],
+    ) -> Result<Vec<AllocatedNum<F>>, SynthesisError> {
+        let next = AllocatedNum::alloc(cs.namespace(|| "next"), || {
+            let a = z[0].get_value().ok_or(SynthesisError::AssignmentMissing)?;
+            let b = z[1].get_value().ok_or(SynthesisError::AssignmentMissing)?;
+            Ok(a + b)
+        })?;
+        cs.enforce(
+            || "next = a + b",
+            |lc| lc + z[0].get_variable() + z[1].get_variable(),
+            |lc| lc + CS::one(),
+            |lc| lc + next.get_variable(),
+        );
+        Ok(vec![z[1].clone(), next])
+    }
+}
+
+fn main() -> Result<()> {
+    let circuit_primary = C1::default();
+    let circuit_secondary = C2::default();
+
+    let pp = PublicParams::<E1, E2, C1, C2>::setup(
+        &circuit_primary,
+        &circuit_secondary,
+        &*S1::ck_floor(),
+        &*S2::ck_floor(),
+    )?;
+
+    let z0_primary = vec![<E1 as Engine>::Scalar::from(0), <E1 as Engine>::Scalar::from(1)];
+    let z0_secondary = vec![<E2 as Engine>::Scalar::from(0)];
+
+    let mut recursive_snark = RecursiveSNARK::<E1, E2, C1, C2>::new(
+        &pp,
+        &circuit_primary,
+        &circuit_secondary,
+        &z0_primary,
+        &z0_secondary,
+    )?;
+    for _ in 0..NUM_STEPS {
+        recursive_snark.prove_step(&pp, &circuit_primary, &circuit_secondary)?;
+    }
+    let num_steps = recursive_snark.num_steps();
+
+    let (pk, vk) = CompressedSNARK::<E1, E2, C1, C2, S1, S2>::setup(&pp)?;
+    let proof = CompressedSNARK::<E1, E2, C1, C2, S1, S2>::prove(&pp, &pk, &recursive_snark)?;
+    let (zn_primary, zn_secondary) = proof.verify(&vk, num_steps, &z0_primary, &z0_secondary)?;
+
+    let pub_input = NovaPublicInput {
+        num_steps,
+        z0_primary,
+        z0_secondary,
+        zn_primary,
+        zn_secondary,
+    };
+
+    std::fs::write("../nova_fibonacci.proof", bincode::serialize(&proof)?)?;
+    std::fs::write("../nova_fibonacci.pub", bincode::serialize(&pub_input)?)?;
+    std::fs::write("../nova_fibonacci.vk", bincode::serialize(&vk)?)?;
+
+    println!("Nova Fibonacci compressed SNARK, public input and verifier key generated");
+    Ok(())
+}
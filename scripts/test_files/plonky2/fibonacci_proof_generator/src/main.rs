@@ -0,0 +1,51 @@
+use anyhow::Result;
+use plonky2::field::types::Field;
+use plonky2::iop::witness::{PartialWitness, WitnessWrite};
+use plonky2::plonk::circuit_builder::CircuitBuilder;
+use plonky2::plonk::circuit_data::CircuitConfig;
+use plonky2::plonk::config::{GenericConfig, PoseidonGoldilocksConfig};
+use plonky2::util::serialization::DefaultGateSerializer;
+
+// Same configuration the verifier expects
+const D: usize = 2;
+type C = PoseidonGoldilocksConfig;
+type F = <C as GenericConfig<D>>::F;
+
+/// Proves the 100th element of the Fibonacci sequence starting at the two public inputs
+fn main() -> Result<()> {
+    let config = CircuitConfig::standard_recursion_config();
+    let mut builder = CircuitBuilder::<F, D>::new(config);
+
+    let initial_a = builder.add_virtual_target();
+    let initial_b = builder.add_virtual_target();
+    let mut prev_target = initial_a;
+    let mut cur_target = initial_b;
+    for _ in 0..99 {
+        let temp = builder.add(prev_target, cur_target);
+        prev_target = cur_target;
+        cur_target = temp;
+    }
+
+    builder.register_public_input(initial_a);
+    builder.register_public_input(initial_b);
+    builder.register_public_input(cur_target);
+
+    let mut pw = PartialWitness::new();
+    pw.set_target(initial_a, F::ZERO);
+    pw.set_target(initial_b, F::ONE);
+
+    let data = builder.build::<C>();
+    let proof = data.prove(pw)?;
+    data.verify(proof.clone())?;
+
+    let verifier_data = data
+        .verifier_data()
+        .to_bytes(&DefaultGateSerializer)
+        .map_err(|_| anyhow::anyhow!("could not serialize the verifier data"))?;
+
+    std::fs::write("../plonky2_fibonacci.proof", proof.to_bytes())?;
+    std::fs::write("../plonky2_fibonacci.vd", verifier_data)?;
+
+    println!("Plonky2 Fibonacci proof and verifier data generated");
+    Ok(())
+}
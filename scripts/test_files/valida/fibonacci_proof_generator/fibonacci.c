@@ -0,0 +1,24 @@
+#include <stdio.h>
+
+// Writes the 25th element of the Fibonacci sequence to the output, in decimal
+int main() {
+    unsigned int a = 0;
+    unsigned int b = 1;
+    for (int i = 0; i < 25; i++) {
+        unsigned int c = a + b;
+        a = b;
+        b = c;
+    }
+
+    char digits[10];
+    int n = 0;
+    do {
+        digits[n++] = '0' + a % 10;
+        a /= 10;
+    } while (a > 0);
+    while (n > 0) {
+        putchar(digits[--n]);
+    }
+    putchar('\n');
+    return 0;
+}
@@ -0,0 +1,20 @@
+#!/bin/bash
+# Compiles fibonacci.c to Valida machine code and proves its execution with the Valida CLI, whose
+# STARK configuration is the one the operator verifies. The public input is the program output.
+# Requires the Valida v0.5.0-alpha toolchain, e.g. in its build container
+# ghcr.io/lita-xyz/llvm-valida-releases/valida-build-container:v0.5.0-alpha
+set -e
+
+cd "$(dirname "$0")"
+OUT_DIR=..
+VALIDA_TOOLCHAIN=${VALIDA_TOOLCHAIN:-/valida-toolchain}
+
+clang -c -target delendum fibonacci.c -o fibonacci.o
+ld.lld --script=$VALIDA_TOOLCHAIN/valida.ld -o $OUT_DIR/valida_fibonacci.bin fibonacci.o
+
+valida run $OUT_DIR/valida_fibonacci.bin $OUT_DIR/valida_fibonacci.pub
+valida prove $OUT_DIR/valida_fibonacci.bin $OUT_DIR/valida_fibonacci.proof
+valida verify $OUT_DIR/valida_fibonacci.bin $OUT_DIR/valida_fibonacci.proof
+
+rm -f fibonacci.o
+echo "Valida Fibonacci program, proof and output generated in scripts/test_files/valida folder"
@@ -0,0 +1,128 @@
+package aligned
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	csservicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// verifyBatchInclusionMethod is the overload of verifyBatchInclusion taking the sender address,
+// the one the other overload calls with the BatcherPaymentService address
+const verifyBatchInclusionMethod = "verifyBatchInclusion"
+
+// VerifyBatchInclusionCaller calls verifyBatchInclusion, as the service manager binding does
+type VerifyBatchInclusionCaller interface {
+	VerifyBatchInclusion(opts *bind.CallOpts, proofCommitment [32]byte, pubInputCommitment [32]byte, provingSystemAuxDataCommitment [32]byte, proofGeneratorAddr [20]byte, batchMerkleRoot [32]byte, merkleProof []byte, verificationDataBatchIndex *big.Int, senderAddress ethcommon.Address) (bool, error)
+}
+
+// VerifyBatchInclusionArgs are the arguments of the verifyBatchInclusion function of the
+// AlignedLayerServiceManager contract, which returns whether a proof was verified by Aligned
+type VerifyBatchInclusionArgs struct {
+	ProofCommitment                [32]byte
+	PubInputCommitment             [32]byte
+	ProvingSystemAuxDataCommitment [32]byte
+	ProofGeneratorAddr             [20]byte
+	BatchMerkleRoot                [32]byte
+	// MerkleProof is the concatenation of the nodes of the merkle path
+	MerkleProof                []byte
+	VerificationDataBatchIndex *big.Int
+	// SenderAddress is the address that created the task of the batch
+	SenderAddress ethcommon.Address
+}
+
+// NewVerifyBatchInclusionArgs returns the arguments to check the proof of the aligned
+// verification data was verified, in a batch created by the batcher of the network
+func NewVerifyBatchInclusionArgs(alignedVerificationData batcher.AlignedVerificationData, network Network) (VerifyBatchInclusionArgs, error) {
+	paymentServiceAddr, err := network.BatcherPaymentServiceAddress()
+	if err != nil {
+		return VerifyBatchInclusionArgs{}, err
+	}
+	return verifyBatchInclusionArgs(&alignedVerificationData, paymentServiceAddr), nil
+}
+
+func verifyBatchInclusionArgs(data *batcher.AlignedVerificationData, senderAddress ethcommon.Address) VerifyBatchInclusionArgs {
+	merkleProof := make([]byte, 0, len(data.BatchInclusionProof)*32)
+	for _, node := range data.BatchInclusionProof {
+		merkleProof = append(merkleProof, node[:]...)
+	}
+	commitment := data.VerificationDataCommitment
+	return VerifyBatchInclusionArgs{
+		ProofCommitment:                commitment.ProofCommitment,
+		PubInputCommitment:             commitment.PubInputCommitment,
+		ProvingSystemAuxDataCommitment: commitment.ProvingSystemAuxDataCommitment,
+		ProofGeneratorAddr:             commitment.ProofGeneratorAddr,
+		BatchMerkleRoot:                data.BatchMerkleRoot,
+		MerkleProof:                    merkleProof,
+		VerificationDataBatchIndex:     new(big.Int).SetUint64(data.IndexInBatch),
+		SenderAddress:                  senderAddress,
+	}
+}
+
+func verifyBatchInclusionAbiMethod() (abi.Method, error) {
+	serviceManagerAbi, err := csservicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		return abi.Method{}, err
+	}
+	method, ok := serviceManagerAbi.Methods[verifyBatchInclusionMethod]
+	if !ok {
+		return abi.Method{}, fmt.Errorf("method %s not in the service manager abi", verifyBatchInclusionMethod)
+	}
+	return method, nil
+}
+
+// EncodedArgs returns the ABI encoding of the arguments, without the function selector, to be
+// passed on to contracts that call verifyBatchInclusion themselves
+func (a *VerifyBatchInclusionArgs) EncodedArgs() ([]byte, error) {
+	method, err := verifyBatchInclusionAbiMethod()
+	if err != nil {
+		return nil, err
+	}
+	return method.Inputs.Pack(a.values()...)
+}
+
+// Calldata returns the calldata of the verifyBatchInclusion call, the function selector followed
+// by the encoded arguments
+func (a *VerifyBatchInclusionArgs) Calldata() ([]byte, error) {
+	method, err := verifyBatchInclusionAbiMethod()
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Pack(a.values()...)
+	if err != nil {
+		return nil, err
+	}
+	return append(method.ID, args...), nil
+}
+
+// Call calls verifyBatchInclusion with the arguments, returning whether the proof was verified
+func (a *VerifyBatchInclusionArgs) Call(opts *bind.CallOpts, caller VerifyBatchInclusionCaller) (bool, error) {
+	return caller.VerifyBatchInclusion(
+		opts,
+		a.ProofCommitment,
+		a.PubInputCommitment,
+		a.ProvingSystemAuxDataCommitment,
+		a.ProofGeneratorAddr,
+		a.BatchMerkleRoot,
+		a.MerkleProof,
+		a.VerificationDataBatchIndex,
+		a.SenderAddress,
+	)
+}
+
+func (a *VerifyBatchInclusionArgs) values() []interface{} {
+	return []interface{}{
+		a.ProofCommitment,
+		a.PubInputCommitment,
+		a.ProvingSystemAuxDataCommitment,
+		a.ProofGeneratorAddr,
+		a.BatchMerkleRoot,
+		a.MerkleProof,
+		a.VerificationDataBatchIndex,
+		a.SenderAddress,
+	}
+}
@@ -0,0 +1,46 @@
+package aligned
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVerifyBatchInclusionCalldata(t *testing.T) {
+	_, alignedVerificationData := testBatch()
+	args, err := NewVerifyBatchInclusionArgs(alignedVerificationData, Devnet)
+	if err != nil {
+		t.Fatalf("could not build args: %v", err)
+	}
+	paymentServiceAddr, _ := Devnet.BatcherPaymentServiceAddress()
+	if args.SenderAddress != paymentServiceAddr || !bytes.Equal(args.MerkleProof, alignedVerificationData.BatchInclusionProof[0][:]) {
+		t.Errorf("unexpected args %+v", args)
+	}
+
+	calldata, err := args.Calldata()
+	if err != nil {
+		t.Fatalf("could not encode calldata: %v", err)
+	}
+	selector := crypto.Keccak256([]byte("verifyBatchInclusion(bytes32,bytes32,bytes32,bytes20,bytes32,bytes,uint256,address)"))[:4]
+	if !bytes.Equal(calldata[:4], selector) {
+		t.Errorf("unexpected selector %x", calldata[:4])
+	}
+	encodedArgs, err := args.EncodedArgs()
+	if err != nil || !bytes.Equal(encodedArgs, calldata[4:]) {
+		t.Errorf("encoded args don't match the calldata: %v", err)
+	}
+
+	method, err := verifyBatchInclusionAbiMethod()
+	if err != nil {
+		t.Fatalf("could not get method: %v", err)
+	}
+	values, err := method.Inputs.Unpack(encodedArgs)
+	if err != nil {
+		t.Fatalf("could not decode args: %v", err)
+	}
+	if values[4].([32]byte) != args.BatchMerkleRoot || values[6].(*big.Int).Uint64() != 1 {
+		t.Errorf("unexpected decoded args %v", values)
+	}
+}
@@ -0,0 +1,85 @@
+package aligned
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// The errors of the SDK calls wrap one of these when their cause is known, so they can be handled
+// with errors.Is. ErrVerificationTimeout is returned when the wait for a verification times out.
+var (
+	// ErrInsufficientBalance is returned when the balance of the key in the BatcherPaymentService
+	// doesn't cover the max fee of the proofs sent
+	ErrInsufficientBalance = batcher.ErrInsufficientBalance
+	// ErrInvalidProof is returned when the batcher refuses a proof it couldn't verify
+	ErrInvalidProof = batcher.ErrInvalidProof
+	// ErrBatcherUnavailable is returned when the batcher can't be connected to, or drops the connection
+	ErrBatcherUnavailable = errors.New("batcher unavailable")
+	// ErrReplacedBatch is returned when a proof sent won't be included in a batch, as it was replaced
+	// by a message with its nonce or the batcher reset its queue, so it must be sent again
+	ErrReplacedBatch = errors.New("proof removed from the batcher queue")
+)
+
+// sdkError wraps the error of the batcher with the SDK error of its cause
+func sdkError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, batcher.ErrProofReplaced), errors.Is(err, batcher.ErrProofQueueFlushed):
+		return fmt.Errorf("%w: %w", ErrReplacedBatch, err)
+	case errors.Is(err, batcher.ErrConnection), errors.Is(err, batcher.ErrConnectionLost):
+		return fmt.Errorf("%w: %w", ErrBatcherUnavailable, err)
+	}
+	return err
+}
+
+// IsRetryable returns whether the call failing with the error may succeed if made again: when the
+// batcher is unavailable or failed to reach the chain, or the proofs must be sent again
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return errors.Is(err, ErrBatcherUnavailable) ||
+		errors.Is(err, ErrReplacedBatch) ||
+		errors.Is(err, batcher.ErrEthRpc) ||
+		errors.Is(err, batcher.ErrCreateNewTask)
+}
+
+// RetryPolicy configures how the SDK calls are retried by WithRetries
+type RetryPolicy struct {
+	// Params are the backoff and the max number of retries
+	Params *retry.RetryParams
+	// Retryable returns whether the error is retried, IsRetryable if nil
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy retries the retryable errors 3 times, waiting 1, 2 and 4 seconds
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{Params: retry.NetworkRetryParams()}
+}
+
+// WithRetries calls the function until it succeeds, it fails with an error the policy doesn't
+// retry, or the retries of the policy run out, returning the last error.
+// Sending proofs again after ErrReplacedBatch needs a new nonce, so the nonce of the submission
+// should be nil to fetch it from the batcher on each try.
+func WithRetries[T any](ctx context.Context, policy RetryPolicy, call func(ctx context.Context) (T, error)) (T, error) {
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	params := policy.Params
+	if params == nil {
+		params = retry.NetworkRetryParams()
+	}
+	return retry.RetryWithData(func() (T, error) {
+		result, err := call(ctx)
+		if err != nil && (ctx.Err() != nil || !retryable(err)) {
+			return result, retry.PermanentError{Inner: err}
+		}
+		return result, err
+	}, params)
+}
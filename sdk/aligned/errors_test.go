@@ -0,0 +1,75 @@
+package aligned
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+func TestSdkErrorClassifiesBatcherErrors(t *testing.T) {
+	cases := []struct {
+		err       error
+		expected  error
+		retryable bool
+	}{
+		{fmt.Errorf("%w: 0x01", batcher.ErrInsufficientBalance), ErrInsufficientBalance, false},
+		{batcher.ErrInvalidProof, ErrInvalidProof, false},
+		{fmt.Errorf("%w: dial tcp: connection refused", batcher.ErrConnection), ErrBatcherUnavailable, true},
+		{fmt.Errorf("%w: %w: closed", batcher.ErrProofReplaced, batcher.ErrConnectionLost), ErrReplacedBatch, true},
+		{batcher.ErrProofQueueFlushed, ErrReplacedBatch, true},
+	}
+	for _, c := range cases {
+		err := sdkError(c.err)
+		if !errors.Is(err, c.expected) {
+			t.Errorf("expected %v to be %v", err, c.expected)
+		}
+		if IsRetryable(err) != c.retryable {
+			t.Errorf("expected %v retryable to be %v", err, c.retryable)
+		}
+	}
+	if IsRetryable(fmt.Errorf("%w: %w", ErrVerificationTimeout, context.DeadlineExceeded)) {
+		t.Errorf("expected verification timeout not to be retryable")
+	}
+}
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{Params: &retry.RetryParams{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		Multiplier:      1,
+		NumRetries:      3,
+	}}
+}
+
+func TestWithRetriesRetriesRetryableErrors(t *testing.T) {
+	calls := 0
+	result, err := WithRetries(context.Background(), testRetryPolicy(), func(ctx context.Context) (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, sdkError(batcher.ErrConnection)
+		}
+		return calls, nil
+	})
+	if err != nil || result != 3 {
+		t.Errorf("expected success on the third call, got %d, %v", result, err)
+	}
+}
+
+func TestWithRetriesStopsOnPermanentErrors(t *testing.T) {
+	calls := 0
+	_, err := WithRetries(context.Background(), testRetryPolicy(), func(ctx context.Context) (int, error) {
+		calls++
+		return 0, sdkError(batcher.ErrInsufficientBalance)
+	})
+	if !errors.Is(err, ErrInsufficientBalance) {
+		t.Errorf("expected insufficient balance, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
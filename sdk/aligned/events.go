@@ -0,0 +1,279 @@
+package aligned
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	csservicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+const (
+	// DefaultEventsPollInterval is how often new blocks are checked for events
+	DefaultEventsPollInterval = 12 * time.Second
+	// DefaultEventsBlockRange is the max number of blocks to query events of at once
+	DefaultEventsBlockRange = 1000
+)
+
+type EventType string
+
+const (
+	// EventBatchCreated is emitted when the task of a batch is created, from the NewBatchV3 event
+	EventBatchCreated EventType = "batch_created"
+	// EventBatchVerified is emitted when the operators' response to a batch is accepted, from the BatchVerified event
+	EventBatchVerified EventType = "batch_verified"
+	// EventProofVerified is emitted for each of the watched proofs when the batch it was sent in is verified
+	EventProofVerified EventType = "proof_verified"
+)
+
+// Event is an event of the Aligned service manager
+type Event struct {
+	Type            EventType
+	BatchMerkleRoot [32]byte
+	// SenderAddress is the address that created the task of the batch
+	SenderAddress ethcommon.Address
+	// BatchDataPointer and RespondToTaskFeeLimit are only set for batch created events
+	BatchDataPointer      string
+	RespondToTaskFeeLimit *big.Int
+	// Proof is only set for proof verified events
+	Proof       *batcher.AlignedVerificationData
+	BlockNumber uint64
+	TxHash      ethcommon.Hash
+}
+
+// LogSource reads the logs of the chain, as ethclient.Client does
+type LogSource interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// SubscribeOpts selects the events to stream
+type SubscribeOpts struct {
+	// FromBlock is the first block to stream the events of, the latest block if 0, so past
+	// events can be backfilled
+	FromBlock uint64
+	// SenderAddress filters the batch events by the address that created their task, all
+	// of them if nil
+	SenderAddress *ethcommon.Address
+	// Proofs are the proofs sent through the batcher to emit proof verified events for
+	Proofs []batcher.AlignedVerificationData
+	// PollInterval defaults to DefaultEventsPollInterval, and BlockRange to DefaultEventsBlockRange
+	PollInterval time.Duration
+	BlockRange   uint64
+}
+
+// Subscription streams the events of the service manager until its context is done. Events
+// are read from the logs of the chain, so they are streamed in order, and a failing rpc is
+// retried from the last block read, without missing nor repeating events.
+type Subscription struct {
+	events chan Event
+	errs   chan error
+}
+
+// Events returns the events, closed when the context of the subscription is done
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Errors returns the errors reading the events, which are retried. Errors are dropped if not read.
+func (s *Subscription) Errors() <-chan error {
+	return s.errs
+}
+
+// eventsReader reads the events of a service manager from the logs
+type eventsReader struct {
+	source             LogSource
+	filterer           *csservicemanager.ContractAlignedLayerServiceManagerFilterer
+	serviceManagerAddr ethcommon.Address
+	paymentServiceAddr ethcommon.Address
+	topics             []ethcommon.Hash
+	opts               SubscribeOpts
+	// proofs are the watched proofs not verified yet, by batch merkle root
+	proofs map[[32]byte][]batcher.AlignedVerificationData
+}
+
+// SubscribeEvents streams the events of the service manager of the network from the block of
+// the options on. Proof verified events are emitted for proofs whose batch was created by the
+// BatcherPaymentService of the network.
+func SubscribeEvents(ctx context.Context, source LogSource, network Network, opts SubscribeOpts) (*Subscription, error) {
+	serviceManagerAddr, err := network.AlignedServiceManagerAddress()
+	if err != nil {
+		return nil, err
+	}
+	paymentServiceAddr, err := network.BatcherPaymentServiceAddress()
+	if err != nil {
+		return nil, err
+	}
+	reader, err := newEventsReader(source, serviceManagerAddr, paymentServiceAddr, opts)
+	if err != nil {
+		return nil, err
+	}
+	if reader.opts.FromBlock == 0 {
+		if reader.opts.FromBlock, err = source.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("could not get block number: %w", err)
+		}
+	}
+
+	subscription := &Subscription{
+		events: make(chan Event),
+		errs:   make(chan error, 1),
+	}
+	go reader.run(ctx, subscription)
+	return subscription, nil
+}
+
+func newEventsReader(source LogSource, serviceManagerAddr ethcommon.Address, paymentServiceAddr ethcommon.Address, opts SubscribeOpts) (*eventsReader, error) {
+	filterer, err := csservicemanager.NewContractAlignedLayerServiceManagerFilterer(serviceManagerAddr, nil)
+	if err != nil {
+		return nil, err
+	}
+	serviceManagerAbi, err := csservicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = DefaultEventsPollInterval
+	}
+	if opts.BlockRange == 0 {
+		opts.BlockRange = DefaultEventsBlockRange
+	}
+	proofs := make(map[[32]byte][]batcher.AlignedVerificationData)
+	for _, proof := range opts.Proofs {
+		proofs[proof.BatchMerkleRoot] = append(proofs[proof.BatchMerkleRoot], proof)
+	}
+	return &eventsReader{
+		source:             source,
+		filterer:           filterer,
+		serviceManagerAddr: serviceManagerAddr,
+		paymentServiceAddr: paymentServiceAddr,
+		topics:             []ethcommon.Hash{eventId(serviceManagerAbi, "NewBatchV3"), eventId(serviceManagerAbi, "BatchVerified")},
+		opts:               opts,
+		proofs:             proofs,
+	}, nil
+}
+
+func eventId(contractAbi *abi.ABI, name string) ethcommon.Hash {
+	return contractAbi.Events[name].ID
+}
+
+func (r *eventsReader) run(ctx context.Context, subscription *Subscription) {
+	defer close(subscription.events)
+
+	next := r.opts.FromBlock
+	for {
+		var err error
+		next, err = r.readUntilLatest(ctx, next, subscription.events)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case subscription.errs <- err:
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.opts.PollInterval):
+		}
+	}
+}
+
+// readUntilLatest emits the events from the block to the latest one, returning the next block to read
+func (r *eventsReader) readUntilLatest(ctx context.Context, from uint64, events chan<- Event) (uint64, error) {
+	latest, err := r.source.BlockNumber(ctx)
+	if err != nil {
+		return from, fmt.Errorf("could not get block number: %w", err)
+	}
+	for from <= latest {
+		to := min(from+r.opts.BlockRange-1, latest)
+		logs, err := r.filterLogs(ctx, from, to)
+		if err != nil {
+			return from, err
+		}
+		for _, log := range logs {
+			for _, event := range r.parse(log) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return from, ctx.Err()
+				}
+			}
+		}
+		from = to + 1
+	}
+	return from, nil
+}
+
+// filterLogs returns the logs of the batch events of the blocks
+func (r *eventsReader) filterLogs(ctx context.Context, from uint64, to uint64) ([]types.Log, error) {
+	logs, err := r.source.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []ethcommon.Address{r.serviceManagerAddr},
+		Topics:    [][]ethcommon.Hash{r.topics},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get logs of blocks %d to %d: %w", from, to, err)
+	}
+	return logs, nil
+}
+
+// parse returns the events of the log that match the options
+func (r *eventsReader) parse(log types.Log) []Event {
+	if log.Removed || len(log.Topics) == 0 {
+		return nil
+	}
+	var event Event
+	switch log.Topics[0] {
+	case r.topics[0]:
+		newBatch, err := r.filterer.ParseNewBatchV3(log)
+		if err != nil {
+			return nil
+		}
+		event = Event{
+			Type:                  EventBatchCreated,
+			BatchMerkleRoot:       newBatch.BatchMerkleRoot,
+			SenderAddress:         newBatch.SenderAddress,
+			BatchDataPointer:      newBatch.BatchDataPointer,
+			RespondToTaskFeeLimit: newBatch.RespondToTaskFeeLimit,
+		}
+	case r.topics[1]:
+		batchVerified, err := r.filterer.ParseBatchVerified(log)
+		if err != nil {
+			return nil
+		}
+		event = Event{
+			Type:            EventBatchVerified,
+			BatchMerkleRoot: batchVerified.BatchMerkleRoot,
+			SenderAddress:   batchVerified.SenderAddress,
+		}
+	default:
+		return nil
+	}
+	event.BlockNumber = log.BlockNumber
+	event.TxHash = log.TxHash
+
+	var events []Event
+	if r.opts.SenderAddress == nil || *r.opts.SenderAddress == event.SenderAddress {
+		events = append(events, event)
+	}
+	if event.Type == EventBatchVerified && event.SenderAddress == r.paymentServiceAddr {
+		for i := range r.proofs[event.BatchMerkleRoot] {
+			proofVerified := event
+			proofVerified.Type = EventProofVerified
+			proofVerified.Proof = &r.proofs[event.BatchMerkleRoot][i]
+			events = append(events, proofVerified)
+		}
+		delete(r.proofs, event.BatchMerkleRoot)
+	}
+	return events
+}
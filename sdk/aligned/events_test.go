@@ -0,0 +1,126 @@
+package aligned
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	csservicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// fakeLogSource serves the logs added to it, failing the queries while failing is set
+type fakeLogSource struct {
+	mu      sync.Mutex
+	block   uint64
+	logs    []types.Log
+	failing bool
+}
+
+func (s *fakeLogSource) BlockNumber(ctx context.Context) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.block, nil
+}
+
+func (s *fakeLogSource) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failing {
+		return nil, errors.New("connection refused")
+	}
+	var logs []types.Log
+	for _, log := range s.logs {
+		if log.BlockNumber >= query.FromBlock.Uint64() && log.BlockNumber <= query.ToBlock.Uint64() {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (s *fakeLogSource) add(t *testing.T, block uint64, name string, root [32]byte, args ...interface{}) {
+	serviceManagerAbi, err := csservicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("could not get abi: %v", err)
+	}
+	event := serviceManagerAbi.Events[name]
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		t.Fatalf("could not pack %s: %v", name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs = append(s.logs, types.Log{Topics: []ethcommon.Hash{event.ID, root}, Data: data, BlockNumber: block})
+	s.block = block
+}
+
+func (s *fakeLogSource) setFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = failing
+}
+
+func nextEvent(t *testing.T, subscription *Subscription) Event {
+	select {
+	case event := <-subscription.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no event received")
+		return Event{}
+	}
+}
+
+func TestSubscribeEventsBackfillsAndRetries(t *testing.T) {
+	paymentServiceAddr, _ := Devnet.BatcherPaymentServiceAddress()
+	otherSender := ethcommon.HexToAddress("0x1")
+	proof := batcher.AlignedVerificationData{BatchMerkleRoot: [32]byte{1}}
+
+	source := &fakeLogSource{}
+	source.add(t, 10, "NewBatchV3", [32]byte{1}, paymentServiceAddr, uint32(10), "http://batch/1", big.NewInt(100))
+	source.add(t, 11, "NewBatchV3", [32]byte{2}, otherSender, uint32(11), "http://batch/2", big.NewInt(100))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	subscription, err := SubscribeEvents(ctx, source, Devnet, SubscribeOpts{
+		FromBlock:     1,
+		SenderAddress: &paymentServiceAddr,
+		Proofs:        []batcher.AlignedVerificationData{proof},
+		PollInterval:  10 * time.Millisecond,
+		BlockRange:    3,
+	})
+	if err != nil {
+		t.Fatalf("could not subscribe: %v", err)
+	}
+
+	// the past batch of the sender is backfilled, the one of the other sender filtered out
+	if event := nextEvent(t, subscription); event.Type != EventBatchCreated || event.BatchDataPointer != "http://batch/1" || event.BlockNumber != 10 {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	source.setFailing(true)
+	source.add(t, 20, "BatchVerified", [32]byte{1}, paymentServiceAddr)
+	select {
+	case <-subscription.Errors():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no error reported")
+	}
+	source.setFailing(false)
+
+	if event := nextEvent(t, subscription); event.Type != EventBatchVerified || event.BatchMerkleRoot != proof.BatchMerkleRoot {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if event := nextEvent(t, subscription); event.Type != EventProofVerified || event.Proof == nil || event.Proof.BatchMerkleRoot != proof.BatchMerkleRoot {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	cancel()
+	for range subscription.Events() {
+		t.Errorf("unexpected event after cancelling")
+	}
+}
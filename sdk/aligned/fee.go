@@ -0,0 +1,118 @@
+package aligned
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// The gas costs the fees are estimated from, as in the Rust SDK
+const (
+	DefaultAggregatorGasCost             = 330_000
+	BatcherSubmissionBaseGasCost         = 125_000
+	AdditionalSubmissionGasCostPerProof  = 2_000
+	DefaultAggregatorFeePercentageFactor = 125
+	// DefaultConstantGasCost is the gas of creating and responding to the task of a batch
+	DefaultConstantGasCost = DefaultAggregatorGasCost*DefaultAggregatorFeePercentageFactor/100 + BatcherSubmissionBaseGasCost
+
+	// MaxFeeBatchProofNumber is the number of proofs of the batch the fees are estimated for
+	MaxFeeBatchProofNumber = 32
+	// MaxFeeDefaultProofNumber is the number of proofs of the batch paid by the default estimate
+	MaxFeeDefaultProofNumber = 10
+)
+
+var ErrFeeAboveCap = errors.New("estimated fee above the max fee cap")
+
+// PriceEstimate is how much of a batch of MaxFeeBatchProofNumber proofs a proof pays for, which
+// decides how soon the batcher includes it
+type PriceEstimate string
+
+const (
+	// PriceEstimateMin pays for one proof of the batch, the lowest priority
+	PriceEstimateMin PriceEstimate = "min"
+	// PriceEstimateDefault pays for MaxFeeDefaultProofNumber proofs of the batch
+	PriceEstimateDefault PriceEstimate = "default"
+	// PriceEstimateInstant pays for the whole batch, so the proof is sent without waiting for others
+	PriceEstimateInstant PriceEstimate = "instant"
+)
+
+// GasPriceSource returns the gas price of the chain, as ethclient.Client does
+type GasPriceSource interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// FeePerProof returns the fee of a proof in a batch of numProofsPerBatch proofs at the current gas price
+func FeePerProof(ctx context.Context, source GasPriceSource, numProofsPerBatch int) (*big.Int, error) {
+	if numProofsPerBatch <= 0 {
+		return nil, fmt.Errorf("invalid number of proofs per batch %d", numProofsPerBatch)
+	}
+	gasPrice, err := source.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get gas price: %w", err)
+	}
+	gasPerProof := (DefaultConstantGasCost + AdditionalSubmissionGasCostPerProof*uint64(numProofsPerBatch)) / uint64(numProofsPerBatch)
+	return new(big.Int).Mul(new(big.Int).SetUint64(gasPerProof), gasPrice), nil
+}
+
+// EstimateFee returns the max fee of a proof for the estimate at the current gas price
+func EstimateFee(ctx context.Context, source GasPriceSource, estimate PriceEstimate) (*big.Int, error) {
+	var proofsPaid int64
+	switch estimate {
+	case PriceEstimateMin:
+		proofsPaid = 1
+	case PriceEstimateDefault:
+		proofsPaid = MaxFeeDefaultProofNumber
+	case PriceEstimateInstant:
+		proofsPaid = MaxFeeBatchProofNumber
+	default:
+		return nil, fmt.Errorf("unknown price estimate %q", estimate)
+	}
+	feePerProof, err := FeePerProof(ctx, source, MaxFeeBatchProofNumber)
+	if err != nil {
+		return nil, err
+	}
+	return feePerProof.Mul(feePerProof, big.NewInt(proofsPaid)), nil
+}
+
+// EstimateSubmissionCost returns the most that sending the number of proofs with the estimate costs
+func EstimateSubmissionCost(ctx context.Context, source GasPriceSource, estimate PriceEstimate, numProofs int) (*big.Int, error) {
+	maxFee, err := EstimateFee(ctx, source, estimate)
+	if err != nil {
+		return nil, err
+	}
+	return maxFee.Mul(maxFee, big.NewInt(int64(numProofs))), nil
+}
+
+// CappedMaxFee returns the max fee of a proof for the estimate, failing with ErrFeeAboveCap if it's
+// over the cap, so a proof is never paid more than the cap
+func CappedMaxFee(ctx context.Context, source GasPriceSource, estimate PriceEstimate, maxFeeCap *big.Int) (*big.Int, error) {
+	maxFee, err := EstimateFee(ctx, source, estimate)
+	if err != nil {
+		return nil, err
+	}
+	if maxFee.Cmp(maxFeeCap) > 0 {
+		return nil, fmt.Errorf("%w: fee %s, cap %s", ErrFeeAboveCap, maxFee, maxFeeCap)
+	}
+	return maxFee, nil
+}
+
+// SubmitMultipleWithFeeCapAndWaitForVerification estimates the max fee of the proofs at the current
+// gas price, and sends them as SubmitMultipleAndWaitForVerification unless it's over the cap, in
+// which case nothing is sent and ErrFeeAboveCap is returned
+func SubmitMultipleWithFeeCapAndWaitForVerification(ctx context.Context, batcherUrl string, ethRpcUrl string, network Network, verificationData []batcher.VerificationData, estimate PriceEstimate, maxFeeCap *big.Int, privateKey *ecdsa.PrivateKey, nonce *big.Int) ([]batcher.AlignedVerificationData, error) {
+	ethClient, err := ethclient.DialContext(ctx, ethRpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to eth rpc: %w", err)
+	}
+	maxFee, err := CappedMaxFee(ctx, ethClient, estimate, maxFeeCap)
+	ethClient.Close()
+	if err != nil {
+		return nil, err
+	}
+	return SubmitMultipleAndWaitForVerification(ctx, batcherUrl, ethRpcUrl, network, verificationData, maxFee, privateKey, nonce)
+}
@@ -0,0 +1,46 @@
+package aligned
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+type fakeGasPriceSource struct {
+	gasPrice *big.Int
+}
+
+func (s fakeGasPriceSource) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(s.gasPrice), nil
+}
+
+func TestEstimateFee(t *testing.T) {
+	source := fakeGasPriceSource{gasPrice: big.NewInt(1_000_000_000)}
+	// (330000 * 125% + 125000 + 2000 * 32) / 32 gas per proof, as the Rust SDK
+	feePerProof := big.NewInt(18_796 * 1_000_000_000)
+
+	cases := map[PriceEstimate]int64{PriceEstimateMin: 1, PriceEstimateDefault: 10, PriceEstimateInstant: 32}
+	for estimate, proofsPaid := range cases {
+		fee, err := EstimateFee(context.Background(), source, estimate)
+		if err != nil {
+			t.Fatalf("could not estimate fee: %v", err)
+		}
+		if expected := new(big.Int).Mul(feePerProof, big.NewInt(proofsPaid)); fee.Cmp(expected) != 0 {
+			t.Errorf("expected %s fee to be %s, got %s", estimate, expected, fee)
+		}
+	}
+}
+
+func TestCappedMaxFee(t *testing.T) {
+	source := fakeGasPriceSource{gasPrice: big.NewInt(1_000_000_000)}
+	feePerProof := big.NewInt(18_796 * 1_000_000_000)
+
+	fee, err := CappedMaxFee(context.Background(), source, PriceEstimateMin, feePerProof)
+	if err != nil || fee.Cmp(feePerProof) != 0 {
+		t.Errorf("expected fee %s under the cap, got %s, %v", feePerProof, fee, err)
+	}
+	if _, err := CappedMaxFee(context.Background(), source, PriceEstimateDefault, feePerProof); !errors.Is(err, ErrFeeAboveCap) {
+		t.Errorf("expected fee above cap, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package aligned
+
+import (
+	"errors"
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+var (
+	ErrCommitmentMismatch = errors.New("verification data does not match the commitment of the proof")
+	ErrInvalidMerklePath  = errors.New("merkle path does not lead to the batch merkle root")
+	ErrBatchNotCreated    = errors.New("batch task was not created by the batcher")
+	ErrBatchNotResponded  = errors.New("batch was not verified yet")
+)
+
+// BatchStateReader reads the state of the batches in the service manager, as chainio.AvsReader does
+type BatchStateReader interface {
+	GetBatchState(batchMerkleRoot [32]byte, senderAddress ethcommon.Address) (chainio.BatchState, error)
+}
+
+// VerifyProofInclusion checks the proof is in the batch of the aligned verification data, by
+// recomputing its commitment and the merkle path from it to the batch merkle root. It doesn't
+// need a connection, so it can be checked before trusting the data returned by a batcher.
+func VerifyProofInclusion(verificationData batcher.VerificationData, alignedVerificationData batcher.AlignedVerificationData) error {
+	if verificationData.Commitment() != alignedVerificationData.VerificationDataCommitment {
+		return ErrCommitmentMismatch
+	}
+	if !alignedVerificationData.VerifyInclusion() {
+		return ErrInvalidMerklePath
+	}
+	return nil
+}
+
+// CheckBatchOnChain checks the task of the batch was created by the batcher of the network and
+// was responded, so its proofs were verified by the operators
+func CheckBatchOnChain(reader BatchStateReader, network Network, batchMerkleRoot [32]byte) error {
+	paymentServiceAddr, err := network.BatcherPaymentServiceAddress()
+	if err != nil {
+		return err
+	}
+	state, err := reader.GetBatchState(batchMerkleRoot, paymentServiceAddr)
+	if err != nil {
+		return err
+	}
+	if state.TaskCreatedBlock == 0 {
+		return ErrBatchNotCreated
+	}
+	if !state.Responded {
+		return fmt.Errorf("%w: task created in block %d", ErrBatchNotResponded, state.TaskCreatedBlock)
+	}
+	return nil
+}
+
+// ConfirmProofInclusion independently confirms the proof was verified by Aligned: it is in the
+// batch, and the batch was verified on chain
+func ConfirmProofInclusion(reader BatchStateReader, network Network, verificationData batcher.VerificationData, alignedVerificationData batcher.AlignedVerificationData) error {
+	if err := VerifyProofInclusion(verificationData, alignedVerificationData); err != nil {
+		return err
+	}
+	return CheckBatchOnChain(reader, network, alignedVerificationData.BatchMerkleRoot)
+}
@@ -0,0 +1,81 @@
+package aligned
+
+import (
+	"errors"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// fakeBatchStateReader returns the state of the batches created by sender
+type fakeBatchStateReader struct {
+	sender ethcommon.Address
+	states map[[32]byte]chainio.BatchState
+}
+
+func (r *fakeBatchStateReader) GetBatchState(batchMerkleRoot [32]byte, senderAddress ethcommon.Address) (chainio.BatchState, error) {
+	if senderAddress != r.sender {
+		return chainio.BatchState{}, nil
+	}
+	return r.states[batchMerkleRoot], nil
+}
+
+// testBatch returns two proofs and the aligned verification data of the second one in a batch of both
+func testBatch() ([]batcher.VerificationData, batcher.AlignedVerificationData) {
+	proofs := []batcher.VerificationData{
+		{ProvingSystem: common.Groth16Bn254, Proof: []byte{1}, PubInput: []byte{2}, VerificationKey: []byte{3}},
+		{ProvingSystem: common.SP1, Proof: []byte{4}, VmProgramCode: []byte{5}},
+	}
+	leaves := [][32]byte{}
+	for _, proof := range proofs {
+		commitment := proof.Commitment()
+		leaves = append(leaves, commitment.Hash())
+	}
+	root := crypto.Keccak256Hash(leaves[0][:], leaves[1][:])
+	return proofs, batcher.AlignedVerificationData{
+		VerificationDataCommitment: proofs[1].Commitment(),
+		BatchMerkleRoot:            root,
+		BatchInclusionProof:        [][32]byte{leaves[0]},
+		IndexInBatch:               1,
+	}
+}
+
+func TestVerifyProofInclusion(t *testing.T) {
+	proofs, alignedVerificationData := testBatch()
+	if err := VerifyProofInclusion(proofs[1], alignedVerificationData); err != nil {
+		t.Errorf("expected proof to be included, got %v", err)
+	}
+	if err := VerifyProofInclusion(proofs[0], alignedVerificationData); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Errorf("expected commitment mismatch, got %v", err)
+	}
+	alignedVerificationData.IndexInBatch = 0
+	if err := VerifyProofInclusion(proofs[1], alignedVerificationData); !errors.Is(err, ErrInvalidMerklePath) {
+		t.Errorf("expected invalid merkle path, got %v", err)
+	}
+}
+
+func TestConfirmProofInclusion(t *testing.T) {
+	proofs, alignedVerificationData := testBatch()
+	paymentServiceAddr, _ := Devnet.BatcherPaymentServiceAddress()
+	reader := &fakeBatchStateReader{sender: paymentServiceAddr, states: map[[32]byte]chainio.BatchState{}}
+
+	if err := ConfirmProofInclusion(reader, Devnet, proofs[1], alignedVerificationData); !errors.Is(err, ErrBatchNotCreated) {
+		t.Errorf("expected batch not created, got %v", err)
+	}
+	reader.states[alignedVerificationData.BatchMerkleRoot] = chainio.BatchState{TaskCreatedBlock: 10}
+	if err := ConfirmProofInclusion(reader, Devnet, proofs[1], alignedVerificationData); !errors.Is(err, ErrBatchNotResponded) {
+		t.Errorf("expected batch not responded, got %v", err)
+	}
+	reader.states[alignedVerificationData.BatchMerkleRoot] = chainio.BatchState{TaskCreatedBlock: 10, Responded: true}
+	if err := ConfirmProofInclusion(reader, Devnet, proofs[1], alignedVerificationData); err != nil {
+		t.Errorf("expected proof to be confirmed, got %v", err)
+	}
+	// the batch of another network's batcher isn't the one the proof was sent in
+	if err := ConfirmProofInclusion(reader, Mainnet, proofs[1], alignedVerificationData); !errors.Is(err, ErrBatchNotCreated) {
+		t.Errorf("expected batch not created on mainnet, got %v", err)
+	}
+}
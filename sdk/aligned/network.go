@@ -0,0 +1,195 @@
+package aligned
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// Network is a deployment of Aligned, named as in the Rust SDK. Custom deployments are added with
+// RegisterDeployment.
+type Network string
+
+const (
+	Devnet       Network = "devnet"
+	Holesky      Network = "holesky"
+	HoleskyStage Network = "holesky-stage"
+	Mainnet      Network = "mainnet"
+)
+
+// Deployment holds the contracts of a network and the endpoints of its services
+type Deployment struct {
+	ChainId uint64
+	// DeploymentBlock is the block the contracts were deployed in, from which their events can be read
+	DeploymentBlock uint64
+
+	AlignedServiceManager  ethcommon.Address
+	BatcherPaymentService  ethcommon.Address
+	RegistryCoordinator    ethcommon.Address
+	BlsApkRegistry         ethcommon.Address
+	StakeRegistry          ethcommon.Address
+	IndexRegistry          ethcommon.Address
+	OperatorStateRetriever ethcommon.Address
+
+	BatcherUrl string
+	// EthRpcUrl is a public rpc of the chain, empty if there's none
+	EthRpcUrl   string
+	ExplorerUrl string
+}
+
+var (
+	deploymentsMutex sync.RWMutex
+	deployments      = map[Network]Deployment{
+		Devnet: {
+			ChainId:                31337,
+			AlignedServiceManager:  ethcommon.HexToAddress("0x851356ae760d987E095750cCeb3bC6014560891C"),
+			BatcherPaymentService:  ethcommon.HexToAddress("0x7bc06c482DEAd17c0e297aFbC32f6e63d3846650"),
+			RegistryCoordinator:    ethcommon.HexToAddress("0xf5059a5D33d5853360D16C683c16e67980206f36"),
+			BlsApkRegistry:         ethcommon.HexToAddress("0x70e0bA845a1A0F2DA3359C97E0285013525FFC49"),
+			StakeRegistry:          ethcommon.HexToAddress("0x998abeb3E57409262aE5b751f60747921B33613E"),
+			IndexRegistry:          ethcommon.HexToAddress("0x95401dc811bb5740090279Ba06cfA8fcF6113778"),
+			OperatorStateRetriever: ethcommon.HexToAddress("0xCD8a1C3ba11CF5ECfa6267617243239504a98d90"),
+			BatcherUrl:             "ws://localhost:8080",
+			EthRpcUrl:              "http://localhost:8545",
+		},
+		Holesky: {
+			ChainId:                17000,
+			DeploymentBlock:        1628199,
+			AlignedServiceManager:  ethcommon.HexToAddress("0x58F280BeBE9B34c9939C3C39e0890C81f163B623"),
+			BatcherPaymentService:  ethcommon.HexToAddress("0x815aeCA64a974297942D2Bbf034ABEe22a38A003"),
+			RegistryCoordinator:    ethcommon.HexToAddress("0x3aD77134c986193c9ef98e55e800B71e72835b62"),
+			BlsApkRegistry:         ethcommon.HexToAddress("0xD0A725d82649f9e4155D7A60B638Fe33b3F25e3b"),
+			StakeRegistry:          ethcommon.HexToAddress("0x51462D5511563A0F97Bb3Ce5475E1c3905b83F4b"),
+			IndexRegistry:          ethcommon.HexToAddress("0x4A7DE0a9fBBAa4fF0270d31852B363592F68B81F"),
+			OperatorStateRetriever: ethcommon.HexToAddress("0x59755AF41dB1680dC6F47CaFc09e40C0e757C5E9"),
+			BatcherUrl:             "wss://batcher.alignedlayer.com",
+			EthRpcUrl:              "https://ethereum-holesky-rpc.publicnode.com",
+			ExplorerUrl:            "https://holesky.explorer.alignedlayer.com",
+		},
+		HoleskyStage: {
+			ChainId:                17000,
+			DeploymentBlock:        1754855,
+			AlignedServiceManager:  ethcommon.HexToAddress("0x9C5231FC88059C086Ea95712d105A2026048c39B"),
+			BatcherPaymentService:  ethcommon.HexToAddress("0x7577Ec4ccC1E6C529162ec8019A49C13F6DAd98b"),
+			RegistryCoordinator:    ethcommon.HexToAddress("0x945821C32397A847F13C41a2C22cbE771CE2Ce2f"),
+			BlsApkRegistry:         ethcommon.HexToAddress("0xEe1b6Dc663F17eC69987b6D56255a7282b358a09"),
+			StakeRegistry:          ethcommon.HexToAddress("0x1b0C9b87b094d821911500F91914B1A1D2856F14"),
+			IndexRegistry:          ethcommon.HexToAddress("0xF12b82A933381391fE0e9a0270111f90FB10a810"),
+			OperatorStateRetriever: ethcommon.HexToAddress("0x3aBe0288b1088807f58146a71bC41FCDF247d30B"),
+			BatcherUrl:             "wss://stage.batcher.alignedlayer.com",
+			EthRpcUrl:              "https://ethereum-holesky-rpc.publicnode.com",
+			ExplorerUrl:            "https://stage.explorer.alignedlayer.com",
+		},
+		Mainnet: {
+			ChainId:                1,
+			DeploymentBlock:        21289146,
+			AlignedServiceManager:  ethcommon.HexToAddress("0xeF2A435e5EE44B2041100EF8cbC8ae035166606c"),
+			BatcherPaymentService:  ethcommon.HexToAddress("0xb0567184A52cB40956df6333510d6eF35B89C8de"),
+			RegistryCoordinator:    ethcommon.HexToAddress("0xA8CC0749b4409c3c47012323E625aEcBA92f64b9"),
+			BlsApkRegistry:         ethcommon.HexToAddress("0x3CcfB7e6e8fe2A8d941a8Ce4C69A944a770E8228"),
+			StakeRegistry:          ethcommon.HexToAddress("0x45F5290a3630Cd6dc277B6f92227526121ca7c22"),
+			IndexRegistry:          ethcommon.HexToAddress("0x9Bf1275e18eC8FA3cA7f9bffF1b0DF3e14C6E134"),
+			OperatorStateRetriever: ethcommon.HexToAddress("0x6e0046205cAfA503F6b7465195A6C63C47d214f1"),
+			BatcherUrl:             "wss://mainnet.batcher.alignedlayer.com",
+			EthRpcUrl:              "https://ethereum-rpc.publicnode.com",
+			ExplorerUrl:            "https://explorer.alignedlayer.com",
+		},
+	}
+)
+
+// RegisterDeployment adds a custom deployment under the name, replacing the known one if it has
+// the name of a known network, so the SDK calls can be used with it
+func RegisterDeployment(network Network, deployment Deployment) {
+	deploymentsMutex.Lock()
+	defer deploymentsMutex.Unlock()
+	deployments[network] = deployment
+}
+
+// Networks returns the names of the networks with a deployment, sorted
+func Networks() []Network {
+	deploymentsMutex.RLock()
+	defer deploymentsMutex.RUnlock()
+	networks := make([]Network, 0, len(deployments))
+	for network := range deployments {
+		networks = append(networks, network)
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i] < networks[j] })
+	return networks
+}
+
+// ParseNetwork returns the network with the name, failing if it has no deployment
+func ParseNetwork(name string) (Network, error) {
+	network := Network(name)
+	if _, err := network.Deployment(); err != nil {
+		return "", err
+	}
+	return network, nil
+}
+
+// Deployment returns the deployment of the network
+func (n Network) Deployment() (Deployment, error) {
+	deploymentsMutex.RLock()
+	defer deploymentsMutex.RUnlock()
+	deployment, ok := deployments[n]
+	if !ok {
+		return Deployment{}, fmt.Errorf("unknown network %q", n)
+	}
+	return deployment, nil
+}
+
+// AlignedServiceManagerAddress returns the address of the AlignedLayerServiceManager contract of the network
+func (n Network) AlignedServiceManagerAddress() (ethcommon.Address, error) {
+	deployment, err := n.Deployment()
+	return deployment.AlignedServiceManager, err
+}
+
+// BatcherPaymentServiceAddress returns the address of the BatcherPaymentService contract of the network
+func (n Network) BatcherPaymentServiceAddress() (ethcommon.Address, error) {
+	deployment, err := n.Deployment()
+	return deployment.BatcherPaymentService, err
+}
+
+// deploymentOutput is the deployment output the contract scripts write, in contracts/script/output
+type deploymentOutput struct {
+	Addresses struct {
+		AlignedLayerServiceManager ethcommon.Address `json:"alignedLayerServiceManager"`
+		BatcherPaymentService      ethcommon.Address `json:"batcherPaymentService"`
+		RegistryCoordinator        ethcommon.Address `json:"registryCoordinator"`
+		BlsApkRegistry             ethcommon.Address `json:"blsApkRegistry"`
+		StakeRegistry              ethcommon.Address `json:"stakeRegistry"`
+		IndexRegistry              ethcommon.Address `json:"indexRegistry"`
+		OperatorStateRetriever     ethcommon.Address `json:"operatorStateRetriever"`
+	} `json:"addresses"`
+	ChainInfo struct {
+		ChainId         uint64 `json:"chainId"`
+		DeploymentBlock uint64 `json:"deploymentBlock"`
+	} `json:"chainInfo"`
+}
+
+// LoadDeployment reads the contracts of a deployment from the output of the deployment scripts,
+// alignedlayer_deployment_output.json. Its endpoints are left to be set before registering it.
+func LoadDeployment(path string) (Deployment, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Deployment{}, fmt.Errorf("could not read deployment output: %w", err)
+	}
+	var output deploymentOutput
+	if err := json.Unmarshal(content, &output); err != nil {
+		return Deployment{}, fmt.Errorf("could not parse deployment output: %w", err)
+	}
+	return Deployment{
+		ChainId:                output.ChainInfo.ChainId,
+		DeploymentBlock:        output.ChainInfo.DeploymentBlock,
+		AlignedServiceManager:  output.Addresses.AlignedLayerServiceManager,
+		BatcherPaymentService:  output.Addresses.BatcherPaymentService,
+		RegistryCoordinator:    output.Addresses.RegistryCoordinator,
+		BlsApkRegistry:         output.Addresses.BlsApkRegistry,
+		StakeRegistry:          output.Addresses.StakeRegistry,
+		IndexRegistry:          output.Addresses.IndexRegistry,
+		OperatorStateRetriever: output.Addresses.OperatorStateRetriever,
+	}, nil
+}
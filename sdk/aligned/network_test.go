@@ -0,0 +1,52 @@
+package aligned
+
+import (
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+func TestDeploymentsMatchDeploymentOutputs(t *testing.T) {
+	outputs := map[Network]string{
+		Devnet:       "../../contracts/script/output/devnet/alignedlayer_deployment_output.json",
+		Holesky:      "../../contracts/script/output/holesky/alignedlayer_deployment_output.json",
+		HoleskyStage: "../../contracts/script/output/holesky/alignedlayer_deployment_output.stage.json",
+		Mainnet:      "../../contracts/script/output/mainnet/alignedlayer_deployment_output.json",
+	}
+	for network, path := range outputs {
+		loaded, err := LoadDeployment(path)
+		if err != nil {
+			t.Fatalf("could not load deployment of %s: %v", network, err)
+		}
+		deployment, err := network.Deployment()
+		if err != nil {
+			t.Fatalf("could not get deployment of %s: %v", network, err)
+		}
+		// the endpoints aren't in the deployment output
+		loaded.BatcherUrl, loaded.EthRpcUrl, loaded.ExplorerUrl = deployment.BatcherUrl, deployment.EthRpcUrl, deployment.ExplorerUrl
+		if loaded != deployment {
+			t.Errorf("deployment of %s doesn't match its output:\n%+v\n%+v", network, deployment, loaded)
+		}
+	}
+}
+
+func TestRegisterDeployment(t *testing.T) {
+	network := Network("custom")
+	if _, err := ParseNetwork("custom"); err == nil {
+		t.Fatalf("expected unknown network error")
+	}
+
+	deployment := Deployment{
+		ChainId:               1337,
+		AlignedServiceManager: ethcommon.HexToAddress("0x1"),
+		BatcherPaymentService: ethcommon.HexToAddress("0x2"),
+		BatcherUrl:            "ws://batcher:8080",
+	}
+	RegisterDeployment(network, deployment)
+	if parsed, err := ParseNetwork("custom"); err != nil || parsed != network {
+		t.Fatalf("expected custom network, got %q, %v", parsed, err)
+	}
+	if address, err := network.BatcherPaymentServiceAddress(); err != nil || address != deployment.BatcherPaymentService {
+		t.Errorf("unexpected payment service address %s, %v", address, err)
+	}
+}
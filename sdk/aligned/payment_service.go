@@ -0,0 +1,161 @@
+package aligned
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// The BatcherPaymentService functions used by the proof senders
+const batcherPaymentServiceAbi = `[
+	{"type":"receive","stateMutability":"payable"},
+	{"type":"function","name":"unlock","stateMutability":"nonpayable","inputs":[],"outputs":[]},
+	{"type":"function","name":"lock","stateMutability":"nonpayable","inputs":[],"outputs":[]},
+	{"type":"function","name":"withdraw","stateMutability":"nonpayable","inputs":[{"name":"amount","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"user_balances","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"user_nonces","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"user_unlock_block","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+var ErrTransactionReverted = errors.New("transaction reverted")
+
+// ContractBackend sends transactions and waits for them to be mined, as ethclient.Client does
+type ContractBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// PaymentService is the BatcherPaymentService contract of a network, which holds the balance the
+// proofs sent to the batcher are paid from. A balance can only be withdrawn once unlocked, after
+// the unlock period, so the batcher can rely on it while the proofs are queued.
+type PaymentService struct {
+	contract *bind.BoundContract
+	backend  ContractBackend
+	address  ethcommon.Address
+	chainId  *big.Int
+}
+
+// NewPaymentService returns the BatcherPaymentService contract of the network
+func NewPaymentService(ctx context.Context, backend ContractBackend, network Network) (*PaymentService, error) {
+	address, err := network.BatcherPaymentServiceAddress()
+	if err != nil {
+		return nil, err
+	}
+	return NewPaymentServiceAt(ctx, backend, address)
+}
+
+// NewPaymentServiceAt returns the BatcherPaymentService contract at the address, for custom deployments
+func NewPaymentServiceAt(ctx context.Context, backend ContractBackend, address ethcommon.Address) (*PaymentService, error) {
+	parsed, err := abi.JSON(strings.NewReader(batcherPaymentServiceAbi))
+	if err != nil {
+		return nil, err
+	}
+	chainId, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get chain id: %w", err)
+	}
+	return &PaymentService{
+		contract: bind.NewBoundContract(address, parsed, backend, backend, backend),
+		backend:  backend,
+		address:  address,
+		chainId:  chainId,
+	}, nil
+}
+
+func (p *PaymentService) Address() ethcommon.Address {
+	return p.address
+}
+
+// Signer returns the signer of the messages sent to the batcher, which pays for them from the
+// balance of the key in this contract
+func (p *PaymentService) Signer(privateKey *ecdsa.PrivateKey) *batcher.Signer {
+	return batcher.NewSigner(privateKey, p.chainId, p.address)
+}
+
+func (p *PaymentService) call(ctx context.Context, method string, account ethcommon.Address) (*big.Int, error) {
+	var out []interface{}
+	if err := p.contract.Call(&bind.CallOpts{Context: ctx}, &out, method, account); err != nil {
+		return nil, fmt.Errorf("could not call %s: %w", method, err)
+	}
+	return abi.ConvertType(out[0], new(big.Int)).(*big.Int), nil
+}
+
+// Balance returns the balance of the account, which pays for its proofs
+func (p *PaymentService) Balance(ctx context.Context, account ethcommon.Address) (*big.Int, error) {
+	return p.call(ctx, "user_balances", account)
+}
+
+// Nonce returns the number of proofs of the account paid in batches. The batcher also counts
+// the proofs in its queue, see batcher.Client.GetNonce.
+func (p *PaymentService) Nonce(ctx context.Context, account ethcommon.Address) (*big.Int, error) {
+	return p.call(ctx, "user_nonces", account)
+}
+
+// UnlockTime returns when the balance of the account can be withdrawn, or the zero time if it's locked
+func (p *PaymentService) UnlockTime(ctx context.Context, account ethcommon.Address) (time.Time, error) {
+	unlockTime, err := p.call(ctx, "user_unlock_block", account)
+	if err != nil || unlockTime.Sign() == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(unlockTime.Int64(), 0), nil
+}
+
+// transact sends the transaction from the key and waits for it to be mined. An empty method
+// sends the value to the contract.
+func (p *PaymentService) transact(ctx context.Context, privateKey *ecdsa.PrivateKey, value *big.Int, method string, params ...interface{}) (*types.Receipt, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(privateKey, p.chainId)
+	if err != nil {
+		return nil, err
+	}
+	opts.Context = ctx
+	opts.Value = value
+
+	var tx *types.Transaction
+	if method == "" {
+		tx, err = p.contract.Transfer(opts)
+	} else {
+		tx, err = p.contract.Transact(opts, method, params...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not send transaction: %w", err)
+	}
+	receipt, err := bind.WaitMined(ctx, p.backend, tx)
+	if err != nil {
+		return nil, fmt.Errorf("could not wait for transaction %s: %w", tx.Hash().Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("%w: %s", ErrTransactionReverted, tx.Hash().Hex())
+	}
+	return receipt, nil
+}
+
+// Deposit adds the amount to the balance of the key, locking it
+func (p *PaymentService) Deposit(ctx context.Context, privateKey *ecdsa.PrivateKey, amount *big.Int) (*types.Receipt, error) {
+	return p.transact(ctx, privateKey, amount, "")
+}
+
+// Unlock starts the unlock period of the balance of the key, after which it can be withdrawn
+func (p *PaymentService) Unlock(ctx context.Context, privateKey *ecdsa.PrivateKey) (*types.Receipt, error) {
+	return p.transact(ctx, privateKey, nil, "unlock")
+}
+
+// Lock cancels the unlock of the balance of the key
+func (p *PaymentService) Lock(ctx context.Context, privateKey *ecdsa.PrivateKey) (*types.Receipt, error) {
+	return p.transact(ctx, privateKey, nil, "lock")
+}
+
+// Withdraw sends the amount of the unlocked balance of the key back to it, locking the rest
+func (p *PaymentService) Withdraw(ctx context.Context, privateKey *ecdsa.PrivateKey, amount *big.Int) (*types.Receipt, error) {
+	return p.transact(ctx, privateKey, nil, "withdraw", amount)
+}
@@ -0,0 +1,38 @@
+package aligned
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// The functions of the inline abi must match the ones of the deployed contract, whose abi the Rust SDK ships
+func TestPaymentServiceAbiMatchesContract(t *testing.T) {
+	contractAbiJson, err := os.ReadFile("../../batcher/aligned-sdk/abi/BatcherPaymentService.json")
+	if err != nil {
+		t.Fatalf("could not read contract abi: %v", err)
+	}
+	var artifact struct {
+		Abi abi.ABI `json:"abi"`
+	}
+	if err := json.Unmarshal(contractAbiJson, &artifact); err != nil {
+		t.Fatalf("could not parse contract abi: %v", err)
+	}
+	inlineAbi, err := abi.JSON(strings.NewReader(batcherPaymentServiceAbi))
+	if err != nil {
+		t.Fatalf("could not parse inline abi: %v", err)
+	}
+
+	for name, method := range inlineAbi.Methods {
+		contractMethod, ok := artifact.Abi.Methods[name]
+		if !ok || contractMethod.Sig != method.Sig || len(contractMethod.Outputs) != len(method.Outputs) {
+			t.Errorf("method %s doesn't match the contract", method.Sig)
+		}
+	}
+	if !artifact.Abi.HasReceive() {
+		t.Errorf("contract has no receive function to deposit to")
+	}
+}
@@ -0,0 +1,188 @@
+package aligned
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	csservicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
+)
+
+// QuorumThresholdPercentage is the percentage of the stake of the quorum that must sign a batch, as
+// in the service manager
+const QuorumThresholdPercentage = 67
+
+var (
+	ErrInvalidAggregatedSignature = errors.New("invalid aggregated signature")
+	ErrQuorumThresholdNotMet      = errors.New("signed stake below the quorum threshold")
+	ErrNotTaskResponse            = errors.New("not a task response")
+)
+
+// TaskResponse is the response to the task of a batch the aggregator sent to the service manager,
+// with the aggregated signature of the operators on the batch identifier hash
+type TaskResponse struct {
+	BatchMerkleRoot             [32]byte
+	SenderAddress               ethcommon.Address
+	NonSignerStakesAndSignature csservicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature
+}
+
+// StakeTotals are the stake of the quorum that signed a response and the total one at the
+// block the task was created
+type StakeTotals struct {
+	Signed *big.Int
+	Total  *big.Int
+}
+
+// SignatureChecker checks the signature of a response against the registries of the operators, as
+// the service manager does
+type SignatureChecker interface {
+	CheckSignatures(opts *bind.CallOpts, msgHash [32]byte, referenceBlockNumber uint32, params csservicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature) (csservicemanager.IBLSSignatureCheckerQuorumStakeTotals, [32]byte, error)
+}
+
+// BatchIdentifierHash returns the hash the operators sign for a batch, identifying it by its
+// merkle root and the address that created its task
+func BatchIdentifierHash(batchMerkleRoot [32]byte, senderAddress ethcommon.Address) [32]byte {
+	return merkle.BatchIdentifierHash(merkle.CurrentVersion, batchMerkleRoot, senderAddress)
+}
+
+// ParseTaskResponse decodes the calldata of a respondToTaskV2 transaction of the service manager
+func ParseTaskResponse(calldata []byte) (*TaskResponse, error) {
+	serviceManagerAbi, err := csservicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	method := serviceManagerAbi.Methods["respondToTaskV2"]
+	if len(calldata) < 4 || !bytes.Equal(calldata[:4], method.ID) {
+		return nil, ErrNotTaskResponse
+	}
+	values, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, fmt.Errorf("could not decode task response: %w", err)
+	}
+	response := &TaskResponse{
+		BatchMerkleRoot: values[0].([32]byte),
+		SenderAddress:   values[1].(ethcommon.Address),
+	}
+	response.NonSignerStakesAndSignature = *abi.ConvertType(values[2], new(csservicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature)).(*csservicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature)
+	return response, nil
+}
+
+// BatchIdentifierHash returns the hash signed by the response
+func (r *TaskResponse) BatchIdentifierHash() [32]byte {
+	return BatchIdentifierHash(r.BatchMerkleRoot, r.SenderAddress)
+}
+
+// VerifySignature checks the aggregated signature is the signature of the batch identifier hash by
+// the aggregated G2 public key, and that this key matches the G1 public key of the quorum without
+// the non signers. Aligned has a single quorum, so responses of more than one are refused.
+// It doesn't check the keys against the registries, see CheckStake.
+func (r *TaskResponse) VerifySignature() error {
+	params := &r.NonSignerStakesAndSignature
+	if len(params.QuorumApks) != 1 {
+		return fmt.Errorf("%w: %d quorums, expected 1", ErrInvalidAggregatedSignature, len(params.QuorumApks))
+	}
+	// The points come from the transaction calldata, the pairing assumes they are valid
+	apk, err := utils.ConvertFromBN254G1Point("quorum apk", params.QuorumApks[0])
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAggregatedSignature, err)
+	}
+	for _, nonSignerPubkey := range params.NonSignerPubkeys {
+		nonSigner, err := utils.ConvertFromBN254G1Point("non signer pubkey", nonSignerPubkey)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAggregatedSignature, err)
+		}
+		apk.Sub(nonSigner)
+	}
+	apkG2, err := utils.ConvertFromBN254G2Point("apk G2", params.ApkG2)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAggregatedSignature, err)
+	}
+
+	matches, err := apk.VerifyEquivalence(apkG2)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAggregatedSignature, err)
+	}
+	if !matches {
+		return fmt.Errorf("%w: G1 and G2 aggregated public keys don't match", ErrInvalidAggregatedSignature)
+	}
+	sigma, err := utils.ConvertFromBN254G1Point("signature", params.Sigma)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAggregatedSignature, err)
+	}
+	signature := bls.Signature{G1Point: sigma}
+	valid, err := signature.Verify(apkG2, r.BatchIdentifierHash())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAggregatedSignature, err)
+	}
+	if !valid {
+		return ErrInvalidAggregatedSignature
+	}
+	return nil
+}
+
+// CheckStake checks the response against the operators registered at the reference block, the one
+// the task was created in, returning the stake that signed it. It fails with ErrQuorumThresholdNotMet
+// if the signed stake is below the threshold.
+func (r *TaskResponse) CheckStake(opts *bind.CallOpts, checker SignatureChecker, referenceBlock uint32) (StakeTotals, error) {
+	totals, _, err := checker.CheckSignatures(opts, r.BatchIdentifierHash(), referenceBlock, r.NonSignerStakesAndSignature)
+	if err != nil {
+		return StakeTotals{}, fmt.Errorf("%w: %w", ErrInvalidAggregatedSignature, err)
+	}
+	if len(totals.SignedStakeForQuorum) == 0 || len(totals.TotalStakeForQuorum) == 0 {
+		return StakeTotals{}, fmt.Errorf("%w: no quorum stake", ErrInvalidAggregatedSignature)
+	}
+	stake := StakeTotals{Signed: totals.SignedStakeForQuorum[0], Total: totals.TotalStakeForQuorum[0]}
+	signed := new(big.Int).Mul(stake.Signed, big.NewInt(100))
+	threshold := new(big.Int).Mul(stake.Total, big.NewInt(QuorumThresholdPercentage))
+	if signed.Cmp(threshold) < 0 {
+		return stake, fmt.Errorf("%w: signed %s of %s", ErrQuorumThresholdNotMet, stake.Signed, stake.Total)
+	}
+	return stake, nil
+}
+
+// VerifyTaskResponse reads the response sent in the transaction to the service manager of the network
+// and checks its signature and the stake that signed it, at the block the task of the batch was created
+func VerifyTaskResponse(ctx context.Context, client *ethclient.Client, network Network, txHash ethcommon.Hash) (*TaskResponse, StakeTotals, error) {
+	serviceManagerAddr, err := network.AlignedServiceManagerAddress()
+	if err != nil {
+		return nil, StakeTotals{}, err
+	}
+	tx, _, err := client.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, StakeTotals{}, fmt.Errorf("could not get transaction %s: %w", txHash.Hex(), err)
+	}
+	if tx.To() == nil || *tx.To() != serviceManagerAddr {
+		return nil, StakeTotals{}, fmt.Errorf("%w: transaction %s not sent to the service manager", ErrNotTaskResponse, txHash.Hex())
+	}
+	response, err := ParseTaskResponse(tx.Data())
+	if err != nil {
+		return nil, StakeTotals{}, err
+	}
+	if err := response.VerifySignature(); err != nil {
+		return response, StakeTotals{}, err
+	}
+
+	serviceManager, err := csservicemanager.NewContractAlignedLayerServiceManager(serviceManagerAddr, client)
+	if err != nil {
+		return response, StakeTotals{}, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	batchState, err := serviceManager.BatchesState(opts, response.BatchIdentifierHash())
+	if err != nil {
+		return response, StakeTotals{}, fmt.Errorf("could not get batch state: %w", err)
+	}
+	if batchState.TaskCreatedBlock == 0 {
+		return response, StakeTotals{}, ErrBatchNotCreated
+	}
+	stake, err := response.CheckStake(opts, serviceManager, batchState.TaskCreatedBlock)
+	return response, stake, err
+}
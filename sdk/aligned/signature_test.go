@@ -0,0 +1,110 @@
+package aligned
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	csservicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+type fakeSignatureChecker struct {
+	signed, total int64
+}
+
+func (c fakeSignatureChecker) CheckSignatures(opts *bind.CallOpts, msgHash [32]byte, referenceBlockNumber uint32, params csservicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature) (csservicemanager.IBLSSignatureCheckerQuorumStakeTotals, [32]byte, error) {
+	return csservicemanager.IBLSSignatureCheckerQuorumStakeTotals{
+		SignedStakeForQuorum: []*big.Int{big.NewInt(c.signed)},
+		TotalStakeForQuorum:  []*big.Int{big.NewInt(c.total)},
+	}, [32]byte{}, nil
+}
+
+// testTaskResponseCalldata returns the calldata of a response signed by the first of two operators
+func testTaskResponseCalldata(t *testing.T, root [32]byte, sender ethcommon.Address) []byte {
+	signer, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("could not generate keys: %v", err)
+	}
+	nonSigner, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("could not generate keys: %v", err)
+	}
+	quorumApk := bls.NewZeroG1Point().Add(signer.GetPubKeyG1()).Add(nonSigner.GetPubKeyG1())
+	signature := signer.SignMessage(BatchIdentifierHash(root, sender))
+
+	params := csservicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature{
+		NonSignerQuorumBitmapIndices: []uint32{0},
+		NonSignerPubkeys:             []csservicemanager.BN254G1Point{utils.ConvertToBN254G1Point(nonSigner.GetPubKeyG1())},
+		QuorumApks:                   []csservicemanager.BN254G1Point{utils.ConvertToBN254G1Point(quorumApk)},
+		ApkG2:                        utils.ConvertToBN254G2Point(signer.GetPubKeyG2()),
+		Sigma:                        utils.ConvertToBN254G1Point(signature.G1Point),
+		QuorumApkIndices:             []uint32{0},
+		TotalStakeIndices:            []uint32{0},
+		NonSignerStakeIndices:        [][]uint32{{0}},
+	}
+	serviceManagerAbi, err := csservicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("could not get abi: %v", err)
+	}
+	calldata, err := serviceManagerAbi.Pack("respondToTaskV2", root, sender, params)
+	if err != nil {
+		t.Fatalf("could not encode response: %v", err)
+	}
+	return calldata
+}
+
+func TestVerifyTaskResponseSignature(t *testing.T) {
+	root := [32]byte{1}
+	sender := ethcommon.HexToAddress("0x2")
+	response, err := ParseTaskResponse(testTaskResponseCalldata(t, root, sender))
+	if err != nil {
+		t.Fatalf("could not parse response: %v", err)
+	}
+	if response.BatchMerkleRoot != root || response.SenderAddress != sender {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if err := response.VerifySignature(); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+
+	// the signature of another batch
+	response.BatchMerkleRoot = [32]byte{3}
+	if err := response.VerifySignature(); !errors.Is(err, ErrInvalidAggregatedSignature) {
+		t.Errorf("expected invalid signature, got %v", err)
+	}
+	response.BatchMerkleRoot = root
+
+	// a signature off the curve
+	sigma := response.NonSignerStakesAndSignature.Sigma
+	response.NonSignerStakesAndSignature.Sigma.Y = new(big.Int).Add(sigma.Y, big.NewInt(1))
+	var invalidPoint *utils.InvalidPointError
+	if err := response.VerifySignature(); !errors.Is(err, ErrInvalidAggregatedSignature) || !errors.As(err, &invalidPoint) {
+		t.Errorf("expected invalid signature point, got %v", err)
+	}
+	response.NonSignerStakesAndSignature.Sigma = sigma
+
+	// a signature claiming the non signer signed
+	response.NonSignerStakesAndSignature.NonSignerPubkeys = nil
+	if err := response.VerifySignature(); !errors.Is(err, ErrInvalidAggregatedSignature) {
+		t.Errorf("expected invalid signature without non signers, got %v", err)
+	}
+
+	if _, err := ParseTaskResponse([]byte{1, 2, 3, 4}); !errors.Is(err, ErrNotTaskResponse) {
+		t.Errorf("expected not a task response, got %v", err)
+	}
+}
+
+func TestCheckStake(t *testing.T) {
+	response := &TaskResponse{}
+	stake, err := response.CheckStake(nil, fakeSignatureChecker{signed: 67, total: 100}, 1)
+	if err != nil || stake.Signed.Int64() != 67 {
+		t.Errorf("expected threshold met, got %+v, %v", stake, err)
+	}
+	if _, err := response.CheckStake(nil, fakeSignatureChecker{signed: 66, total: 100}, 1); !errors.Is(err, ErrQuorumThresholdNotMet) {
+		t.Errorf("expected threshold not met, got %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package aligned
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+const (
+	// DefaultProofStatusLookback is the number of blocks searched for the batch of a proof, about a day
+	DefaultProofStatusLookback = 7200
+	// DefaultConfirmationDepth is the number of blocks after which a response is considered final,
+	// two epochs
+	DefaultConfirmationDepth = 64
+	// maxBatchLeavesSize bounds the download of the leaves of a batch
+	maxBatchLeavesSize = 32 << 20
+)
+
+var ErrBatchLeavesUnavailable = errors.New("batch leaves unavailable")
+
+// ProofState is the stage a proof sent through the batcher is in
+type ProofState string
+
+const (
+	// ProofPending is the state of a proof not included in a batch yet, queued in the batcher or not sent
+	ProofPending ProofState = "pending"
+	// ProofBatched is the state of a proof in a batch whose task was created but not responded
+	ProofBatched ProofState = "batched"
+	// ProofResponded is the state of a proof whose batch was verified, with less confirmations than the depth
+	ProofResponded ProofState = "responded"
+	// ProofFinalized is the state of a proof whose batch was verified at least the confirmation depth blocks ago
+	ProofFinalized ProofState = "finalized"
+)
+
+// ProofStatus is the stage of a proof, and the batch it's in
+type ProofStatus struct {
+	State ProofState
+	// Proof is the verification data of the proof in its batch, nil while pending
+	Proof *batcher.AlignedVerificationData
+	// CreatedBlock is the block the task of the batch was created in
+	CreatedBlock uint64
+	// RespondedBlock is the block the batch was verified in, and Confirmations the number of blocks
+	// since, counting it
+	RespondedBlock uint64
+	Confirmations  uint64
+}
+
+// ProofStatusOpts configures the search of the batch of a proof
+type ProofStatusOpts struct {
+	// FromBlock is the first block to search the batch in, DefaultProofStatusLookback blocks
+	// before the latest one if 0
+	FromBlock uint64
+	// ConfirmationDepth defaults to DefaultConfirmationDepth, and BlockRange to DefaultEventsBlockRange
+	ConfirmationDepth uint64
+	BlockRange        uint64
+	// HttpClient downloads the leaves of the batches, http.DefaultClient if nil
+	HttpClient *http.Client
+}
+
+// GetProofStatus returns the stage of the proof with the commitment sent through the batcher of the
+// network. Its batch is searched in the batches created by the BatcherPaymentService since the
+// block of the options, downloading their leaves from the batch data service, and its response in
+// the BatchVerified events.
+// A proof not found is pending, unless the leaves of a batch couldn't be downloaded, which returns
+// ErrBatchLeavesUnavailable, as the proof may be in it.
+func GetProofStatus(ctx context.Context, source LogSource, network Network, commitment batcher.VerificationDataCommitment, opts ProofStatusOpts) (ProofStatus, error) {
+	serviceManagerAddr, err := network.AlignedServiceManagerAddress()
+	if err != nil {
+		return ProofStatus{}, err
+	}
+	paymentServiceAddr, err := network.BatcherPaymentServiceAddress()
+	if err != nil {
+		return ProofStatus{}, err
+	}
+	reader, err := newEventsReader(source, serviceManagerAddr, paymentServiceAddr, SubscribeOpts{
+		SenderAddress: &paymentServiceAddr,
+		BlockRange:    opts.BlockRange,
+	})
+	if err != nil {
+		return ProofStatus{}, err
+	}
+	if opts.ConfirmationDepth == 0 {
+		opts.ConfirmationDepth = DefaultConfirmationDepth
+	}
+	if opts.HttpClient == nil {
+		opts.HttpClient = http.DefaultClient
+	}
+
+	latest, err := source.BlockNumber(ctx)
+	if err != nil {
+		return ProofStatus{}, fmt.Errorf("could not get block number: %w", err)
+	}
+	from := opts.FromBlock
+	if from == 0 {
+		from = latest - min(DefaultProofStatusLookback, latest)
+	}
+
+	status := ProofStatus{State: ProofPending}
+	leaf := commitment.Hash()
+	var leavesErr error
+	for start := from; start <= latest; start += reader.opts.BlockRange {
+		end := min(start+reader.opts.BlockRange-1, latest)
+		logs, err := reader.filterLogs(ctx, start, end)
+		if err != nil {
+			return ProofStatus{}, err
+		}
+		for _, log := range logs {
+			for _, event := range reader.parse(log) {
+				switch {
+				case event.Type == EventBatchCreated && status.Proof == nil:
+					proof, err := findProofInBatch(ctx, opts.HttpClient, event, leaf)
+					if err != nil {
+						leavesErr = err
+						continue
+					}
+					if proof != nil {
+						proof.VerificationDataCommitment = commitment
+						status = ProofStatus{State: ProofBatched, Proof: proof, CreatedBlock: event.BlockNumber}
+					}
+				case event.Type == EventBatchVerified && status.Proof != nil && event.BatchMerkleRoot == status.Proof.BatchMerkleRoot:
+					status.RespondedBlock = event.BlockNumber
+					status.Confirmations = latest - event.BlockNumber + 1
+					status.State = ProofResponded
+					if status.Confirmations >= opts.ConfirmationDepth {
+						status.State = ProofFinalized
+					}
+					return status, nil
+				}
+			}
+		}
+	}
+	if status.Proof == nil && leavesErr != nil {
+		return status, leavesErr
+	}
+	return status, nil
+}
+
+// findProofInBatch downloads the leaves of the batch, returning the inclusion of the leaf in it,
+// or nil if it's not in the batch
+func findProofInBatch(ctx context.Context, client *http.Client, event Event, leaf [32]byte) (*batcher.AlignedVerificationData, error) {
+	leaves, err := FetchBatchLeaves(ctx, client, event.BatchDataPointer, event.BatchMerkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	for i := range leaves {
+		if leaves[i] == leaf {
+			_, path := merklePath(leaves, i)
+			return &batcher.AlignedVerificationData{
+				BatchMerkleRoot:     event.BatchMerkleRoot,
+				BatchInclusionProof: path,
+				IndexInBatch:        uint64(i),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// FetchBatchLeaves downloads the leaves of a batch, the commitment of each of its proofs, which the
+// batcher uploads next to the batch in the data service, checking they build its merkle root
+func FetchBatchLeaves(ctx context.Context, client *http.Client, batchDataPointer string, batchMerkleRoot [32]byte) ([][32]byte, error) {
+	leaves, err := fetchBatchLeaves(ctx, client, batchDataPointer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: batch %x: %w", ErrBatchLeavesUnavailable, batchMerkleRoot, err)
+	}
+	if BatchMerkleRoot(leaves) != batchMerkleRoot {
+		return nil, fmt.Errorf("%w: batch %x: leaves don't match the batch merkle root", ErrBatchLeavesUnavailable, batchMerkleRoot)
+	}
+	return leaves, nil
+}
+
+// fetchBatchLeaves downloads the leaves of the batch from the data service
+func fetchBatchLeaves(ctx context.Context, client *http.Client, batchDataPointer string) ([][32]byte, error) {
+	pointerUrl, err := url.Parse(batchDataPointer)
+	if err != nil || (pointerUrl.Scheme != "http" && pointerUrl.Scheme != "https") || !strings.HasSuffix(pointerUrl.Path, ".json") {
+		return nil, fmt.Errorf("batch %s is not in the data service", batchDataPointer)
+	}
+	pointerUrl.Path = strings.TrimSuffix(pointerUrl.Path, ".json") + ".leaves"
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, pointerUrl.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", response.Status)
+	}
+	encodedLeaves, err := io.ReadAll(io.LimitReader(response.Body, maxBatchLeavesSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(encodedLeaves) == 0 || len(encodedLeaves)%32 != 0 || len(encodedLeaves) > maxBatchLeavesSize {
+		return nil, fmt.Errorf("invalid batch leaves size %d", len(encodedLeaves))
+	}
+	leaves := make([][32]byte, len(encodedLeaves)/32)
+	for i := range leaves {
+		copy(leaves[i][:], encodedLeaves[i*32:])
+	}
+	return leaves, nil
+}
+
+// BatchMerkleRoot returns the merkle root of a batch with the leaves, the commitment hashes of its
+// proofs in order
+func BatchMerkleRoot(leaves [][32]byte) [32]byte {
+	root, _ := merklePath(leaves, 0)
+	return root
+}
+
+// merklePath returns the root of the merkle tree of the leaves and the path of the leaf at the index,
+// built as in the batcher
+func merklePath(leaves [][32]byte, index int) ([32]byte, [][32]byte) {
+	root, path, _ := merkle.Path(merkle.CurrentVersion, leaves, index)
+	return root, path
+}
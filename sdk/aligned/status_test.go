@@ -0,0 +1,71 @@
+package aligned
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+func TestGetProofStatus(t *testing.T) {
+	paymentServiceAddr, _ := Devnet.BatcherPaymentServiceAddress()
+	commitments := []batcher.VerificationDataCommitment{
+		{ProofCommitment: [32]byte{1}},
+		{ProofCommitment: [32]byte{2}},
+		{ProofCommitment: [32]byte{3}},
+	}
+	var leaves [][32]byte
+	var encodedLeaves []byte
+	for i := range commitments {
+		leaf := commitments[i].Hash()
+		leaves = append(leaves, leaf)
+		encodedLeaves = append(encodedLeaves, leaf[:]...)
+	}
+	root, _ := merklePath(leaves, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/batch.leaves" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(encodedLeaves)
+	}))
+	defer server.Close()
+
+	source := &fakeLogSource{}
+	source.add(t, 10, "NewBatchV3", root, paymentServiceAddr, uint32(10), server.URL+"/batch.json", big.NewInt(100))
+	ctx := context.Background()
+	opts := ProofStatusOpts{FromBlock: 1, BlockRange: 8, ConfirmationDepth: 10}
+
+	status, err := GetProofStatus(ctx, source, Devnet, commitments[1], opts)
+	if err != nil {
+		t.Fatalf("could not get proof status: %v", err)
+	}
+	if status.State != ProofBatched || status.CreatedBlock != 10 || status.Proof == nil || !status.Proof.VerifyInclusion() {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	source.add(t, 20, "BatchVerified", root, paymentServiceAddr)
+	if status, err = GetProofStatus(ctx, source, Devnet, commitments[2], opts); err != nil || status.State != ProofResponded || status.RespondedBlock != 20 {
+		t.Errorf("expected responded status, got %+v, %v", status, err)
+	}
+	source.block = 29
+	if status, err = GetProofStatus(ctx, source, Devnet, commitments[2], opts); err != nil || status.State != ProofFinalized || status.Confirmations != 10 {
+		t.Errorf("expected finalized status, got %+v, %v", status, err)
+	}
+
+	other := batcher.VerificationDataCommitment{ProofGeneratorAddr: ethcommon.HexToAddress("0x1")}
+	if status, err = GetProofStatus(ctx, source, Devnet, other, opts); err != nil || status.State != ProofPending {
+		t.Errorf("expected pending status, got %+v, %v", status, err)
+	}
+
+	source.add(t, 30, "NewBatchV3", [32]byte{9}, paymentServiceAddr, uint32(30), server.URL+"/missing.json", big.NewInt(100))
+	if _, err = GetProofStatus(ctx, source, Devnet, other, opts); !errors.Is(err, ErrBatchLeavesUnavailable) {
+		t.Errorf("expected batch leaves unavailable, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package aligned
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+	csservicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// VerificationPollInterval is how often the verification of a proof is checked while waiting for
+// it, besides when the BatchVerified event of its batch is received
+const VerificationPollInterval = 10 * time.Second
+
+var ErrVerificationTimeout = errors.New("proof not verified in time")
+
+// serviceManager is the part of the AlignedLayerServiceManager contract used to check verifications
+type serviceManager interface {
+	VerifyBatchInclusionCaller
+	WatchBatchVerified(opts *bind.WatchOpts, sink chan<- *csservicemanager.ContractAlignedLayerServiceManagerBatchVerified, batchMerkleRoot [][32]byte) (event.Subscription, error)
+}
+
+// verifier checks the verification of the proofs sent through the batcher of a network, whose
+// batches are created by the BatcherPaymentService contract
+type verifier struct {
+	serviceManager     serviceManager
+	paymentServiceAddr ethcommon.Address
+	pollInterval       time.Duration
+}
+
+func newVerifier(client *ethclient.Client, network Network) (*verifier, error) {
+	serviceManagerAddr, err := network.AlignedServiceManagerAddress()
+	if err != nil {
+		return nil, err
+	}
+	paymentServiceAddr, err := network.BatcherPaymentServiceAddress()
+	if err != nil {
+		return nil, err
+	}
+	serviceManager, err := csservicemanager.NewContractAlignedLayerServiceManager(serviceManagerAddr, client)
+	if err != nil {
+		return nil, err
+	}
+	return &verifier{
+		serviceManager:     serviceManager,
+		paymentServiceAddr: paymentServiceAddr,
+		pollInterval:       VerificationPollInterval,
+	}, nil
+}
+
+// isVerified returns whether the batch of the proof was verified and the proof is in it
+func (v *verifier) isVerified(ctx context.Context, data *batcher.AlignedVerificationData) (bool, error) {
+	args := verifyBatchInclusionArgs(data, v.paymentServiceAddr)
+	return args.Call(&bind.CallOpts{Context: ctx}, v.serviceManager)
+}
+
+// waitForVerification returns once the proof is verified, checking it when the BatchVerified
+// event of its batch is received and every poll interval, as the event is missed if the batch
+// was verified before subscribing, and http rpcs don't support subscriptions.
+func (v *verifier) waitForVerification(ctx context.Context, data *batcher.AlignedVerificationData) error {
+	verifiedBatches := make(chan *csservicemanager.ContractAlignedLayerServiceManagerBatchVerified, 1)
+	var subscriptionErrs <-chan error
+	subscription, err := v.serviceManager.WatchBatchVerified(&bind.WatchOpts{Context: ctx}, verifiedBatches, [][32]byte{data.BatchMerkleRoot})
+	if err == nil {
+		defer subscription.Unsubscribe()
+		subscriptionErrs = subscription.Err()
+	}
+
+	ticker := time.NewTicker(v.pollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		verified, err := v.isVerified(ctx, data)
+		if err == nil && verified {
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%w: %w, last check failed: %w", ErrVerificationTimeout, ctx.Err(), lastErr)
+			}
+			return fmt.Errorf("%w: %w", ErrVerificationTimeout, ctx.Err())
+		case <-verifiedBatches:
+		case <-subscriptionErrs:
+			// keep polling without the subscription
+			subscriptionErrs = nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsProofVerified returns whether the proof sent through the batcher was verified by Aligned
+func IsProofVerified(ctx context.Context, ethRpcUrl string, network Network, data batcher.AlignedVerificationData) (bool, error) {
+	client, err := ethclient.DialContext(ctx, ethRpcUrl)
+	if err != nil {
+		return false, fmt.Errorf("could not connect to eth rpc: %w", err)
+	}
+	defer client.Close()
+	v, err := newVerifier(client, network)
+	if err != nil {
+		return false, err
+	}
+	return v.isVerified(ctx, &data)
+}
+
+// SubmitMultipleAndWaitForVerification signs and sends the proofs to the batcher with consecutive
+// nonces starting at nonce, fetching it from the batcher if nil, and waits for the batches they
+// are included in to be verified on chain. The proofs are paid from the balance of the key in the
+// BatcherPaymentService contract, up to maxFee each.
+// The wait is bounded by the context. On error, it returns the verification data of the proofs
+// included so far, which may be verified later. The errors can be retried with WithRetries.
+func SubmitMultipleAndWaitForVerification(ctx context.Context, batcherUrl string, ethRpcUrl string, network Network, verificationData []batcher.VerificationData, maxFee *big.Int, privateKey *ecdsa.PrivateKey, nonce *big.Int) ([]batcher.AlignedVerificationData, error) {
+	ethClient, err := ethclient.DialContext(ctx, ethRpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to eth rpc: %w", err)
+	}
+	defer ethClient.Close()
+	chainId, err := ethClient.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get chain id: %w", err)
+	}
+	v, err := newVerifier(ethClient, network)
+	if err != nil {
+		return nil, err
+	}
+
+	batcherClient, err := batcher.Dial(ctx, batcherUrl)
+	if err != nil {
+		return nil, sdkError(err)
+	}
+	signer := batcher.NewSigner(privateKey, chainId, v.paymentServiceAddr)
+	responses, err := batcherClient.Submit(ctx, signer, verificationData, maxFee, nonce)
+	// the verification is read from the chain, so the connection isn't needed while waiting
+	_ = batcherClient.Close()
+	if err != nil {
+		return responses, sdkError(err)
+	}
+
+	// a batch is verified as a whole, so it's waited for once
+	waited := make(map[[32]byte]bool)
+	for i := range responses {
+		if waited[responses[i].BatchMerkleRoot] {
+			continue
+		}
+		if err := v.waitForVerification(ctx, &responses[i]); err != nil {
+			return responses, err
+		}
+		waited[responses[i].BatchMerkleRoot] = true
+	}
+	return responses, nil
+}
+
+// SubmitAndWaitForVerification sends a proof to the batcher and waits for it to be verified on chain,
+// as SubmitMultipleAndWaitForVerification
+func SubmitAndWaitForVerification(ctx context.Context, batcherUrl string, ethRpcUrl string, network Network, verificationData batcher.VerificationData, maxFee *big.Int, privateKey *ecdsa.PrivateKey, nonce *big.Int) (batcher.AlignedVerificationData, error) {
+	responses, err := SubmitMultipleAndWaitForVerification(ctx, batcherUrl, ethRpcUrl, network, []batcher.VerificationData{verificationData}, maxFee, privateKey, nonce)
+	if err != nil {
+		return batcher.AlignedVerificationData{}, err
+	}
+	return responses[0], nil
+}
@@ -0,0 +1,96 @@
+package aligned
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	csservicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/sdk/batcher"
+)
+
+// fakeServiceManager reports the proofs as verified once verify is called, sending the event
+// to the subscribers
+type fakeServiceManager struct {
+	mu       sync.Mutex
+	verified bool
+	sinks    []chan<- *csservicemanager.ContractAlignedLayerServiceManagerBatchVerified
+}
+
+func (m *fakeServiceManager) VerifyBatchInclusion(opts *bind.CallOpts, proofCommitment [32]byte, pubInputCommitment [32]byte, provingSystemAuxDataCommitment [32]byte, proofGeneratorAddr [20]byte, batchMerkleRoot [32]byte, merkleProof []byte, verificationDataBatchIndex *big.Int, senderAddress ethcommon.Address) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.verified, nil
+}
+
+func (m *fakeServiceManager) WatchBatchVerified(opts *bind.WatchOpts, sink chan<- *csservicemanager.ContractAlignedLayerServiceManagerBatchVerified, batchMerkleRoot [][32]byte) (event.Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sinks = append(m.sinks, sink)
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func (m *fakeServiceManager) verify(batchMerkleRoot [32]byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verified = true
+	for _, sink := range m.sinks {
+		sink <- &csservicemanager.ContractAlignedLayerServiceManagerBatchVerified{BatchMerkleRoot: batchMerkleRoot}
+	}
+}
+
+func (m *fakeServiceManager) subscribers() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sinks)
+}
+
+func TestWaitForVerificationReturnsOnBatchVerifiedEvent(t *testing.T) {
+	serviceManager := &fakeServiceManager{}
+	// the poll interval is longer than the test, so only the event can make it return
+	v := &verifier{serviceManager: serviceManager, pollInterval: time.Hour}
+	data := &batcher.AlignedVerificationData{BatchMerkleRoot: [32]byte{1}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- v.waitForVerification(ctx, data) }()
+
+	for serviceManager.subscribers() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	serviceManager.verify(data.BatchMerkleRoot)
+	if err := <-done; err != nil {
+		t.Errorf("expected the proof to be verified, got %v", err)
+	}
+}
+
+func TestWaitForVerificationTimesOut(t *testing.T) {
+	v := &verifier{serviceManager: &fakeServiceManager{}, pollInterval: 10 * time.Millisecond}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := v.waitForVerification(ctx, &batcher.AlignedVerificationData{})
+	if !errors.Is(err, ErrVerificationTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected verification timeout, got %v", err)
+	}
+}
+
+func TestNetworkAddresses(t *testing.T) {
+	addr, err := Mainnet.AlignedServiceManagerAddress()
+	if err != nil || addr != ethcommon.HexToAddress("0xeF2A435e5EE44B2041100EF8cbC8ae035166606c") {
+		t.Errorf("unexpected mainnet service manager address %s, %v", addr, err)
+	}
+	if _, err := Network("unknown").BatcherPaymentServiceAddress(); err == nil {
+		t.Errorf("expected error for unknown network")
+	}
+}
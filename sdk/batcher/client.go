@@ -0,0 +1,208 @@
+package batcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+)
+
+// ExpectedProtocolVersion is the newest batcher protocol version the client speaks
+const ExpectedProtocolVersion = 4
+
+// MaxProofsPerSubmission is the max number of proofs sent at once
+const MaxProofsPerSubmission = 10000
+
+var (
+	ErrConnection     = errors.New("could not connect to batcher")
+	ErrConnectionLost = errors.New("batcher connection lost")
+	// ErrProofReplaced is returned while waiting for proofs when the batcher closes the connection,
+	// which it does when a proof is replaced by a message with its nonce and a higher max fee
+	ErrProofReplaced = errors.New("proof replaced by another message")
+)
+
+// Client is a websocket connection to the batcher. Calls are serialized, as the batcher answers
+// the messages of a connection in order. A call cancelled by its context closes the connection,
+// and the client can't be used afterwards.
+type Client struct {
+	conn            *websocket.Conn
+	protocolVersion uint16
+	mu              sync.Mutex
+}
+
+// Dial connects to the batcher and checks its protocol version, which it sends first on each connection
+func Dial(ctx context.Context, batcherUrl string) (*Client, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, batcherUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnection, err)
+	}
+	c := &Client{conn: conn}
+
+	message, err := c.read(ctx)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	version, err := decodeProtocolVersion(message)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if version > ExpectedProtocolVersion {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w: batcher version %d, supported version %d", ErrProtocolVersionMismatch, version, ExpectedProtocolVersion)
+	}
+	c.protocolVersion = version
+	return c, nil
+}
+
+// ProtocolVersion returns the protocol version sent by the batcher
+func (c *Client) ProtocolVersion() uint16 {
+	return c.protocolVersion
+}
+
+// Close closes the connection, which makes the calls in progress return
+func (c *Client) Close() error {
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return c.conn.Close()
+}
+
+// read returns the next binary message of the batcher
+func (c *Client) read(ctx context.Context) ([]byte, error) {
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	for {
+		messageType, message, err := c.conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				_ = c.conn.Close()
+				return nil, ctx.Err()
+			}
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				return nil, fmt.Errorf("%w: batcher closed the connection: %w", ErrConnectionLost, err)
+			}
+			return nil, fmt.Errorf("%w: could not read batcher message: %w", ErrConnectionLost, err)
+		}
+		if messageType == websocket.BinaryMessage {
+			return message, nil
+		}
+	}
+}
+
+func (c *Client) write(ctx context.Context, message []byte) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetWriteDeadline(deadline)
+		defer func() { _ = c.conn.SetWriteDeadline(time.Time{}) }()
+	}
+	if err := c.conn.WriteMessage(websocket.BinaryMessage, message); err != nil {
+		return fmt.Errorf("%w: could not send message to batcher: %w", ErrConnectionLost, err)
+	}
+	return nil
+}
+
+// GetNonce returns the next nonce of the address for the batcher, which counts the proofs in
+// its queue not yet paid in the BatcherPaymentService contract
+func (c *Client) GetNonce(ctx context.Context, address ethcommon.Address) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	message, err := encodeGetNonceMessage(address)
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize message: %w", err)
+	}
+	if err := c.write(ctx, message); err != nil {
+		return nil, err
+	}
+	response, err := c.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return decodeGetNonceResponse(response)
+}
+
+// Submit signs and sends the proofs with consecutive nonces starting at nonce, fetching it from
+// the batcher if nil, and waits for the batcher to include them in batches. Each inclusion proof
+// is checked against the commitment of the proof sent.
+// It returns the verification data of the proofs in the order the batcher responded, which are all
+// of them unless an error is returned, as the batcher stops answering on the first proof it rejects.
+func (c *Client) Submit(ctx context.Context, signer *Signer, verificationData []VerificationData, maxFee *big.Int, nonce *big.Int) ([]AlignedVerificationData, error) {
+	if len(verificationData) == 0 {
+		return nil, fmt.Errorf("no verification data to submit")
+	}
+	if len(verificationData) > MaxProofsPerSubmission {
+		return nil, fmt.Errorf("too many proofs to submit at once: %d, max %d", len(verificationData), MaxProofsPerSubmission)
+	}
+	if nonce == nil {
+		var err error
+		if nonce, err = c.GetNonce(ctx, signer.Address()); err != nil {
+			return nil, fmt.Errorf("could not get nonce: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the commitments of the proofs sent by nonce, to match the responses
+	sent := make(map[string]VerificationDataCommitment, len(verificationData))
+	for i := range verificationData {
+		data := signer.Nonced(verificationData[i], new(big.Int).Add(nonce, big.NewInt(int64(i))), maxFee)
+		signature, err := signer.Sign(&data)
+		if err != nil {
+			return nil, err
+		}
+		message, err := encodeSubmitProofMessage(&data, signature)
+		if err != nil {
+			return nil, fmt.Errorf("could not serialize message: %w", err)
+		}
+		if err := c.write(ctx, message); err != nil {
+			return nil, err
+		}
+		sent[data.Nonce.String()] = verificationData[i].Commitment()
+	}
+
+	responses := make([]AlignedVerificationData, 0, len(verificationData))
+	for len(responses) < len(verificationData) {
+		message, err := c.read(ctx)
+		if err != nil {
+			if closedByBatcher(err) {
+				return responses, fmt.Errorf("%w: %w", ErrProofReplaced, err)
+			}
+			return responses, err
+		}
+		inclusionData, err := decodeSubmitProofResponse(message)
+		if err != nil {
+			return responses, err
+		}
+		commitment, ok := sent[inclusionData.UserNonce.String()]
+		if !ok {
+			return responses, fmt.Errorf("%w: inclusion data for nonce %s which wasn't sent", ErrUnexpectedResponse, inclusionData.UserNonce)
+		}
+		delete(sent, inclusionData.UserNonce.String())
+		if !inclusionData.VerifyInclusion(commitment) {
+			return responses, ErrInvalidInclusionProof
+		}
+		responses = append(responses, AlignedVerificationData{
+			VerificationDataCommitment: commitment,
+			BatchMerkleRoot:            inclusionData.BatchMerkleRoot,
+			BatchInclusionProof:        inclusionData.BatchInclusionProof,
+			IndexInBatch:               inclusionData.IndexInBatch,
+		})
+	}
+	return responses, nil
+}
+
+// closedByBatcher returns whether the batcher closed the connection with a close message, rather
+// than the connection being dropped
+func closedByBatcher(err error) bool {
+	var closeErr *websocket.CloseError
+	return errors.As(err, &closeErr) && closeErr.Code != websocket.CloseAbnormalClosure
+}
@@ -0,0 +1,185 @@
+package batcher
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/websocket"
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+// fakeBatcher answers nonce requests, and includes the proofs sent in a batch once batchSize are received
+type fakeBatcher struct {
+	t               *testing.T
+	protocolVersion uint16
+	nonce           *big.Int
+	batchSize       int
+	rejectNonce     bool
+	// closeOnSubmit closes the connection on the first proof, as the batcher does when it's replaced
+	closeOnSubmit bool
+}
+
+func (b *fakeBatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		b.t.Errorf("could not upgrade connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	b.send(conn)(EncodeProtocolVersion(b.protocolVersion))
+
+	var leaves [][32]byte
+	var nonces []*big.Int
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		message, err := DecodeClientMessage(data)
+		if err != nil {
+			b.t.Errorf("could not decode message: %v", err)
+			return
+		}
+		if message.GetNonceForAddress != nil {
+			b.send(conn)(EncodeGetNonceResponse(b.nonce, nil))
+			continue
+		}
+
+		proof := message.SubmitProof
+		sender, err := proof.VerificationData.RecoverSigner(proof.Signature)
+		if err != nil {
+			b.t.Errorf("could not recover signer: %v", err)
+			return
+		}
+		if b.closeOnSubmit {
+			_ = conn.WriteControl(websocket.CloseMessage, []byte{}, time.Now().Add(time.Second))
+			return
+		}
+		if b.rejectNonce {
+			b.send(conn)(EncodeSubmitProofError(ErrInvalidNonce, sender))
+			continue
+		}
+		commitment := proof.VerificationData.VerificationData.Commitment()
+		leaves = append(leaves, commitment.Hash())
+		nonces = append(nonces, proof.VerificationData.Nonce)
+		if len(leaves) < b.batchSize {
+			continue
+		}
+
+		// a batch of two leaves
+		root := crypto.Keccak256Hash(leaves[0][:], leaves[1][:])
+		for i := range leaves {
+			b.send(conn)(EncodeBatchInclusionData(&BatchInclusionData{
+				BatchMerkleRoot:     root,
+				BatchInclusionProof: [][32]byte{leaves[1-i]},
+				IndexInBatch:        uint64(i),
+				UserNonce:           nonces[i],
+			}))
+		}
+		leaves, nonces = nil, nil
+	}
+}
+
+// send returns a function sending an encoded message, to be called with the result of its encoding
+func (b *fakeBatcher) send(conn *websocket.Conn) func([]byte, error) {
+	return func(encoded []byte, err error) {
+		if err != nil {
+			b.t.Errorf("could not encode message: %v", err)
+			return
+		}
+		if err := conn.WriteMessage(websocket.BinaryMessage, encoded); err != nil {
+			b.t.Errorf("could not send message: %v", err)
+		}
+	}
+}
+
+func dialFakeBatcher(t *testing.T, batcher *fakeBatcher) *Client {
+	server := httptest.NewServer(batcher)
+	t.Cleanup(server.Close)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"))
+	if err != nil {
+		t.Fatalf("could not connect to batcher: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func testSigner(t *testing.T) *Signer {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	return NewSigner(privateKey, big.NewInt(31337), crypto.PubkeyToAddress(privateKey.PublicKey))
+}
+
+func TestSubmitWaitsForInclusion(t *testing.T) {
+	client := dialFakeBatcher(t, &fakeBatcher{t: t, protocolVersion: ExpectedProtocolVersion, nonce: big.NewInt(7), batchSize: 2})
+	signer := testSigner(t)
+	verificationData := []VerificationData{
+		{ProvingSystem: common.SP1, Proof: []byte{1, 2, 3}, VmProgramCode: []byte{4, 5}, ProofGeneratorAddr: signer.Address()},
+		{ProvingSystem: common.Groth16Bn254, Proof: []byte{6}, PubInput: []byte{7}, VerificationKey: []byte{8}, ProofGeneratorAddr: signer.Address()},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	responses, err := client.Submit(ctx, signer, verificationData, big.NewInt(1000), nil)
+	if err != nil {
+		t.Fatalf("could not submit proofs: %v", err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+	for i, response := range responses {
+		if response.IndexInBatch != uint64(i) || response.VerificationDataCommitment != verificationData[i].Commitment() {
+			t.Errorf("unexpected response %d: %+v", i, response)
+		}
+	}
+}
+
+func TestSubmitReturnsBatcherErrors(t *testing.T) {
+	client := dialFakeBatcher(t, &fakeBatcher{t: t, protocolVersion: ExpectedProtocolVersion, rejectNonce: true})
+	signer := testSigner(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	responses, err := client.Submit(ctx, signer, []VerificationData{{ProvingSystem: common.SP1, Proof: []byte{1}}}, big.NewInt(1000), big.NewInt(0))
+	if !errors.Is(err, ErrInvalidNonce) {
+		t.Errorf("expected invalid nonce error, got %v", err)
+	}
+	if len(responses) != 0 {
+		t.Errorf("expected no responses, got %d", len(responses))
+	}
+}
+
+func TestSubmitReturnsProofReplaced(t *testing.T) {
+	client := dialFakeBatcher(t, &fakeBatcher{t: t, protocolVersion: ExpectedProtocolVersion, closeOnSubmit: true})
+	signer := testSigner(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.Submit(ctx, signer, []VerificationData{{ProvingSystem: common.SP1, Proof: []byte{1}}}, big.NewInt(1000), big.NewInt(0))
+	if !errors.Is(err, ErrProofReplaced) || !errors.Is(err, ErrConnectionLost) {
+		t.Errorf("expected proof replaced error, got %v", err)
+	}
+}
+
+func TestDialChecksProtocolVersion(t *testing.T) {
+	server := httptest.NewServer(&fakeBatcher{t: t, protocolVersion: ExpectedProtocolVersion + 1})
+	defer server.Close()
+
+	_, err := Dial(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"))
+	if !errors.Is(err, ErrProtocolVersionMismatch) {
+		t.Errorf("expected protocol version mismatch, got %v", err)
+	}
+}
@@ -0,0 +1,359 @@
+package batcher
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+// The messages are CBOR encoded the way serde serializes the types of the batcher: structs as maps
+// of their fields, enums as the name of unit variants or as a single entry map from the variant name
+// to its fields, byte vectors and arrays as arrays of integers, and integers and addresses as hex strings.
+
+var (
+	ErrProtocolVersionMismatch      = errors.New("batcher protocol version is newer than the supported one")
+	ErrUnexpectedResponse           = errors.New("unexpected batcher response")
+	ErrInvalidInclusionProof        = errors.New("batch inclusion proof does not match the proof sent")
+	ErrInvalidNonce                 = errors.New("invalid nonce")
+	ErrInvalidSignature             = errors.New("invalid signature")
+	ErrProofTooLarge                = errors.New("proof too large")
+	ErrInvalidMaxFee                = errors.New("invalid max fee")
+	ErrInsufficientBalance          = errors.New("insufficient balance")
+	ErrInvalidChainId               = errors.New("invalid chain id")
+	ErrInvalidReplacementMessage    = errors.New("invalid replacement message")
+	ErrAddToBatch                   = errors.New("could not add proof to batch")
+	ErrEthRpc                       = errors.New("batcher eth rpc error")
+	ErrInvalidPaymentServiceAddress = errors.New("invalid payment service address")
+	ErrInvalidProof                 = errors.New("invalid proof")
+	ErrCreateNewTask                = errors.New("could not create task")
+	ErrProofQueueFlushed            = errors.New("batcher proof queue flushed")
+	ErrBatcher                      = errors.New("batcher error")
+	ErrInvalidRequest               = errors.New("invalid request")
+)
+
+var encMode, _ = cbor.EncOptions{ByteArray: cbor.ByteArrayToArray}.EncMode()
+var decMode, _ = cbor.DecOptions{MaxArrayElements: 2147483647}.DecMode()
+
+const (
+	cborMajorTypeArray = 4
+	cborNull           = 0xf6
+)
+
+// cborBytes is encoded as an array of integers, as the batcher serializes byte vectors, or as null if nil
+type cborBytes []byte
+
+func (b cborBytes) MarshalCBOR() ([]byte, error) {
+	if b == nil {
+		return []byte{cborNull}, nil
+	}
+	encoded := appendCborHead(make([]byte, 0, 9+2*len(b)), cborMajorTypeArray, uint64(len(b)))
+	for _, v := range b {
+		if v < 24 {
+			encoded = append(encoded, v)
+		} else {
+			encoded = append(encoded, 24, v)
+		}
+	}
+	return encoded, nil
+}
+
+func (b *cborBytes) UnmarshalCBOR(data []byte) error {
+	var decoded []byte
+	if err := decMode.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+func appendCborHead(encoded []byte, majorType byte, n uint64) []byte {
+	head := majorType << 5
+	switch {
+	case n < 24:
+		return append(encoded, head|byte(n))
+	case n <= 0xff:
+		return append(encoded, head|24, byte(n))
+	case n <= 0xffff:
+		return append(encoded, head|25, byte(n>>8), byte(n))
+	case n <= 0xffffffff:
+		return append(encoded, head|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	default:
+		return append(encoded, head|27, byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32),
+			byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+// encodeU256 encodes an integer as a hex string without leading zeros
+func encodeU256(n *big.Int) string {
+	if n == nil {
+		return "0x0"
+	}
+	return "0x" + n.Text(16)
+}
+
+func decodeU256(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok || !strings.HasPrefix(s, "0x") || n.Sign() < 0 || n.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid uint256 %q", s)
+	}
+	return n, nil
+}
+
+func encodeAddress(address ethcommon.Address) string {
+	return "0x" + hex.EncodeToString(address[:])
+}
+
+func decodeAddress(s string) (ethcommon.Address, error) {
+	if !strings.HasPrefix(s, "0x") || !ethcommon.IsHexAddress(s) {
+		return ethcommon.Address{}, fmt.Errorf("invalid address %q", s)
+	}
+	return ethcommon.HexToAddress(s), nil
+}
+
+type wireVerificationData struct {
+	ProvingSystem      common.ProvingSystemId `cbor:"proving_system"`
+	Proof              cborBytes              `cbor:"proof"`
+	PubInput           cborBytes              `cbor:"pub_input"`
+	VerificationKey    cborBytes              `cbor:"verification_key"`
+	VmProgramCode      cborBytes              `cbor:"vm_program_code"`
+	ProofGeneratorAddr string                 `cbor:"proof_generator_addr"`
+}
+
+type wireNoncedVerificationData struct {
+	VerificationData   wireVerificationData `cbor:"verification_data"`
+	Nonce              string               `cbor:"nonce"`
+	MaxFee             string               `cbor:"max_fee"`
+	ChainId            string               `cbor:"chain_id"`
+	PaymentServiceAddr string               `cbor:"payment_service_addr"`
+}
+
+type wireSignature struct {
+	R string `cbor:"r"`
+	S string `cbor:"s"`
+	V uint64 `cbor:"v"`
+}
+
+type wireSubmitProofMessage struct {
+	VerificationData wireNoncedVerificationData `cbor:"verification_data"`
+	Signature        wireSignature              `cbor:"signature"`
+}
+
+type wireMerkleProof struct {
+	MerklePath [][32]byte `cbor:"merkle_path"`
+}
+
+type wireBatchInclusionData struct {
+	BatchMerkleRoot     [32]byte        `cbor:"batch_merkle_root"`
+	BatchInclusionProof wireMerkleProof `cbor:"batch_inclusion_proof"`
+	IndexInBatch        uint64          `cbor:"index_in_batch"`
+	UserNonce           string          `cbor:"user_nonce"`
+}
+
+func encodeGetNonceMessage(address ethcommon.Address) ([]byte, error) {
+	return encMode.Marshal(map[string]string{"GetNonceForAddress": encodeAddress(address)})
+}
+
+func toWireVerificationData(verificationData *VerificationData) wireVerificationData {
+	return wireVerificationData{
+		ProvingSystem:      verificationData.ProvingSystem,
+		Proof:              verificationData.Proof,
+		PubInput:           verificationData.PubInput,
+		VerificationKey:    verificationData.VerificationKey,
+		VmProgramCode:      verificationData.VmProgramCode,
+		ProofGeneratorAddr: encodeAddress(verificationData.ProofGeneratorAddr),
+	}
+}
+
+func (w *wireVerificationData) toVerificationData() (VerificationData, error) {
+	proofGeneratorAddr, err := decodeAddress(w.ProofGeneratorAddr)
+	if err != nil {
+		return VerificationData{}, err
+	}
+	return VerificationData{
+		ProvingSystem:      w.ProvingSystem,
+		Proof:              w.Proof,
+		PubInput:           w.PubInput,
+		VerificationKey:    w.VerificationKey,
+		VmProgramCode:      w.VmProgramCode,
+		ProofGeneratorAddr: proofGeneratorAddr,
+	}, nil
+}
+
+func encodeSubmitProofMessage(data *NoncedVerificationData, signature Signature) ([]byte, error) {
+	return encMode.Marshal(map[string]wireSubmitProofMessage{
+		"SubmitProof": {
+			VerificationData: wireNoncedVerificationData{
+				VerificationData:   toWireVerificationData(&data.VerificationData),
+				Nonce:              encodeU256(data.Nonce),
+				MaxFee:             encodeU256(data.MaxFee),
+				ChainId:            encodeU256(data.ChainId),
+				PaymentServiceAddr: encodeAddress(data.PaymentServiceAddr),
+			},
+			Signature: wireSignature{
+				R: encodeU256(signature.R),
+				S: encodeU256(signature.S),
+				V: signature.V,
+			},
+		},
+	})
+}
+
+// decodeVariant decodes an enum serialized by the batcher into its variant name and fields,
+// which are nil for unit variants
+func decodeVariant(data []byte) (string, cbor.RawMessage, error) {
+	var name string
+	if err := decMode.Unmarshal(data, &name); err == nil {
+		return name, nil, nil
+	}
+	var variant map[string]cbor.RawMessage
+	if err := decMode.Unmarshal(data, &variant); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+	}
+	if len(variant) == 1 {
+		for name, fields := range variant {
+			return name, fields, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%w: expected a single variant, got %d", ErrUnexpectedResponse, len(variant))
+}
+
+func decodeFields(fields cbor.RawMessage, v any) error {
+	if fields == nil {
+		return fmt.Errorf("%w: missing variant fields", ErrUnexpectedResponse)
+	}
+	if err := decMode.Unmarshal(fields, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+	}
+	return nil
+}
+
+// decodeProtocolVersion decodes the first message sent by the batcher on each connection
+func decodeProtocolVersion(data []byte) (uint16, error) {
+	name, fields, err := decodeVariant(data)
+	if err != nil {
+		return 0, err
+	}
+	if name != "ProtocolVersion" {
+		return 0, fmt.Errorf("%w: expected the protocol version, got %s", ErrUnexpectedResponse, name)
+	}
+	var version uint16
+	if err := decodeFields(fields, &version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// decodeSubmitProofResponse decodes the response to a proof sent, returning an error if the batcher
+// didn't include it in a batch
+func decodeSubmitProofResponse(data []byte) (*BatchInclusionData, error) {
+	name, fields, err := decodeVariant(data)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "BatchInclusionData":
+		var wire wireBatchInclusionData
+		if err := decodeFields(fields, &wire); err != nil {
+			return nil, err
+		}
+		userNonce, err := decodeU256(wire.UserNonce)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+		}
+		return &BatchInclusionData{
+			BatchMerkleRoot:     wire.BatchMerkleRoot,
+			BatchInclusionProof: wire.BatchInclusionProof.MerklePath,
+			IndexInBatch:        wire.IndexInBatch,
+			UserNonce:           userNonce,
+		}, nil
+	case "InvalidNonce":
+		return nil, ErrInvalidNonce
+	case "InvalidSignature":
+		return nil, ErrInvalidSignature
+	case "ProofTooLarge":
+		return nil, ErrProofTooLarge
+	case "InvalidMaxFee":
+		return nil, ErrInvalidMaxFee
+	case "InvalidChainId":
+		return nil, ErrInvalidChainId
+	case "InvalidReplacementMessage":
+		return nil, ErrInvalidReplacementMessage
+	case "AddToBatchError":
+		return nil, ErrAddToBatch
+	case "EthRpcError":
+		return nil, ErrEthRpc
+	case "BatchReset":
+		return nil, ErrProofQueueFlushed
+	case "InsufficientBalance":
+		var address string
+		if err := decodeFields(fields, &address); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInsufficientBalance, address)
+	case "InvalidPaymentServiceAddress":
+		var addresses [2]string
+		if err := decodeFields(fields, &addresses); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s, expected %s", ErrInvalidPaymentServiceAddress, addresses[0], addresses[1])
+	case "InvalidProof":
+		reason, reasonFields, err := decodeVariant(fields)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "DisabledVerifier" {
+			var provingSystem common.ProvingSystemId
+			if err := decodeFields(reasonFields, &provingSystem); err != nil {
+				return nil, err
+			}
+			reason = fmt.Sprintf("%s %s", reason, provingSystem.String())
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProof, reason)
+	case "CreateNewTaskError":
+		var rootAndError [2]string
+		if err := decodeFields(fields, &rootAndError); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w with merkle root %s: %s", ErrCreateNewTask, rootAndError[0], rootAndError[1])
+	case "Error":
+		var message string
+		if err := decodeFields(fields, &message); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrBatcher, message)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedResponse, name)
+	}
+}
+
+// decodeGetNonceResponse decodes the response to a nonce request
+func decodeGetNonceResponse(data []byte) (*big.Int, error) {
+	name, fields, err := decodeVariant(data)
+	if err != nil {
+		return nil, err
+	}
+	var value string
+	if err := decodeFields(fields, &value); err != nil {
+		return nil, err
+	}
+	switch name {
+	case "Nonce":
+		nonce, err := decodeU256(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+		}
+		return nonce, nil
+	case "EthRpcError":
+		return nil, fmt.Errorf("%w: %s", ErrEthRpc, value)
+	case "InvalidRequest":
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, value)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedResponse, name)
+	}
+}
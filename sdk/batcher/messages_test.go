@@ -0,0 +1,192 @@
+package batcher
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+func TestEncodeGetNonceMessage(t *testing.T) {
+	address := ethcommon.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	encoded, err := encodeGetNonceMessage(address)
+	if err != nil {
+		t.Fatalf("could not encode message: %v", err)
+	}
+
+	// {"GetNonceForAddress": "0x70997970c51812dc3a010c7d01b50e0d17dc79c8"}
+	expected := append([]byte{0xa1, 0x72}, "GetNonceForAddress"...)
+	expected = append(expected, 0x78, 42)
+	expected = append(expected, "0x70997970c51812dc3a010c7d01b50e0d17dc79c8"...)
+	if !bytes.Equal(encoded, expected) {
+		t.Errorf("unexpected encoding %s, expected %s", hex.EncodeToString(encoded), hex.EncodeToString(expected))
+	}
+}
+
+func TestCborBytesEncoding(t *testing.T) {
+	cases := []struct {
+		value    cborBytes
+		expected []byte
+	}{
+		{nil, []byte{0xf6}},
+		{cborBytes{}, []byte{0x80}},
+		{cborBytes{1, 23, 24, 200}, []byte{0x84, 1, 23, 0x18, 24, 0x18, 200}},
+	}
+	for _, c := range cases {
+		encoded, err := encMode.Marshal(c.value)
+		if err != nil {
+			t.Fatalf("could not encode %v: %v", c.value, err)
+		}
+		if !bytes.Equal(encoded, c.expected) {
+			t.Errorf("unexpected encoding of %v: %x, expected %x", c.value, encoded, c.expected)
+		}
+
+		var decoded cborBytes
+		if err := decMode.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("could not decode %x: %v", encoded, err)
+		}
+		if !bytes.Equal(decoded, c.value) || (decoded == nil) != (c.value == nil) {
+			t.Errorf("unexpected decoding of %x: %v, expected %v", encoded, decoded, c.value)
+		}
+	}
+}
+
+func TestU256Encoding(t *testing.T) {
+	for _, n := range []*big.Int{big.NewInt(0), big.NewInt(255), new(big.Int).Lsh(big.NewInt(1), 255)} {
+		decoded, err := decodeU256(encodeU256(n))
+		if err != nil || decoded.Cmp(n) != 0 {
+			t.Errorf("could not roundtrip %s: %v, %v", n, decoded, err)
+		}
+	}
+	if encodeU256(big.NewInt(255)) != "0xff" {
+		t.Errorf("unexpected encoding %s", encodeU256(big.NewInt(255)))
+	}
+	for _, s := range []string{"ff", "0x", "0xzz", "0x1" + string(bytes.Repeat([]byte("0"), 64))} {
+		if _, err := decodeU256(s); err == nil {
+			t.Errorf("expected error decoding %q", s)
+		}
+	}
+}
+
+func TestDecodeSubmitProofResponse(t *testing.T) {
+	inclusionData, err := encMode.Marshal(map[string]wireBatchInclusionData{
+		"BatchInclusionData": {
+			BatchMerkleRoot:     [32]byte{1, 2, 3},
+			BatchInclusionProof: wireMerkleProof{MerklePath: [][32]byte{{4}, {5}}},
+			IndexInBatch:        2,
+			UserNonce:           "0x2a",
+		},
+	})
+	if err != nil {
+		t.Fatalf("could not encode response: %v", err)
+	}
+	decoded, err := decodeSubmitProofResponse(inclusionData)
+	if err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+	if decoded.BatchMerkleRoot != [32]byte{1, 2, 3} || decoded.IndexInBatch != 2 || decoded.UserNonce.Int64() != 42 ||
+		len(decoded.BatchInclusionProof) != 2 || decoded.BatchInclusionProof[1] != [32]byte{5} {
+		t.Errorf("unexpected inclusion data %+v", decoded)
+	}
+
+	cases := []struct {
+		response any
+		expected error
+	}{
+		{"InvalidNonce", ErrInvalidNonce},
+		{"BatchReset", ErrProofQueueFlushed},
+		{map[string]string{"InsufficientBalance": "0x70997970c51812dc3a010c7d01b50e0d17dc79c8"}, ErrInsufficientBalance},
+		{map[string]string{"InvalidProof": "RejectedProof"}, ErrInvalidProof},
+		{map[string]map[string]common.ProvingSystemId{"InvalidProof": {"DisabledVerifier": common.SP1}}, ErrInvalidProof},
+		{map[string][]string{"CreateNewTaskError": {"0x01", "reverted"}}, ErrCreateNewTask},
+		{map[string]uint16{"ProtocolVersion": 4}, ErrUnexpectedResponse},
+		{"Unknown", ErrUnexpectedResponse},
+	}
+	for _, c := range cases {
+		encoded, err := encMode.Marshal(c.response)
+		if err != nil {
+			t.Fatalf("could not encode %v: %v", c.response, err)
+		}
+		if _, err := decodeSubmitProofResponse(encoded); !errors.Is(err, c.expected) {
+			t.Errorf("unexpected error decoding %v: %v, expected %v", c.response, err, c.expected)
+		}
+	}
+}
+
+func TestSignatureRecoversSigner(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	signer := NewSigner(privateKey, big.NewInt(31337), ethcommon.HexToAddress("0x7bc06c482DEAd17c0e297aFbC32f6e63d3846650"))
+	data := signer.Nonced(VerificationData{
+		ProvingSystem:      common.Groth16Bn254,
+		Proof:              []byte{42, 42, 42, 42},
+		PubInput:           []byte{32, 32, 32, 32},
+		VerificationKey:    []byte{8, 8, 8, 8},
+		ProofGeneratorAddr: signer.Address(),
+	}, big.NewInt(1), big.NewInt(2))
+
+	signature, err := signer.Sign(&data)
+	if err != nil {
+		t.Fatalf("could not sign: %v", err)
+	}
+	if signature.V != 27 && signature.V != 28 {
+		t.Errorf("unexpected recovery id %d", signature.V)
+	}
+	recovered, err := data.RecoverSigner(signature)
+	if err != nil {
+		t.Fatalf("could not recover signer: %v", err)
+	}
+	if recovered != signer.Address() {
+		t.Errorf("recovered %s, expected %s", recovered, signer.Address())
+	}
+
+	data.Nonce = big.NewInt(2)
+	if recovered, _ := data.RecoverSigner(signature); recovered == signer.Address() {
+		t.Errorf("signature recovered the signer for different data")
+	}
+}
+
+func TestDecodeClientMessage(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	signer := NewSigner(privateKey, big.NewInt(31337), ethcommon.HexToAddress("0x7bc06c482DEAd17c0e297aFbC32f6e63d3846650"))
+	data := signer.Nonced(VerificationData{
+		ProvingSystem:      common.SP1,
+		Proof:              []byte{1, 2, 3},
+		VmProgramCode:      []byte{4, 5, 6},
+		ProofGeneratorAddr: signer.Address(),
+	}, big.NewInt(3), big.NewInt(1000))
+	signature, err := signer.Sign(&data)
+	if err != nil {
+		t.Fatalf("could not sign: %v", err)
+	}
+	encoded, err := encodeSubmitProofMessage(&data, signature)
+	if err != nil {
+		t.Fatalf("could not encode message: %v", err)
+	}
+
+	message, err := DecodeClientMessage(encoded)
+	if err != nil {
+		t.Fatalf("could not decode message: %v", err)
+	}
+	if message.SubmitProof == nil {
+		t.Fatalf("expected a proof, got %+v", message)
+	}
+	decoded := message.SubmitProof.VerificationData
+	if decoded.VerificationData.PubInput != nil || decoded.VerificationData.Commitment() != data.VerificationData.Commitment() {
+		t.Errorf("unexpected verification data %+v", decoded.VerificationData)
+	}
+	sender, err := decoded.RecoverSigner(message.SubmitProof.Signature)
+	if err != nil || sender != signer.Address() {
+		t.Errorf("recovered %s, %v, expected %s", sender, err, signer.Address())
+	}
+}
@@ -0,0 +1,175 @@
+package batcher
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// The batcher side of the protocol, to implement batchers speaking it
+
+// SubmitProofMessage is a proof sent to the batcher, signed by its sender
+type SubmitProofMessage struct {
+	VerificationData NoncedVerificationData
+	Signature        Signature
+}
+
+// ClientMessage is a message sent to the batcher, either a nonce request or a proof
+type ClientMessage struct {
+	GetNonceForAddress *ethcommon.Address
+	SubmitProof        *SubmitProofMessage
+}
+
+// DecodeClientMessage decodes a message sent to the batcher
+func DecodeClientMessage(data []byte) (*ClientMessage, error) {
+	name, fields, err := decodeVariant(data)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "GetNonceForAddress":
+		var wire string
+		if err := decodeFields(fields, &wire); err != nil {
+			return nil, err
+		}
+		address, err := decodeAddress(wire)
+		if err != nil {
+			return nil, err
+		}
+		return &ClientMessage{GetNonceForAddress: &address}, nil
+	case "SubmitProof":
+		var wire wireSubmitProofMessage
+		if err := decodeFields(fields, &wire); err != nil {
+			return nil, err
+		}
+		message, err := wire.toSubmitProofMessage()
+		if err != nil {
+			return nil, err
+		}
+		return &ClientMessage{SubmitProof: message}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown message %s", ErrUnexpectedResponse, name)
+	}
+}
+
+func (w *wireSubmitProofMessage) toSubmitProofMessage() (*SubmitProofMessage, error) {
+	verificationData, err := w.VerificationData.VerificationData.toVerificationData()
+	if err != nil {
+		return nil, err
+	}
+	var integers [5]*big.Int
+	for i, s := range []string{w.VerificationData.Nonce, w.VerificationData.MaxFee, w.VerificationData.ChainId, w.Signature.R, w.Signature.S} {
+		if integers[i], err = decodeU256(s); err != nil {
+			return nil, err
+		}
+	}
+	paymentServiceAddr, err := decodeAddress(w.VerificationData.PaymentServiceAddr)
+	if err != nil {
+		return nil, err
+	}
+	return &SubmitProofMessage{
+		VerificationData: NoncedVerificationData{
+			VerificationData:   verificationData,
+			Nonce:              integers[0],
+			MaxFee:             integers[1],
+			ChainId:            integers[2],
+			PaymentServiceAddr: paymentServiceAddr,
+		},
+		Signature: Signature{R: integers[3], S: integers[4], V: w.Signature.V},
+	}, nil
+}
+
+// EncodeProtocolVersion encodes the first message the batcher sends on each connection
+func EncodeProtocolVersion(version uint16) ([]byte, error) {
+	return encMode.Marshal(map[string]uint16{"ProtocolVersion": version})
+}
+
+// EncodeGetNonceResponse encodes the response to a nonce request, or its error
+func EncodeGetNonceResponse(nonce *big.Int, err error) ([]byte, error) {
+	switch {
+	case err == nil:
+		return encMode.Marshal(map[string]string{"Nonce": encodeU256(nonce)})
+	case errors.Is(err, ErrEthRpc):
+		return encMode.Marshal(map[string]string{"EthRpcError": err.Error()})
+	default:
+		return encMode.Marshal(map[string]string{"InvalidRequest": err.Error()})
+	}
+}
+
+// EncodeBatchInclusionData encodes the response to a proof included in a batch
+func EncodeBatchInclusionData(inclusionData *BatchInclusionData) ([]byte, error) {
+	return encMode.Marshal(map[string]wireBatchInclusionData{
+		"BatchInclusionData": {
+			BatchMerkleRoot:     inclusionData.BatchMerkleRoot,
+			BatchInclusionProof: wireMerkleProof{MerklePath: inclusionData.BatchInclusionProof},
+			IndexInBatch:        inclusionData.IndexInBatch,
+			UserNonce:           encodeU256(inclusionData.UserNonce),
+		},
+	})
+}
+
+// EncodeSubmitProofError encodes the response to a proof of the sender rejected with err, which
+// is one of the errors of the batcher responses, or a generic batcher error otherwise
+func EncodeSubmitProofError(err error, sender ethcommon.Address) ([]byte, error) {
+	unitVariants := []struct {
+		err  error
+		name string
+	}{
+		{ErrInvalidNonce, "InvalidNonce"},
+		{ErrInvalidSignature, "InvalidSignature"},
+		{ErrProofTooLarge, "ProofTooLarge"},
+		{ErrInvalidMaxFee, "InvalidMaxFee"},
+		{ErrInvalidChainId, "InvalidChainId"},
+		{ErrInvalidReplacementMessage, "InvalidReplacementMessage"},
+		{ErrAddToBatch, "AddToBatchError"},
+		{ErrEthRpc, "EthRpcError"},
+		{ErrProofQueueFlushed, "BatchReset"},
+	}
+	for _, variant := range unitVariants {
+		if errors.Is(err, variant.err) {
+			return encMode.Marshal(variant.name)
+		}
+	}
+	switch {
+	case errors.Is(err, ErrInsufficientBalance):
+		return encMode.Marshal(map[string]string{"InsufficientBalance": encodeAddress(sender)})
+	case errors.Is(err, ErrInvalidProof):
+		return encMode.Marshal(map[string]string{"InvalidProof": "RejectedProof"})
+	default:
+		return encMode.Marshal(map[string]string{"Error": err.Error()})
+	}
+}
+
+// EncodeCreateNewTaskError encodes the response to the proofs of a batch whose task couldn't be created
+func EncodeCreateNewTaskError(batchMerkleRoot [32]byte, err error) ([]byte, error) {
+	return encMode.Marshal(map[string][2]string{
+		"CreateNewTaskError": {ethcommon.Bytes2Hex(batchMerkleRoot[:]), err.Error()},
+	})
+}
+
+// EncodeBatch encodes the verification data of a batch, as uploaded for operators to download
+func EncodeBatch(verificationData []VerificationData) ([]byte, error) {
+	batch := make([]wireVerificationData, len(verificationData))
+	for i := range verificationData {
+		batch[i] = toWireVerificationData(&verificationData[i])
+	}
+	return encMode.Marshal(batch)
+}
+
+// DecodeBatch decodes the verification data of a batch, as downloaded by operators
+func DecodeBatch(data []byte) ([]VerificationData, error) {
+	var batch []wireVerificationData
+	if err := decMode.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("could not deserialize batch: %w", err)
+	}
+	verificationData := make([]VerificationData, len(batch))
+	for i := range batch {
+		var err error
+		if verificationData[i], err = batch[i].toVerificationData(); err != nil {
+			return nil, err
+		}
+	}
+	return verificationData, nil
+}
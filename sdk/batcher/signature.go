@@ -0,0 +1,110 @@
+package batcher
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	eip712DomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	// The verification data is hashed into its merkle leaf, as the BatcherPaymentService contract only has the leaf
+	noncedVerificationDataTypeHash = crypto.Keccak256Hash([]byte("NoncedVerificationData(bytes32 verification_data_hash,uint256 nonce,uint256 max_fee)"))
+	eip712DomainNameHash           = crypto.Keccak256Hash([]byte("Aligned"))
+	eip712DomainVersionHash        = crypto.Keccak256Hash([]byte("1"))
+)
+
+// Signature is an ECDSA signature with the recovery id as 27 or 28, as sent to the batcher
+type Signature struct {
+	R *big.Int
+	S *big.Int
+	V uint64
+}
+
+// Signer signs the verification data sent to the batcher, which is paid from the balance of its
+// address in the BatcherPaymentService contract
+type Signer struct {
+	privateKey         *ecdsa.PrivateKey
+	chainId            *big.Int
+	paymentServiceAddr ethcommon.Address
+}
+
+func NewSigner(privateKey *ecdsa.PrivateKey, chainId *big.Int, paymentServiceAddr ethcommon.Address) *Signer {
+	return &Signer{
+		privateKey:         privateKey,
+		chainId:            chainId,
+		paymentServiceAddr: paymentServiceAddr,
+	}
+}
+
+func (s *Signer) Address() ethcommon.Address {
+	return crypto.PubkeyToAddress(s.privateKey.PublicKey)
+}
+
+// Nonced returns the verification data to be signed with the given nonce and max fee
+func (s *Signer) Nonced(verificationData VerificationData, nonce *big.Int, maxFee *big.Int) NoncedVerificationData {
+	return NoncedVerificationData{
+		VerificationData:   verificationData,
+		Nonce:              nonce,
+		MaxFee:             maxFee,
+		ChainId:            s.chainId,
+		PaymentServiceAddr: s.paymentServiceAddr,
+	}
+}
+
+// Sign signs the EIP-712 typed data hash of the nonced verification data
+func (s *Signer) Sign(data *NoncedVerificationData) (Signature, error) {
+	signature, err := crypto.Sign(data.TypedDataHash().Bytes(), s.privateKey)
+	if err != nil {
+		return Signature{}, fmt.Errorf("could not sign verification data: %w", err)
+	}
+	return Signature{
+		R: new(big.Int).SetBytes(signature[:32]),
+		S: new(big.Int).SetBytes(signature[32:64]),
+		V: uint64(signature[64]) + 27,
+	}, nil
+}
+
+// TypedDataHash returns the EIP-712 hash of the nonced verification data, in the Aligned domain of
+// its chain and BatcherPaymentService contract
+func (d *NoncedVerificationData) TypedDataHash() ethcommon.Hash {
+	domainSeparator := crypto.Keccak256Hash(
+		eip712DomainTypeHash[:],
+		eip712DomainNameHash[:],
+		eip712DomainVersionHash[:],
+		math.U256Bytes(new(big.Int).Set(d.ChainId)),
+		ethcommon.LeftPadBytes(d.PaymentServiceAddr[:], 32),
+	)
+	commitment := d.VerificationData.Commitment()
+	verificationDataHash := commitment.Hash()
+	structHash := crypto.Keccak256Hash(
+		noncedVerificationDataTypeHash[:],
+		verificationDataHash[:],
+		math.U256Bytes(new(big.Int).Set(d.Nonce)),
+		math.U256Bytes(new(big.Int).Set(d.MaxFee)),
+	)
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator[:], structHash[:])
+}
+
+// RecoverSigner returns the address that signed the nonced verification data
+func (d *NoncedVerificationData) RecoverSigner(signature Signature) (ethcommon.Address, error) {
+	if signature.V != 27 && signature.V != 28 {
+		return ethcommon.Address{}, fmt.Errorf("invalid signature recovery id %d", signature.V)
+	}
+	if signature.R == nil || signature.S == nil || signature.R.BitLen() > 256 || signature.S.BitLen() > 256 {
+		return ethcommon.Address{}, fmt.Errorf("invalid signature values")
+	}
+	sig := make([]byte, 65)
+	signature.R.FillBytes(sig[:32])
+	signature.S.FillBytes(sig[32:64])
+	sig[64] = byte(signature.V - 27)
+	publicKey, err := crypto.SigToPub(d.TypedDataHash().Bytes(), sig)
+	if err != nil {
+		return ethcommon.Address{}, fmt.Errorf("could not recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
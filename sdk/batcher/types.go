@@ -0,0 +1,68 @@
+package batcher
+
+import (
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/core/utils/merkle"
+)
+
+// VerificationData is a proof to be verified by Aligned, with the data its verifier needs
+type VerificationData struct {
+	ProvingSystem   common.ProvingSystemId
+	Proof           []byte
+	PubInput        []byte
+	VerificationKey []byte
+	// VmProgramCode is the program of the zkVM proving systems, SP1 and Risc0
+	VmProgramCode      []byte
+	ProofGeneratorAddr ethcommon.Address
+}
+
+// NoncedVerificationData is the verification data signed by the sender to be paid from its balance
+// in the BatcherPaymentService contract
+type NoncedVerificationData struct {
+	VerificationData   VerificationData
+	Nonce              *big.Int
+	MaxFee             *big.Int
+	ChainId            *big.Int
+	PaymentServiceAddr ethcommon.Address
+}
+
+// VerificationDataCommitment is the leaf of a proof in the batch merkle tree
+type VerificationDataCommitment = merkle.VerificationDataCommitment
+
+// BatchInclusionData is the response of the batcher once a proof is included in a batch
+type BatchInclusionData struct {
+	BatchMerkleRoot     [32]byte
+	BatchInclusionProof [][32]byte
+	IndexInBatch        uint64
+	UserNonce           *big.Int
+}
+
+// AlignedVerificationData is the data needed to check a proof was verified by Aligned
+type AlignedVerificationData struct {
+	VerificationDataCommitment VerificationDataCommitment
+	BatchMerkleRoot            [32]byte
+	BatchInclusionProof        [][32]byte
+	IndexInBatch               uint64
+}
+
+// Commitment computes the commitment of the verification data, matching the one of the batcher
+func (v *VerificationData) Commitment() VerificationDataCommitment {
+	return merkle.NewVerificationDataCommitment(byte(v.ProvingSystem), v.Proof, v.PubInput, v.VerificationKey, v.VmProgramCode, v.ProofGeneratorAddr)
+}
+
+// VerifyInclusion checks the merkle path of the inclusion data leads from the commitment to the batch merkle root
+func (d *BatchInclusionData) VerifyInclusion(commitment VerificationDataCommitment) bool {
+	return verifyMerklePath(commitment.Hash(), d.BatchInclusionProof, d.IndexInBatch, d.BatchMerkleRoot)
+}
+
+// VerifyInclusion checks the merkle path of the verification data leads from its commitment to the batch merkle root
+func (d *AlignedVerificationData) VerifyInclusion() bool {
+	return verifyMerklePath(d.VerificationDataCommitment.Hash(), d.BatchInclusionProof, d.IndexInBatch, d.BatchMerkleRoot)
+}
+
+func verifyMerklePath(leaf [32]byte, path [][32]byte, index uint64, root [32]byte) bool {
+	return merkle.VerifyPath(merkle.CurrentVersion, leaf, path, index, root)
+}
@@ -20,6 +20,14 @@ if System.get_env("PHX_SERVER") do
   config :telemetry_api, TelemetryApiWeb.Endpoint, server: true
 end
 
+# Tokens accepted to submit traces, comma separated. Traces are accepted without a token if unset.
+config :telemetry_api,
+       :trace_api_tokens,
+       String.split(System.get_env("TELEMETRY_API_TOKENS", ""), ",", trim: true)
+
+# Port of the gRPC server receiving the aggregator traces
+config :telemetry_api, :grpc_port, String.to_integer(System.get_env("GRPC_PORT", "4002"))
+
 if config_env() == :prod do
   database_url =
     System.get_env("DATABASE_URL") ||
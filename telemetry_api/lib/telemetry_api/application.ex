@@ -19,6 +19,10 @@ defmodule TelemetryApi.Application do
       # {TelemetryApi.Worker, arg},
       # Start to serve requests, typically the last entry
       TelemetryApiWeb.Endpoint,
+      {GRPC.Server.Supervisor,
+       endpoint: TelemetryApiGrpc.Endpoint,
+       port: Application.get_env(:telemetry_api, :grpc_port, 4002),
+       start_server: true},
       TelemetryApi.Periodically
     ]
 
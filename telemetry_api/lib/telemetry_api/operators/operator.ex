@@ -8,6 +8,7 @@ defmodule TelemetryApi.Operators.Operator do
     field :stake, :string
     field :name, :string
     field :version, :string
+    field :version_status, :string
     field :status, :string
     field :eth_rpc_url, :string
     field :eth_rpc_url_fallback, :string
@@ -26,6 +27,7 @@ defmodule TelemetryApi.Operators.Operator do
       :stake,
       :name,
       :version,
+      :version_status,
       :status,
       :eth_rpc_url,
       :eth_rpc_url_fallback,
@@ -16,12 +16,14 @@ defmodule TelemetryApi.Traces do
   Send the trace to OpenTelemetry
 
   This function is responsible for creating a new span and storing the context in the Agent.
+  Returns the hex encoded trace id and aggregator span id, so the aggregator can link its metrics
+  to the trace and pass the trace context on to the operators.
 
   ## Examples
 
       iex> merkle_root = "0x1234567890abcdef"
       iex> create_task_trace(merkle_root)
-      :ok
+      {:ok, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"}
   """
   def create_task_trace(merkle_root) do
     with {:ok, trace} <- set_current_trace(merkle_root) do
@@ -45,7 +47,8 @@ defmodule TelemetryApi.Traces do
           | subspans: Map.put(trace.subspans, :aggregator, aggregator_subspan_ctx)
         })
 
-        :ok
+        {:ok, OpenTelemetry.Span.hex_trace_id(trace.parent_span),
+         OpenTelemetry.Span.hex_span_id(aggregator_subspan_ctx)}
       end
     end
   end
@@ -103,6 +106,93 @@ defmodule TelemetryApi.Traces do
     end
   end
 
+  @doc """
+  Registers the times an operator spent on the batch as an operator span of the task trace,
+  so they can be joined with the aggregator span. The span starts when the operator received
+  the batch, and has its download, verification per proving system and signature submission
+  times, in milliseconds, as attributes, and an event per traced zkVM proof. If the batch trace has the W3C traceparent the operator
+  got from the aggregator, the span is a child of the aggregator span, otherwise the trace is looked
+  up by merkle root.
+
+  ## Examples
+
+      iex> merkle_root = "0x1234567890abcdef"
+      iex> operator_id = "0x..."
+      iex> batch_trace = %{"total_time_ms" => 1200, "download_time_ms" => 300, "verification_time_ms" => %{"SP1" => 800}}
+      iex> register_operator_batch_trace(merkle_root, operator_id, batch_trace)
+      :ok
+  """
+  def register_operator_batch_trace(merkle_root, operator_id, batch_trace) do
+    with {:ok, operator} <- Operators.get_operator(%{id: operator_id}),
+         :ok <- validate_operator_registration(operator),
+         {:ok, parent_ctx} <-
+           operator_span_parent(merkle_root, Map.get(batch_trace, "traceparent")) do
+      verification_attributes =
+        batch_trace
+        |> Map.get("verification_time_ms", %{})
+        |> Enum.map(fn {proving_system, time} -> {"verification_time_ms." <> proving_system, time} end)
+
+      proofs_attributes =
+        batch_trace
+        |> Map.get("proofs", %{})
+        |> Enum.map(fn {proving_system, proofs} -> {"proofs." <> proving_system, proofs} end)
+
+      total_time = Map.get(batch_trace, "total_time_ms", 0)
+
+      operator_span_ctx =
+        Tracer.start_span(
+          parent_ctx,
+          "Operator: " <> operator.name,
+          %{
+            start_time:
+              :opentelemetry.timestamp() -
+                System.convert_time_unit(total_time, :millisecond, :native),
+            attributes:
+              [
+                {:merkle_root, merkle_root},
+                {:operator_id, operator_id},
+                {:name, operator.name},
+                {:total_time_ms, total_time},
+                {:download_time_ms, Map.get(batch_trace, "download_time_ms", 0)},
+                {:signature_submission_time_ms,
+                 Map.get(batch_trace, "signature_submission_time_ms", 0)},
+                {:signed, Map.get(batch_trace, "signed", false)}
+              ] ++ verification_attributes ++ proofs_attributes
+          }
+        )
+
+      # zkVM proofs are also traced one by one, with the hash of the program they prove
+      batch_trace
+      |> Map.get("proof_traces", [])
+      |> Enum.each(fn proof_trace ->
+        OpenTelemetry.Span.add_event(operator_span_ctx, "Proof verified", %{
+          proving_system: Map.get(proof_trace, "proving_system"),
+          program_hash: Map.get(proof_trace, "program_hash"),
+          verification_time_ms: Map.get(proof_trace, "verification_time_ms", 0),
+          result: Map.get(proof_trace, "result")
+        })
+      end)
+
+      OpenTelemetry.Span.end_span(operator_span_ctx)
+
+      IO.inspect(
+        "Operator batch trace included. merkle_root: #{inspect(merkle_root)} operator_id: #{inspect(operator_id)}"
+      )
+
+      :ok
+    end
+  end
+
+  defp operator_span_parent(merkle_root, traceparent) when traceparent in [nil, ""] do
+    with {:ok, _trace} <- set_current_trace(merkle_root) do
+      {:ok, Ctx.get_current()}
+    end
+  end
+
+  defp operator_span_parent(_merkle_root, traceparent) do
+    {:ok, :otel_propagator_text_map.extract_to(Ctx.new(), [{"traceparent", traceparent}])}
+  end
+
   @doc """
   Registers the failure creating a batcher task in the task trace.
 
@@ -236,17 +326,35 @@ defmodule TelemetryApi.Traces do
 
   @doc """
   Registers the start of the creation of a batcher task in the task trace.
+  The batch metadata, its size in bytes and number of proofs of each proving system, is set
+  in the batcher span, to correlate its latency with them.
 
   ## Examples
 
       iex> merkle_root
-      iex> batcher_task_started(merkle_root)
+      iex> batch_metadata = %{batch_size_bytes: 1024, proofs_by_proving_system: %{"SP1" => 2}}
+      iex> batcher_task_started(merkle_root, fee_per_proof, total_proofs, batch_metadata)
       :ok
   """
-  def batcher_task_started(merkle_root, fee_per_proof, total_proofs) do
+  def batcher_task_started(merkle_root, fee_per_proof, total_proofs, batch_metadata \\ %{}) do
     with {:ok, _trace} <- set_current_trace_with_subspan(merkle_root, :batcher) do
       IO.inspect("fee_per_proof: #{fee_per_proof}")
 
+      proving_system_attributes =
+        batch_metadata
+        |> Map.get(:proofs_by_proving_system, %{})
+        |> Enum.map(fn {proving_system, proofs} -> {"proofs." <> proving_system, proofs} end)
+
+      Tracer.set_attributes(
+        [
+          {:fee_per_proof, fee_per_proof},
+          {:total_proofs, total_proofs},
+          {:batch_size_bytes, Map.get(batch_metadata, :batch_size_bytes)}
+        ]
+        |> Enum.reject(fn {_key, value} -> is_nil(value) end)
+        |> Enum.concat(proving_system_attributes)
+      )
+
       Tracer.add_event("Batcher Task being created",
         fee_per_proof: fee_per_proof,
         total_proofs: total_proofs
@@ -257,16 +365,20 @@ defmodule TelemetryApi.Traces do
   end
 
   @doc """
-  Registers a reached quorum in the task trace.
+  Registers a reached quorum in the task trace, with the number of operators that didn't sign the batch.
 
   ## Examples
 
       iex> merkle_root = "0x1234567890abcdef"
-      iex> quorum_reached(merkle_root)
+      iex> quorum_reached(merkle_root, 2)
       :ok
   """
-  def quorum_reached(merkle_root) do
+  def quorum_reached(merkle_root, non_signers \\ nil) do
     with {:ok, _trace} <- set_current_trace_with_subspan(merkle_root, :aggregator) do
+      if non_signers != nil do
+        Tracer.set_attributes([{:non_signers, non_signers}])
+      end
+
       Tracer.add_event("Quorum Reached", [])
       IO.inspect("Reached quorum registered. merkle_root: #{merkle_root}")
       :ok
@@ -316,17 +428,23 @@ defmodule TelemetryApi.Traces do
   end
 
   @doc """
-  Registers the sending of an aggregator task to Ethereum in the task trace.
+  Registers the sending of an aggregator task to Ethereum in the task trace, with the gas used
+  and the fee paid by the transaction.
 
   ## Examples
 
       iex> merkle_root
       iex> tx_hash
-      iex> aggregator_task_sent(merkle_root, tx_hash, effective_gas_price)
+      iex> aggregator_task_sent(merkle_root, tx_hash, effective_gas_price, gas_used, fee_paid)
       :ok
   """
-  def aggregator_task_sent(merkle_root, tx_hash, effective_gas_price) do
+  def aggregator_task_sent(merkle_root, tx_hash, effective_gas_price, gas_used \\ nil, fee_paid \\ nil) do
     with {:ok, _trace} <- set_current_trace_with_subspan(merkle_root, :aggregator) do
+      Tracer.set_attributes(
+        [{:gas_used, gas_used}, {:fee_paid, fee_paid}]
+        |> Enum.reject(fn {_key, value} -> is_nil(value) end)
+      )
+
       Tracer.add_event("Task Sent to Ethereum", [{"tx_hash", tx_hash}, {"effective_gas_price", effective_gas_price}])
       :ok
     end
@@ -0,0 +1,26 @@
+defmodule TelemetryApiGrpc.AuthInterceptor do
+  @moduledoc """
+  Authenticates the gRPC calls with the same tokens as the HTTP trace submissions, sent in the
+  `authorization` metadata. See `TelemetryApiWeb.Plugs.TraceAuth`.
+  """
+  @behaviour GRPC.Server.Interceptor
+
+  alias TelemetryApiWeb.Plugs.TraceAuth
+
+  @impl true
+  def init(opts), do: opts
+
+  @impl true
+  def call(request, stream, next, _opts) do
+    tokens = Application.get_env(:telemetry_api, :trace_api_tokens, [])
+    authorization = GRPC.Stream.get_headers(stream)["authorization"]
+
+    if tokens == [] or TraceAuth.authorized?(authorization, tokens) do
+      next.(request, stream)
+    else
+      raise GRPC.RPCError,
+        status: GRPC.Status.unauthenticated(),
+        message: "Invalid or missing API token"
+    end
+  end
+end
@@ -0,0 +1,6 @@
+defmodule TelemetryApiGrpc.Endpoint do
+  use GRPC.Endpoint
+
+  intercept(TelemetryApiGrpc.AuthInterceptor)
+  run(TelemetryApiGrpc.TelemetryServer)
+end
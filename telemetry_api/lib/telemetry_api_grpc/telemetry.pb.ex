@@ -0,0 +1,45 @@
+# Messages and service of priv/proto/telemetry.proto
+defmodule TelemetryApiGrpc.InitTaskTraceRequest do
+  use Protobuf, syntax: :proto3
+
+  field :merkle_root, 1, type: :string, json_name: "merkleRoot"
+end
+
+defmodule TelemetryApiGrpc.InitTaskTraceResponse do
+  use Protobuf, syntax: :proto3
+
+  field :merkle_root, 1, type: :string, json_name: "merkleRoot"
+  field :trace_id, 2, type: :string, json_name: "traceId"
+  field :span_id, 3, type: :string, json_name: "spanId"
+end
+
+defmodule TelemetryApiGrpc.TelemetryEvent do
+  use Protobuf, syntax: :proto3
+
+  field :endpoint, 1, type: :string
+  field :message, 2, type: :bytes
+end
+
+defmodule TelemetryApiGrpc.TelemetryEvents do
+  use Protobuf, syntax: :proto3
+
+  field :events, 1, repeated: true, type: TelemetryApiGrpc.TelemetryEvent
+end
+
+defmodule TelemetryApiGrpc.StreamEventsResponse do
+  use Protobuf, syntax: :proto3
+
+  field :received, 1, type: :uint64
+end
+
+defmodule TelemetryApiGrpc.Telemetry.Service do
+  use GRPC.Service, name: "aligned.telemetry.v1.Telemetry"
+
+  rpc :InitTaskTrace,
+      TelemetryApiGrpc.InitTaskTraceRequest,
+      TelemetryApiGrpc.InitTaskTraceResponse
+
+  rpc :StreamEvents,
+      stream(TelemetryApiGrpc.TelemetryEvents),
+      TelemetryApiGrpc.StreamEventsResponse
+end
@@ -0,0 +1,88 @@
+defmodule TelemetryApiGrpc.TelemetryServer do
+  @moduledoc """
+  Receives the task traces of the aggregator over gRPC. Starting a trace is a call, since the
+  aggregator needs its trace context right away, while the rest of the events are streamed in
+  batches over a persistent connection.
+
+  The events are the JSON messages the aggregator would post to the HTTP endpoints, so they are
+  handled the same way. The events that fail are logged and skipped, so they don't close the
+  stream.
+  """
+  use GRPC.Server, service: TelemetryApiGrpc.Telemetry.Service
+
+  require Logger
+
+  alias TelemetryApi.Traces
+  alias TelemetryApiGrpc.InitTaskTraceResponse
+  alias TelemetryApiGrpc.StreamEventsResponse
+
+  def init_task_trace(%{merkle_root: merkle_root}, _stream) do
+    case Traces.create_task_trace(merkle_root) do
+      {:ok, trace_id, span_id} ->
+        %InitTaskTraceResponse{merkle_root: merkle_root, trace_id: trace_id, span_id: span_id}
+
+      error ->
+        raise GRPC.RPCError, status: GRPC.Status.internal(), message: inspect(error)
+    end
+  end
+
+  def stream_events(batches, _stream) do
+    received =
+      Enum.reduce(batches, 0, fn %{events: events}, received ->
+        Enum.each(events, &handle_event/1)
+        received + length(events)
+      end)
+
+    %StreamEventsResponse{received: received}
+  end
+
+  defp handle_event(%{endpoint: endpoint, message: message}) do
+    with {:ok, params} <- Jason.decode(message),
+         :ok <- dispatch(endpoint, params) do
+      :ok
+    else
+      error ->
+        Logger.warning("Could not handle telemetry event #{endpoint}: #{inspect(error)}")
+    end
+  end
+
+  defp dispatch("/api/operatorResponse", %{
+         "merkle_root" => merkle_root,
+         "operator_id" => operator_id
+       }),
+       do: Traces.register_operator_response(merkle_root, operator_id)
+
+  defp dispatch("/api/quorumReached", %{"merkle_root" => merkle_root} = params),
+    do: Traces.quorum_reached(merkle_root, params["non_signers"])
+
+  defp dispatch("/api/taskError", %{"merkle_root" => merkle_root, "error" => error}),
+    do: Traces.task_error(merkle_root, error)
+
+  defp dispatch("/api/aggregatorTaskSetGasPrice", %{
+         "merkle_root" => merkle_root,
+         "gas_price" => gas_price
+       }),
+       do: Traces.aggregator_task_set_gas_price(merkle_root, gas_price)
+
+  defp dispatch(
+         "/api/aggregatorTaskSent",
+         %{
+           "merkle_root" => merkle_root,
+           "tx_hash" => tx_hash,
+           "effective_gas_price" => effective_gas_price
+         } = params
+       ),
+       do:
+         Traces.aggregator_task_sent(
+           merkle_root,
+           tx_hash,
+           effective_gas_price,
+           params["gas_used"],
+           params["fee_paid"]
+         )
+
+  defp dispatch("/api/finishTaskTrace", %{"merkle_root" => merkle_root}),
+    do: Traces.finish_task_trace(merkle_root)
+
+  defp dispatch(endpoint, _params), do: {:error, "unknown endpoint #{endpoint}"}
+end
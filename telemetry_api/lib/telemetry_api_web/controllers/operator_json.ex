@@ -22,6 +22,7 @@ defmodule TelemetryApiWeb.OperatorJSON do
       stake: operator.stake,
       name: operator.name,
       version: operator.version,
+      version_status: operator.version_status,
       status: operator.status,
       eth_rpc_url: operator.eth_rpc_url,
       eth_rpc_url_fallback: operator.eth_rpc_url_fallback,
@@ -50,7 +51,8 @@ defmodule TelemetryApiWeb.OperatorJSON do
       id: operator.id,
       stake: operator.stake,
       name: operator.name,
-      version: operator.version
+      version: operator.version,
+      version_status: operator.version_status
     }
   end
 end
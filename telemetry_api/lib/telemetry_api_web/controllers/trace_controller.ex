@@ -10,10 +10,10 @@ defmodule TelemetryApiWeb.TraceController do
   Method: POST initTaskTrace
   """
   def create_task_trace(conn, %{"merkle_root" => merkle_root}) do
-    with :ok <- Traces.create_task_trace(merkle_root) do
+    with {:ok, trace_id, span_id} <- Traces.create_task_trace(merkle_root) do
       conn
       |> put_status(:ok)
-      |> render(:show_merkle, merkle_root: merkle_root)
+      |> render(:show_trace, merkle_root: merkle_root, trace_id: trace_id, span_id: span_id)
     end
   end
 
@@ -45,12 +45,21 @@ defmodule TelemetryApiWeb.TraceController do
   Register a batcher task started in the trace of the given merkle_root
   Method: POST batcherTaskStarted
   """
-  def batcher_task_started(conn, %{
-        "merkle_root" => merkle_root,
-        "fee_per_proof" => fee_per_proof,
-        "num_proofs_in_batch" => total_proofs
-      }) do
-    with :ok <- Traces.batcher_task_started(merkle_root, fee_per_proof, total_proofs) do
+  def batcher_task_started(
+        conn,
+        %{
+          "merkle_root" => merkle_root,
+          "fee_per_proof" => fee_per_proof,
+          "num_proofs_in_batch" => total_proofs
+        } = params
+      ) do
+    batch_metadata = %{
+      batch_size_bytes: params["batch_size_bytes"],
+      proofs_by_proving_system: Map.get(params, "proofs_by_proving_system", %{})
+    }
+
+    with :ok <-
+           Traces.batcher_task_started(merkle_root, fee_per_proof, total_proofs, batch_metadata) do
       conn
       |> put_status(:ok)
       |> render(:show_merkle, merkle_root: merkle_root)
@@ -98,12 +107,30 @@ defmodule TelemetryApiWeb.TraceController do
     end
   end
 
+  @doc """
+  Register the times an operator spent on the batch in the trace of the given merkle_root
+  Method: POST operatorBatchTrace
+  """
+  def register_operator_batch_trace(
+        conn,
+        %{
+          "merkle_root" => merkle_root,
+          "operator_id" => operator_id
+        } = params
+      ) do
+    with :ok <- Traces.register_operator_batch_trace(merkle_root, operator_id, params) do
+      conn
+      |> put_status(:ok)
+      |> render(:show_operator, operator_id: operator_id)
+    end
+  end
+
   @doc """
   Registers a reached quorum in the trace of the given merkle_root
   Method: POST quorumReached
   """
-  def quorum_reached(conn, %{"merkle_root" => merkle_root}) do
-    with :ok <- Traces.quorum_reached(merkle_root) do
+  def quorum_reached(conn, %{"merkle_root" => merkle_root} = params) do
+    with :ok <- Traces.quorum_reached(merkle_root, params["non_signers"]) do
       conn
       |> put_status(:ok)
       |> render(:show_merkle, merkle_root: merkle_root)
@@ -141,8 +168,8 @@ defmodule TelemetryApiWeb.TraceController do
   Register a task sent, from the aggregator, to Ethereum in the trace of the given merkle_root
   Method: POST aggregatorTaskSent
   """
-  def aggregator_task_sent(conn, %{"merkle_root" => merkle_root, "tx_hash" => tx_hash, "effective_gas_price" => effective_gas_price}) do
-    with :ok <- Traces.aggregator_task_sent(merkle_root, tx_hash, effective_gas_price) do
+  def aggregator_task_sent(conn, %{"merkle_root" => merkle_root, "tx_hash" => tx_hash, "effective_gas_price" => effective_gas_price} = params) do
+    with :ok <- Traces.aggregator_task_sent(merkle_root, tx_hash, effective_gas_price, params["gas_used"], params["fee_paid"]) do
       conn
       |> put_status(:ok)
       |> render(:show_merkle, merkle_root: merkle_root)
@@ -10,6 +10,17 @@ defmodule TelemetryApiWeb.TraceJSON do
 
   @doc """
 
+  """
+  def show_trace(%{merkle_root: merkle_root, trace_id: trace_id, span_id: span_id}) do
+    %{
+      merkle_root: merkle_root,
+      trace_id: trace_id,
+      span_id: span_id
+    }
+  end
+
+  @doc """
+
   """
   def show_operator(%{operator_id: operator_id}) do
     %{
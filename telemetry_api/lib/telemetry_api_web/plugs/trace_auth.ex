@@ -0,0 +1,53 @@
+defmodule TelemetryApiWeb.Plugs.TraceAuth do
+  @moduledoc """
+  Authenticates the trace submissions of the aggregator, operators and batcher, so traces can't
+  be spoofed or spammed by anyone who learns the endpoint.
+
+  Requests must have an `Authorization: Bearer <token>` header with one of the tokens configured
+  in the `TELEMETRY_API_TOKENS` environment variable. If no tokens are configured, every request
+  is accepted.
+  """
+  import Plug.Conn
+
+  def init(opts), do: opts
+
+  def call(conn, opts) do
+    tokens =
+      Keyword.get_lazy(opts, :tokens, fn ->
+        Application.get_env(:telemetry_api, :trace_api_tokens, [])
+      end)
+
+    cond do
+      tokens == [] ->
+        conn
+
+      valid_token?(conn, tokens) ->
+        conn
+
+      true ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(:unauthorized, Jason.encode!(%{error: "Invalid or missing API token"}))
+        |> halt()
+    end
+  end
+
+  defp valid_token?(conn, tokens) do
+    case get_req_header(conn, "authorization") do
+      [authorization] -> authorized?(authorization, tokens)
+      _ -> false
+    end
+  end
+
+  @doc """
+  Returns whether the authorization header is a bearer token of the tokens
+  """
+  def authorized?("Bearer " <> token, tokens) do
+    # Every token is compared, in constant time, so the comparisons don't leak them
+    Enum.reduce(tokens, false, fn valid_token, valid? ->
+      Plug.Crypto.secure_compare(token, valid_token) or valid?
+    end)
+  end
+
+  def authorized?(_authorization, _tokens), do: false
+end
@@ -5,14 +5,24 @@ defmodule TelemetryApiWeb.Router do
     plug :accepts, ["json"]
   end
 
+  pipeline :trace_api do
+    plug TelemetryApiWeb.Plugs.TraceAuth
+  end
+
   scope "/api", TelemetryApiWeb do
     pipe_through :api
 
     get "/operators", OperatorController, :index
     get "/operators/:id", OperatorController, :show
     post "/operators", OperatorController, :create_or_update
+  end
+
+  scope "/api", TelemetryApiWeb do
+    pipe_through [:api, :trace_api]
+
     post "/initTaskTrace", TraceController, :create_task_trace
     post "/operatorResponse", TraceController, :register_operator_response
+    post "/operatorBatchTrace", TraceController, :register_operator_batch_trace
     post "/quorumReached", TraceController, :quorum_reached
     post "/taskError", TraceController, :task_error
     post "/aggregatorTaskSetGasPrice", TraceController, :aggregator_task_set_gas_price
@@ -61,7 +61,9 @@ defmodule TelemetryApi.MixProject do
       {:opentelemetry_api, "~> 1.2"},
       {:opentelemetry_exporter, "~> 1.6"},
       {:prometheus_ex, "~> 3.0"},
-      {:prometheus_plugs, "~> 1.0"}
+      {:prometheus_plugs, "~> 1.0"},
+      {:grpc, "~> 0.9"},
+      {:protobuf, "~> 0.12"}
     ]
   end
 
@@ -0,0 +1,9 @@
+defmodule TelemetryApi.Repo.Migrations.AddOperatorVersionStatus do
+  use Ecto.Migration
+
+  def change do
+    alter table(:operators) do
+      add :version_status, :string
+    end
+  end
+end
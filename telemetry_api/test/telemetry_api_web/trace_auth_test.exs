@@ -0,0 +1,43 @@
+defmodule TelemetryApiWeb.Plugs.TraceAuthTest do
+  use ExUnit.Case, async: true
+  import Plug.Test
+  import Plug.Conn
+
+  alias TelemetryApiWeb.Plugs.TraceAuth
+
+  defp call(conn, tokens), do: TraceAuth.call(conn, TraceAuth.init(tokens: tokens))
+
+  test "accepts every request without tokens configured" do
+    conn = call(conn(:post, "/api/quorumReached"), [])
+    refute conn.halted
+  end
+
+  test "accepts a configured token" do
+    conn =
+      conn(:post, "/api/quorumReached")
+      |> put_req_header("authorization", "Bearer operator-token")
+      |> call(["aggregator-token", "operator-token"])
+
+    refute conn.halted
+  end
+
+  test "rejects a missing or unknown token" do
+    conn = call(conn(:post, "/api/quorumReached"), ["aggregator-token"])
+    assert conn.halted
+    assert conn.status == 401
+
+    conn =
+      conn(:post, "/api/quorumReached")
+      |> put_req_header("authorization", "Bearer other-token")
+      |> call(["aggregator-token"])
+
+    assert conn.halted
+    assert conn.status == 401
+  end
+
+  test "checks the authorization of the gRPC calls" do
+    assert TraceAuth.authorized?("Bearer aggregator-token", ["aggregator-token"])
+    refute TraceAuth.authorized?("Bearer other-token", ["aggregator-token"])
+    refute TraceAuth.authorized?(nil, ["aggregator-token"])
+  end
+end